@@ -14,6 +14,7 @@ import (
 type Interface interface {
 	FirstVersionCreated(appSlug, buildSlug, platform string)
 	PublishFinished(appSlug string, appVersionID uuid.UUID, result string)
+	CodeSigningFileExpiring(appSlug, fileSlug string, daysLeft int)
 }
 
 // Client ...
@@ -66,3 +67,19 @@ func (c *Client) PublishFinished(appSlug string, appVersionID uuid.UUID, result
 		c.logger.Warn("Failed to track analytics (PublishFinished)", zap.Error(err))
 	}
 }
+
+// CodeSigningFileExpiring ...
+func (c *Client) CodeSigningFileExpiring(appSlug, fileSlug string, daysLeft int) {
+	err := c.client.Enqueue(segment.Track{
+		UserId: appSlug,
+		Event:  "Code signing file is about to expire",
+		Properties: segment.NewProperties().
+			Set("app_slug", appSlug).
+			Set("file_slug", fileSlug).
+			Set("days_left", daysLeft).
+			Set("datetime", time.Now()),
+	})
+	if err != nil {
+		c.logger.Warn("Failed to track analytics (CodeSigningFileExpiring)", zap.Error(err))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -26,6 +27,7 @@ type APIInterface interface {
 	GetArtifactPublicInstallPageURL(string, string, string, string) (string, error)
 	GetAppDetails(authToken, appSlug string) (*AppDetails, error)
 	GetBuildDetails(authToken, appSlug, buildSlug string) (*BuildDetails, error)
+	GetBuilds(authToken, appSlug string, limit int) ([]BuildDetails, error)
 	GetProvisioningProfiles(authToken, appSlug string) ([]ProvisioningProfile, error)
 	GetProvisioningProfile(authToken, appSlug, provProfileSlug string) (*ProvisioningProfile, error)
 	GetCodeSigningIdentities(authToken, appSlug string) ([]CodeSigningIdentity, error)
@@ -34,6 +36,7 @@ type APIInterface interface {
 	GetAndroidKeystoreFile(authToken, appSlug, keystoreSlug string) (*AndroidKeystoreFile, error)
 	GetServiceAccountFiles(authToken, appSlug string) ([]GenericProjectFile, error)
 	GetServiceAccountFile(authToken, appSlug, serviceJSONSLug string) (*GenericProjectFile, error)
+	DownloadFile(downloadURL string) ([]byte, error)
 	TriggerDENTask(params TaskParams) (*TriggerResponse, error)
 	RegisterWebhook(authToken, appSlug, secret, callbackURL string) error
 }
@@ -202,6 +205,45 @@ func (a *API) GetBuildDetails(authToken, appSlug, buildSlug string) (*BuildDetai
 	return &responseModel.Data, nil
 }
 
+// GetBuilds returns the latest finished builds of the app, latest first
+func (a *API) GetBuilds(authToken, appSlug string, limit int) ([]BuildDetails, error) {
+	var builds []BuildDetails
+	next := ""
+	for len(builds) < limit {
+		path := fmt.Sprintf("apps/%s/builds?sort_by=created_at&limit=%d", appSlug, limit-len(builds))
+		if next != "" {
+			path = fmt.Sprintf("%s&next=%s", path, next)
+		}
+		resp, err := a.doRequest(authToken, "GET", path, nil)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			httpresponse.BodyCloseWithErrorLog(resp)
+			return nil, errors.Errorf("Failed to fetch builds: status: %d", resp.StatusCode)
+		}
+		var responseModel buildListResponseModel
+		err = json.NewDecoder(resp.Body).Decode(&responseModel)
+		httpresponse.BodyCloseWithErrorLog(resp)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		for _, build := range responseModel.Data {
+			if build.Status != BuildStatusInProgress {
+				builds = append(builds, build)
+			}
+		}
+		next = responseModel.Paging.Next
+		if next == "" {
+			break
+		}
+	}
+	if len(builds) > limit {
+		builds = builds[:limit]
+	}
+	return builds, nil
+}
+
 // GetProvisioningProfiles ...
 func (a *API) GetProvisioningProfiles(authToken, appSlug string) ([]ProvisioningProfile, error) {
 	resp, err := a.doRequest(authToken, "GET", fmt.Sprintf("apps/%s/provisioning-profiles", appSlug), nil)
@@ -346,6 +388,24 @@ func (a *API) GetServiceAccountFile(authToken, appSlug, serviceJSONSLug string)
 	return responseModel.Data, nil
 }
 
+// DownloadFile downloads an uploaded code signing or generic project file by
+// its download URL
+func (a *API) DownloadFile(downloadURL string) ([]byte, error) {
+	resp, err := a.Get(downloadURL)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer httpresponse.BodyCloseWithErrorLog(resp)
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("Failed to download file: status: %d", resp.StatusCode)
+	}
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return content, nil
+}
+
 // TriggerDENTask ...
 func (a *API) TriggerDENTask(params TaskParams) (*TriggerResponse, error) {
 	payloadBytes, err := json.Marshal(params)
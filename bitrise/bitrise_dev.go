@@ -82,6 +82,11 @@ func (a *APIDev) GetBuildDetails(authToken, appSlug, buildSlug string) (*BuildDe
 	return &BuildDetails{CommitMessage: "El commito messago"}, nil
 }
 
+// GetBuilds ...
+func (a *APIDev) GetBuilds(authToken, appSlug string, limit int) ([]BuildDetails, error) {
+	return []BuildDetails{}, nil
+}
+
 // GetProvisioningProfiles ...
 func (a *APIDev) GetProvisioningProfiles(authToken, appSlug string) ([]ProvisioningProfile, error) {
 	return []ProvisioningProfile{
@@ -177,6 +182,12 @@ func (a *APIDev) GetServiceAccountFile(authToken, appSlug, serviceJSONSLug strin
 	}, nil
 }
 
+// DownloadFile ...
+func (a *APIDev) DownloadFile(downloadURL string) ([]byte, error) {
+	realClient := New()
+	return realClient.DownloadFile(downloadURL)
+}
+
 // TriggerDENTask ...
 func (a *APIDev) TriggerDENTask(params TaskParams) (*TriggerResponse, error) {
 	realClient := New()
@@ -1,10 +1,28 @@
 package bitrise
 
+const (
+	// BuildStatusInProgress ...
+	BuildStatusInProgress = 0
+	// BuildStatusSuccess ...
+	BuildStatusSuccess = 1
+)
+
 // BuildDetails ...
 type BuildDetails struct {
-	CommitMessage string `json:"commit_message"`
+	Slug              string `json:"slug"`
+	BuildNumber       int    `json:"build_number"`
+	Status            int    `json:"status"`
+	TriggeredWorkflow string `json:"triggered_workflow"`
+	Branch            string `json:"branch"`
+	Tag               string `json:"tag"`
+	CommitMessage     string `json:"commit_message"`
 }
 
 type buildShowResponseModel struct {
 	Data BuildDetails `json:"data"`
 }
+
+type buildListResponseModel struct {
+	Data   []BuildDetails      `json:"data"`
+	Paging pagingResponseModel `json:"paging"`
+}
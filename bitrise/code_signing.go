@@ -0,0 +1,80 @@
+package bitrise
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/xml"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/pkcs12"
+)
+
+// ProvisioningProfileExpiry returns the expiry date of a provisioning profile.
+// The profile is a signed message, which contains the profile plist
+// unencrypted, so the expiry date is read from the plist.
+func ProvisioningProfileExpiry(content []byte) (time.Time, error) {
+	start := bytes.Index(content, []byte("<?xml"))
+	end := bytes.Index(content, []byte("</plist>"))
+	if start < 0 || end < start {
+		return time.Time{}, errors.New("No plist found in provisioning profile")
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(content[start : end+len("</plist>")]))
+	var lastKey string
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return time.Time{}, errors.New("No expiration date found in provisioning profile")
+		}
+		if err != nil {
+			return time.Time{}, errors.WithStack(err)
+		}
+		element, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch element.Name.Local {
+		case "key":
+			if err := decoder.DecodeElement(&lastKey, &element); err != nil {
+				return time.Time{}, errors.WithStack(err)
+			}
+		case "date":
+			var date string
+			if err := decoder.DecodeElement(&date, &element); err != nil {
+				return time.Time{}, errors.WithStack(err)
+			}
+			if lastKey == "ExpirationDate" {
+				expiry, err := time.Parse(time.RFC3339, strings.TrimSpace(date))
+				if err != nil {
+					return time.Time{}, errors.WithStack(err)
+				}
+				return expiry, nil
+			}
+		}
+	}
+}
+
+// CodeSigningIdentityExpiry returns the expiry date of the certificate of a
+// code signing identity, which is a password protected PKCS #12 file
+func CodeSigningIdentityExpiry(content []byte, password string) (time.Time, error) {
+	blocks, err := pkcs12.ToPEM(content, password)
+	if err != nil {
+		return time.Time{}, errors.WithStack(err)
+	}
+	for _, block := range blocks {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return time.Time{}, errors.WithStack(err)
+		}
+		if !certificate.IsCA {
+			return certificate.NotAfter, nil
+		}
+	}
+	return time.Time{}, errors.New("No certificate found in code signing identity")
+}
@@ -0,0 +1,67 @@
+package bitrise_test
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/bitrise-io/addons-ship-backend/bitrise"
+	"github.com/stretchr/testify/require"
+)
+
+const testProvisioningProfilePlist = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>CreationDate</key>
+	<date>2019-06-11T09:14:31Z</date>
+	<key>Entitlements</key>
+	<dict>
+		<key>get-task-allow</key>
+		<false/>
+	</dict>
+	<key>ExpirationDate</key>
+	<date>2020-06-10T09:14:31Z</date>
+	<key>Name</key>
+	<string>Ship App Store</string>
+</dict>
+</plist>`
+
+// a code signing identity with the password "secret"
+const testCodeSigningIdentity = "MIIDmgIBAzCCA2AGCSqGSIb3DQEHAaCCA1EEggNNMIIDSTCCAj8GCSqGSIb3DQEHBqCCAjAwggIsAgEAMIICJQYJKoZIhvcNAQcBMBwGCiqGSIb3DQEMAQMwDgQIfgjM5B+3w3ACAggAgIIB+IMWZijaWW/kRyHuoPTWjHCsuWk9acv41av46Djpz+EBnjmK8A/Ghfx44UXFzjt5/3J2k736+WaYT5+ANmEqKUcYDHqX6jeB7RxFuMR1hcBTEgR8B+toBrS77v3yBeJ/lSuS5aaP+FETNQsF9F9EKDJLD61W7GLvKs59H96eKFDFxNENyXc1ZCSjbQBpZHJhy757xhjCfkosaJgbioFjPY6ULJS6J72m64baxgzJqsGDmP/kK+YWm2RORouOoJ9Mxw5CQdM2I1aXxWD5jWY/NPkfUSZGXVnsUwfcUvrLxlMZjOxkbZHfXtrc8HgbstrYl6ZLjqtqNmi2pPqBubOMwxQarKFDLF6U/m+BIi/sH48Zx4Gc1RXRfGXtSGuefvXj5DCQs21nJ1XPi5VDrsZxhnkVVc9JP4+pc+zyoqqokLUZdBlaUueLWfWte2jPsQQP85E8PvS2KNolilajjmQvK5t1tOUIzoBxSN8pCfIlR1Zmt5gD7hmWXEgOvcUWqDBFEQIno2uEsNuyvB738Zs77pDQ4/XnYzD+Px2ieE3qPRVoTeMvXJtRHa5tUT1xqzv88nmFxYHq6+lyipKIoaz9ejWPVE3hllKR6up/4/HajMRC8fWtOj3uBr1p/XIGXlrU7XVnJI3nKyh5+klx09en8zICgzkLVipfGDCCAQIGCSqGSIb3DQEHAaCB9ASB8TCB7jCB6wYLKoZIhvcNAQwKAQKggbQwgbEwHAYKKoZIhvcNAQwBAzAOBAiLBZsrRblAxAICCAAEgZDe9l+Ow9AsrGTtSGNs/KcVO4R784qEkSwoNkoUSsclvYSAkhj9brbjXx+IxKy4Z8eMB45TgdJKFud3dS+WcgEFM9R+R2a9JJiz6BiFUSn4/ecmgsarp/lf2ikagf7EgsUS3smyEeIiepsRtNfAOScV23RmTI9yHrmPC5P7nwXZHCbzcgyEIheL6HPKpBhg6JgxJTAjBgkqhkiG9w0BCRUxFgQUigFiF+Ed/4HZhJm/LsmXfVwkMEQwMTAhMAkGBSsOAwIaBQAEFJLMo2x1qWi1JX0mJ5GOhaVwnBpZBAgxXMgvjMPDMAICCAA="
+
+func Test_ProvisioningProfileExpiry(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		content := append([]byte("0\x80\x06\x09*\x86H\x86\xf7\r\x01\x07\x02"), []byte(testProvisioningProfilePlist)...)
+		content = append(content, []byte("\x00\x00signature")...)
+		expiry, err := bitrise.ProvisioningProfileExpiry(content)
+		require.NoError(t, err)
+		require.Equal(t, time.Date(2020, 6, 10, 9, 14, 31, 0, time.UTC), expiry)
+	})
+
+	t.Run("when content has no plist", func(t *testing.T) {
+		_, err := bitrise.ProvisioningProfileExpiry([]byte("not a provisioning profile"))
+		require.EqualError(t, err, "No plist found in provisioning profile")
+	})
+
+	t.Run("when plist has no expiration date", func(t *testing.T) {
+		_, err := bitrise.ProvisioningProfileExpiry([]byte(`<?xml version="1.0"?><plist><dict><key>Name</key><string>Ship</string></dict></plist>`))
+		require.EqualError(t, err, "No expiration date found in provisioning profile")
+	})
+}
+
+func Test_CodeSigningIdentityExpiry(t *testing.T) {
+	content, err := base64.StdEncoding.DecodeString(testCodeSigningIdentity)
+	require.NoError(t, err)
+
+	t.Run("ok", func(t *testing.T) {
+		expiry, err := bitrise.CodeSigningIdentityExpiry(content, "secret")
+		require.NoError(t, err)
+		require.Equal(t, time.Date(2036, 10, 16, 17, 33, 11, 0, time.UTC), expiry.UTC())
+	})
+
+	t.Run("when password is wrong", func(t *testing.T) {
+		_, err := bitrise.CodeSigningIdentityExpiry(content, "wrong")
+		require.EqualError(t, err, "pkcs12: decryption password incorrect")
+	})
+}
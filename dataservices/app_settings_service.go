@@ -1,9 +1,15 @@
 package dataservices
 
-import "github.com/bitrise-io/addons-ship-backend/models"
+import (
+	"time"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+)
 
 // AppSettingsService ...
 type AppSettingsService interface {
 	Find(*models.AppSettings) (*models.AppSettings, error)
+	FindAllWithCodeSigningExpiringBefore(expiresBefore time.Time) ([]models.AppSettings, error)
+	FindAllWithRetentionPolicy() ([]models.AppSettings, error)
 	Update(appSettings *models.AppSettings, whitelist []string) (validationErrors []error, dbErr error)
 }
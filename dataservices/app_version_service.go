@@ -9,4 +9,6 @@ type AppVersionService interface {
 	FindAll(app *models.App, filterParams map[string]interface{}) ([]models.AppVersion, error)
 	Update(appVersion *models.AppVersion, whitelist []string) (validationErrors []error, dbErr error)
 	Latest(appVersion *models.AppVersion) (*models.AppVersion, error)
+	LatestPublished(appVersion *models.AppVersion) (*models.AppVersion, error)
+	Delete(appVersion *models.AppVersion) error
 }
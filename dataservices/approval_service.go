@@ -0,0 +1,12 @@
+package dataservices
+
+import "github.com/bitrise-io/addons-ship-backend/models"
+
+// ApprovalService ...
+type ApprovalService interface {
+	Create(approval *models.Approval) (*models.Approval, error)
+	Find(approval *models.Approval) (*models.Approval, error)
+	FindAll(appVersion *models.AppVersion) ([]models.Approval, error)
+	Update(approval *models.Approval, whitelist []string) error
+	InvalidateAll(appVersion *models.AppVersion) (int, error)
+}
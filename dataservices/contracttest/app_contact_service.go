@@ -0,0 +1,97 @@
+package contracttest
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/c2fo/testify/require"
+	uuid "github.com/satori/go.uuid"
+)
+
+func testAppContactService(t *testing.T, services Services) {
+	app := createApp(t, services, "app-contact-test-app-slug")
+
+	createAppContact := func(t *testing.T, email string) *models.AppContact {
+		appContact, verrs, err := services.AppContactService.Create(&models.AppContact{AppID: app.ID, Email: email})
+		require.NoError(t, err)
+		require.Empty(t, verrs)
+		return appContact
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		appContact := createAppContact(t, "create@bitrise.io")
+		require.False(t, uuid.Equal(uuid.UUID{}, appContact.ID))
+		require.Equal(t, "app-contact-test-app-slug", appContact.App.AppSlug)
+
+		notificationPreferences, err := appContact.NotificationPreferences()
+		require.NoError(t, err)
+		require.Equal(t, models.NotificationPreferences{}, notificationPreferences)
+
+		t.Log("when email has wrong format")
+		_, verrs, err := services.AppContactService.Create(&models.AppContact{AppID: app.ID, Email: "not-an-email"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"email: Wrong format"}, errorMessages(verrs))
+	})
+
+	t.Run("Find", func(t *testing.T) {
+		appContact := createAppContact(t, "find@bitrise.io")
+
+		foundAppContact, err := services.AppContactService.Find(&models.AppContact{Record: models.Record{ID: appContact.ID}, AppID: app.ID})
+		require.NoError(t, err)
+		require.Equal(t, "find@bitrise.io", foundAppContact.Email)
+		require.Equal(t, "app-contact-test-app-slug", foundAppContact.App.AppSlug)
+
+		_, err = services.AppContactService.Find(&models.AppContact{Record: models.Record{ID: appContact.ID}, AppID: uuid.NewV4()})
+		requireNotFound(t, err)
+	})
+
+	t.Run("FindAll", func(t *testing.T) {
+		otherApp := createApp(t, services, "app-contact-find-all-app-slug")
+		appContacts := []uuid.UUID{}
+		for _, email := range []string{"find-all-1@bitrise.io", "find-all-2@bitrise.io"} {
+			appContact, _, err := services.AppContactService.Create(&models.AppContact{AppID: otherApp.ID, Email: email})
+			require.NoError(t, err)
+			appContacts = append(appContacts, appContact.ID)
+		}
+
+		foundAppContacts, err := services.AppContactService.FindAll(otherApp)
+		require.NoError(t, err)
+		foundIDs := []uuid.UUID{}
+		for _, appContact := range foundAppContacts {
+			foundIDs = append(foundIDs, appContact.ID)
+		}
+		require.Equal(t, sortedIDs(appContacts...), sortedIDs(foundIDs...))
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		appContact := createAppContact(t, "update@bitrise.io")
+
+		appContact.NotificationPreferencesData = json.RawMessage(`{"new_version":true}`)
+		appContact.Email = "renamed@bitrise.io"
+		require.NoError(t, services.AppContactService.Update(appContact, []string{"NotificationPreferencesData"}))
+
+		foundAppContact, err := services.AppContactService.Find(&models.AppContact{Record: models.Record{ID: appContact.ID}})
+		require.NoError(t, err)
+		require.Equal(t, "update@bitrise.io", foundAppContact.Email)
+		notificationPreferences, err := foundAppContact.NotificationPreferences()
+		require.NoError(t, err)
+		require.True(t, notificationPreferences.NewVersion)
+
+		t.Log("when email has wrong format")
+		foundAppContact.Email = "not-an-email"
+		require.Error(t, services.AppContactService.Update(foundAppContact, []string{"Email"}))
+
+		t.Log("when trying to update non-existing field")
+		require.EqualError(t, services.AppContactService.Update(foundAppContact, []string{"NonExistingField"}), "Attribute name doesn't exist in the model")
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		appContact := createAppContact(t, "delete@bitrise.io")
+
+		require.NoError(t, services.AppContactService.Delete(appContact))
+		_, err := services.AppContactService.Find(&models.AppContact{Record: models.Record{ID: appContact.ID}})
+		requireNotFound(t, err)
+		requireNotFound(t, services.AppContactService.Delete(appContact))
+	})
+}
@@ -0,0 +1,115 @@
+package contracttest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/c2fo/testify/require"
+)
+
+func testAppService(t *testing.T, services Services) {
+	t.Run("Create", func(t *testing.T) {
+		app := createApp(t, services, "app-create-slug")
+		require.False(t, app.ID.String() == "00000000-0000-0000-0000-000000000000")
+		require.False(t, app.CreatedAt.IsZero())
+		require.NotEmpty(t, app.EncryptedSecret)
+		require.NotEmpty(t, app.EncryptedSecretIV)
+
+		secret, err := app.Secret()
+		require.NoError(t, err)
+		require.Len(t, secret, 24)
+
+		t.Log("creates the settings of the app")
+		appSettings, err := services.AppSettingsService.Find(&models.AppSettings{AppID: app.ID})
+		require.NoError(t, err)
+		require.Equal(t, app.AppSettings.ID, appSettings.ID)
+	})
+
+	t.Run("Find", func(t *testing.T) {
+		app := createApp(t, services, "app-find-slug")
+		createApp(t, services, "app-find-other-slug")
+		olderVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "ios", Record: models.Record{CreatedAt: time.Now().Add(-time.Hour)}})
+		newerVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "ios"})
+
+		foundApp, err := services.AppService.Find(&models.App{AppSlug: "app-find-slug"})
+		require.NoError(t, err)
+		require.Equal(t, app.ID, foundApp.ID)
+		require.Equal(t, "gold", foundApp.Plan)
+		require.Equal(t, app.EncryptedSecret, foundApp.EncryptedSecret)
+
+		t.Log("loads the versions of the app, latest first")
+		require.Len(t, foundApp.AppVersions, 2)
+		require.Equal(t, newerVersion.ID, foundApp.AppVersions[0].ID)
+		require.Equal(t, olderVersion.ID, foundApp.AppVersions[1].ID)
+
+		_, err = services.AppService.Find(&models.App{AppSlug: "app-find-non-existing-slug"})
+		requireNotFound(t, err)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		createApp(t, services, "app-update-slug")
+		app, err := services.AppService.Find(&models.App{AppSlug: "app-update-slug"})
+		require.NoError(t, err)
+		app.Plan = "silver"
+		app.HeaderColor1 = "#ffffff"
+		verrs, err := services.AppService.Update(app, []string{"Plan"})
+		require.NoError(t, err)
+		require.Empty(t, verrs)
+
+		t.Log("only updates the whitelisted attributes")
+		foundApp, err := services.AppService.Find(&models.App{Record: models.Record{ID: app.ID}})
+		require.NoError(t, err)
+		require.Equal(t, "silver", foundApp.Plan)
+		require.Equal(t, "", foundApp.HeaderColor1)
+
+		_, err = services.AppService.Update(app, []string{})
+		require.EqualError(t, err, "No attributes to update")
+		_, err = services.AppService.Update(app, []string{"NonExistingField"})
+		require.EqualError(t, err, "Attribute name doesn't exist in the model")
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		app := createApp(t, services, "app-delete-slug")
+		require.NoError(t, services.AppService.Delete(app))
+
+		_, err := services.AppService.Find(&models.App{AppSlug: "app-delete-slug"})
+		requireNotFound(t, err)
+		requireNotFound(t, services.AppService.Delete(app))
+	})
+
+	t.Run("Deprovision and restore", func(t *testing.T) {
+		now := time.Now().UTC().Truncate(time.Second)
+		app := createApp(t, services, "app-deprovisioned-slug")
+		laterApp := createApp(t, services, "app-later-deprovisioned-slug")
+		for deprovisionedApp, deprovisionedAt := range map[*models.App]time.Time{app: now.Add(-48 * time.Hour), laterApp: now} {
+			deprovisionedAt := deprovisionedAt
+			deprovisionedApp.DeprovisionedAt = &deprovisionedAt
+			verrs, err := services.AppService.Update(deprovisionedApp, []string{"DeprovisionedAt"})
+			require.NoError(t, err)
+			require.Empty(t, verrs)
+		}
+
+		t.Log("deprovisioned apps are not found")
+		_, err := services.AppService.Find(&models.App{AppSlug: "app-deprovisioned-slug"})
+		requireNotFound(t, err)
+		foundApp, err := services.AppService.FindDeprovisioned(&models.App{AppSlug: "app-deprovisioned-slug"})
+		require.NoError(t, err)
+		require.Equal(t, app.ID, foundApp.ID)
+
+		apps, err := services.AppService.FindAllDeprovisionedBefore(now.Add(-24 * time.Hour))
+		require.NoError(t, err)
+		require.Len(t, apps, 1)
+		require.Equal(t, app.ID, apps[0].ID)
+
+		t.Log("restored apps are found again")
+		foundApp.DeprovisionedAt = nil
+		verrs, err := services.AppService.Update(foundApp, []string{"DeprovisionedAt"})
+		require.NoError(t, err)
+		require.Empty(t, verrs)
+		_, err = services.AppService.Find(&models.App{AppSlug: "app-deprovisioned-slug"})
+		require.NoError(t, err)
+		_, err = services.AppService.FindDeprovisioned(&models.App{AppSlug: "app-deprovisioned-slug"})
+		requireNotFound(t, err)
+	})
+}
@@ -0,0 +1,128 @@
+package contracttest
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/go-utils/envutil"
+	"github.com/c2fo/testify/require"
+	uuid "github.com/satori/go.uuid"
+)
+
+func testAppSettingsService(t *testing.T, services Services) {
+	t.Run("Find", func(t *testing.T) {
+		app := createApp(t, services, "app-settings-find-app-slug")
+
+		appSettings, err := services.AppSettingsService.Find(&models.AppSettings{AppID: app.ID})
+		require.NoError(t, err)
+		require.Equal(t, app.ID, appSettings.AppID)
+		require.Equal(t, "app-settings-find-app-slug", appSettings.App.AppSlug)
+
+		iosSettings, err := appSettings.IosSettings()
+		require.NoError(t, err)
+		require.Equal(t, models.IosSettings{}, iosSettings)
+
+		_, err = services.AppSettingsService.Find(&models.AppSettings{AppID: uuid.NewV4()})
+		requireNotFound(t, err)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		app := createApp(t, services, "app-settings-update-app-slug")
+		otherApp := createApp(t, services, "app-settings-update-other-app-slug")
+
+		appSettings, err := services.AppSettingsService.Find(&models.AppSettings{AppID: app.ID})
+		require.NoError(t, err)
+		appSettings.IosSettingsData = json.RawMessage(`{"app_sku":"20180601"}`)
+		appSettings.IosWorkflow = "deploy"
+		verrs, err := services.AppSettingsService.Update(appSettings, []string{"IosSettingsData"})
+		require.NoError(t, err)
+		require.Empty(t, verrs)
+
+		foundAppSettings, err := services.AppSettingsService.Find(&models.AppSettings{AppID: app.ID})
+		require.NoError(t, err)
+		iosSettings, err := foundAppSettings.IosSettings()
+		require.NoError(t, err)
+		require.Equal(t, "20180601", iosSettings.AppSKU)
+		require.Equal(t, "", foundAppSettings.IosWorkflow)
+
+		t.Log("does not update the settings of other apps")
+		otherAppSettings, err := services.AppSettingsService.Find(&models.AppSettings{AppID: otherApp.ID})
+		require.NoError(t, err)
+		otherIosSettings, err := otherAppSettings.IosSettings()
+		require.NoError(t, err)
+		require.Equal(t, models.IosSettings{}, otherIosSettings)
+	})
+
+	t.Run("Update encrypted iOS API key", func(t *testing.T) {
+		revokeFn, err := envutil.RevokableSetenv("APP_SETTINGS_ENCRYPT_KEY", "06042e86a7bd421c642c8c3e4ab13840")
+		require.NoError(t, err)
+		defer func() { require.NoError(t, revokeFn()) }()
+		app := createApp(t, services, "app-settings-api-key-app-slug")
+
+		appSettings, err := services.AppSettingsService.Find(&models.AppSettings{AppID: app.ID})
+		require.NoError(t, err)
+		require.NoError(t, appSettings.SetIosAPIKey("my-private-key"))
+		verrs, err := services.AppSettingsService.Update(appSettings, []string{"EncryptedIosAPIKey", "EncryptedIosAPIKeyIV"})
+		require.NoError(t, err)
+		require.Empty(t, verrs)
+
+		foundAppSettings, err := services.AppSettingsService.Find(&models.AppSettings{AppID: app.ID})
+		require.NoError(t, err)
+		privateKey, err := foundAppSettings.IosAPIKey()
+		require.NoError(t, err)
+		require.Equal(t, "my-private-key", privateKey)
+	})
+	t.Run("FindAllWithCodeSigningExpiringBefore", func(t *testing.T) {
+		now := time.Now().UTC().Truncate(time.Second)
+		expiringApp := createApp(t, services, "app-settings-expiring-app-slug")
+		laterExpiringApp := createApp(t, services, "app-settings-later-expiring-app-slug")
+		createApp(t, services, "app-settings-not-expiring-app-slug")
+
+		for app, expiresAt := range map[*models.App]time.Time{expiringApp: now.Add(24 * time.Hour), laterExpiringApp: now.Add(60 * 24 * time.Hour)} {
+			appSettings, err := services.AppSettingsService.Find(&models.AppSettings{AppID: app.ID})
+			require.NoError(t, err)
+			require.NoError(t, appSettings.SetCodeSigningExpiry(models.CodeSigningExpiry{
+				DistributionCertificate: &models.CodeSigningFileExpiry{Slug: "code-signing-slug", ExpiresAt: expiresAt},
+			}))
+			verrs, err := services.AppSettingsService.Update(appSettings, []string{"CodeSigningExpiryData", "CodeSigningExpiresAt"})
+			require.NoError(t, err)
+			require.Empty(t, verrs)
+		}
+
+		appSettingsList, err := services.AppSettingsService.FindAllWithCodeSigningExpiringBefore(now.Add(31 * 24 * time.Hour))
+		require.NoError(t, err)
+		require.Len(t, appSettingsList, 1)
+		require.Equal(t, expiringApp.ID, appSettingsList[0].AppID)
+		require.Equal(t, "app-settings-expiring-app-slug", appSettingsList[0].App.AppSlug)
+		codeSigningExpiry, err := appSettingsList[0].CodeSigningExpiry()
+		require.NoError(t, err)
+		require.Equal(t, "code-signing-slug", codeSigningExpiry.DistributionCertificate.Slug)
+	})
+	t.Run("FindAllWithRetentionPolicy", func(t *testing.T) {
+		retainingApp := createApp(t, services, "app-settings-retaining-app-slug")
+		disabledApp := createApp(t, services, "app-settings-disabled-retention-app-slug")
+
+		for app, retentionPolicy := range map[*models.App]models.RetentionPolicy{
+			retainingApp: {KeepLast: 5, KeepPublished: true, Action: models.RetentionActionArchive},
+			disabledApp:  {KeepPublished: true},
+		} {
+			appSettings, err := services.AppSettingsService.Find(&models.AppSettings{AppID: app.ID})
+			require.NoError(t, err)
+			require.NoError(t, appSettings.SetRetentionPolicy(retentionPolicy))
+			verrs, err := services.AppSettingsService.Update(appSettings, []string{"RetentionPolicyData"})
+			require.NoError(t, err)
+			require.Empty(t, verrs)
+		}
+
+		appSettingsList, err := services.AppSettingsService.FindAllWithRetentionPolicy()
+		require.NoError(t, err)
+		require.Len(t, appSettingsList, 1)
+		require.Equal(t, retainingApp.ID, appSettingsList[0].AppID)
+		require.Equal(t, "app-settings-retaining-app-slug", appSettingsList[0].App.AppSlug)
+		retentionPolicy, err := appSettingsList[0].RetentionPolicy()
+		require.NoError(t, err)
+		require.Equal(t, models.RetentionPolicy{KeepLast: 5, KeepPublished: true, Action: models.RetentionActionArchive}, retentionPolicy)
+	})
+}
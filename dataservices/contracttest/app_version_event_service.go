@@ -0,0 +1,77 @@
+package contracttest
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/c2fo/testify/require"
+	uuid "github.com/satori/go.uuid"
+)
+
+func testAppVersionEventService(t *testing.T, services Services) {
+	app := createApp(t, services, "app-version-event-test-app-slug")
+	appVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "ios"})
+
+	t.Run("Create", func(t *testing.T) {
+		event, err := services.AppVersionEventService.Create(&models.AppVersionEvent{
+			AppVersionID: appVersion.ID,
+			Status:       "in-progress",
+			Text:         "Publishing has started",
+		})
+		require.NoError(t, err)
+		require.False(t, uuid.Equal(uuid.UUID{}, event.ID))
+		require.Equal(t, appVersion.ID, event.AppVersion.ID)
+		require.Equal(t, "app-version-event-test-app-slug", event.AppVersion.App.AppSlug)
+
+		logPath, err := event.LogAWSPath()
+		require.NoError(t, err)
+		require.Equal(t, "logs/app-version-event-test-app-slug/"+appVersion.ID.String()+"/"+event.ID.String()+".log", logPath)
+	})
+
+	t.Run("Find", func(t *testing.T) {
+		event, err := services.AppVersionEventService.Create(&models.AppVersionEvent{AppVersionID: appVersion.ID, Status: "finished"})
+		require.NoError(t, err)
+
+		foundEvent, err := services.AppVersionEventService.Find(&models.AppVersionEvent{Record: models.Record{ID: event.ID}})
+		require.NoError(t, err)
+		require.Equal(t, "finished", foundEvent.Status)
+		require.Equal(t, "app-version-event-test-app-slug", foundEvent.AppVersion.App.AppSlug)
+
+		_, err = services.AppVersionEventService.Find(&models.AppVersionEvent{Record: models.Record{ID: uuid.NewV4()}})
+		requireNotFound(t, err)
+	})
+
+	t.Run("FindAll", func(t *testing.T) {
+		otherAppVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "android"})
+		events := []uuid.UUID{}
+		for _, status := range []string{"in-progress", "finished"} {
+			event, err := services.AppVersionEventService.Create(&models.AppVersionEvent{AppVersionID: otherAppVersion.ID, Status: status})
+			require.NoError(t, err)
+			events = append(events, event.ID)
+		}
+
+		foundEvents, err := services.AppVersionEventService.FindAll(otherAppVersion)
+		require.NoError(t, err)
+		foundEventIDs := []uuid.UUID{}
+		for _, event := range foundEvents {
+			require.Equal(t, "app-version-event-test-app-slug", event.AppVersion.App.AppSlug)
+			foundEventIDs = append(foundEventIDs, event.ID)
+		}
+		require.Equal(t, sortedIDs(events...), sortedIDs(foundEventIDs...))
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		event, err := services.AppVersionEventService.Create(&models.AppVersionEvent{AppVersionID: appVersion.ID, Status: "in-progress"})
+		require.NoError(t, err)
+		event.IsLogAvailable = true
+		event.Status = "finished"
+		verrs, err := services.AppVersionEventService.Update(event, []string{"IsLogAvailable"})
+		require.NoError(t, err)
+		require.Empty(t, verrs)
+
+		foundEvent, err := services.AppVersionEventService.Find(&models.AppVersionEvent{Record: models.Record{ID: event.ID}})
+		require.NoError(t, err)
+		require.True(t, foundEvent.IsLogAvailable)
+		require.Equal(t, "in-progress", foundEvent.Status)
+	})
+}
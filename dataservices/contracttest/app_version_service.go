@@ -0,0 +1,233 @@
+package contracttest
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/c2fo/testify/require"
+	uuid "github.com/satori/go.uuid"
+)
+
+func testAppVersionService(t *testing.T, services Services) {
+	app := createApp(t, services, "app-version-test-app-slug")
+
+	t.Run("Create", func(t *testing.T) {
+		appVersion, verrs, err := services.AppVersionService.Create(&models.AppVersion{
+			AppID:            app.ID,
+			Platform:         "ios",
+			ArtifactInfoData: json.RawMessage(`{"version":"1.0"}`),
+		})
+		require.NoError(t, err)
+		require.Empty(t, verrs)
+		require.False(t, uuid.Equal(uuid.UUID{}, appVersion.ID))
+		require.False(t, appVersion.CreatedAt.IsZero())
+		require.Equal(t, "draft", appVersion.Status)
+
+		appStoreInfo, err := appVersion.AppStoreInfo()
+		require.NoError(t, err)
+		require.Equal(t, models.AppStoreInfo{}, appStoreInfo)
+
+		t.Log("when version is empty")
+		appVersion, verrs, err = services.AppVersionService.Create(&models.AppVersion{
+			AppID:            app.ID,
+			ArtifactInfoData: json.RawMessage(`{"minimum_os":"11.0"}`),
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"version: Cannot be empty"}, errorMessages(verrs))
+		require.Nil(t, appVersion)
+
+		t.Log("when artifact info is not a valid JSON")
+		appVersion, verrs, err = services.AppVersionService.Create(&models.AppVersion{
+			AppID:            app.ID,
+			ArtifactInfoData: json.RawMessage(`invalid JSON`),
+		})
+		require.Error(t, err)
+		require.Empty(t, verrs)
+		require.Nil(t, appVersion)
+
+		t.Log("when app store info is not a valid JSON")
+		appVersion, verrs, err = services.AppVersionService.Create(&models.AppVersion{
+			AppID:            app.ID,
+			ArtifactInfoData: json.RawMessage(`{"version":"1.0"}`),
+			AppStoreInfoData: json.RawMessage(`invalid JSON`),
+		})
+		require.Error(t, err)
+		require.Empty(t, verrs)
+		require.Nil(t, appVersion)
+	})
+
+	t.Run("Find", func(t *testing.T) {
+		appVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "android", BuildSlug: "find-build-slug"})
+
+		foundAppVersion, err := services.AppVersionService.Find(&models.AppVersion{Record: models.Record{ID: appVersion.ID}})
+		require.NoError(t, err)
+		require.Equal(t, "find-build-slug", foundAppVersion.BuildSlug)
+		require.Equal(t, "app-version-test-app-slug", foundAppVersion.App.AppSlug)
+
+		_, err = services.AppVersionService.Find(&models.AppVersion{Record: models.Record{ID: uuid.NewV4()}})
+		requireNotFound(t, err)
+	})
+
+	t.Run("FindAll", func(t *testing.T) {
+		otherApp := createApp(t, services, "app-version-find-all-app-slug")
+		iosVersion := createAppVersion(t, services, &models.AppVersion{AppID: otherApp.ID, Platform: "ios"})
+		androidVersion := createAppVersion(t, services, &models.AppVersion{AppID: otherApp.ID, Platform: "android"})
+
+		appVersions, err := services.AppVersionService.FindAll(otherApp, map[string]interface{}{})
+		require.NoError(t, err)
+		require.Equal(t, sortedIDs(iosVersion.ID, androidVersion.ID), appVersionIDs(appVersions))
+
+		appVersions, err = services.AppVersionService.FindAll(otherApp, map[string]interface{}{"platform": "android"})
+		require.NoError(t, err)
+		require.Equal(t, sortedIDs(androidVersion.ID), appVersionIDs(appVersions))
+
+		appVersions, err = services.AppVersionService.FindAll(otherApp, map[string]interface{}{"platform": "windows"})
+		require.NoError(t, err)
+		require.Empty(t, appVersions)
+
+		t.Log("when archived versions are filtered out")
+		archivedAt := time.Now()
+		archivedVersion := createAppVersion(t, services, &models.AppVersion{AppID: otherApp.ID, Platform: "ios", ArchivedAt: &archivedAt})
+		appVersions, err = services.AppVersionService.FindAll(otherApp, map[string]interface{}{"platform": "ios"})
+		require.NoError(t, err)
+		require.Equal(t, sortedIDs(iosVersion.ID, archivedVersion.ID), appVersionIDs(appVersions))
+		appVersions, err = services.AppVersionService.FindAll(otherApp, map[string]interface{}{"platform": "ios", "archived_at": nil})
+		require.NoError(t, err)
+		require.Equal(t, sortedIDs(iosVersion.ID), appVersionIDs(appVersions))
+
+		t.Log("when filtered by status")
+		publishedVersion := createAppVersion(t, services, &models.AppVersion{AppID: otherApp.ID, Platform: "android", Status: "published"})
+		appVersions, err = services.AppVersionService.FindAll(otherApp, map[string]interface{}{"status": "published"})
+		require.NoError(t, err)
+		require.Equal(t, sortedIDs(publishedVersion.ID), appVersionIDs(appVersions))
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		appVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "ios", BuildNumber: "12"})
+		appVersion.AppStoreInfoData = json.RawMessage(`{"whats_new":"Everything"}`)
+		appVersion.BuildNumber = "13"
+		verrs, err := services.AppVersionService.Update(appVersion, []string{"AppStoreInfoData"})
+		require.NoError(t, err)
+		require.Empty(t, verrs)
+
+		t.Log("only updates the whitelisted attributes")
+		foundAppVersion, err := services.AppVersionService.Find(&models.AppVersion{Record: models.Record{ID: appVersion.ID}})
+		require.NoError(t, err)
+		appStoreInfo, err := foundAppVersion.AppStoreInfo()
+		require.NoError(t, err)
+		require.Equal(t, "Everything", appStoreInfo.WhatsNew)
+		require.Equal(t, "12", foundAppVersion.BuildNumber)
+
+		t.Log("when ios release options are invalid")
+		appVersion.IosReleaseOptionsData = json.RawMessage(`{"release_type":"scheduled"}`)
+		verrs, err = services.AppVersionService.Update(appVersion, []string{"IosReleaseOptionsData"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"ios_release_options.scheduled_release_date: Cannot be empty for scheduled releases"}, errorMessages(verrs))
+		appVersion.IosReleaseOptionsData = json.RawMessage(`{"release_type":"manual"}`)
+
+		t.Log("when version gets empty")
+		appVersion.ArtifactInfoData = json.RawMessage(`{}`)
+		verrs, err = services.AppVersionService.Update(appVersion, []string{"ArtifactInfoData"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"version: Cannot be empty"}, errorMessages(verrs))
+	})
+
+	t.Run("Latest", func(t *testing.T) {
+		otherApp := createApp(t, services, "app-version-latest-app-slug")
+		createAppVersion(t, services, &models.AppVersion{AppID: otherApp.ID, Platform: "ios", Record: models.Record{CreatedAt: time.Now().Add(-2 * time.Hour)}})
+		latestIosVersion := createAppVersion(t, services, &models.AppVersion{AppID: otherApp.ID, Platform: "ios", Record: models.Record{CreatedAt: time.Now().Add(-time.Hour)}})
+		createAppVersion(t, services, &models.AppVersion{AppID: otherApp.ID, Platform: "android"})
+
+		latestAppVersion, err := services.AppVersionService.Latest(&models.AppVersion{AppID: otherApp.ID, Platform: "ios"})
+		require.NoError(t, err)
+		require.Equal(t, latestIosVersion.ID, latestAppVersion.ID)
+		require.Equal(t, "app-version-latest-app-slug", latestAppVersion.App.AppSlug)
+
+		_, err = services.AppVersionService.Latest(&models.AppVersion{AppID: otherApp.ID, Platform: "windows"})
+		requireNotFound(t, err)
+
+		t.Log("when bundle ID is given")
+		whiteLabelVersion := createAppVersion(t, services, &models.AppVersion{AppID: otherApp.ID, Platform: "ios", BundleID: "io.bitrise.white-label", Record: models.Record{CreatedAt: time.Now().Add(-90 * time.Minute)}})
+		latestAppVersion, err = services.AppVersionService.Latest(&models.AppVersion{AppID: otherApp.ID, Platform: "ios", BundleID: "io.bitrise.white-label"})
+		require.NoError(t, err)
+		require.Equal(t, whiteLabelVersion.ID, latestAppVersion.ID)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		appVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "ios"})
+		_, err := services.AppVersionEventService.Create(&models.AppVersionEvent{AppVersionID: appVersion.ID, Text: "New version was created"})
+		require.NoError(t, err)
+		require.NoError(t, services.AppVersionService.Delete(appVersion))
+
+		_, err = services.AppVersionService.Find(&models.AppVersion{Record: models.Record{ID: appVersion.ID}})
+		requireNotFound(t, err)
+		events, err := services.AppVersionEventService.FindAll(appVersion)
+		require.NoError(t, err)
+		require.Empty(t, events)
+		requireNotFound(t, services.AppVersionService.Delete(appVersion))
+	})
+
+	t.Run("LatestPublished", func(t *testing.T) {
+		otherApp := createApp(t, services, "app-version-latest-published-app-slug")
+		createPublishTask := func(appVersion *models.AppVersion, status string, updatedAt time.Time) {
+			_, err := services.PublishTaskService.Create(&models.PublishTask{
+				TaskID:       uuid.NewV4(),
+				AppVersionID: appVersion.ID,
+				Status:       status,
+				Record:       models.Record{CreatedAt: updatedAt, UpdatedAt: updatedAt},
+			})
+			require.NoError(t, err)
+		}
+		newAndroidVersion := func(productFlavor, versionCode string) *models.AppVersion {
+			return createAppVersion(t, services, &models.AppVersion{
+				AppID:            otherApp.ID,
+				Platform:         "android",
+				ProductFlavor:    productFlavor,
+				ArtifactInfoData: json.RawMessage(`{"version":"1.0","version_code":"` + versionCode + `"}`),
+			})
+		}
+		publishedVersion := newAndroidVersion("free", "12")
+		createPublishTask(publishedVersion, models.PublishTaskStatusSuccess, time.Now().Add(-3*time.Hour))
+		lowerPublishedVersion := newAndroidVersion("free", "11")
+		createPublishTask(lowerPublishedVersion, models.PublishTaskStatusSuccess, time.Now().Add(-2*time.Hour))
+		failedVersion := newAndroidVersion("free", "20")
+		createPublishTask(failedVersion, models.PublishTaskStatusFailed, time.Now().Add(-time.Hour))
+		otherFlavorVersion := newAndroidVersion("paid", "30")
+		createPublishTask(otherFlavorVersion, models.PublishTaskStatusSuccess, time.Now().Add(-time.Hour))
+		newVersion := newAndroidVersion("free", "13")
+
+		t.Log("the version with the greatest build code is returned, even if another one was published later")
+		latestPublished, err := services.AppVersionService.LatestPublished(newVersion)
+		require.NoError(t, err)
+		require.Equal(t, publishedVersion.ID, latestPublished.ID)
+		require.Equal(t, "app-version-latest-published-app-slug", latestPublished.App.AppSlug)
+
+		t.Log("when the version itself has the greatest published build code")
+		createPublishTask(newVersion, models.PublishTaskStatusSuccess, time.Now())
+		latestPublished, err = services.AppVersionService.LatestPublished(newVersion)
+		require.NoError(t, err)
+		require.Equal(t, publishedVersion.ID, latestPublished.ID)
+
+		t.Log("when no other version was published")
+		_, err = services.AppVersionService.LatestPublished(&models.AppVersion{AppID: otherApp.ID, Platform: "ios"})
+		requireNotFound(t, err)
+	})
+}
+
+func appVersionIDs(appVersions []models.AppVersion) []string {
+	ids := []uuid.UUID{}
+	for _, appVersion := range appVersions {
+		ids = append(ids, appVersion.ID)
+	}
+	return sortedIDs(ids...)
+}
+
+func errorMessages(errs []error) []string {
+	messages := []string{}
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	return messages
+}
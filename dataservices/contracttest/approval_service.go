@@ -0,0 +1,113 @@
+package contracttest
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/c2fo/testify/require"
+	uuid "github.com/satori/go.uuid"
+)
+
+func testApprovalService(t *testing.T, services Services) {
+	app := createApp(t, services, "approval-test-app-slug")
+
+	t.Run("Create", func(t *testing.T) {
+		appVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "ios"})
+		approval, err := services.ApprovalService.Create(&models.Approval{AppVersionID: appVersion.ID, Approver: "qa@bitrise.io", Comment: "Tested on device"})
+		require.NoError(t, err)
+		require.False(t, uuid.Equal(uuid.UUID{}, approval.ID))
+		require.Equal(t, models.ApprovalStatusApproved, approval.Status)
+	})
+
+	t.Run("Find", func(t *testing.T) {
+		appVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "ios"})
+		approval, err := services.ApprovalService.Create(&models.Approval{AppVersionID: appVersion.ID, Approver: "qa@bitrise.io"})
+		require.NoError(t, err)
+
+		foundApproval, err := services.ApprovalService.Find(&models.Approval{AppVersionID: appVersion.ID, Approver: "qa@bitrise.io", Status: models.ApprovalStatusApproved})
+		require.NoError(t, err)
+		require.Equal(t, approval.ID, foundApproval.ID)
+
+		_, err = services.ApprovalService.Find(&models.Approval{AppVersionID: appVersion.ID, Approver: "product@bitrise.io"})
+		requireNotFound(t, err)
+	})
+
+	t.Run("Find by token", func(t *testing.T) {
+		appVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "ios"})
+		token := "5om3-r4nd0m-5tr1ng"
+		approval, err := services.ApprovalService.Create(&models.Approval{AppVersionID: appVersion.ID, Approver: "qa@bitrise.io", Status: models.ApprovalStatusRequested, Token: &token})
+		require.NoError(t, err)
+
+		foundApproval, err := services.ApprovalService.Find(&models.Approval{Token: &token})
+		require.NoError(t, err)
+		require.Equal(t, approval.ID, foundApproval.ID)
+		require.Equal(t, models.ApprovalStatusRequested, foundApproval.Status)
+
+		foundApproval.Token = nil
+		require.NoError(t, services.ApprovalService.Update(foundApproval, []string{"Token"}))
+		_, err = services.ApprovalService.Find(&models.Approval{Token: &token})
+		requireNotFound(t, err)
+	})
+
+	t.Run("FindAll", func(t *testing.T) {
+		appVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "ios"})
+		otherAppVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "ios"})
+		approvalIDs := []uuid.UUID{}
+		for _, approver := range []string{"qa@bitrise.io", "product@bitrise.io"} {
+			approval, err := services.ApprovalService.Create(&models.Approval{AppVersionID: appVersion.ID, Approver: approver})
+			require.NoError(t, err)
+			approvalIDs = append(approvalIDs, approval.ID)
+		}
+		_, err := services.ApprovalService.Create(&models.Approval{AppVersionID: otherAppVersion.ID, Approver: "qa@bitrise.io"})
+		require.NoError(t, err)
+
+		approvals, err := services.ApprovalService.FindAll(appVersion)
+		require.NoError(t, err)
+		foundApprovalIDs := []uuid.UUID{}
+		for _, approval := range approvals {
+			foundApprovalIDs = append(foundApprovalIDs, approval.ID)
+		}
+		require.Equal(t, sortedIDs(approvalIDs...), sortedIDs(foundApprovalIDs...))
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		appVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "ios"})
+		approval, err := services.ApprovalService.Create(&models.Approval{AppVersionID: appVersion.ID, Approver: "qa@bitrise.io", Comment: "Tested on device"})
+		require.NoError(t, err)
+		approval.Status = models.ApprovalStatusRevoked
+		approval.RevokeComment = "Crashes on launch"
+		approval.Comment = "Changed"
+		require.NoError(t, services.ApprovalService.Update(approval, []string{"Status", "RevokeComment"}))
+
+		foundApproval, err := services.ApprovalService.Find(&models.Approval{Record: models.Record{ID: approval.ID}})
+		require.NoError(t, err)
+		require.Equal(t, models.ApprovalStatusRevoked, foundApproval.Status)
+		require.Equal(t, "Crashes on launch", foundApproval.RevokeComment)
+		require.Equal(t, "Tested on device", foundApproval.Comment)
+	})
+
+	t.Run("InvalidateAll", func(t *testing.T) {
+		appVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "ios"})
+		otherAppVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "ios"})
+		valid, err := services.ApprovalService.Create(&models.Approval{AppVersionID: appVersion.ID, Approver: "qa@bitrise.io"})
+		require.NoError(t, err)
+		revoked, err := services.ApprovalService.Create(&models.Approval{AppVersionID: appVersion.ID, Approver: "product@bitrise.io", Status: models.ApprovalStatusRevoked})
+		require.NoError(t, err)
+		other, err := services.ApprovalService.Create(&models.Approval{AppVersionID: otherAppVersion.ID, Approver: "qa@bitrise.io"})
+		require.NoError(t, err)
+
+		invalidated, err := services.ApprovalService.InvalidateAll(appVersion)
+		require.NoError(t, err)
+		require.Equal(t, 1, invalidated)
+
+		for id, status := range map[uuid.UUID]string{
+			valid.ID:   models.ApprovalStatusInvalidated,
+			revoked.ID: models.ApprovalStatusRevoked,
+			other.ID:   models.ApprovalStatusApproved,
+		} {
+			foundApproval, err := services.ApprovalService.Find(&models.Approval{Record: models.Record{ID: id}})
+			require.NoError(t, err)
+			require.Equal(t, status, foundApproval.Status)
+		}
+	})
+}
@@ -0,0 +1,91 @@
+// Package contracttest is the shared test suite of the dataservices interfaces,
+// which every implementation of them has to pass.
+package contracttest
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/bitrise-io/addons-ship-backend/dataservices"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/c2fo/testify/require"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Services ...
+type Services struct {
+	AppService              dataservices.AppService
+	AppVersionService       dataservices.AppVersionService
+	AppSettingsService      dataservices.AppSettingsService
+	AppVersionEventService  dataservices.AppVersionEventService
+	PublishTaskService      dataservices.PublishTaskService
+	ScreenshotService       dataservices.ScreenshotService
+	FeatureGraphicService   dataservices.FeatureGraphicService
+	AppContactService       dataservices.AppContactService
+	ReleaseService          dataservices.ReleaseService
+	ScheduledPublishService dataservices.ScheduledPublishService
+	ApprovalService         dataservices.ApprovalService
+}
+
+// Setup returns the services to test on an empty data store, and a function
+// to be called when the test is done with them.
+type Setup func(t *testing.T) (Services, func())
+
+// Run ...
+func Run(t *testing.T, setup Setup) {
+	for _, test := range []struct {
+		name string
+		fn   func(t *testing.T, services Services)
+	}{
+		{name: "AppService", fn: testAppService},
+		{name: "AppVersionService", fn: testAppVersionService},
+		{name: "AppSettingsService", fn: testAppSettingsService},
+		{name: "AppVersionEventService", fn: testAppVersionEventService},
+		{name: "PublishTaskService", fn: testPublishTaskService},
+		{name: "ScreenshotService", fn: testScreenshotService},
+		{name: "FeatureGraphicService", fn: testFeatureGraphicService},
+		{name: "AppContactService", fn: testAppContactService},
+		{name: "ReleaseService", fn: testReleaseService},
+		{name: "ScheduledPublishService", fn: testScheduledPublishService},
+		{name: "ApprovalService", fn: testApprovalService},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			services, teardown := setup(t)
+			defer teardown()
+			test.fn(t, services)
+		})
+	}
+}
+
+func requireNotFound(t *testing.T, err error) {
+	require.Equal(t, gorm.ErrRecordNotFound, errors.Cause(err))
+}
+
+func createApp(t *testing.T, services Services, appSlug string) *models.App {
+	app, err := services.AppService.Create(&models.App{AppSlug: appSlug, Plan: "gold"})
+	require.NoError(t, err)
+	return app
+}
+
+func createAppVersion(t *testing.T, services Services, appVersion *models.AppVersion) *models.AppVersion {
+	if appVersion.ArtifactInfoData == nil {
+		appVersion.ArtifactInfoData = json.RawMessage(`{"version":"1.0"}`)
+	}
+	createdAppVersion, verrs, err := services.AppVersionService.Create(appVersion)
+	require.NoError(t, err)
+	require.Empty(t, verrs)
+	return createdAppVersion
+}
+
+// sortedIDs makes lists of records comparable regardless of their order.
+func sortedIDs(ids ...uuid.UUID) []string {
+	sorted := []string{}
+	for _, id := range ids {
+		sorted = append(sorted, id.String())
+	}
+	sort.Strings(sorted)
+	return sorted
+}
@@ -0,0 +1,99 @@
+package contracttest
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/c2fo/testify/require"
+	uuid "github.com/satori/go.uuid"
+)
+
+func testFeatureGraphicService(t *testing.T, services Services) {
+	app := createApp(t, services, "feature-graphic-test-app-slug")
+
+	newFeatureGraphic := func(appVersion *models.AppVersion, filename string, filesize int64) *models.FeatureGraphic {
+		return &models.FeatureGraphic{
+			AppVersionID:     appVersion.ID,
+			UploadableObject: models.UploadableObject{Filename: filename, Filesize: filesize},
+		}
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		appVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "android"})
+
+		featureGraphic, verrs, err := services.FeatureGraphicService.Create(newFeatureGraphic(appVersion, "create.png", 1234))
+		require.NoError(t, err)
+		require.Empty(t, verrs)
+		require.False(t, uuid.Equal(uuid.UUID{}, featureGraphic.ID))
+		require.Equal(t, "feature-graphic-test-app-slug", featureGraphic.AppVersion.App.AppSlug)
+
+		t.Log("when the app version already has a feature graphic")
+		_, verrs, err = services.FeatureGraphicService.Create(newFeatureGraphic(appVersion, "create-2.png", 1234))
+		require.NoError(t, err)
+		require.Equal(t, []string{"feature_graphics: Maximum count of feature graphics is 1"}, errorMessages(verrs))
+
+		t.Log("when filesize is too big")
+		otherAppVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "android"})
+		_, verrs, err = services.FeatureGraphicService.Create(newFeatureGraphic(otherAppVersion, "create-3.png", models.MaxFeatureGraphicFileByteSize+1))
+		require.NoError(t, err)
+		require.Equal(t, []string{"filesize: Must be smaller than 10 megabytes"}, errorMessages(verrs))
+	})
+
+	t.Run("Find", func(t *testing.T) {
+		appVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "android"})
+		featureGraphic, _, err := services.FeatureGraphicService.Create(newFeatureGraphic(appVersion, "find.png", 1234))
+		require.NoError(t, err)
+
+		foundFeatureGraphic, err := services.FeatureGraphicService.Find(&models.FeatureGraphic{AppVersionID: appVersion.ID})
+		require.NoError(t, err)
+		require.Equal(t, featureGraphic.ID, foundFeatureGraphic.ID)
+		require.Equal(t, "find.png", foundFeatureGraphic.Filename)
+		require.Equal(t, "feature-graphic-test-app-slug", foundFeatureGraphic.AppVersion.App.AppSlug)
+
+		_, err = services.FeatureGraphicService.Find(&models.FeatureGraphic{AppVersionID: uuid.NewV4()})
+		requireNotFound(t, err)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		appVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "android"})
+		featureGraphic, _, err := services.FeatureGraphicService.Create(newFeatureGraphic(appVersion, "update.png", 1234))
+		require.NoError(t, err)
+
+		featureGraphic.Uploaded = true
+		featureGraphic.Filename = "renamed.png"
+		verrs, err := services.FeatureGraphicService.Update(*featureGraphic, []string{"Uploaded"})
+		require.NoError(t, err)
+		require.Empty(t, verrs)
+
+		foundFeatureGraphic, err := services.FeatureGraphicService.Find(&models.FeatureGraphic{Record: models.Record{ID: featureGraphic.ID}})
+		require.NoError(t, err)
+		require.True(t, foundFeatureGraphic.Uploaded)
+		require.Equal(t, "update.png", foundFeatureGraphic.Filename)
+
+		t.Log("when filesize is too big")
+		featureGraphic.Filesize = models.MaxFeatureGraphicFileByteSize + 1
+		verrs, err = services.FeatureGraphicService.Update(*featureGraphic, []string{"Filesize"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"filesize: Must be smaller than 10 megabytes"}, errorMessages(verrs))
+
+		t.Log("when trying to update non-existing field")
+		_, err = services.FeatureGraphicService.Update(*featureGraphic, []string{"NonExistingField"})
+		require.EqualError(t, err, "Attribute name doesn't exist in the model")
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		appVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "android"})
+		featureGraphic, _, err := services.FeatureGraphicService.Create(newFeatureGraphic(appVersion, "delete.png", 1234))
+		require.NoError(t, err)
+
+		require.NoError(t, services.FeatureGraphicService.Delete(featureGraphic))
+		_, err = services.FeatureGraphicService.Find(&models.FeatureGraphic{Record: models.Record{ID: featureGraphic.ID}})
+		requireNotFound(t, err)
+		requireNotFound(t, services.FeatureGraphicService.Delete(featureGraphic))
+
+		t.Log("a new feature graphic can be created for the app version")
+		_, verrs, err := services.FeatureGraphicService.Create(newFeatureGraphic(appVersion, "delete-2.png", 1234))
+		require.NoError(t, err)
+		require.Empty(t, verrs)
+	})
+}
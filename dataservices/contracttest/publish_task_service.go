@@ -0,0 +1,90 @@
+package contracttest
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/c2fo/testify/require"
+	uuid "github.com/satori/go.uuid"
+)
+
+func testPublishTaskService(t *testing.T, services Services) {
+	app := createApp(t, services, "publish-task-test-app-slug")
+	appVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "ios", BuildSlug: "publish-task-build-slug"})
+
+	t.Run("Create", func(t *testing.T) {
+		publishTask, err := services.PublishTaskService.Create(&models.PublishTask{TaskID: uuid.NewV4(), AppVersionID: appVersion.ID})
+		require.NoError(t, err)
+		require.False(t, uuid.Equal(uuid.UUID{}, publishTask.ID))
+		require.False(t, publishTask.CreatedAt.IsZero())
+		require.Equal(t, models.PublishTaskStatusPending, publishTask.Status)
+		require.Equal(t, models.PublishTaskActionPublish, publishTask.Action)
+	})
+
+	t.Run("Find", func(t *testing.T) {
+		taskID := uuid.NewV4()
+		publishTask, err := services.PublishTaskService.Create(&models.PublishTask{TaskID: taskID, AppVersionID: appVersion.ID})
+		require.NoError(t, err)
+
+		foundPublishTask, err := services.PublishTaskService.Find(&models.PublishTask{TaskID: taskID})
+		require.NoError(t, err)
+		require.Equal(t, publishTask.ID, foundPublishTask.ID)
+		require.Equal(t, "publish-task-build-slug", foundPublishTask.AppVersion.BuildSlug)
+
+		_, err = services.PublishTaskService.Find(&models.PublishTask{TaskID: uuid.NewV4()})
+		requireNotFound(t, err)
+	})
+
+	t.Run("FindAll", func(t *testing.T) {
+		appVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "android"})
+		otherAppVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "android"})
+		batchID := uuid.NewV4()
+		firstPublishTask, err := services.PublishTaskService.Create(&models.PublishTask{TaskID: uuid.NewV4(), AppVersionID: appVersion.ID, Destination: "google-play", Track: "internal", BatchID: batchID})
+		require.NoError(t, err)
+		secondPublishTask, err := services.PublishTaskService.Create(&models.PublishTask{TaskID: uuid.NewV4(), AppVersionID: appVersion.ID, Destination: "google-play", Track: "beta", BatchID: batchID})
+		require.NoError(t, err)
+		_, err = services.PublishTaskService.Create(&models.PublishTask{TaskID: uuid.NewV4(), AppVersionID: otherAppVersion.ID})
+		require.NoError(t, err)
+
+		foundPublishTasks, err := services.PublishTaskService.FindAll(appVersion)
+		require.NoError(t, err)
+		require.Len(t, foundPublishTasks, 2)
+		require.Equal(t, firstPublishTask.ID, foundPublishTasks[0].ID)
+		require.Equal(t, "internal", foundPublishTasks[0].Track)
+		require.Equal(t, secondPublishTask.ID, foundPublishTasks[1].ID)
+		require.Equal(t, batchID, foundPublishTasks[1].BatchID)
+	})
+
+	t.Run("FindAllForApp", func(t *testing.T) {
+		otherApp := createApp(t, services, "publish-task-test-other-app-slug")
+		appVersion := createAppVersion(t, services, &models.AppVersion{AppID: otherApp.ID, Platform: "android", BuildSlug: "find-all-for-app-build-slug"})
+		publishTask, err := services.PublishTaskService.Create(&models.PublishTask{TaskID: uuid.NewV4(), AppVersionID: appVersion.ID, Action: models.PublishTaskActionPromote, FromTrack: "internal", Track: "beta"})
+		require.NoError(t, err)
+
+		foundPublishTasks, err := services.PublishTaskService.FindAllForApp(otherApp)
+		require.NoError(t, err)
+		require.Len(t, foundPublishTasks, 1)
+		require.Equal(t, publishTask.ID, foundPublishTasks[0].ID)
+		require.Equal(t, "internal", foundPublishTasks[0].FromTrack)
+		require.Equal(t, "find-all-for-app-build-slug", foundPublishTasks[0].AppVersion.BuildSlug)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		taskID := uuid.NewV4()
+		publishTask, err := services.PublishTaskService.Create(&models.PublishTask{TaskID: taskID, AppVersionID: appVersion.ID, Destination: "app-store-connect"})
+		require.NoError(t, err)
+
+		publishTask.Status = models.PublishTaskStatusSuccess
+		publishTask.Destination = "google-play"
+		require.NoError(t, services.PublishTaskService.Update(publishTask, []string{"Status"}))
+
+		foundPublishTask, err := services.PublishTaskService.Find(&models.PublishTask{TaskID: taskID})
+		require.NoError(t, err)
+		require.Equal(t, models.PublishTaskStatusSuccess, foundPublishTask.Status)
+		require.Equal(t, "app-store-connect", foundPublishTask.Destination)
+		require.True(t, foundPublishTask.Finished())
+
+		t.Log("when trying to update non-existing field")
+		require.EqualError(t, services.PublishTaskService.Update(publishTask, []string{"NonExistingField"}), "Attribute name doesn't exist in the model")
+	})
+}
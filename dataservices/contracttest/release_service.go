@@ -0,0 +1,75 @@
+package contracttest
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/c2fo/testify/require"
+	uuid "github.com/satori/go.uuid"
+)
+
+func testReleaseService(t *testing.T, services Services) {
+	app := createApp(t, services, "release-test-app-slug")
+
+	t.Run("Create", func(t *testing.T) {
+		release, err := services.ReleaseService.Create(&models.Release{AppID: app.ID, BuildSlug: "create-build-slug", Version: "1.0"})
+		require.NoError(t, err)
+		require.False(t, uuid.Equal(uuid.UUID{}, release.ID))
+		require.Equal(t, "release-test-app-slug", release.App.AppSlug)
+		require.Empty(t, release.AppVersions)
+	})
+
+	t.Run("Find", func(t *testing.T) {
+		release, err := services.ReleaseService.Create(&models.Release{AppID: app.ID, BuildSlug: "find-build-slug", Version: "1.1"})
+		require.NoError(t, err)
+		iosVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "ios", ReleaseID: &release.ID})
+		androidVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "android", ReleaseID: &release.ID})
+		createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "android"})
+
+		foundRelease, err := services.ReleaseService.Find(&models.Release{AppID: app.ID, Version: "1.1"})
+		require.NoError(t, err)
+		require.Equal(t, release.ID, foundRelease.ID)
+		require.Equal(t, "release-test-app-slug", foundRelease.App.AppSlug)
+		foundVersionIDs := []uuid.UUID{}
+		for _, appVersion := range foundRelease.AppVersions {
+			foundVersionIDs = append(foundVersionIDs, appVersion.ID)
+		}
+		require.Equal(t, sortedIDs(iosVersion.ID, androidVersion.ID), sortedIDs(foundVersionIDs...))
+
+		_, err = services.ReleaseService.Find(&models.Release{AppID: app.ID, BuildSlug: "other-build-slug"})
+		requireNotFound(t, err)
+	})
+
+	t.Run("FindAll", func(t *testing.T) {
+		otherApp := createApp(t, services, "other-release-test-app-slug")
+		releases := []uuid.UUID{}
+		for _, buildSlug := range []string{"build-slug-1", "build-slug-2"} {
+			release, err := services.ReleaseService.Create(&models.Release{AppID: otherApp.ID, BuildSlug: buildSlug})
+			require.NoError(t, err)
+			releases = append(releases, release.ID)
+		}
+
+		foundReleases, err := services.ReleaseService.FindAll(otherApp)
+		require.NoError(t, err)
+		foundReleaseIDs := []uuid.UUID{}
+		for _, release := range foundReleases {
+			foundReleaseIDs = append(foundReleaseIDs, release.ID)
+		}
+		require.Equal(t, sortedIDs(releases...), sortedIDs(foundReleaseIDs...))
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		release, err := services.ReleaseService.Create(&models.Release{AppID: app.ID, BuildSlug: "update-build-slug", Version: "1.2"})
+		require.NoError(t, err)
+		release.Notes = "Bug fixes on both platforms"
+		release.Version = "2.0"
+		verrs, err := services.ReleaseService.Update(release, []string{"Notes"})
+		require.NoError(t, err)
+		require.Empty(t, verrs)
+
+		foundRelease, err := services.ReleaseService.Find(&models.Release{Record: models.Record{ID: release.ID}})
+		require.NoError(t, err)
+		require.Equal(t, "Bug fixes on both platforms", foundRelease.Notes)
+		require.Equal(t, "1.2", foundRelease.Version)
+	})
+}
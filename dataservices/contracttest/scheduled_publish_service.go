@@ -0,0 +1,115 @@
+package contracttest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/c2fo/testify/require"
+	uuid "github.com/satori/go.uuid"
+)
+
+func testScheduledPublishService(t *testing.T, services Services) {
+	app := createApp(t, services, "scheduled-publish-test-app-slug")
+	scheduledAt := time.Date(2019, time.December, 2, 9, 0, 0, 0, time.UTC)
+
+	t.Run("Create", func(t *testing.T) {
+		appVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "ios"})
+		scheduledPublish := &models.ScheduledPublish{AppVersionID: appVersion.ID, ScheduledAt: scheduledAt}
+		require.NoError(t, scheduledPublish.SetDestinations([]models.ScheduledPublishDestination{{ID: "app-store-connect", Track: "testflight"}}))
+		scheduledPublish, err := services.ScheduledPublishService.Create(scheduledPublish)
+		require.NoError(t, err)
+		require.False(t, uuid.Equal(uuid.UUID{}, scheduledPublish.ID))
+		require.Equal(t, models.ScheduledPublishStatusScheduled, scheduledPublish.Status)
+
+		foundScheduledPublish, err := services.ScheduledPublishService.Find(&models.ScheduledPublish{Record: models.Record{ID: scheduledPublish.ID}})
+		require.NoError(t, err)
+		require.True(t, scheduledAt.Equal(foundScheduledPublish.ScheduledAt))
+		destinations, err := foundScheduledPublish.Destinations()
+		require.NoError(t, err)
+		require.Equal(t, []models.ScheduledPublishDestination{{ID: "app-store-connect", Track: "testflight"}}, destinations)
+	})
+
+	t.Run("Find", func(t *testing.T) {
+		appVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "android"})
+		scheduledPublish, err := services.ScheduledPublishService.Create(&models.ScheduledPublish{AppVersionID: appVersion.ID, ScheduledAt: scheduledAt})
+		require.NoError(t, err)
+
+		foundScheduledPublish, err := services.ScheduledPublishService.Find(&models.ScheduledPublish{Record: models.Record{ID: scheduledPublish.ID}, AppVersionID: appVersion.ID})
+		require.NoError(t, err)
+		require.Equal(t, appVersion.ID, foundScheduledPublish.AppVersion.ID)
+		require.Equal(t, "scheduled-publish-test-app-slug", foundScheduledPublish.AppVersion.App.AppSlug)
+		destinations, err := foundScheduledPublish.Destinations()
+		require.NoError(t, err)
+		require.Empty(t, destinations)
+
+		_, err = services.ScheduledPublishService.Find(&models.ScheduledPublish{Record: models.Record{ID: scheduledPublish.ID}, AppVersionID: uuid.NewV4()})
+		requireNotFound(t, err)
+	})
+
+	t.Run("FindAll", func(t *testing.T) {
+		appVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "ios"})
+		otherAppVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "ios"})
+		later, err := services.ScheduledPublishService.Create(&models.ScheduledPublish{AppVersionID: appVersion.ID, ScheduledAt: scheduledAt.Add(time.Hour)})
+		require.NoError(t, err)
+		earlier, err := services.ScheduledPublishService.Create(&models.ScheduledPublish{AppVersionID: appVersion.ID, ScheduledAt: scheduledAt})
+		require.NoError(t, err)
+		_, err = services.ScheduledPublishService.Create(&models.ScheduledPublish{AppVersionID: otherAppVersion.ID, ScheduledAt: scheduledAt})
+		require.NoError(t, err)
+
+		scheduledPublishes, err := services.ScheduledPublishService.FindAll(appVersion)
+		require.NoError(t, err)
+		require.Len(t, scheduledPublishes, 2)
+		require.Equal(t, earlier.ID, scheduledPublishes[0].ID)
+		require.Equal(t, later.ID, scheduledPublishes[1].ID)
+	})
+
+	t.Run("FindAllDue", func(t *testing.T) {
+		otherApp := createApp(t, services, "due-scheduled-publish-test-app-slug")
+		appVersion := createAppVersion(t, services, &models.AppVersion{AppID: otherApp.ID, Platform: "ios"})
+		dueAt := time.Date(2019, time.December, 9, 9, 0, 0, 0, time.UTC)
+		due, err := services.ScheduledPublishService.Create(&models.ScheduledPublish{AppVersionID: appVersion.ID, ScheduledAt: dueAt})
+		require.NoError(t, err)
+		_, err = services.ScheduledPublishService.Create(&models.ScheduledPublish{AppVersionID: appVersion.ID, ScheduledAt: dueAt.Add(time.Minute)})
+		require.NoError(t, err)
+		_, err = services.ScheduledPublishService.Create(&models.ScheduledPublish{AppVersionID: appVersion.ID, ScheduledAt: dueAt, Status: models.ScheduledPublishStatusCanceled})
+		require.NoError(t, err)
+
+		deprovisionedApp := createApp(t, services, "due-scheduled-publish-deprovisioned-test-app-slug")
+		deprovisionedAppVersion := createAppVersion(t, services, &models.AppVersion{AppID: deprovisionedApp.ID, Platform: "ios"})
+		_, err = services.ScheduledPublishService.Create(&models.ScheduledPublish{AppVersionID: deprovisionedAppVersion.ID, ScheduledAt: dueAt})
+		require.NoError(t, err)
+		deprovisionedApp.DeprovisionedAt = &dueAt
+		verrs, err := services.AppService.Update(deprovisionedApp, []string{"DeprovisionedAt"})
+		require.NoError(t, err)
+		require.Empty(t, verrs)
+
+		scheduledPublishes, err := services.ScheduledPublishService.FindAllDue(dueAt)
+		require.NoError(t, err)
+		dueIDs := []uuid.UUID{}
+		for _, scheduledPublish := range scheduledPublishes {
+			require.False(t, uuid.Equal(scheduledPublish.AppVersionID, deprovisionedAppVersion.ID))
+			if uuid.Equal(scheduledPublish.AppVersionID, appVersion.ID) {
+				require.Equal(t, "due-scheduled-publish-test-app-slug", scheduledPublish.AppVersion.App.AppSlug)
+				dueIDs = append(dueIDs, scheduledPublish.ID)
+			}
+		}
+		require.Equal(t, []uuid.UUID{due.ID}, dueIDs)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		appVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "ios"})
+		scheduledPublish, err := services.ScheduledPublishService.Create(&models.ScheduledPublish{AppVersionID: appVersion.ID, ScheduledAt: scheduledAt})
+		require.NoError(t, err)
+		scheduledPublish.Status = models.ScheduledPublishStatusFailed
+		scheduledPublish.FailureReason = "version is not ready to publish"
+		scheduledPublish.ScheduledAt = scheduledAt.Add(time.Hour)
+		require.NoError(t, services.ScheduledPublishService.Update(scheduledPublish, []string{"Status", "FailureReason"}))
+
+		foundScheduledPublish, err := services.ScheduledPublishService.Find(&models.ScheduledPublish{Record: models.Record{ID: scheduledPublish.ID}})
+		require.NoError(t, err)
+		require.Equal(t, models.ScheduledPublishStatusFailed, foundScheduledPublish.Status)
+		require.Equal(t, "version is not ready to publish", foundScheduledPublish.FailureReason)
+		require.True(t, scheduledAt.Equal(foundScheduledPublish.ScheduledAt))
+	})
+}
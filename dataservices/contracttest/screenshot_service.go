@@ -0,0 +1,127 @@
+package contracttest
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/c2fo/testify/require"
+	uuid "github.com/satori/go.uuid"
+)
+
+func testScreenshotService(t *testing.T, services Services) {
+	app := createApp(t, services, "screenshot-test-app-slug")
+
+	newScreenshot := func(appVersion *models.AppVersion, filename string, filesize int64) *models.Screenshot {
+		return &models.Screenshot{
+			AppVersionID:     appVersion.ID,
+			DeviceType:       "iPhone XS Max",
+			ScreenSize:       "6.5 inch",
+			UploadableObject: models.UploadableObject{Filename: filename, Filesize: filesize},
+		}
+	}
+
+	t.Run("BatchCreate", func(t *testing.T) {
+		appVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "ios"})
+
+		screenshots, verrs, err := services.ScreenshotService.BatchCreate([]*models.Screenshot{
+			newScreenshot(appVersion, "batch-create-1.png", 1234),
+			newScreenshot(appVersion, "batch-create-2.png", 1234),
+		})
+		require.NoError(t, err)
+		require.Empty(t, verrs)
+		require.Len(t, screenshots, 2)
+		require.False(t, uuid.Equal(uuid.UUID{}, screenshots[0].ID))
+		require.Equal(t, "ios", screenshots[0].AppVersion.Platform)
+		require.Equal(t, "screenshot-test-app-slug", screenshots[1].AppVersion.App.AppSlug)
+
+		t.Log("when filesize is too big, none of the screenshots get created")
+		otherAppVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "ios"})
+		screenshots, verrs, err = services.ScreenshotService.BatchCreate([]*models.Screenshot{
+			newScreenshot(otherAppVersion, "batch-create-3.png", 1234),
+			newScreenshot(otherAppVersion, "batch-create-4.png", models.MaxScreenshotFileByteSize+1),
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"filesize: Must be smaller than 10 megabytes"}, errorMessages(verrs))
+		require.Empty(t, screenshots)
+
+		foundScreenshots, err := services.ScreenshotService.FindAll(otherAppVersion)
+		require.NoError(t, err)
+		require.Empty(t, foundScreenshots)
+	})
+
+	t.Run("Find", func(t *testing.T) {
+		appVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "ios"})
+		screenshots, _, err := services.ScreenshotService.BatchCreate([]*models.Screenshot{newScreenshot(appVersion, "find.png", 1234)})
+		require.NoError(t, err)
+
+		foundScreenshot, err := services.ScreenshotService.Find(&models.Screenshot{Record: models.Record{ID: screenshots[0].ID}, AppVersionID: appVersion.ID})
+		require.NoError(t, err)
+		require.Equal(t, "find.png", foundScreenshot.Filename)
+
+		_, err = services.ScreenshotService.Find(&models.Screenshot{Record: models.Record{ID: screenshots[0].ID}, AppVersionID: uuid.NewV4()})
+		requireNotFound(t, err)
+	})
+
+	t.Run("FindAll", func(t *testing.T) {
+		appVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "ios"})
+		otherAppVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "ios"})
+		screenshots, _, err := services.ScreenshotService.BatchCreate([]*models.Screenshot{
+			newScreenshot(appVersion, "find-all-1.png", 1234),
+			newScreenshot(appVersion, "find-all-2.png", 1234),
+			newScreenshot(otherAppVersion, "find-all-3.png", 1234),
+		})
+		require.NoError(t, err)
+
+		foundScreenshots, err := services.ScreenshotService.FindAll(appVersion)
+		require.NoError(t, err)
+		foundIDs := []uuid.UUID{}
+		for _, screenshot := range foundScreenshots {
+			require.Equal(t, "screenshot-test-app-slug", screenshot.AppVersion.App.AppSlug)
+			foundIDs = append(foundIDs, screenshot.ID)
+		}
+		require.Equal(t, sortedIDs(screenshots[0].ID, screenshots[1].ID), sortedIDs(foundIDs...))
+	})
+
+	t.Run("BatchUpdate", func(t *testing.T) {
+		appVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "ios"})
+		screenshots, _, err := services.ScreenshotService.BatchCreate([]*models.Screenshot{
+			newScreenshot(appVersion, "batch-update-1.png", 1234),
+			newScreenshot(appVersion, "batch-update-2.png", 1234),
+		})
+		require.NoError(t, err)
+
+		updates := []models.Screenshot{*screenshots[0], *screenshots[1]}
+		updates[0].Uploaded = true
+		updates[1].Uploaded = true
+		updates[1].Filename = "renamed.png"
+		verrs, err := services.ScreenshotService.BatchUpdate(updates, []string{"Uploaded"})
+		require.NoError(t, err)
+		require.Empty(t, verrs)
+
+		foundScreenshot, err := services.ScreenshotService.Find(&models.Screenshot{Record: models.Record{ID: screenshots[1].ID}})
+		require.NoError(t, err)
+		require.True(t, foundScreenshot.Uploaded)
+		require.Equal(t, "batch-update-2.png", foundScreenshot.Filename)
+
+		t.Log("when filesize is too big")
+		updates[0].Filesize = models.MaxScreenshotFileByteSize + 1
+		verrs, err = services.ScreenshotService.BatchUpdate(updates[:1], []string{"Filesize"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"filesize: Must be smaller than 10 megabytes"}, errorMessages(verrs))
+
+		t.Log("when trying to update non-existing field")
+		_, err = services.ScreenshotService.BatchUpdate(updates, []string{"NonExistingField"})
+		require.EqualError(t, err, "Attribute name doesn't exist in the model")
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		appVersion := createAppVersion(t, services, &models.AppVersion{AppID: app.ID, Platform: "ios"})
+		screenshots, _, err := services.ScreenshotService.BatchCreate([]*models.Screenshot{newScreenshot(appVersion, "delete.png", 1234)})
+		require.NoError(t, err)
+
+		require.NoError(t, services.ScreenshotService.Delete(screenshots[0]))
+		_, err = services.ScreenshotService.Find(&models.Screenshot{Record: models.Record{ID: screenshots[0].ID}})
+		requireNotFound(t, err)
+		requireNotFound(t, services.ScreenshotService.Delete(screenshots[0]))
+	})
+}
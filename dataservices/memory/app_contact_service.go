@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"encoding/json"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/jinzhu/gorm"
+	uuid "github.com/satori/go.uuid"
+)
+
+// AppContactService ...
+type AppContactService struct {
+	Store *Store
+	models.UpdatableModelService
+}
+
+// Create ...
+func (s *AppContactService) Create(appContact *models.AppContact) (*models.AppContact, []error, error) {
+	if appContact.NotificationPreferencesData == nil {
+		appContact.NotificationPreferencesData = json.RawMessage(`{}`)
+	}
+	if verrs := appContact.Validate(); len(verrs) > 0 {
+		return nil, verrs, nil
+	}
+	if err := validateJSONColumns(*appContact); err != nil {
+		return nil, nil, err
+	}
+
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	assignForeignKeys(appContact)
+	newRecord(&appContact.Record)
+	s.Store.appContacts = append(s.Store.appContacts, detach(*appContact).(models.AppContact))
+	app := s.Store.appByID(appContact.AppID)
+	appContact.App = &app
+	return appContact, nil, nil
+}
+
+// Find ...
+func (s *AppContactService) Find(appContact *models.AppContact) (*models.AppContact, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	condition := detach(*appContact)
+	i := firstIndex(len(s.Store.appContacts),
+		func(i int) uuid.UUID { return s.Store.appContacts[i].ID },
+		func(i int) bool { return matchesStruct(condition, s.Store.appContacts[i]) })
+	if i < 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	*appContact = detach(s.Store.appContacts[i]).(models.AppContact)
+	app := s.Store.appByID(appContact.AppID)
+	appContact.App = &app
+	return appContact, nil
+}
+
+// FindAll ...
+func (s *AppContactService) FindAll(app *models.App) ([]models.AppContact, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	appContacts := []models.AppContact{}
+	for _, appContact := range s.Store.appContacts {
+		if uuid.Equal(appContact.AppID, app.ID) {
+			appContacts = append(appContacts, detach(appContact).(models.AppContact))
+		}
+	}
+	return appContacts, nil
+}
+
+// Update ...
+func (s *AppContactService) Update(appContact *models.AppContact, whitelist []string) error {
+	if _, err := s.UpdateData(*appContact, whitelist); err != nil {
+		return err
+	}
+	if verrs := appContact.Validate(); len(verrs) > 0 {
+		return validationError(verrs)
+	}
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	touchRecord(&appContact.Record)
+	for i := range s.Store.appContacts {
+		if !uuid.Equal(s.Store.appContacts[i].ID, appContact.ID) {
+			continue
+		}
+		updated := s.Store.appContacts[i]
+		if err := updateAttributes(&updated, appContact, whitelist); err != nil {
+			return err
+		}
+		s.Store.appContacts[i] = updated
+	}
+	return nil
+}
+
+// Delete ...
+func (s *AppContactService) Delete(appContact *models.AppContact) error {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	for i, storedAppContact := range s.Store.appContacts {
+		if uuid.Equal(storedAppContact.ID, appContact.ID) {
+			s.Store.appContacts = append(s.Store.appContacts[:i], s.Store.appContacts[i+1:]...)
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
@@ -0,0 +1,126 @@
+package memory
+
+import (
+	"bytes"
+	"sort"
+	"time"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// AppService ...
+type AppService struct {
+	Store *Store
+	models.UpdatableModelService
+}
+
+// Create ...
+func (a *AppService) Create(app *models.App) (*models.App, error) {
+	a.Store.mu.Lock()
+	defer a.Store.mu.Unlock()
+
+	newRecord(&app.Record)
+	err := app.GenerateSecret(func(iv []byte) (bool, error) {
+		for _, storedApp := range a.Store.apps {
+			if bytes.Equal(storedApp.EncryptedSecretIV, iv) {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	a.Store.apps = append(a.Store.apps, detach(*app).(models.App))
+
+	app.AppSettings.App = app
+	app.AppSettings.AppID = app.ID
+	newAppSettings(&app.AppSettings)
+	a.Store.appSettings = append(a.Store.appSettings, detach(app.AppSettings).(models.AppSettings))
+	return app, nil
+}
+
+// Find ...
+func (a *AppService) Find(app *models.App) (*models.App, error) {
+	return a.find(app, false)
+}
+
+// FindDeprovisioned ...
+func (a *AppService) FindDeprovisioned(app *models.App) (*models.App, error) {
+	return a.find(app, true)
+}
+
+func (a *AppService) find(app *models.App, deprovisioned bool) (*models.App, error) {
+	a.Store.mu.RLock()
+	defer a.Store.mu.RUnlock()
+
+	condition := detach(*app)
+	i := firstIndex(len(a.Store.apps),
+		func(i int) uuid.UUID { return a.Store.apps[i].ID },
+		func(i int) bool {
+			return (a.Store.apps[i].DeprovisionedAt != nil) == deprovisioned && matchesStruct(condition, a.Store.apps[i])
+		})
+	if i < 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	*app = detach(a.Store.apps[i]).(models.App)
+	if !deprovisioned {
+		app.AppVersions = a.Store.appVersionsOfApp(app.ID, 100)
+	}
+	return app, nil
+}
+
+// FindAllDeprovisionedBefore ...
+func (a *AppService) FindAllDeprovisionedBefore(deprovisionedBefore time.Time) ([]models.App, error) {
+	a.Store.mu.RLock()
+	defer a.Store.mu.RUnlock()
+
+	apps := []models.App{}
+	for _, app := range a.Store.apps {
+		if app.DeprovisionedAt == nil || !app.DeprovisionedAt.Before(deprovisionedBefore) {
+			continue
+		}
+		apps = append(apps, detach(app).(models.App))
+	}
+	sort.SliceStable(apps, func(i, j int) bool {
+		return apps[i].DeprovisionedAt.Before(*apps[j].DeprovisionedAt)
+	})
+	return apps, nil
+}
+
+// Update ...
+func (a *AppService) Update(app *models.App, whitelist []string) (validationErrors []error, dbErr error) {
+	if _, err := a.UpdateData(*app, whitelist); err != nil {
+		return nil, err
+	}
+	a.Store.mu.Lock()
+	defer a.Store.mu.Unlock()
+
+	touchRecord(&app.Record)
+	for i := range a.Store.apps {
+		if !uuid.Equal(a.Store.apps[i].ID, app.ID) {
+			continue
+		}
+		updated := a.Store.apps[i]
+		if err := updateAttributes(&updated, app, whitelist); err != nil {
+			return nil, err
+		}
+		a.Store.apps[i] = updated
+	}
+	return nil, nil
+}
+
+// Delete ...
+func (a *AppService) Delete(app *models.App) error {
+	a.Store.mu.Lock()
+	defer a.Store.mu.Unlock()
+
+	if !a.Store.deleteApp(app.ID) {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package memory
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/jinzhu/gorm"
+	uuid "github.com/satori/go.uuid"
+)
+
+// AppSettingsService ...
+type AppSettingsService struct {
+	Store *Store
+	models.UpdatableModelService
+}
+
+func newAppSettings(appSettings *models.AppSettings) {
+	assignForeignKeys(appSettings)
+	newRecord(&appSettings.Record)
+	if appSettings.IosSettingsData == nil {
+		appSettings.IosSettingsData = json.RawMessage(`{}`)
+	}
+	if appSettings.AndroidSettingsData == nil {
+		appSettings.AndroidSettingsData = json.RawMessage(`{}`)
+	}
+	if appSettings.CodeSigningExpiryData == nil {
+		appSettings.CodeSigningExpiryData = json.RawMessage(`{}`)
+	}
+	if appSettings.RetentionPolicyData == nil {
+		appSettings.RetentionPolicyData = json.RawMessage(`{}`)
+	}
+	if appSettings.VersionRulesData == nil {
+		appSettings.VersionRulesData = json.RawMessage(`{}`)
+	}
+	if appSettings.AutoPublishRulesData == nil {
+		appSettings.AutoPublishRulesData = json.RawMessage(`[]`)
+	}
+	if appSettings.ApprovalRequirementsData == nil {
+		appSettings.ApprovalRequirementsData = json.RawMessage(`[]`)
+	}
+}
+
+// Find ...
+func (s *AppSettingsService) Find(appSettings *models.AppSettings) (*models.AppSettings, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	condition := detach(*appSettings)
+	i := firstIndex(len(s.Store.appSettings),
+		func(i int) uuid.UUID { return s.Store.appSettings[i].ID },
+		func(i int) bool { return matchesStruct(condition, s.Store.appSettings[i]) })
+	if i < 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	*appSettings = detach(s.Store.appSettings[i]).(models.AppSettings)
+	app := s.Store.appByID(appSettings.AppID)
+	appSettings.App = &app
+	return appSettings, nil
+}
+
+// FindAllWithCodeSigningExpiringBefore ...
+func (s *AppSettingsService) FindAllWithCodeSigningExpiringBefore(expiresBefore time.Time) ([]models.AppSettings, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	appSettingsList := []models.AppSettings{}
+	for _, appSettings := range s.Store.appSettings {
+		if appSettings.CodeSigningExpiresAt == nil || !appSettings.CodeSigningExpiresAt.Before(expiresBefore) {
+			continue
+		}
+		app := s.Store.appByID(appSettings.AppID)
+		if app.DeprovisionedAt != nil {
+			continue
+		}
+		appSettings = detach(appSettings).(models.AppSettings)
+		appSettings.App = &app
+		appSettingsList = append(appSettingsList, appSettings)
+	}
+	sort.SliceStable(appSettingsList, func(i, j int) bool {
+		return appSettingsList[i].CodeSigningExpiresAt.Before(*appSettingsList[j].CodeSigningExpiresAt)
+	})
+	return appSettingsList, nil
+}
+
+// FindAllWithRetentionPolicy ...
+func (s *AppSettingsService) FindAllWithRetentionPolicy() ([]models.AppSettings, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	appSettingsList := []models.AppSettings{}
+	for _, appSettings := range s.Store.appSettings {
+		retentionPolicy, err := appSettings.RetentionPolicy()
+		if err != nil || !retentionPolicy.Enabled() {
+			continue
+		}
+		app := s.Store.appByID(appSettings.AppID)
+		if app.DeprovisionedAt != nil {
+			continue
+		}
+		appSettings = detach(appSettings).(models.AppSettings)
+		appSettings.App = &app
+		appSettingsList = append(appSettingsList, appSettings)
+	}
+	sort.SliceStable(appSettingsList, func(i, j int) bool {
+		return appSettingsList[i].CreatedAt.Before(appSettingsList[j].CreatedAt)
+	})
+	return appSettingsList, nil
+}
+
+// Update ...
+func (s *AppSettingsService) Update(appSettings *models.AppSettings, whitelist []string) (validationErrors []error, dbErr error) {
+	if _, err := s.UpdateData(*appSettings, whitelist); err != nil {
+		return nil, err
+	}
+	if verrs := appSettings.Validate(); len(verrs) > 0 {
+		return verrs, nil
+	}
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	touchRecord(&appSettings.Record)
+	for i := range s.Store.appSettings {
+		if !uuid.Equal(s.Store.appSettings[i].ID, appSettings.ID) {
+			continue
+		}
+		updated := s.Store.appSettings[i]
+		if err := updateAttributes(&updated, appSettings, whitelist); err != nil {
+			return nil, err
+		}
+		s.Store.appSettings[i] = updated
+	}
+	return nil, nil
+}
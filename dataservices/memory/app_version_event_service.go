@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/jinzhu/gorm"
+	uuid "github.com/satori/go.uuid"
+)
+
+// AppVersionEventService ...
+type AppVersionEventService struct {
+	Store *Store
+	models.UpdatableModelService
+}
+
+// Create ...
+func (a *AppVersionEventService) Create(appVersionEvent *models.AppVersionEvent) (*models.AppVersionEvent, error) {
+	a.Store.mu.Lock()
+	defer a.Store.mu.Unlock()
+
+	assignForeignKeys(appVersionEvent)
+	newRecord(&appVersionEvent.Record)
+	a.Store.appVersionEvents = append(a.Store.appVersionEvents, detach(*appVersionEvent).(models.AppVersionEvent))
+	appVersionEvent.AppVersion = a.Store.appVersionWithApp(appVersionEvent.AppVersionID)
+	return appVersionEvent, nil
+}
+
+// Find ...
+func (a *AppVersionEventService) Find(appVersionEvent *models.AppVersionEvent) (*models.AppVersionEvent, error) {
+	a.Store.mu.RLock()
+	defer a.Store.mu.RUnlock()
+
+	condition := detach(*appVersionEvent)
+	i := firstIndex(len(a.Store.appVersionEvents),
+		func(i int) uuid.UUID { return a.Store.appVersionEvents[i].ID },
+		func(i int) bool { return matchesStruct(condition, a.Store.appVersionEvents[i]) })
+	if i < 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	*appVersionEvent = detach(a.Store.appVersionEvents[i]).(models.AppVersionEvent)
+	appVersionEvent.AppVersion = a.Store.appVersionWithApp(appVersionEvent.AppVersionID)
+	return appVersionEvent, nil
+}
+
+// FindAll ...
+func (a *AppVersionEventService) FindAll(appVersion *models.AppVersion) ([]models.AppVersionEvent, error) {
+	a.Store.mu.RLock()
+	defer a.Store.mu.RUnlock()
+
+	appVersionEvents := []models.AppVersionEvent{}
+	for _, appVersionEvent := range a.Store.appVersionEvents {
+		if !uuid.Equal(appVersionEvent.AppVersionID, appVersion.ID) {
+			continue
+		}
+		event := detach(appVersionEvent).(models.AppVersionEvent)
+		event.AppVersion = a.Store.appVersionWithApp(event.AppVersionID)
+		appVersionEvents = append(appVersionEvents, event)
+	}
+	return appVersionEvents, nil
+}
+
+// Update ...
+func (a *AppVersionEventService) Update(appVersionEvent *models.AppVersionEvent, whitelist []string) (validationErrors []error, dbErr error) {
+	if _, err := a.UpdateData(*appVersionEvent, whitelist); err != nil {
+		return nil, err
+	}
+	a.Store.mu.Lock()
+	defer a.Store.mu.Unlock()
+
+	touchRecord(&appVersionEvent.Record)
+	for i := range a.Store.appVersionEvents {
+		if !uuid.Equal(a.Store.appVersionEvents[i].ID, appVersionEvent.ID) {
+			continue
+		}
+		updated := a.Store.appVersionEvents[i]
+		if err := updateAttributes(&updated, appVersionEvent, whitelist); err != nil {
+			return nil, err
+		}
+		a.Store.appVersionEvents[i] = updated
+	}
+	return nil, nil
+}
@@ -0,0 +1,192 @@
+package memory
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/jinzhu/gorm"
+	uuid "github.com/satori/go.uuid"
+)
+
+// AppVersionService ...
+type AppVersionService struct {
+	Store *Store
+	models.UpdatableModelService
+}
+
+// Create ...
+func (a *AppVersionService) Create(appVersion *models.AppVersion) (*models.AppVersion, []error, error) {
+	if appVersion.AppStoreInfoData == nil {
+		appVersion.AppStoreInfoData = json.RawMessage(`{}`)
+	}
+	if appVersion.ArtifactInfoData == nil {
+		appVersion.ArtifactInfoData = json.RawMessage(`{}`)
+	}
+	if appVersion.IosReleaseOptionsData == nil {
+		appVersion.IosReleaseOptionsData = json.RawMessage(`{}`)
+	}
+	if appVersion.Status == "" {
+		appVersion.Status = models.AppVersionStatusDraft
+	}
+	verrs, err := appVersion.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(verrs) > 0 {
+		return nil, verrs, nil
+	}
+	if err := validateJSONColumns(*appVersion); err != nil {
+		return nil, nil, err
+	}
+
+	a.Store.mu.Lock()
+	defer a.Store.mu.Unlock()
+
+	assignForeignKeys(appVersion)
+	newRecord(&appVersion.Record)
+	a.Store.appVersions = append(a.Store.appVersions, detach(*appVersion).(models.AppVersion))
+	return appVersion, nil, nil
+}
+
+// Find ...
+func (a *AppVersionService) Find(appVersion *models.AppVersion) (*models.AppVersion, error) {
+	a.Store.mu.RLock()
+	defer a.Store.mu.RUnlock()
+
+	i := firstIndex(len(a.Store.appVersions),
+		func(i int) uuid.UUID { return a.Store.appVersions[i].ID },
+		func(i int) bool {
+			return uuid.Equal(appVersion.ID, uuid.UUID{}) || uuid.Equal(appVersion.ID, a.Store.appVersions[i].ID)
+		})
+	if i < 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	*appVersion = a.Store.appVersionWithApp(a.Store.appVersions[i].ID)
+	return appVersion, nil
+}
+
+// FindAll ...
+func (a *AppVersionService) FindAll(app *models.App, filterParams map[string]interface{}) ([]models.AppVersion, error) {
+	a.Store.mu.RLock()
+	defer a.Store.mu.RUnlock()
+
+	appVersions := []models.AppVersion{}
+	filterParams["app_id"] = app.ID
+	for _, appVersion := range a.Store.appVersions {
+		match, err := matchesColumns(filterParams, appVersion)
+		if err != nil {
+			return nil, err
+		}
+		if match {
+			appVersions = append(appVersions, detach(appVersion).(models.AppVersion))
+		}
+	}
+	return appVersions, nil
+}
+
+// Update ...
+func (a *AppVersionService) Update(appVersion *models.AppVersion, whitelist []string) (validationErrors []error, dbErr error) {
+	if _, err := a.UpdateData(*appVersion, whitelist); err != nil {
+		return nil, err
+	}
+	verrs, err := appVersion.Validate()
+	if err != nil {
+		return nil, err
+	}
+	if len(verrs) > 0 {
+		return verrs, nil
+	}
+
+	a.Store.mu.Lock()
+	defer a.Store.mu.Unlock()
+
+	touchRecord(&appVersion.Record)
+	for i := range a.Store.appVersions {
+		if !uuid.Equal(a.Store.appVersions[i].ID, appVersion.ID) {
+			continue
+		}
+		updated := a.Store.appVersions[i]
+		if err := updateAttributes(&updated, appVersion, whitelist); err != nil {
+			return nil, err
+		}
+		a.Store.appVersions[i] = updated
+	}
+	return nil, nil
+}
+
+// Latest ...
+func (a *AppVersionService) Latest(appVersion *models.AppVersion) (*models.AppVersion, error) {
+	a.Store.mu.RLock()
+	defer a.Store.mu.RUnlock()
+
+	condition := detach(*appVersion)
+	latest := -1
+	for i, storedAppVersion := range a.Store.appVersions {
+		if !matchesStruct(condition, storedAppVersion) {
+			continue
+		}
+		if latest == -1 || isLater(storedAppVersion.Record, a.Store.appVersions[latest].Record) {
+			latest = i
+		}
+	}
+	if latest < 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	*appVersion = a.Store.appVersionWithApp(a.Store.appVersions[latest].ID)
+	return appVersion, nil
+}
+
+// LatestPublished ...
+func (a *AppVersionService) LatestPublished(appVersion *models.AppVersion) (*models.AppVersion, error) {
+	a.Store.mu.RLock()
+	defer a.Store.mu.RUnlock()
+
+	publishedIDs := map[uuid.UUID]bool{}
+	for _, task := range a.Store.publishTasks {
+		if task.Status == models.PublishTaskStatusSuccess {
+			publishedIDs[task.AppVersionID] = true
+		}
+	}
+	publishedVersions := []models.AppVersion{}
+	for _, published := range a.Store.appVersions {
+		if !publishedIDs[published.ID] || uuid.Equal(published.ID, appVersion.ID) ||
+			!uuid.Equal(published.AppID, appVersion.AppID) || published.Platform != appVersion.Platform ||
+			published.ProductFlavor != appVersion.ProductFlavor || published.BundleID != appVersion.BundleID {
+			continue
+		}
+		publishedVersions = append(publishedVersions, published)
+	}
+	sort.Slice(publishedVersions, func(i, j int) bool {
+		return isLater(publishedVersions[i].Record, publishedVersions[j].Record)
+	})
+	latestPublished := models.HighestBuildCode(publishedVersions)
+	if latestPublished == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	withApp := a.Store.appVersionWithApp(latestPublished.ID)
+	return &withApp, nil
+}
+
+// Delete ...
+func (a *AppVersionService) Delete(appVersion *models.AppVersion) error {
+	a.Store.mu.Lock()
+	defer a.Store.mu.Unlock()
+
+	if !a.Store.deleteAppVersion(appVersion.ID) {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// isLater orders records by creation time descending, then by primary key,
+// like ORDER BY created_at DESC, id ASC.
+func isLater(record, other models.Record) bool {
+	if !record.CreatedAt.Equal(other.CreatedAt) {
+		return record.CreatedAt.After(other.CreatedAt)
+	}
+	return record.ID.String() < other.ID.String()
+}
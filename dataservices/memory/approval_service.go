@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"sort"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/jinzhu/gorm"
+	uuid "github.com/satori/go.uuid"
+)
+
+// ApprovalService ...
+type ApprovalService struct {
+	models.UpdatableModelService
+	Store *Store
+}
+
+// Create ...
+func (a *ApprovalService) Create(approval *models.Approval) (*models.Approval, error) {
+	a.Store.mu.Lock()
+	defer a.Store.mu.Unlock()
+
+	if approval.Status == "" {
+		approval.Status = models.ApprovalStatusApproved
+	}
+	assignForeignKeys(approval)
+	newRecord(&approval.Record)
+	a.Store.approvals = append(a.Store.approvals, detach(*approval).(models.Approval))
+	return approval, nil
+}
+
+// Find ...
+func (a *ApprovalService) Find(approval *models.Approval) (*models.Approval, error) {
+	a.Store.mu.RLock()
+	defer a.Store.mu.RUnlock()
+
+	condition := detach(*approval)
+	i := firstIndex(len(a.Store.approvals),
+		func(i int) uuid.UUID { return a.Store.approvals[i].ID },
+		func(i int) bool { return matchesStruct(condition, a.Store.approvals[i]) })
+	if i < 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	*approval = detach(a.Store.approvals[i]).(models.Approval)
+	return approval, nil
+}
+
+// FindAll ...
+func (a *ApprovalService) FindAll(appVersion *models.AppVersion) ([]models.Approval, error) {
+	a.Store.mu.RLock()
+	defer a.Store.mu.RUnlock()
+
+	approvals := []models.Approval{}
+	for _, approval := range a.Store.approvals {
+		if uuid.Equal(approval.AppVersionID, appVersion.ID) {
+			approvals = append(approvals, detach(approval).(models.Approval))
+		}
+	}
+	sort.SliceStable(approvals, func(i, j int) bool {
+		return approvals[i].CreatedAt.Before(approvals[j].CreatedAt)
+	})
+	return approvals, nil
+}
+
+// Update ...
+func (a *ApprovalService) Update(approval *models.Approval, whitelist []string) error {
+	if _, err := a.UpdateData(*approval, whitelist); err != nil {
+		return err
+	}
+	a.Store.mu.Lock()
+	defer a.Store.mu.Unlock()
+
+	touchRecord(&approval.Record)
+	for i := range a.Store.approvals {
+		if !uuid.Equal(a.Store.approvals[i].ID, approval.ID) {
+			continue
+		}
+		updated := a.Store.approvals[i]
+		if err := updateAttributes(&updated, approval, whitelist); err != nil {
+			return err
+		}
+		a.Store.approvals[i] = updated
+	}
+	return nil
+}
+
+// InvalidateAll ...
+func (a *ApprovalService) InvalidateAll(appVersion *models.AppVersion) (int, error) {
+	a.Store.mu.Lock()
+	defer a.Store.mu.Unlock()
+
+	invalidated := 0
+	for i := range a.Store.approvals {
+		approval := &a.Store.approvals[i]
+		if !uuid.Equal(approval.AppVersionID, appVersion.ID) || approval.Status != models.ApprovalStatusApproved {
+			continue
+		}
+		approval.Status = models.ApprovalStatusInvalidated
+		touchRecord(&approval.Record)
+		invalidated++
+	}
+	return invalidated, nil
+}
@@ -0,0 +1,32 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/addons-ship-backend/dataservices/contracttest"
+	"github.com/bitrise-io/addons-ship-backend/dataservices/memory"
+	"github.com/bitrise-io/go-utils/envutil"
+	"github.com/c2fo/testify/require"
+)
+
+func Test_Contract(t *testing.T) {
+	contracttest.Run(t, func(t *testing.T) (contracttest.Services, func()) {
+		revokeFn, err := envutil.RevokableSetenv("APP_WEBHOOK_SECRET_ENCRYPT_KEY", "06042e86a7bd421c642c8c3e4ab13840")
+		require.NoError(t, err)
+
+		store := memory.New()
+		return contracttest.Services{
+			AppService:              &memory.AppService{Store: store},
+			AppVersionService:       &memory.AppVersionService{Store: store},
+			AppSettingsService:      &memory.AppSettingsService{Store: store},
+			AppVersionEventService:  &memory.AppVersionEventService{Store: store},
+			PublishTaskService:      &memory.PublishTaskService{Store: store},
+			ScreenshotService:       &memory.ScreenshotService{Store: store},
+			FeatureGraphicService:   &memory.FeatureGraphicService{Store: store},
+			AppContactService:       &memory.AppContactService{Store: store},
+			ReleaseService:          &memory.ReleaseService{Store: store},
+			ScheduledPublishService: &memory.ScheduledPublishService{Store: store},
+			ApprovalService:         &memory.ApprovalService{Store: store},
+		}, func() { require.NoError(t, revokeFn()) }
+	})
+}
@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/jinzhu/gorm"
+	uuid "github.com/satori/go.uuid"
+)
+
+// FeatureGraphicService ...
+type FeatureGraphicService struct {
+	Store *Store
+	models.UpdatableModelService
+}
+
+// Create ...
+func (s *FeatureGraphicService) Create(featureGraphic *models.FeatureGraphic) (*models.FeatureGraphic, []error, error) {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	var featureGraphicCnt int64
+	for _, storedFeatureGraphic := range s.Store.featureGraphics {
+		if uuid.Equal(storedFeatureGraphic.AppVersionID, featureGraphic.AppVersionID) {
+			featureGraphicCnt++
+		}
+	}
+	verrs := append(featureGraphic.Validate(), featureGraphic.ValidateCount(featureGraphicCnt)...)
+	if len(verrs) > 0 {
+		return nil, verrs, nil
+	}
+
+	assignForeignKeys(featureGraphic)
+	newRecord(&featureGraphic.Record)
+	s.Store.featureGraphics = append(s.Store.featureGraphics, detach(*featureGraphic).(models.FeatureGraphic))
+	featureGraphic.AppVersion = s.Store.appVersionWithApp(featureGraphic.AppVersionID)
+	return featureGraphic, nil, nil
+}
+
+// Find ...
+func (s *FeatureGraphicService) Find(featureGraphic *models.FeatureGraphic) (*models.FeatureGraphic, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	condition := detach(*featureGraphic)
+	i := firstIndex(len(s.Store.featureGraphics),
+		func(i int) uuid.UUID { return s.Store.featureGraphics[i].ID },
+		func(i int) bool { return matchesStruct(condition, s.Store.featureGraphics[i]) })
+	if i < 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	*featureGraphic = detach(s.Store.featureGraphics[i]).(models.FeatureGraphic)
+	featureGraphic.AppVersion = s.Store.appVersionWithApp(featureGraphic.AppVersionID)
+	return featureGraphic, nil
+}
+
+// Update ...
+func (s *FeatureGraphicService) Update(featureGraphic models.FeatureGraphic, whitelist []string) ([]error, error) {
+	if _, err := s.UpdateData(featureGraphic, whitelist); err != nil {
+		return nil, err
+	}
+	if verrs := featureGraphic.Validate(); len(verrs) > 0 {
+		return verrs, nil
+	}
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	touchRecord(&featureGraphic.Record)
+	for i := range s.Store.featureGraphics {
+		if !uuid.Equal(s.Store.featureGraphics[i].ID, featureGraphic.ID) {
+			continue
+		}
+		updated := s.Store.featureGraphics[i]
+		if err := updateAttributes(&updated, featureGraphic, whitelist); err != nil {
+			return nil, err
+		}
+		s.Store.featureGraphics[i] = updated
+	}
+	return nil, nil
+}
+
+// Delete ...
+func (s *FeatureGraphicService) Delete(featureGraphic *models.FeatureGraphic) error {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	for i, storedFeatureGraphic := range s.Store.featureGraphics {
+		if uuid.Equal(storedFeatureGraphic.ID, featureGraphic.ID) {
+			s.Store.featureGraphics = append(s.Store.featureGraphics[:i], s.Store.featureGraphics[i+1:]...)
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
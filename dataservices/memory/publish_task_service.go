@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"sort"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/jinzhu/gorm"
+	uuid "github.com/satori/go.uuid"
+)
+
+// PublishTaskService ...
+type PublishTaskService struct {
+	models.UpdatableModelService
+	Store *Store
+}
+
+// Create ...
+func (t *PublishTaskService) Create(publishTask *models.PublishTask) (*models.PublishTask, error) {
+	t.Store.mu.Lock()
+	defer t.Store.mu.Unlock()
+
+	if publishTask.Status == "" {
+		publishTask.Status = models.PublishTaskStatusPending
+	}
+	if publishTask.Action == "" {
+		publishTask.Action = models.PublishTaskActionPublish
+	}
+	assignForeignKeys(publishTask)
+	newRecord(&publishTask.Record)
+	t.Store.publishTasks = append(t.Store.publishTasks, detach(*publishTask).(models.PublishTask))
+	return publishTask, nil
+}
+
+// Find ...
+func (t *PublishTaskService) Find(publishTask *models.PublishTask) (*models.PublishTask, error) {
+	t.Store.mu.RLock()
+	defer t.Store.mu.RUnlock()
+
+	condition := detach(*publishTask)
+	i := firstIndex(len(t.Store.publishTasks),
+		func(i int) uuid.UUID { return t.Store.publishTasks[i].ID },
+		func(i int) bool { return matchesStruct(condition, t.Store.publishTasks[i]) })
+	if i < 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	*publishTask = detach(t.Store.publishTasks[i]).(models.PublishTask)
+	publishTask.AppVersion = t.Store.appVersionByID(publishTask.AppVersionID)
+	return publishTask, nil
+}
+
+// FindAll ...
+func (t *PublishTaskService) FindAll(appVersion *models.AppVersion) ([]models.PublishTask, error) {
+	t.Store.mu.RLock()
+	defer t.Store.mu.RUnlock()
+
+	publishTasks := []models.PublishTask{}
+	for _, publishTask := range t.Store.publishTasks {
+		if uuid.Equal(publishTask.AppVersionID, appVersion.ID) {
+			publishTasks = append(publishTasks, detach(publishTask).(models.PublishTask))
+		}
+	}
+	sort.SliceStable(publishTasks, func(i, j int) bool {
+		return publishTasks[i].CreatedAt.Before(publishTasks[j].CreatedAt)
+	})
+	return publishTasks, nil
+}
+
+// FindAllForApp ...
+func (t *PublishTaskService) FindAllForApp(app *models.App) ([]models.PublishTask, error) {
+	t.Store.mu.RLock()
+	defer t.Store.mu.RUnlock()
+
+	publishTasks := []models.PublishTask{}
+	for _, publishTask := range t.Store.publishTasks {
+		appVersion := t.Store.appVersionByID(publishTask.AppVersionID)
+		if uuid.Equal(appVersion.AppID, app.ID) {
+			publishTask = detach(publishTask).(models.PublishTask)
+			publishTask.AppVersion = appVersion
+			publishTasks = append(publishTasks, publishTask)
+		}
+	}
+	sort.SliceStable(publishTasks, func(i, j int) bool {
+		return publishTasks[i].CreatedAt.Before(publishTasks[j].CreatedAt)
+	})
+	return publishTasks, nil
+}
+
+// Update ...
+func (t *PublishTaskService) Update(publishTask *models.PublishTask, whitelist []string) error {
+	if _, err := t.UpdateData(*publishTask, whitelist); err != nil {
+		return err
+	}
+	t.Store.mu.Lock()
+	defer t.Store.mu.Unlock()
+
+	touchRecord(&publishTask.Record)
+	for i := range t.Store.publishTasks {
+		if !uuid.Equal(t.Store.publishTasks[i].ID, publishTask.ID) {
+			continue
+		}
+		updated := t.Store.publishTasks[i]
+		if err := updateAttributes(&updated, publishTask, whitelist); err != nil {
+			return err
+		}
+		t.Store.publishTasks[i] = updated
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// column is a struct field which is stored in a database column, as opposed
+// to associations, which are loaded by preloading.
+type column struct {
+	name  string
+	json  bool
+	value reflect.Value
+}
+
+func columnName(field reflect.StructField) string {
+	if name := tagSetting(field, "column"); name != "" {
+		return name
+	}
+	return gorm.ToColumnName(field.Name)
+}
+
+func isAssociation(fieldType reflect.Type) bool {
+	switch fieldType.Kind() {
+	case reflect.Struct:
+		return fieldType != timeType
+	case reflect.Ptr, reflect.Slice:
+		elemType := fieldType.Elem()
+		if elemType.Kind() == reflect.Ptr {
+			elemType = elemType.Elem()
+		}
+		return elemType.Kind() == reflect.Struct && elemType != timeType
+	}
+	return false
+}
+
+// columns lists the columns of a model struct, flattening embedded structs
+// the same way gorm does.
+func columns(value reflect.Value) []column {
+	cols := []column{}
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.PkgPath != "" || field.Tag.Get("gorm") == "-" {
+			continue
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			cols = append(cols, columns(value.Field(i))...)
+			continue
+		}
+		if isAssociation(field.Type) {
+			continue
+		}
+		cols = append(cols, column{
+			name:  columnName(field),
+			json:  strings.Contains(field.Tag.Get("gorm"), "type:json"),
+			value: value.Field(i),
+		})
+	}
+	return cols
+}
+
+// detach returns a copy of a model struct without its associations and
+// without sharing the backing arrays of its slice fields.
+func detach(model interface{}) interface{} {
+	value := reflect.New(reflect.TypeOf(model)).Elem()
+	value.Set(reflect.ValueOf(model))
+	detachValue(value)
+	return value.Interface()
+}
+
+func detachValue(value reflect.Value) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		fieldValue := value.Field(i)
+		switch {
+		case field.Anonymous && field.Type.Kind() == reflect.Struct:
+			detachValue(fieldValue)
+		case isAssociation(field.Type):
+			fieldValue.Set(reflect.Zero(field.Type))
+		case field.Type.Kind() == reflect.Slice && !fieldValue.IsNil():
+			copied := reflect.MakeSlice(field.Type, fieldValue.Len(), fieldValue.Len())
+			reflect.Copy(copied, fieldValue)
+			fieldValue.Set(copied)
+		}
+	}
+}
+
+func tagSetting(field reflect.StructField, name string) string {
+	for _, setting := range strings.Split(field.Tag.Get("gorm"), ";") {
+		if strings.HasPrefix(strings.ToLower(setting), name+":") {
+			return setting[len(name)+1:]
+		}
+	}
+	return ""
+}
+
+// assignForeignKeys sets the blank foreign keys of a model from its belongs to
+// associations, the way gorm does when a record is saved with them.
+func assignForeignKeys(model interface{}) {
+	value := reflect.ValueOf(model).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if !isAssociation(field.Type) || field.Type.Kind() == reflect.Slice {
+			continue
+		}
+		foreignKey := value.FieldByName(tagSetting(field, "foreignkey"))
+		association := reflect.Indirect(value.Field(i))
+		if !foreignKey.IsValid() || !isBlank(foreignKey) || !association.IsValid() {
+			continue
+		}
+		if id := association.FieldByName("ID"); id.IsValid() && !isBlank(id) {
+			foreignKey.Set(id)
+		}
+	}
+}
+
+func isBlank(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		return value.Len() == 0
+	case reflect.Interface, reflect.Ptr:
+		return value.IsNil()
+	}
+	return reflect.DeepEqual(value.Interface(), reflect.Zero(value.Type()).Interface())
+}
+
+func valuesEqual(a, b interface{}) bool {
+	if aTime, ok := a.(time.Time); ok {
+		bTime, ok := b.(time.Time)
+		return ok && aTime.Equal(bTime)
+	}
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	aValue, bValue := reflect.ValueOf(a), reflect.ValueOf(b)
+	if aValue.Kind() == reflect.Ptr && !aValue.IsNil() {
+		a = aValue.Elem().Interface()
+	}
+	if bValue.Kind() == reflect.Ptr && !bValue.IsNil() {
+		b = bValue.Elem().Interface()
+	}
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+// matchesStruct reports whether a record matches the non-blank columns of the
+// condition, like a gorm Where called with a struct.
+func matchesStruct(condition, record interface{}) bool {
+	recordColumns := map[string]reflect.Value{}
+	for _, col := range columns(reflect.ValueOf(record)) {
+		recordColumns[col.name] = col.value
+	}
+	for _, col := range columns(reflect.ValueOf(condition)) {
+		if isBlank(col.value) {
+			continue
+		}
+		if !valuesEqual(col.value.Interface(), recordColumns[col.name].Interface()) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesColumns reports whether a record matches all the column values of the
+// condition, like a gorm Where called with a map.
+func matchesColumns(condition map[string]interface{}, record interface{}) (bool, error) {
+	recordColumns := map[string]reflect.Value{}
+	for _, col := range columns(reflect.ValueOf(record)) {
+		recordColumns[col.name] = col.value
+	}
+	for name, value := range condition {
+		recordValue, ok := recordColumns[name]
+		if !ok {
+			return false, errors.Errorf("column \"%s\" does not exist", name)
+		}
+		if !valuesEqual(recordValue.Interface(), value) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// updateAttributes copies the whitelisted attributes and the update timestamp
+// of a model to the stored record, then checks the JSON columns of the result.
+func updateAttributes(record interface{}, model interface{}, whitelist []string) error {
+	recordValue := reflect.ValueOf(record).Elem()
+	modelValue := reflect.Indirect(reflect.ValueOf(model))
+	for _, attribute := range append(append([]string{}, whitelist...), "UpdatedAt") {
+		recordValue.FieldByName(attribute).Set(modelValue.FieldByName(attribute))
+	}
+	detachValue(recordValue)
+	return validateJSONColumns(recordValue.Interface())
+}
+
+func validateJSONColumns(record interface{}) error {
+	for _, col := range columns(reflect.ValueOf(record)) {
+		if !col.json || col.value.Len() == 0 {
+			continue
+		}
+		if !json.Valid(col.value.Bytes()) {
+			return errors.New("invalid input syntax for type json")
+		}
+	}
+	return nil
+}
+
+func validationError(verrs []error) error {
+	errs := gorm.Errors{}
+	for _, verr := range verrs {
+		errs = errs.Add(models.NewValidationError(verr.Error()))
+	}
+	return errs.Add(errors.New("Validation failed"))
+}
@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"sort"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/jinzhu/gorm"
+	uuid "github.com/satori/go.uuid"
+)
+
+// ReleaseService ...
+type ReleaseService struct {
+	Store *Store
+	models.UpdatableModelService
+}
+
+// Create ...
+func (r *ReleaseService) Create(release *models.Release) (*models.Release, error) {
+	r.Store.mu.Lock()
+	defer r.Store.mu.Unlock()
+
+	assignForeignKeys(release)
+	newRecord(&release.Record)
+	r.Store.releases = append(r.Store.releases, detach(*release).(models.Release))
+	*release = r.Store.releaseWithAssociations(*release)
+	return release, nil
+}
+
+// Find ...
+func (r *ReleaseService) Find(release *models.Release) (*models.Release, error) {
+	r.Store.mu.RLock()
+	defer r.Store.mu.RUnlock()
+
+	condition := detach(*release)
+	i := firstIndex(len(r.Store.releases),
+		func(i int) uuid.UUID { return r.Store.releases[i].ID },
+		func(i int) bool { return matchesStruct(condition, r.Store.releases[i]) })
+	if i < 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	*release = r.Store.releaseWithAssociations(r.Store.releases[i])
+	return release, nil
+}
+
+// FindAll ...
+func (r *ReleaseService) FindAll(app *models.App) ([]models.Release, error) {
+	r.Store.mu.RLock()
+	defer r.Store.mu.RUnlock()
+
+	releases := []models.Release{}
+	for _, release := range r.Store.releases {
+		if uuid.Equal(release.AppID, app.ID) {
+			releases = append(releases, r.Store.releaseWithAssociations(release))
+		}
+	}
+	sort.Slice(releases, func(i, j int) bool {
+		return isLater(releases[i].Record, releases[j].Record)
+	})
+	return releases, nil
+}
+
+// Update ...
+func (r *ReleaseService) Update(release *models.Release, whitelist []string) (validationErrors []error, dbErr error) {
+	if _, err := r.UpdateData(*release, whitelist); err != nil {
+		return nil, err
+	}
+	r.Store.mu.Lock()
+	defer r.Store.mu.Unlock()
+
+	touchRecord(&release.Record)
+	for i := range r.Store.releases {
+		if !uuid.Equal(r.Store.releases[i].ID, release.ID) {
+			continue
+		}
+		updated := r.Store.releases[i]
+		if err := updateAttributes(&updated, release, whitelist); err != nil {
+			return nil, err
+		}
+		r.Store.releases[i] = updated
+	}
+	return nil, nil
+}
@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/jinzhu/gorm"
+	uuid "github.com/satori/go.uuid"
+)
+
+// ScheduledPublishService ...
+type ScheduledPublishService struct {
+	models.UpdatableModelService
+	Store *Store
+}
+
+// Create ...
+func (p *ScheduledPublishService) Create(scheduledPublish *models.ScheduledPublish) (*models.ScheduledPublish, error) {
+	p.Store.mu.Lock()
+	defer p.Store.mu.Unlock()
+
+	if scheduledPublish.Status == "" {
+		scheduledPublish.Status = models.ScheduledPublishStatusScheduled
+	}
+	if scheduledPublish.DestinationsData == nil {
+		scheduledPublish.DestinationsData = json.RawMessage(`[]`)
+	}
+	assignForeignKeys(scheduledPublish)
+	newRecord(&scheduledPublish.Record)
+	if err := validateJSONColumns(*scheduledPublish); err != nil {
+		return nil, err
+	}
+	p.Store.scheduledPublishes = append(p.Store.scheduledPublishes, detach(*scheduledPublish).(models.ScheduledPublish))
+	return scheduledPublish, nil
+}
+
+// Find ...
+func (p *ScheduledPublishService) Find(scheduledPublish *models.ScheduledPublish) (*models.ScheduledPublish, error) {
+	p.Store.mu.RLock()
+	defer p.Store.mu.RUnlock()
+
+	condition := detach(*scheduledPublish)
+	i := firstIndex(len(p.Store.scheduledPublishes),
+		func(i int) uuid.UUID { return p.Store.scheduledPublishes[i].ID },
+		func(i int) bool { return matchesStruct(condition, p.Store.scheduledPublishes[i]) })
+	if i < 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	*scheduledPublish = detach(p.Store.scheduledPublishes[i]).(models.ScheduledPublish)
+	scheduledPublish.AppVersion = p.Store.appVersionWithApp(scheduledPublish.AppVersionID)
+	return scheduledPublish, nil
+}
+
+// FindAll ...
+func (p *ScheduledPublishService) FindAll(appVersion *models.AppVersion) ([]models.ScheduledPublish, error) {
+	p.Store.mu.RLock()
+	defer p.Store.mu.RUnlock()
+
+	scheduledPublishes := []models.ScheduledPublish{}
+	for _, scheduledPublish := range p.Store.scheduledPublishes {
+		if uuid.Equal(scheduledPublish.AppVersionID, appVersion.ID) {
+			scheduledPublishes = append(scheduledPublishes, detach(scheduledPublish).(models.ScheduledPublish))
+		}
+	}
+	sort.SliceStable(scheduledPublishes, func(i, j int) bool {
+		return scheduledPublishes[i].ScheduledAt.Before(scheduledPublishes[j].ScheduledAt)
+	})
+	return scheduledPublishes, nil
+}
+
+// FindAllDue ...
+func (p *ScheduledPublishService) FindAllDue(now time.Time) ([]models.ScheduledPublish, error) {
+	p.Store.mu.RLock()
+	defer p.Store.mu.RUnlock()
+
+	scheduledPublishes := []models.ScheduledPublish{}
+	for _, scheduledPublish := range p.Store.scheduledPublishes {
+		if scheduledPublish.Status != models.ScheduledPublishStatusScheduled || scheduledPublish.ScheduledAt.After(now) {
+			continue
+		}
+		scheduledPublish = detach(scheduledPublish).(models.ScheduledPublish)
+		scheduledPublish.AppVersion = p.Store.appVersionWithApp(scheduledPublish.AppVersionID)
+		if scheduledPublish.AppVersion.App.DeprovisionedAt != nil {
+			continue
+		}
+		scheduledPublishes = append(scheduledPublishes, scheduledPublish)
+	}
+	sort.SliceStable(scheduledPublishes, func(i, j int) bool {
+		return scheduledPublishes[i].ScheduledAt.Before(scheduledPublishes[j].ScheduledAt)
+	})
+	return scheduledPublishes, nil
+}
+
+// Update ...
+func (p *ScheduledPublishService) Update(scheduledPublish *models.ScheduledPublish, whitelist []string) error {
+	if _, err := p.UpdateData(*scheduledPublish, whitelist); err != nil {
+		return err
+	}
+	p.Store.mu.Lock()
+	defer p.Store.mu.Unlock()
+
+	touchRecord(&scheduledPublish.Record)
+	for i := range p.Store.scheduledPublishes {
+		if !uuid.Equal(p.Store.scheduledPublishes[i].ID, scheduledPublish.ID) {
+			continue
+		}
+		updated := p.Store.scheduledPublishes[i]
+		if err := updateAttributes(&updated, scheduledPublish, whitelist); err != nil {
+			return err
+		}
+		p.Store.scheduledPublishes[i] = updated
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/jinzhu/gorm"
+	uuid "github.com/satori/go.uuid"
+)
+
+// ScreenshotService ...
+type ScreenshotService struct {
+	Store *Store
+	models.UpdatableModelService
+}
+
+// BatchCreate ...
+func (s *ScreenshotService) BatchCreate(screenshots []*models.Screenshot) ([]*models.Screenshot, []error, error) {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	created := []models.Screenshot{}
+	for _, screenshot := range screenshots {
+		assignForeignKeys(screenshot)
+		newRecord(&screenshot.Record)
+		if verrs := screenshot.Validate(); len(verrs) > 0 {
+			return nil, verrs, nil
+		}
+		created = append(created, detach(*screenshot).(models.Screenshot))
+	}
+	s.Store.screenshots = append(s.Store.screenshots, created...)
+
+	for _, screenshot := range screenshots {
+		screenshot.AppVersion = s.Store.appVersionWithApp(screenshot.AppVersionID)
+	}
+	return screenshots, nil, nil
+}
+
+// Find ...
+func (s *ScreenshotService) Find(screenshot *models.Screenshot) (*models.Screenshot, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	condition := detach(*screenshot)
+	i := firstIndex(len(s.Store.screenshots),
+		func(i int) uuid.UUID { return s.Store.screenshots[i].ID },
+		func(i int) bool { return matchesStruct(condition, s.Store.screenshots[i]) })
+	if i < 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	*screenshot = detach(s.Store.screenshots[i]).(models.Screenshot)
+	return screenshot, nil
+}
+
+// FindAll ...
+func (s *ScreenshotService) FindAll(appVersion *models.AppVersion) ([]models.Screenshot, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	screenshots := []models.Screenshot{}
+	for _, storedScreenshot := range s.Store.screenshots {
+		if !uuid.Equal(storedScreenshot.AppVersionID, appVersion.ID) {
+			continue
+		}
+		screenshot := detach(storedScreenshot).(models.Screenshot)
+		screenshot.AppVersion = s.Store.appVersionWithApp(screenshot.AppVersionID)
+		screenshots = append(screenshots, screenshot)
+	}
+	return screenshots, nil
+}
+
+// BatchUpdate ...
+func (s *ScreenshotService) BatchUpdate(screenshots []models.Screenshot, whitelist []string) ([]error, error) {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	for _, screenshot := range screenshots {
+		if _, err := s.UpdateData(screenshot, whitelist); err != nil {
+			return nil, err
+		}
+		if verrs := screenshot.Validate(); len(verrs) > 0 {
+			return verrs, nil
+		}
+		touchRecord(&screenshot.Record)
+		for i := range s.Store.screenshots {
+			if !uuid.Equal(s.Store.screenshots[i].ID, screenshot.ID) {
+				continue
+			}
+			updated := s.Store.screenshots[i]
+			if err := updateAttributes(&updated, screenshot, whitelist); err != nil {
+				return nil, err
+			}
+			s.Store.screenshots[i] = updated
+		}
+	}
+	return nil, nil
+}
+
+// Delete ...
+func (s *ScreenshotService) Delete(screenshot *models.Screenshot) error {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	for i, storedScreenshot := range s.Store.screenshots {
+		if uuid.Equal(storedScreenshot.ID, screenshot.ID) {
+			s.Store.screenshots = append(s.Store.screenshots[:i], s.Store.screenshots[i+1:]...)
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
@@ -0,0 +1,233 @@
+// Package memory implements the dataservices interfaces on top of an in-memory
+// store, following the behaviour of the gorm based services of the models
+// package, validation errors included.
+//
+// Database level constraints (foreign keys, unique indexes) are not enforced,
+// the same way as in the test database, but deleting an app or an app version
+// cascades to its dependent records like in the production schema.
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/jinzhu/gorm"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Store ...
+type Store struct {
+	mu sync.RWMutex
+
+	apps               []models.App
+	appVersions        []models.AppVersion
+	appSettings        []models.AppSettings
+	appContacts        []models.AppContact
+	appVersionEvents   []models.AppVersionEvent
+	publishTasks       []models.PublishTask
+	screenshots        []models.Screenshot
+	featureGraphics    []models.FeatureGraphic
+	releases           []models.Release
+	scheduledPublishes []models.ScheduledPublish
+	approvals          []models.Approval
+}
+
+// New ...
+func New() *Store {
+	return &Store{}
+}
+
+func newRecord(record *models.Record) {
+	if uuid.Equal(record.ID, uuid.UUID{}) {
+		record.ID = uuid.NewV4()
+	}
+	now := gorm.NowFunc()
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+}
+
+func touchRecord(record *models.Record) {
+	record.UpdatedAt = gorm.NowFunc()
+}
+
+// firstIndex returns the index of the record with the lowest ID amongst the
+// matching ones, the same way as gorm's First orders by primary key.
+func firstIndex(count int, id func(i int) uuid.UUID, match func(i int) bool) int {
+	found := -1
+	for i := 0; i < count; i++ {
+		if !match(i) {
+			continue
+		}
+		if found == -1 || id(i).String() < id(found).String() {
+			found = i
+		}
+	}
+	return found
+}
+
+func (s *Store) appByID(id uuid.UUID) models.App {
+	for _, app := range s.apps {
+		if uuid.Equal(app.ID, id) {
+			return detach(app).(models.App)
+		}
+	}
+	return models.App{}
+}
+
+func (s *Store) appVersionByID(id uuid.UUID) models.AppVersion {
+	for _, appVersion := range s.appVersions {
+		if uuid.Equal(appVersion.ID, id) {
+			return detach(appVersion).(models.AppVersion)
+		}
+	}
+	return models.AppVersion{}
+}
+
+func (s *Store) appVersionWithApp(id uuid.UUID) models.AppVersion {
+	appVersion := s.appVersionByID(id)
+	appVersion.App = s.appByID(appVersion.AppID)
+	return appVersion
+}
+
+// appVersionsOfApp returns the versions of an app, latest first.
+func (s *Store) appVersionsOfApp(appID uuid.UUID, limit int) []models.AppVersion {
+	appVersions := []models.AppVersion{}
+	for _, appVersion := range s.appVersions {
+		if uuid.Equal(appVersion.AppID, appID) {
+			appVersions = append(appVersions, detach(appVersion).(models.AppVersion))
+		}
+	}
+	sort.Slice(appVersions, func(i, j int) bool {
+		return isLater(appVersions[i].Record, appVersions[j].Record)
+	})
+	if len(appVersions) > limit {
+		appVersions = appVersions[:limit]
+	}
+	return appVersions
+}
+
+// releaseWithAssociations returns a copy of a release with its app and its
+// versions, latest first.
+func (s *Store) releaseWithAssociations(release models.Release) models.Release {
+	release = detach(release).(models.Release)
+	release.App = s.appByID(release.AppID)
+	release.AppVersions = []models.AppVersion{}
+	for _, appVersion := range s.appVersions {
+		if appVersion.ReleaseID != nil && uuid.Equal(*appVersion.ReleaseID, release.ID) {
+			release.AppVersions = append(release.AppVersions, detach(appVersion).(models.AppVersion))
+		}
+	}
+	sort.Slice(release.AppVersions, func(i, j int) bool {
+		return isLater(release.AppVersions[i].Record, release.AppVersions[j].Record)
+	})
+	return release
+}
+
+func (s *Store) deleteApp(id uuid.UUID) bool {
+	deleted := false
+	apps := s.apps[:0]
+	for _, app := range s.apps {
+		if uuid.Equal(app.ID, id) {
+			deleted = true
+			continue
+		}
+		apps = append(apps, app)
+	}
+	s.apps = apps
+	if !deleted {
+		return false
+	}
+
+	for _, appVersion := range append([]models.AppVersion{}, s.appVersions...) {
+		if uuid.Equal(appVersion.AppID, id) {
+			s.deleteAppVersion(appVersion.ID)
+		}
+	}
+	appSettings := s.appSettings[:0]
+	for _, settings := range s.appSettings {
+		if !uuid.Equal(settings.AppID, id) {
+			appSettings = append(appSettings, settings)
+		}
+	}
+	s.appSettings = appSettings
+	appContacts := s.appContacts[:0]
+	for _, contact := range s.appContacts {
+		if !uuid.Equal(contact.AppID, id) {
+			appContacts = append(appContacts, contact)
+		}
+	}
+	s.appContacts = appContacts
+	releases := s.releases[:0]
+	for _, release := range s.releases {
+		if !uuid.Equal(release.AppID, id) {
+			releases = append(releases, release)
+		}
+	}
+	s.releases = releases
+	return true
+}
+
+func (s *Store) deleteAppVersion(id uuid.UUID) bool {
+	deleted := false
+	appVersions := s.appVersions[:0]
+	for _, appVersion := range s.appVersions {
+		if uuid.Equal(appVersion.ID, id) {
+			deleted = true
+			continue
+		}
+		appVersions = append(appVersions, appVersion)
+	}
+	s.appVersions = appVersions
+	if !deleted {
+		return false
+	}
+
+	appVersionEvents := s.appVersionEvents[:0]
+	for _, event := range s.appVersionEvents {
+		if !uuid.Equal(event.AppVersionID, id) {
+			appVersionEvents = append(appVersionEvents, event)
+		}
+	}
+	s.appVersionEvents = appVersionEvents
+	publishTasks := s.publishTasks[:0]
+	for _, task := range s.publishTasks {
+		if !uuid.Equal(task.AppVersionID, id) {
+			publishTasks = append(publishTasks, task)
+		}
+	}
+	s.publishTasks = publishTasks
+	scheduledPublishes := s.scheduledPublishes[:0]
+	for _, scheduledPublish := range s.scheduledPublishes {
+		if !uuid.Equal(scheduledPublish.AppVersionID, id) {
+			scheduledPublishes = append(scheduledPublishes, scheduledPublish)
+		}
+	}
+	s.scheduledPublishes = scheduledPublishes
+	approvals := s.approvals[:0]
+	for _, approval := range s.approvals {
+		if !uuid.Equal(approval.AppVersionID, id) {
+			approvals = append(approvals, approval)
+		}
+	}
+	s.approvals = approvals
+	screenshots := s.screenshots[:0]
+	for _, screenshot := range s.screenshots {
+		if !uuid.Equal(screenshot.AppVersionID, id) {
+			screenshots = append(screenshots, screenshot)
+		}
+	}
+	s.screenshots = screenshots
+	featureGraphics := s.featureGraphics[:0]
+	for _, featureGraphic := range s.featureGraphics {
+		if !uuid.Equal(featureGraphic.AppVersionID, id) {
+			featureGraphics = append(featureGraphics, featureGraphic)
+		}
+	}
+	s.featureGraphics = featureGraphics
+	return true
+}
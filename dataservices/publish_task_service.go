@@ -8,4 +8,7 @@ import (
 type PublishTaskService interface {
 	Create(publishTask *models.PublishTask) (*models.PublishTask, error)
 	Find(publishTask *models.PublishTask) (*models.PublishTask, error)
+	FindAll(appVersion *models.AppVersion) ([]models.PublishTask, error)
+	FindAllForApp(app *models.App) ([]models.PublishTask, error)
+	Update(publishTask *models.PublishTask, whitelist []string) error
 }
@@ -0,0 +1,11 @@
+package dataservices
+
+import "github.com/bitrise-io/addons-ship-backend/models"
+
+// ReleaseService ...
+type ReleaseService interface {
+	Create(release *models.Release) (*models.Release, error)
+	Find(release *models.Release) (*models.Release, error)
+	FindAll(app *models.App) ([]models.Release, error)
+	Update(release *models.Release, whitelist []string) (validationErrors []error, dbErr error)
+}
@@ -0,0 +1,16 @@
+package dataservices
+
+import (
+	"time"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+)
+
+// ScheduledPublishService ...
+type ScheduledPublishService interface {
+	Create(scheduledPublish *models.ScheduledPublish) (*models.ScheduledPublish, error)
+	Find(scheduledPublish *models.ScheduledPublish) (*models.ScheduledPublish, error)
+	FindAll(appVersion *models.AppVersion) ([]models.ScheduledPublish, error)
+	FindAllDue(now time.Time) ([]models.ScheduledPublish, error)
+	Update(scheduledPublish *models.ScheduledPublish, whitelist []string) error
+}
@@ -10,4 +10,6 @@ type WorkerService interface {
 	EnqueueStoreLogToAWS(appVersionEventID, publishTaskExternalID uuid.UUID, numberOfLogChunks int64, awsPath string, secondsFromNow int64) error
 	EnqueueStoreLogChunkToRedis(publishTaskExternalID string, logChunk models.LogChunk, secondsFromNow int64) error
 	EnqueueCopyUploadablesToNewAppVersion(appVersionFromCopyID, appVersionToCopyID string) error
+	EnqueueAutoPublishAppVersion(appVersionID uuid.UUID, workflow, branch, copyUploadablesFromID string) error
+	EnqueueDeleteAppVersion(appVersionID uuid.UUID) error
 }
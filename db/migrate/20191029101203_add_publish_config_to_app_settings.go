@@ -0,0 +1,25 @@
+package migration
+
+import (
+	"database/sql"
+
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(up20191029101203, down20191029101203)
+}
+
+func up20191029101203(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE app_settings
+		ADD COLUMN publish_bitrise_yml text NOT NULL DEFAULT '',
+		ADD COLUMN publish_stack_id text NOT NULL DEFAULT '';`)
+	return err
+}
+
+func down20191029101203(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE app_settings
+		DROP COLUMN publish_bitrise_yml,
+		DROP COLUMN publish_stack_id;`)
+	return err
+}
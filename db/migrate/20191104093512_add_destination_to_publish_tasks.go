@@ -0,0 +1,48 @@
+package migration
+
+import (
+	"database/sql"
+
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(up20191104093512, down20191104093512)
+}
+
+func up20191104093512(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE publish_tasks
+		ADD COLUMN destination text NOT NULL DEFAULT '',
+		ADD COLUMN track text NOT NULL DEFAULT '',
+		ADD COLUMN status text NOT NULL DEFAULT '',
+		ADD COLUMN batch_id uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000';`)
+	if err != nil {
+		return err
+	}
+	// the existing tasks get the result of the first publish of their version
+	// which finished after they were created, the ones which never finished are
+	// taken as failed
+	_, err = tx.Exec(`UPDATE publish_tasks SET status = COALESCE((
+		SELECT CASE app_version_events.event_text
+			WHEN 'Successfully published' THEN 'success'
+			ELSE 'failed'
+		END
+		FROM app_version_events
+		WHERE app_version_events.app_version_id = publish_tasks.app_version_id
+			AND app_version_events.event_text IN ('Successfully published', 'Failed to publish')
+			AND app_version_events.created_at >= publish_tasks.created_at
+		ORDER BY app_version_events.created_at ASC
+		LIMIT 1
+	), 'failed')
+	WHERE status = '';`)
+	return err
+}
+
+func down20191104093512(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE publish_tasks
+		DROP COLUMN destination,
+		DROP COLUMN track,
+		DROP COLUMN status,
+		DROP COLUMN batch_id;`)
+	return err
+}
@@ -0,0 +1,25 @@
+package migration
+
+import (
+	"database/sql"
+
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(up20191106142208, down20191106142208)
+}
+
+func up20191106142208(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE publish_tasks
+		ADD COLUMN release_status text NOT NULL DEFAULT '',
+		ADD COLUMN user_fraction double precision NOT NULL DEFAULT 0;`)
+	return err
+}
+
+func down20191106142208(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE publish_tasks
+		DROP COLUMN release_status,
+		DROP COLUMN user_fraction;`)
+	return err
+}
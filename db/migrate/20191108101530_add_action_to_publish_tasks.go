@@ -0,0 +1,25 @@
+package migration
+
+import (
+	"database/sql"
+
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(up20191108101530, down20191108101530)
+}
+
+func up20191108101530(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE publish_tasks
+		ADD COLUMN action text NOT NULL DEFAULT '',
+		ADD COLUMN from_track text NOT NULL DEFAULT '';`)
+	return err
+}
+
+func down20191108101530(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE publish_tasks
+		DROP COLUMN action,
+		DROP COLUMN from_track;`)
+	return err
+}
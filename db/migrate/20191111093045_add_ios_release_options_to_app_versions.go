@@ -0,0 +1,21 @@
+package migration
+
+import (
+	"database/sql"
+
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(up20191111093045, down20191111093045)
+}
+
+func up20191111093045(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE app_versions ADD COLUMN ios_release_options json NOT NULL DEFAULT '{}'::json;`)
+	return err
+}
+
+func down20191111093045(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE app_versions DROP COLUMN ios_release_options;`)
+	return err
+}
@@ -0,0 +1,25 @@
+package migration
+
+import (
+	"database/sql"
+
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(up20191112140312, down20191112140312)
+}
+
+func up20191112140312(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE app_settings
+		ADD COLUMN encrypted_ios_api_key bytea,
+		ADD COLUMN encrypted_ios_api_key_iv bytea;`)
+	return err
+}
+
+func down20191112140312(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE app_settings
+		DROP COLUMN encrypted_ios_api_key,
+		DROP COLUMN encrypted_ios_api_key_iv;`)
+	return err
+}
@@ -0,0 +1,25 @@
+package migration
+
+import (
+	"database/sql"
+
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(up20191114083021, down20191114083021)
+}
+
+func up20191114083021(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE app_settings
+		ADD COLUMN code_signing_expiry json NOT NULL DEFAULT '{}'::json,
+		ADD COLUMN code_signing_expires_at timestamp with time zone;`)
+	return err
+}
+
+func down20191114083021(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE app_settings
+		DROP COLUMN code_signing_expiry,
+		DROP COLUMN code_signing_expires_at;`)
+	return err
+}
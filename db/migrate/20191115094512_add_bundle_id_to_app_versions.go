@@ -0,0 +1,25 @@
+package migration
+
+import (
+	"database/sql"
+
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(up20191115094512, down20191115094512)
+}
+
+func up20191115094512(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE app_versions ADD COLUMN bundle_id text NOT NULL DEFAULT '';`)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`UPDATE app_versions SET bundle_id = COALESCE(artifact_info->>'bundle_id', '') WHERE platform = 'ios';`)
+	return err
+}
+
+func down20191115094512(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE app_versions DROP COLUMN bundle_id;`)
+	return err
+}
@@ -0,0 +1,37 @@
+package migration
+
+import (
+	"database/sql"
+
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(up20191118093027, down20191118093027)
+}
+
+func up20191118093027(tx *sql.Tx) error {
+	_, err := tx.Exec(`CREATE TABLE releases (
+		id uuid primary key NOT NULL,
+		app_id uuid NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+		build_slug text NOT NULL DEFAULT '',
+		version text NOT NULL DEFAULT '',
+		notes text NOT NULL DEFAULT '',
+		created_at timestamp with time zone NOT NULL,
+		updated_at timestamp with time zone NOT NULL
+	);`)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`ALTER TABLE app_versions ADD COLUMN release_id uuid REFERENCES releases (id) ON DELETE SET NULL;`)
+	return err
+}
+
+func down20191118093027(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE app_versions DROP COLUMN release_id;`)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`DROP TABLE releases;`)
+	return err
+}
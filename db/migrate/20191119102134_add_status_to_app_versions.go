@@ -0,0 +1,60 @@
+package migration
+
+import (
+	"database/sql"
+
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(up20191119102134, down20191119102134)
+}
+
+func up20191119102134(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE app_versions ADD COLUMN status text NOT NULL DEFAULT 'draft';
+	CREATE INDEX app_versions_status_idx ON app_versions(status);`)
+	if err != nil {
+		return err
+	}
+	// the existing versions get the result of their latest finished publish,
+	// which is recorded by the events of the version. A publish which is still
+	// running sets the status when it finishes.
+	_, err = tx.Exec(`UPDATE app_versions SET status = (
+		SELECT CASE app_version_events.event_text
+			WHEN 'Successfully published' THEN 'published'
+			ELSE 'failed'
+		END
+		FROM app_version_events
+		WHERE app_version_events.app_version_id = app_versions.id
+			AND app_version_events.event_text IN ('Successfully published', 'Failed to publish')
+		ORDER BY app_version_events.created_at DESC
+		LIMIT 1
+	)
+	WHERE EXISTS (
+		SELECT 1 FROM app_version_events
+		WHERE app_version_events.app_version_id = app_versions.id
+			AND app_version_events.event_text IN ('Successfully published', 'Failed to publish')
+	);`)
+	if err != nil {
+		return err
+	}
+	// a version which was never published is ready when it's the latest one of
+	// its platform, flavor and bundle ID, and superseded otherwise
+	_, err = tx.Exec(`UPDATE app_versions SET status = CASE
+		WHEN app_versions.created_at = (
+			SELECT MAX(latest.created_at) FROM app_versions latest
+			WHERE latest.app_id = app_versions.app_id
+				AND latest.platform = app_versions.platform
+				AND COALESCE(latest.product_flavor, '') = COALESCE(app_versions.product_flavor, '')
+				AND latest.bundle_id = app_versions.bundle_id
+		) THEN 'ready'
+		ELSE 'superseded'
+	END
+	WHERE status = 'draft';`)
+	return err
+}
+
+func down20191119102134(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE app_versions DROP COLUMN status;`)
+	return err
+}
@@ -0,0 +1,21 @@
+package migration
+
+import (
+	"database/sql"
+
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(up20191120083512, down20191120083512)
+}
+
+func up20191120083512(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE app_versions ADD COLUMN archived_at timestamp with time zone;`)
+	return err
+}
+
+func down20191120083512(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE app_versions DROP COLUMN archived_at;`)
+	return err
+}
@@ -0,0 +1,23 @@
+package migration
+
+import (
+	"database/sql"
+
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(up20191121091540, down20191121091540)
+}
+
+func up20191121091540(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE app_settings
+		ADD COLUMN retention_policy json NOT NULL DEFAULT '{}'::json;`)
+	return err
+}
+
+func down20191121091540(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE app_settings
+		DROP COLUMN retention_policy;`)
+	return err
+}
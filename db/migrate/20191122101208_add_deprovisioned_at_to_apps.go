@@ -0,0 +1,25 @@
+package migration
+
+import (
+	"database/sql"
+
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(up20191122101208, down20191122101208)
+}
+
+func up20191122101208(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE apps
+		ADD COLUMN deprovisioned_at timestamp with time zone;
+		CREATE INDEX apps_deprovisioned_at_idx ON apps (deprovisioned_at);`)
+	return err
+}
+
+func down20191122101208(tx *sql.Tx) error {
+	_, err := tx.Exec(`DROP INDEX apps_deprovisioned_at_idx;
+		ALTER TABLE apps
+		DROP COLUMN deprovisioned_at;`)
+	return err
+}
@@ -0,0 +1,23 @@
+package migration
+
+import (
+	"database/sql"
+
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(up20191125093018, down20191125093018)
+}
+
+func up20191125093018(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE app_settings
+		ADD COLUMN version_rules json NOT NULL DEFAULT '{}'::json;`)
+	return err
+}
+
+func down20191125093018(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE app_settings
+		DROP COLUMN version_rules;`)
+	return err
+}
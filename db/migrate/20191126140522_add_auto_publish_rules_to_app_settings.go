@@ -0,0 +1,23 @@
+package migration
+
+import (
+	"database/sql"
+
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(up20191126140522, down20191126140522)
+}
+
+func up20191126140522(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE app_settings
+		ADD COLUMN auto_publish_rules json NOT NULL DEFAULT '[]'::json;`)
+	return err
+}
+
+func down20191126140522(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE app_settings
+		DROP COLUMN auto_publish_rules;`)
+	return err
+}
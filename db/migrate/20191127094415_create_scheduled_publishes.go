@@ -0,0 +1,31 @@
+package migration
+
+import (
+	"database/sql"
+
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(up20191127094415, down20191127094415)
+}
+
+func up20191127094415(tx *sql.Tx) error {
+	_, err := tx.Exec(`CREATE TABLE scheduled_publishes (
+		id uuid primary key NOT NULL,
+		app_version_id uuid NOT NULL REFERENCES app_versions (id) ON DELETE CASCADE,
+		scheduled_at timestamp with time zone NOT NULL,
+		destinations json NOT NULL DEFAULT '[]',
+		status text NOT NULL DEFAULT 'scheduled',
+		failure_reason text NOT NULL DEFAULT '',
+		created_at timestamp with time zone NOT NULL,
+		updated_at timestamp with time zone NOT NULL
+	);
+	CREATE INDEX scheduled_publishes_status_scheduled_at_idx ON scheduled_publishes (status, scheduled_at);`)
+	return err
+}
+
+func down20191127094415(tx *sql.Tx) error {
+	_, err := tx.Exec(`DROP TABLE scheduled_publishes;`)
+	return err
+}
@@ -0,0 +1,41 @@
+package migration
+
+import (
+	"database/sql"
+
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(up20191128103046, down20191128103046)
+}
+
+func up20191128103046(tx *sql.Tx) error {
+	_, err := tx.Exec(`CREATE TABLE approvals (
+		id uuid primary key NOT NULL,
+		app_version_id uuid NOT NULL REFERENCES app_versions (id) ON DELETE CASCADE,
+		approver text NOT NULL,
+		comment text NOT NULL DEFAULT '',
+		status text NOT NULL DEFAULT 'approved',
+		revoke_comment text NOT NULL DEFAULT '',
+		created_at timestamp with time zone NOT NULL,
+		updated_at timestamp with time zone NOT NULL
+	);
+	CREATE INDEX approvals_app_version_id_idx ON approvals (app_version_id);`)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`ALTER TABLE app_settings
+		ADD COLUMN approval_requirements json NOT NULL DEFAULT '[]'::json;`)
+	return err
+}
+
+func down20191128103046(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE app_settings
+		DROP COLUMN approval_requirements;`)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`DROP TABLE approvals;`)
+	return err
+}
@@ -0,0 +1,23 @@
+package migration
+
+import (
+	"database/sql"
+
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(up20191202091214, down20191202091214)
+}
+
+func up20191202091214(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE approvals
+		ADD COLUMN token text UNIQUE;`)
+	return err
+}
+
+func down20191202091214(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE approvals
+		DROP COLUMN token;`)
+	return err
+}
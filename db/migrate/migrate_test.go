@@ -0,0 +1,122 @@
+// +build database
+
+package migration_test
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/bitrise-io/addons-ship-backend/dataservices"
+	_ "github.com/bitrise-io/addons-ship-backend/db/migrate"
+	"github.com/c2fo/testify/require"
+	"github.com/pressly/goose"
+	uuid "github.com/satori/go.uuid"
+)
+
+// prepareDB recreates the test database, and migrates it up to the given
+// version
+func prepareDB(t *testing.T, version int64) *sql.DB {
+	dataservices.Close()
+	require.NoError(t, dataservices.InitializeConnection(dataservices.ConnectionParams{}, false))
+	testDBName := os.Getenv("TEST_DB_NAME")
+	require.NoError(t, dataservices.GetDB().Exec("DROP DATABASE IF EXISTS "+testDBName).Error)
+	require.NoError(t, dataservices.GetDB().Exec("CREATE DATABASE "+testDBName).Error)
+	dataservices.Close()
+
+	require.NoError(t, dataservices.InitializeConnection(dataservices.ConnectionParams{DBName: testDBName}, true))
+	db := dataservices.GetDB().DB()
+	require.NoError(t, goose.UpTo(db, ".", version))
+	return db
+}
+
+func insertApp(t *testing.T, db *sql.DB) string {
+	id := uuid.NewV4().String()
+	_, err := db.Exec(`INSERT INTO apps (id, app_slug, created_at, updated_at) VALUES ($1, $2, now(), now())`, id, id)
+	require.NoError(t, err)
+	return id
+}
+
+func insertAppVersion(t *testing.T, db *sql.DB, appID string, createdAt time.Time) string {
+	id := uuid.NewV4().String()
+	_, err := db.Exec(`INSERT INTO app_versions (id, app_id, platform, created_at, updated_at) VALUES ($1, $2, 'android', $3, $3)`,
+		id, appID, createdAt)
+	require.NoError(t, err)
+	return id
+}
+
+func insertPublishTask(t *testing.T, db *sql.DB, appVersionID string, createdAt time.Time) string {
+	id := uuid.NewV4().String()
+	_, err := db.Exec(`INSERT INTO publish_tasks (id, app_version_id, task_id, created_at, updated_at) VALUES ($1, $2, $3, $4, $4)`,
+		id, appVersionID, uuid.NewV4().String(), createdAt)
+	require.NoError(t, err)
+	return id
+}
+
+func insertAppVersionEvent(t *testing.T, db *sql.DB, appVersionID, status, text string, createdAt time.Time) {
+	_, err := db.Exec(`INSERT INTO app_version_events (id, app_version_id, status, event_text, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $5)`,
+		uuid.NewV4().String(), appVersionID, status, text, createdAt)
+	require.NoError(t, err)
+}
+
+func Test_AddDestinationToPublishTasks(t *testing.T) {
+	db := prepareDB(t, 20191029101203)
+	defer dataservices.Close()
+
+	startedAt := time.Now().Add(-time.Hour)
+	appID := insertApp(t, db)
+	appVersionID := insertAppVersion(t, db, appID, startedAt)
+	successfulTaskID := insertPublishTask(t, db, appVersionID, startedAt)
+	insertAppVersionEvent(t, db, appVersionID, "in_progress", "Publishing has started", startedAt.Add(time.Minute))
+	insertAppVersionEvent(t, db, appVersionID, "success", "Successfully published", startedAt.Add(2*time.Minute))
+	failedTaskID := insertPublishTask(t, db, appVersionID, startedAt.Add(3*time.Minute))
+	insertAppVersionEvent(t, db, appVersionID, "failed", "Failed to publish", startedAt.Add(4*time.Minute))
+	unfinishedTaskID := insertPublishTask(t, db, appVersionID, startedAt.Add(5*time.Minute))
+
+	require.NoError(t, goose.UpTo(db, ".", 20191104093512))
+
+	for taskID, status := range map[string]string{
+		successfulTaskID: "success",
+		failedTaskID:     "failed",
+		unfinishedTaskID: "failed",
+	} {
+		var migratedStatus string
+		require.NoError(t, db.QueryRow(`SELECT status FROM publish_tasks WHERE id = $1`, taskID).Scan(&migratedStatus))
+		require.Equal(t, status, migratedStatus)
+	}
+}
+
+func Test_AddStatusToAppVersions(t *testing.T) {
+	db := prepareDB(t, 20191118093027)
+	defer dataservices.Close()
+
+	createdAt := time.Now().Add(-time.Hour)
+	appID := insertApp(t, db)
+	publishedVersionID := insertAppVersion(t, db, appID, createdAt)
+	// a task created before its status got backfilled
+	insertPublishTask(t, db, publishedVersionID, createdAt)
+	insertAppVersionEvent(t, db, publishedVersionID, "in_progress", "Publishing has started", createdAt.Add(time.Minute))
+	insertAppVersionEvent(t, db, publishedVersionID, "success", "Successfully published", createdAt.Add(2*time.Minute))
+	failedVersionID := insertAppVersion(t, db, appID, createdAt.Add(3*time.Minute))
+	insertPublishTask(t, db, failedVersionID, createdAt.Add(3*time.Minute))
+	insertAppVersionEvent(t, db, failedVersionID, "success", "Successfully published", createdAt.Add(4*time.Minute))
+	insertAppVersionEvent(t, db, failedVersionID, "failed", "Failed to publish", createdAt.Add(5*time.Minute))
+	supersededVersionID := insertAppVersion(t, db, appID, createdAt.Add(6*time.Minute))
+	insertPublishTask(t, db, supersededVersionID, createdAt.Add(6*time.Minute))
+	insertAppVersionEvent(t, db, supersededVersionID, "in_progress", "Publishing has started", createdAt.Add(7*time.Minute))
+	readyVersionID := insertAppVersion(t, db, appID, createdAt.Add(8*time.Minute))
+
+	require.NoError(t, goose.UpTo(db, ".", 20191119102134))
+
+	for appVersionID, status := range map[string]string{
+		publishedVersionID:  "published",
+		failedVersionID:     "failed",
+		supersededVersionID: "superseded",
+		readyVersionID:      "ready",
+	} {
+		var migratedStatus string
+		require.NoError(t, db.QueryRow(`SELECT status FROM app_versions WHERE id = $1`, appVersionID).Scan(&migratedStatus))
+		require.Equal(t, status, migratedStatus)
+	}
+}
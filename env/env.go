@@ -10,12 +10,14 @@ import (
 	"github.com/bitrise-io/addons-ship-backend/analytics"
 	"github.com/bitrise-io/addons-ship-backend/bitrise"
 	"github.com/bitrise-io/addons-ship-backend/dataservices"
+	"github.com/bitrise-io/addons-ship-backend/dataservices/memory"
 	"github.com/bitrise-io/addons-ship-backend/mailer"
 	"github.com/bitrise-io/addons-ship-backend/models"
 	"github.com/bitrise-io/addons-ship-backend/redis"
 	"github.com/bitrise-io/api-utils/logging"
 	"github.com/bitrise-io/api-utils/providers"
 	"github.com/bitrise-io/api-utils/security"
+	"github.com/bitrise-io/api-utils/utils"
 	"github.com/jinzhu/gorm"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
@@ -45,6 +47,9 @@ type AppEnv struct {
 	AppSettingsService       dataservices.AppSettingsService
 	AppVersionEventService   dataservices.AppVersionEventService
 	PublishTaskService       dataservices.PublishTaskService
+	ReleaseService           dataservices.ReleaseService
+	ScheduledPublishService  dataservices.ScheduledPublishService
+	ApprovalService          dataservices.ApprovalService
 	BitriseAPI               bitrise.APIInterface
 	RequestParams            providers.RequestParamsInterface
 	AWS                      providers.AWSInterface
@@ -59,6 +64,10 @@ type AppEnv struct {
 	AnalyticsClient          analytics.Interface
 	TimeService              dataservices.TimeInterface
 	JWTService               security.JWTInterface
+
+	// DeprovisionGracePeriod is how long a deprovisioned app is kept before
+	// it gets purged
+	DeprovisionGracePeriod time.Duration
 }
 
 // New ...
@@ -98,6 +107,9 @@ func New(db *gorm.DB) (*AppEnv, error) {
 	env.AppSettingsService = &models.AppSettingsService{DB: db}
 	env.AppVersionEventService = &models.AppVersionEventService{DB: db}
 	env.PublishTaskService = &models.PublishTaskService{DB: db}
+	env.ReleaseService = &models.ReleaseService{DB: db}
+	env.ScheduledPublishService = &models.ScheduledPublishService{DB: db}
+	env.ApprovalService = &models.ApprovalService{DB: db}
 	if env.Environment == ServerEnvDevelopment {
 		env.BitriseAPI = &bitrise.APIDev{}
 	} else {
@@ -123,6 +135,7 @@ func New(db *gorm.DB) (*AppEnv, error) {
 	env.RedisExpirationTime = int(redisExpiration)
 	env.Redis = redis.New()
 	env.LogStoreService = &models.LogStoreService{Redis: redis.New(), Expiration: env.RedisExpirationTime}
+	env.DeprovisionGracePeriod = time.Duration(utils.GetInt64EnvWithDefault("DEPROVISION_GRACE_PERIOD_DAYS", 30)) * 24 * time.Hour
 
 	awsMailRegion, ok := os.LookupEnv("AWS_MAIL_REGION")
 	if !ok {
@@ -176,6 +189,22 @@ func New(db *gorm.DB) (*AppEnv, error) {
 	return env, nil
 }
 
+// UseInMemoryDataStore replaces the database backed data services with in-memory ones
+func (env *AppEnv) UseInMemoryDataStore() {
+	store := memory.New()
+	env.AppService = &memory.AppService{Store: store}
+	env.AppContactService = &memory.AppContactService{Store: store}
+	env.AppVersionService = &memory.AppVersionService{Store: store}
+	env.ScreenshotService = &memory.ScreenshotService{Store: store}
+	env.FeatureGraphicService = &memory.FeatureGraphicService{Store: store}
+	env.AppSettingsService = &memory.AppSettingsService{Store: store}
+	env.AppVersionEventService = &memory.AppVersionEventService{Store: store}
+	env.PublishTaskService = &memory.PublishTaskService{Store: store}
+	env.ReleaseService = &memory.ReleaseService{Store: store}
+	env.ScheduledPublishService = &memory.ScheduledPublishService{Store: store}
+	env.ApprovalService = &memory.ApprovalService{Store: store}
+}
+
 func awsConfig() (providers.AWSConfig, error) {
 	awsBucket, ok := os.LookupEnv("AWS_BUCKET")
 	if !ok {
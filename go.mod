@@ -14,6 +14,7 @@ require (
 	github.com/go-yaml/yaml v2.1.0+incompatible
 	github.com/gocraft/work v0.5.1
 	github.com/gomodule/redigo v2.0.0+incompatible
+	github.com/gorilla/mux v1.7.4
 	github.com/heroku/x v0.0.26
 	github.com/jinzhu/gorm v1.9.12
 	github.com/justinas/alice v1.2.0
@@ -32,6 +33,7 @@ require (
 	github.com/tinylib/msgp v1.1.2 // indirect
 	github.com/xtgo/uuid v0.0.0-20140804021211-a0b114877d4c // indirect
 	go.uber.org/zap v1.14.1
+	golang.org/x/crypto v0.0.0-20200220183623-bac4c82f6975
 	golang.org/x/time v0.0.0-20191024005414-555d28b269f0 // indirect
 	gopkg.in/DataDog/dd-trace-go.v1 v1.22.0
 	gopkg.in/segmentio/analytics-go.v3 v3.1.0
@@ -13,6 +13,8 @@ type Interface interface {
 	SendEmailConfirmation(confirmURL string, contact *models.AppContact, appDetails *bitrise.AppDetails) error
 	SendEmailNewVersion(appVersion *models.AppVersion, contacts []models.AppContact, frontendBaseURL string, appDetails *bitrise.AppDetails) error
 	SendEmailPublish(appVersion *models.AppVersion, contacts []models.AppContact, appDetails *bitrise.AppDetails, frontendBaseURL string, publishSucceeded bool) error
+	SendEmailCodeSigningExpiry(app *models.App, contacts []models.AppContact, appDetails *bitrise.AppDetails, frontendBaseURL string, expiringFiles []models.CodeSigningFileExpiry) error
+	SendEmailApprovalRequest(appVersion *models.AppVersion, approval *models.Approval, appDetails *bitrise.AppDetails, frontendBaseURL string) error
 }
 
 // Request ...
@@ -132,7 +132,7 @@ func (m *SES) SendEmailPublish(appVersion *models.AppVersion, contacts []models.
 	}
 
 	var publishTarget, publishURL string
-	if appVersion.Platform == "ios" {
+	if appVersion.IsApplePlatform() {
 		publishTarget = "App Store Connect"
 		publishURL = "https://appstoreconnect.apple.com"
 	} else if appVersion.Platform == "android" {
@@ -184,6 +184,75 @@ func (m *SES) SendEmailPublish(appVersion *models.AppVersion, contacts []models.
 	return nil
 }
 
+// SendEmailCodeSigningExpiry ...
+func (m *SES) SendEmailCodeSigningExpiry(app *models.App, contacts []models.AppContact, appDetails *bitrise.AppDetails, frontendBaseURL string, expiringFiles []models.CodeSigningFileExpiry) error {
+	appIconURL := defaultIconURL(appDetails.ProjectType)
+	if appDetails.AvatarURL != nil {
+		appIconURL = *appDetails.AvatarURL
+	}
+	for _, contact := range contacts {
+		if contact.ConfirmedAt.IsZero() {
+			continue
+		}
+		nameForHey := getUsernameFromEmail(contact.Email)
+		err := m.sendMail(&Request{
+			To:      []string{contact.Email},
+			From:    m.FromEmail,
+			Subject: "⏰ Code signing files of your app are about to expire. ⏰",
+		},
+			"email/code_signing_expiry.html",
+			map[string]interface{}{
+				"CurrentTime":   func() time.Time { return time.Now() },
+				"Name":          func() string { return nameForHey },
+				"AppTitle":      func() string { return appDetails.Title },
+				"AppIconURL":    func() string { return appIconURL },
+				"ExpiringFiles": func() []models.CodeSigningFileExpiry { return expiringFiles },
+				"AppURL": func() string {
+					return fmt.Sprintf("%s/apps/%s/settings", frontendBaseURL, app.AppSlug)
+				},
+			})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// SendEmailApprovalRequest sends the link, which approves publishing the app
+// version by the app contact of the approval, identified by its token
+func (m *SES) SendEmailApprovalRequest(appVersion *models.AppVersion, approval *models.Approval, appDetails *bitrise.AppDetails, frontendBaseURL string) error {
+	if approval.Token == nil {
+		return errors.New("Approval token is empty")
+	}
+	artifactInfo, err := appVersion.ArtifactInfo()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	appIconURL := defaultIconURL(appDetails.ProjectType)
+	if appDetails.AvatarURL != nil {
+		appIconURL = *appDetails.AvatarURL
+	}
+	nameForHey := getUsernameFromEmail(approval.Approver)
+	return m.sendMail(&Request{
+		To:      []string{approval.Approver},
+		From:    m.FromEmail,
+		Subject: "✅ A new app version is waiting for your approval on Ship. ✅",
+	},
+		"email/approval_request.html",
+		map[string]interface{}{
+			"CurrentTime": func() time.Time { return time.Now() },
+			"Name":        func() string { return nameForHey },
+			"AppTitle":    func() string { return appDetails.Title },
+			"AppIconURL":  func() string { return appIconURL },
+			"Version":     func() string { return artifactInfo.Version },
+			"BuildNumber": func() string { return appVersion.BuildNumber },
+			"AppURL": func() string {
+				return fmt.Sprintf("%s/apps/%s/versions/%s/approve?token=%s", frontendBaseURL, appVersion.App.AppSlug, appVersion.ID, *approval.Token)
+			},
+		},
+	)
+}
+
 func getUsernameFromEmail(email string) string {
 	return strings.Split(email, "@")[0]
 }
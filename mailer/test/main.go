@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/bitrise-io/addons-ship-backend/bitrise"
 	"github.com/bitrise-io/addons-ship-backend/mailer"
@@ -72,6 +73,22 @@ func main() {
 		if err != nil {
 			failEmailSend(err)
 		}
+	case "code_signing_expiry":
+		testAppContacts[0].ConfirmedAt = time.Now()
+		err := ses.SendEmailCodeSigningExpiry(&testAppVersion.App, testAppContacts, testAppDetails, "http://bitrise.io", []models.CodeSigningFileExpiry{
+			models.CodeSigningFileExpiry{Slug: "prov-profile-slug", Filename: "app_store.mobileprovision", ExpiresAt: time.Now().AddDate(0, 0, 7)},
+		})
+		if err != nil {
+			failEmailSend(err)
+		}
+	case "approval_request":
+		err := ses.SendEmailApprovalRequest(testAppVersion, &models.Approval{
+			Approver: targetEmail,
+			Token:    pointers.NewStringPtr("your-approval-token"),
+		}, testAppDetails, "http://bitrise.io")
+		if err != nil {
+			failEmailSend(err)
+		}
 	default:
 		failEmailSend(errors.New("No MAIL_TO_SEND env var defined"))
 	}
@@ -46,19 +46,26 @@ func main() {
 		tracer.Start(tracer.WithServiceName("addons-ship"))
 		defer tracer.Stop()
 
-		err := dataservices.InitializeConnection(dataservices.ConnectionParams{}, true)
-		if err != nil {
-			logger.Error("Failed to initialize DB connection", zap.Any("error", err))
-			os.Exit(1)
+		inMemoryDataStore := os.Getenv("IN_MEMORY_DATA_STORE") == "true"
+		if !inMemoryDataStore {
+			err := dataservices.InitializeConnection(dataservices.ConnectionParams{}, true)
+			if err != nil {
+				logger.Error("Failed to initialize DB connection", zap.Any("error", err))
+				os.Exit(1)
+			}
+			defer dataservices.Close()
+			log.Println(" [OK] Database connection established")
 		}
-		defer dataservices.Close()
-		log.Println(" [OK] Database connection established")
 
 		appEnv, err := env.New(dataservices.GetDB())
 		if err != nil {
 			logger.Error("Failed to initialize Application Environment object", zap.Any("error", err))
 			os.Exit(1)
 		}
+		if inMemoryDataStore {
+			appEnv.UseInMemoryDataStore()
+			log.Println(" [OK] Using in-memory data store, data is lost on restart")
+		}
 
 		appEnv.WorkerService = &worker.Service{}
 
@@ -0,0 +1,84 @@
+package models
+
+import "github.com/pkg/errors"
+
+const (
+	// ReleaseStatusDraft ...
+	ReleaseStatusDraft = "draft"
+	// ReleaseStatusInProgress ...
+	ReleaseStatusInProgress = "inProgress"
+	// ReleaseStatusHalted ...
+	ReleaseStatusHalted = "halted"
+	// ReleaseStatusCompleted ...
+	ReleaseStatusCompleted = "completed"
+
+	maxInAppUpdatePriority = 5
+)
+
+// AndroidRollout describes how a version gets released on a Google Play track.
+// A release with inProgress or halted status is a staged rollout, which is
+// available for the given fraction of the users.
+type AndroidRollout struct {
+	UserFraction        float64 `json:"user_fraction,omitempty"`
+	ReleaseStatus       string  `json:"release_status,omitempty"`
+	ReleaseName         string  `json:"release_name,omitempty"`
+	InAppUpdatePriority int     `json:"in_app_update_priority,omitempty"`
+}
+
+// Staged ...
+func (r AndroidRollout) Staged() bool {
+	return r.ReleaseStatus == ReleaseStatusInProgress || r.ReleaseStatus == ReleaseStatusHalted
+}
+
+// Validate ...
+func (r AndroidRollout) Validate() []error {
+	verrs := []error{}
+	switch r.ReleaseStatus {
+	case "", ReleaseStatusDraft, ReleaseStatusCompleted:
+		if r.UserFraction != 0 {
+			verrs = append(verrs, errors.New("user_fraction: Can only be set for staged rollouts"))
+		}
+	case ReleaseStatusInProgress, ReleaseStatusHalted:
+		if r.UserFraction <= 0 || r.UserFraction >= 1 {
+			verrs = append(verrs, errors.New("user_fraction: Must be greater than 0 and less than 1"))
+		}
+	default:
+		verrs = append(verrs, errors.New("release_status: Must be one of draft, inProgress, halted, completed"))
+	}
+	if r.InAppUpdatePriority < 0 || r.InAppUpdatePriority > maxInAppUpdatePriority {
+		verrs = append(verrs, errors.New("in_app_update_priority: Must be between 0 and 5"))
+	}
+	return verrs
+}
+
+// Increase returns the rollout made available for more users
+func (r AndroidRollout) Increase(userFraction float64) (AndroidRollout, error) {
+	if !r.Staged() {
+		return AndroidRollout{}, errors.New("Rollout can only be increased while it's staged")
+	}
+	if userFraction <= r.UserFraction || userFraction >= 1 {
+		return AndroidRollout{}, errors.New("User fraction has to be greater than the current one and less than 1")
+	}
+	r.ReleaseStatus = ReleaseStatusInProgress
+	r.UserFraction = userFraction
+	return r, nil
+}
+
+// Halt returns the rollout stopped for new users
+func (r AndroidRollout) Halt() (AndroidRollout, error) {
+	if r.ReleaseStatus != ReleaseStatusInProgress {
+		return AndroidRollout{}, errors.New("Rollout can only be halted while it's in progress")
+	}
+	r.ReleaseStatus = ReleaseStatusHalted
+	return r, nil
+}
+
+// Complete returns the rollout made available for every user
+func (r AndroidRollout) Complete() (AndroidRollout, error) {
+	if !r.Staged() {
+		return AndroidRollout{}, errors.New("Rollout can only be completed while it's staged")
+	}
+	r.ReleaseStatus = ReleaseStatusCompleted
+	r.UserFraction = 0
+	return r, nil
+}
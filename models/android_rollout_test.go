@@ -0,0 +1,92 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/c2fo/testify/require"
+)
+
+func Test_AndroidRollout_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		rollout        models.AndroidRollout
+		expectedErrors []string
+	}{
+		{
+			name:           "when rollout is empty",
+			rollout:        models.AndroidRollout{},
+			expectedErrors: []string{},
+		},
+		{
+			name:           "when rollout is staged",
+			rollout:        models.AndroidRollout{ReleaseStatus: "inProgress", UserFraction: 0.1, ReleaseName: "1.0.0", InAppUpdatePriority: 5},
+			expectedErrors: []string{},
+		},
+		{
+			name:           "when user fraction is missing for a staged rollout",
+			rollout:        models.AndroidRollout{ReleaseStatus: "halted"},
+			expectedErrors: []string{"user_fraction: Must be greater than 0 and less than 1"},
+		},
+		{
+			name:           "when user fraction is set for a completed release",
+			rollout:        models.AndroidRollout{ReleaseStatus: "completed", UserFraction: 0.5},
+			expectedErrors: []string{"user_fraction: Can only be set for staged rollouts"},
+		},
+		{
+			name:    "when release status and in-app update priority are invalid",
+			rollout: models.AndroidRollout{ReleaseStatus: "rolling", InAppUpdatePriority: 6},
+			expectedErrors: []string{
+				"release_status: Must be one of draft, inProgress, halted, completed",
+				"in_app_update_priority: Must be between 0 and 5",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			errorMessages := []string{}
+			for _, verr := range tc.rollout.Validate() {
+				errorMessages = append(errorMessages, verr.Error())
+			}
+			require.Equal(t, tc.expectedErrors, errorMessages)
+		})
+	}
+}
+
+func Test_AndroidRollout_Transitions(t *testing.T) {
+	staged := models.AndroidRollout{ReleaseStatus: "inProgress", UserFraction: 0.1, ReleaseName: "1.0.0"}
+
+	t.Run("Increase", func(t *testing.T) {
+		increased, err := staged.Increase(0.5)
+		require.NoError(t, err)
+		require.Equal(t, models.AndroidRollout{ReleaseStatus: "inProgress", UserFraction: 0.5, ReleaseName: "1.0.0"}, increased)
+
+		resumed, err := models.AndroidRollout{ReleaseStatus: "halted", UserFraction: 0.1}.Increase(0.2)
+		require.NoError(t, err)
+		require.Equal(t, models.AndroidRollout{ReleaseStatus: "inProgress", UserFraction: 0.2}, resumed)
+
+		_, err = staged.Increase(0.1)
+		require.EqualError(t, err, "User fraction has to be greater than the current one and less than 1")
+		_, err = staged.Increase(1)
+		require.EqualError(t, err, "User fraction has to be greater than the current one and less than 1")
+		_, err = models.AndroidRollout{}.Increase(0.5)
+		require.EqualError(t, err, "Rollout can only be increased while it's staged")
+	})
+
+	t.Run("Halt", func(t *testing.T) {
+		halted, err := staged.Halt()
+		require.NoError(t, err)
+		require.Equal(t, models.AndroidRollout{ReleaseStatus: "halted", UserFraction: 0.1, ReleaseName: "1.0.0"}, halted)
+
+		_, err = halted.Halt()
+		require.EqualError(t, err, "Rollout can only be halted while it's in progress")
+	})
+
+	t.Run("Complete", func(t *testing.T) {
+		completed, err := staged.Complete()
+		require.NoError(t, err)
+		require.Equal(t, models.AndroidRollout{ReleaseStatus: "completed", ReleaseName: "1.0.0"}, completed)
+
+		_, err = completed.Complete()
+		require.EqualError(t, err, "Rollout can only be completed while it's staged")
+	})
+}
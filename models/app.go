@@ -2,6 +2,7 @@ package models
 
 import (
 	"os"
+	"time"
 
 	"github.com/bitrise-io/go-crypto/crypto"
 	"github.com/jinzhu/gorm"
@@ -23,6 +24,10 @@ type App struct {
 	HeaderColor2      string         `db:"header_color_2" gorm:"column:header_color_2" json:"header_color_2"`
 	AndroidErrors     pq.StringArray `json:"android_errors" gorm:"type:varchar(128)[]"`
 	IosErrors         pq.StringArray `json:"ios_errors" gorm:"type:varchar(128)[]"`
+	// DeprovisionedAt is set for the apps which were deprovisioned, they are
+	// kept for a grace period so that they can be restored by provisioning
+	// them again
+	DeprovisionedAt *time.Time `db:"deprovisioned_at" json:"deprovisioned_at,omitempty"`
 
 	AppVersions []AppVersion `gorm:"foreignkey:AppID" json:"app_versions"`
 	AppSettings AppSettings  `gorm:"foreignkey:AppsID" json:"app_settings"`
@@ -34,6 +39,17 @@ func (a *App) BeforeCreate(scope *gorm.Scope) error {
 		a.ID = uuid.NewV4()
 	}
 
+	return a.GenerateSecret(func(iv []byte) (bool, error) {
+		var appCount int64
+		err := scope.DB().Model(&App{}).Where("encrypted_secret_iv = ?", iv).Count(&appCount).Error
+		return appCount > 0, err
+	})
+}
+
+// GenerateSecret generates and encrypts the webhook secret of a new app, unless
+// it already has one. The given function reports whether an IV is already
+// used by another app.
+func (a *App) GenerateSecret(isIVTaken func(iv []byte) (bool, error)) error {
 	if len(a.EncryptedSecretIV) != 0 {
 		return nil
 	}
@@ -47,12 +63,11 @@ func (a *App) BeforeCreate(scope *gorm.Scope) error {
 		if err != nil {
 			return errors.WithStack(err)
 		}
-		var appCount int64
-		err := scope.DB().Model(&App{}).Where("encrypted_secret_iv = ?", a.EncryptedSecretIV).Count(&appCount).Error
+		taken, err := isIVTaken(a.EncryptedSecretIV)
 		if err != nil {
 			return errors.WithStack(err)
 		}
-		if appCount == 0 {
+		if !taken {
 			break
 		}
 	}
@@ -92,3 +107,30 @@ func (a *App) Secret() (string, error) {
 
 	return secret, nil
 }
+
+// AndroidServiceAccountErrors returns the problems of the selected service
+// account file
+func (a *App) AndroidServiceAccountErrors() []string {
+	serviceAccountErrors := []string{}
+	for _, androidError := range a.AndroidErrors {
+		if isServiceAccountError(androidError) {
+			serviceAccountErrors = append(serviceAccountErrors, androidError)
+		}
+	}
+	return serviceAccountErrors
+}
+
+// SetAndroidServiceAccountErrors replaces the problems of the selected service
+// account file, the rest of the Android errors are kept
+func (a *App) SetAndroidServiceAccountErrors(errs []error) {
+	androidErrors := []string{}
+	for _, androidError := range a.AndroidErrors {
+		if !isServiceAccountError(androidError) {
+			androidErrors = append(androidErrors, androidError)
+		}
+	}
+	for _, err := range errs {
+		androidErrors = append(androidErrors, err.Error())
+	}
+	a.AndroidErrors = androidErrors
+}
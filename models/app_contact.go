@@ -55,18 +55,20 @@ func (a *AppContact) BeforeSave(scope *gorm.Scope) error {
 }
 
 func (a *AppContact) validate(scope *gorm.Scope) error {
-	var err error
+	return addValidationErrors(scope, a.Validate())
+}
+
+// Validate ...
+func (a *AppContact) Validate() []error {
+	verrs := []error{}
 	if len(a.Email) > maxCharNumberForEmail {
-		err = scope.DB().AddError(NewValidationError("email: Too long"))
+		verrs = append(verrs, errors.New("email: Too long"))
 	}
 	ev := EmailVerifier{Email: a.Email}
 	if !ev.Verify() {
-		err = scope.DB().AddError(NewValidationError("email: Wrong format"))
+		verrs = append(verrs, errors.New("email: Wrong format"))
 	}
-	if err != nil {
-		return errors.New("Validation failed")
-	}
-	return nil
+	return verrs
 }
 
 // NotificationPreferences ...
@@ -1,6 +1,10 @@
 package models
 
-import "github.com/jinzhu/gorm"
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
 
 // AppService ...
 type AppService struct {
@@ -18,9 +22,9 @@ func (a *AppService) Create(app *App) (*App, error) {
 	return app, a.DB.Create(&app.AppSettings).Error
 }
 
-// Find ...
+// Find returns the app, unless it was deprovisioned
 func (a *AppService) Find(app *App) (*App, error) {
-	err := a.DB.Where(app).First(app).Error
+	err := a.DB.Where(app).Where("deprovisioned_at IS NULL").First(app).Error
 	if err != nil {
 		return nil, err
 	}
@@ -35,6 +39,28 @@ func (a *AppService) Find(app *App) (*App, error) {
 	return app, nil
 }
 
+// FindDeprovisioned returns the app, if it was deprovisioned
+func (a *AppService) FindDeprovisioned(app *App) (*App, error) {
+	err := a.DB.Where(app).Where("deprovisioned_at IS NOT NULL").First(app).Error
+	if err != nil {
+		return nil, err
+	}
+	return app, nil
+}
+
+// FindAllDeprovisionedBefore returns the apps which were deprovisioned before
+// the given time
+func (a *AppService) FindAllDeprovisionedBefore(deprovisionedBefore time.Time) ([]App, error) {
+	var apps []App
+	err := a.DB.Where("deprovisioned_at < ?", deprovisionedBefore).
+		Order("deprovisioned_at ASC").
+		Find(&apps).Error
+	if err != nil {
+		return nil, err
+	}
+	return apps, nil
+}
+
 // Update ...
 func (a *AppService) Update(app *App, whitelist []string) (validationErrors []error, dbErr error) {
 	updateData, err := a.UpdateData(*app, whitelist)
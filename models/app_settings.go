@@ -2,9 +2,13 @@ package models
 
 import (
 	"encoding/json"
+	"os"
 	"reflect"
+	"time"
 
+	"github.com/bitrise-io/go-crypto/crypto"
 	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 	"github.com/thoas/go-funk"
 )
@@ -17,6 +21,12 @@ type IosSettings struct {
 	SelectedAppStoreProvisioningProfiles []string `json:"selected_app_store_provisioning_profiles"`
 	SelectedCodeSigningIdentity          string   `json:"selected_code_signing_identity"`
 	IncludeBitCode                       bool     `json:"include_bit_code"`
+	// App Store Connect API key, used instead of the Apple ID and the
+	// app-specific password when it's set. The private key is only sent in
+	// requests, it's stored encrypted in the app settings.
+	APIKeyIssuerID   string `json:"api_key_issuer_id"`
+	APIKeyID         string `json:"api_key_id"`
+	APIKeyPrivateKey string `json:"api_key_private_key,omitempty"`
 }
 
 // Valid ...
@@ -24,6 +34,30 @@ func (s IosSettings) Valid() bool {
 	return !reflect.DeepEqual(s, IosSettings{})
 }
 
+// HasAPIKey ...
+func (s IosSettings) HasAPIKey() bool {
+	return s.APIKeyIssuerID != "" || s.APIKeyID != ""
+}
+
+// Validate ...
+func (s IosSettings) Validate() []error {
+	verrs := []error{}
+	if s.HasAPIKey() {
+		if err := validateAPIKeyIssuerID(s.APIKeyIssuerID); err != nil {
+			verrs = append(verrs, err)
+		}
+		if err := validateAPIKeyID(s.APIKeyID); err != nil {
+			verrs = append(verrs, err)
+		}
+	}
+	if s.APIKeyPrivateKey != "" {
+		if err := validateAPIKeyPrivateKey(s.APIKeyPrivateKey); err != nil {
+			verrs = append(verrs, err)
+		}
+	}
+	return verrs
+}
+
 // ValidateProvisioningProfileSlugs ...
 func (s *IosSettings) ValidateSelectedProvisioningProfileSlugs(provProfiles []string) {
 	if len(provProfiles) == 0 && len(s.SelectedAppStoreProvisioningProfiles) > 0 {
@@ -40,6 +74,11 @@ type AndroidSettings struct {
 	SelectedKeystoreFile   string `json:"selected_keystore_file"`
 	SelectedServiceAccount string `json:"selected_service_account"`
 	Module                 string `json:"module"`
+	// rollout configuration of the releases, see AndroidRollout
+	UserFraction        float64 `json:"user_fraction"`
+	ReleaseStatus       string  `json:"release_status"`
+	ReleaseName         string  `json:"release_name"`
+	InAppUpdatePriority int     `json:"in_app_update_priority"`
 }
 
 // Valid ...
@@ -47,6 +86,16 @@ func (s AndroidSettings) Valid() bool {
 	return s != (AndroidSettings{})
 }
 
+// Rollout ...
+func (s AndroidSettings) Rollout() AndroidRollout {
+	return AndroidRollout{
+		UserFraction:        s.UserFraction,
+		ReleaseStatus:       s.ReleaseStatus,
+		ReleaseName:         s.ReleaseName,
+		InAppUpdatePriority: s.InAppUpdatePriority,
+	}
+}
+
 // AppSettings ...
 type AppSettings struct {
 	Record
@@ -54,6 +103,28 @@ type AppSettings struct {
 	AndroidWorkflow     string          `json:"android_workflow"`
 	IosSettingsData     json.RawMessage `json:"-" db:"ios_settings" gorm:"column:ios_settings;type:json"`
 	AndroidSettingsData json.RawMessage `json:"-" db:"android_settings" gorm:"column:android_settings;type:json"`
+	PublishBitriseYML   string          `json:"publish_bitrise_yml" db:"publish_bitrise_yml"`
+	PublishStackID      string          `json:"publish_stack_id" db:"publish_stack_id"`
+	// EncryptedIosAPIKey is the encrypted private key of the App Store Connect
+	// API key of the iOS settings
+	EncryptedIosAPIKey   []byte `json:"-" db:"encrypted_ios_api_key"`
+	EncryptedIosAPIKeyIV []byte `json:"-" db:"encrypted_ios_api_key_iv"`
+	// CodeSigningExpiryData is the expiry of the selected code signing files,
+	// CodeSigningExpiresAt is the earliest one of them
+	CodeSigningExpiryData json.RawMessage `json:"-" db:"code_signing_expiry" gorm:"column:code_signing_expiry;type:json"`
+	CodeSigningExpiresAt  *time.Time      `json:"-" db:"code_signing_expires_at"`
+	// RetentionPolicyData describes which versions of the app get archived or
+	// purged automatically, see RetentionPolicy
+	RetentionPolicyData json.RawMessage `json:"-" db:"retention_policy" gorm:"column:retention_policy;type:json"`
+	// VersionRulesData decides which builds create app versions, see
+	// AppVersionRules
+	VersionRulesData json.RawMessage `json:"-" db:"version_rules" gorm:"column:version_rules;type:json"`
+	// AutoPublishRulesData are the rules the new versions get published by,
+	// see AutoPublishRule
+	AutoPublishRulesData json.RawMessage `json:"-" db:"auto_publish_rules" gorm:"column:auto_publish_rules;type:json"`
+	// ApprovalRequirementsData are the approvals the versions need before
+	// publishing, see ApprovalRequirement
+	ApprovalRequirementsData json.RawMessage `json:"-" db:"approval_requirements" gorm:"column:approval_requirements;type:json"`
 
 	AppID uuid.UUID `db:"app_id" json:"-"`
 	App   *App      `gorm:"foreignkey:AppID" json:"-"`
@@ -70,9 +141,96 @@ func (a *AppSettings) BeforeCreate(scope *gorm.Scope) error {
 	if a.AndroidSettingsData == nil {
 		a.AndroidSettingsData = json.RawMessage(`{}`)
 	}
+	if a.CodeSigningExpiryData == nil {
+		a.CodeSigningExpiryData = json.RawMessage(`{}`)
+	}
+	if a.RetentionPolicyData == nil {
+		a.RetentionPolicyData = json.RawMessage(`{}`)
+	}
+	if a.VersionRulesData == nil {
+		a.VersionRulesData = json.RawMessage(`{}`)
+	}
+	if a.AutoPublishRulesData == nil {
+		a.AutoPublishRulesData = json.RawMessage(`[]`)
+	}
+	if a.ApprovalRequirementsData == nil {
+		a.ApprovalRequirementsData = json.RawMessage(`[]`)
+	}
 	return nil
 }
 
+// BeforeSave ...
+func (a *AppSettings) BeforeSave(scope *gorm.Scope) error {
+	err := a.validate(scope)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func (a *AppSettings) validate(scope *gorm.Scope) error {
+	return addValidationErrors(scope, a.Validate())
+}
+
+// Validate ...
+func (a *AppSettings) Validate() []error {
+	verrs := []error{}
+	if iosSettings, err := a.IosSettings(); err == nil {
+		verrs = append(verrs, iosSettings.Validate()...)
+		if iosSettings.HasAPIKey() && len(a.EncryptedIosAPIKey) == 0 {
+			verrs = append(verrs, errors.New("api_key_private_key: Cannot be empty"))
+		}
+	}
+	if androidSettings, err := a.AndroidSettings(); err == nil {
+		verrs = append(verrs, androidSettings.Rollout().Validate()...)
+	}
+	if retentionPolicy, err := a.RetentionPolicy(); err == nil {
+		plan := ""
+		if a.App != nil {
+			plan = a.App.Plan
+		}
+		verrs = append(verrs, retentionPolicy.Validate(plan)...)
+	}
+	if versionRules, err := a.VersionRules(); err == nil {
+		verrs = append(verrs, versionRules.Validate()...)
+	}
+	if autoPublishRules, err := a.AutoPublishRules(); err == nil {
+		for _, autoPublishRule := range autoPublishRules {
+			verrs = append(verrs, autoPublishRule.Validate()...)
+		}
+	}
+	if approvalRequirements, err := a.ApprovalRequirements(); err == nil {
+		for _, approvalRequirement := range approvalRequirements {
+			verrs = append(verrs, approvalRequirement.Validate()...)
+		}
+	}
+	if a.PublishBitriseYML == "" {
+		return verrs
+	}
+	publishConfig, err := a.PublishConfig()
+	if err != nil {
+		return append(verrs, errors.New("publish_bitrise_yml: Invalid bitrise.yml"))
+	}
+	return append(verrs, publishConfig.Validate()...)
+}
+
+// PublishConfig returns the custom publish config of the app, or nil if the
+// app uses the default one
+func (a *AppSettings) PublishConfig() (PublishConfig, error) {
+	if a.PublishBitriseYML == "" {
+		return nil, nil
+	}
+	return ParsePublishConfig(a.PublishBitriseYML)
+}
+
+// PublishStack returns the ID of the stack the publish workflows run on
+func (a *AppSettings) PublishStack() string {
+	if a.PublishStackID == "" {
+		return DefaultPublishStackID
+	}
+	return a.PublishStackID
+}
+
 // IosSettings ...
 func (a *AppSettings) IosSettings() (IosSettings, error) {
 	var iosSettings IosSettings
@@ -83,6 +241,167 @@ func (a *AppSettings) IosSettings() (IosSettings, error) {
 	return iosSettings, nil
 }
 
+// SetIosAPIKey encrypts and stores the private key of the App Store Connect API
+// key, an empty key removes the stored one
+func (a *AppSettings) SetIosAPIKey(privateKey string) error {
+	if privateKey == "" {
+		a.EncryptedIosAPIKey, a.EncryptedIosAPIKeyIV = nil, nil
+		return nil
+	}
+	encryptKey, ok := os.LookupEnv("APP_SETTINGS_ENCRYPT_KEY")
+	if !ok {
+		return errors.New("No encrypt key provided")
+	}
+	iv, err := crypto.GenerateIV()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	encryptedKey, err := crypto.AES256GCMCipher(privateKey, iv, encryptKey)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	a.EncryptedIosAPIKey, a.EncryptedIosAPIKeyIV = encryptedKey, iv
+	return nil
+}
+
+// IosAPIKey returns the decrypted private key of the App Store Connect API key,
+// or an empty string if no key is stored
+func (a *AppSettings) IosAPIKey() (string, error) {
+	if len(a.EncryptedIosAPIKey) == 0 {
+		return "", nil
+	}
+	encryptKey, ok := os.LookupEnv("APP_SETTINGS_ENCRYPT_KEY")
+	if !ok {
+		return "", errors.New("No encrypt key provided")
+	}
+	privateKey, err := crypto.AES256GCMDecipher(a.EncryptedIosAPIKey, a.EncryptedIosAPIKeyIV, encryptKey)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return privateKey, nil
+}
+
+// CodeSigningExpiry ...
+func (a *AppSettings) CodeSigningExpiry() (CodeSigningExpiry, error) {
+	var codeSigningExpiry CodeSigningExpiry
+	if len(a.CodeSigningExpiryData) == 0 {
+		return codeSigningExpiry, nil
+	}
+	err := json.Unmarshal(a.CodeSigningExpiryData, &codeSigningExpiry)
+	if err != nil {
+		return CodeSigningExpiry{}, err
+	}
+	return codeSigningExpiry, nil
+}
+
+// SetCodeSigningExpiry ...
+func (a *AppSettings) SetCodeSigningExpiry(codeSigningExpiry CodeSigningExpiry) error {
+	codeSigningExpiryData, err := json.Marshal(codeSigningExpiry)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	a.CodeSigningExpiryData = codeSigningExpiryData
+	a.CodeSigningExpiresAt = codeSigningExpiry.ExpiresAt()
+	return nil
+}
+
+// RetentionPolicy ...
+func (a *AppSettings) RetentionPolicy() (RetentionPolicy, error) {
+	var retentionPolicy RetentionPolicy
+	if len(a.RetentionPolicyData) == 0 {
+		return retentionPolicy, nil
+	}
+	err := json.Unmarshal(a.RetentionPolicyData, &retentionPolicy)
+	if err != nil {
+		return RetentionPolicy{}, err
+	}
+	return retentionPolicy, nil
+}
+
+// SetRetentionPolicy ...
+func (a *AppSettings) SetRetentionPolicy(retentionPolicy RetentionPolicy) error {
+	retentionPolicyData, err := json.Marshal(retentionPolicy)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	a.RetentionPolicyData = retentionPolicyData
+	return nil
+}
+
+// VersionRules returns the version rules of the app. The platforms without a
+// workflow list fall back to the workflow whitelist of the platform.
+func (a *AppSettings) VersionRules() (AppVersionRules, error) {
+	var versionRules AppVersionRules
+	if len(a.VersionRulesData) > 0 {
+		if err := json.Unmarshal(a.VersionRulesData, &versionRules); err != nil {
+			return AppVersionRules{}, err
+		}
+	}
+	if versionRules.Ios.Workflows == nil {
+		versionRules.Ios.Workflows = workflowsFromWhitelist(a.IosWorkflow)
+	}
+	if versionRules.Android.Workflows == nil {
+		versionRules.Android.Workflows = workflowsFromWhitelist(a.AndroidWorkflow)
+	}
+	return versionRules, nil
+}
+
+// SetVersionRules ...
+func (a *AppSettings) SetVersionRules(versionRules AppVersionRules) error {
+	versionRulesData, err := json.Marshal(versionRules)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	a.VersionRulesData = versionRulesData
+	return nil
+}
+
+// AutoPublishRules ...
+func (a *AppSettings) AutoPublishRules() ([]AutoPublishRule, error) {
+	var autoPublishRules []AutoPublishRule
+	if len(a.AutoPublishRulesData) == 0 {
+		return autoPublishRules, nil
+	}
+	err := json.Unmarshal(a.AutoPublishRulesData, &autoPublishRules)
+	if err != nil {
+		return nil, err
+	}
+	return autoPublishRules, nil
+}
+
+// SetAutoPublishRules ...
+func (a *AppSettings) SetAutoPublishRules(autoPublishRules []AutoPublishRule) error {
+	autoPublishRulesData, err := json.Marshal(autoPublishRules)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	a.AutoPublishRulesData = autoPublishRulesData
+	return nil
+}
+
+// ApprovalRequirements ...
+func (a *AppSettings) ApprovalRequirements() ([]ApprovalRequirement, error) {
+	var approvalRequirements []ApprovalRequirement
+	if len(a.ApprovalRequirementsData) == 0 {
+		return approvalRequirements, nil
+	}
+	err := json.Unmarshal(a.ApprovalRequirementsData, &approvalRequirements)
+	if err != nil {
+		return nil, err
+	}
+	return approvalRequirements, nil
+}
+
+// SetApprovalRequirements ...
+func (a *AppSettings) SetApprovalRequirements(approvalRequirements []ApprovalRequirement) error {
+	approvalRequirementsData, err := json.Marshal(approvalRequirements)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	a.ApprovalRequirementsData = approvalRequirementsData
+	return nil
+}
+
 // AndroidSettings ...
 func (a *AppSettings) AndroidSettings() (AndroidSettings, error) {
 	var androidSettings AndroidSettings
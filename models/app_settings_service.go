@@ -1,6 +1,12 @@
 package models
 
-import "github.com/jinzhu/gorm"
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+const notDeprovisionedCondition = "app_id IN (SELECT id FROM apps WHERE deprovisioned_at IS NULL)"
 
 // AppSettingsService ...
 type AppSettingsService struct {
@@ -18,6 +24,37 @@ func (s *AppSettingsService) Find(appSettings *AppSettings) (*AppSettings, error
 	return appSettings, nil
 }
 
+// FindAllWithCodeSigningExpiringBefore returns the settings of the apps, which
+// have a selected code signing file expiring before the given time. The
+// deprovisioned apps are left out.
+func (s *AppSettingsService) FindAllWithCodeSigningExpiringBefore(expiresBefore time.Time) ([]AppSettings, error) {
+	var appSettings []AppSettings
+	err := s.DB.Preload("App").
+		Where("code_signing_expires_at < ?", expiresBefore).
+		Where(notDeprovisionedCondition).
+		Order("code_signing_expires_at ASC").
+		Find(&appSettings).Error
+	if err != nil {
+		return nil, err
+	}
+	return appSettings, nil
+}
+
+// FindAllWithRetentionPolicy returns the settings of the apps, which have an
+// enabled retention policy. The deprovisioned apps are left out.
+func (s *AppSettingsService) FindAllWithRetentionPolicy() ([]AppSettings, error) {
+	var appSettings []AppSettings
+	err := s.DB.Preload("App").
+		Where("COALESCE((retention_policy->>'keep_last')::int, 0) > 0 OR COALESCE((retention_policy->>'delete_unpublished_after_days')::int, 0) > 0").
+		Where(notDeprovisionedCondition).
+		Order("created_at ASC").
+		Find(&appSettings).Error
+	if err != nil {
+		return nil, err
+	}
+	return appSettings, nil
+}
+
 // Update ...
 func (s *AppSettingsService) Update(appSettings *AppSettings, whitelist []string) (validationErrors []error, dbErr error) {
 	updateData, err := s.UpdateData(*appSettings, whitelist)
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/x509"
+	"encoding/pem"
+	"regexp"
+
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+var apiKeyIDPattern = regexp.MustCompile(`^[A-Z0-9]{10}$`)
+
+// validateAPIKeyIssuerID ...
+func validateAPIKeyIssuerID(issuerID string) error {
+	if _, err := uuid.FromString(issuerID); err != nil {
+		return errors.New("api_key_issuer_id: Must be a UUID")
+	}
+	return nil
+}
+
+// validateAPIKeyID ...
+func validateAPIKeyID(keyID string) error {
+	if !apiKeyIDPattern.MatchString(keyID) {
+		return errors.New("api_key_id: Must be 10 uppercase letters or digits")
+	}
+	return nil
+}
+
+// validateAPIKeyPrivateKey checks that the private key is the content of an
+// App Store Connect API key file (.p8), which is a PKCS #8 encoded ECDSA key on
+// the P-256 curve, in PEM format
+func validateAPIKeyPrivateKey(privateKey string) error {
+	invalidErr := errors.New("api_key_private_key: Must be the content of an App Store Connect API key file (.p8)")
+	block, _ := pem.Decode([]byte(privateKey))
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return invalidErr
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return invalidErr
+	}
+	ecdsaKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok || ecdsaKey.Curve != elliptic.P256() {
+		return invalidErr
+	}
+	return nil
+}
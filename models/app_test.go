@@ -8,6 +8,8 @@ import (
 	"github.com/bitrise-io/go-crypto/crypto"
 	"github.com/bitrise-io/go-utils/envutil"
 	"github.com/c2fo/testify/require"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
 )
 
 func Test_App_Secret(t *testing.T) {
@@ -50,3 +52,21 @@ func Test_App_Secret(t *testing.T) {
 		require.NoError(t, revokeFn())
 	})
 }
+
+func Test_App_AndroidServiceAccountErrors(t *testing.T) {
+	t.Run("when service account errors are replaced", func(t *testing.T) {
+		testApp := models.App{AndroidErrors: []string{"Multiple modules found, but none was selected in the settings", "Service account file: Invalid JSON"}}
+		require.Equal(t, []string{"Service account file: Invalid JSON"}, testApp.AndroidServiceAccountErrors())
+
+		testApp.SetAndroidServiceAccountErrors([]error{errors.New("Service account file: project_id cannot be empty")})
+		require.Equal(t, pq.StringArray{"Multiple modules found, but none was selected in the settings", "Service account file: project_id cannot be empty"}, testApp.AndroidErrors)
+		require.Equal(t, []string{"Service account file: project_id cannot be empty"}, testApp.AndroidServiceAccountErrors())
+	})
+
+	t.Run("when service account errors are cleared", func(t *testing.T) {
+		testApp := models.App{AndroidErrors: []string{"Service account file: Invalid JSON"}}
+		testApp.SetAndroidServiceAccountErrors(nil)
+		require.Equal(t, pq.StringArray{}, testApp.AndroidErrors)
+		require.Equal(t, []string{}, testApp.AndroidServiceAccountErrors())
+	})
+}
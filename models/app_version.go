@@ -7,6 +7,28 @@ import (
 	"github.com/jinzhu/gorm"
 	"github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
+	"github.com/thoas/go-funk"
+)
+
+// ApplePlatforms are the platforms of the versions built from Xcode archives,
+// all of them are published to App Store Connect
+var ApplePlatforms = []string{"ios", "tvos", "watchos", "macos"}
+
+const (
+	// AppVersionStatusDraft is the status of the versions whose build has no
+	// artifact which could be published
+	AppVersionStatusDraft = "draft"
+	// AppVersionStatusReady ...
+	AppVersionStatusReady = "ready"
+	// AppVersionStatusPublishing ...
+	AppVersionStatusPublishing = "publishing"
+	// AppVersionStatusPublished ...
+	AppVersionStatusPublished = "published"
+	// AppVersionStatusFailed ...
+	AppVersionStatusFailed = "failed"
+	// AppVersionStatusSuperseded is the status of the versions which weren't
+	// published before a newer version of the same platform and flavor was built
+	AppVersionStatusSuperseded = "superseded"
 )
 
 // ArtifactInfo ...
@@ -39,16 +61,30 @@ type AppStoreInfo struct {
 // AppVersion ...
 type AppVersion struct {
 	Record
-	Platform         string          `json:"platform"`
-	BuildNumber      string          `json:"build_number"`
-	BuildSlug        string          `json:"build_slug"`
-	LastUpdate       time.Time       `json:"last_update"`
-	Scheme           string          `json:"scheme"`
-	Configuration    string          `json:"configuration"`
-	CommitMessage    string          `json:"commit_message"`
-	ProductFlavor    string          `json:"product_flavor"`
+	Platform      string    `json:"platform"`
+	BuildNumber   string    `json:"build_number"`
+	BuildSlug     string    `json:"build_slug"`
+	LastUpdate    time.Time `json:"last_update"`
+	Scheme        string    `json:"scheme"`
+	Configuration string    `json:"configuration"`
+	CommitMessage string    `json:"commit_message"`
+	ProductFlavor string    `json:"product_flavor"`
+	// BundleID is only set for iOS versions, the versions of the same bundle
+	// ID follow each other, like the versions of the same product flavor
+	BundleID         string          `json:"bundle_id"`
 	ArtifactInfoData json.RawMessage `json:"-" db:"artifact_info" gorm:"column:artifact_info;type:json"`
 	AppStoreInfoData json.RawMessage `json:"-" db:"app_store_info" gorm:"column:app_store_info;type:json"`
+	// IosReleaseOptionsData is only set for iOS versions, see IosReleaseOptions
+	IosReleaseOptionsData json.RawMessage `json:"-" db:"ios_release_options" gorm:"column:ios_release_options;type:json"`
+
+	// ReleaseID is the release the version is released together with, see
+	// Release
+	ReleaseID *uuid.UUID `db:"release_id" json:"release_id,omitempty"`
+	// Status is the lifecycle status of the version, see the AppVersionStatus
+	// constants
+	Status string `json:"status"`
+	// ArchivedAt is set for the versions hidden from the version list
+	ArchivedAt *time.Time `db:"archived_at" json:"archived_at,omitempty"`
 
 	AppID uuid.UUID `db:"app_id" json:"-"`
 	App   App       `gorm:"foreignkey:AppID" json:"-"`
@@ -65,6 +101,12 @@ func (a *AppVersion) BeforeCreate(scope *gorm.Scope) error {
 	if a.ArtifactInfoData == nil {
 		a.ArtifactInfoData = json.RawMessage(`{}`)
 	}
+	if a.IosReleaseOptionsData == nil {
+		a.IosReleaseOptionsData = json.RawMessage(`{}`)
+	}
+	if a.Status == "" {
+		a.Status = AppVersionStatusDraft
+	}
 	err := a.validate(scope)
 	if err != nil {
 		return errors.WithStack(err)
@@ -82,15 +124,106 @@ func (a *AppVersion) BeforeUpdate(scope *gorm.Scope) error {
 }
 
 func (a *AppVersion) validate(scope *gorm.Scope) error {
-	artifactInfo, err := a.ArtifactInfo()
+	verrs, err := a.Validate()
 	if err != nil {
 		return errors.WithStack(err)
 	}
-	err = artifactInfo.validate(scope)
+	return addValidationErrors(scope, verrs)
+}
+
+// Validate ...
+func (a *AppVersion) Validate() ([]error, error) {
+	artifactInfo, err := a.ArtifactInfo()
 	if err != nil {
-		return errors.New("Validation failed")
+		return nil, err
 	}
-	return nil
+	verrs := artifactInfo.validate()
+
+	releaseOptions, err := a.IosReleaseOptions()
+	if err != nil {
+		return nil, err
+	}
+	if !releaseOptions.Empty() && !a.IsApplePlatform() {
+		verrs = append(verrs, errors.New("ios_release_options: Can only be set for iOS versions"))
+	}
+	for _, verr := range releaseOptions.Validate() {
+		verrs = append(verrs, errors.New("ios_release_options."+verr.Error()))
+	}
+	return verrs, nil
+}
+
+// IsApplePlatform ...
+func (a *AppVersion) IsApplePlatform() bool {
+	return funk.ContainsString(ApplePlatforms, a.Platform)
+}
+
+// Supersedable tells whether the version gets superseded by a newer version,
+// which is the case until its publishing is started
+func (a *AppVersion) Supersedable() bool {
+	return funk.ContainsString([]string{AppVersionStatusDraft, AppVersionStatusReady, AppVersionStatusFailed}, a.Status)
+}
+
+// Publishable tells whether the version can be published by its status: its
+// build has an artifact which can be published, and it isn't being published,
+// nor superseded by a newer version
+func (a *AppVersion) Publishable() bool {
+	return funk.ContainsString([]string{AppVersionStatusReady, AppVersionStatusPublished, AppVersionStatusFailed}, a.Status)
+}
+
+// BuildCode returns the number the stores tell the builds of a version apart
+// by, which has to grow from one published build to the next: the version code
+// of Android versions, and the build number of the versions of Apple platforms
+func (a *AppVersion) BuildCode() (string, error) {
+	if a.Platform != "android" {
+		return a.BuildNumber, nil
+	}
+	artifactInfo, err := a.ArtifactInfo()
+	if err != nil {
+		return "", err
+	}
+	return artifactInfo.VersionCode, nil
+}
+
+// CompareAppVersions orders app versions by their version, then by their build
+// code, see VersionNumber.Compare. Versions and build codes which can't be
+// parsed are taken as the lowest ones.
+func CompareAppVersions(a, b *AppVersion) int {
+	version, buildCode := a.versionNumbers()
+	otherVersion, otherBuildCode := b.versionNumbers()
+	if result := version.Compare(otherVersion); result != 0 {
+		return result
+	}
+	return buildCode.Compare(otherBuildCode)
+}
+
+// HighestBuildCode returns the app version with the greatest build code, the
+// first one of them when more versions have the same build code. Build codes
+// which can't be parsed are taken as the lowest ones.
+func HighestBuildCode(appVersions []AppVersion) *AppVersion {
+	var highest *AppVersion
+	for i := range appVersions {
+		if highest == nil {
+			highest = &appVersions[i]
+			continue
+		}
+		_, buildCode := appVersions[i].versionNumbers()
+		_, highestBuildCode := highest.versionNumbers()
+		if buildCode.Compare(highestBuildCode) > 0 {
+			highest = &appVersions[i]
+		}
+	}
+	return highest
+}
+
+func (a *AppVersion) versionNumbers() (VersionNumber, VersionNumber) {
+	var version, buildCode VersionNumber
+	if artifactInfo, err := a.ArtifactInfo(); err == nil {
+		version, _ = ParseVersionNumber(artifactInfo.Version)
+	}
+	if code, err := a.BuildCode(); err == nil {
+		buildCode, _ = ParseVersionNumber(code)
+	}
+	return version, buildCode
 }
 
 // AppStoreInfo ...
@@ -103,6 +236,19 @@ func (a *AppVersion) AppStoreInfo() (AppStoreInfo, error) {
 	return appStoreInfo, nil
 }
 
+// IosReleaseOptions ...
+func (a *AppVersion) IosReleaseOptions() (IosReleaseOptions, error) {
+	var releaseOptions IosReleaseOptions
+	if len(a.IosReleaseOptionsData) == 0 {
+		return releaseOptions, nil
+	}
+	err := json.Unmarshal(a.IosReleaseOptionsData, &releaseOptions)
+	if err != nil {
+		return IosReleaseOptions{}, err
+	}
+	return releaseOptions, nil
+}
+
 // ArtifactInfo ...
 func (a *AppVersion) ArtifactInfo() (ArtifactInfo, error) {
 	var artifactInfo ArtifactInfo
@@ -113,9 +259,10 @@ func (a *AppVersion) ArtifactInfo() (ArtifactInfo, error) {
 	return artifactInfo, nil
 }
 
-func (a *ArtifactInfo) validate(scope *gorm.Scope) error {
+func (a *ArtifactInfo) validate() []error {
+	verrs := []error{}
 	if a.Version == "" {
-		return scope.DB().AddError(NewValidationError("version: Cannot be empty"))
+		verrs = append(verrs, errors.New("version: Cannot be empty"))
 	}
-	return nil
+	return verrs
 }
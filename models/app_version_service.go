@@ -66,3 +66,38 @@ func (a *AppVersionService) Latest(appVersion *AppVersion) (*AppVersion, error)
 	}
 	return appVersion, nil
 }
+
+// LatestPublished returns the other version of the same app, platform, product
+// flavor and bundle ID with the greatest build code among the ones which were
+// published successfully, as the stores reject every build code which isn't
+// greater than it
+func (a *AppVersionService) LatestPublished(appVersion *AppVersion) (*AppVersion, error) {
+	var publishedVersions []AppVersion
+	err := a.DB.Preload("App").
+		Where("app_versions.app_id = ? AND app_versions.platform = ? AND COALESCE(app_versions.product_flavor, '') = ? AND app_versions.bundle_id = ?",
+			appVersion.AppID, appVersion.Platform, appVersion.ProductFlavor, appVersion.BundleID).
+		Where("app_versions.id <> ?", appVersion.ID).
+		Where("EXISTS (SELECT 1 FROM publish_tasks WHERE publish_tasks.app_version_id = app_versions.id AND publish_tasks.status = ?)", PublishTaskStatusSuccess).
+		Order("app_versions.created_at DESC").
+		Find(&publishedVersions).Error
+	if err != nil {
+		return nil, err
+	}
+	latestPublished := HighestBuildCode(publishedVersions)
+	if latestPublished == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return latestPublished, nil
+}
+
+// Delete ...
+func (a *AppVersionService) Delete(appVersion *AppVersion) error {
+	result := a.DB.Delete(appVersion)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected < 1 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
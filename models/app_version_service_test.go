@@ -12,6 +12,7 @@ import (
 	"github.com/bitrise-io/addons-ship-backend/models"
 	"github.com/c2fo/testify/require"
 	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
 )
 
 func compareAppVersion(t *testing.T, expected, actual models.AppVersion) {
@@ -270,3 +271,34 @@ func Test_AppVersionService_Latest(t *testing.T) {
 		require.Nil(t, foundAppVersion)
 	})
 }
+
+func Test_AppVersionService_LatestPublished(t *testing.T) {
+	dbCloseCallbackMethod := prepareDB(t)
+	defer dbCloseCallbackMethod()
+
+	appVersionService := models.AppVersionService{DB: dataservices.GetDB()}
+	testApp := createTestApp(t, &models.App{})
+
+	t.Run("when the product flavor of the published version is NULL", func(t *testing.T) {
+		publishedVersion := createTestAppVersion(t, &models.AppVersion{
+			App:              *testApp,
+			Platform:         "android",
+			ArtifactInfoData: json.RawMessage(`{"version":"1.0","version_code":"12"}`),
+		})
+		require.NoError(t, dataservices.GetDB().Exec("UPDATE app_versions SET product_flavor = NULL WHERE id = ?", publishedVersion.ID).Error)
+		createTestPublishTask(t, &models.PublishTask{
+			TaskID:       uuid.NewV4(),
+			AppVersionID: publishedVersion.ID,
+			Status:       models.PublishTaskStatusSuccess,
+		})
+		newVersion := createTestAppVersion(t, &models.AppVersion{
+			App:              *testApp,
+			Platform:         "android",
+			ArtifactInfoData: json.RawMessage(`{"version":"1.0","version_code":"13"}`),
+		})
+
+		latestPublished, err := appVersionService.LatestPublished(newVersion)
+		require.NoError(t, err)
+		require.Equal(t, publishedVersion.ID, latestPublished.ID)
+	})
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/bitrise-io/addons-ship-backend/models"
 	"github.com/c2fo/testify/require"
+	uuid "github.com/satori/go.uuid"
 )
 
 func Test_AppVersion_AppStoreInfo(t *testing.T) {
@@ -39,3 +40,143 @@ func Test_AppVersion_ArtifactInfo(t *testing.T) {
 		require.Equal(t, models.ArtifactInfo{}, artifactInfo)
 	})
 }
+
+func Test_AppVersion_Validate(t *testing.T) {
+	t.Run("ok - ios release options", func(t *testing.T) {
+		testAppVersion := &models.AppVersion{
+			Platform:              "ios",
+			ArtifactInfoData:      json.RawMessage(`{"version":"1.0"}`),
+			IosReleaseOptionsData: json.RawMessage(`{"release_type":"after_approval","phased_release":true}`),
+		}
+		verrs, err := testAppVersion.Validate()
+		require.NoError(t, err)
+		require.Empty(t, verrs)
+	})
+
+	t.Run("when ios release options are invalid", func(t *testing.T) {
+		testAppVersion := &models.AppVersion{
+			Platform:              "ios",
+			ArtifactInfoData:      json.RawMessage(`{"version":"1.0"}`),
+			IosReleaseOptionsData: json.RawMessage(`{"release_type":"scheduled"}`),
+		}
+		verrs, err := testAppVersion.Validate()
+		require.NoError(t, err)
+		require.Len(t, verrs, 1)
+		require.EqualError(t, verrs[0], "ios_release_options.scheduled_release_date: Cannot be empty for scheduled releases")
+	})
+
+	t.Run("when ios release options are set for an android version", func(t *testing.T) {
+		testAppVersion := &models.AppVersion{
+			Platform:              "android",
+			ArtifactInfoData:      json.RawMessage(`{"version":"1.0"}`),
+			IosReleaseOptionsData: json.RawMessage(`{"release_type":"manual"}`),
+		}
+		verrs, err := testAppVersion.Validate()
+		require.NoError(t, err)
+		require.Len(t, verrs, 1)
+		require.EqualError(t, verrs[0], "ios_release_options: Can only be set for iOS versions")
+	})
+}
+
+func Test_AppVersion_IosReleaseOptions(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		testAppVersion := &models.AppVersion{IosReleaseOptionsData: json.RawMessage(`{"release_type":"manual","reset_ratings":true}`)}
+		releaseOptions, err := testAppVersion.IosReleaseOptions()
+		require.NoError(t, err)
+		require.Equal(t, models.IosReleaseOptions{ReleaseType: "manual", ResetRatings: true}, releaseOptions)
+	})
+
+	t.Run("when release options are not set", func(t *testing.T) {
+		testAppVersion := &models.AppVersion{}
+		releaseOptions, err := testAppVersion.IosReleaseOptions()
+		require.NoError(t, err)
+		require.Equal(t, models.IosReleaseOptions{}, releaseOptions)
+	})
+}
+
+func Test_AppVersion_IsApplePlatform(t *testing.T) {
+	for _, platform := range []string{"ios", "tvos", "watchos", "macos"} {
+		require.True(t, (&models.AppVersion{Platform: platform}).IsApplePlatform(), platform)
+	}
+	require.False(t, (&models.AppVersion{Platform: "android"}).IsApplePlatform())
+	require.False(t, (&models.AppVersion{}).IsApplePlatform())
+}
+
+func Test_AppVersion_BuildCode(t *testing.T) {
+	t.Run("when it's an android version", func(t *testing.T) {
+		testAppVersion := &models.AppVersion{Platform: "android", BuildNumber: "123", ArtifactInfoData: json.RawMessage(`{"version_code":"42"}`)}
+		buildCode, err := testAppVersion.BuildCode()
+		require.NoError(t, err)
+		require.Equal(t, "42", buildCode)
+	})
+
+	t.Run("when it's an ios version", func(t *testing.T) {
+		testAppVersion := &models.AppVersion{Platform: "ios", BuildNumber: "123", ArtifactInfoData: json.RawMessage(`{"version_code":"42"}`)}
+		buildCode, err := testAppVersion.BuildCode()
+		require.NoError(t, err)
+		require.Equal(t, "123", buildCode)
+	})
+
+	t.Run("error unmarshaling artifact info", func(t *testing.T) {
+		testAppVersion := &models.AppVersion{Platform: "android"}
+		_, err := testAppVersion.BuildCode()
+		require.EqualError(t, err, "unexpected end of JSON input")
+	})
+}
+
+func Test_CompareAppVersions(t *testing.T) {
+	newVersion := func(version, buildNumber string) *models.AppVersion {
+		return &models.AppVersion{Platform: "ios", BuildNumber: buildNumber, ArtifactInfoData: json.RawMessage(`{"version":"` + version + `"}`)}
+	}
+
+	require.Equal(t, 1, models.CompareAppVersions(newVersion("1.10.0", "1"), newVersion("1.9.0", "20")))
+	require.Equal(t, -1, models.CompareAppVersions(newVersion("1.0.0-beta", "2"), newVersion("1.0.0", "1")))
+	require.Equal(t, -1, models.CompareAppVersions(newVersion("1.0.0", "9"), newVersion("1.0.0", "10")))
+	require.Equal(t, 0, models.CompareAppVersions(newVersion("1.0", "10"), newVersion("1.0.0", "10")))
+	require.Equal(t, -1, models.CompareAppVersions(newVersion("invalid", "10"), newVersion("0.0.1", "1")))
+}
+
+func Test_HighestBuildCode(t *testing.T) {
+	newVersion := func(id, versionCode string) models.AppVersion {
+		return models.AppVersion{
+			Record:           models.Record{ID: uuid.FromStringOrNil(id)},
+			Platform:         "android",
+			ArtifactInfoData: json.RawMessage(`{"version":"1.0.0","version_code":"` + versionCode + `"}`),
+		}
+	}
+	appVersions := []models.AppVersion{
+		newVersion("de438ddc-98e5-4226-a5f4-fd2d53474879", "9"),
+		newVersion("4e2e0e38-4b5d-4d2e-9e0e-6bc7f4d4ab5e", "10"),
+		newVersion("8d4f2b5e-2d3c-4c8a-9a44-1f9c3c2c5f10", "invalid"),
+		newVersion("0c7d5a8e-7f1b-4b43-8f4e-54ad2c7ee0a2", "10"),
+	}
+
+	require.Equal(t, appVersions[1].ID, models.HighestBuildCode(appVersions).ID)
+	require.Nil(t, models.HighestBuildCode([]models.AppVersion{}))
+}
+
+func Test_AppVersion_Supersedable(t *testing.T) {
+	for status, supersedable := range map[string]bool{
+		"draft":      true,
+		"ready":      true,
+		"failed":     true,
+		"publishing": false,
+		"published":  false,
+		"superseded": false,
+	} {
+		require.Equal(t, supersedable, (&models.AppVersion{Status: status}).Supersedable(), status)
+	}
+}
+
+func Test_AppVersion_Publishable(t *testing.T) {
+	for status, publishable := range map[string]bool{
+		"draft":      false,
+		"ready":      true,
+		"failed":     true,
+		"publishing": false,
+		"published":  true,
+		"superseded": false,
+	} {
+		require.Equal(t, publishable, (&models.AppVersion{Status: status}).Publishable(), status)
+	}
+}
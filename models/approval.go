@@ -0,0 +1,49 @@
+package models
+
+import uuid "github.com/satori/go.uuid"
+
+const (
+	// ApprovalStatusRequested is the status of the approvals requested from an
+	// app contact, who hasn't approved the version yet
+	ApprovalStatusRequested = "requested"
+	// ApprovalStatusApproved ...
+	ApprovalStatusApproved = "approved"
+	// ApprovalStatusRevoked ...
+	ApprovalStatusRevoked = "revoked"
+	// ApprovalStatusInvalidated is the status of the approvals given before the
+	// store info of the version got edited
+	ApprovalStatusInvalidated = "invalidated"
+)
+
+// Approval is the sign-off of an app contact on publishing an app version,
+// see ApprovalRequirement. Approvals aren't deleted when they are revoked or
+// invalidated, so that they keep the history of the sign-offs. The token
+// authenticates the approver, it's sent to them in the approval request, and
+// it's moved to their latest approval of the version.
+type Approval struct {
+	Record
+	Approver      string  `json:"approver"`
+	Comment       string  `json:"comment"`
+	Status        string  `json:"status"`
+	RevokeComment string  `db:"revoke_comment" json:"revoke_comment,omitempty"`
+	Token         *string `db:"token" json:"-"`
+
+	AppVersionID uuid.UUID  `db:"app_version_id" json:"-"`
+	AppVersion   AppVersion `gorm:"foreignkey:AppVersionID" json:"-"`
+}
+
+// BeforeCreate ...
+func (a *Approval) BeforeCreate() error {
+	if uuid.Equal(a.ID, uuid.UUID{}) {
+		a.ID = uuid.NewV4()
+	}
+	if a.Status == "" {
+		a.Status = ApprovalStatusApproved
+	}
+	return nil
+}
+
+// Valid ...
+func (a *Approval) Valid() bool {
+	return a.Status == ApprovalStatusApproved
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ApprovalRequirement is the number of approvals an app version needs before
+// it can be published to a destination. Destination is the ID of the publish
+// destination, an empty one requires the approvals for every destination.
+// Track limits the requirement to a track of the destination, e.g. to the
+// production track of Google Play, so that publishing or promoting to the other
+// tracks doesn't need the approvals. Approvers are the emails of the app
+// contacts whose approvals count, any contact of the app can approve when it's
+// empty.
+type ApprovalRequirement struct {
+	Destination string   `json:"destination"`
+	Track       string   `json:"track,omitempty"`
+	Approvals   int      `json:"approvals"`
+	Approvers   []string `json:"approvers"`
+}
+
+// AppliesTo ...
+func (r ApprovalRequirement) AppliesTo(destinationID, track string) bool {
+	return (r.Destination == "" || r.Destination == destinationID) && (r.Track == "" || r.Track == track)
+}
+
+// CountsApprover tells whether the approvals of the approver count towards the
+// requirement
+func (r ApprovalRequirement) CountsApprover(approver string) bool {
+	if len(r.Approvers) == 0 {
+		return true
+	}
+	for _, email := range r.Approvers {
+		if strings.EqualFold(strings.TrimSpace(email), strings.TrimSpace(approver)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the requirement without its destination and track, as the
+// publish destinations are registered by the services
+func (r ApprovalRequirement) Validate() []error {
+	verrs := []error{}
+	if r.Approvals < 1 {
+		verrs = append(verrs, errors.New("approval_requirements.approvals: Must be at least 1"))
+	}
+	if r.Track != "" && r.Destination == "" {
+		verrs = append(verrs, errors.New("approval_requirements.track: Requires a destination"))
+	}
+	if len(r.Approvers) > 0 && r.Approvals > len(r.Approvers) {
+		verrs = append(verrs, errors.New("approval_requirements.approvals: Cannot be more than the number of approvers"))
+	}
+	for _, approver := range r.Approvers {
+		if strings.TrimSpace(approver) == "" {
+			verrs = append(verrs, errors.New("approval_requirements.approvers: Cannot contain an empty email"))
+			break
+		}
+	}
+	return verrs
+}
@@ -0,0 +1,60 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/c2fo/testify/require"
+	"github.com/pkg/errors"
+)
+
+func Test_ApprovalRequirement_AppliesTo(t *testing.T) {
+	require.True(t, models.ApprovalRequirement{Approvals: 1}.AppliesTo("google-play", "beta"))
+	require.True(t, models.ApprovalRequirement{Destination: "google-play", Approvals: 1}.AppliesTo("google-play", "beta"))
+	require.False(t, models.ApprovalRequirement{Destination: "app-store-connect", Approvals: 1}.AppliesTo("google-play", "beta"))
+
+	t.Run("when track is set", func(t *testing.T) {
+		approvalRequirement := models.ApprovalRequirement{Destination: "google-play", Track: "production", Approvals: 1}
+		require.True(t, approvalRequirement.AppliesTo("google-play", "production"))
+		require.False(t, approvalRequirement.AppliesTo("google-play", "beta"))
+	})
+}
+
+func Test_ApprovalRequirement_CountsApprover(t *testing.T) {
+	t.Run("when approvers are not set", func(t *testing.T) {
+		require.True(t, models.ApprovalRequirement{Approvals: 1}.CountsApprover("qa@bitrise.io"))
+	})
+
+	t.Run("when approvers are set", func(t *testing.T) {
+		approvalRequirement := models.ApprovalRequirement{Approvals: 1, Approvers: []string{"QA@bitrise.io"}}
+		require.True(t, approvalRequirement.CountsApprover("qa@bitrise.io"))
+		require.False(t, approvalRequirement.CountsApprover("dev@bitrise.io"))
+	})
+}
+
+func Test_ApprovalRequirement_Validate(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		approvalRequirement := models.ApprovalRequirement{Destination: "google-play", Approvals: 2, Approvers: []string{"qa@bitrise.io", "product@bitrise.io"}}
+		require.Empty(t, approvalRequirement.Validate())
+	})
+
+	t.Run("when values are invalid", func(t *testing.T) {
+		approvalRequirement := models.ApprovalRequirement{Approvals: 3, Approvers: []string{"qa@bitrise.io", " "}}
+		require.Equal(t, []error{
+			errors.New("approval_requirements.approvals: Cannot be more than the number of approvers"),
+			errors.New("approval_requirements.approvers: Cannot contain an empty email"),
+		}, approvalRequirement.Validate())
+	})
+
+	t.Run("when track is set without destination", func(t *testing.T) {
+		require.Equal(t, []error{
+			errors.New("approval_requirements.track: Requires a destination"),
+		}, models.ApprovalRequirement{Track: "production", Approvals: 1}.Validate())
+	})
+
+	t.Run("when no approvals are required", func(t *testing.T) {
+		require.Equal(t, []error{
+			errors.New("approval_requirements.approvals: Must be at least 1"),
+		}, models.ApprovalRequirement{}.Validate())
+	})
+}
@@ -0,0 +1,60 @@
+package models
+
+import "github.com/jinzhu/gorm"
+
+// ApprovalService ...
+type ApprovalService struct {
+	DB *gorm.DB
+	UpdatableModelService
+}
+
+// Create ...
+func (a *ApprovalService) Create(approval *Approval) (*Approval, error) {
+	result := a.DB.Create(approval)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return approval, nil
+}
+
+// Find ...
+func (a *ApprovalService) Find(approval *Approval) (*Approval, error) {
+	err := a.DB.Where(approval).First(approval).Error
+	if err != nil {
+		return nil, err
+	}
+	return approval, nil
+}
+
+// FindAll returns the approvals of an app version, the earliest first
+func (a *ApprovalService) FindAll(appVersion *AppVersion) ([]Approval, error) {
+	var approvals []Approval
+	err := a.DB.Where(map[string]interface{}{"app_version_id": appVersion.ID}).
+		Order("created_at ASC").
+		Find(&approvals).Error
+	if err != nil {
+		return nil, err
+	}
+	return approvals, nil
+}
+
+// Update ...
+func (a *ApprovalService) Update(approval *Approval, whitelist []string) error {
+	updateData, err := a.UpdateData(*approval, whitelist)
+	if err != nil {
+		return err
+	}
+	return a.DB.Model(approval).Updates(updateData).Error
+}
+
+// InvalidateAll invalidates the valid approvals of an app version, and returns
+// the number of the invalidated ones
+func (a *ApprovalService) InvalidateAll(appVersion *AppVersion) (int, error) {
+	result := a.DB.Model(&Approval{}).
+		Where(map[string]interface{}{"app_version_id": appVersion.ID, "status": ApprovalStatusApproved}).
+		Updates(map[string]interface{}{"status": ApprovalStatusInvalidated})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return int(result.RowsAffected), nil
+}
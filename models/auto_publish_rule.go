@@ -0,0 +1,52 @@
+package models
+
+import (
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/thoas/go-funk"
+)
+
+// AutoPublishRule publishes the app versions of a platform which get created
+// from the matching builds. Workflow and Branch are glob patterns, an empty one
+// matches every build. Destination is the ID of the publish destination, the
+// default destination of the platform is used when it's empty.
+type AutoPublishRule struct {
+	Name        string `json:"name"`
+	Platform    string `json:"platform"`
+	Workflow    string `json:"workflow"`
+	Branch      string `json:"branch"`
+	Destination string `json:"destination"`
+	Track       string `json:"track"`
+}
+
+// Matches ...
+func (r AutoPublishRule) Matches(appVersion *AppVersion, workflow, branch string) bool {
+	if r.Platform != appVersion.Platform {
+		return false
+	}
+	if r.Workflow != "" && !matchesPattern(r.Workflow, workflow) {
+		return false
+	}
+	return r.Branch == "" || matchesPattern(r.Branch, branch)
+}
+
+// Validate checks the rule without its destination, as the publish
+// destinations are registered by the services
+func (r AutoPublishRule) Validate() []error {
+	verrs := []error{}
+	if strings.TrimSpace(r.Name) == "" {
+		verrs = append(verrs, errors.New("auto_publish_rules.name: Cannot be empty"))
+	}
+	if r.Platform != "android" && !funk.ContainsString(ApplePlatforms, r.Platform) {
+		verrs = append(verrs, errors.Errorf("auto_publish_rules.platform: Must be one of android, %s", strings.Join(ApplePlatforms, ", ")))
+	}
+	if _, err := path.Match(r.Workflow, ""); err != nil {
+		verrs = append(verrs, errors.Errorf("auto_publish_rules.workflow: Invalid pattern %s", r.Workflow))
+	}
+	if _, err := path.Match(r.Branch, ""); err != nil {
+		verrs = append(verrs, errors.Errorf("auto_publish_rules.branch: Invalid pattern %s", r.Branch))
+	}
+	return verrs
+}
@@ -0,0 +1,46 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/c2fo/testify/require"
+	"github.com/pkg/errors"
+)
+
+func Test_AutoPublishRule_Matches(t *testing.T) {
+	appVersion := &models.AppVersion{Platform: "android"}
+	for _, tc := range []struct {
+		name             string
+		autoPublishRule  models.AutoPublishRule
+		workflow, branch string
+		expected         bool
+	}{
+		{name: "when only the platform is set", autoPublishRule: models.AutoPublishRule{Platform: "android"}, workflow: "primary", branch: "feature", expected: true},
+		{name: "when the platform differs", autoPublishRule: models.AutoPublishRule{Platform: "ios"}, workflow: "primary", expected: false},
+		{name: "workflow and branch", autoPublishRule: models.AutoPublishRule{Platform: "android", Workflow: "deploy-*", Branch: "main"}, workflow: "deploy-play", branch: "main", expected: true},
+		{name: "when the workflow differs", autoPublishRule: models.AutoPublishRule{Platform: "android", Workflow: "deploy"}, workflow: "deploy-play", branch: "main", expected: false},
+		{name: "when the branch differs", autoPublishRule: models.AutoPublishRule{Platform: "android", Branch: "main"}, workflow: "deploy", branch: "feature/main", expected: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.autoPublishRule.Matches(appVersion, tc.workflow, tc.branch))
+		})
+	}
+}
+
+func Test_AutoPublishRule_Validate(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		autoPublishRule := models.AutoPublishRule{Name: "Internal", Platform: "android", Branch: "main", Destination: "google-play", Track: "internal"}
+		require.Empty(t, autoPublishRule.Validate())
+	})
+
+	t.Run("when values are invalid", func(t *testing.T) {
+		autoPublishRule := models.AutoPublishRule{Platform: "windows", Workflow: "deploy-[", Branch: "release/["}
+		require.Equal(t, []error{
+			errors.New("auto_publish_rules.name: Cannot be empty"),
+			errors.New("auto_publish_rules.platform: Must be one of android, ios, tvos, watchos, macos"),
+			errors.New("auto_publish_rules.workflow: Invalid pattern deploy-["),
+			errors.New("auto_publish_rules.branch: Invalid pattern release/["),
+		}, autoPublishRule.Validate())
+	})
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+)
+
+// CodeSigningExpiryReminderDays are the number of days before the expiry of a
+// code signing file, when the contacts of the app get reminded
+var CodeSigningExpiryReminderDays = []int{30, 7, 1}
+
+// CodeSigningFileExpiry ...
+type CodeSigningFileExpiry struct {
+	Slug      string    `json:"slug"`
+	Filename  string    `json:"filename"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// DaysLeft returns the number of days left until the expiry of the file,
+// counted in calendar days
+func (e CodeSigningFileExpiry) DaysLeft(now time.Time) int {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	expiresAt := e.ExpiresAt.In(now.Location())
+	expiryDay := time.Date(expiresAt.Year(), expiresAt.Month(), expiresAt.Day(), 0, 0, 0, 0, time.UTC)
+	return int(expiryDay.Sub(today).Hours() / 24)
+}
+
+// CodeSigningExpiry is the expiry of the code signing files selected in the
+// iOS settings
+type CodeSigningExpiry struct {
+	ProvisioningProfiles    []CodeSigningFileExpiry `json:"provisioning_profiles"`
+	DistributionCertificate *CodeSigningFileExpiry  `json:"distribution_certificate"`
+}
+
+// Empty ...
+func (e CodeSigningExpiry) Empty() bool {
+	return len(e.ProvisioningProfiles) == 0 && e.DistributionCertificate == nil
+}
+
+// Files returns all the code signing files with an expiry
+func (e CodeSigningExpiry) Files() []CodeSigningFileExpiry {
+	files := append([]CodeSigningFileExpiry{}, e.ProvisioningProfiles...)
+	if e.DistributionCertificate != nil {
+		files = append(files, *e.DistributionCertificate)
+	}
+	return files
+}
+
+// ExpiresAt returns the earliest expiry of the code signing files, or nil if
+// there's no file with an expiry
+func (e CodeSigningExpiry) ExpiresAt() *time.Time {
+	var expiresAt *time.Time
+	for _, file := range e.Files() {
+		if expiresAt == nil || file.ExpiresAt.Before(*expiresAt) {
+			fileExpiresAt := file.ExpiresAt
+			expiresAt = &fileExpiresAt
+		}
+	}
+	return expiresAt
+}
+
+// FilesToRemind returns the files, which have as many days left until their
+// expiry as one of the reminder days
+func (e CodeSigningExpiry) FilesToRemind(now time.Time) []CodeSigningFileExpiry {
+	files := []CodeSigningFileExpiry{}
+	for _, file := range e.Files() {
+		daysLeft := file.DaysLeft(now)
+		for _, reminderDays := range CodeSigningExpiryReminderDays {
+			if daysLeft == reminderDays {
+				files = append(files, file)
+			}
+		}
+	}
+	return files
+}
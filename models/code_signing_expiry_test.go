@@ -0,0 +1,70 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/c2fo/testify/require"
+)
+
+func Test_CodeSigningFileExpiry_DaysLeft(t *testing.T) {
+	now := time.Date(2019, 11, 14, 22, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name      string
+		expiresAt time.Time
+		expected  int
+	}{
+		{name: "expires later today", expiresAt: time.Date(2019, 11, 14, 23, 0, 0, 0, time.UTC), expected: 0},
+		{name: "expires tomorrow, in less than a day", expiresAt: time.Date(2019, 11, 15, 1, 0, 0, 0, time.UTC), expected: 1},
+		{name: "expires in a week", expiresAt: time.Date(2019, 11, 21, 8, 0, 0, 0, time.UTC), expected: 7},
+		{name: "already expired", expiresAt: time.Date(2019, 11, 12, 8, 0, 0, 0, time.UTC), expected: -2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			file := models.CodeSigningFileExpiry{ExpiresAt: tc.expiresAt}
+			require.Equal(t, tc.expected, file.DaysLeft(now))
+		})
+	}
+}
+
+func Test_CodeSigningExpiry_ExpiresAt(t *testing.T) {
+	t.Run("when there are no files", func(t *testing.T) {
+		codeSigningExpiry := models.CodeSigningExpiry{}
+		require.True(t, codeSigningExpiry.Empty())
+		require.Nil(t, codeSigningExpiry.ExpiresAt())
+	})
+
+	t.Run("returns the earliest expiry", func(t *testing.T) {
+		earliest := time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC)
+		codeSigningExpiry := models.CodeSigningExpiry{
+			ProvisioningProfiles: []models.CodeSigningFileExpiry{
+				{Slug: "prov-profile-1", ExpiresAt: time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)},
+				{Slug: "prov-profile-2", ExpiresAt: earliest},
+			},
+			DistributionCertificate: &models.CodeSigningFileExpiry{Slug: "identity", ExpiresAt: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+		}
+		require.False(t, codeSigningExpiry.Empty())
+		require.Len(t, codeSigningExpiry.Files(), 3)
+		require.Equal(t, earliest, *codeSigningExpiry.ExpiresAt())
+	})
+}
+
+func Test_CodeSigningExpiry_FilesToRemind(t *testing.T) {
+	now := time.Date(2019, 11, 14, 8, 0, 0, 0, time.UTC)
+	codeSigningExpiry := models.CodeSigningExpiry{
+		ProvisioningProfiles: []models.CodeSigningFileExpiry{
+			{Slug: "in-30-days", ExpiresAt: now.AddDate(0, 0, 30)},
+			{Slug: "in-8-days", ExpiresAt: now.AddDate(0, 0, 8)},
+			{Slug: "tomorrow", ExpiresAt: now.AddDate(0, 0, 1)},
+		},
+		DistributionCertificate: &models.CodeSigningFileExpiry{Slug: "in-7-days", ExpiresAt: now.AddDate(0, 0, 7)},
+	}
+
+	files := codeSigningExpiry.FilesToRemind(now)
+	slugs := []string{}
+	for _, file := range files {
+		slugs = append(slugs, file.Slug)
+	}
+	require.Equal(t, []string{"in-30-days", "tomorrow", "in-7-days"}, slugs)
+}
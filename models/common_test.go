@@ -167,6 +167,15 @@ func runTestMigrations(t *testing.T, db *gorm.DB) {
 				return nil
 			},
 		},
+		{
+			message: "create releases table",
+			fn: func() error {
+				if !db.HasTable(&models.Release{}) {
+					return db.CreateTable(&models.Release{}).Error
+				}
+				return nil
+			},
+		},
 	} {
 		t.Log(migration.message)
 		panicIfErr(migration.fn())
@@ -0,0 +1,32 @@
+// +build database
+
+package models_test
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/addons-ship-backend/dataservices"
+	"github.com/bitrise-io/addons-ship-backend/dataservices/contracttest"
+	"github.com/bitrise-io/addons-ship-backend/models"
+)
+
+func Test_Contract(t *testing.T) {
+	contracttest.Run(t, func(t *testing.T) (contracttest.Services, func()) {
+		dbCloseCallbackMethod := prepareDB(t)
+
+		db := dataservices.GetDB()
+		return contracttest.Services{
+			AppService:              &models.AppService{DB: db},
+			AppVersionService:       &models.AppVersionService{DB: db},
+			AppSettingsService:      &models.AppSettingsService{DB: db},
+			AppVersionEventService:  &models.AppVersionEventService{DB: db},
+			PublishTaskService:      &models.PublishTaskService{DB: db},
+			ScreenshotService:       &models.ScreenshotService{DB: db},
+			FeatureGraphicService:   &models.FeatureGraphicService{DB: db},
+			AppContactService:       &models.AppContactService{DB: db},
+			ReleaseService:          &models.ReleaseService{DB: db},
+			ScheduledPublishService: &models.ScheduledPublishService{DB: db},
+			ApprovalService:         &models.ApprovalService{DB: db},
+		}, dbCloseCallbackMethod
+	})
+}
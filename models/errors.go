@@ -3,6 +3,7 @@ package models
 import (
 	"strings"
 
+	"github.com/jinzhu/gorm"
 	"github.com/pkg/errors"
 )
 
@@ -25,3 +26,13 @@ func ValidationErrors(errs []error) []error {
 	}
 	return verrs
 }
+
+func addValidationErrors(scope *gorm.Scope, verrs []error) error {
+	if len(verrs) == 0 {
+		return nil
+	}
+	for _, verr := range verrs {
+		scope.DB().AddError(NewValidationError(verr.Error()))
+	}
+	return errors.New("Validation failed")
+}
@@ -46,21 +46,34 @@ func (f *FeatureGraphic) BeforeUpdate(scope *gorm.Scope) error {
 }
 
 func (f *FeatureGraphic) validate(scope *gorm.Scope, action string) error {
-	var err error
-	if f.Filesize > MaxFeatureGraphicFileByteSize {
-		err = scope.DB().AddError(NewValidationError("filesize: Must be smaller than 10 megabytes"))
-	}
+	verrs := f.Validate()
 	if action == "create" {
 		var featureGraphicCnt int64
-		err = scope.DB().Model(&FeatureGraphic{}).Where("app_version_id = ?", f.AppVersionID).Count(&featureGraphicCnt).Error
-		if featureGraphicCnt > 0 {
-			err = scope.DB().AddError(NewValidationError("feature_graphics: Maximum count of feature graphics is 1"))
+		err := scope.DB().Model(&FeatureGraphic{}).Where("app_version_id = ?", f.AppVersionID).Count(&featureGraphicCnt).Error
+		if err != nil {
+			return errors.WithStack(err)
 		}
+		verrs = append(verrs, f.ValidateCount(featureGraphicCnt)...)
 	}
-	if err != nil {
-		return errors.New("Validation failed")
+	return addValidationErrors(scope, verrs)
+}
+
+// Validate ...
+func (f *FeatureGraphic) Validate() []error {
+	verrs := []error{}
+	if f.Filesize > MaxFeatureGraphicFileByteSize {
+		verrs = append(verrs, errors.New("filesize: Must be smaller than 10 megabytes"))
 	}
-	return nil
+	return verrs
+}
+
+// ValidateCount validates the number of feature graphics already existing
+// for the app version of a feature graphic to be created.
+func (f *FeatureGraphic) ValidateCount(existingFeatureGraphicCount int64) []error {
+	if existingFeatureGraphicCount > 0 {
+		return []error{errors.New("feature_graphics: Maximum count of feature graphics is 1")}
+	}
+	return []error{}
 }
 
 // AWSPath ...
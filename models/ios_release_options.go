@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// IosReleaseTypeManual ...
+	IosReleaseTypeManual = "manual"
+	// IosReleaseTypeAfterApproval ...
+	IosReleaseTypeAfterApproval = "after_approval"
+	// IosReleaseTypeScheduled ...
+	IosReleaseTypeScheduled = "scheduled"
+)
+
+// IosReleaseOptions describes how a version gets released on the App Store
+// after it's approved. An empty release type leaves the release to the defaults
+// of the publish task. A phased release makes the version available for the
+// users with automatic updates gradually, in 7 days.
+type IosReleaseOptions struct {
+	ReleaseType          string     `json:"release_type,omitempty"`
+	ScheduledReleaseDate *time.Time `json:"scheduled_release_date,omitempty"`
+	PhasedRelease        bool       `json:"phased_release"`
+	ResetRatings         bool       `json:"reset_ratings"`
+}
+
+// Empty ...
+func (o IosReleaseOptions) Empty() bool {
+	return o == IosReleaseOptions{}
+}
+
+// Equal compares the scheduled release dates by their time, not by their
+// location
+func (o IosReleaseOptions) Equal(other IosReleaseOptions) bool {
+	if (o.ScheduledReleaseDate == nil) != (other.ScheduledReleaseDate == nil) {
+		return false
+	}
+	if o.ScheduledReleaseDate != nil && !o.ScheduledReleaseDate.Equal(*other.ScheduledReleaseDate) {
+		return false
+	}
+	o.ScheduledReleaseDate, other.ScheduledReleaseDate = nil, nil
+	return o == other
+}
+
+// Validate ...
+func (o IosReleaseOptions) Validate() []error {
+	verrs := []error{}
+	switch o.ReleaseType {
+	case "", IosReleaseTypeManual, IosReleaseTypeAfterApproval:
+		if o.ScheduledReleaseDate != nil {
+			verrs = append(verrs, errors.New("scheduled_release_date: Can only be set for scheduled releases"))
+		}
+	case IosReleaseTypeScheduled:
+		if o.ScheduledReleaseDate == nil {
+			verrs = append(verrs, errors.New("scheduled_release_date: Cannot be empty for scheduled releases"))
+		}
+	default:
+		verrs = append(verrs, errors.New("release_type: Must be one of manual, after_approval, scheduled"))
+	}
+	return verrs
+}
@@ -0,0 +1,100 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/c2fo/testify/require"
+)
+
+func Test_IosReleaseOptions_Validate(t *testing.T) {
+	releaseDate := time.Date(2019, 12, 1, 10, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name           string
+		releaseOptions models.IosReleaseOptions
+		expectedErrors []string
+	}{
+		{
+			name:           "when release options are empty",
+			releaseOptions: models.IosReleaseOptions{},
+			expectedErrors: []string{},
+		},
+		{
+			name:           "when release is automatic and phased",
+			releaseOptions: models.IosReleaseOptions{ReleaseType: "after_approval", PhasedRelease: true, ResetRatings: true},
+			expectedErrors: []string{},
+		},
+		{
+			name:           "when release is scheduled",
+			releaseOptions: models.IosReleaseOptions{ReleaseType: "scheduled", ScheduledReleaseDate: &releaseDate},
+			expectedErrors: []string{},
+		},
+		{
+			name:           "when release date is missing for a scheduled release",
+			releaseOptions: models.IosReleaseOptions{ReleaseType: "scheduled"},
+			expectedErrors: []string{"scheduled_release_date: Cannot be empty for scheduled releases"},
+		},
+		{
+			name:           "when release date is set for a manual release",
+			releaseOptions: models.IosReleaseOptions{ReleaseType: "manual", ScheduledReleaseDate: &releaseDate},
+			expectedErrors: []string{"scheduled_release_date: Can only be set for scheduled releases"},
+		},
+		{
+			name:           "when release type is invalid",
+			releaseOptions: models.IosReleaseOptions{ReleaseType: "immediate"},
+			expectedErrors: []string{"release_type: Must be one of manual, after_approval, scheduled"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			errorMessages := []string{}
+			for _, verr := range tc.releaseOptions.Validate() {
+				errorMessages = append(errorMessages, verr.Error())
+			}
+			require.Equal(t, tc.expectedErrors, errorMessages)
+		})
+	}
+}
+
+func Test_IosReleaseOptions_Equal(t *testing.T) {
+	releaseDate := time.Date(2019, 12, 1, 10, 0, 0, 0, time.UTC)
+	sameReleaseDate := releaseDate.In(time.FixedZone("CET", 3600))
+	otherReleaseDate := releaseDate.Add(time.Hour)
+
+	for _, tc := range []struct {
+		name          string
+		options       models.IosReleaseOptions
+		otherOptions  models.IosReleaseOptions
+		expectedEqual bool
+	}{
+		{
+			name:          "when release dates are the same time in different locations",
+			options:       models.IosReleaseOptions{ReleaseType: "scheduled", ScheduledReleaseDate: &releaseDate},
+			otherOptions:  models.IosReleaseOptions{ReleaseType: "scheduled", ScheduledReleaseDate: &sameReleaseDate},
+			expectedEqual: true,
+		},
+		{
+			name:          "when release dates differ",
+			options:       models.IosReleaseOptions{ReleaseType: "scheduled", ScheduledReleaseDate: &releaseDate},
+			otherOptions:  models.IosReleaseOptions{ReleaseType: "scheduled", ScheduledReleaseDate: &otherReleaseDate},
+			expectedEqual: false,
+		},
+		{
+			name:          "when only one of the options has a release date",
+			options:       models.IosReleaseOptions{ReleaseType: "scheduled", ScheduledReleaseDate: &releaseDate},
+			otherOptions:  models.IosReleaseOptions{ReleaseType: "scheduled"},
+			expectedEqual: false,
+		},
+		{
+			name:          "when other options differ",
+			options:       models.IosReleaseOptions{ReleaseType: "after_approval", PhasedRelease: true},
+			otherOptions:  models.IosReleaseOptions{ReleaseType: "after_approval"},
+			expectedEqual: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expectedEqual, tc.options.Equal(tc.otherOptions))
+		})
+	}
+}
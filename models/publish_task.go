@@ -2,10 +2,41 @@ package models
 
 import uuid "github.com/satori/go.uuid"
 
+const (
+	// PublishTaskStatusPending ...
+	PublishTaskStatusPending = "pending"
+	// PublishTaskStatusInProgress ...
+	PublishTaskStatusInProgress = "in_progress"
+	// PublishTaskStatusSuccess ...
+	PublishTaskStatusSuccess = "success"
+	// PublishTaskStatusFailed ...
+	PublishTaskStatusFailed = "failed"
+
+	// PublishTaskActionPublish ...
+	PublishTaskActionPublish = "publish"
+	// PublishTaskActionRollout ...
+	PublishTaskActionRollout = "rollout"
+	// PublishTaskActionPromote ...
+	PublishTaskActionPromote = "promote"
+)
+
 // PublishTask ...
 type PublishTask struct {
 	Record
-	TaskID uuid.UUID `json:"task_id"`
+	TaskID      uuid.UUID `json:"task_id"`
+	Destination string    `json:"destination"`
+	Track       string    `json:"track,omitempty"`
+	Status      string    `json:"status"`
+	// Action tells whether the task published the version, changed the rollout
+	// of its release, or promoted the release from FromTrack to Track
+	Action    string `json:"action"`
+	FromTrack string `json:"from_track,omitempty"`
+	// BatchID is shared by the tasks triggered by the same publish request
+	BatchID uuid.UUID `db:"batch_id" json:"batch_id"`
+	// ReleaseStatus and UserFraction are the rollout of the Google Play release
+	// the task creates or updates
+	ReleaseStatus string  `json:"release_status,omitempty"`
+	UserFraction  float64 `json:"user_fraction,omitempty"`
 
 	AppVersionID uuid.UUID  `db:"app_version_id" json:"-"`
 	AppVersion   AppVersion `gorm:"foreignkey:AppVersionID" json:"-"`
@@ -16,5 +47,21 @@ func (t *PublishTask) BeforeCreate() error {
 	if uuid.Equal(t.ID, uuid.UUID{}) {
 		t.ID = uuid.NewV4()
 	}
+	if t.Status == "" {
+		t.Status = PublishTaskStatusPending
+	}
+	if t.Action == "" {
+		t.Action = PublishTaskActionPublish
+	}
 	return nil
 }
+
+// Rollout ...
+func (t *PublishTask) Rollout() AndroidRollout {
+	return AndroidRollout{ReleaseStatus: t.ReleaseStatus, UserFraction: t.UserFraction}
+}
+
+// Finished ...
+func (t *PublishTask) Finished() bool {
+	return t.Status == PublishTaskStatusSuccess || t.Status == PublishTaskStatusFailed
+}
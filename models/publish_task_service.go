@@ -21,3 +21,39 @@ func (t *PublishTaskService) Find(publishTask *PublishTask) (*PublishTask, error
 	}
 	return publishTask, nil
 }
+
+// FindAll ...
+func (t *PublishTaskService) FindAll(appVersion *AppVersion) ([]PublishTask, error) {
+	var publishTasks []PublishTask
+	err := t.DB.Where(map[string]interface{}{"app_version_id": appVersion.ID}).
+		Order("created_at ASC").
+		Find(&publishTasks).Error
+	if err != nil {
+		return nil, err
+	}
+	return publishTasks, nil
+}
+
+// FindAllForApp returns the publish tasks of every version of an app, with
+// their app versions
+func (t *PublishTaskService) FindAllForApp(app *App) ([]PublishTask, error) {
+	var publishTasks []PublishTask
+	err := t.DB.Joins("JOIN app_versions ON app_versions.id = publish_tasks.app_version_id").
+		Where("app_versions.app_id = ?", app.ID).
+		Preload("AppVersion").
+		Order("publish_tasks.created_at ASC").
+		Find(&publishTasks).Error
+	if err != nil {
+		return nil, err
+	}
+	return publishTasks, nil
+}
+
+// Update ...
+func (t *PublishTaskService) Update(publishTask *PublishTask, whitelist []string) error {
+	updateData, err := t.UpdateData(*publishTask, whitelist)
+	if err != nil {
+		return err
+	}
+	return t.DB.Model(publishTask).Updates(updateData).Error
+}
@@ -0,0 +1,527 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/thoas/go-funk"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	// IosPublishWorkflowID ...
+	IosPublishWorkflowID = "resign_archive_app_store"
+	// MacPublishWorkflowID is the publish workflow of macOS versions, the
+	// versions of the other Apple platforms are published by the iOS one
+	MacPublishWorkflowID = "resign_archive_mac_app_store"
+	// AndroidPublishWorkflowID ...
+	AndroidPublishWorkflowID = "resign_android"
+	// AndroidPromoteWorkflowID is the workflow moving a published release to
+	// another track, without re-signing and uploading it again
+	AndroidPromoteWorkflowID = "promote_android"
+	// AndroidRolloutWorkflowID is the workflow changing the rollout of a
+	// published release, without re-signing and uploading it again
+	AndroidRolloutWorkflowID = "rollout_android"
+	// DefaultPublishStackID ...
+	DefaultPublishStackID = "osx-vs4mac-stable"
+
+	publishStepLibSource = "https://github.com/bitrise-io/bitrise-steplib.git"
+
+	shipIosMetadataDownloaderStepID = "git@github.com:bitrise-io/addons-ship-metadata-downloader-ios.git"
+	shipIosWorkerTaskStepID         = "git@github.com:bitrise-io/addons-ship-bg-worker-task-ios.git"
+	shipAndroidPrepareStepID        = "git@github.com:bitrise-io/addons-ship-bg-worker-task-android-prepare.git"
+	shipAndroidSyncStepID           = "git@github.com:bitrise-io/addons-ship-bg-worker-task-android-sync.git"
+	shipAndroidPromoteStepID        = "git@github.com:bitrise-io/addons-ship-bg-worker-task-android-promote.git"
+	shipAndroidRolloutStepID        = "git@github.com:bitrise-io/addons-ship-bg-worker-task-android-rollout.git"
+)
+
+// AllowedPublishSteps is the list of steps the workflows of a custom publish
+// config can use, identified by their step ID or git URL, without version. Steps
+// running arbitrary code, like script, are only allowed in the required steps
+// taken from the default config, as the publish tasks get secrets.
+var AllowedPublishSteps = []string{
+	"activate-ssh-key",
+	"cache-pull",
+	"cache-push",
+	"certificate-and-profile-installer",
+	"deploy-to-bitrise-io",
+	"email-with-mailgun",
+	"export-xcarchive",
+	"export-xcarchive-mac-os",
+	"google-play-deploy",
+	"sign-apk",
+	"slack",
+	shipIosMetadataDownloaderStepID,
+	shipIosWorkerTaskStepID,
+	shipAndroidPrepareStepID,
+	shipAndroidSyncStepID,
+	shipAndroidPromoteStepID,
+	shipAndroidRolloutStepID,
+}
+
+// requiredPublishSteps are the steps of the default publish workflows which
+// publishing can't work without. They are always taken from the default config,
+// the ones in the before list are put before, the ones in the after list are put
+// after the steps of a custom publish workflow.
+var requiredPublishSteps = map[string]struct{ before, after []string }{
+	IosPublishWorkflowID: {
+		before: []string{"activate-ssh-key", shipIosMetadataDownloaderStepID, "script"},
+		after:  []string{shipIosWorkerTaskStepID},
+	},
+	MacPublishWorkflowID: {
+		before: []string{"activate-ssh-key", shipIosMetadataDownloaderStepID, "script"},
+		after:  []string{shipIosWorkerTaskStepID},
+	},
+	AndroidPublishWorkflowID: {
+		before: []string{"activate-ssh-key", shipAndroidPrepareStepID},
+		after:  []string{shipAndroidSyncStepID},
+	},
+	AndroidPromoteWorkflowID: {
+		before: []string{"activate-ssh-key"},
+		after:  []string{shipAndroidPromoteStepID},
+	},
+	AndroidRolloutWorkflowID: {
+		before: []string{"activate-ssh-key"},
+		after:  []string{shipAndroidRolloutStepID},
+	},
+}
+
+// allowedPublishConfigKeys, allowedPublishAppKeys and allowedPublishWorkflowKeys
+// are the keys a custom publish config can set, e.g. before_run and after_run
+// aren't allowed, as they could run workflows around the required steps
+var (
+	allowedPublishConfigKeys   = []string{"format_version", "default_step_lib_source", "project_type", "title", "summary", "description", "app", "workflows"}
+	allowedPublishAppKeys      = []string{"title", "summary", "description", "envs"}
+	allowedPublishWorkflowKeys = []string{"title", "summary", "description", "envs", "steps"}
+)
+
+// publishTaskEnvs are the envs the publish tasks get from Ship, or set for the
+// required steps, which a custom publish config can't override
+var publishTaskEnvs = []string{
+	"BITRISE_APP_SLUG", "BITRISE_BUILD_SLUG", "BITRISE_ARTIFACT_SLUG", "CONFIG_JSON_URL", "GIT_REPOSITORY_URL",
+}
+
+// publishTaskSecretEnvs are the secrets of the publish tasks, and the App Store
+// Connect credentials exported by the metadata downloader step, which none of
+// the allowed steps of a custom publish config needs
+var publishTaskSecretEnvs = []string{
+	"BITRISE_ACCESS_TOKEN", "ADDON_SHIP_ACCESS_TOKEN", "ADDON_SHIP_APP_ACCESS_TOKEN", "SSH_RSA_PRIVATE_KEY",
+	"BITRISE_SHIP_APPLE_USER", "BITRISE_SHIP_APP_SPECIFIC_PASSWORD",
+	"BITRISE_SHIP_API_KEY_ISSUER_ID", "BITRISE_SHIP_API_KEY_ID", "BITRISE_SHIP_API_KEY_PRIVATE_KEY",
+}
+
+var envReferencePattern = regexp.MustCompile(`\$\{?([A-Za-z_][A-Za-z0-9_]*)`)
+
+// PublishConfig is a bitrise.yml, which contains the workflows publishing an
+// app version
+type PublishConfig map[string]interface{}
+
+// ParsePublishConfig ...
+func ParsePublishConfig(content string) (PublishConfig, error) {
+	var config interface{}
+	err := yaml.Unmarshal([]byte(content), &config)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	config, err = convertYAMLKeys(config)
+	if err != nil {
+		return nil, err
+	}
+	configMap, ok := config.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("Config has to be a map")
+	}
+	return PublishConfig(configMap), nil
+}
+
+// Validate ...
+func (c PublishConfig) Validate() []error {
+	workflows, err := c.workflows()
+	if err != nil {
+		return []error{errors.Errorf("publish_bitrise_yml: %s", err)}
+	}
+	if len(workflows) == 0 {
+		return []error{errors.New("publish_bitrise_yml: No workflows defined")}
+	}
+
+	verrs := []error{}
+	for _, key := range sortedKeys(c) {
+		if !funk.ContainsString(allowedPublishConfigKeys, key) {
+			verrs = append(verrs, errors.Errorf("publish_bitrise_yml: Key is not allowed: %s", key))
+		}
+	}
+	if stepLibSource, ok := c["default_step_lib_source"]; ok && stepLibSource != publishStepLibSource {
+		verrs = append(verrs, errors.New("publish_bitrise_yml: Step library source is not allowed"))
+	}
+	if c["app"] != nil {
+		app, ok := c["app"].(map[string]interface{})
+		if !ok {
+			verrs = append(verrs, errors.New("publish_bitrise_yml: App has to be a map"))
+		} else {
+			for _, key := range sortedKeys(app) {
+				if !funk.ContainsString(allowedPublishAppKeys, key) {
+					verrs = append(verrs, errors.Errorf("publish_bitrise_yml: App: Key is not allowed: %s", key))
+				}
+			}
+			if err := validatePublishEnvs(app["envs"]); err != nil {
+				verrs = append(verrs, errors.Errorf("publish_bitrise_yml: App: %s", err))
+			}
+		}
+	}
+	for _, envKey := range referencedSecretEnvs(c) {
+		verrs = append(verrs, errors.Errorf("publish_bitrise_yml: Secret env can't be referenced: %s", envKey))
+	}
+	for _, workflowID := range sortedKeys(workflows) {
+		steps, err := workflowSteps(workflows[workflowID])
+		if err != nil {
+			verrs = append(verrs, errors.Errorf("publish_bitrise_yml: Workflow %s: %s", workflowID, err))
+			continue
+		}
+		if workflow, ok := workflows[workflowID].(map[string]interface{}); ok {
+			for _, key := range sortedKeys(workflow) {
+				if !funk.ContainsString(allowedPublishWorkflowKeys, key) {
+					verrs = append(verrs, errors.Errorf("publish_bitrise_yml: Workflow %s: Key is not allowed: %s", workflowID, key))
+				}
+			}
+			if err := validatePublishEnvs(workflow["envs"]); err != nil {
+				verrs = append(verrs, errors.Errorf("publish_bitrise_yml: Workflow %s: %s", workflowID, err))
+			}
+		}
+		for _, step := range steps {
+			stepKey, err := publishStepKey(step)
+			if err != nil {
+				verrs = append(verrs, errors.Errorf("publish_bitrise_yml: Workflow %s: %s", workflowID, err))
+				continue
+			}
+			if !funk.ContainsString(AllowedPublishSteps, publishStepID(stepKey)) {
+				verrs = append(verrs, errors.Errorf("publish_bitrise_yml: Workflow %s: Step is not allowed: %s", workflowID, stepKey))
+			}
+		}
+	}
+	return verrs
+}
+
+// MergePublishConfig returns the config to publish with. It contains the
+// workflows of the custom config, extended with the required steps of the
+// default publish workflows, and the default workflows the custom config
+// doesn't override. The custom config is validated again, as it could have been
+// saved before the current rules.
+func MergePublishConfig(defaultConfig, customConfig PublishConfig) (PublishConfig, error) {
+	if verrs := customConfig.Validate(); len(verrs) > 0 {
+		return nil, errors.Wrap(verrs[0], "Invalid custom publish config")
+	}
+
+	merged := PublishConfig{}
+	for key, value := range defaultConfig {
+		merged[key] = value
+	}
+	for key, value := range customConfig {
+		if key != "app" && key != "workflows" && funk.ContainsString(allowedPublishConfigKeys, key) {
+			merged[key] = value
+		}
+	}
+
+	merged["default_step_lib_source"] = publishStepLibSource
+
+	app, err := mergePublishConfigApp(defaultConfig["app"], customConfig["app"])
+	if err != nil {
+		return nil, err
+	}
+	if app != nil {
+		merged["app"] = app
+	}
+
+	defaultWorkflows, err := defaultConfig.workflows()
+	if err != nil {
+		return nil, err
+	}
+	customWorkflows, err := customConfig.workflows()
+	if err != nil {
+		return nil, err
+	}
+	workflows := map[string]interface{}{}
+	for workflowID, workflow := range defaultWorkflows {
+		workflows[workflowID] = workflow
+	}
+	for workflowID, workflow := range customWorkflows {
+		required, ok := requiredPublishSteps[workflowID]
+		if !ok {
+			workflows[workflowID] = workflow
+			continue
+		}
+		workflows[workflowID], err = mergePublishWorkflow(defaultWorkflows[workflowID], workflow, required.before, required.after)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Workflow %s", workflowID)
+		}
+	}
+	merged["workflows"] = workflows
+	return merged, nil
+}
+
+// mergePublishConfigApp puts the app envs of the default config after the
+// custom ones, so that the envs required for publishing can't be overridden
+func mergePublishConfigApp(defaultApp, customApp interface{}) (interface{}, error) {
+	if customApp == nil {
+		return defaultApp, nil
+	}
+	customAppMap, ok := customApp.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("App has to be a map")
+	}
+	defaultAppMap, ok := defaultApp.(map[string]interface{})
+	if !ok {
+		return customApp, nil
+	}
+
+	app := map[string]interface{}{}
+	for key, value := range customAppMap {
+		if funk.ContainsString(allowedPublishAppKeys, key) {
+			app[key] = value
+		}
+	}
+	envs := []interface{}{}
+	for _, appMap := range []map[string]interface{}{customAppMap, defaultAppMap} {
+		if appMap["envs"] == nil {
+			continue
+		}
+		appEnvs, ok := appMap["envs"].([]interface{})
+		if !ok {
+			return nil, errors.New("App envs have to be a list")
+		}
+		envs = append(envs, appEnvs...)
+	}
+	app["envs"] = envs
+	return app, nil
+}
+
+func mergePublishWorkflow(defaultWorkflow, customWorkflow interface{}, before, after []string) (interface{}, error) {
+	customWorkflowMap, ok := customWorkflow.(map[string]interface{})
+	if !ok && customWorkflow != nil {
+		return nil, errors.New("Workflow has to be a map")
+	}
+	defaultSteps, err := workflowSteps(defaultWorkflow)
+	if err != nil {
+		return nil, err
+	}
+	customSteps, err := workflowSteps(customWorkflow)
+	if err != nil {
+		return nil, err
+	}
+
+	requiredSteps := func(stepIDs []string) ([]interface{}, error) {
+		steps := []interface{}{}
+		for _, stepID := range stepIDs {
+			step, err := findPublishStep(defaultSteps, stepID)
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, step)
+		}
+		return steps, nil
+	}
+	steps, err := requiredSteps(before)
+	if err != nil {
+		return nil, err
+	}
+	for _, step := range customSteps {
+		stepKey, err := publishStepKey(step)
+		if err != nil {
+			return nil, err
+		}
+		stepID := publishStepID(stepKey)
+		if !funk.ContainsString(before, stepID) && !funk.ContainsString(after, stepID) {
+			steps = append(steps, step)
+		}
+	}
+	afterSteps, err := requiredSteps(after)
+	if err != nil {
+		return nil, err
+	}
+	steps = append(steps, afterSteps...)
+
+	workflow := map[string]interface{}{}
+	for key, value := range customWorkflowMap {
+		if funk.ContainsString(allowedPublishWorkflowKeys, key) {
+			workflow[key] = value
+		}
+	}
+	workflow["steps"] = steps
+	return workflow, nil
+}
+
+// validatePublishEnvs checks the envs of the app or a workflow of a custom
+// publish config, which have to be a list of single env maps, with optional
+// opts, and can't override the envs of the publish tasks
+func validatePublishEnvs(envs interface{}) error {
+	if envs == nil {
+		return nil
+	}
+	envList, ok := envs.([]interface{})
+	if !ok {
+		return errors.New("Envs have to be a list")
+	}
+	for _, env := range envList {
+		envMap, ok := env.(map[string]interface{})
+		if !ok {
+			return errors.New("Env has to be a map")
+		}
+		envKeys := []string{}
+		for key := range envMap {
+			if key != "opts" {
+				envKeys = append(envKeys, key)
+			}
+		}
+		if len(envKeys) != 1 {
+			return errors.New("Env has to have a single key")
+		}
+		envKey := envKeys[0]
+		if funk.ContainsString(publishTaskEnvs, envKey) || funk.ContainsString(publishTaskSecretEnvs, envKey) {
+			return errors.Errorf("Env can't be overridden: %s", envKey)
+		}
+	}
+	return nil
+}
+
+// referencedSecretEnvs returns the secret envs of the publish tasks referenced
+// by the values of a custom publish config, e.g. by the inputs of its steps
+func referencedSecretEnvs(value interface{}) []string {
+	envKeys := []string{}
+	switch typed := value.(type) {
+	case PublishConfig:
+		return referencedSecretEnvs(map[string]interface{}(typed))
+	case map[string]interface{}:
+		for _, key := range sortedKeys(typed) {
+			envKeys = append(envKeys, referencedSecretEnvs(typed[key])...)
+		}
+	case []interface{}:
+		for _, item := range typed {
+			envKeys = append(envKeys, referencedSecretEnvs(item)...)
+		}
+	case string:
+		for _, match := range envReferencePattern.FindAllStringSubmatch(typed, -1) {
+			if funk.ContainsString(publishTaskSecretEnvs, match[1]) {
+				envKeys = append(envKeys, match[1])
+			}
+		}
+	}
+	return funk.UniqString(envKeys)
+}
+
+func sortedKeys(values map[string]interface{}) []string {
+	keys := []string{}
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func findPublishStep(steps []interface{}, stepID string) (interface{}, error) {
+	for _, step := range steps {
+		stepKey, err := publishStepKey(step)
+		if err != nil {
+			return nil, err
+		}
+		if publishStepID(stepKey) == stepID {
+			return step, nil
+		}
+	}
+	return nil, errors.Errorf("Required step not found in default workflow: %s", stepID)
+}
+
+func (c PublishConfig) workflows() (map[string]interface{}, error) {
+	if c["workflows"] == nil {
+		return map[string]interface{}{}, nil
+	}
+	workflows, ok := c["workflows"].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("Workflows have to be a map")
+	}
+	return workflows, nil
+}
+
+func workflowSteps(workflow interface{}) ([]interface{}, error) {
+	if workflow == nil {
+		return []interface{}{}, nil
+	}
+	workflowMap, ok := workflow.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("Workflow has to be a map")
+	}
+	if workflowMap["steps"] == nil {
+		return []interface{}{}, nil
+	}
+	steps, ok := workflowMap["steps"].([]interface{})
+	if !ok {
+		return nil, errors.New("Steps have to be a list")
+	}
+	return steps, nil
+}
+
+func publishStepKey(step interface{}) (string, error) {
+	stepMap, ok := step.(map[string]interface{})
+	if !ok || len(stepMap) != 1 {
+		return "", errors.New("Step has to be a map with a single key")
+	}
+	for stepKey := range stepMap {
+		return stepKey, nil
+	}
+	return "", nil
+}
+
+// publishStepID returns the step ID, or the git URL of git steps, without the
+// version, e.g. script for script@1.1. Steps of other step libraries and local
+// steps are returned as they are.
+func publishStepID(stepKey string) string {
+	if strings.HasPrefix(stepKey, "git::") {
+		stepID := strings.TrimPrefix(stepKey, "git::")
+		if i := strings.LastIndex(stepID, ".git@"); i >= 0 {
+			return stepID[:i+len(".git")]
+		}
+		return stepID
+	}
+	if i := strings.Index(stepKey, "::"); i >= 0 {
+		if stepKey[:i] != publishStepLibSource {
+			return stepKey
+		}
+		stepKey = stepKey[i+len("::"):]
+	}
+	if i := strings.Index(stepKey, "@"); i >= 0 {
+		return stepKey[:i]
+	}
+	return stepKey
+}
+
+// convertYAMLKeys converts the maps of a parsed YAML to JSON compatible ones
+func convertYAMLKeys(value interface{}) (interface{}, error) {
+	switch typed := value.(type) {
+	case map[interface{}]interface{}:
+		converted := map[string]interface{}{}
+		for key, item := range typed {
+			keyStr, ok := key.(string)
+			if !ok {
+				return nil, errors.Errorf("Key has to be a string: %s", fmt.Sprint(key))
+			}
+			convertedItem, err := convertYAMLKeys(item)
+			if err != nil {
+				return nil, err
+			}
+			converted[keyStr] = convertedItem
+		}
+		return converted, nil
+	case []interface{}:
+		converted := make([]interface{}, len(typed))
+		for i, item := range typed {
+			convertedItem, err := convertYAMLKeys(item)
+			if err != nil {
+				return nil, err
+			}
+			converted[i] = convertedItem
+		}
+		return converted, nil
+	}
+	return value, nil
+}
@@ -0,0 +1,292 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/c2fo/testify/require"
+)
+
+const testDefaultPublishConfig = `format_version: '7'
+default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+app:
+  envs:
+    - SHIP_ADDON_CONFIG_ANDROID: $CONFIG_JSON_URL
+workflows:
+  resign_archive_app_store:
+    steps:
+      - activate-ssh-key@4: {}
+      - git::git@github.com:bitrise-io/addons-ship-metadata-downloader-ios.git@update: {}
+      - certificate-and-profile-installer@1.10: {}
+      - script@1.1: {}
+      - export-xcarchive@2.1: {}
+      - git::git@github.com:bitrise-io/addons-ship-bg-worker-task-ios.git@master: {}
+  resign_android:
+    steps:
+      - activate-ssh-key@4.0: {}
+      - git::git@github.com:bitrise-io/addons-ship-bg-worker-task-android-prepare.git@master: {}
+      - google-play-deploy@3.1: {}
+      - git::git@github.com:bitrise-io/addons-ship-bg-worker-task-android-sync.git@master: {}
+`
+
+func stepKeys(t *testing.T, config models.PublishConfig, workflowID string) []string {
+	workflow, ok := config["workflows"].(map[string]interface{})[workflowID].(map[string]interface{})
+	require.True(t, ok)
+	keys := []string{}
+	for _, step := range workflow["steps"].([]interface{}) {
+		for key := range step.(map[string]interface{}) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func Test_ParsePublishConfig(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		config, err := models.ParsePublishConfig(testDefaultPublishConfig)
+		require.NoError(t, err)
+		require.Equal(t, "7", config["format_version"])
+		require.Len(t, stepKeys(t, config, "resign_android"), 4)
+	})
+
+	t.Run("when config is not a map", func(t *testing.T) {
+		_, err := models.ParsePublishConfig(`- workflows`)
+		require.EqualError(t, err, "Config has to be a map")
+	})
+
+	t.Run("when config has a non-string key", func(t *testing.T) {
+		_, err := models.ParsePublishConfig(`workflows: {1: {}}`)
+		require.EqualError(t, err, "Key has to be a string: 1")
+	})
+}
+
+func Test_PublishConfig_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		config        string
+		expectedVerrs []string
+	}{
+		{
+			name:   "default config",
+			config: testDefaultPublishConfig,
+			expectedVerrs: []string{
+				"publish_bitrise_yml: Workflow resign_archive_app_store: Step is not allowed: script@1.1",
+			},
+		},
+		{
+			name: "allowed steps with pinned versions",
+			config: `workflows:
+  resign_android:
+    steps:
+      - slack@3.1.2: {}
+      - https://github.com/bitrise-io/bitrise-steplib.git::google-play-deploy@3: {}
+      - sign-apk: {}`,
+			expectedVerrs: []string{},
+		},
+		{
+			name: "steps running arbitrary code",
+			config: `workflows:
+  resign_android:
+    steps:
+      - script@1: {}
+      - https://github.com/bitrise-io/bitrise-steplib.git::script@1: {}`,
+			expectedVerrs: []string{
+				"publish_bitrise_yml: Workflow resign_android: Step is not allowed: script@1",
+				"publish_bitrise_yml: Workflow resign_android: Step is not allowed: https://github.com/bitrise-io/bitrise-steplib.git::script@1",
+			},
+		},
+		{
+			name:          "no workflows",
+			config:        `format_version: '7'`,
+			expectedVerrs: []string{"publish_bitrise_yml: No workflows defined"},
+		},
+		{
+			name: "steps not in the allowlist",
+			config: `workflows:
+  resign_android:
+    steps:
+      - deploy-to-itunesconnect-deliver@2: {}
+      - path::./script: {}
+      - https://github.com/someone/steplib.git::script@1: {}
+      - git::git@github.com:someone/addons-ship-bg-worker-task-android-sync.git@master: {}`,
+			expectedVerrs: []string{
+				"publish_bitrise_yml: Workflow resign_android: Step is not allowed: deploy-to-itunesconnect-deliver@2",
+				"publish_bitrise_yml: Workflow resign_android: Step is not allowed: path::./script",
+				"publish_bitrise_yml: Workflow resign_android: Step is not allowed: https://github.com/someone/steplib.git::script@1",
+				"publish_bitrise_yml: Workflow resign_android: Step is not allowed: git::git@github.com:someone/addons-ship-bg-worker-task-android-sync.git@master",
+			},
+		},
+		{
+			name: "steps not in the allowlist in other workflows",
+			config: `workflows:
+  _notify:
+    steps:
+      - xcode-archive@2: {}`,
+			expectedVerrs: []string{"publish_bitrise_yml: Workflow _notify: Step is not allowed: xcode-archive@2"},
+		},
+		{
+			name: "keys running other workflows",
+			config: `trigger_map:
+  - push_branch: master
+    workflow: resign_android
+workflows:
+  _notify:
+    steps:
+      - slack@3: {}
+  resign_android:
+    before_run:
+      - _notify
+    after_run:
+      - _notify`,
+			expectedVerrs: []string{
+				"publish_bitrise_yml: Key is not allowed: trigger_map",
+				"publish_bitrise_yml: Workflow resign_android: Key is not allowed: after_run",
+				"publish_bitrise_yml: Workflow resign_android: Key is not allowed: before_run",
+			},
+		},
+		{
+			name: "envs overriding the envs of the publish task",
+			config: `app:
+  envs:
+    - CONFIG_JSON_URL: https://example.com/config.json
+workflows:
+  resign_android:
+    envs:
+      - SSH_RSA_PRIVATE_KEY: key
+      - opts:
+          is_expand: false`,
+			expectedVerrs: []string{
+				"publish_bitrise_yml: App: Env can't be overridden: CONFIG_JSON_URL",
+				"publish_bitrise_yml: Workflow resign_android: Env can't be overridden: SSH_RSA_PRIVATE_KEY",
+			},
+		},
+		{
+			name: "secret envs referenced",
+			config: `workflows:
+  resign_android:
+    envs:
+      - TOKEN: $ADDON_SHIP_ACCESS_TOKEN
+    steps:
+      - slack@3:
+          inputs:
+            - message: ${BITRISE_SHIP_APP_SPECIFIC_PASSWORD} $BITRISE_SHIP_API_KEY_PRIVATE_KEY $KEYSTORE_ALIAS`,
+			expectedVerrs: []string{
+				"publish_bitrise_yml: Secret env can't be referenced: ADDON_SHIP_ACCESS_TOKEN",
+				"publish_bitrise_yml: Secret env can't be referenced: BITRISE_SHIP_APP_SPECIFIC_PASSWORD",
+				"publish_bitrise_yml: Secret env can't be referenced: BITRISE_SHIP_API_KEY_PRIVATE_KEY",
+			},
+		},
+		{
+			name: "other step library",
+			config: `default_step_lib_source: https://github.com/someone/steplib.git
+workflows:
+  resign_android: {}`,
+			expectedVerrs: []string{"publish_bitrise_yml: Step library source is not allowed"},
+		},
+		{
+			name: "invalid steps",
+			config: `workflows:
+  resign_android:
+    steps:
+      - script@1`,
+			expectedVerrs: []string{"publish_bitrise_yml: Workflow resign_android: Step has to be a map with a single key"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			config, err := models.ParsePublishConfig(tc.config)
+			require.NoError(t, err)
+			verrs := []string{}
+			for _, verr := range config.Validate() {
+				verrs = append(verrs, verr.Error())
+			}
+			require.Equal(t, tc.expectedVerrs, verrs)
+		})
+	}
+}
+
+func Test_MergePublishConfig(t *testing.T) {
+	defaultConfig, err := models.ParsePublishConfig(testDefaultPublishConfig)
+	require.NoError(t, err)
+
+	t.Run("puts the required steps around the custom steps", func(t *testing.T) {
+		customConfig, err := models.ParsePublishConfig(`workflows:
+  resign_android:
+    title: Custom publish
+    steps:
+      - activate-ssh-key@3: {}
+      - sign-apk@1: {}
+      - google-play-deploy@3.0: {}
+      - slack@3: {}`)
+		require.NoError(t, err)
+
+		config, err := models.MergePublishConfig(defaultConfig, customConfig)
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"activate-ssh-key@4.0",
+			"git::git@github.com:bitrise-io/addons-ship-bg-worker-task-android-prepare.git@master",
+			"sign-apk@1",
+			"google-play-deploy@3.0",
+			"slack@3",
+			"git::git@github.com:bitrise-io/addons-ship-bg-worker-task-android-sync.git@master",
+		}, stepKeys(t, config, "resign_android"))
+		require.Equal(t, "Custom publish", config["workflows"].(map[string]interface{})["resign_android"].(map[string]interface{})["title"])
+
+		t.Log("keeps the default workflows not overridden")
+		require.Equal(t, []string{
+			"activate-ssh-key@4",
+			"git::git@github.com:bitrise-io/addons-ship-metadata-downloader-ios.git@update",
+			"certificate-and-profile-installer@1.10",
+			"script@1.1",
+			"export-xcarchive@2.1",
+			"git::git@github.com:bitrise-io/addons-ship-bg-worker-task-ios.git@master",
+		}, stepKeys(t, config, "resign_archive_app_store"))
+		require.Equal(t, "7", config["format_version"])
+	})
+
+	t.Run("when the custom config is invalid", func(t *testing.T) {
+		customConfig, err := models.ParsePublishConfig(`workflows:
+  resign_android:
+    before_run:
+      - _prepare`)
+		require.NoError(t, err)
+
+		config, err := models.MergePublishConfig(defaultConfig, customConfig)
+		require.EqualError(t, err, "Invalid custom publish config: publish_bitrise_yml: Workflow resign_android: Key is not allowed: before_run")
+		require.Nil(t, config)
+	})
+
+	t.Run("keeps the other custom workflows and the required app envs", func(t *testing.T) {
+		customConfig, err := models.ParsePublishConfig(`app:
+  envs:
+    - SLACK_CHANNEL: releases
+workflows:
+  _notify:
+    steps:
+      - slack@3: {}
+  resign_archive_app_store:
+    envs:
+      - EXPORT_METHOD: app-store
+    steps:
+      - certificate-and-profile-installer@1: {}
+      - export-xcarchive@2: {}`)
+		require.NoError(t, err)
+
+		config, err := models.MergePublishConfig(defaultConfig, customConfig)
+		require.NoError(t, err)
+		require.Equal(t, []string{"slack@3"}, stepKeys(t, config, "_notify"))
+		require.Equal(t, []string{
+			"activate-ssh-key@4",
+			"git::git@github.com:bitrise-io/addons-ship-metadata-downloader-ios.git@update",
+			"script@1.1",
+			"certificate-and-profile-installer@1",
+			"export-xcarchive@2",
+			"git::git@github.com:bitrise-io/addons-ship-bg-worker-task-ios.git@master",
+		}, stepKeys(t, config, "resign_archive_app_store"))
+		require.Equal(t, []interface{}{map[string]interface{}{"EXPORT_METHOD": "app-store"}},
+			config["workflows"].(map[string]interface{})["resign_archive_app_store"].(map[string]interface{})["envs"])
+		require.Equal(t, []interface{}{
+			map[string]interface{}{"SLACK_CHANNEL": "releases"},
+			map[string]interface{}{"SHIP_ADDON_CONFIG_ANDROID": "$CONFIG_JSON_URL"},
+		}, config["app"].(map[string]interface{})["envs"])
+	})
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"sort"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	// ReleasePublishStatusUnpublished ...
+	ReleasePublishStatusUnpublished = "unpublished"
+	// ReleasePublishStatusInProgress ...
+	ReleasePublishStatusInProgress = "in_progress"
+	// ReleasePublishStatusFailed ...
+	ReleasePublishStatusFailed = "failed"
+	// ReleasePublishStatusPartiallyPublished ...
+	ReleasePublishStatusPartiallyPublished = "partially_published"
+	// ReleasePublishStatusPublished ...
+	ReleasePublishStatusPublished = "published"
+)
+
+// Release groups the versions of an app which are released together, like the
+// iOS and Android versions of a cross-platform app built by the same build, or
+// having the same version string
+type Release struct {
+	Record
+	BuildSlug string `json:"build_slug"`
+	Version   string `json:"version"`
+	Notes     string `json:"notes"`
+
+	AppID       uuid.UUID    `db:"app_id" json:"-"`
+	App         App          `gorm:"foreignkey:AppID" json:"-"`
+	AppVersions []AppVersion `gorm:"foreignkey:ReleaseID" json:"app_versions"`
+}
+
+// BeforeCreate ...
+func (r *Release) BeforeCreate() error {
+	if uuid.Equal(r.ID, uuid.UUID{}) {
+		r.ID = uuid.NewV4()
+	}
+	return nil
+}
+
+// LatestAppVersions returns the latest of the versions of the release which
+// aren't archived, one per platform, product flavor and bundle ID, see
+// CompareAppVersions. Only these versions of a release are published.
+func (r *Release) LatestAppVersions() []AppVersion {
+	sorted := append([]AppVersion{}, r.AppVersions...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return CompareAppVersions(&sorted[i], &sorted[j]) > 0
+	})
+
+	latest := []AppVersion{}
+	groups := map[string]bool{}
+	for _, appVersion := range sorted {
+		group := appVersion.Platform + "/" + appVersion.ProductFlavor + "/" + appVersion.BundleID
+		if appVersion.ArchivedAt != nil || groups[group] {
+			continue
+		}
+		groups[group] = true
+		latest = append(latest, appVersion)
+	}
+	return latest
+}
+
+// ReleasePublishStatus combines the publish statuses of the versions of a
+// release. The statuses are the ones of the latest publish tasks of the
+// versions, an empty status stands for a version which wasn't published yet.
+func ReleasePublishStatus(statuses []string) string {
+	published := 0
+	failed := false
+	for _, status := range statuses {
+		switch status {
+		case PublishTaskStatusPending, PublishTaskStatusInProgress:
+			return ReleasePublishStatusInProgress
+		case PublishTaskStatusFailed:
+			failed = true
+		case PublishTaskStatusSuccess:
+			published++
+		}
+	}
+	switch {
+	case failed:
+		return ReleasePublishStatusFailed
+	case published > 0 && published == len(statuses):
+		return ReleasePublishStatusPublished
+	case published > 0:
+		return ReleasePublishStatusPartiallyPublished
+	}
+	return ReleasePublishStatusUnpublished
+}
@@ -0,0 +1,60 @@
+package models
+
+import "github.com/jinzhu/gorm"
+
+// ReleaseService ...
+type ReleaseService struct {
+	DB *gorm.DB
+	UpdatableModelService
+}
+
+// Create ...
+func (r *ReleaseService) Create(release *Release) (*Release, error) {
+	result := r.DB.Create(release)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return r.Find(&Release{Record: Record{ID: release.ID}})
+}
+
+// Find ...
+func (r *ReleaseService) Find(release *Release) (*Release, error) {
+	err := r.DB.Where(release).Preload("App").Preload("AppVersions", latestFirst).First(release).Error
+	if err != nil {
+		return nil, err
+	}
+	return release, nil
+}
+
+// FindAll ...
+func (r *ReleaseService) FindAll(app *App) ([]Release, error) {
+	var releases []Release
+	err := r.DB.Where(map[string]interface{}{"app_id": app.ID}).
+		Preload("App").Preload("AppVersions", latestFirst).
+		Order("created_at DESC").Find(&releases).Error
+	if err != nil {
+		return nil, err
+	}
+	return releases, nil
+}
+
+// Update ...
+func (r *ReleaseService) Update(release *Release, whitelist []string) (validationErrors []error, dbErr error) {
+	updateData, err := r.UpdateData(*release, whitelist)
+	if err != nil {
+		return nil, err
+	}
+	result := r.DB.Model(release).Updates(updateData)
+	verrs := ValidationErrors(result.GetErrors())
+	if len(verrs) > 0 {
+		return verrs, nil
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return nil, nil
+}
+
+func latestFirst(db *gorm.DB) *gorm.DB {
+	return db.Order("created_at DESC")
+}
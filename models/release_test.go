@@ -0,0 +1,47 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/c2fo/testify/require"
+)
+
+func Test_ReleasePublishStatus(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		statuses []string
+		expected string
+	}{
+		{name: "when the release has no versions", statuses: []string{}, expected: "unpublished"},
+		{name: "when no version was published", statuses: []string{"", ""}, expected: "unpublished"},
+		{name: "when a version is being published", statuses: []string{"success", "failed", "in_progress"}, expected: "in_progress"},
+		{name: "when a publish task is pending", statuses: []string{"", "pending"}, expected: "in_progress"},
+		{name: "when a version failed to be published", statuses: []string{"success", "failed"}, expected: "failed"},
+		{name: "when only some versions were published", statuses: []string{"success", ""}, expected: "partially_published"},
+		{name: "when every version was published", statuses: []string{"success", "success"}, expected: "published"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, models.ReleasePublishStatus(tc.statuses))
+		})
+	}
+}
+
+func Test_Release_LatestAppVersions(t *testing.T) {
+	archivedAt := time.Date(2019, 12, 1, 10, 0, 0, 0, time.UTC)
+	iosVersion := models.AppVersion{Platform: "ios", BundleID: "io.bitrise.app", BuildNumber: "12",
+		ArtifactInfoData: json.RawMessage(`{"version":"1.0"}`)}
+	olderIosVersion := models.AppVersion{Platform: "ios", BundleID: "io.bitrise.app", BuildNumber: "11",
+		ArtifactInfoData: json.RawMessage(`{"version":"1.0"}`)}
+	extensionVersion := models.AppVersion{Platform: "ios", BundleID: "io.bitrise.app.extension", BuildNumber: "11",
+		ArtifactInfoData: json.RawMessage(`{"version":"1.0"}`)}
+	archivedAndroidVersion := models.AppVersion{Platform: "android", ArchivedAt: &archivedAt,
+		ArtifactInfoData: json.RawMessage(`{"version":"1.0","version_code":"3"}`)}
+	androidVersion := models.AppVersion{Platform: "android",
+		ArtifactInfoData: json.RawMessage(`{"version":"1.0","version_code":"2"}`)}
+
+	release := models.Release{AppVersions: []models.AppVersion{olderIosVersion, archivedAndroidVersion, iosVersion, extensionVersion, androidVersion}}
+	require.Equal(t, []models.AppVersion{iosVersion, extensionVersion, androidVersion}, release.LatestAppVersions())
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	// RetentionActionArchive ...
+	RetentionActionArchive = "archive"
+	// RetentionActionPurge ...
+	RetentionActionPurge = "purge"
+)
+
+// RetentionMaxKeepLastByPlan is the maximum number of versions an app can keep
+// per platform, product flavor and bundle ID with a retention policy, the plans
+// which aren't listed have no maximum
+var RetentionMaxKeepLastByPlan = map[string]int{
+	"free": 20,
+}
+
+// RetentionPolicy describes which versions of an app get archived or purged
+// automatically. KeepLast keeps the latest versions per platform, product flavor
+// and bundle ID, DeleteUnpublishedAfterDays removes the versions which weren't
+// published in the given number of days. A zero value disables the rule.
+type RetentionPolicy struct {
+	KeepLast                   int    `json:"keep_last"`
+	KeepPublished              bool   `json:"keep_published"`
+	DeleteUnpublishedAfterDays int    `json:"delete_unpublished_after_days"`
+	Action                     string `json:"action"`
+}
+
+// Enabled ...
+func (p RetentionPolicy) Enabled() bool {
+	return p.KeepLast > 0 || p.DeleteUnpublishedAfterDays > 0
+}
+
+// Validate checks the policy against the maximum of the plan of the app
+func (p RetentionPolicy) Validate(plan string) []error {
+	verrs := []error{}
+	if p.KeepLast < 0 {
+		verrs = append(verrs, errors.New("keep_last: Cannot be negative"))
+	}
+	if maxKeepLast, ok := RetentionMaxKeepLastByPlan[plan]; ok && p.Enabled() && (p.KeepLast == 0 || p.KeepLast > maxKeepLast) {
+		verrs = append(verrs, errors.Errorf("keep_last: Must be between 1 and %d on the %s plan", maxKeepLast, plan))
+	}
+	if p.DeleteUnpublishedAfterDays < 0 {
+		verrs = append(verrs, errors.New("delete_unpublished_after_days: Cannot be negative"))
+	}
+	switch p.Action {
+	case "", RetentionActionArchive, RetentionActionPurge:
+	default:
+		verrs = append(verrs, errors.New("action: Must be one of archive, purge"))
+	}
+	return verrs
+}
+
+// Expired returns the versions which have to be removed by the policy. The
+// versions being published are always kept, and so are the published ones when
+// KeepPublished is set. A version counts as published when it's in the given
+// IDs of the versions which have ever been published successfully, as the
+// status of a published version changes when a newer one gets published.
+func (p RetentionPolicy) Expired(appVersions []AppVersion, publishedIDs map[uuid.UUID]bool, now time.Time) []AppVersion {
+	sorted := append([]AppVersion{}, appVersions...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return CompareAppVersions(&sorted[i], &sorted[j]) > 0
+	})
+
+	expired := []AppVersion{}
+	counts := map[string]int{}
+	for _, appVersion := range sorted {
+		group := appVersion.Platform + "/" + appVersion.ProductFlavor + "/" + appVersion.BundleID
+		counts[group]++
+		if appVersion.Status == AppVersionStatusPublishing {
+			continue
+		}
+		published := publishedIDs[appVersion.ID] || appVersion.Status == AppVersionStatusPublished
+		if published && p.KeepPublished {
+			continue
+		}
+		switch {
+		case p.KeepLast > 0 && counts[group] > p.KeepLast:
+			expired = append(expired, appVersion)
+		case p.DeleteUnpublishedAfterDays > 0 && !published &&
+			appVersion.CreatedAt.Before(now.Add(-time.Duration(p.DeleteUnpublishedAfterDays)*24*time.Hour)):
+			expired = append(expired, appVersion)
+		}
+	}
+	return expired
+}
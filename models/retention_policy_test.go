@@ -0,0 +1,77 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/c2fo/testify/require"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+func Test_RetentionPolicy_Validate(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		for _, retentionPolicy := range []models.RetentionPolicy{
+			{},
+			{KeepLast: 50, KeepPublished: true, DeleteUnpublishedAfterDays: 90, Action: "purge"},
+			{DeleteUnpublishedAfterDays: 30, Action: "archive"},
+		} {
+			require.Empty(t, retentionPolicy.Validate("gold"))
+		}
+	})
+
+	t.Run("when values are invalid", func(t *testing.T) {
+		retentionPolicy := models.RetentionPolicy{KeepLast: -1, DeleteUnpublishedAfterDays: -1, Action: "remove"}
+		require.Equal(t, []error{
+			errors.New("keep_last: Cannot be negative"),
+			errors.New("delete_unpublished_after_days: Cannot be negative"),
+			errors.New("action: Must be one of archive, purge"),
+		}, retentionPolicy.Validate("gold"))
+	})
+
+	t.Run("when the plan has a maximum", func(t *testing.T) {
+		require.Empty(t, models.RetentionPolicy{}.Validate("free"))
+		require.Empty(t, models.RetentionPolicy{KeepLast: 20}.Validate("free"))
+		for _, retentionPolicy := range []models.RetentionPolicy{{KeepLast: 21}, {DeleteUnpublishedAfterDays: 90}} {
+			require.Equal(t, []error{errors.New("keep_last: Must be between 1 and 20 on the free plan")}, retentionPolicy.Validate("free"))
+		}
+	})
+}
+
+func Test_RetentionPolicy_Expired(t *testing.T) {
+	now := time.Date(2019, 11, 21, 12, 0, 0, 0, time.UTC)
+	newAppVersion := func(platform, buildNumber, status string, daysAgo int) models.AppVersion {
+		return models.AppVersion{
+			Record:      models.Record{ID: uuid.NewV4(), CreatedAt: now.Add(-time.Duration(daysAgo) * 24 * time.Hour)},
+			Platform:    platform,
+			BuildNumber: buildNumber,
+			Status:      status,
+		}
+	}
+	ios1 := newAppVersion("ios", "1", "published", 100)
+	ios2 := newAppVersion("ios", "2", "failed", 95)
+	ios3 := newAppVersion("ios", "3", "publishing", 60)
+	ios4 := newAppVersion("ios", "4", "ready", 1)
+	android1 := newAppVersion("android", "1", "draft", 100)
+	// superseded by a newer published version
+	ios0 := newAppVersion("ios", "0", "superseded", 120)
+	appVersions := []models.AppVersion{ios4, android1, ios1, ios3, ios0, ios2}
+	publishedIDs := map[uuid.UUID]bool{ios0.ID: true}
+
+	for _, tc := range []struct {
+		name            string
+		retentionPolicy models.RetentionPolicy
+		expected        []models.AppVersion
+	}{
+		{name: "when the policy is disabled", retentionPolicy: models.RetentionPolicy{KeepPublished: true}, expected: []models.AppVersion{}},
+		{name: "keep last", retentionPolicy: models.RetentionPolicy{KeepLast: 1}, expected: []models.AppVersion{ios2, ios1, ios0}},
+		{name: "keep last and published", retentionPolicy: models.RetentionPolicy{KeepLast: 1, KeepPublished: true}, expected: []models.AppVersion{ios2}},
+		{name: "delete unpublished after days", retentionPolicy: models.RetentionPolicy{DeleteUnpublishedAfterDays: 90}, expected: []models.AppVersion{ios2, android1}},
+		{name: "combined", retentionPolicy: models.RetentionPolicy{KeepLast: 3, DeleteUnpublishedAfterDays: 90}, expected: []models.AppVersion{ios2, ios1, android1, ios0}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.retentionPolicy.Expired(appVersions, publishedIDs, now))
+		})
+	}
+}
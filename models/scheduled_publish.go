@@ -0,0 +1,84 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	// ScheduledPublishStatusScheduled ...
+	ScheduledPublishStatusScheduled = "scheduled"
+	// ScheduledPublishStatusStarted ...
+	ScheduledPublishStatusStarted = "started"
+	// ScheduledPublishStatusFailed ...
+	ScheduledPublishStatusFailed = "failed"
+	// ScheduledPublishStatusCanceled ...
+	ScheduledPublishStatusCanceled = "canceled"
+)
+
+// ScheduledPublish is a publish of an app version which is started by the
+// worker at ScheduledAt, with the destinations of the publish request
+type ScheduledPublish struct {
+	Record
+	ScheduledAt      time.Time       `db:"scheduled_at" json:"scheduled_at"`
+	DestinationsData json.RawMessage `db:"destinations" gorm:"column:destinations;type:json" json:"destinations"`
+	Status           string          `json:"status"`
+	FailureReason    string          `db:"failure_reason" json:"failure_reason,omitempty"`
+
+	AppVersionID uuid.UUID  `db:"app_version_id" json:"-"`
+	AppVersion   AppVersion `gorm:"foreignkey:AppVersionID" json:"-"`
+}
+
+// ScheduledPublishDestination is a destination and track the version gets
+// published to, an empty track is the default one of the destination
+type ScheduledPublishDestination struct {
+	ID    string `json:"id"`
+	Track string `json:"track"`
+}
+
+// BeforeCreate ...
+func (p *ScheduledPublish) BeforeCreate() error {
+	if uuid.Equal(p.ID, uuid.UUID{}) {
+		p.ID = uuid.NewV4()
+	}
+	if p.Status == "" {
+		p.Status = ScheduledPublishStatusScheduled
+	}
+	if p.DestinationsData == nil {
+		p.DestinationsData = json.RawMessage(`[]`)
+	}
+	return nil
+}
+
+// Destinations ...
+func (p *ScheduledPublish) Destinations() ([]ScheduledPublishDestination, error) {
+	destinations := []ScheduledPublishDestination{}
+	if len(p.DestinationsData) == 0 {
+		return destinations, nil
+	}
+	if err := json.Unmarshal(p.DestinationsData, &destinations); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return destinations, nil
+}
+
+// SetDestinations ...
+func (p *ScheduledPublish) SetDestinations(destinations []ScheduledPublishDestination) error {
+	if destinations == nil {
+		destinations = []ScheduledPublishDestination{}
+	}
+	data, err := json.Marshal(destinations)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	p.DestinationsData = data
+	return nil
+}
+
+// Pending tells whether the publish is yet to be started
+func (p *ScheduledPublish) Pending() bool {
+	return p.Status == ScheduledPublishStatusScheduled
+}
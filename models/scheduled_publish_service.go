@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// ScheduledPublishService ...
+type ScheduledPublishService struct {
+	DB *gorm.DB
+	UpdatableModelService
+}
+
+// Create ...
+func (p *ScheduledPublishService) Create(scheduledPublish *ScheduledPublish) (*ScheduledPublish, error) {
+	result := p.DB.Create(scheduledPublish)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return scheduledPublish, nil
+}
+
+// Find ...
+func (p *ScheduledPublishService) Find(scheduledPublish *ScheduledPublish) (*ScheduledPublish, error) {
+	err := p.DB.Where(scheduledPublish).Preload("AppVersion").Preload("AppVersion.App").First(scheduledPublish).Error
+	if err != nil {
+		return nil, err
+	}
+	return scheduledPublish, nil
+}
+
+// FindAll returns the scheduled publishes of an app version, the earliest
+// first
+func (p *ScheduledPublishService) FindAll(appVersion *AppVersion) ([]ScheduledPublish, error) {
+	var scheduledPublishes []ScheduledPublish
+	err := p.DB.Where(map[string]interface{}{"app_version_id": appVersion.ID}).
+		Order("scheduled_at ASC").
+		Find(&scheduledPublishes).Error
+	if err != nil {
+		return nil, err
+	}
+	return scheduledPublishes, nil
+}
+
+// FindAllDue returns the publishes which are still scheduled and are due by the
+// given time, with their app versions and apps. The publishes of deprovisioned
+// apps are left out.
+func (p *ScheduledPublishService) FindAllDue(now time.Time) ([]ScheduledPublish, error) {
+	var scheduledPublishes []ScheduledPublish
+	err := p.DB.Joins("JOIN app_versions ON app_versions.id = scheduled_publishes.app_version_id").
+		Joins("JOIN apps ON apps.id = app_versions.app_id").
+		Where("scheduled_publishes.status = ? AND scheduled_publishes.scheduled_at <= ?", ScheduledPublishStatusScheduled, now).
+		Where("apps.deprovisioned_at IS NULL").
+		Preload("AppVersion").Preload("AppVersion.App").
+		Order("scheduled_publishes.scheduled_at ASC").
+		Find(&scheduledPublishes).Error
+	if err != nil {
+		return nil, err
+	}
+	return scheduledPublishes, nil
+}
+
+// Update ...
+func (p *ScheduledPublishService) Update(scheduledPublish *ScheduledPublish, whitelist []string) error {
+	updateData, err := p.UpdateData(*scheduledPublish, whitelist)
+	if err != nil {
+		return err
+	}
+	return p.DB.Model(scheduledPublish).Updates(updateData).Error
+}
@@ -0,0 +1,37 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/c2fo/testify/require"
+)
+
+func Test_ScheduledPublish_Destinations(t *testing.T) {
+	t.Run("when the destinations are set", func(t *testing.T) {
+		scheduledPublish := models.ScheduledPublish{}
+		require.NoError(t, scheduledPublish.SetDestinations([]models.ScheduledPublishDestination{{ID: "google-play", Track: "beta"}}))
+		require.Equal(t, `[{"id":"google-play","track":"beta"}]`, string(scheduledPublish.DestinationsData))
+
+		destinations, err := scheduledPublish.Destinations()
+		require.NoError(t, err)
+		require.Equal(t, []models.ScheduledPublishDestination{{ID: "google-play", Track: "beta"}}, destinations)
+	})
+
+	t.Run("when there are no destinations", func(t *testing.T) {
+		scheduledPublish := models.ScheduledPublish{}
+		require.NoError(t, scheduledPublish.SetDestinations(nil))
+		require.Equal(t, `[]`, string(scheduledPublish.DestinationsData))
+
+		destinations, err := (&models.ScheduledPublish{}).Destinations()
+		require.NoError(t, err)
+		require.Equal(t, []models.ScheduledPublishDestination{}, destinations)
+	})
+
+	t.Run("when the destinations are invalid", func(t *testing.T) {
+		scheduledPublish := models.ScheduledPublish{DestinationsData: json.RawMessage(`{"id":"google-play"}`)}
+		_, err := scheduledPublish.Destinations()
+		require.Error(t, err)
+	})
+}
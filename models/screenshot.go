@@ -45,14 +45,16 @@ func (s *Screenshot) BeforeSave(scope *gorm.Scope) error {
 }
 
 func (s *Screenshot) validate(scope *gorm.Scope) error {
-	var err error
+	return addValidationErrors(scope, s.Validate())
+}
+
+// Validate ...
+func (s *Screenshot) Validate() []error {
+	verrs := []error{}
 	if s.Filesize > MaxScreenshotFileByteSize {
-		err = scope.DB().AddError(NewValidationError("filesize: Must be smaller than 10 megabytes"))
+		verrs = append(verrs, errors.New("filesize: Must be smaller than 10 megabytes"))
 	}
-	if err != nil {
-		return errors.New("Validation failed")
-	}
-	return nil
+	return verrs
 }
 
 // AWSPath ...
@@ -0,0 +1,56 @@
+package models
+
+// ScreenshotSize is a device type and screen size the store of a platform
+// accepts screenshots of
+type ScreenshotSize struct {
+	DeviceType string `json:"device_type"`
+	ScreenSize string `json:"screen_size"`
+}
+
+// screenshotSizes are the screenshot sizes of the platforms, the screen size is
+// the key of the screenshots in the store config of the app version
+var screenshotSizes = map[string][]ScreenshotSize{
+	"ios": []ScreenshotSize{
+		{DeviceType: "iPhone", ScreenSize: "6.5 inch"},
+		{DeviceType: "iPhone", ScreenSize: "5.8 inch"},
+		{DeviceType: "iPhone", ScreenSize: "5.5 inch"},
+		{DeviceType: "iPhone", ScreenSize: "4.7 inch"},
+		{DeviceType: "iPhone", ScreenSize: "4 inch"},
+		{DeviceType: "iPhone", ScreenSize: "3.5 inch"},
+		{DeviceType: "iPad", ScreenSize: "12.9 inch"},
+		{DeviceType: "iPad", ScreenSize: "11 inch"},
+		{DeviceType: "iPad", ScreenSize: "10.5 inch"},
+		{DeviceType: "iPad", ScreenSize: "9.7 inch"},
+	},
+	"tvos": []ScreenshotSize{
+		{DeviceType: "Apple TV", ScreenSize: "3840x2160"},
+		{DeviceType: "Apple TV", ScreenSize: "1920x1080"},
+	},
+	"watchos": []ScreenshotSize{
+		{DeviceType: "Apple Watch", ScreenSize: "Series 4"},
+		{DeviceType: "Apple Watch", ScreenSize: "Series 3"},
+	},
+	"macos": []ScreenshotSize{
+		{DeviceType: "Mac", ScreenSize: "2880x1800"},
+		{DeviceType: "Mac", ScreenSize: "2560x1600"},
+		{DeviceType: "Mac", ScreenSize: "1440x900"},
+		{DeviceType: "Mac", ScreenSize: "1280x800"},
+	},
+	"android": []ScreenshotSize{
+		{DeviceType: "Phone", ScreenSize: "phone"},
+		{DeviceType: "Tablet", ScreenSize: "seven_inch"},
+		{DeviceType: "Tablet", ScreenSize: "ten_inch"},
+		{DeviceType: "TV", ScreenSize: "tv"},
+		{DeviceType: "Watch", ScreenSize: "wear"},
+	},
+}
+
+// ScreenshotSizes returns the screenshot sizes of a platform, or nil for an
+// unknown platform
+func ScreenshotSizes(platform string) []ScreenshotSize {
+	sizes, ok := screenshotSizes[platform]
+	if !ok {
+		return nil
+	}
+	return append([]ScreenshotSize{}, sizes...)
+}
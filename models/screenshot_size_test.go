@@ -0,0 +1,27 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/c2fo/testify/require"
+)
+
+func Test_ScreenshotSizes(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		require.Equal(t, []models.ScreenshotSize{
+			{DeviceType: "Apple TV", ScreenSize: "3840x2160"},
+			{DeviceType: "Apple TV", ScreenSize: "1920x1080"},
+		}, models.ScreenshotSizes("tvos"))
+	})
+
+	t.Run("every platform has screenshot sizes", func(t *testing.T) {
+		for _, platform := range append(models.ApplePlatforms, "android") {
+			require.NotEmpty(t, models.ScreenshotSizes(platform), platform)
+		}
+	})
+
+	t.Run("when platform is unknown", func(t *testing.T) {
+		require.Nil(t, models.ScreenshotSizes("windows"))
+	})
+}
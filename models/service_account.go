@@ -0,0 +1,62 @@
+package models
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// serviceAccountErrorPrefix marks the problems of the selected service account
+// file among the Android errors of an app
+const serviceAccountErrorPrefix = "Service account file: "
+
+// ServiceAccount is the JSON key file of a Google Play service account
+type ServiceAccount struct {
+	Type        string `json:"type"`
+	ProjectID   string `json:"project_id"`
+	PrivateKey  string `json:"private_key"`
+	ClientEmail string `json:"client_email"`
+}
+
+// ValidateServiceAccount parses the content of a service account key file, and
+// returns the problems which would make publishing to Google Play fail
+func ValidateServiceAccount(content []byte) []error {
+	var serviceAccount ServiceAccount
+	if err := json.Unmarshal(content, &serviceAccount); err != nil {
+		return []error{serviceAccountError("Invalid JSON")}
+	}
+	verrs := []error{}
+	if serviceAccount.Type != "service_account" {
+		verrs = append(verrs, serviceAccountError("type has to be service_account"))
+	}
+	if serviceAccount.ClientEmail == "" {
+		verrs = append(verrs, serviceAccountError("client_email cannot be empty"))
+	}
+	if serviceAccount.ProjectID == "" {
+		verrs = append(verrs, serviceAccountError("project_id cannot be empty"))
+	}
+	block, _ := pem.Decode([]byte(serviceAccount.PrivateKey))
+	if block == nil || block.Type != "PRIVATE KEY" {
+		verrs = append(verrs, serviceAccountError("private_key has to be a PEM encoded private key"))
+	} else if _, err := x509.ParsePKCS8PrivateKey(block.Bytes); err != nil {
+		verrs = append(verrs, serviceAccountError("private_key has to be a PEM encoded private key"))
+	}
+	return verrs
+}
+
+// ServiceAccountNotCheckedError is the problem of a service account file which
+// couldn't be downloaded to validate it
+func ServiceAccountNotCheckedError() error {
+	return serviceAccountError("Could not be checked, as it failed to download")
+}
+
+func serviceAccountError(msg string) error {
+	return errors.New(serviceAccountErrorPrefix + msg)
+}
+
+func isServiceAccountError(msg string) bool {
+	return strings.HasPrefix(msg, serviceAccountErrorPrefix)
+}
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// VersionNumber is a version string in a comparable form. It covers semantic
+// versions with prerelease tags (1.2.3-beta.1), the dot separated build numbers
+// of iOS versions (1.2.3) and the integer version codes of Android versions.
+type VersionNumber struct {
+	Numbers    []int64
+	Prerelease []string
+}
+
+// ParseVersionNumber parses a version string, ignoring the leading v and the
+// build metadata after a +
+func ParseVersionNumber(version string) (VersionNumber, error) {
+	trimmed := strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(version), "v"), "V")
+	if i := strings.Index(trimmed, "+"); i >= 0 {
+		trimmed = trimmed[:i]
+	}
+	versionNumber := VersionNumber{}
+	if i := strings.Index(trimmed, "-"); i >= 0 {
+		versionNumber.Prerelease = strings.Split(trimmed[i+1:], ".")
+		trimmed = trimmed[:i]
+		for _, identifier := range versionNumber.Prerelease {
+			if identifier == "" {
+				return VersionNumber{}, errors.Errorf("Invalid prerelease tag in version: %s", version)
+			}
+		}
+	}
+	for _, component := range strings.Split(trimmed, ".") {
+		number, err := strconv.ParseInt(component, 10, 64)
+		if err != nil || number < 0 {
+			return VersionNumber{}, errors.Errorf("Invalid version: %s", version)
+		}
+		versionNumber.Numbers = append(versionNumber.Numbers, number)
+	}
+	return versionNumber, nil
+}
+
+// Compare returns -1, 0 or 1 when the version is less than, equal to or greater
+// than the other one. The missing trailing numbers are taken as 0, so 1.2
+// equals 1.2.0, and a prerelease is less than the release it precedes.
+func (v VersionNumber) Compare(other VersionNumber) int {
+	for i := 0; i < len(v.Numbers) || i < len(other.Numbers); i++ {
+		var number, otherNumber int64
+		if i < len(v.Numbers) {
+			number = v.Numbers[i]
+		}
+		if i < len(other.Numbers) {
+			otherNumber = other.Numbers[i]
+		}
+		if number != otherNumber {
+			return compareInts(number, otherNumber)
+		}
+	}
+
+	switch {
+	case len(v.Prerelease) == 0 && len(other.Prerelease) == 0:
+		return 0
+	case len(v.Prerelease) == 0:
+		return 1
+	case len(other.Prerelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(v.Prerelease) && i < len(other.Prerelease); i++ {
+		if result := comparePrereleaseIdentifiers(v.Prerelease[i], other.Prerelease[i]); result != 0 {
+			return result
+		}
+	}
+	return compareInts(int64(len(v.Prerelease)), int64(len(other.Prerelease)))
+}
+
+// comparePrereleaseIdentifiers compares numeric identifiers numerically and
+// alphanumeric ones lexically, numeric identifiers are the lower ones
+func comparePrereleaseIdentifiers(identifier, other string) int {
+	number, err := strconv.ParseInt(identifier, 10, 64)
+	isNumeric := err == nil
+	otherNumber, err := strconv.ParseInt(other, 10, 64)
+	isOtherNumeric := err == nil
+	switch {
+	case isNumeric && isOtherNumeric:
+		return compareInts(number, otherNumber)
+	case isNumeric:
+		return -1
+	case isOtherNumeric:
+		return 1
+	}
+	return strings.Compare(identifier, other)
+}
+
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,61 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/c2fo/testify/require"
+)
+
+func Test_ParseVersionNumber(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		for _, tc := range []struct {
+			version  string
+			expected models.VersionNumber
+		}{
+			{version: "1.2.3", expected: models.VersionNumber{Numbers: []int64{1, 2, 3}}},
+			{version: "v1.2", expected: models.VersionNumber{Numbers: []int64{1, 2}}},
+			{version: "42", expected: models.VersionNumber{Numbers: []int64{42}}},
+			{version: "1.0.0-beta.1", expected: models.VersionNumber{Numbers: []int64{1, 0, 0}, Prerelease: []string{"beta", "1"}}},
+			{version: "1.0.0+20191118", expected: models.VersionNumber{Numbers: []int64{1, 0, 0}}},
+		} {
+			versionNumber, err := models.ParseVersionNumber(tc.version)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, versionNumber)
+		}
+	})
+
+	t.Run("when version is invalid", func(t *testing.T) {
+		for _, version := range []string{"", "1.a", "1..2", "1.-2", "1.0.0-", "1.0.0-beta..1"} {
+			_, err := models.ParseVersionNumber(version)
+			require.Error(t, err, version)
+		}
+	})
+}
+
+func Test_VersionNumber_Compare(t *testing.T) {
+	for _, tc := range []struct {
+		version  string
+		other    string
+		expected int
+	}{
+		{version: "1.2.3", other: "1.2.3", expected: 0},
+		{version: "1.2", other: "1.2.0", expected: 0},
+		{version: "1.10.0", other: "1.9.0", expected: 1},
+		{version: "2", other: "10", expected: -1},
+		{version: "1.0.0-beta", other: "1.0.0", expected: -1},
+		{version: "1.0.0-alpha", other: "1.0.0-beta", expected: -1},
+		{version: "1.0.0-beta.2", other: "1.0.0-beta.11", expected: -1},
+		{version: "1.0.0-beta.1", other: "1.0.0-beta", expected: 1},
+		{version: "1.0.0-1", other: "1.0.0-alpha", expected: -1},
+	} {
+		t.Run(tc.version+" compared to "+tc.other, func(t *testing.T) {
+			version, err := models.ParseVersionNumber(tc.version)
+			require.NoError(t, err)
+			other, err := models.ParseVersionNumber(tc.other)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, version.Compare(other))
+			require.Equal(t, -tc.expected, other.Compare(version))
+		})
+	}
+}
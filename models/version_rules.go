@@ -0,0 +1,81 @@
+package models
+
+import (
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// VersionRules decide which builds of a platform create app versions.
+// Workflows are exact workflow names or glob patterns, Branch is a glob pattern
+// of the branch, and TagsOnly only lets the tagged builds through. An empty
+// rule doesn't restrict the builds.
+type VersionRules struct {
+	Workflows []string `json:"workflows"`
+	Branch    string   `json:"branch"`
+	TagsOnly  bool     `json:"tags_only"`
+}
+
+// Matches ...
+func (r VersionRules) Matches(workflow, branch, tag string) bool {
+	if r.TagsOnly && tag == "" {
+		return false
+	}
+	if r.Branch != "" && !matchesPattern(r.Branch, branch) {
+		return false
+	}
+	if len(r.Workflows) == 0 {
+		return true
+	}
+	for _, pattern := range r.Workflows {
+		if matchesPattern(pattern, workflow) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate ...
+func (r VersionRules) Validate(field string) []error {
+	verrs := []error{}
+	for _, pattern := range r.Workflows {
+		if strings.TrimSpace(pattern) == "" {
+			verrs = append(verrs, errors.Errorf("%s.workflows: Cannot contain an empty pattern", field))
+		} else if _, err := path.Match(pattern, ""); err != nil {
+			verrs = append(verrs, errors.Errorf("%s.workflows: Invalid pattern %s", field, pattern))
+		}
+	}
+	if _, err := path.Match(r.Branch, ""); err != nil {
+		verrs = append(verrs, errors.Errorf("%s.branch: Invalid pattern %s", field, r.Branch))
+	}
+	return verrs
+}
+
+// AppVersionRules are the version rules of the platforms of an app
+type AppVersionRules struct {
+	Ios     VersionRules `json:"ios"`
+	Android VersionRules `json:"android"`
+}
+
+// Validate ...
+func (r AppVersionRules) Validate() []error {
+	return append(r.Ios.Validate("version_rules.ios"), r.Android.Validate("version_rules.android")...)
+}
+
+func matchesPattern(pattern, name string) bool {
+	matched, err := path.Match(pattern, name)
+	return err == nil && matched
+}
+
+// workflowsFromWhitelist splits the comma separated workflow whitelist, which
+// was used before the version rules
+func workflowsFromWhitelist(whitelist string) []string {
+	var workflows []string
+	for _, workflow := range strings.Split(whitelist, ",") {
+		if workflow = strings.TrimSpace(workflow); workflow != "" {
+			workflows = append(workflows, workflow)
+		}
+	}
+	return workflows
+}
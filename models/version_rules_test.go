@@ -0,0 +1,72 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/c2fo/testify/require"
+	"github.com/pkg/errors"
+)
+
+func Test_VersionRules_Matches(t *testing.T) {
+	for _, tc := range []struct {
+		name                  string
+		versionRules          models.VersionRules
+		workflow, branch, tag string
+		expected              bool
+	}{
+		{name: "when the rules are empty", versionRules: models.VersionRules{}, workflow: "primary", expected: true},
+		{name: "exact workflow", versionRules: models.VersionRules{Workflows: []string{"deploy"}}, workflow: "deploy", expected: true},
+		{name: "workflow which contains a whitelisted one", versionRules: models.VersionRules{Workflows: []string{"deploy"}}, workflow: "deploy-staging", expected: false},
+		{name: "workflow pattern", versionRules: models.VersionRules{Workflows: []string{"primary", "deploy-*"}}, workflow: "deploy-staging", expected: true},
+		{name: "branch pattern", versionRules: models.VersionRules{Branch: "release/*"}, workflow: "deploy", branch: "release/1.2", expected: true},
+		{name: "branch which doesn't match", versionRules: models.VersionRules{Branch: "release/*"}, workflow: "deploy", branch: "master", expected: false},
+		{name: "tagged build", versionRules: models.VersionRules{TagsOnly: true}, workflow: "deploy", tag: "1.2.0", expected: true},
+		{name: "build without tag", versionRules: models.VersionRules{TagsOnly: true}, workflow: "deploy", branch: "master", expected: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.versionRules.Matches(tc.workflow, tc.branch, tc.tag))
+		})
+	}
+}
+
+func Test_AppVersionRules_Validate(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		versionRules := models.AppVersionRules{
+			Ios:     models.VersionRules{Workflows: []string{"deploy", "release-*"}, Branch: "release/*"},
+			Android: models.VersionRules{TagsOnly: true},
+		}
+		require.Empty(t, versionRules.Validate())
+	})
+
+	t.Run("when patterns are invalid", func(t *testing.T) {
+		versionRules := models.AppVersionRules{
+			Ios:     models.VersionRules{Workflows: []string{" ", "deploy-[a"}},
+			Android: models.VersionRules{Branch: "release/[a"},
+		}
+		require.Equal(t, []error{
+			errors.New("version_rules.ios.workflows: Cannot contain an empty pattern"),
+			errors.New("version_rules.ios.workflows: Invalid pattern deploy-[a"),
+			errors.New("version_rules.android.branch: Invalid pattern release/[a"),
+		}, versionRules.Validate())
+	})
+}
+
+func Test_AppSettings_VersionRules(t *testing.T) {
+	t.Run("when the rules have no workflow list", func(t *testing.T) {
+		appSettings := models.AppSettings{IosWorkflow: "deploy, release", VersionRulesData: json.RawMessage(`{"ios":{"branch":"master"}}`)}
+		versionRules, err := appSettings.VersionRules()
+		require.NoError(t, err)
+		require.Equal(t, models.AppVersionRules{
+			Ios: models.VersionRules{Workflows: []string{"deploy", "release"}, Branch: "master"},
+		}, versionRules)
+	})
+
+	t.Run("when the rules have a workflow list", func(t *testing.T) {
+		appSettings := models.AppSettings{IosWorkflow: "deploy", VersionRulesData: json.RawMessage(`{"ios":{"workflows":[]}}`)}
+		versionRules, err := appSettings.VersionRules()
+		require.NoError(t, err)
+		require.Equal(t, models.AppVersionRules{Ios: models.VersionRules{Workflows: []string{}}}, versionRules)
+	})
+}
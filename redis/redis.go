@@ -17,6 +17,7 @@ type Interface interface {
 	GetString(string) (string, error)
 	GetInt64(key string) (int64, error)
 	Set(string, interface{}, int) error
+	Delete(keys ...string) error
 }
 
 // Client ...
@@ -96,6 +97,23 @@ func (c *Client) GetInt64(key string) (int64, error) {
 	return value, conn.Close()
 }
 
+// Delete ...
+func (c *Client) Delete(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	conn := c.pool.Get()
+	args := make([]interface{}, len(keys))
+	for i, key := range keys {
+		args[i] = key
+	}
+	_, err := conn.Do("DEL", args...)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
 // DialURL ...
 func DialURL(urlToParse string) (string, error) {
 	if !strings.HasPrefix(urlToParse, "redis://") {
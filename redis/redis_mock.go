@@ -5,6 +5,7 @@ type Mock struct {
 	GetStringFn func(string) (string, error)
 	GetInt64Fn  func(string) (int64, error)
 	SetFn       func(string, interface{}, int) error
+	DeleteFn    func(...string) error
 }
 
 // GetString ...
@@ -30,3 +31,11 @@ func (m *Mock) Set(key string, value interface{}, ttl int) error {
 	}
 	return m.SetFn(key, value, ttl)
 }
+
+// Delete ...
+func (m *Mock) Delete(keys ...string) error {
+	if m.DeleteFn == nil {
+		panic("You have to override Delete function in tests")
+	}
+	return m.DeleteFn(keys...)
+}
@@ -10,17 +10,19 @@ import (
 	"gopkg.in/DataDog/dd-trace-go.v1/contrib/gorilla/mux"
 )
 
+type route struct {
+	path           string
+	middleware     alice.Chain
+	handler        func(e *env.AppEnv, w http.ResponseWriter, r *http.Request) error
+	allowedMethods []string
+}
+
 // New ...
 func New(appEnv *env.AppEnv) *mux.Router {
 	// StrictSlash: allow "trim slash"; /x/ REDIRECTS to /x
 	r := mux.NewRouter(mux.WithServiceName("addons-ship-mux")).StrictSlash(true)
 
-	for _, route := range []struct {
-		path           string
-		middleware     alice.Chain
-		handler        func(e *env.AppEnv, w http.ResponseWriter, r *http.Request) error
-		allowedMethods []string
-	}{
+	routes := []route{
 		{
 			path: "/", middleware: services.CommonMiddleware(appEnv),
 			handler: services.RootHandler, allowedMethods: []string{"GET", "OPTIONS"},
@@ -41,6 +43,10 @@ func New(appEnv *env.AppEnv) *mux.Router {
 			path: "/apps/{app-slug}/versions", middleware: services.AuthorizedAppMiddleware(appEnv),
 			handler: services.AppVersionsGetHandler, allowedMethods: []string{"GET", "OPTIONS"},
 		},
+		{
+			path: "/apps/{app-slug}/versions/import", middleware: services.AuthorizedAppMiddleware(appEnv),
+			handler: services.AppVersionImportPostHandler, allowedMethods: []string{"POST", "OPTIONS"},
+		},
 		{
 			path: "/apps/{app-slug}/versions/{version-id}", middleware: services.AuthorizedAppVersionMiddleware(appEnv),
 			handler: services.AppVersionGetHandler, allowedMethods: []string{"GET", "OPTIONS"},
@@ -49,10 +55,54 @@ func New(appEnv *env.AppEnv) *mux.Router {
 			path: "/apps/{app-slug}/versions/{version-id}", middleware: services.AuthorizedAppVersionMiddleware(appEnv),
 			handler: services.AppVersionPutHandler, allowedMethods: []string{"PUT", "OPTIONS"},
 		},
+		{
+			path: "/apps/{app-slug}/versions/{version-id}", middleware: services.AuthorizedAppVersionMiddleware(appEnv),
+			handler: services.AppVersionDeleteHandler, allowedMethods: []string{"DELETE", "OPTIONS"},
+		},
+		{
+			path: "/apps/{app-slug}/versions/{version-id}/archive", middleware: services.AuthorizedAppVersionMiddleware(appEnv),
+			handler: services.AppVersionArchivePostHandler, allowedMethods: []string{"POST", "OPTIONS"},
+		},
 		{
 			path: "/apps/{app-slug}/versions/{version-id}/publish", middleware: services.AuthorizedAppVersionMiddleware(appEnv),
 			handler: services.AppVersionPublishPostHandler, allowedMethods: []string{"POST", "OPTIONS"},
 		},
+		{
+			path: "/apps/{app-slug}/versions/{version-id}/scheduled-publishes", middleware: services.AuthorizedAppVersionMiddleware(appEnv),
+			handler: services.AppVersionScheduledPublishesGetHandler, allowedMethods: []string{"GET", "OPTIONS"},
+		},
+		{
+			path: "/apps/{app-slug}/versions/{version-id}/scheduled-publishes/{scheduled-publish-id}", middleware: services.AuthorizedAppVersionScheduledPublishMiddleware(appEnv),
+			handler: services.AppVersionScheduledPublishDeleteHandler, allowedMethods: []string{"DELETE", "OPTIONS"},
+		},
+		{
+			path: "/apps/{app-slug}/versions/{version-id}/approvals", middleware: services.AuthorizedAppVersionMiddleware(appEnv),
+			handler: services.AppVersionApprovalsGetHandler, allowedMethods: []string{"GET", "OPTIONS"},
+		},
+		{
+			path: "/apps/{app-slug}/versions/{version-id}/approval-requests", middleware: services.AuthorizedAppVersionMiddleware(appEnv),
+			handler: services.AppVersionApprovalRequestsPostHandler, allowedMethods: []string{"POST", "OPTIONS"},
+		},
+		{
+			path: "/apps/{app-slug}/versions/{version-id}/rollout/increase", middleware: services.AuthorizedAppVersionMiddleware(appEnv),
+			handler: services.AppVersionRolloutIncreasePostHandler, allowedMethods: []string{"POST", "OPTIONS"},
+		},
+		{
+			path: "/apps/{app-slug}/versions/{version-id}/rollout/halt", middleware: services.AuthorizedAppVersionMiddleware(appEnv),
+			handler: services.AppVersionRolloutHaltPostHandler, allowedMethods: []string{"POST", "OPTIONS"},
+		},
+		{
+			path: "/apps/{app-slug}/versions/{version-id}/rollout/complete", middleware: services.AuthorizedAppVersionMiddleware(appEnv),
+			handler: services.AppVersionRolloutCompletePostHandler, allowedMethods: []string{"POST", "OPTIONS"},
+		},
+		{
+			path: "/apps/{app-slug}/versions/{version-id}/promote", middleware: services.AuthorizedAppVersionMiddleware(appEnv),
+			handler: services.AppVersionPromotePostHandler, allowedMethods: []string{"POST", "OPTIONS"},
+		},
+		{
+			path: "/apps/{app-slug}/versions/{version-id}/track-history", middleware: services.AuthorizedAppVersionMiddleware(appEnv),
+			handler: services.AppVersionTrackHistoryGetHandler, allowedMethods: []string{"GET", "OPTIONS"},
+		},
 		{
 			path: "/apps/{app-slug}/versions/{version-id}/screenshots", middleware: services.AuthorizedAppVersionMiddleware(appEnv),
 			handler: services.ScreenshotsGetHandler, allowedMethods: []string{"GET", "OPTIONS"},
@@ -85,14 +135,6 @@ func New(appEnv *env.AppEnv) *mux.Router {
 			path: "/apps/{app-slug}/versions/{version-id}/feature-graphic", middleware: services.AuthorizedAppVersionMiddleware(appEnv),
 			handler: services.FeatureGraphicDeleteHandler, allowedMethods: []string{"DELETE", "OPTIONS"},
 		},
-		{
-			path: "/apps/{app-slug}/versions/{version-id}/android-config", middleware: services.AuthorizedAppVersionMiddleware(appEnv),
-			handler: services.AppVersionAndroidConfigGetHandler, allowedMethods: []string{"GET", "OPTIONS"},
-		},
-		{
-			path: "/apps/{app-slug}/versions/{version-id}/ios-config", middleware: services.AuthorizedAppVersionMiddleware(appEnv),
-			handler: services.AppVersionIosConfigGetHandler, allowedMethods: []string{"GET", "OPTIONS"},
-		},
 		{
 			path: "/apps/{app-slug}/settings", middleware: services.AuthorizedAppMiddleware(appEnv),
 			handler: services.AppSettingsGetHandler, allowedMethods: []string{"GET", "OPTIONS"},
@@ -109,6 +151,14 @@ func New(appEnv *env.AppEnv) *mux.Router {
 			path: "/confirm_email", middleware: services.AuthorizeForAppContactEmailConfirmationHandling(appEnv),
 			handler: services.AppContactConfirmPatchHandler, allowedMethods: []string{"PATCH", "OPTIONS"},
 		},
+		{
+			path: "/approve", middleware: services.AuthorizeForApprovalHandling(appEnv),
+			handler: services.AppVersionApprovalPostHandler, allowedMethods: []string{"POST", "OPTIONS"},
+		},
+		{
+			path: "/approve/revoke", middleware: services.AuthorizeForApprovalHandling(appEnv),
+			handler: services.AppVersionApprovalRevokePostHandler, allowedMethods: []string{"POST", "OPTIONS"},
+		},
 		{
 			path: "/apps/{app-slug}/contacts", middleware: services.AuthorizedAppMiddleware(appEnv),
 			handler: services.AppContactPostHandler, allowedMethods: []string{"POST", "OPTIONS"},
@@ -141,7 +191,35 @@ func New(appEnv *env.AppEnv) *mux.Router {
 			path: `/resources/{rest:[a-zA-Z0-9=\-\/]+}`, middleware: services.AuthorizedAppResourceMiddleware(appEnv),
 			handler: services.ResourcesHandler, allowedMethods: []string{"GET", "OPTIONS"},
 		},
-	} {
+		{
+			path: "/apps/{app-slug}/releases", middleware: services.AuthorizedAppMiddleware(appEnv),
+			handler: services.ReleasesGetHandler, allowedMethods: []string{"GET", "OPTIONS"},
+		},
+		{
+			path: "/apps/{app-slug}/releases/{release-id}", middleware: services.AuthorizedReleaseMiddleware(appEnv),
+			handler: services.ReleasePatchHandler, allowedMethods: []string{"PATCH", "OPTIONS"},
+		},
+		{
+			path: "/apps/{app-slug}/releases/{release-id}/publish", middleware: services.AuthorizedReleaseMiddleware(appEnv),
+			handler: services.ReleasePublishPostHandler, allowedMethods: []string{"POST", "OPTIONS"},
+		},
+		{
+			path: "/apps/{app-slug}/rollback", middleware: services.AuthorizedAppMiddleware(appEnv),
+			handler: services.AppRollbackPostHandler, allowedMethods: []string{"POST", "OPTIONS"},
+		},
+		{
+			path: "/apps/{app-slug}/publish-destinations", middleware: services.AuthorizedAppMiddleware(appEnv),
+			handler: services.PublishDestinationsGetHandler, allowedMethods: []string{"GET", "OPTIONS"},
+		},
+	}
+	for _, destination := range services.PublishDestinations() {
+		routes = append(routes, route{
+			path: "/apps/{app-slug}/versions/{version-id}/" + destination.ConfigPath(), middleware: services.AuthorizedAppVersionMiddleware(appEnv),
+			handler: destination.ConfigHandler, allowedMethods: []string{"GET", "OPTIONS"},
+		})
+	}
+
+	for _, route := range routes {
 		r.Handle(route.path, route.middleware.Then(services.Handler{Env: appEnv, H: route.handler})).
 			Methods(route.allowedMethods...)
 	}
@@ -3,8 +3,9 @@ package services_test
 import uuid "github.com/satori/go.uuid"
 
 type testAnalyticsClient struct {
-	firstVersionCreatedFn func(appSlug, buildSlug, platform string)
-	publishFinishedFn     func(appSlug string, appVersionID uuid.UUID, result string)
+	firstVersionCreatedFn     func(appSlug, buildSlug, platform string)
+	publishFinishedFn         func(appSlug string, appVersionID uuid.UUID, result string)
+	codeSigningFileExpiringFn func(appSlug, fileSlug string, daysLeft int)
 }
 
 func (c *testAnalyticsClient) FirstVersionCreated(appSlug, buildSlug, platform string) {
@@ -20,3 +21,10 @@ func (c *testAnalyticsClient) PublishFinished(appSlug string, appVersionID uuid.
 	}
 	c.publishFinishedFn(appSlug, appVersionID, result)
 }
+
+func (c *testAnalyticsClient) CodeSigningFileExpiring(appSlug, fileSlug string, daysLeft int) {
+	if c.codeSigningFileExpiringFn == nil {
+		panic("You have to override the CodeSigningFileExpiring function in tests")
+	}
+	c.codeSigningFileExpiringFn(appSlug, fileSlug, daysLeft)
+}
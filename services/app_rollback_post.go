@@ -0,0 +1,275 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/api-utils/httprequest"
+	"github.com/bitrise-io/api-utils/httpresponse"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// AppRollbackParams ...
+type AppRollbackParams struct {
+	Platform      string `json:"platform"`
+	ProductFlavor string `json:"product_flavor"`
+	BundleID      string `json:"bundle_id"`
+}
+
+// AppRollbackResponseData ...
+type AppRollbackResponseData struct {
+	// RolledBackVersion is the version which was published the last
+	RolledBackVersion *models.AppVersion `json:"rolled_back_version"`
+	// AppVersion is the version published before it, which the users get again
+	AppVersion   *models.AppVersion   `json:"app_version"`
+	Destination  string               `json:"destination"`
+	PublishTasks []models.PublishTask `json:"publish_tasks"`
+	// Alternatives explain how the release can be rolled back, when it can't
+	// be rolled back by Ship
+	Alternatives []string `json:"alternatives"`
+}
+
+// AppRollbackResponse ...
+type AppRollbackResponse struct {
+	Data AppRollbackResponseData `json:"data"`
+}
+
+// AppRollbackPostHandler rolls back the release of a platform and flavor of an
+// app to the version published before it. When the last version is being
+// rolled out on Google Play, its rollout gets halted, so the users get the
+// previous release of the track again. Otherwise the previous version gets
+// published again to the default destination of the platform, without checking
+// the approvals and the build code, as it was already published. When the
+// destination doesn't accept a version it has already got, nothing is
+// published, and the ways of rolling back by hand are returned instead.
+func AppRollbackPostHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Request) error {
+	authorizedAppID, err := GetAuthorizedAppIDFromContext(r.Context())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if env.AppVersionService == nil {
+		return errors.New("No App Version Service defined for handler")
+	}
+	if env.AppSettingsService == nil {
+		return errors.New("No App Settings Service defined for handler")
+	}
+	if env.PublishTaskService == nil {
+		return errors.New("No Publish Task Service defined for handler")
+	}
+	if env.AppVersionEventService == nil {
+		return errors.New("No App Version Event Service defined for handler")
+	}
+	if env.BitriseAPI == nil {
+		return errors.New("No Bitrise API Service defined for handler")
+	}
+
+	var params AppRollbackParams
+	defer httprequest.BodyCloseWithErrorLog(r)
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		return httpresponse.RespondWithBadRequestError(w, "Invalid request body, JSON decode failed")
+	}
+	if params.Platform == "" {
+		return httpresponse.RespondWithUnprocessableEntity(w, []error{errors.New("platform: Cannot be empty")})
+	}
+	destination := publishDestinationForPlatform(params.Platform)
+	if destination == nil {
+		return httpresponse.RespondWithBadRequestError(w, fmt.Sprintf("No publish destination for platform: %s", params.Platform))
+	}
+
+	appSettings, err := env.AppSettingsService.Find(&models.AppSettings{AppID: authorizedAppID})
+	switch {
+	case errors.Cause(err) == gorm.ErrRecordNotFound:
+		return httpresponse.RespondWithNotFoundError(w)
+	case err != nil:
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	appPublishTasks, err := env.PublishTaskService.FindAllForApp(&models.App{Record: models.Record{ID: authorizedAppID}})
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+	current, previous, err := rollbackPublishTasks(appPublishTasks, destination, params)
+	if err != nil {
+		return httpresponse.RespondWithBadRequestError(w, err.Error())
+	}
+
+	previousVersion, err := env.AppVersionService.Find(&models.AppVersion{Record: models.Record{ID: previous.AppVersionID}})
+	switch {
+	case errors.Cause(err) == gorm.ErrRecordNotFound:
+		return httpresponse.RespondWithNotFoundError(w)
+	case err != nil:
+		return errors.Wrap(err, "SQL Error")
+	}
+	currentVersion := current.AppVersion
+	response := AppRollbackResponseData{
+		RolledBackVersion: &currentVersion,
+		AppVersion:        previousVersion,
+		Destination:       destination.ID(),
+		PublishTasks:      []models.PublishTask{},
+		Alternatives:      []string{},
+	}
+
+	if destination.ID() == googlePlayDestinationID {
+		track, rollout, err := rollbackRollout(current, appPublishTasks, appSettings)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if rollout != nil {
+			rolledBackVersion, err := env.AppVersionService.Find(&models.AppVersion{Record: models.Record{ID: current.AppVersionID}})
+			switch {
+			case errors.Cause(err) == gorm.ErrRecordNotFound:
+				return httpresponse.RespondWithNotFoundError(w)
+			case err != nil:
+				return errors.Wrap(err, "SQL Error")
+			}
+			publishTask, err := triggerRolloutUpdate(env, rolledBackVersion, appSettings, track, *rollout)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			response.PublishTasks = []models.PublishTask{*publishTask}
+			_, err = env.AppVersionEventService.Create(&models.AppVersionEvent{
+				Status: models.PublishTaskStatusInProgress,
+				Text: fmt.Sprintf("Rolling back to version %s by halting the rollout on %s track",
+					appVersionLabel(previousVersion), track),
+				AppVersionID: current.AppVersionID,
+			})
+			if err != nil {
+				return errors.Wrap(err, "SQL Error")
+			}
+			return httpresponse.RespondWithSuccess(w, AppRollbackResponse{Data: response})
+		}
+	}
+
+	if !destination.AllowsReupload() {
+		response.Alternatives = rollbackAlternatives(destination, &currentVersion, previousVersion)
+		_, err = env.AppVersionEventService.Create(&models.AppVersionEvent{
+			Text: fmt.Sprintf("Rollback to version %s isn't possible, %s doesn't accept a version it has already got",
+				appVersionLabel(previousVersion), destination.ID()),
+			AppVersionID: current.AppVersionID,
+		})
+		if err != nil {
+			return errors.Wrap(err, "SQL Error")
+		}
+		return httpresponse.RespondWithSuccess(w, AppRollbackResponse{Data: response})
+	}
+
+	targets := []publishTarget{{destination: destination, track: previous.Track}}
+	_, response.PublishTasks, err = publishAppVersion(env, previousVersion, appSettings, targets, uuid.NewV4())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	for _, event := range []models.AppVersionEvent{
+		{Text: fmt.Sprintf("Rolled back to version %s", appVersionLabel(previousVersion)), AppVersionID: current.AppVersionID},
+		{
+			Status:       models.PublishTaskStatusInProgress,
+			Text:         fmt.Sprintf("Publishing again as the rollback of version %s", appVersionLabel(&currentVersion)),
+			AppVersionID: previousVersion.ID,
+		},
+	} {
+		event := event
+		if _, err := env.AppVersionEventService.Create(&event); err != nil {
+			return errors.Wrap(err, "SQL Error")
+		}
+	}
+
+	return httpresponse.RespondWithSuccess(w, AppRollbackResponse{Data: response})
+}
+
+// rollbackPublishTasks returns the last successful publish task of the platform
+// and flavor to the destination, and the last successful one of another version
+// before the first publish of the same version. So the version which got rolled
+// back isn't published again by the next rollback.
+func rollbackPublishTasks(appPublishTasks []models.PublishTask, destination PublishDestination, params AppRollbackParams) (models.PublishTask, models.PublishTask, error) {
+	published := []models.PublishTask{}
+	for _, publishTask := range appPublishTasks {
+		appVersion := publishTask.AppVersion
+		if publishTask.Action == models.PublishTaskActionRollout || appVersion.Platform != params.Platform ||
+			appVersion.ProductFlavor != params.ProductFlavor || appVersion.BundleID != params.BundleID ||
+			publishTaskDestinationID(&appVersion, publishTask) != destination.ID() {
+			continue
+		}
+		if publishTask.Status != "" && !publishTask.Finished() {
+			return models.PublishTask{}, models.PublishTask{}, errors.New("A publish task of the platform is still running")
+		}
+		if publishTask.Status == models.PublishTaskStatusSuccess {
+			published = append(published, publishTask)
+		}
+	}
+	if len(published) == 0 {
+		return models.PublishTask{}, models.PublishTask{}, errors.New("No version of the platform has been published yet")
+	}
+
+	current := published[len(published)-1]
+	for i, publishTask := range published {
+		if uuid.Equal(publishTask.AppVersionID, current.AppVersionID) {
+			if i == 0 {
+				break
+			}
+			return current, published[i-1], nil
+		}
+	}
+	return models.PublishTask{}, models.PublishTask{}, errors.New("No version was published before the last published one")
+}
+
+// rollbackRollout returns the track and the halted rollout of the Google Play
+// release of the rolled back version, when its rollout is in progress, as
+// halting it serves the previous release of the track to the users again. No
+// rollout is returned when the release has been rolled out completely.
+func rollbackRollout(current models.PublishTask, appPublishTasks []models.PublishTask, appSettings *models.AppSettings) (string, *models.AndroidRollout, error) {
+	androidSettings, err := publishAndroidSettings(appSettings)
+	if err != nil {
+		return "", nil, errors.WithStack(err)
+	}
+	publishTasks := []models.PublishTask{}
+	for _, publishTask := range appPublishTasks {
+		if uuid.Equal(publishTask.AppVersionID, current.AppVersionID) {
+			publishTasks = append(publishTasks, publishTask)
+		}
+	}
+	currentVersion := current.AppVersion
+	track := publishTaskTrack(current, androidSettings.Track)
+	release, err := currentPublishedRelease(&currentVersion, publishTasks, track, androidSettings.Track)
+	if err != nil || release.Rollout().ReleaseStatus != models.ReleaseStatusInProgress {
+		return "", nil, nil
+	}
+	rollout, err := release.Rollout().Halt()
+	if err != nil {
+		return "", nil, errors.WithStack(err)
+	}
+	return track, &rollout, nil
+}
+
+// rollbackAlternatives explain how a release can be rolled back by hand, when
+// the destination doesn't allow publishing the previous version again
+func rollbackAlternatives(destination PublishDestination, currentVersion, previousVersion *models.AppVersion) []string {
+	alternatives := []string{}
+	if _, ok := destination.(*appStoreConnectDestination); ok {
+		alternatives = append(alternatives, fmt.Sprintf("Submit the build of version %s for review again in App Store Connect, as long as it hasn't expired",
+			appVersionLabel(previousVersion)))
+	}
+	alternatives = append(alternatives, fmt.Sprintf("Build version %s again with a %s greater than the one of version %s, and publish it",
+		appVersionLabel(previousVersion), strings.ToLower(buildCodeName(previousVersion)), appVersionLabel(currentVersion)))
+	return alternatives
+}
+
+// appVersionLabel returns the version and the build code of an app version,
+// the way the stores show them
+func appVersionLabel(appVersion *models.AppVersion) string {
+	artifactInfo, err := appVersion.ArtifactInfo()
+	if err != nil {
+		return appVersion.BuildNumber
+	}
+	buildCode, err := appVersion.BuildCode()
+	if err != nil {
+		return artifactInfo.Version
+	}
+	return fmt.Sprintf("%s (%s)", artifactInfo.Version, buildCode)
+}
@@ -0,0 +1,360 @@
+package services_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/bitrise-io/addons-ship-backend/bitrise"
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/addons-ship-backend/services"
+	ctxpkg "github.com/bitrise-io/api-utils/context"
+	"github.com/bitrise-io/api-utils/httpresponse"
+	"github.com/bitrise-io/api-utils/security"
+	"github.com/c2fo/testify/require"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+func Test_AppRollbackPostHandler(t *testing.T) {
+	httpMethod := "POST"
+	url := "/apps/{app-slug}/rollback"
+	handler := services.AppRollbackPostHandler
+
+	testAppID := uuid.FromStringOrNil("211afc15-127a-40f9-8cbe-1dadc1f86cdf")
+	newAppVersion := func(id, platform, version, buildNumber, versionCode string) models.AppVersion {
+		return models.AppVersion{
+			Record:           models.Record{ID: uuid.FromStringOrNil(id)},
+			Platform:         platform,
+			BuildNumber:      buildNumber,
+			ArtifactInfoData: json.RawMessage(`{"version":"` + version + `","version_code":"` + versionCode + `"}`),
+			Status:           models.AppVersionStatusPublished,
+		}
+	}
+	testIosVersion1 := newAppVersion("de438ddc-98e5-4226-a5f4-fd2d53474879", "ios", "1.0.0", "10", "")
+	testIosVersion2 := newAppVersion("4e2e0e38-4b5d-4d2e-9e0e-6bc7f4d4ab5e", "ios", "1.1.0", "11", "")
+	testAndroidVersion1 := newAppVersion("8d4f2b5e-2d3c-4c8a-9a44-1f9c3c2c5f10", "android", "1.0.0", "30", "30")
+	testAndroidVersion2 := newAppVersion("0c7d5a8e-7f1b-4b43-8f4e-54ad2c7ee0a2", "android", "1.1.0", "31", "31")
+
+	behavesAsServiceCravingHandler(t, httpMethod, url, handler, []string{"AppVersionService", "AppSettingsService", "PublishTaskService", "AppVersionEventService", "BitriseAPI"}, ControllerTestCase{
+		contextElements: map[ctxpkg.RequestContextKey]interface{}{
+			services.ContextKeyAuthorizedAppID: testAppID,
+		},
+		env: &env.AppEnv{
+			AppVersionService:      &testAppVersionService{},
+			AppSettingsService:     &testAppSettingsService{},
+			PublishTaskService:     &testPublishTaskService{},
+			AppVersionEventService: &testAppVersionEventService{},
+			BitriseAPI:             &testBitriseAPI{},
+		},
+		requestBody: `{"platform":"ios"}`,
+	})
+
+	behavesAsContextCravingHandler(t, httpMethod, url, handler, []ctxpkg.RequestContextKey{services.ContextKeyAuthorizedAppID}, ControllerTestCase{
+		contextElements: map[ctxpkg.RequestContextKey]interface{}{
+			services.ContextKeyAuthorizedAppID: testAppID,
+		},
+		env: &env.AppEnv{
+			AppVersionService:      &testAppVersionService{},
+			AppSettingsService:     &testAppSettingsService{},
+			PublishTaskService:     &testPublishTaskService{},
+			AppVersionEventService: &testAppVersionEventService{},
+			BitriseAPI:             &testBitriseAPI{},
+		},
+		requestBody: `{"platform":"ios"}`,
+	})
+
+	t.Run("ok - when App Store Connect doesn't accept the previous version again", func(t *testing.T) {
+		events := []models.AppVersionEvent{}
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppID: testAppID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: &testAppVersionService{
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						require.Equal(t, testIosVersion1.ID, appVersion.ID)
+						previousVersion := testIosVersion1
+						return &previousVersion, nil
+					},
+				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						require.Equal(t, testAppID, appSettings.AppID)
+						return &models.AppSettings{}, nil
+					},
+				},
+				PublishTaskService: &testPublishTaskService{
+					findAllForAppFn: func(app *models.App) ([]models.PublishTask, error) {
+						require.Equal(t, testAppID, app.ID)
+						return []models.PublishTask{
+							{AppVersionID: testIosVersion1.ID, AppVersion: testIosVersion1, Destination: "app-store-connect", Status: "success"},
+							{AppVersionID: testAndroidVersion1.ID, AppVersion: testAndroidVersion1, Destination: "google-play", Status: "success"},
+							{AppVersionID: testIosVersion2.ID, AppVersion: testIosVersion2, Destination: "app-store-connect", Status: "failed"},
+							{AppVersionID: testIosVersion2.ID, AppVersion: testIosVersion2, Destination: "app-store-connect", Status: "success"},
+						}, nil
+					},
+				},
+				AppVersionEventService: &testAppVersionEventService{
+					createFn: func(event *models.AppVersionEvent) (*models.AppVersionEvent, error) {
+						events = append(events, *event)
+						return event, nil
+					},
+				},
+				BitriseAPI: &testBitriseAPI{},
+			},
+			requestBody:        `{"platform":"ios"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppRollbackResponse{
+				Data: services.AppRollbackResponseData{
+					RolledBackVersion: &testIosVersion2,
+					AppVersion:        &testIosVersion1,
+					Destination:       "app-store-connect",
+					PublishTasks:      []models.PublishTask{},
+					Alternatives: []string{
+						"Submit the build of version 1.0.0 (10) for review again in App Store Connect, as long as it hasn't expired",
+						"Build version 1.0.0 (10) again with a build number greater than the one of version 1.1.0 (11), and publish it",
+					},
+				},
+			},
+		})
+		require.Equal(t, []models.AppVersionEvent{
+			{Text: "Rollback to version 1.0.0 (10) isn't possible, app-store-connect doesn't accept a version it has already got", AppVersionID: testIosVersion2.ID},
+		}, events)
+	})
+
+	t.Run("ok - when the last version is being rolled out on Google Play", func(t *testing.T) {
+		testTaskIdentifier := uuid.FromStringOrNil("13a94c5d-4609-404e-ae69-c625e93b8b71")
+		events := []models.AppVersionEvent{}
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppID: testAppID,
+			},
+			env: &env.AppEnv{
+				AddonHostURL: "http://ship.addon.url",
+				AppVersionService: &testAppVersionService{
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						if uuid.Equal(testAndroidVersion2.ID, appVersion.ID) {
+							rolledBackVersion := testAndroidVersion2
+							rolledBackVersion.App = models.App{AppSlug: "test-app-slug"}
+							return &rolledBackVersion, nil
+						}
+						require.Equal(t, testAndroidVersion1.ID, appVersion.ID)
+						previousVersion := testAndroidVersion1
+						return &previousVersion, nil
+					},
+				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						return &models.AppSettings{AndroidSettingsData: json.RawMessage(`{"track":"production"}`)}, nil
+					},
+				},
+				PublishTaskService: &testPublishTaskService{
+					findAllForAppFn: func(app *models.App) ([]models.PublishTask, error) {
+						return []models.PublishTask{
+							{AppVersionID: testAndroidVersion1.ID, AppVersion: testAndroidVersion1, Destination: "google-play", Track: "production", Status: "success"},
+							{AppVersionID: testAndroidVersion2.ID, AppVersion: testAndroidVersion2, Destination: "google-play", Track: "production", Status: "success", ReleaseStatus: "inProgress", UserFraction: 0.1},
+							{AppVersionID: testAndroidVersion2.ID, AppVersion: testAndroidVersion2, Destination: "google-play", Track: "production", Status: "success", Action: "rollout", ReleaseStatus: "inProgress", UserFraction: 0.5},
+						}, nil
+					},
+					createFn: func(publishTask *models.PublishTask) (*models.PublishTask, error) {
+						require.False(t, uuid.Equal(uuid.UUID{}, publishTask.BatchID))
+						publishTask.BatchID = uuid.UUID{}
+						return publishTask, nil
+					},
+				},
+				AppVersionEventService: &testAppVersionEventService{
+					createFn: func(event *models.AppVersionEvent) (*models.AppVersionEvent, error) {
+						events = append(events, *event)
+						return event, nil
+					},
+				},
+				BitriseAPI: &testBitriseAPI{
+					triggerDENTaskFn: func(params bitrise.TaskParams) (*bitrise.TriggerResponse, error) {
+						require.Equal(t, "rollout_android", params.Workflow)
+						require.Equal(t, "http://ship.addon.url/apps/test-app-slug/versions/0c7d5a8e-7f1b-4b43-8f4e-54ad2c7ee0a2/android-config?release_status=halted&rollout_only=true&track=production&user_fraction=0.5", params.InlineEnvs["CONFIG_JSON_URL"])
+						return &bitrise.TriggerResponse{TaskIdentifier: testTaskIdentifier}, nil
+					},
+				},
+				JWTService: &security.JWTMock{
+					SignFn: func(token string) (string, error) {
+						return "jwt-token", nil
+					},
+				},
+			},
+			requestBody:        `{"platform":"android"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppRollbackResponse{
+				Data: services.AppRollbackResponseData{
+					RolledBackVersion: &testAndroidVersion2,
+					AppVersion:        &testAndroidVersion1,
+					Destination:       "google-play",
+					PublishTasks: []models.PublishTask{
+						{TaskID: testTaskIdentifier, AppVersionID: testAndroidVersion2.ID, Destination: "google-play", Track: "production", Status: "pending", Action: "rollout", ReleaseStatus: "halted", UserFraction: 0.5},
+					},
+					Alternatives: []string{},
+				},
+			},
+		})
+		require.Equal(t, []models.AppVersionEvent{
+			{Status: "in_progress", Text: "Rolling back to version 1.0.0 (30) by halting the rollout on production track", AppVersionID: testAndroidVersion2.ID},
+		}, events)
+	})
+
+	t.Run("ok - when Google Play doesn't accept the previous version again", func(t *testing.T) {
+		events := []models.AppVersionEvent{}
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppID: testAppID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: &testAppVersionService{
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						require.Equal(t, testAndroidVersion1.ID, appVersion.ID)
+						previousVersion := testAndroidVersion1
+						return &previousVersion, nil
+					},
+				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						return &models.AppSettings{AndroidSettingsData: json.RawMessage(`{"track":"production"}`)}, nil
+					},
+				},
+				PublishTaskService: &testPublishTaskService{
+					findAllForAppFn: func(app *models.App) ([]models.PublishTask, error) {
+						return []models.PublishTask{
+							{AppVersionID: testAndroidVersion1.ID, AppVersion: testAndroidVersion1, Destination: "google-play", Track: "production", Status: "success"},
+							{AppVersionID: testAndroidVersion2.ID, AppVersion: testAndroidVersion2, Destination: "google-play", Track: "production", Status: "success", ReleaseStatus: "inProgress", UserFraction: 0.1},
+							{AppVersionID: testAndroidVersion2.ID, AppVersion: testAndroidVersion2, Destination: "google-play", Track: "production", Status: "success", Action: "rollout", ReleaseStatus: "completed"},
+						}, nil
+					},
+				},
+				AppVersionEventService: &testAppVersionEventService{
+					createFn: func(event *models.AppVersionEvent) (*models.AppVersionEvent, error) {
+						events = append(events, *event)
+						return event, nil
+					},
+				},
+				BitriseAPI: &testBitriseAPI{},
+			},
+			requestBody:        `{"platform":"android"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppRollbackResponse{
+				Data: services.AppRollbackResponseData{
+					RolledBackVersion: &testAndroidVersion2,
+					AppVersion:        &testAndroidVersion1,
+					Destination:       "google-play",
+					PublishTasks:      []models.PublishTask{},
+					Alternatives: []string{
+						"Build version 1.0.0 (30) again with a version code greater than the one of version 1.1.0 (31), and publish it",
+					},
+				},
+			},
+		})
+		require.Equal(t, []models.AppVersionEvent{
+			{Text: "Rollback to version 1.0.0 (30) isn't possible, google-play doesn't accept a version it has already got", AppVersionID: testAndroidVersion2.ID},
+		}, events)
+	})
+
+	for _, tc := range []struct {
+		name            string
+		publishTasks    []models.PublishTask
+		expectedMessage string
+	}{
+		{
+			name:            "when no version of the platform has been published",
+			publishTasks:    []models.PublishTask{{AppVersionID: testAndroidVersion1.ID, AppVersion: testAndroidVersion1, Destination: "google-play", Status: "success"}},
+			expectedMessage: "No version of the platform has been published yet",
+		},
+		{
+			name: "when the last published version was rolled back to already",
+			publishTasks: []models.PublishTask{
+				{AppVersionID: testIosVersion1.ID, AppVersion: testIosVersion1, Destination: "app-store-connect", Status: "success"},
+				{AppVersionID: testIosVersion2.ID, AppVersion: testIosVersion2, Destination: "app-store-connect", Status: "success"},
+				{AppVersionID: testIosVersion1.ID, AppVersion: testIosVersion1, Destination: "app-store-connect", Status: "success"},
+			},
+			expectedMessage: "No version was published before the last published one",
+		},
+		{
+			name: "when a publish task of the platform is still running",
+			publishTasks: []models.PublishTask{
+				{AppVersionID: testIosVersion1.ID, AppVersion: testIosVersion1, Destination: "app-store-connect", Status: "success"},
+				{AppVersionID: testIosVersion2.ID, AppVersion: testIosVersion2, Destination: "app-store-connect", Status: "in_progress"},
+			},
+			expectedMessage: "A publish task of the platform is still running",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+				contextElements: map[ctxpkg.RequestContextKey]interface{}{
+					services.ContextKeyAuthorizedAppID: testAppID,
+				},
+				env: &env.AppEnv{
+					AppVersionService: &testAppVersionService{},
+					AppSettingsService: &testAppSettingsService{
+						findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+							return &models.AppSettings{}, nil
+						},
+					},
+					PublishTaskService: &testPublishTaskService{
+						findAllForAppFn: func(app *models.App) ([]models.PublishTask, error) {
+							return tc.publishTasks, nil
+						},
+					},
+					AppVersionEventService: &testAppVersionEventService{},
+					BitriseAPI:             &testBitriseAPI{},
+				},
+				requestBody:        `{"platform":"ios"}`,
+				expectedStatusCode: http.StatusBadRequest,
+				expectedResponse:   httpresponse.StandardErrorRespModel{Message: tc.expectedMessage},
+			})
+		})
+	}
+
+	t.Run("when platform is empty", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppID: testAppID,
+			},
+			env: &env.AppEnv{
+				AppVersionService:      &testAppVersionService{},
+				AppSettingsService:     &testAppSettingsService{},
+				PublishTaskService:     &testPublishTaskService{},
+				AppVersionEventService: &testAppVersionEventService{},
+				BitriseAPI:             &testBitriseAPI{},
+			},
+			requestBody:        `{"product_flavor":"free"}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedResponse: httpresponse.ValidationErrorRespModel{
+				Message: "Unprocessable Entity",
+				Errors:  []string{"platform: Cannot be empty"},
+			},
+		})
+	})
+
+	t.Run("when error happens at finding publish tasks", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppID: testAppID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: &testAppVersionService{},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						return &models.AppSettings{}, nil
+					},
+				},
+				PublishTaskService: &testPublishTaskService{
+					findAllForAppFn: func(app *models.App) ([]models.PublishTask, error) {
+						return nil, errors.New("SOME-SQL-ERROR")
+					},
+				},
+				AppVersionEventService: &testAppVersionEventService{},
+				BitriseAPI:             &testBitriseAPI{},
+			},
+			requestBody:         `{"platform":"ios"}`,
+			expectedInternalErr: "SQL Error: SOME-SQL-ERROR",
+		})
+	})
+}
@@ -1,12 +1,18 @@
 package services_test
 
-import "github.com/bitrise-io/addons-ship-backend/models"
+import (
+	"time"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+)
 
 type testAppService struct {
-	createFn func(*models.App) (*models.App, error)
-	findFn   func(*models.App) (*models.App, error)
-	updateFn func(*models.App, []string) ([]error, error)
-	deleteFn func(*models.App) error
+	createFn                     func(*models.App) (*models.App, error)
+	findFn                       func(*models.App) (*models.App, error)
+	findDeprovisionedFn          func(*models.App) (*models.App, error)
+	findAllDeprovisionedBeforeFn func(time.Time) ([]models.App, error)
+	updateFn                     func(*models.App, []string) ([]error, error)
+	deleteFn                     func(*models.App) error
 }
 
 func (a *testAppService) Create(app *models.App) (*models.App, error) {
@@ -23,6 +29,20 @@ func (a *testAppService) Find(app *models.App) (*models.App, error) {
 	panic("You have to override Find function in tests")
 }
 
+func (a *testAppService) FindDeprovisioned(app *models.App) (*models.App, error) {
+	if a.findDeprovisionedFn != nil {
+		return a.findDeprovisionedFn(app)
+	}
+	panic("You have to override FindDeprovisioned function in tests")
+}
+
+func (a *testAppService) FindAllDeprovisionedBefore(deprovisionedBefore time.Time) ([]models.App, error) {
+	if a.findAllDeprovisionedBeforeFn != nil {
+		return a.findAllDeprovisionedBeforeFn(deprovisionedBefore)
+	}
+	panic("You have to override FindAllDeprovisionedBefore function in tests")
+}
+
 func (a *testAppService) Update(app *models.App, whitelist []string) (validationErrors []error, dbErr error) {
 	if a.updateFn != nil {
 		return a.updateFn(app, whitelist)
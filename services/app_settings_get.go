@@ -16,6 +16,8 @@ type IosSettingsData struct {
 	models.IosSettings
 	AvailableProvisioningProfiles  []bitrise.ProvisioningProfile `json:"available_provisioning_profiles"`
 	AvailableCodeSigningIdentities []bitrise.CodeSigningIdentity `json:"available_code_signing_identities"`
+	// CodeSigningExpiry is the expiry of the selected code signing files
+	CodeSigningExpiry *models.CodeSigningExpiry `json:"code_signing_expiry,omitempty"`
 }
 
 // AndroidSettingsData ...
@@ -23,14 +25,22 @@ type AndroidSettingsData struct {
 	models.AndroidSettings
 	AvailableKeystoreFiles       []bitrise.AndroidKeystoreFile `json:"available_keystore_files"`
 	AvailableServiceAccountFiles []bitrise.GenericProjectFile  `json:"available_service_account_files"`
+	// ServiceAccountErrors are the problems of the selected service account file
+	ServiceAccountErrors []string `json:"service_account_errors,omitempty"`
 }
 
 // AppSettingsGetResponseData ...
 type AppSettingsGetResponseData struct {
 	*models.AppSettings
-	ProjectType     string               `json:"project_type"`
-	IosSettings     *IosSettingsData     `json:"ios_settings,omitempty"`
-	AndroidSettings *AndroidSettingsData `json:"android_settings,omitempty"`
+	ProjectType     string                 `json:"project_type"`
+	IosSettings     *IosSettingsData       `json:"ios_settings,omitempty"`
+	AndroidSettings *AndroidSettingsData   `json:"android_settings,omitempty"`
+	RetentionPolicy models.RetentionPolicy `json:"retention_policy"`
+	VersionRules    models.AppVersionRules `json:"version_rules"`
+	// AutoPublishRules are the rules the new versions get published by
+	AutoPublishRules []models.AutoPublishRule `json:"auto_publish_rules"`
+	// ApprovalRequirements are the approvals the versions need before publishing
+	ApprovalRequirements []models.ApprovalRequirement `json:"approval_requirements"`
 }
 
 // AppSettingsGetResponse ...
@@ -74,19 +84,40 @@ func AppSettingsGetHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Reque
 		}
 	}
 
-	if appDetails.ProjectType != "ios" {
+	if appDetails.ProjectType != "ios" && appDetails.ProjectType != "macos" {
 		androidSettingsData, err = makeAndroidSettingsData(env, appSettings)
 		if err != nil {
 			return errors.WithStack(err)
 		}
 	}
 
+	retentionPolicy, err := appSettings.RetentionPolicy()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	versionRules, err := appSettings.VersionRules()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	autoPublishRules, err := appSettings.AutoPublishRules()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	approvalRequirements, err := appSettings.ApprovalRequirements()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
 	return httpresponse.RespondWithSuccess(w, AppSettingsGetResponse{
 		Data: AppSettingsGetResponseData{
-			AppSettings:     appSettings,
-			ProjectType:     appDetails.ProjectType,
-			IosSettings:     iosSettingsData,
-			AndroidSettings: androidSettingsData,
+			AppSettings:          appSettings,
+			ProjectType:          appDetails.ProjectType,
+			IosSettings:          iosSettingsData,
+			AndroidSettings:      androidSettingsData,
+			RetentionPolicy:      retentionPolicy,
+			VersionRules:         versionRules,
+			AutoPublishRules:     autoPublishRules,
+			ApprovalRequirements: approvalRequirements,
 		},
 	})
 }
@@ -104,13 +135,31 @@ func makeIosSettingsData(env *env.AppEnv, appSettings *models.AppSettings) (*Ios
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
+	codeSigningExpiry, err := newCodeSigningExpiryResponse(appSettings)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
 	return &IosSettingsData{
 		IosSettings:                    iosSettings,
 		AvailableProvisioningProfiles:  provisioningProfiles,
 		AvailableCodeSigningIdentities: codeSigningIdentities,
+		CodeSigningExpiry:              codeSigningExpiry,
 	}, nil
 }
 
+// newCodeSigningExpiryResponse returns the expiry of the selected code signing
+// files, or nil if none of them has an expiry
+func newCodeSigningExpiryResponse(appSettings *models.AppSettings) (*models.CodeSigningExpiry, error) {
+	codeSigningExpiry, err := appSettings.CodeSigningExpiry()
+	if err != nil {
+		return nil, err
+	}
+	if codeSigningExpiry.Empty() {
+		return nil, nil
+	}
+	return &codeSigningExpiry, nil
+}
+
 func makeAndroidSettingsData(env *env.AppEnv, appSettings *models.AppSettings) (*AndroidSettingsData, error) {
 	androidKeyStoreFiles, err := env.BitriseAPI.GetAndroidKeystoreFiles(appSettings.App.BitriseAPIToken, appSettings.App.AppSlug)
 	if err != nil {
@@ -124,9 +173,14 @@ func makeAndroidSettingsData(env *env.AppEnv, appSettings *models.AppSettings) (
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
+	var serviceAccountErrors []string
+	if appSettings.App != nil {
+		serviceAccountErrors = appSettings.App.AndroidServiceAccountErrors()
+	}
 	return &AndroidSettingsData{
 		AndroidSettings:              androidSettings,
 		AvailableKeystoreFiles:       androidKeyStoreFiles,
 		AvailableServiceAccountFiles: serviceAccountfiles,
+		ServiceAccountErrors:         serviceAccountErrors,
 	}, nil
 }
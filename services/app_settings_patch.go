@@ -12,6 +12,7 @@ import (
 	"github.com/jinzhu/gorm"
 	"github.com/pkg/errors"
 	"github.com/thoas/go-funk"
+	"go.uber.org/zap"
 )
 
 // AppSettingsPatchParams ...
@@ -20,6 +21,15 @@ type AppSettingsPatchParams struct {
 	AndroidSettings models.AndroidSettings `json:"android_settings"`
 	IosWorkflow     string                 `json:"ios_workflow"`
 	AndroidWorkflow string                 `json:"android_workflow"`
+	// PublishBitriseYML and PublishStackID are only updated when they are sent
+	PublishBitriseYML *string `json:"publish_bitrise_yml"`
+	PublishStackID    *string `json:"publish_stack_id"`
+	// RetentionPolicy, VersionRules, AutoPublishRules and ApprovalRequirements
+	// are only updated when they are sent
+	RetentionPolicy      *models.RetentionPolicy       `json:"retention_policy"`
+	VersionRules         *models.AppVersionRules       `json:"version_rules"`
+	AutoPublishRules     *[]models.AutoPublishRule     `json:"auto_publish_rules"`
+	ApprovalRequirements *[]models.ApprovalRequirement `json:"approval_requirements"`
 }
 
 // AppSettingsPatchResponseData ...
@@ -27,6 +37,14 @@ type AppSettingsPatchResponseData struct {
 	*models.AppSettings
 	IosSettings     models.IosSettings     `json:"ios_settings"`
 	AndroidSettings models.AndroidSettings `json:"android_settings"`
+	// ServiceAccountErrors are the problems of the selected service account file
+	ServiceAccountErrors []string `json:"service_account_errors,omitempty"`
+	// CodeSigningExpiry is the expiry of the selected code signing files
+	CodeSigningExpiry    *models.CodeSigningExpiry    `json:"code_signing_expiry,omitempty"`
+	RetentionPolicy      models.RetentionPolicy       `json:"retention_policy"`
+	VersionRules         models.AppVersionRules       `json:"version_rules"`
+	AutoPublishRules     []models.AutoPublishRule     `json:"auto_publish_rules"`
+	ApprovalRequirements []models.ApprovalRequirement `json:"approval_requirements"`
 }
 
 // AppSettingsPatchResponse ...
@@ -50,6 +68,22 @@ func AppSettingsPatchHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Req
 		return httpresponse.RespondWithBadRequestError(w, "Invalid request body, JSON decode failed")
 	}
 
+	// the private key isn't part of the stored iOS settings, so it's validated
+	// before it gets encrypted
+	if verrs := params.IosSettings.Validate(); len(verrs) > 0 {
+		return httpresponse.RespondWithUnprocessableEntity(w, verrs)
+	}
+	if params.AutoPublishRules != nil {
+		if verrs := validateAutoPublishRules(*params.AutoPublishRules); len(verrs) > 0 {
+			return httpresponse.RespondWithUnprocessableEntity(w, verrs)
+		}
+	}
+	if params.ApprovalRequirements != nil {
+		if verrs := validateApprovalRequirements(*params.ApprovalRequirements); len(verrs) > 0 {
+			return httpresponse.RespondWithUnprocessableEntity(w, verrs)
+		}
+	}
+
 	appSettingsToUpdate, err := env.AppSettingsService.Find(&models.AppSettings{AppID: authorizedAppID})
 	switch {
 	case errors.Cause(err) == gorm.ErrRecordNotFound:
@@ -58,11 +92,30 @@ func AppSettingsPatchHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Req
 		return errors.Wrap(err, "SQL Error")
 	}
 
-	appSettingsToUpdate, updateWhiteList, err := prepareAppSettingsToUpdate(env.BitriseAPI, appSettingsToUpdate, params)
+	// the previous requirements are unknown when they can't be parsed, it's
+	// handled as a change
+	previousApprovalRequirements, err := appSettingsToUpdate.ApprovalRequirements()
+	approvalRequirementsChanged := params.ApprovalRequirements != nil &&
+		(err != nil || !sameApprovalRequirements(previousApprovalRequirements, *params.ApprovalRequirements))
+
+	appSettingsToUpdate, updateWhiteList, err := prepareAppSettingsToUpdate(env, appSettingsToUpdate, params)
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
+	// the service account file is checked when it's selected, or when the
+	// problems of the previously selected one have to be cleared
+	app := appSettingsToUpdate.App
+	checkServiceAccount := params.AndroidSettings.Valid() && app != nil &&
+		(params.AndroidSettings.SelectedServiceAccount != "" || len(app.AndroidServiceAccountErrors()) > 0)
+	var serviceAccountErrors []error
+	if checkServiceAccount {
+		serviceAccountErrors, err = validateServiceAccountFile(env, app, params.AndroidSettings.SelectedServiceAccount)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
 	verr, err := env.AppSettingsService.Update(appSettingsToUpdate, updateWhiteList)
 	if len(verr) > 0 {
 		return httpresponse.RespondWithUnprocessableEntity(w, verr)
@@ -71,6 +124,27 @@ func AppSettingsPatchHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Req
 		return errors.Wrap(err, "SQL Error")
 	}
 
+	if approvalRequirementsChanged {
+		err = recordApprovalRequirementsChange(env, appSettingsToUpdate.AppID, *params.ApprovalRequirements)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	if checkServiceAccount {
+		if env.AppService == nil {
+			return errors.New("No App Service defined for handler")
+		}
+		app.SetAndroidServiceAccountErrors(serviceAccountErrors)
+		verr, err := env.AppService.Update(app, []string{"AndroidErrors"})
+		if len(verr) > 0 {
+			return httpresponse.RespondWithUnprocessableEntity(w, verr)
+		}
+		if err != nil {
+			return errors.Wrap(err, "SQL Error")
+		}
+	}
+
 	response, err := newAppSettingsPatchResponse(appSettingsToUpdate)
 	if err != nil {
 		return errors.WithStack(err)
@@ -81,7 +155,8 @@ func AppSettingsPatchHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Req
 	})
 }
 
-func prepareAppSettingsToUpdate(api bitrise.APIInterface, appSettingsToUpdate *models.AppSettings, params AppSettingsPatchParams) (*models.AppSettings, []string, error) {
+func prepareAppSettingsToUpdate(env *env.AppEnv, appSettingsToUpdate *models.AppSettings, params AppSettingsPatchParams) (*models.AppSettings, []string, error) {
+	api := env.BitriseAPI
 	updateWhiteList := []string{}
 	if params.IosSettings.Valid() {
 		if len(params.IosSettings.SelectedAppStoreProvisioningProfiles) > 0 {
@@ -98,12 +173,40 @@ func prepareAppSettingsToUpdate(api bitrise.APIInterface, appSettingsToUpdate *m
 			}
 			params.IosSettings.ValidateSelectedProvisioningProfileSlugs(existingProvProfileSlugs)
 		}
+		// the private key of the API key is only stored encrypted, and it's
+		// removed together with the API key
+		if params.IosSettings.APIKeyPrivateKey != "" || (!params.IosSettings.HasAPIKey() && len(appSettingsToUpdate.EncryptedIosAPIKey) > 0) {
+			if err := appSettingsToUpdate.SetIosAPIKey(params.IosSettings.APIKeyPrivateKey); err != nil {
+				return nil, nil, errors.WithStack(err)
+			}
+			params.IosSettings.APIKeyPrivateKey = ""
+			updateWhiteList = append(updateWhiteList, "EncryptedIosAPIKey", "EncryptedIosAPIKeyIV")
+		}
+		// the expiry is only read again when other code signing files got
+		// selected, or when it hasn't been read yet
+		previousIosSettings, err := appSettingsToUpdate.IosSettings()
+		refreshCodeSigningExpiry := err != nil || len(appSettingsToUpdate.CodeSigningExpiryData) == 0 ||
+			!sameCodeSigningFiles(previousIosSettings, params.IosSettings)
 		iosSettings, err := json.Marshal(params.IosSettings)
 		if err != nil {
 			return nil, nil, errors.WithStack(err)
 		}
 		appSettingsToUpdate.IosSettingsData = iosSettings
 		updateWhiteList = append(updateWhiteList, "IosSettingsData")
+
+		if refreshCodeSigningExpiry {
+			// the settings are saved even if the files can't be read, the
+			// previous expiry is kept then
+			codeSigningExpiry, err := newCodeSigningExpiry(api, appSettingsToUpdate.App, params.IosSettings)
+			if err != nil {
+				env.Logger.Warn("Failed to read the expiry of the code signing files", zap.String("app_slug", appSettingsToUpdate.App.AppSlug), zap.Error(err))
+			} else {
+				if err := appSettingsToUpdate.SetCodeSigningExpiry(codeSigningExpiry); err != nil {
+					return nil, nil, errors.WithStack(err)
+				}
+				updateWhiteList = append(updateWhiteList, "CodeSigningExpiryData", "CodeSigningExpiresAt")
+			}
+		}
 	}
 	if params.AndroidSettings.Valid() {
 		androidSettings, err := json.Marshal(params.AndroidSettings)
@@ -118,9 +221,134 @@ func prepareAppSettingsToUpdate(api bitrise.APIInterface, appSettingsToUpdate *m
 	appSettingsToUpdate.AndroidWorkflow = params.AndroidWorkflow
 	updateWhiteList = append(updateWhiteList, "IosWorkflow", "AndroidWorkflow")
 
+	if params.PublishBitriseYML != nil {
+		appSettingsToUpdate.PublishBitriseYML = *params.PublishBitriseYML
+		updateWhiteList = append(updateWhiteList, "PublishBitriseYML")
+	}
+	if params.PublishStackID != nil {
+		appSettingsToUpdate.PublishStackID = *params.PublishStackID
+		updateWhiteList = append(updateWhiteList, "PublishStackID")
+	}
+	if params.RetentionPolicy != nil {
+		if err := appSettingsToUpdate.SetRetentionPolicy(*params.RetentionPolicy); err != nil {
+			return nil, nil, errors.WithStack(err)
+		}
+		updateWhiteList = append(updateWhiteList, "RetentionPolicyData")
+	}
+	if params.VersionRules != nil {
+		if err := appSettingsToUpdate.SetVersionRules(*params.VersionRules); err != nil {
+			return nil, nil, errors.WithStack(err)
+		}
+		updateWhiteList = append(updateWhiteList, "VersionRulesData")
+	}
+	if params.AutoPublishRules != nil {
+		if err := appSettingsToUpdate.SetAutoPublishRules(*params.AutoPublishRules); err != nil {
+			return nil, nil, errors.WithStack(err)
+		}
+		updateWhiteList = append(updateWhiteList, "AutoPublishRulesData")
+	}
+	if params.ApprovalRequirements != nil {
+		if err := appSettingsToUpdate.SetApprovalRequirements(*params.ApprovalRequirements); err != nil {
+			return nil, nil, errors.WithStack(err)
+		}
+		updateWhiteList = append(updateWhiteList, "ApprovalRequirementsData")
+	}
+
 	return appSettingsToUpdate, updateWhiteList, nil
 }
 
+// sameCodeSigningFiles tells whether the same code signing files are selected
+// in both of the settings
+func sameCodeSigningFiles(settings, otherSettings models.IosSettings) bool {
+	if settings.SelectedCodeSigningIdentity != otherSettings.SelectedCodeSigningIdentity {
+		return false
+	}
+	if len(settings.SelectedAppStoreProvisioningProfiles) != len(otherSettings.SelectedAppStoreProvisioningProfiles) {
+		return false
+	}
+	for _, provProfileSlug := range otherSettings.SelectedAppStoreProvisioningProfiles {
+		if !funk.ContainsString(settings.SelectedAppStoreProvisioningProfiles, provProfileSlug) {
+			return false
+		}
+	}
+	return true
+}
+
+// newCodeSigningExpiry downloads the selected code signing files to read their
+// expiry, the files which can't be parsed are left out
+func newCodeSigningExpiry(api bitrise.APIInterface, app *models.App, iosSettings models.IosSettings) (models.CodeSigningExpiry, error) {
+	codeSigningExpiry := models.CodeSigningExpiry{ProvisioningProfiles: []models.CodeSigningFileExpiry{}}
+	for _, provProfileSlug := range iosSettings.SelectedAppStoreProvisioningProfiles {
+		provProfile, err := api.GetProvisioningProfile(app.BitriseAPIToken, app.AppSlug, provProfileSlug)
+		if err != nil {
+			return models.CodeSigningExpiry{}, errors.Wrap(err, "Failed to fetch provisioning profile")
+		}
+		content, err := api.DownloadFile(provProfile.DownloadURL)
+		if err != nil {
+			return models.CodeSigningExpiry{}, errors.Wrap(err, "Failed to download provisioning profile")
+		}
+		expiresAt, err := bitrise.ProvisioningProfileExpiry(content)
+		if err != nil {
+			continue
+		}
+		codeSigningExpiry.ProvisioningProfiles = append(codeSigningExpiry.ProvisioningProfiles, models.CodeSigningFileExpiry{
+			Slug:      provProfile.Slug,
+			Filename:  provProfile.Filename,
+			ExpiresAt: expiresAt,
+		})
+	}
+	if iosSettings.SelectedCodeSigningIdentity == "" {
+		return codeSigningExpiry, nil
+	}
+	codeSigningIdentity, err := api.GetCodeSigningIdentity(app.BitriseAPIToken, app.AppSlug, iosSettings.SelectedCodeSigningIdentity)
+	if err != nil {
+		return models.CodeSigningExpiry{}, errors.Wrap(err, "Failed to fetch code signing identity")
+	}
+	content, err := api.DownloadFile(codeSigningIdentity.DownloadURL)
+	if err != nil {
+		return models.CodeSigningExpiry{}, errors.Wrap(err, "Failed to download code signing identity")
+	}
+	if expiresAt, err := bitrise.CodeSigningIdentityExpiry(content, codeSigningIdentity.CertificatePassword); err == nil {
+		codeSigningExpiry.DistributionCertificate = &models.CodeSigningFileExpiry{
+			Slug:      codeSigningIdentity.Slug,
+			Filename:  codeSigningIdentity.Filename,
+			ExpiresAt: expiresAt,
+		}
+	}
+	return codeSigningExpiry, nil
+}
+
+// validateServiceAccountFile downloads the selected service account file and
+// returns its problems, so that a wrong file doesn't only turn out at publishing.
+// A file which can't be downloaded doesn't block saving the settings, it's
+// reported as a problem instead.
+func validateServiceAccountFile(env *env.AppEnv, app *models.App, serviceAccountSlug string) ([]error, error) {
+	if serviceAccountSlug == "" {
+		return nil, nil
+	}
+	if env.BitriseAPI == nil {
+		return nil, errors.New("No Bitrise API Service defined for handler")
+	}
+	content, err := downloadServiceAccountFile(env.BitriseAPI, app, serviceAccountSlug)
+	if err != nil {
+		env.Logger.Warn("Failed to check service account file", zap.String("app_slug", app.AppSlug), zap.Error(err))
+		return []error{models.ServiceAccountNotCheckedError()}, nil
+	}
+	return models.ValidateServiceAccount(content), nil
+}
+
+func downloadServiceAccountFile(api bitrise.APIInterface, app *models.App, serviceAccountSlug string) ([]byte, error) {
+	serviceAccountFile, err := api.GetServiceAccountFile(app.BitriseAPIToken, app.AppSlug, serviceAccountSlug)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to fetch service account file")
+	}
+	content, err := api.DownloadFile(serviceAccountFile.DownloadURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to download service account file")
+	}
+	return content, nil
+}
+
 func newAppSettingsPatchResponse(appSettings *models.AppSettings) (AppSettingsPatchResponseData, error) {
 	iosSettings, err := appSettings.IosSettings()
 	if err != nil {
@@ -130,9 +358,39 @@ func newAppSettingsPatchResponse(appSettings *models.AppSettings) (AppSettingsPa
 	if err != nil {
 		return AppSettingsPatchResponseData{}, err
 	}
+	var serviceAccountErrors []string
+	if appSettings.App != nil {
+		serviceAccountErrors = appSettings.App.AndroidServiceAccountErrors()
+	}
+	codeSigningExpiry, err := newCodeSigningExpiryResponse(appSettings)
+	if err != nil {
+		return AppSettingsPatchResponseData{}, err
+	}
+	retentionPolicy, err := appSettings.RetentionPolicy()
+	if err != nil {
+		return AppSettingsPatchResponseData{}, err
+	}
+	versionRules, err := appSettings.VersionRules()
+	if err != nil {
+		return AppSettingsPatchResponseData{}, err
+	}
+	autoPublishRules, err := appSettings.AutoPublishRules()
+	if err != nil {
+		return AppSettingsPatchResponseData{}, err
+	}
+	approvalRequirements, err := appSettings.ApprovalRequirements()
+	if err != nil {
+		return AppSettingsPatchResponseData{}, err
+	}
 	return AppSettingsPatchResponseData{
-		AppSettings:     appSettings,
-		IosSettings:     iosSettings,
-		AndroidSettings: androidSettings,
+		AppSettings:          appSettings,
+		IosSettings:          iosSettings,
+		AndroidSettings:      androidSettings,
+		ServiceAccountErrors: serviceAccountErrors,
+		CodeSigningExpiry:    codeSigningExpiry,
+		RetentionPolicy:      retentionPolicy,
+		VersionRules:         versionRules,
+		AutoPublishRules:     autoPublishRules,
+		ApprovalRequirements: approvalRequirements,
 	}, nil
 }
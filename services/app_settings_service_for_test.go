@@ -1,10 +1,16 @@
 package services_test
 
-import "github.com/bitrise-io/addons-ship-backend/models"
+import (
+	"time"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+)
 
 type testAppSettingsService struct {
-	findFn   func(*models.AppSettings) (*models.AppSettings, error)
-	updateFn func(*models.AppSettings, []string) (validationErrors []error, dbErr error)
+	findFn                                 func(*models.AppSettings) (*models.AppSettings, error)
+	findAllWithCodeSigningExpiringBeforeFn func(time.Time) ([]models.AppSettings, error)
+	findAllWithRetentionPolicyFn           func() ([]models.AppSettings, error)
+	updateFn                               func(*models.AppSettings, []string) (validationErrors []error, dbErr error)
 }
 
 func (a *testAppSettingsService) Find(appSettings *models.AppSettings) (*models.AppSettings, error) {
@@ -14,6 +20,20 @@ func (a *testAppSettingsService) Find(appSettings *models.AppSettings) (*models.
 	panic("You have to override Find function in tests")
 }
 
+func (a *testAppSettingsService) FindAllWithCodeSigningExpiringBefore(expiresBefore time.Time) ([]models.AppSettings, error) {
+	if a.findAllWithCodeSigningExpiringBeforeFn != nil {
+		return a.findAllWithCodeSigningExpiringBeforeFn(expiresBefore)
+	}
+	panic("You have to override FindAllWithCodeSigningExpiringBefore function in tests")
+}
+
+func (a *testAppSettingsService) FindAllWithRetentionPolicy() ([]models.AppSettings, error) {
+	if a.findAllWithRetentionPolicyFn != nil {
+		return a.findAllWithRetentionPolicyFn()
+	}
+	panic("You have to override FindAllWithRetentionPolicy function in tests")
+}
+
 func (a *testAppSettingsService) Update(appSettings *models.AppSettings, whitelist []string) (validationErrors []error, dbErr error) {
 	if a.updateFn != nil {
 		return a.updateFn(appSettings, whitelist)
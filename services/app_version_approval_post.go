@@ -0,0 +1,141 @@
+package services
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/api-utils/httprequest"
+	"github.com/bitrise-io/api-utils/httpresponse"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// AppVersionApprovalParams ...
+type AppVersionApprovalParams struct {
+	Comment string `json:"comment"`
+}
+
+// AppVersionApprovalResponse ...
+type AppVersionApprovalResponse struct {
+	Data *models.Approval `json:"data"`
+}
+
+// AppVersionApprovalPostHandler records the approval of the app contact, who
+// got the approval request of an app version, authorized by its token
+func AppVersionApprovalPostHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Request) error {
+	authorizedApprovalID, err := GetAuthorizedApprovalIDFromContext(r.Context())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	var params AppVersionApprovalParams
+	defer httprequest.BodyCloseWithErrorLog(r)
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil && err != io.EOF {
+		return httpresponse.RespondWithBadRequestError(w, "Invalid request body, JSON decode failed")
+	}
+
+	if env.AppVersionService == nil {
+		return errors.New("No App Version Service defined for handler")
+	}
+	if env.AppContactService == nil {
+		return errors.New("No App Contact Service defined for handler")
+	}
+	if env.ApprovalService == nil {
+		return errors.New("No Approval Service defined for handler")
+	}
+	if env.AppVersionEventService == nil {
+		return errors.New("No App Version Event Service defined for handler")
+	}
+
+	approval, appVersion, err := authorizedApproval(env, authorizedApprovalID)
+	switch {
+	case errors.Cause(err) == gorm.ErrRecordNotFound:
+		return httpresponse.RespondWithNotFoundError(w)
+	case err != nil:
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	approver, err := confirmedAppContact(env, appVersion, approval.Approver)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if approver == nil {
+		return httpresponse.RespondWithUnprocessableEntity(w, []error{errors.New("approver: Must be a confirmed contact of the app")})
+	}
+
+	switch approval.Status {
+	case models.ApprovalStatusApproved:
+		return httpresponse.RespondWithUnprocessableEntity(w, []error{errors.New("approver: Has already approved the version")})
+	case models.ApprovalStatusRequested:
+		approval.Status = models.ApprovalStatusApproved
+		approval.Comment = params.Comment
+		err = env.ApprovalService.Update(approval, []string{"Status", "Comment"})
+		if err != nil {
+			return errors.Wrap(err, "SQL Error")
+		}
+	default:
+		// the revoked or invalidated approval is kept, the token moves to the
+		// new one, so the approver can revoke it
+		token := approval.Token
+		approval.Token = nil
+		err = env.ApprovalService.Update(approval, []string{"Token"})
+		if err != nil {
+			return errors.Wrap(err, "SQL Error")
+		}
+		approval, err = env.ApprovalService.Create(&models.Approval{
+			AppVersionID: appVersion.ID,
+			Approver:     approval.Approver,
+			Comment:      params.Comment,
+			Status:       models.ApprovalStatusApproved,
+			Token:        token,
+		})
+		if err != nil {
+			return errors.Wrap(err, "SQL Error")
+		}
+	}
+
+	_, err = env.AppVersionEventService.Create(&models.AppVersionEvent{
+		Text:         approvalEventText("Approved by "+approval.Approver, params.Comment),
+		AppVersionID: appVersion.ID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	return httpresponse.RespondWithSuccess(w, AppVersionApprovalResponse{Data: approval})
+}
+
+// authorizedApproval returns the approval authorized by its token, and its
+// app version
+func authorizedApproval(env *env.AppEnv, approvalID uuid.UUID) (*models.Approval, *models.AppVersion, error) {
+	approval, err := env.ApprovalService.Find(&models.Approval{Record: models.Record{ID: approvalID}})
+	if err != nil {
+		return nil, nil, err
+	}
+	appVersion, err := env.AppVersionService.Find(&models.AppVersion{Record: models.Record{ID: approval.AppVersionID}})
+	if err != nil {
+		return nil, nil, err
+	}
+	return approval, appVersion, nil
+}
+
+// confirmedAppContact returns the confirmed contact of the app of the version
+// with the given email, or nil if the app has no such contact
+func confirmedAppContact(env *env.AppEnv, appVersion *models.AppVersion, email string) (*models.AppContact, error) {
+	appContacts, err := env.AppContactService.FindAll(&models.App{Record: models.Record{ID: appVersion.AppID}})
+	if err != nil {
+		return nil, errors.Wrap(err, "SQL Error")
+	}
+	for _, appContact := range appContacts {
+		if strings.EqualFold(appContact.Email, strings.TrimSpace(email)) && !appContact.ConfirmedAt.IsZero() {
+			appContact := appContact
+			return &appContact, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,267 @@
+package services_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/addons-ship-backend/services"
+	ctxpkg "github.com/bitrise-io/api-utils/context"
+	"github.com/bitrise-io/api-utils/httpresponse"
+	"github.com/bitrise-io/go-utils/pointers"
+	"github.com/c2fo/testify/require"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+func Test_AppVersionApprovalPostHandler(t *testing.T) {
+	httpMethod := "POST"
+	url := "/approve"
+	handler := services.AppVersionApprovalPostHandler
+
+	testAppID := uuid.FromStringOrNil("211afc15-127a-40f9-8cbe-1dadc1f86cdf")
+	testAppVersionID := uuid.FromStringOrNil("de438ddc-98e5-4226-a5f4-fd2d53474879")
+	testApprovalID := uuid.FromStringOrNil("8a230385-0113-4cf3-a9c6-469a313e587a")
+	testAppVersion := &models.AppVersion{Record: models.Record{ID: testAppVersionID}, AppID: testAppID}
+	testAppContacts := []models.AppContact{
+		{Email: "qa@bitrise.io", ConfirmedAt: time.Now()},
+		{Email: "unconfirmed@bitrise.io"},
+	}
+	testApproval := func(approver, status string) *models.Approval {
+		return &models.Approval{
+			Record:       models.Record{ID: testApprovalID},
+			AppVersionID: testAppVersionID,
+			Approver:     approver,
+			Status:       status,
+			Token:        pointers.NewStringPtr("5om3-r4nd0m-5tr1ng"),
+		}
+	}
+	testFindFns := func() (*testAppVersionService, *testAppContactService) {
+		return &testAppVersionService{
+			findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+				require.Equal(t, testAppVersionID, appVersion.ID)
+				return testAppVersion, nil
+			},
+		}, &testAppContactService{
+			findAllFn: func(app *models.App) ([]models.AppContact, error) {
+				require.Equal(t, testAppID, app.ID)
+				return testAppContacts, nil
+			},
+		}
+	}
+
+	behavesAsServiceCravingHandler(t, httpMethod, url, handler, []string{"AppVersionService", "AppContactService", "ApprovalService", "AppVersionEventService"}, ControllerTestCase{
+		contextElements: map[ctxpkg.RequestContextKey]interface{}{
+			services.ContextKeyAuthorizedApprovalID: testApprovalID,
+		},
+		env: &env.AppEnv{
+			AppVersionService:      &testAppVersionService{},
+			AppContactService:      &testAppContactService{},
+			ApprovalService:        &testApprovalService{},
+			AppVersionEventService: &testAppVersionEventService{},
+		},
+	})
+
+	behavesAsContextCravingHandler(t, httpMethod, url, handler, []ctxpkg.RequestContextKey{services.ContextKeyAuthorizedApprovalID}, ControllerTestCase{
+		contextElements: map[ctxpkg.RequestContextKey]interface{}{
+			services.ContextKeyAuthorizedApprovalID: testApprovalID,
+		},
+		env: &env.AppEnv{
+			AppVersionService:      &testAppVersionService{},
+			AppContactService:      &testAppContactService{},
+			ApprovalService:        &testApprovalService{},
+			AppVersionEventService: &testAppVersionEventService{},
+		},
+	})
+
+	t.Run("ok - approves the requested approval", func(t *testing.T) {
+		appVersionService, appContactService := testFindFns()
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedApprovalID: testApprovalID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: appVersionService,
+				AppContactService: appContactService,
+				ApprovalService: &testApprovalService{
+					findFn: func(approval *models.Approval) (*models.Approval, error) {
+						require.Equal(t, testApprovalID, approval.ID)
+						return testApproval("qa@bitrise.io", "requested"), nil
+					},
+					updateFn: func(approval *models.Approval, whitelist []string) error {
+						require.Equal(t, []string{"Status", "Comment"}, whitelist)
+						require.Equal(t, "approved", approval.Status)
+						require.Equal(t, "Tested on device", approval.Comment)
+						return nil
+					},
+				},
+				AppVersionEventService: &testAppVersionEventService{
+					createFn: func(event *models.AppVersionEvent) (*models.AppVersionEvent, error) {
+						require.Equal(t, "Approved by qa@bitrise.io: Tested on device", event.Text)
+						require.Equal(t, testAppVersionID, event.AppVersionID)
+						return event, nil
+					},
+				},
+			},
+			requestBody:        `{"approval_token":"5om3-r4nd0m-5tr1ng","comment":"Tested on device"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppVersionApprovalResponse{
+				Data: &models.Approval{
+					Record:       models.Record{ID: testApprovalID},
+					AppVersionID: testAppVersionID,
+					Approver:     "qa@bitrise.io",
+					Comment:      "Tested on device",
+					Status:       "approved",
+				},
+			},
+		})
+	})
+
+	t.Run("ok - approves again after an invalidation, keeping the invalidated approval", func(t *testing.T) {
+		appVersionService, appContactService := testFindFns()
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedApprovalID: testApprovalID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: appVersionService,
+				AppContactService: appContactService,
+				ApprovalService: &testApprovalService{
+					findFn: func(approval *models.Approval) (*models.Approval, error) {
+						return testApproval("qa@bitrise.io", "invalidated"), nil
+					},
+					updateFn: func(approval *models.Approval, whitelist []string) error {
+						require.Equal(t, testApprovalID, approval.ID)
+						require.Equal(t, []string{"Token"}, whitelist)
+						require.Nil(t, approval.Token)
+						return nil
+					},
+					createFn: func(approval *models.Approval) (*models.Approval, error) {
+						require.Equal(t, testAppVersionID, approval.AppVersionID)
+						require.Equal(t, "qa@bitrise.io", approval.Approver)
+						require.Equal(t, "approved", approval.Status)
+						require.Equal(t, pointers.NewStringPtr("5om3-r4nd0m-5tr1ng"), approval.Token)
+						return approval, nil
+					},
+				},
+				AppVersionEventService: &testAppVersionEventService{
+					createFn: func(event *models.AppVersionEvent) (*models.AppVersionEvent, error) {
+						require.Equal(t, "Approved by qa@bitrise.io", event.Text)
+						return event, nil
+					},
+				},
+			},
+			requestBody:        `{"approval_token":"5om3-r4nd0m-5tr1ng"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppVersionApprovalResponse{
+				Data: &models.Approval{
+					AppVersionID: testAppVersionID,
+					Approver:     "qa@bitrise.io",
+					Status:       "approved",
+				},
+			},
+		})
+	})
+
+	t.Run("when approver is no longer a confirmed contact of the app", func(t *testing.T) {
+		appVersionService, appContactService := testFindFns()
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedApprovalID: testApprovalID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: appVersionService,
+				AppContactService: appContactService,
+				ApprovalService: &testApprovalService{
+					findFn: func(approval *models.Approval) (*models.Approval, error) {
+						return testApproval("unconfirmed@bitrise.io", "requested"), nil
+					},
+				},
+				AppVersionEventService: &testAppVersionEventService{},
+			},
+			requestBody:        `{"approval_token":"5om3-r4nd0m-5tr1ng"}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedResponse: httpresponse.ValidationErrorRespModel{
+				Message: "Unprocessable Entity",
+				Errors:  []string{"approver: Must be a confirmed contact of the app"},
+			},
+		})
+	})
+
+	t.Run("when approver has already approved the version", func(t *testing.T) {
+		appVersionService, appContactService := testFindFns()
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedApprovalID: testApprovalID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: appVersionService,
+				AppContactService: appContactService,
+				ApprovalService: &testApprovalService{
+					findFn: func(approval *models.Approval) (*models.Approval, error) {
+						return testApproval("qa@bitrise.io", "approved"), nil
+					},
+				},
+				AppVersionEventService: &testAppVersionEventService{},
+			},
+			requestBody:        `{"approval_token":"5om3-r4nd0m-5tr1ng"}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedResponse: httpresponse.ValidationErrorRespModel{
+				Message: "Unprocessable Entity",
+				Errors:  []string{"approver: Has already approved the version"},
+			},
+		})
+	})
+
+	t.Run("when app version not found", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedApprovalID: testApprovalID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: &testAppVersionService{
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+				},
+				AppContactService: &testAppContactService{},
+				ApprovalService: &testApprovalService{
+					findFn: func(approval *models.Approval) (*models.Approval, error) {
+						return testApproval("qa@bitrise.io", "requested"), nil
+					},
+				},
+				AppVersionEventService: &testAppVersionEventService{},
+			},
+			requestBody:        `{"approval_token":"5om3-r4nd0m-5tr1ng"}`,
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   httpresponse.StandardErrorRespModel{Message: "Not Found"},
+		})
+	})
+
+	t.Run("when error happens at updating approval", func(t *testing.T) {
+		appVersionService, appContactService := testFindFns()
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedApprovalID: testApprovalID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: appVersionService,
+				AppContactService: appContactService,
+				ApprovalService: &testApprovalService{
+					findFn: func(approval *models.Approval) (*models.Approval, error) {
+						return testApproval("qa@bitrise.io", "requested"), nil
+					},
+					updateFn: func(approval *models.Approval, whitelist []string) error {
+						return errors.New("SOME-SQL-ERROR")
+					},
+				},
+				AppVersionEventService: &testAppVersionEventService{},
+			},
+			requestBody:         `{"approval_token":"5om3-r4nd0m-5tr1ng"}`,
+			expectedInternalErr: "SQL Error: SOME-SQL-ERROR",
+		})
+	})
+}
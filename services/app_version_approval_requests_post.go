@@ -0,0 +1,154 @@
+package services
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/api-utils/httpresponse"
+	"github.com/bitrise-io/go-crypto/crypto"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// AppVersionApprovalRequestsResponse ...
+type AppVersionApprovalRequestsResponse struct {
+	Data []models.Approval `json:"data"`
+}
+
+// AppVersionApprovalRequestsPostHandler requests the approval of an app version
+// from the confirmed app contacts, who count as approvers of the approval
+// requirements. Each of them gets an email with the link of their approval, the
+// token of which authorizes them to approve, or revoke their approval.
+func AppVersionApprovalRequestsPostHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Request) error {
+	authorizedAppVersionID, err := GetAuthorizedAppVersionIDFromContext(r.Context())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if env.AppVersionService == nil {
+		return errors.New("No App Version Service defined for handler")
+	}
+	if env.AppSettingsService == nil {
+		return errors.New("No App Settings Service defined for handler")
+	}
+	if env.AppContactService == nil {
+		return errors.New("No App Contact Service defined for handler")
+	}
+	if env.ApprovalService == nil {
+		return errors.New("No Approval Service defined for handler")
+	}
+	if env.AppVersionEventService == nil {
+		return errors.New("No App Version Event Service defined for handler")
+	}
+	if env.BitriseAPI == nil {
+		return errors.New("No Bitrise API Service defined for handler")
+	}
+	if env.Mailer == nil {
+		return errors.New("No Mailer defined for handler")
+	}
+
+	appVersion, err := env.AppVersionService.Find(&models.AppVersion{Record: models.Record{ID: authorizedAppVersionID}})
+	switch {
+	case errors.Cause(err) == gorm.ErrRecordNotFound:
+		return httpresponse.RespondWithNotFoundError(w)
+	case err != nil:
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	appSettings, err := env.AppSettingsService.Find(&models.AppSettings{AppID: appVersion.AppID})
+	switch {
+	case errors.Cause(err) == gorm.ErrRecordNotFound:
+		return httpresponse.RespondWithNotFoundError(w)
+	case err != nil:
+		return errors.Wrap(err, "SQL Error")
+	}
+	approvalRequirements, err := appSettings.ApprovalRequirements()
+	if err != nil {
+		return errors.Wrap(err, "Failed to parse approval requirements")
+	}
+	if len(approvalRequirements) == 0 {
+		return httpresponse.RespondWithBadRequestError(w, "The app has no approval requirements")
+	}
+
+	appContacts, err := env.AppContactService.FindAll(&models.App{Record: models.Record{ID: appVersion.AppID}})
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+	approvals, err := env.ApprovalService.FindAll(appVersion)
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	appDetails, err := env.BitriseAPI.GetAppDetails(appVersion.App.BitriseAPIToken, appVersion.App.AppSlug)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	requested := []models.Approval{}
+	for _, appContact := range appContacts {
+		if appContact.ConfirmedAt.IsZero() || !countsForApprovalRequirements(approvalRequirements, appContact.Email) {
+			continue
+		}
+		approval := currentApproval(approvals, appContact.Email)
+		if approval != nil && approval.Status == models.ApprovalStatusApproved {
+			continue
+		}
+		if approval == nil {
+			token := crypto.SecureRandomHash(24)
+			approval, err = env.ApprovalService.Create(&models.Approval{
+				AppVersionID: appVersion.ID,
+				Approver:     appContact.Email,
+				Status:       models.ApprovalStatusRequested,
+				Token:        &token,
+			})
+			if err != nil {
+				return errors.Wrap(err, "SQL Error")
+			}
+		}
+		err = env.Mailer.SendEmailApprovalRequest(appVersion, approval, appDetails, env.AddonFrontendHostURL)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		requested = append(requested, *approval)
+	}
+	if len(requested) == 0 {
+		return httpresponse.RespondWithBadRequestError(w, "No confirmed app contact has to approve the version")
+	}
+
+	approvers := []string{}
+	for _, approval := range requested {
+		approvers = append(approvers, approval.Approver)
+	}
+	_, err = env.AppVersionEventService.Create(&models.AppVersionEvent{
+		Text:         "Approval was requested from " + strings.Join(approvers, ", "),
+		AppVersionID: appVersion.ID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	return httpresponse.RespondWithSuccess(w, AppVersionApprovalRequestsResponse{Data: requested})
+}
+
+func countsForApprovalRequirements(approvalRequirements []models.ApprovalRequirement, email string) bool {
+	for _, approvalRequirement := range approvalRequirements {
+		if approvalRequirement.CountsApprover(email) {
+			return true
+		}
+	}
+	return false
+}
+
+// currentApproval returns the approval of the approver, which has the token
+// sent to them, or nil if their approval wasn't requested yet
+func currentApproval(approvals []models.Approval, approver string) *models.Approval {
+	for _, approval := range approvals {
+		if approval.Token != nil && strings.EqualFold(approval.Approver, approver) {
+			approval := approval
+			return &approval
+		}
+	}
+	return nil
+}
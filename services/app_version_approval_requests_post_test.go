@@ -0,0 +1,207 @@
+package services_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bitrise-io/addons-ship-backend/bitrise"
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/addons-ship-backend/services"
+	ctxpkg "github.com/bitrise-io/api-utils/context"
+	"github.com/bitrise-io/api-utils/httpresponse"
+	"github.com/bitrise-io/go-utils/pointers"
+	"github.com/c2fo/testify/require"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+func Test_AppVersionApprovalRequestsPostHandler(t *testing.T) {
+	httpMethod := "POST"
+	url := "/apps/{app-slug}/versions/{version-id}/approval-requests"
+	handler := services.AppVersionApprovalRequestsPostHandler
+
+	testAppID := uuid.FromStringOrNil("211afc15-127a-40f9-8cbe-1dadc1f86cdf")
+	testAppVersionID := uuid.FromStringOrNil("de438ddc-98e5-4226-a5f4-fd2d53474879")
+	testAppVersion := &models.AppVersion{
+		Record: models.Record{ID: testAppVersionID},
+		AppID:  testAppID,
+		App:    models.App{AppSlug: "test-app-slug", BitriseAPIToken: "test-api-token"},
+	}
+	testAppContacts := []models.AppContact{
+		{Email: "qa@bitrise.io", ConfirmedAt: time.Now()},
+		{Email: "product@bitrise.io", ConfirmedAt: time.Now()},
+		{Email: "dev@bitrise.io", ConfirmedAt: time.Now()},
+		{Email: "unconfirmed@bitrise.io"},
+	}
+	testAppDetails := &bitrise.AppDetails{Title: "Standup Timer"}
+	testEnv := func(approvalRequirements string, approvalService *testApprovalService, mailer *testMailer) *env.AppEnv {
+		return &env.AppEnv{
+			AddonFrontendHostURL: "http://ship.bitrise.io",
+			AppVersionService: &testAppVersionService{
+				findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+					require.Equal(t, testAppVersionID, appVersion.ID)
+					return testAppVersion, nil
+				},
+			},
+			AppSettingsService: &testAppSettingsService{
+				findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+					require.Equal(t, testAppID, appSettings.AppID)
+					return &models.AppSettings{ApprovalRequirementsData: json.RawMessage(approvalRequirements)}, nil
+				},
+			},
+			AppContactService: &testAppContactService{
+				findAllFn: func(app *models.App) ([]models.AppContact, error) {
+					require.Equal(t, testAppID, app.ID)
+					return testAppContacts, nil
+				},
+			},
+			ApprovalService: approvalService,
+			AppVersionEventService: &testAppVersionEventService{
+				createFn: func(event *models.AppVersionEvent) (*models.AppVersionEvent, error) {
+					require.Equal(t, "Approval was requested from qa@bitrise.io, product@bitrise.io", event.Text)
+					require.Equal(t, testAppVersionID, event.AppVersionID)
+					return event, nil
+				},
+			},
+			BitriseAPI: &testBitriseAPI{
+				getAppDetailsFn: func(authToken, appSlug string) (*bitrise.AppDetails, error) {
+					require.Equal(t, "test-api-token", authToken)
+					require.Equal(t, "test-app-slug", appSlug)
+					return testAppDetails, nil
+				},
+			},
+			Mailer: mailer,
+		}
+	}
+
+	behavesAsServiceCravingHandler(t, httpMethod, url, handler, []string{"AppVersionService", "AppSettingsService", "AppContactService", "ApprovalService", "AppVersionEventService", "BitriseAPI", "Mailer"}, ControllerTestCase{
+		contextElements: map[ctxpkg.RequestContextKey]interface{}{
+			services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+		},
+		env: &env.AppEnv{
+			AppVersionService:      &testAppVersionService{},
+			AppSettingsService:     &testAppSettingsService{},
+			AppContactService:      &testAppContactService{},
+			ApprovalService:        &testApprovalService{},
+			AppVersionEventService: &testAppVersionEventService{},
+			BitriseAPI:             &testBitriseAPI{},
+			Mailer:                 &testMailer{},
+		},
+	})
+
+	behavesAsContextCravingHandler(t, httpMethod, url, handler, []ctxpkg.RequestContextKey{services.ContextKeyAuthorizedAppVersionID}, ControllerTestCase{
+		contextElements: map[ctxpkg.RequestContextKey]interface{}{
+			services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+		},
+		env: &env.AppEnv{
+			AppVersionService:      &testAppVersionService{},
+			AppSettingsService:     &testAppSettingsService{},
+			AppContactService:      &testAppContactService{},
+			ApprovalService:        &testApprovalService{},
+			AppVersionEventService: &testAppVersionEventService{},
+			BitriseAPI:             &testBitriseAPI{},
+			Mailer:                 &testMailer{},
+		},
+	})
+
+	t.Run("ok - requests the approval of the approvers, who haven't approved yet", func(t *testing.T) {
+		sentTo := []string{}
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: testEnv(`[{"approvals":1,"approvers":["qa@bitrise.io","product@bitrise.io","unconfirmed@bitrise.io"]}]`,
+				&testApprovalService{
+					findAllFn: func(appVersion *models.AppVersion) ([]models.Approval, error) {
+						require.Equal(t, testAppVersionID, appVersion.ID)
+						return []models.Approval{
+							{Approver: "product@bitrise.io", Status: "revoked"},
+							{Approver: "product@bitrise.io", Status: "requested", Token: pointers.NewStringPtr("pr0duct-t0k3n")},
+						}, nil
+					},
+					createFn: func(approval *models.Approval) (*models.Approval, error) {
+						require.Equal(t, testAppVersionID, approval.AppVersionID)
+						require.Equal(t, "qa@bitrise.io", approval.Approver)
+						require.Equal(t, "requested", approval.Status)
+						require.NotNil(t, approval.Token)
+						require.NotEmpty(t, *approval.Token)
+						return approval, nil
+					},
+				},
+				&testMailer{
+					sendEmailApprovalRequestFn: func(appVersion *models.AppVersion, approval *models.Approval, appDetails *bitrise.AppDetails, frontendBaseURL string) error {
+						require.Equal(t, testAppVersion, appVersion)
+						require.Equal(t, testAppDetails, appDetails)
+						require.Equal(t, "http://ship.bitrise.io", frontendBaseURL)
+						require.NotNil(t, approval.Token)
+						if approval.Approver == "product@bitrise.io" {
+							require.Equal(t, "pr0duct-t0k3n", *approval.Token)
+						}
+						sentTo = append(sentTo, approval.Approver)
+						return nil
+					},
+				}),
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppVersionApprovalRequestsResponse{
+				Data: []models.Approval{
+					{AppVersionID: testAppVersionID, Approver: "qa@bitrise.io", Status: "requested"},
+					{Approver: "product@bitrise.io", Status: "requested"},
+				},
+			},
+		})
+		require.Equal(t, []string{"qa@bitrise.io", "product@bitrise.io"}, sentTo)
+	})
+
+	t.Run("when the app has no approval requirements", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env:                testEnv(`[]`, &testApprovalService{}, &testMailer{}),
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   httpresponse.StandardErrorRespModel{Message: "The app has no approval requirements"},
+		})
+	})
+
+	t.Run("when every approver has approved the version", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: testEnv(`[{"approvals":1,"approvers":["qa@bitrise.io"]}]`,
+				&testApprovalService{
+					findAllFn: func(appVersion *models.AppVersion) ([]models.Approval, error) {
+						return []models.Approval{{Approver: "qa@bitrise.io", Status: "approved", Token: pointers.NewStringPtr("qa-t0k3n")}}, nil
+					},
+				}, &testMailer{}),
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   httpresponse.StandardErrorRespModel{Message: "No confirmed app contact has to approve the version"},
+		})
+	})
+
+	t.Run("when error happens at sending the email", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: testEnv(`[{"approvals":1,"approvers":["qa@bitrise.io"]}]`,
+				&testApprovalService{
+					findAllFn: func(appVersion *models.AppVersion) ([]models.Approval, error) {
+						return []models.Approval{}, nil
+					},
+					createFn: func(approval *models.Approval) (*models.Approval, error) {
+						return approval, nil
+					},
+				},
+				&testMailer{
+					sendEmailApprovalRequestFn: func(appVersion *models.AppVersion, approval *models.Approval, appDetails *bitrise.AppDetails, frontendBaseURL string) error {
+						return errors.New("SOME-SES-ERROR")
+					},
+				}),
+			expectedInternalErr: "SOME-SES-ERROR",
+		})
+	})
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/api-utils/httprequest"
+	"github.com/bitrise-io/api-utils/httpresponse"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// AppVersionApprovalRevokePostHandler revokes the valid approval of the app
+// contact authorized by its token, the approval is kept with the comment of
+// the revocation
+func AppVersionApprovalRevokePostHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Request) error {
+	authorizedApprovalID, err := GetAuthorizedApprovalIDFromContext(r.Context())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	var params AppVersionApprovalParams
+	defer httprequest.BodyCloseWithErrorLog(r)
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil && err != io.EOF {
+		return httpresponse.RespondWithBadRequestError(w, "Invalid request body, JSON decode failed")
+	}
+
+	if env.AppVersionService == nil {
+		return errors.New("No App Version Service defined for handler")
+	}
+	if env.ApprovalService == nil {
+		return errors.New("No Approval Service defined for handler")
+	}
+	if env.AppVersionEventService == nil {
+		return errors.New("No App Version Event Service defined for handler")
+	}
+
+	approval, appVersion, err := authorizedApproval(env, authorizedApprovalID)
+	switch {
+	case errors.Cause(err) == gorm.ErrRecordNotFound:
+		return httpresponse.RespondWithNotFoundError(w)
+	case err != nil:
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	if approval.Status != models.ApprovalStatusApproved {
+		return httpresponse.RespondWithUnprocessableEntity(w, []error{errors.New("approver: Has no approval to revoke")})
+	}
+
+	approval.Status = models.ApprovalStatusRevoked
+	approval.RevokeComment = params.Comment
+	err = env.ApprovalService.Update(approval, []string{"Status", "RevokeComment"})
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	_, err = env.AppVersionEventService.Create(&models.AppVersionEvent{
+		Text:         approvalEventText("Approval of "+approval.Approver+" was revoked", params.Comment),
+		AppVersionID: appVersion.ID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	return httpresponse.RespondWithSuccess(w, AppVersionApprovalResponse{Data: approval})
+}
@@ -0,0 +1,161 @@
+package services_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/addons-ship-backend/services"
+	ctxpkg "github.com/bitrise-io/api-utils/context"
+	"github.com/bitrise-io/api-utils/httpresponse"
+	"github.com/c2fo/testify/require"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+func Test_AppVersionApprovalRevokePostHandler(t *testing.T) {
+	httpMethod := "POST"
+	url := "/approve/revoke"
+	handler := services.AppVersionApprovalRevokePostHandler
+
+	testAppID := uuid.FromStringOrNil("211afc15-127a-40f9-8cbe-1dadc1f86cdf")
+	testAppVersionID := uuid.FromStringOrNil("de438ddc-98e5-4226-a5f4-fd2d53474879")
+	testApprovalID := uuid.FromStringOrNil("8a230385-0113-4cf3-a9c6-469a313e587a")
+	testAppVersion := &models.AppVersion{Record: models.Record{ID: testAppVersionID}, AppID: testAppID}
+	appVersionService := &testAppVersionService{
+		findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+			require.Equal(t, testAppVersionID, appVersion.ID)
+			return testAppVersion, nil
+		},
+	}
+
+	behavesAsServiceCravingHandler(t, httpMethod, url, handler, []string{"AppVersionService", "ApprovalService", "AppVersionEventService"}, ControllerTestCase{
+		contextElements: map[ctxpkg.RequestContextKey]interface{}{
+			services.ContextKeyAuthorizedApprovalID: testApprovalID,
+		},
+		env: &env.AppEnv{
+			AppVersionService:      &testAppVersionService{},
+			ApprovalService:        &testApprovalService{},
+			AppVersionEventService: &testAppVersionEventService{},
+		},
+	})
+
+	behavesAsContextCravingHandler(t, httpMethod, url, handler, []ctxpkg.RequestContextKey{services.ContextKeyAuthorizedApprovalID}, ControllerTestCase{
+		contextElements: map[ctxpkg.RequestContextKey]interface{}{
+			services.ContextKeyAuthorizedApprovalID: testApprovalID,
+		},
+		env: &env.AppEnv{
+			AppVersionService:      &testAppVersionService{},
+			ApprovalService:        &testApprovalService{},
+			AppVersionEventService: &testAppVersionEventService{},
+		},
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedApprovalID: testApprovalID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: appVersionService,
+				ApprovalService: &testApprovalService{
+					findFn: func(approval *models.Approval) (*models.Approval, error) {
+						require.Equal(t, testApprovalID, approval.ID)
+						return &models.Approval{AppVersionID: testAppVersionID, Approver: "qa@bitrise.io", Comment: "Tested", Status: "approved"}, nil
+					},
+					updateFn: func(approval *models.Approval, whitelist []string) error {
+						require.Equal(t, []string{"Status", "RevokeComment"}, whitelist)
+						require.Equal(t, "revoked", approval.Status)
+						require.Equal(t, "Crashes on start", approval.RevokeComment)
+						return nil
+					},
+				},
+				AppVersionEventService: &testAppVersionEventService{
+					createFn: func(event *models.AppVersionEvent) (*models.AppVersionEvent, error) {
+						require.Equal(t, "Approval of qa@bitrise.io was revoked: Crashes on start", event.Text)
+						require.Equal(t, testAppVersionID, event.AppVersionID)
+						return event, nil
+					},
+				},
+			},
+			requestBody:        `{"approval_token":"5om3-r4nd0m-5tr1ng","comment":"Crashes on start"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppVersionApprovalResponse{
+				Data: &models.Approval{
+					AppVersionID:  testAppVersionID,
+					Approver:      "qa@bitrise.io",
+					Comment:       "Tested",
+					Status:        "revoked",
+					RevokeComment: "Crashes on start",
+				},
+			},
+		})
+	})
+
+	t.Run("when approver has no approval to revoke", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedApprovalID: testApprovalID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: appVersionService,
+				ApprovalService: &testApprovalService{
+					findFn: func(approval *models.Approval) (*models.Approval, error) {
+						return &models.Approval{AppVersionID: testAppVersionID, Approver: "qa@bitrise.io", Status: "requested"}, nil
+					},
+				},
+				AppVersionEventService: &testAppVersionEventService{},
+			},
+			requestBody:        `{"approval_token":"5om3-r4nd0m-5tr1ng"}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedResponse: httpresponse.ValidationErrorRespModel{
+				Message: "Unprocessable Entity",
+				Errors:  []string{"approver: Has no approval to revoke"},
+			},
+		})
+	})
+
+	t.Run("when approval not found", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedApprovalID: testApprovalID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: &testAppVersionService{},
+				ApprovalService: &testApprovalService{
+					findFn: func(approval *models.Approval) (*models.Approval, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+				},
+				AppVersionEventService: &testAppVersionEventService{},
+			},
+			requestBody:        `{"approval_token":"5om3-r4nd0m-5tr1ng"}`,
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   httpresponse.StandardErrorRespModel{Message: "Not Found"},
+		})
+	})
+
+	t.Run("when error happens at updating approval", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedApprovalID: testApprovalID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: appVersionService,
+				ApprovalService: &testApprovalService{
+					findFn: func(approval *models.Approval) (*models.Approval, error) {
+						return &models.Approval{AppVersionID: testAppVersionID, Approver: "qa@bitrise.io", Status: "approved"}, nil
+					},
+					updateFn: func(approval *models.Approval, whitelist []string) error {
+						return errors.New("SOME-SQL-ERROR")
+					},
+				},
+				AppVersionEventService: &testAppVersionEventService{},
+			},
+			requestBody:         `{"approval_token":"5om3-r4nd0m-5tr1ng"}`,
+			expectedInternalErr: "SQL Error: SOME-SQL-ERROR",
+		})
+	})
+}
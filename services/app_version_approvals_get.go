@@ -0,0 +1,34 @@
+package services
+
+import (
+	"net/http"
+
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/api-utils/httpresponse"
+	"github.com/pkg/errors"
+)
+
+// AppVersionApprovalsGetResponse ...
+type AppVersionApprovalsGetResponse struct {
+	Data []models.Approval `json:"data"`
+}
+
+// AppVersionApprovalsGetHandler ...
+func AppVersionApprovalsGetHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Request) error {
+	authorizedAppVersionID, err := GetAuthorizedAppVersionIDFromContext(r.Context())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if env.ApprovalService == nil {
+		return errors.New("No Approval Service defined for handler")
+	}
+
+	approvals, err := env.ApprovalService.FindAll(&models.AppVersion{Record: models.Record{ID: authorizedAppVersionID}})
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	return httpresponse.RespondWithSuccess(w, AppVersionApprovalsGetResponse{Data: approvals})
+}
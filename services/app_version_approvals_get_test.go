@@ -0,0 +1,90 @@
+package services_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/addons-ship-backend/services"
+	ctxpkg "github.com/bitrise-io/api-utils/context"
+	"github.com/c2fo/testify/require"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+func Test_AppVersionApprovalsGetHandler(t *testing.T) {
+	httpMethod := "GET"
+	url := "/apps/{app-slug}/versions/{version-id}/approvals"
+	handler := services.AppVersionApprovalsGetHandler
+
+	testAppVersionID := uuid.FromStringOrNil("de438ddc-98e5-4226-a5f4-fd2d53474879")
+
+	behavesAsServiceCravingHandler(t, httpMethod, url, handler, []string{"ApprovalService"}, ControllerTestCase{
+		contextElements: map[ctxpkg.RequestContextKey]interface{}{
+			services.ContextKeyAuthorizedAppVersionID: uuid.NewV4(),
+		},
+		env: &env.AppEnv{
+			ApprovalService: &testApprovalService{
+				findAllFn: func(appVersion *models.AppVersion) ([]models.Approval, error) {
+					return []models.Approval{}, nil
+				},
+			},
+		},
+	})
+
+	behavesAsContextCravingHandler(t, httpMethod, url, handler, []ctxpkg.RequestContextKey{services.ContextKeyAuthorizedAppVersionID}, ControllerTestCase{
+		contextElements: map[ctxpkg.RequestContextKey]interface{}{
+			services.ContextKeyAuthorizedAppVersionID: uuid.NewV4(),
+		},
+		env: &env.AppEnv{
+			ApprovalService: &testApprovalService{
+				findAllFn: func(appVersion *models.AppVersion) ([]models.Approval, error) {
+					return []models.Approval{}, nil
+				},
+			},
+		},
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				ApprovalService: &testApprovalService{
+					findAllFn: func(appVersion *models.AppVersion) ([]models.Approval, error) {
+						require.Equal(t, testAppVersionID, appVersion.ID)
+						return []models.Approval{
+							{Approver: "qa@bitrise.io", Comment: "Tested", Status: "approved"},
+							{Approver: "product@bitrise.io", Status: "invalidated"},
+						}, nil
+					},
+				},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppVersionApprovalsGetResponse{
+				Data: []models.Approval{
+					{Approver: "qa@bitrise.io", Comment: "Tested", Status: "approved"},
+					{Approver: "product@bitrise.io", Status: "invalidated"},
+				},
+			},
+		})
+	})
+
+	t.Run("when error happens at finding approvals", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				ApprovalService: &testApprovalService{
+					findAllFn: func(appVersion *models.AppVersion) ([]models.Approval, error) {
+						return nil, errors.New("SOME-SQL-ERROR")
+					},
+				},
+			},
+			expectedInternalErr: "SQL Error: SOME-SQL-ERROR",
+		})
+	})
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"net/http"
+
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/api-utils/httpresponse"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// AppVersionArchiveResponse ...
+type AppVersionArchiveResponse struct {
+	Data *models.AppVersion `json:"data"`
+}
+
+// AppVersionArchivePostHandler hides an app version from the version list
+func AppVersionArchivePostHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Request) error {
+	authorizedAppVersionID, err := GetAuthorizedAppVersionIDFromContext(r.Context())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if env.AppVersionService == nil {
+		return errors.New("No App Version Service defined for handler")
+	}
+	if env.TimeService == nil {
+		return errors.New("No Time Service defined for handler")
+	}
+
+	appVersion, err := env.AppVersionService.Find(
+		&models.AppVersion{Record: models.Record{ID: authorizedAppVersionID}},
+	)
+	switch {
+	case errors.Cause(err) == gorm.ErrRecordNotFound:
+		return httpresponse.RespondWithNotFoundError(w)
+	case err != nil:
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	verrs, err := archiveAppVersion(env, appVersion)
+	if len(verrs) > 0 {
+		return httpresponse.RespondWithUnprocessableEntity(w, verrs)
+	}
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return httpresponse.RespondWithSuccess(w, AppVersionArchiveResponse{Data: appVersion})
+}
+
+// archiveAppVersion sets the archive time of an app version, unless it's
+// archived already
+func archiveAppVersion(env *env.AppEnv, appVersion *models.AppVersion) ([]error, error) {
+	if appVersion.ArchivedAt != nil {
+		return nil, nil
+	}
+	archivedAt := env.TimeService.Now()
+	appVersion.ArchivedAt = &archivedAt
+	verrs, err := env.AppVersionService.Update(appVersion, []string{"ArchivedAt"})
+	if err != nil {
+		return nil, errors.Wrap(err, "SQL Error")
+	}
+	return verrs, nil
+}
@@ -0,0 +1,154 @@
+package services_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/addons-ship-backend/services"
+	ctxpkg "github.com/bitrise-io/api-utils/context"
+	"github.com/bitrise-io/api-utils/httpresponse"
+	"github.com/c2fo/testify/require"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+func Test_AppVersionArchivePostHandler(t *testing.T) {
+	httpMethod := "POST"
+	url := "/apps/{app-slug}/versions/{version-id}/archive"
+	handler := services.AppVersionArchivePostHandler
+
+	testAppVersionID := uuid.FromStringOrNil("de438ddc-98e5-4226-a5f4-fd2d53474879")
+	testArchivedAt := time.Date(2019, 11, 20, 8, 35, 12, 0, time.UTC)
+
+	behavesAsServiceCravingHandler(t, httpMethod, url, handler, []string{"AppVersionService", "TimeService"}, ControllerTestCase{
+		contextElements: map[ctxpkg.RequestContextKey]interface{}{
+			services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+		},
+		env: &env.AppEnv{
+			AppVersionService: &testAppVersionService{},
+			TimeService:       &testTimeService{},
+		},
+	})
+
+	behavesAsContextCravingHandler(t, httpMethod, url, handler, []ctxpkg.RequestContextKey{services.ContextKeyAuthorizedAppVersionID}, ControllerTestCase{
+		contextElements: map[ctxpkg.RequestContextKey]interface{}{
+			services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+		},
+		env: &env.AppEnv{
+			AppVersionService: &testAppVersionService{},
+			TimeService:       &testTimeService{},
+		},
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: &testAppVersionService{
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						require.Equal(t, testAppVersionID, appVersion.ID)
+						return &models.AppVersion{Record: models.Record{ID: testAppVersionID}, Platform: "ios"}, nil
+					},
+					updateFn: func(appVersion *models.AppVersion, whitelist []string) ([]error, error) {
+						require.Equal(t, []string{"ArchivedAt"}, whitelist)
+						require.Equal(t, testArchivedAt, *appVersion.ArchivedAt)
+						return nil, nil
+					},
+				},
+				TimeService: &testTimeService{nowFn: func() time.Time { return testArchivedAt }},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppVersionArchiveResponse{
+				Data: &models.AppVersion{Record: models.Record{ID: testAppVersionID}, Platform: "ios", ArchivedAt: &testArchivedAt},
+			},
+		})
+	})
+
+	t.Run("when the version is archived already", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: &testAppVersionService{
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						return &models.AppVersion{Record: models.Record{ID: testAppVersionID}, ArchivedAt: &testArchivedAt}, nil
+					},
+				},
+				TimeService: &testTimeService{nowFn: func() time.Time { return testArchivedAt }},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppVersionArchiveResponse{
+				Data: &models.AppVersion{Record: models.Record{ID: testAppVersionID}, ArchivedAt: &testArchivedAt},
+			},
+		})
+	})
+
+	t.Run("when validation error happens at updating the version", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: &testAppVersionService{
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						return &models.AppVersion{}, nil
+					},
+					updateFn: func(appVersion *models.AppVersion, whitelist []string) ([]error, error) {
+						return []error{errors.New("version: Cannot be empty")}, nil
+					},
+				},
+				TimeService: &testTimeService{nowFn: func() time.Time { return testArchivedAt }},
+			},
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedResponse: httpresponse.ValidationErrorRespModel{
+				Message: "Unprocessable Entity",
+				Errors:  []string{"version: Cannot be empty"},
+			},
+		})
+	})
+
+	t.Run("when error happens at updating the version", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: &testAppVersionService{
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						return &models.AppVersion{}, nil
+					},
+					updateFn: func(appVersion *models.AppVersion, whitelist []string) ([]error, error) {
+						return nil, errors.New("SOME-SQL-ERROR")
+					},
+				},
+				TimeService: &testTimeService{nowFn: func() time.Time { return testArchivedAt }},
+			},
+			expectedInternalErr: "SQL Error: SOME-SQL-ERROR",
+		})
+	})
+
+	t.Run("when app version not found in database", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: &testAppVersionService{
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+				},
+				TimeService: &testTimeService{nowFn: func() time.Time { return testArchivedAt }},
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   httpresponse.StandardErrorRespModel{Message: "Not Found"},
+		})
+	})
+}
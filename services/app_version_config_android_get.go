@@ -106,6 +106,20 @@ func AppVersionAndroidConfigGetHandler(env *env.AppEnv, w http.ResponseWriter, r
 		return errors.WithStack(err)
 	}
 	config.MetaData.Track = androidSettings.Track
+	// the track of the publish task overrides the one in the settings
+	if track := r.URL.Query().Get("track"); track != "" {
+		config.MetaData.Track = track
+	}
+	rollout, err := androidRolloutFromQuery(androidSettings.Rollout(), r.URL.Query())
+	if err != nil {
+		return httpresponse.RespondWithBadRequestError(w, err.Error())
+	}
+	config.MetaData.UserFraction = rollout.UserFraction
+	config.MetaData.ReleaseStatus = rollout.ReleaseStatus
+	config.MetaData.ReleaseName = rollout.ReleaseName
+	config.MetaData.InAppUpdatePriority = rollout.InAppUpdatePriority
+	config.MetaData.RolloutOnly = r.URL.Query().Get("rollout_only") == "true"
+	config.MetaData.PromoteFromTrack = r.URL.Query().Get("promote_from")
 
 	selectedServiceAccount, err := env.BitriseAPI.GetServiceAccountFile(appVersion.App.BitriseAPIToken, appVersion.App.AppSlug, androidSettings.SelectedServiceAccount)
 	if err != nil {
@@ -223,4 +237,15 @@ type MetaData struct {
 	PackageName        string       `json:"package_name"`
 	ServiceAccountJSON string       `json:"service_account_json"`
 	Keystore           Keystore     `json:"keystore"`
+	// rollout of the release, see models.AndroidRollout
+	UserFraction        float64 `json:"user_fraction,omitempty"`
+	ReleaseStatus       string  `json:"release_status,omitempty"`
+	ReleaseName         string  `json:"release_name,omitempty"`
+	InAppUpdatePriority int     `json:"in_app_update_priority,omitempty"`
+	// RolloutOnly is set when the rollout of an already uploaded release has to
+	// be updated, without uploading the artifacts again
+	RolloutOnly bool `json:"rollout_only,omitempty"`
+	// PromoteFromTrack is set when the release has to be moved from the track to
+	// Track, instead of uploading the artifacts again
+	PromoteFromTrack string `json:"promote_from_track,omitempty"`
 }
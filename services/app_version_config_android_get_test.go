@@ -121,6 +121,77 @@ func Test_AppVersionAndroidConfigGetHandler(t *testing.T) {
 		})
 	})
 
+	t.Run("ok - track and rollout of the publish task", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url+"?track=beta&release_status=inProgress&user_fraction=0.5&rollout_only=true", handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: uuid.NewV4(),
+			},
+			env: &env.AppEnv{
+				AppVersionService: &testAppVersionService{
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						appVersion.ArtifactInfoData = json.RawMessage(`{}`)
+						appVersion.AppStoreInfoData = json.RawMessage(`{}`)
+						return appVersion, nil
+					},
+				},
+				FeatureGraphicService: &testFeatureGraphicService{
+					findFn: func(featureGraphic *models.FeatureGraphic) (*models.FeatureGraphic, error) {
+						featureGraphic.AppVersion = models.AppVersion{App: models.App{}}
+						return featureGraphic, nil
+					},
+				},
+				AWS: &providers.AWSMock{
+					GeneratePresignedGETURLFn: func(path string, expiration time.Duration) (string, error) {
+						return "", nil
+					},
+				},
+				BitriseAPI: &testBitriseAPI{
+					getAppDetailsFn: func(apiToken, appSlug string) (*bitrise.AppDetails, error) {
+						return &bitrise.AppDetails{}, nil
+					},
+					getServiceAccountFileFn: func(apiToken, appSlug, serviceJSONSlug string) (*bitrise.GenericProjectFile, error) {
+						return &bitrise.GenericProjectFile{}, nil
+					},
+					getAndroidKeystoreFileFn: func(apiToken, appSlug, keystoreSlug string) (*bitrise.AndroidKeystoreFile, error) {
+						return &bitrise.AndroidKeystoreFile{}, nil
+					},
+					getArtifactsFn: func(apiToken, appSlug, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
+						return []bitrise.ArtifactListElementResponseModel{}, nil
+					},
+					getArtifactFn: func(apiToken, appSlug, buildSlug, artifactSlug string) (*bitrise.ArtifactShowResponseItemModel, error) {
+						return nil, nil
+					},
+				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						appSettings.AndroidSettingsData = json.RawMessage(`{"track":"alpha","release_status":"halted","user_fraction":0.1,"release_name":"1.0.0","in_app_update_priority":3,"selected_service_account":"service-account-slug","selected_keystore_file":"android-keystore-slug"}`)
+						return appSettings, nil
+					},
+				},
+				ScreenshotService: &testScreenshotService{
+					findAllFn: func(appVersion *models.AppVersion) ([]models.Screenshot, error) {
+						return []models.Screenshot{}, nil
+					},
+				},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppVersionAndroidConfigGetResponse{
+				MetaData: services.MetaData{
+					Track:               "beta",
+					ReleaseStatus:       "inProgress",
+					UserFraction:        0.5,
+					ReleaseName:         "1.0.0",
+					InAppUpdatePriority: 3,
+					RolloutOnly:         true,
+					ListingInfo: map[string]services.ListingInfo{
+						"en-GB": services.ListingInfo{},
+					},
+				},
+				Artifacts: []string{},
+			},
+		})
+	})
+
 	t.Run("ok - more complex", func(t *testing.T) {
 		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
 			contextElements: map[ctxpkg.RequestContextKey]interface{}{
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/bitrise-io/addons-ship-backend/env"
 	"github.com/bitrise-io/addons-ship-backend/models"
@@ -46,6 +47,7 @@ func AppVersionIosConfigGetHandler(env *env.AppEnv, w http.ResponseWriter, r *ht
 	if err != nil {
 		return errors.Wrap(err, "SQL Error")
 	}
+	config.MetaData.Platform = appStorePlatform(appVersion.Platform)
 
 	storeInfo, err := appVersion.AppStoreInfo()
 	if err != nil {
@@ -102,8 +104,30 @@ func AppVersionIosConfigGetHandler(env *env.AppEnv, w http.ResponseWriter, r *ht
 
 	config.MetaData.ExportOptions = ExportOptions{IncludeBitcode: iosSettings.IncludeBitCode}
 	config.MetaData.SKU = iosSettings.AppSKU
-	config.MetaData.AppleUser = iosSettings.AppleDeveloperAccountEmail
-	config.MetaData.AppleAppSpecificPassword = iosSettings.ApplSpecificPassword
+	// the API key replaces the Apple ID and the app-specific password
+	if iosSettings.HasAPIKey() {
+		privateKey, err := appSettings.IosAPIKey()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		config.MetaData.APIKey = &AppStoreConnectAPIKey{
+			IssuerID:   iosSettings.APIKeyIssuerID,
+			KeyID:      iosSettings.APIKeyID,
+			PrivateKey: privateKey,
+		}
+	} else {
+		config.MetaData.AppleUser = iosSettings.AppleDeveloperAccountEmail
+		config.MetaData.AppleAppSpecificPassword = iosSettings.ApplSpecificPassword
+	}
+
+	releaseOptions, err := appVersion.IosReleaseOptions()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	config.MetaData.ReleaseType = releaseOptions.ReleaseType
+	config.MetaData.ScheduledReleaseDate = releaseOptions.ScheduledReleaseDate
+	config.MetaData.PhasedRelease = releaseOptions.PhasedRelease
+	config.MetaData.ResetRatings = releaseOptions.ResetRatings
 
 	artifacts, err := env.BitriseAPI.GetArtifacts(appVersion.App.BitriseAPIToken, appVersion.App.AppSlug, appVersion.BuildSlug)
 	if err != nil {
@@ -111,7 +135,7 @@ func AppVersionIosConfigGetHandler(env *env.AppEnv, w http.ResponseWriter, r *ht
 	}
 
 	for _, artifact := range artifacts {
-		if artifact.IsXCodeArchive() {
+		if artifact.IsXCodeArchive() && isIosArtifactOf(artifact, appVersion) {
 			artifactData, err := env.BitriseAPI.GetArtifact(appVersion.App.BitriseAPIToken, appVersion.App.AppSlug, appVersion.BuildSlug, artifact.Slug)
 			if err != nil {
 				return errors.WithStack(err)
@@ -138,6 +162,21 @@ func newIosScreenshotsResponse(screenshotData []models.Screenshot, env *env.AppE
 	return scs, nil
 }
 
+// appStorePlatform returns the App Store Connect platform of the versions of a
+// platform. watchOS apps are uploaded to the App Store Connect record of
+// iOS apps.
+func appStorePlatform(platform string) string {
+	switch platform {
+	case "ios", "watchos":
+		return "ios"
+	case "tvos":
+		return "appletvos"
+	case "macos":
+		return "osx"
+	}
+	return ""
+}
+
 // IosListingInfo ...
 type IosListingInfo struct {
 	Screenshots     map[string][]string `json:"screenshots" yaml:"screenshots"`
@@ -160,12 +199,26 @@ type ExportOptions struct {
 	IncludeBitcode bool `json:"include_bitcode,string"`
 }
 
+// AppStoreConnectAPIKey ...
+type AppStoreConnectAPIKey struct {
+	IssuerID   string `json:"issuer_id"`
+	KeyID      string `json:"key_id"`
+	PrivateKey string `json:"private_key"`
+}
+
 // IosConfigMetaData ...
 type IosConfigMetaData struct {
 	ListingInfoMap           map[string]IosListingInfo `json:"listing_info"`
+	Platform                 string                    `json:"platform,omitempty"`
 	Signing                  Signing                   `json:"signing"`
 	ExportOptions            ExportOptions             `json:"export_options"`
 	SKU                      string                    `json:"sku"`
 	AppleUser                string                    `json:"apple_user"`
 	AppleAppSpecificPassword string                    `json:"apple_app_specific_password"`
+	APIKey                   *AppStoreConnectAPIKey    `json:"api_key,omitempty"`
+	// release options of the version, see models.IosReleaseOptions
+	ReleaseType          string     `json:"release_type,omitempty"`
+	ScheduledReleaseDate *time.Time `json:"scheduled_release_date,omitempty"`
+	PhasedRelease        bool       `json:"phased_release,omitempty"`
+	ResetRatings         bool       `json:"reset_ratings,omitempty"`
 }
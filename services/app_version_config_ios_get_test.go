@@ -13,6 +13,7 @@ import (
 	"github.com/bitrise-io/addons-ship-backend/services"
 	ctxpkg "github.com/bitrise-io/api-utils/context"
 	"github.com/bitrise-io/api-utils/providers"
+	"github.com/bitrise-io/go-utils/envutil"
 	"github.com/bitrise-io/go-utils/pointers"
 	"github.com/c2fo/testify/require"
 	"github.com/jinzhu/gorm"
@@ -116,7 +117,81 @@ func Test_AppVersionIosConfigGetHandler(t *testing.T) {
 		})
 	})
 
+	t.Run("ok - app store connect api key", func(t *testing.T) {
+		revokeFn, err := envutil.RevokableSetenv("APP_SETTINGS_ENCRYPT_KEY", "06042e86a7bd421c642c8c3e4ab13840")
+		require.NoError(t, err)
+		defer func() { require.NoError(t, revokeFn()) }()
+
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: uuid.NewV4(),
+			},
+			env: &env.AppEnv{
+				AppVersionService: &testAppVersionService{
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						appVersion.ArtifactInfoData = json.RawMessage(`{}`)
+						appVersion.AppStoreInfoData = json.RawMessage(`{}`)
+						return appVersion, nil
+					},
+				},
+				AWS: &providers.AWSMock{
+					GeneratePresignedGETURLFn: func(path string, expiration time.Duration) (string, error) {
+						return "", nil
+					},
+				},
+				BitriseAPI: &testBitriseAPI{
+					getAppDetailsFn: func(apiToken, appSlug string) (*bitrise.AppDetails, error) {
+						return &bitrise.AppDetails{}, nil
+					},
+					getProvisioningProfileFn: func(apiToken, appSlug, provProfileSlug string) (*bitrise.ProvisioningProfile, error) {
+						return &bitrise.ProvisioningProfile{Slug: "prov-profile-slug", DownloadURL: "http://here.you.can.find.the.prov.profile"}, nil
+					},
+					getCodeSigningIdentityFn: func(apiToken, appSlug, codeSignIDSlug string) (*bitrise.CodeSigningIdentity, error) {
+						return &bitrise.CodeSigningIdentity{Slug: "code-signing-slug", DownloadURL: "http://here.you.can.find.the.code.signing.id", CertificatePassword: "super-secret"}, nil
+					},
+					getArtifactsFn: func(apiToken, appSlug, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
+						return []bitrise.ArtifactListElementResponseModel{}, nil
+					},
+					getArtifactFn: func(apiToken, appSlug, buildSlug, artifactSlug string) (*bitrise.ArtifactShowResponseItemModel, error) {
+						return nil, nil
+					},
+				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						appSettings.IosSettingsData = json.RawMessage(`{"apple_developer_account_email":"someone@bitrise.io","app_specific_password":"secret","api_key_issuer_id":"69a6de7f-c4f6-47e3-e053-5b8c7c11a4d1","api_key_id":"2X9R4HXF34","selected_app_store_provisioning_profiles":["prov-profile-slug"],"selected_code_signing_identity":"code-signing-slug"}`)
+						require.NoError(t, appSettings.SetIosAPIKey("my-private-key"))
+						return appSettings, nil
+					},
+				},
+				ScreenshotService: &testScreenshotService{
+					findAllFn: func(appVersion *models.AppVersion) ([]models.Screenshot, error) {
+						return []models.Screenshot{}, nil
+					},
+				},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppVersionIosConfigGetResponse{
+				MetaData: services.IosConfigMetaData{
+					ListingInfoMap: map[string]services.IosListingInfo{
+						"en-US": services.IosListingInfo{Screenshots: map[string][]string{}},
+					},
+					APIKey: &services.AppStoreConnectAPIKey{
+						IssuerID:   "69a6de7f-c4f6-47e3-e053-5b8c7c11a4d1",
+						KeyID:      "2X9R4HXF34",
+						PrivateKey: "my-private-key",
+					},
+					Signing: services.Signing{
+						AppStoreProfileURL:                "http://here.you.can.find.the.prov.profile",
+						DistributionCertificateURL:        "http://here.you.can.find.the.code.signing.id",
+						DistributionCertificatePasshprase: "super-secret",
+					},
+				},
+			},
+		})
+	})
+
 	t.Run("ok - more complex", func(t *testing.T) {
+		testReleaseDate := time.Date(2019, 12, 1, 10, 0, 0, 0, time.UTC)
 		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
 			contextElements: map[ctxpkg.RequestContextKey]interface{}{
 				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
@@ -125,6 +200,7 @@ func Test_AppVersionIosConfigGetHandler(t *testing.T) {
 				AppVersionService: &testAppVersionService{
 					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
 						appVersion.ArtifactInfoData = json.RawMessage(`{"package_name":"myPackage"}`)
+						appVersion.IosReleaseOptionsData = json.RawMessage(`{"release_type":"scheduled","scheduled_release_date":"2019-12-01T10:00:00Z","phased_release":true}`)
 						appVersion.AppStoreInfoData = json.RawMessage(`{` +
 							`"full_description":"A bit longer description","promotional_text":"This is an awesome app, you should download it"` +
 							`,"support_url":"http://we-will-help.you","marketing_url":"http://purchase-the.app"` +
@@ -232,12 +308,140 @@ func Test_AppVersionIosConfigGetHandler(t *testing.T) {
 					SKU:                      "some-string",
 					AppleUser:                "my.apple@email.com",
 					AppleAppSpecificPassword: "my-super-secret-pass",
+					ReleaseType:              "scheduled",
+					ScheduledReleaseDate:     &testReleaseDate,
+					PhasedRelease:            true,
 				},
 				Artifacts: []string{"http://the-url-for-artifact.io"},
 			},
 		})
 	})
 
+	t.Run("ok - tvOS version", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: uuid.NewV4(),
+			},
+			env: &env.AppEnv{
+				AppVersionService: &testAppVersionService{
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						appVersion.Platform = "tvos"
+						appVersion.ArtifactInfoData = json.RawMessage(`{}`)
+						appVersion.AppStoreInfoData = json.RawMessage(`{}`)
+						return appVersion, nil
+					},
+				},
+				AWS: &providers.AWSMock{},
+				BitriseAPI: &testBitriseAPI{
+					getProvisioningProfileFn: func(apiToken, appSlug, provProfileSlug string) (*bitrise.ProvisioningProfile, error) {
+						return &bitrise.ProvisioningProfile{Slug: "prov-profile-slug", DownloadURL: "http://here.you.can.find.the.prov.profile"}, nil
+					},
+					getCodeSigningIdentityFn: func(apiToken, appSlug, codeSignIDSlug string) (*bitrise.CodeSigningIdentity, error) {
+						return &bitrise.CodeSigningIdentity{Slug: "code-signing-slug", DownloadURL: "http://here.you.can.find.the.code.signing.id", CertificatePassword: "super-secret"}, nil
+					},
+					getArtifactsFn: func(apiToken, appSlug, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
+						return []bitrise.ArtifactListElementResponseModel{}, nil
+					},
+				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						appSettings.IosSettingsData = json.RawMessage(`{"selected_app_store_provisioning_profiles":["prov-profile-slug"],"selected_code_signing_identity":"code-signing-slug"}`)
+						return appSettings, nil
+					},
+				},
+				ScreenshotService: &testScreenshotService{
+					findAllFn: func(appVersion *models.AppVersion) ([]models.Screenshot, error) {
+						return []models.Screenshot{}, nil
+					},
+				},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppVersionIosConfigGetResponse{
+				MetaData: services.IosConfigMetaData{
+					ListingInfoMap: map[string]services.IosListingInfo{
+						"en-US": services.IosListingInfo{Screenshots: map[string][]string{}},
+					},
+					Platform: "appletvos",
+					Signing: services.Signing{
+						AppStoreProfileURL:                "http://here.you.can.find.the.prov.profile",
+						DistributionCertificateURL:        "http://here.you.can.find.the.code.signing.id",
+						DistributionCertificatePasshprase: "super-secret",
+					},
+				},
+			},
+		})
+	})
+
+	t.Run("ok - build with an archive per bundle ID", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: uuid.NewV4(),
+			},
+			env: &env.AppEnv{
+				AppVersionService: &testAppVersionService{
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						appVersion.ArtifactInfoData = json.RawMessage(`{}`)
+						appVersion.AppStoreInfoData = json.RawMessage(`{}`)
+						appVersion.BundleID = "io.bitrise.white-label"
+						return appVersion, nil
+					},
+				},
+				AWS: &providers.AWSMock{},
+				BitriseAPI: &testBitriseAPI{
+					getProvisioningProfileFn: func(apiToken, appSlug, provProfileSlug string) (*bitrise.ProvisioningProfile, error) {
+						return &bitrise.ProvisioningProfile{Slug: "prov-profile-slug", DownloadURL: "http://here.you.can.find.the.prov.profile"}, nil
+					},
+					getCodeSigningIdentityFn: func(apiToken, appSlug, codeSignIDSlug string) (*bitrise.CodeSigningIdentity, error) {
+						return &bitrise.CodeSigningIdentity{Slug: "code-signing-slug", DownloadURL: "http://here.you.can.find.the.code.signing.id", CertificatePassword: "super-secret"}, nil
+					},
+					getArtifactsFn: func(apiToken, appSlug, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
+						return []bitrise.ArtifactListElementResponseModel{
+							bitrise.ArtifactListElementResponseModel{
+								Title:        "main.xcarchive.zip",
+								Slug:         "main-artifact-slug",
+								ArtifactMeta: &bitrise.ArtifactMeta{AppInfo: bitrise.AppInfo{BundleID: "io.bitrise.app"}},
+							},
+							bitrise.ArtifactListElementResponseModel{
+								Title:        "white-label.xcarchive.zip",
+								Slug:         "white-label-artifact-slug",
+								ArtifactMeta: &bitrise.ArtifactMeta{AppInfo: bitrise.AppInfo{BundleID: "io.bitrise.white-label"}},
+							},
+						}, nil
+					},
+					getArtifactFn: func(apiToken, appSlug, buildSlug, artifactSlug string) (*bitrise.ArtifactShowResponseItemModel, error) {
+						require.Equal(t, "white-label-artifact-slug", artifactSlug)
+						return &bitrise.ArtifactShowResponseItemModel{DownloadPath: pointers.NewStringPtr("http://the-url-for-white-label-artifact.io")}, nil
+					},
+				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						appSettings.IosSettingsData = json.RawMessage(`{"selected_app_store_provisioning_profiles":["prov-profile-slug"],"selected_code_signing_identity":"code-signing-slug"}`)
+						return appSettings, nil
+					},
+				},
+				ScreenshotService: &testScreenshotService{
+					findAllFn: func(appVersion *models.AppVersion) ([]models.Screenshot, error) {
+						return []models.Screenshot{}, nil
+					},
+				},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppVersionIosConfigGetResponse{
+				MetaData: services.IosConfigMetaData{
+					ListingInfoMap: map[string]services.IosListingInfo{
+						"en-US": services.IosListingInfo{Screenshots: map[string][]string{}},
+					},
+					Signing: services.Signing{
+						AppStoreProfileURL:                "http://here.you.can.find.the.prov.profile",
+						DistributionCertificateURL:        "http://here.you.can.find.the.code.signing.id",
+						DistributionCertificatePasshprase: "super-secret",
+					},
+				},
+				Artifacts: []string{"http://the-url-for-white-label-artifact.io"},
+			},
+		})
+	})
+
 	t.Run("when it's failed to find app version", func(t *testing.T) {
 		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
 			contextElements: map[ctxpkg.RequestContextKey]interface{}{
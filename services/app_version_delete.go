@@ -0,0 +1,93 @@
+package services
+
+import (
+	"net/http"
+
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/api-utils/httpresponse"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// AppVersionDeleteResponse ...
+type AppVersionDeleteResponse struct {
+	Data *models.AppVersion `json:"data"`
+}
+
+// AppVersionDeleteHandler deletes an app version with its files on S3. The
+// version gets archived right away, and deleted by a worker job. A version
+// which is being published, or has a pending scheduled publish, can't be
+// deleted.
+func AppVersionDeleteHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Request) error {
+	authorizedAppVersionID, err := GetAuthorizedAppVersionIDFromContext(r.Context())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if env.AppVersionService == nil {
+		return errors.New("No App Version Service defined for handler")
+	}
+	if env.WorkerService == nil {
+		return errors.New("No Worker Service defined for handler")
+	}
+	if env.ScheduledPublishService == nil {
+		return errors.New("No Scheduled Publish Service defined for handler")
+	}
+	if env.TimeService == nil {
+		return errors.New("No Time Service defined for handler")
+	}
+
+	appVersion, err := env.AppVersionService.Find(
+		&models.AppVersion{Record: models.Record{ID: authorizedAppVersionID}},
+	)
+	switch {
+	case errors.Cause(err) == gorm.ErrRecordNotFound:
+		return httpresponse.RespondWithNotFoundError(w)
+	case err != nil:
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	verrs, err := ValidateAppVersionDeletion(env, appVersion)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if len(verrs) > 0 {
+		return httpresponse.RespondWithUnprocessableEntity(w, verrs)
+	}
+
+	verrs, err = archiveAppVersion(env, appVersion)
+	if len(verrs) > 0 {
+		return httpresponse.RespondWithUnprocessableEntity(w, verrs)
+	}
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = env.WorkerService.EnqueueDeleteAppVersion(appVersion.ID)
+	if err != nil {
+		return errors.Wrap(err, "Worker Error")
+	}
+
+	return httpresponse.RespondWithSuccess(w, AppVersionDeleteResponse{Data: appVersion})
+}
+
+// ValidateAppVersionDeletion returns why an app version can't be deleted: the
+// version is being published, or it has a pending scheduled publish
+func ValidateAppVersionDeletion(env *env.AppEnv, appVersion *models.AppVersion) ([]error, error) {
+	if appVersion.Status == models.AppVersionStatusPublishing {
+		return []error{errors.New("status: Version is being published")}, nil
+	}
+	if env.ScheduledPublishService == nil {
+		return nil, errors.New("No Scheduled Publish Service defined for handler")
+	}
+	scheduledPublishes, err := env.ScheduledPublishService.FindAll(appVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "SQL Error")
+	}
+	for _, scheduledPublish := range scheduledPublishes {
+		if scheduledPublish.Pending() {
+			return []error{errors.New("status: Version has a scheduled publish")}, nil
+		}
+	}
+	return nil, nil
+}
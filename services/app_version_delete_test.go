@@ -0,0 +1,241 @@
+package services_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/addons-ship-backend/services"
+	ctxpkg "github.com/bitrise-io/api-utils/context"
+	"github.com/bitrise-io/api-utils/httpresponse"
+	"github.com/c2fo/testify/require"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+func Test_AppVersionDeleteHandler(t *testing.T) {
+	httpMethod := "DELETE"
+	url := "/apps/{app-slug}/versions/{version-id}"
+	handler := services.AppVersionDeleteHandler
+
+	testAppVersionID := uuid.FromStringOrNil("de438ddc-98e5-4226-a5f4-fd2d53474879")
+	testArchivedAt := time.Date(2019, 11, 20, 8, 35, 12, 0, time.UTC)
+
+	behavesAsServiceCravingHandler(t, httpMethod, url, handler, []string{"AppVersionService", "WorkerService", "ScheduledPublishService", "TimeService"}, ControllerTestCase{
+		contextElements: map[ctxpkg.RequestContextKey]interface{}{
+			services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+		},
+		env: &env.AppEnv{
+			AppVersionService:       &testAppVersionService{},
+			WorkerService:           &testWorkerService{},
+			ScheduledPublishService: &testScheduledPublishService{},
+			TimeService:             &testTimeService{},
+		},
+	})
+
+	behavesAsContextCravingHandler(t, httpMethod, url, handler, []ctxpkg.RequestContextKey{services.ContextKeyAuthorizedAppVersionID}, ControllerTestCase{
+		contextElements: map[ctxpkg.RequestContextKey]interface{}{
+			services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+		},
+		env: &env.AppEnv{
+			AppVersionService:       &testAppVersionService{},
+			WorkerService:           &testWorkerService{},
+			ScheduledPublishService: &testScheduledPublishService{},
+			TimeService:             &testTimeService{},
+		},
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		deleteEnqueued := false
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				ScheduledPublishService: &testScheduledPublishService{
+					findAllFn: func(appVersion *models.AppVersion) ([]models.ScheduledPublish, error) {
+						return []models.ScheduledPublish{{Status: "started"}, {Status: "canceled"}}, nil
+					},
+				},
+				TimeService: &testTimeService{nowFn: func() time.Time { return testArchivedAt }},
+				AppVersionService: &testAppVersionService{
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						require.Equal(t, testAppVersionID, appVersion.ID)
+						return &models.AppVersion{Record: models.Record{ID: testAppVersionID}}, nil
+					},
+					updateFn: func(appVersion *models.AppVersion, whitelist []string) ([]error, error) {
+						require.Equal(t, []string{"ArchivedAt"}, whitelist)
+						require.Equal(t, testArchivedAt, *appVersion.ArchivedAt)
+						return nil, nil
+					},
+				},
+				WorkerService: &testWorkerService{
+					enqueueDeleteAppVersionFn: func(appVersionID uuid.UUID) error {
+						require.Equal(t, testAppVersionID, appVersionID)
+						deleteEnqueued = true
+						return nil
+					},
+				},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppVersionDeleteResponse{
+				Data: &models.AppVersion{Record: models.Record{ID: testAppVersionID}, ArchivedAt: &testArchivedAt},
+			},
+		})
+		require.True(t, deleteEnqueued)
+	})
+
+	t.Run("when error happens at enqueuing the delete job", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				ScheduledPublishService: &testScheduledPublishService{
+					findAllFn: func(appVersion *models.AppVersion) ([]models.ScheduledPublish, error) {
+						return []models.ScheduledPublish{{Status: "started"}, {Status: "canceled"}}, nil
+					},
+				},
+				TimeService: &testTimeService{nowFn: func() time.Time { return testArchivedAt }},
+				AppVersionService: &testAppVersionService{
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						return &models.AppVersion{ArchivedAt: &testArchivedAt}, nil
+					},
+				},
+				WorkerService: &testWorkerService{
+					enqueueDeleteAppVersionFn: func(appVersionID uuid.UUID) error {
+						return errors.New("SOME-WORKER-ERROR")
+					},
+				},
+			},
+			expectedInternalErr: "Worker Error: SOME-WORKER-ERROR",
+		})
+	})
+
+	t.Run("when error happens at archiving the version", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				ScheduledPublishService: &testScheduledPublishService{
+					findAllFn: func(appVersion *models.AppVersion) ([]models.ScheduledPublish, error) {
+						return []models.ScheduledPublish{{Status: "started"}, {Status: "canceled"}}, nil
+					},
+				},
+				TimeService: &testTimeService{nowFn: func() time.Time { return testArchivedAt }},
+				AppVersionService: &testAppVersionService{
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						return &models.AppVersion{}, nil
+					},
+					updateFn: func(appVersion *models.AppVersion, whitelist []string) ([]error, error) {
+						return nil, errors.New("SOME-SQL-ERROR")
+					},
+				},
+				WorkerService: &testWorkerService{},
+			},
+			expectedInternalErr: "SQL Error: SOME-SQL-ERROR",
+		})
+	})
+
+	t.Run("when app version not found in database", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				ScheduledPublishService: &testScheduledPublishService{
+					findAllFn: func(appVersion *models.AppVersion) ([]models.ScheduledPublish, error) {
+						return []models.ScheduledPublish{{Status: "started"}, {Status: "canceled"}}, nil
+					},
+				},
+				TimeService: &testTimeService{nowFn: func() time.Time { return testArchivedAt }},
+				AppVersionService: &testAppVersionService{
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+				},
+				WorkerService: &testWorkerService{},
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   httpresponse.StandardErrorRespModel{Message: "Not Found"},
+		})
+	})
+
+	t.Run("when the version is being published", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: &testAppVersionService{
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						return &models.AppVersion{Record: models.Record{ID: testAppVersionID}, Status: "publishing"}, nil
+					},
+				},
+				WorkerService:           &testWorkerService{},
+				ScheduledPublishService: &testScheduledPublishService{},
+				TimeService:             &testTimeService{},
+			},
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedResponse: httpresponse.ValidationErrorRespModel{
+				Message: "Unprocessable Entity",
+				Errors:  []string{"status: Version is being published"},
+			},
+		})
+	})
+
+	t.Run("when the version has a pending scheduled publish", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: &testAppVersionService{
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						return &models.AppVersion{Record: models.Record{ID: testAppVersionID}, Status: "ready"}, nil
+					},
+				},
+				WorkerService: &testWorkerService{},
+				ScheduledPublishService: &testScheduledPublishService{
+					findAllFn: func(appVersion *models.AppVersion) ([]models.ScheduledPublish, error) {
+						require.Equal(t, testAppVersionID, appVersion.ID)
+						return []models.ScheduledPublish{{Status: "canceled"}, {Status: "scheduled"}}, nil
+					},
+				},
+				TimeService: &testTimeService{},
+			},
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedResponse: httpresponse.ValidationErrorRespModel{
+				Message: "Unprocessable Entity",
+				Errors:  []string{"status: Version has a scheduled publish"},
+			},
+		})
+	})
+
+	t.Run("when error happens at finding the scheduled publishes", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: &testAppVersionService{
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						return &models.AppVersion{}, nil
+					},
+				},
+				WorkerService: &testWorkerService{},
+				ScheduledPublishService: &testScheduledPublishService{
+					findAllFn: func(appVersion *models.AppVersion) ([]models.ScheduledPublish, error) {
+						return nil, errors.New("SOME-SQL-ERROR")
+					},
+				},
+				TimeService: &testTimeService{},
+			},
+			expectedInternalErr: "SQL Error: SOME-SQL-ERROR",
+		})
+	})
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/bitrise-io/addons-ship-backend/bitrise"
 	"github.com/bitrise-io/addons-ship-backend/env"
@@ -10,6 +11,7 @@ import (
 	"github.com/bitrise-io/api-utils/httpresponse"
 	"github.com/jinzhu/gorm"
 	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
 )
 
 // AppData ...
@@ -22,23 +24,40 @@ type AppData struct {
 // AppVersionGetResponseData ...
 type AppVersionGetResponseData struct {
 	*models.AppVersion
-	PublicInstallPageURL string              `json:"public_install_page_url"`
-	AppStoreInfo         models.AppStoreInfo `json:"app_store_info"`
-	PublishEnabled       bool                `json:"publish_enabled"`
-	Split                bool                `json:"split"`
-	UniversalAvailable   bool                `json:"universal_available"`
-	AppInfo              AppData             `json:"app_info"`
-	IPAExportMethod      string              `json:"ipa_export_method,omitempty"`
-	Version              string              `json:"version"`
-	VersionCode          string              `json:"version_code"`
-	MinimumOS            string              `json:"minimum_os,omitempty"`
-	MinimumSDK           string              `json:"minimum_sdk,omitempty"`
-	BundleID             string              `json:"bundle_id,omitempty"`
-	PackageName          string              `json:"package_name,omitempty"`
-	SupportedDeviceTypes []string            `json:"supported_device_types"`
-	Module               string              `json:"module"`
-	ProductFlavor        string              `json:"product_flavor"`
-	BuildType            string              `json:"build_type"`
+	PublicInstallPageURL string                    `json:"public_install_page_url"`
+	AppStoreInfo         models.AppStoreInfo       `json:"app_store_info"`
+	PublishEnabled       bool                      `json:"publish_enabled"`
+	Split                bool                      `json:"split"`
+	UniversalAvailable   bool                      `json:"universal_available"`
+	AppInfo              AppData                   `json:"app_info"`
+	IPAExportMethod      string                    `json:"ipa_export_method,omitempty"`
+	Version              string                    `json:"version"`
+	VersionCode          string                    `json:"version_code"`
+	MinimumOS            string                    `json:"minimum_os,omitempty"`
+	MinimumSDK           string                    `json:"minimum_sdk,omitempty"`
+	BundleID             string                    `json:"bundle_id,omitempty"`
+	PackageName          string                    `json:"package_name,omitempty"`
+	SupportedDeviceTypes []string                  `json:"supported_device_types"`
+	Module               string                    `json:"module"`
+	ProductFlavor        string                    `json:"product_flavor"`
+	BuildType            string                    `json:"build_type"`
+	PublishStatuses      []PublishStatusData       `json:"publish_statuses,omitempty"`
+	IosReleaseOptions    *models.IosReleaseOptions `json:"ios_release_options,omitempty"`
+	ScreenshotSizes      []models.ScreenshotSize   `json:"screenshot_sizes"`
+	Warnings             []string                  `json:"warnings,omitempty"`
+}
+
+// PublishStatusData is the status of the latest publish task of a destination
+// and track
+type PublishStatusData struct {
+	Destination string    `json:"destination"`
+	Track       string    `json:"track,omitempty"`
+	Status      string    `json:"status"`
+	TaskID      uuid.UUID `json:"task_id"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	// rollout of the Google Play release, see models.AndroidRollout
+	ReleaseStatus string  `json:"release_status,omitempty"`
+	UserFraction  float64 `json:"user_fraction,omitempty"`
 }
 
 // AppVersionGetResponse ...
@@ -69,6 +88,9 @@ func AppVersionGetHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Reques
 	if env.BitriseAPI == nil {
 		return errors.New("No Bitrise API Service defined for handler")
 	}
+	if env.PublishTaskService == nil {
+		return errors.New("No Publish Task Service defined for handler")
+	}
 
 	artifacts, err := env.BitriseAPI.GetArtifacts(appVersion.App.BitriseAPIToken, appVersion.App.AppSlug, appVersion.BuildSlug)
 	if err != nil {
@@ -80,27 +102,71 @@ func AppVersionGetHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Reques
 		return errors.WithStack(err)
 	}
 
+	publishTasks, err := env.PublishTaskService.FindAll(appVersion)
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+	responseData.PublishStatuses = newPublishStatuses(appVersion, publishTasks)
+
+	warning, err := buildCodeWarning(env, appVersion)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if warning != "" {
+		responseData.Warnings = []string{warning}
+	}
+
 	return httpresponse.RespondWithSuccess(w, AppVersionGetResponse{
 		Data: responseData,
 	})
 }
 
+// newPublishStatuses returns the status of the latest publish task of each
+// destination and track
+func newPublishStatuses(appVersion *models.AppVersion, publishTasks []models.PublishTask) []PublishStatusData {
+	publishStatuses := []PublishStatusData{}
+	for _, publishTask := range publishTasks {
+		publishStatus := PublishStatusData{
+			Destination:   publishTaskDestinationID(appVersion, publishTask),
+			ReleaseStatus: publishTask.ReleaseStatus,
+			UserFraction:  publishTask.UserFraction,
+			Track:         publishTask.Track,
+			Status:        publishTask.Status,
+			TaskID:        publishTask.TaskID,
+			UpdatedAt:     publishTask.UpdatedAt,
+		}
+		replaced := false
+		for i := range publishStatuses {
+			if publishStatuses[i].Destination == publishStatus.Destination && publishStatuses[i].Track == publishStatus.Track {
+				publishStatuses[i] = publishStatus
+				replaced = true
+			}
+		}
+		if !replaced {
+			publishStatuses = append(publishStatuses, publishStatus)
+		}
+	}
+	return publishStatuses
+}
+
 func newArtifactVersionGetResponse(appVersion *models.AppVersion, env *env.AppEnv, artifacts []bitrise.ArtifactListElementResponseModel) (AppVersionGetResponseData, error) {
 	var publishEnabled, publicInstallPageEnabled bool
 	var ipaExportMethod string
 	var publicInstallPageArtifactSlug string
 	var publishAndShareInfo bitrise.PublishAndShareInfo
-	switch appVersion.Platform {
-	case "ios":
-		_, publishEnabled, publicInstallPageEnabled, ipaExportMethod, publicInstallPageArtifactSlug = selectIosArtifact(artifacts)
-	case "android":
+	switch {
+	case appVersion.IsApplePlatform():
+		_, publishEnabled, publicInstallPageEnabled, ipaExportMethod, publicInstallPageArtifactSlug = selectIosArtifact(artifacts, appVersion)
+	case appVersion.Platform == "android":
 		var err error
 		artifactSelector := bitrise.NewArtifactSelector(artifacts)
 		publishAndShareInfo, err = artifactSelector.PublishAndShareInfo(appVersion)
 		if err != nil {
 			return AppVersionGetResponseData{}, errors.WithStack(err)
 		}
-		publishEnabled = publishAndShareInfo.PublishEnabled
+		// a release can't be published with a wrong service account file, its
+		// problems are listed in the Android errors of the app
+		publishEnabled = publishAndShareInfo.PublishEnabled && len(appVersion.App.AndroidServiceAccountErrors()) == 0
 		publicInstallPageEnabled = publishAndShareInfo.PublicInstallPageEnabled
 		publicInstallPageArtifactSlug = publishAndShareInfo.PublicInstallPageArtifactSlug
 	default:
@@ -143,6 +209,10 @@ func newArtifactVersionGetResponse(appVersion *models.AppVersion, env *env.AppEn
 	if err != nil {
 		return AppVersionGetResponseData{}, err
 	}
+	iosReleaseOptions, err := newIosReleaseOptionsResponse(appVersion)
+	if err != nil {
+		return AppVersionGetResponseData{}, err
+	}
 	return AppVersionGetResponseData{
 		AppVersion:           appVersion,
 		PublicInstallPageURL: artifactPublicInstallPageURL,
@@ -162,5 +232,20 @@ func newArtifactVersionGetResponse(appVersion *models.AppVersion, env *env.AppEn
 		Module:               artifactInfo.Module,
 		ProductFlavor:        appVersion.ProductFlavor,
 		BuildType:            artifactInfo.BuildType,
+		IosReleaseOptions:    iosReleaseOptions,
+		ScreenshotSizes:      models.ScreenshotSizes(appVersion.Platform),
 	}, nil
 }
+
+// newIosReleaseOptionsResponse returns the release options of the versions of
+// Apple platforms, and nil for the versions of other platforms
+func newIosReleaseOptionsResponse(appVersion *models.AppVersion) (*models.IosReleaseOptions, error) {
+	if !appVersion.IsApplePlatform() {
+		return nil, nil
+	}
+	releaseOptions, err := appVersion.IosReleaseOptions()
+	if err != nil {
+		return nil, err
+	}
+	return &releaseOptions, nil
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/bitrise-io/addons-ship-backend/bitrise"
 	"github.com/bitrise-io/addons-ship-backend/env"
@@ -23,7 +24,7 @@ func Test_AppVersionGetHandler(t *testing.T) {
 	url := "/apps/{app-slug}/version{version-id}"
 	handler := services.AppVersionGetHandler
 
-	behavesAsServiceCravingHandler(t, httpMethod, url, handler, []string{"AppVersionService", "BitriseAPI"}, ControllerTestCase{
+	behavesAsServiceCravingHandler(t, httpMethod, url, handler, []string{"AppVersionService", "BitriseAPI", "PublishTaskService"}, ControllerTestCase{
 		contextElements: map[ctxpkg.RequestContextKey]interface{}{
 			services.ContextKeyAuthorizedAppVersionID: uuid.NewV4(),
 		},
@@ -33,7 +34,8 @@ func Test_AppVersionGetHandler(t *testing.T) {
 					return nil, nil
 				},
 			},
-			BitriseAPI: &testBitriseAPI{},
+			BitriseAPI:         &testBitriseAPI{},
+			PublishTaskService: &testPublishTaskService{},
 		},
 	})
 
@@ -42,8 +44,9 @@ func Test_AppVersionGetHandler(t *testing.T) {
 			services.ContextKeyAuthorizedAppVersionID: uuid.NewV4(),
 		},
 		env: &env.AppEnv{
-			AppVersionService: &testAppVersionService{},
-			BitriseAPI:        &testBitriseAPI{},
+			AppVersionService:  &testAppVersionService{},
+			BitriseAPI:         &testBitriseAPI{},
+			PublishTaskService: &testPublishTaskService{},
 		},
 	})
 
@@ -55,6 +58,9 @@ func Test_AppVersionGetHandler(t *testing.T) {
 				},
 				env: &env.AppEnv{
 					AppVersionService: &testAppVersionService{
+						latestPublishedFn: func(*models.AppVersion) (*models.AppVersion, error) {
+							return nil, gorm.ErrRecordNotFound
+						},
 						findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
 							require.Equal(t, appVersion.ID.String(), "de438ddc-98e5-4226-a5f4-fd2d53474879")
 							return &models.AppVersion{
@@ -65,6 +71,11 @@ func Test_AppVersionGetHandler(t *testing.T) {
 							}, nil
 						},
 					},
+					PublishTaskService: &testPublishTaskService{
+						findAllFn: func(*models.AppVersion) ([]models.PublishTask, error) {
+							return []models.PublishTask{}, nil
+						},
+					},
 					BitriseAPI: &testBitriseAPI{
 						getArtifactsFn: func(string, string, string) ([]bitrise.ArtifactListElementResponseModel, error) {
 							return []bitrise.ArtifactListElementResponseModel{
@@ -90,10 +101,12 @@ func Test_AppVersionGetHandler(t *testing.T) {
 				expectedStatusCode: http.StatusOK,
 				expectedResponse: services.AppVersionGetResponse{
 					Data: services.AppVersionGetResponseData{
+						ScreenshotSizes: models.ScreenshotSizes("ios"),
 						AppVersion: &models.AppVersion{
 							Platform: "ios",
 						},
-						IPAExportMethod: "development",
+						IosReleaseOptions: &models.IosReleaseOptions{},
+						IPAExportMethod:   "development",
 					},
 				},
 			})
@@ -106,6 +119,9 @@ func Test_AppVersionGetHandler(t *testing.T) {
 				},
 				env: &env.AppEnv{
 					AppVersionService: &testAppVersionService{
+						latestPublishedFn: func(*models.AppVersion) (*models.AppVersion, error) {
+							return nil, gorm.ErrRecordNotFound
+						},
 						findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
 							return &models.AppVersion{
 								Platform:         "ios",
@@ -119,6 +135,11 @@ func Test_AppVersionGetHandler(t *testing.T) {
 							}, nil
 						},
 					},
+					PublishTaskService: &testPublishTaskService{
+						findAllFn: func(*models.AppVersion) ([]models.PublishTask, error) {
+							return []models.PublishTask{}, nil
+						},
+					},
 					BitriseAPI: &testBitriseAPI{
 						getArtifactsFn: func(apiToken, appSlug, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
 							require.Equal(t, "test-api-token", apiToken)
@@ -157,13 +178,15 @@ func Test_AppVersionGetHandler(t *testing.T) {
 				expectedStatusCode: http.StatusOK,
 				expectedResponse: services.AppVersionGetResponse{
 					Data: services.AppVersionGetResponseData{
+						ScreenshotSizes: models.ScreenshotSizes("ios"),
 						AppVersion: &models.AppVersion{
 							Platform:  "ios",
 							BuildSlug: "test-build-slug",
 						},
-						MinimumOS:       "11.1",
-						IPAExportMethod: "app-store",
-						Version:         "v1.0",
+						IosReleaseOptions: &models.IosReleaseOptions{},
+						MinimumOS:         "11.1",
+						IPAExportMethod:   "app-store",
+						Version:           "v1.0",
 						AppInfo: services.AppData{
 							Title:       "The Adventures of Stealy",
 							AppIconURL:  pointers.NewStringPtr("https://bit.ly/1LixVJu"),
@@ -186,6 +209,9 @@ func Test_AppVersionGetHandler(t *testing.T) {
 				},
 				env: &env.AppEnv{
 					AppVersionService: &testAppVersionService{
+						latestPublishedFn: func(*models.AppVersion) (*models.AppVersion, error) {
+							return nil, gorm.ErrRecordNotFound
+						},
 						findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
 							return &models.AppVersion{
 								Platform:         "ios",
@@ -199,6 +225,11 @@ func Test_AppVersionGetHandler(t *testing.T) {
 							}, nil
 						},
 					},
+					PublishTaskService: &testPublishTaskService{
+						findAllFn: func(*models.AppVersion) ([]models.PublishTask, error) {
+							return []models.PublishTask{}, nil
+						},
+					},
 					BitriseAPI: &testBitriseAPI{
 						getArtifactsFn: func(apiToken, appSlug, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
 							require.Equal(t, "test-api-token", apiToken)
@@ -233,12 +264,14 @@ func Test_AppVersionGetHandler(t *testing.T) {
 				expectedStatusCode: http.StatusOK,
 				expectedResponse: services.AppVersionGetResponse{
 					Data: services.AppVersionGetResponseData{
+						ScreenshotSizes: models.ScreenshotSizes("ios"),
 						AppVersion: &models.AppVersion{
 							Platform:  "ios",
 							BuildSlug: "test-build-slug",
 						},
-						Version:   "v1.0",
-						MinimumOS: "11.1",
+						IosReleaseOptions: &models.IosReleaseOptions{},
+						Version:           "v1.0",
+						MinimumOS:         "11.1",
 						AppInfo: services.AppData{
 							Title:       "The Adventures of Stealy",
 							AppIconURL:  pointers.NewStringPtr("https://bit.ly/1LixVJu"),
@@ -261,6 +294,9 @@ func Test_AppVersionGetHandler(t *testing.T) {
 				},
 				env: &env.AppEnv{
 					AppVersionService: &testAppVersionService{
+						latestPublishedFn: func(*models.AppVersion) (*models.AppVersion, error) {
+							return nil, gorm.ErrRecordNotFound
+						},
 						findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
 							return &models.AppVersion{
 								Platform:         "ios",
@@ -272,6 +308,11 @@ func Test_AppVersionGetHandler(t *testing.T) {
 							}, nil
 						},
 					},
+					PublishTaskService: &testPublishTaskService{
+						findAllFn: func(*models.AppVersion) ([]models.PublishTask, error) {
+							return []models.PublishTask{}, nil
+						},
+					},
 					BitriseAPI: &testBitriseAPI{
 						getArtifactsFn: func(string, string, string) ([]bitrise.ArtifactListElementResponseModel, error) {
 							return []bitrise.ArtifactListElementResponseModel{
@@ -301,9 +342,11 @@ func Test_AppVersionGetHandler(t *testing.T) {
 				expectedStatusCode: http.StatusOK,
 				expectedResponse: services.AppVersionGetResponse{
 					Data: services.AppVersionGetResponseData{
+						ScreenshotSizes: models.ScreenshotSizes("ios"),
 						AppVersion: &models.AppVersion{
 							Platform: "ios",
 						},
+						IosReleaseOptions:    &models.IosReleaseOptions{},
 						Version:              "v1.0",
 						MinimumOS:            "10.1",
 						IPAExportMethod:      "development",
@@ -329,6 +372,9 @@ func Test_AppVersionGetHandler(t *testing.T) {
 				},
 				env: &env.AppEnv{
 					AppVersionService: &testAppVersionService{
+						latestPublishedFn: func(*models.AppVersion) (*models.AppVersion, error) {
+							return nil, gorm.ErrRecordNotFound
+						},
 						findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
 							return &models.AppVersion{
 								Platform:         "ios",
@@ -340,6 +386,11 @@ func Test_AppVersionGetHandler(t *testing.T) {
 							}, nil
 						},
 					},
+					PublishTaskService: &testPublishTaskService{
+						findAllFn: func(*models.AppVersion) ([]models.PublishTask, error) {
+							return []models.PublishTask{}, nil
+						},
+					},
 					BitriseAPI: &testBitriseAPI{
 						getArtifactsFn: func(string, string, string) ([]bitrise.ArtifactListElementResponseModel, error) {
 							return []bitrise.ArtifactListElementResponseModel{
@@ -369,9 +420,11 @@ func Test_AppVersionGetHandler(t *testing.T) {
 				expectedStatusCode: http.StatusOK,
 				expectedResponse: services.AppVersionGetResponse{
 					Data: services.AppVersionGetResponseData{
+						ScreenshotSizes: models.ScreenshotSizes("ios"),
 						AppVersion: &models.AppVersion{
 							Platform: "ios",
 						},
+						IosReleaseOptions:    &models.IosReleaseOptions{},
 						Version:              "v1.0",
 						MinimumOS:            "10.1",
 						SupportedDeviceTypes: []string{"iPhone", "iPod Touch", "iPad"},
@@ -395,6 +448,9 @@ func Test_AppVersionGetHandler(t *testing.T) {
 				},
 				env: &env.AppEnv{
 					AppVersionService: &testAppVersionService{
+						latestPublishedFn: func(*models.AppVersion) (*models.AppVersion, error) {
+							return nil, gorm.ErrRecordNotFound
+						},
 						findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
 							return &models.AppVersion{
 								Platform:         "ios",
@@ -406,6 +462,11 @@ func Test_AppVersionGetHandler(t *testing.T) {
 							}, nil
 						},
 					},
+					PublishTaskService: &testPublishTaskService{
+						findAllFn: func(*models.AppVersion) ([]models.PublishTask, error) {
+							return []models.PublishTask{}, nil
+						},
+					},
 					BitriseAPI: &testBitriseAPI{
 						getArtifactsFn: func(string, string, string) ([]bitrise.ArtifactListElementResponseModel, error) {
 							return []bitrise.ArtifactListElementResponseModel{
@@ -435,9 +496,11 @@ func Test_AppVersionGetHandler(t *testing.T) {
 				expectedStatusCode: http.StatusOK,
 				expectedResponse: services.AppVersionGetResponse{
 					Data: services.AppVersionGetResponseData{
+						ScreenshotSizes: models.ScreenshotSizes("ios"),
 						AppVersion: &models.AppVersion{
 							Platform: "ios",
 						},
+						IosReleaseOptions:    &models.IosReleaseOptions{},
 						Version:              "v1.0",
 						MinimumOS:            "10.1",
 						SupportedDeviceTypes: []string{"Unknown"},
@@ -466,6 +529,11 @@ func Test_AppVersionGetHandler(t *testing.T) {
 							return &models.AppVersion{App: models.App{}, Platform: "ios"}, nil
 						},
 					},
+					PublishTaskService: &testPublishTaskService{
+						findAllFn: func(*models.AppVersion) ([]models.PublishTask, error) {
+							return []models.PublishTask{}, nil
+						},
+					},
 					BitriseAPI: &testBitriseAPI{
 						getArtifactsFn: func(string, string, string) ([]bitrise.ArtifactListElementResponseModel, error) {
 							return []bitrise.ArtifactListElementResponseModel{
@@ -504,6 +572,11 @@ func Test_AppVersionGetHandler(t *testing.T) {
 							return &models.AppVersion{App: models.App{}, Platform: "ios"}, nil
 						},
 					},
+					PublishTaskService: &testPublishTaskService{
+						findAllFn: func(*models.AppVersion) ([]models.PublishTask, error) {
+							return []models.PublishTask{}, nil
+						},
+					},
 					BitriseAPI: &testBitriseAPI{
 						getArtifactsFn: func(string, string, string) ([]bitrise.ArtifactListElementResponseModel, error) {
 							return []bitrise.ArtifactListElementResponseModel{
@@ -536,6 +609,11 @@ func Test_AppVersionGetHandler(t *testing.T) {
 							return &models.AppVersion{App: models.App{}, Platform: "ios"}, nil
 						},
 					},
+					PublishTaskService: &testPublishTaskService{
+						findAllFn: func(*models.AppVersion) ([]models.PublishTask, error) {
+							return []models.PublishTask{}, nil
+						},
+					},
 					BitriseAPI: &testBitriseAPI{
 						getArtifactsFn: func(string, string, string) ([]bitrise.ArtifactListElementResponseModel, error) {
 							return []bitrise.ArtifactListElementResponseModel{
@@ -571,6 +649,151 @@ func Test_AppVersionGetHandler(t *testing.T) {
 				},
 				env: &env.AppEnv{
 					AppVersionService: &testAppVersionService{
+						latestPublishedFn: func(*models.AppVersion) (*models.AppVersion, error) {
+							return nil, gorm.ErrRecordNotFound
+						},
+						findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+							require.Equal(t, appVersion.ID.String(), "de438ddc-98e5-4226-a5f4-fd2d53474879")
+							return &models.AppVersion{
+								App:              models.App{},
+								AppStoreInfoData: json.RawMessage(`{}`),
+								ArtifactInfoData: json.RawMessage(`{"package_name":"test.package","version_code":"abc123"}`),
+								Platform:         "android",
+							}, nil
+						},
+					},
+					PublishTaskService: &testPublishTaskService{
+						findAllFn: func(*models.AppVersion) ([]models.PublishTask, error) {
+							return []models.PublishTask{}, nil
+						},
+					},
+					BitriseAPI: &testBitriseAPI{
+						getArtifactsFn: func(string, string, string) ([]bitrise.ArtifactListElementResponseModel, error) {
+							return []bitrise.ArtifactListElementResponseModel{
+								bitrise.ArtifactListElementResponseModel{
+									Title: "app.apk",
+									Slug:  "test-apk-slug",
+									ArtifactMeta: &bitrise.ArtifactMeta{
+										AppInfo: bitrise.AppInfo{},
+									},
+								},
+							}, nil
+						},
+						getArtifactPublicPageURLFn: func(string, string, string, string) (string, error) {
+							return "", nil
+						},
+						getAppDetailsFn: func(string, string) (*bitrise.AppDetails, error) {
+							return &bitrise.AppDetails{}, nil
+						},
+					},
+				},
+				expectedStatusCode: http.StatusOK,
+				expectedResponse: services.AppVersionGetResponse{
+					Data: services.AppVersionGetResponseData{
+						ScreenshotSizes: models.ScreenshotSizes("android"),
+						AppVersion:      &models.AppVersion{Platform: "android"},
+						PackageName:     "test.package",
+						VersionCode:     "abc123",
+					},
+				},
+			})
+		})
+
+		t.Run("ok - when the version code isn't greater than the published one", func(t *testing.T) {
+			performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+				contextElements: map[ctxpkg.RequestContextKey]interface{}{
+					services.ContextKeyAuthorizedAppVersionID: uuid.FromStringOrNil("de438ddc-98e5-4226-a5f4-fd2d53474879"),
+				},
+				env: &env.AppEnv{
+					AppVersionService: &testAppVersionService{
+						findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+							return &models.AppVersion{
+								AppStoreInfoData: json.RawMessage(`{}`),
+								ArtifactInfoData: json.RawMessage(`{"version_code":"12"}`),
+								Platform:         "android",
+								ProductFlavor:    "free",
+							}, nil
+						},
+						latestPublishedFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+							require.Equal(t, "free", appVersion.ProductFlavor)
+							return &models.AppVersion{Platform: "android", ArtifactInfoData: json.RawMessage(`{"version_code":"12"}`)}, nil
+						},
+					},
+					PublishTaskService: &testPublishTaskService{
+						findAllFn: func(*models.AppVersion) ([]models.PublishTask, error) {
+							return []models.PublishTask{}, nil
+						},
+					},
+					BitriseAPI: &testBitriseAPI{
+						getArtifactsFn: func(string, string, string) ([]bitrise.ArtifactListElementResponseModel, error) {
+							return []bitrise.ArtifactListElementResponseModel{}, nil
+						},
+						getAppDetailsFn: func(string, string) (*bitrise.AppDetails, error) {
+							return &bitrise.AppDetails{}, nil
+						},
+					},
+				},
+				expectedStatusCode: http.StatusOK,
+				expectedResponse: services.AppVersionGetResponse{
+					Data: services.AppVersionGetResponseData{
+						ScreenshotSizes: models.ScreenshotSizes("android"),
+						AppVersion:      &models.AppVersion{Platform: "android", ProductFlavor: "free"},
+						VersionCode:     "12",
+						ProductFlavor:   "free",
+						Warnings:        []string{"Version code 12 is not greater than the greatest published one (12)"},
+					},
+				},
+			})
+		})
+
+		t.Run("when error happens at finding the latest published version", func(t *testing.T) {
+			performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+				contextElements: map[ctxpkg.RequestContextKey]interface{}{
+					services.ContextKeyAuthorizedAppVersionID: uuid.FromStringOrNil("de438ddc-98e5-4226-a5f4-fd2d53474879"),
+				},
+				env: &env.AppEnv{
+					AppVersionService: &testAppVersionService{
+						findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+							return &models.AppVersion{
+								AppStoreInfoData: json.RawMessage(`{}`),
+								ArtifactInfoData: json.RawMessage(`{}`),
+								Platform:         "android",
+							}, nil
+						},
+						latestPublishedFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+							return nil, errors.New("SOME-SQL-ERROR")
+						},
+					},
+					PublishTaskService: &testPublishTaskService{
+						findAllFn: func(*models.AppVersion) ([]models.PublishTask, error) {
+							return []models.PublishTask{}, nil
+						},
+					},
+					BitriseAPI: &testBitriseAPI{
+						getArtifactsFn: func(string, string, string) ([]bitrise.ArtifactListElementResponseModel, error) {
+							return []bitrise.ArtifactListElementResponseModel{}, nil
+						},
+						getAppDetailsFn: func(string, string) (*bitrise.AppDetails, error) {
+							return &bitrise.AppDetails{}, nil
+						},
+					},
+				},
+				expectedInternalErr: "SQL Error: SOME-SQL-ERROR",
+			})
+		})
+
+		t.Run("ok - publish statuses", func(t *testing.T) {
+			testTime := time.Date(2019, 11, 4, 9, 35, 12, 0, time.UTC)
+			testTaskIDs := []uuid.UUID{uuid.NewV4(), uuid.NewV4(), uuid.NewV4(), uuid.NewV4()}
+			performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+				contextElements: map[ctxpkg.RequestContextKey]interface{}{
+					services.ContextKeyAuthorizedAppVersionID: uuid.FromStringOrNil("de438ddc-98e5-4226-a5f4-fd2d53474879"),
+				},
+				env: &env.AppEnv{
+					AppVersionService: &testAppVersionService{
+						latestPublishedFn: func(*models.AppVersion) (*models.AppVersion, error) {
+							return nil, gorm.ErrRecordNotFound
+						},
 						findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
 							require.Equal(t, appVersion.ID.String(), "de438ddc-98e5-4226-a5f4-fd2d53474879")
 							return &models.AppVersion{
@@ -581,6 +804,16 @@ func Test_AppVersionGetHandler(t *testing.T) {
 							}, nil
 						},
 					},
+					PublishTaskService: &testPublishTaskService{
+						findAllFn: func(appVersion *models.AppVersion) ([]models.PublishTask, error) {
+							return []models.PublishTask{
+								models.PublishTask{TaskID: testTaskIDs[0], Status: "success", Record: models.Record{UpdatedAt: testTime}},
+								models.PublishTask{TaskID: testTaskIDs[1], Destination: "google-play", Track: "beta", Status: "failed", Record: models.Record{UpdatedAt: testTime}},
+								models.PublishTask{TaskID: testTaskIDs[2], Destination: "google-play", Track: "internal", Status: "success", Record: models.Record{UpdatedAt: testTime}},
+								models.PublishTask{TaskID: testTaskIDs[3], Destination: "google-play", Track: "beta", Status: "in_progress", Record: models.Record{UpdatedAt: testTime}},
+							}, nil
+						},
+					},
 					BitriseAPI: &testBitriseAPI{
 						getArtifactsFn: func(string, string, string) ([]bitrise.ArtifactListElementResponseModel, error) {
 							return []bitrise.ArtifactListElementResponseModel{
@@ -604,9 +837,15 @@ func Test_AppVersionGetHandler(t *testing.T) {
 				expectedStatusCode: http.StatusOK,
 				expectedResponse: services.AppVersionGetResponse{
 					Data: services.AppVersionGetResponseData{
-						AppVersion:  &models.AppVersion{Platform: "android"},
-						PackageName: "test.package",
-						VersionCode: "abc123",
+						ScreenshotSizes: models.ScreenshotSizes("android"),
+						AppVersion:      &models.AppVersion{Platform: "android"},
+						PackageName:     "test.package",
+						VersionCode:     "abc123",
+						PublishStatuses: []services.PublishStatusData{
+							services.PublishStatusData{Destination: "google-play", Status: "success", TaskID: testTaskIDs[0], UpdatedAt: testTime},
+							services.PublishStatusData{Destination: "google-play", Track: "beta", Status: "in_progress", TaskID: testTaskIDs[3], UpdatedAt: testTime},
+							services.PublishStatusData{Destination: "google-play", Track: "internal", Status: "success", TaskID: testTaskIDs[2], UpdatedAt: testTime},
+						},
 					},
 				},
 			})
@@ -619,6 +858,9 @@ func Test_AppVersionGetHandler(t *testing.T) {
 				},
 				env: &env.AppEnv{
 					AppVersionService: &testAppVersionService{
+						latestPublishedFn: func(*models.AppVersion) (*models.AppVersion, error) {
+							return nil, gorm.ErrRecordNotFound
+						},
 						findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
 							require.Equal(t, appVersion.ID.String(), "de438ddc-98e5-4226-a5f4-fd2d53474879")
 							return &models.AppVersion{
@@ -630,6 +872,11 @@ func Test_AppVersionGetHandler(t *testing.T) {
 							}, nil
 						},
 					},
+					PublishTaskService: &testPublishTaskService{
+						findAllFn: func(*models.AppVersion) ([]models.PublishTask, error) {
+							return []models.PublishTask{}, nil
+						},
+					},
 					BitriseAPI: &testBitriseAPI{
 						getArtifactsFn: func(string, string, string) ([]bitrise.ArtifactListElementResponseModel, error) {
 							return []bitrise.ArtifactListElementResponseModel{
@@ -658,6 +905,7 @@ func Test_AppVersionGetHandler(t *testing.T) {
 				expectedStatusCode: http.StatusOK,
 				expectedResponse: services.AppVersionGetResponse{
 					Data: services.AppVersionGetResponseData{
+						ScreenshotSizes:      models.ScreenshotSizes("android"),
 						AppVersion:           &models.AppVersion{Platform: "android"},
 						PublicInstallPageURL: "http://don.t.go.there?source=ship",
 						PublishEnabled:       true,
@@ -670,6 +918,72 @@ func Test_AppVersionGetHandler(t *testing.T) {
 			})
 		})
 
+		t.Run("ok - when the service account file has problems", func(t *testing.T) {
+			performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+				contextElements: map[ctxpkg.RequestContextKey]interface{}{
+					services.ContextKeyAuthorizedAppVersionID: uuid.FromStringOrNil("de438ddc-98e5-4226-a5f4-fd2d53474879"),
+				},
+				env: &env.AppEnv{
+					AppVersionService: &testAppVersionService{
+						latestPublishedFn: func(*models.AppVersion) (*models.AppVersion, error) {
+							return nil, gorm.ErrRecordNotFound
+						},
+						findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+							require.Equal(t, appVersion.ID.String(), "de438ddc-98e5-4226-a5f4-fd2d53474879")
+							return &models.AppVersion{
+								App:              models.App{AndroidErrors: []string{"Service account file: Invalid JSON"}},
+								AppStoreInfoData: json.RawMessage(`{}`),
+								ArtifactInfoData: json.RawMessage(`{"module":"test-module","build_type":"release"}`),
+								Platform:         "android",
+								ProductFlavor:    "test-product-flavor",
+							}, nil
+						},
+					},
+					PublishTaskService: &testPublishTaskService{
+						findAllFn: func(*models.AppVersion) ([]models.PublishTask, error) {
+							return []models.PublishTask{}, nil
+						},
+					},
+					BitriseAPI: &testBitriseAPI{
+						getArtifactsFn: func(string, string, string) ([]bitrise.ArtifactListElementResponseModel, error) {
+							return []bitrise.ArtifactListElementResponseModel{
+								bitrise.ArtifactListElementResponseModel{
+									Title:               "app-universal.apk",
+									Slug:                "test-artifact-slug",
+									IsPublicPageEnabled: true,
+									ArtifactMeta: &bitrise.ArtifactMeta{
+										AppInfo:       bitrise.AppInfo{},
+										BuildType:     "release",
+										Module:        "test-module",
+										ProductFlavor: "test-product-flavor",
+										Universal:     "/bitrise/my-awesome-projcet/app-universal.apk",
+									},
+								},
+							}, nil
+						},
+						getArtifactPublicPageURLFn: func(string, string, string, string) (string, error) {
+							return "http://don.t.go.there", nil
+						},
+						getAppDetailsFn: func(string, string) (*bitrise.AppDetails, error) {
+							return &bitrise.AppDetails{}, nil
+						},
+					},
+				},
+				expectedStatusCode: http.StatusOK,
+				expectedResponse: services.AppVersionGetResponse{
+					Data: services.AppVersionGetResponseData{
+						ScreenshotSizes:      models.ScreenshotSizes("android"),
+						AppVersion:           &models.AppVersion{Platform: "android"},
+						PublicInstallPageURL: "http://don.t.go.there?source=ship",
+						Module:               "test-module",
+						ProductFlavor:        "test-product-flavor",
+						BuildType:            "release",
+						UniversalAvailable:   true,
+					},
+				},
+			})
+		})
+
 		t.Run("when error happens at reading app store info", func(t *testing.T) {
 			performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
 				contextElements: map[ctxpkg.RequestContextKey]interface{}{
@@ -682,6 +996,11 @@ func Test_AppVersionGetHandler(t *testing.T) {
 							return &models.AppVersion{App: models.App{}, Platform: "android"}, nil
 						},
 					},
+					PublishTaskService: &testPublishTaskService{
+						findAllFn: func(*models.AppVersion) ([]models.PublishTask, error) {
+							return []models.PublishTask{}, nil
+						},
+					},
 					BitriseAPI: &testBitriseAPI{
 						getArtifactsFn: func(string, string, string) ([]bitrise.ArtifactListElementResponseModel, error) {
 							return []bitrise.ArtifactListElementResponseModel{
@@ -717,6 +1036,11 @@ func Test_AppVersionGetHandler(t *testing.T) {
 							return &models.AppVersion{App: models.App{}, Platform: "android", ArtifactInfoData: json.RawMessage(`{}`)}, nil
 						},
 					},
+					PublishTaskService: &testPublishTaskService{
+						findAllFn: func(*models.AppVersion) ([]models.PublishTask, error) {
+							return []models.PublishTask{}, nil
+						},
+					},
 					BitriseAPI: &testBitriseAPI{
 						getArtifactsFn: func(string, string, string) ([]bitrise.ArtifactListElementResponseModel, error) {
 							return []bitrise.ArtifactListElementResponseModel{
@@ -749,6 +1073,11 @@ func Test_AppVersionGetHandler(t *testing.T) {
 							return &models.AppVersion{App: models.App{}, Platform: "android", ArtifactInfoData: json.RawMessage(`{}`)}, nil
 						},
 					},
+					PublishTaskService: &testPublishTaskService{
+						findAllFn: func(*models.AppVersion) ([]models.PublishTask, error) {
+							return []models.PublishTask{}, nil
+						},
+					},
 					BitriseAPI: &testBitriseAPI{
 						getArtifactsFn: func(string, string, string) ([]bitrise.ArtifactListElementResponseModel, error) {
 							return []bitrise.ArtifactListElementResponseModel{
@@ -789,6 +1118,11 @@ func Test_AppVersionGetHandler(t *testing.T) {
 						}, nil
 					},
 				},
+				PublishTaskService: &testPublishTaskService{
+					findAllFn: func(*models.AppVersion) ([]models.PublishTask, error) {
+						return []models.PublishTask{}, nil
+					},
+				},
 				BitriseAPI: &testBitriseAPI{
 					getArtifactsFn: func(string, string, string) ([]bitrise.ArtifactListElementResponseModel, error) {
 						return []bitrise.ArtifactListElementResponseModel{
@@ -827,6 +1161,11 @@ func Test_AppVersionGetHandler(t *testing.T) {
 						return &models.AppVersion{App: models.App{}, Platform: "ios"}, nil
 					},
 				},
+				PublishTaskService: &testPublishTaskService{
+					findAllFn: func(*models.AppVersion) ([]models.PublishTask, error) {
+						return []models.PublishTask{}, nil
+					},
+				},
 				BitriseAPI: &testBitriseAPI{
 					getArtifactsFn: func(string, string, string) ([]bitrise.ArtifactListElementResponseModel, error) {
 						return nil, errors.New("SOME-BITRISE-API-ERROR")
@@ -855,6 +1194,11 @@ func Test_AppVersionGetHandler(t *testing.T) {
 						return &models.AppVersion{App: models.App{}, Platform: "ios"}, nil
 					},
 				},
+				PublishTaskService: &testPublishTaskService{
+					findAllFn: func(*models.AppVersion) ([]models.PublishTask, error) {
+						return []models.PublishTask{}, nil
+					},
+				},
 				BitriseAPI: &testBitriseAPI{
 					getArtifactsFn: func(string, string, string) ([]bitrise.ArtifactListElementResponseModel, error) {
 						return []bitrise.ArtifactListElementResponseModel{
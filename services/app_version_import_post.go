@@ -0,0 +1,86 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/bitrise-io/addons-ship-backend/bitrise"
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/api-utils/httprequest"
+	"github.com/bitrise-io/api-utils/httpresponse"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// AppVersionImportPostParams ...
+type AppVersionImportPostParams struct {
+	BuildSlug string `json:"build_slug"`
+}
+
+// AppVersionImportPostResponse ...
+type AppVersionImportPostResponse struct {
+	Data []models.AppVersion `json:"data"`
+}
+
+// AppVersionImportPostHandler creates the app versions of a finished build,
+// the same way as the build webhook does, regardless of the workflow of the build
+func AppVersionImportPostHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Request) error {
+	authorizedAppID, err := GetAuthorizedAppIDFromContext(r.Context())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := CheckBuildImportServices(env); err != nil {
+		return err
+	}
+
+	var params AppVersionImportPostParams
+	defer httprequest.BodyCloseWithErrorLog(r)
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		return httpresponse.RespondWithBadRequestError(w, "Invalid request body, JSON decode failed")
+	}
+	if params.BuildSlug == "" {
+		return httpresponse.RespondWithUnprocessableEntity(w, []error{errors.New("build_slug: Cannot be empty")})
+	}
+
+	appSettings, err := env.AppSettingsService.Find(&models.AppSettings{AppID: authorizedAppID})
+	switch {
+	case errors.Cause(err) == gorm.ErrRecordNotFound:
+		return httpresponse.RespondWithNotFoundError(w)
+	case err != nil:
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	existingAppVersions, err := env.AppVersionService.FindAll(appSettings.App, map[string]interface{}{"build_slug": params.BuildSlug})
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+	if len(existingAppVersions) > 0 {
+		return httpresponse.RespondWithUnprocessableEntity(w, []error{errors.New("build_slug: Build is already imported")})
+	}
+
+	buildDetails, err := env.BitriseAPI.GetBuildDetails(appSettings.App.BitriseAPIToken, appSettings.App.AppSlug, params.BuildSlug)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if buildDetails.Status == bitrise.BuildStatusInProgress {
+		return httpresponse.RespondWithUnprocessableEntity(w, []error{errors.New("build_slug: Build is not finished yet")})
+	}
+
+	appVersions, verrs, err := ImportBuild(env, appSettings, BuildImport{
+		BuildSlug:         params.BuildSlug,
+		BuildNumber:       buildDetails.BuildNumber,
+		TriggeredWorkflow: buildDetails.TriggeredWorkflow,
+	})
+	if len(verrs) > 0 {
+		return httpresponse.RespondWithUnprocessableEntity(w, verrs)
+	}
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if len(appVersions) == 0 {
+		return httpresponse.RespondWithUnprocessableEntity(w, []error{errors.New("build_slug: Build has no artifact to create a version from")})
+	}
+
+	return httpresponse.RespondWithSuccess(w, AppVersionImportPostResponse{Data: appVersions})
+}
@@ -0,0 +1,370 @@
+package services_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bitrise-io/addons-ship-backend/bitrise"
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/addons-ship-backend/services"
+	ctxpkg "github.com/bitrise-io/api-utils/context"
+	"github.com/bitrise-io/api-utils/httpresponse"
+	"github.com/c2fo/testify/require"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+func Test_AppVersionImportPostHandler(t *testing.T) {
+	httpMethod := "POST"
+	url := "/apps/{app-slug}/versions/import"
+	handler := services.AppVersionImportPostHandler
+
+	testAppID := uuid.FromStringOrNil("211afc15-127a-40f9-8cbe-1dadc1f86cdf")
+	testAppVersionID := uuid.FromStringOrNil("9f7b06d1-e736-42d3-94c3-c2bcfda0463c")
+	testReleaseID := uuid.FromStringOrNil("5c2c0f29-9ad8-4a0e-9e1e-1f7a3c6a7b5e")
+	testLastUpdate := time.Date(2019, 11, 25, 10, 12, 0, 0, time.UTC)
+	testApp := &models.App{Record: models.Record{ID: testAppID}, BitriseAPIToken: "test-api-token", AppSlug: "test-app-slug"}
+
+	appSettingsService := &testAppSettingsService{
+		findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+			require.Equal(t, testAppID, appSettings.AppID)
+			return &models.AppSettings{AppID: testAppID, IosWorkflow: "deploy", App: testApp}, nil
+		},
+	}
+	iosArtifacts := []bitrise.ArtifactListElementResponseModel{
+		{
+			Title: "my-ios-artifact.ipa",
+			ArtifactMeta: &bitrise.ArtifactMeta{
+				AppInfo:          bitrise.AppInfo{Version: "1.0", DeviceFamilyList: []int{1}},
+				ProvisioningInfo: bitrise.ProvisioningInfo{IPAExportMethod: "app-store"},
+			},
+		},
+		{
+			Title: "my-ios-artifact.xcarchive.zip",
+			ArtifactMeta: &bitrise.ArtifactMeta{
+				AppInfo:          bitrise.AppInfo{Version: "1.0", BuildNumber: "12", DeviceFamilyList: []int{1}},
+				ProvisioningInfo: bitrise.ProvisioningInfo{IPAExportMethod: "app-store"},
+				Scheme:           "test-scheme",
+			},
+		},
+	}
+
+	behavesAsServiceCravingHandler(t, httpMethod, url, handler, []string{"AppService", "AppSettingsService", "AppVersionService", "AppVersionEventService", "BitriseAPI", "AppContactService", "WorkerService", "ReleaseService"}, ControllerTestCase{
+		contextElements: map[ctxpkg.RequestContextKey]interface{}{
+			services.ContextKeyAuthorizedAppID: testAppID,
+		},
+		env: &env.AppEnv{
+			AppService:             &testAppService{},
+			AppVersionService:      &testAppVersionService{},
+			ReleaseService:         &testReleaseService{},
+			AppVersionEventService: &testAppVersionEventService{},
+			AppSettingsService:     &testAppSettingsService{},
+			BitriseAPI:             &testBitriseAPI{},
+			AppContactService:      &testAppContactService{},
+			WorkerService:          &testWorkerService{},
+		},
+	})
+
+	behavesAsContextCravingHandler(t, httpMethod, url, handler, []ctxpkg.RequestContextKey{services.ContextKeyAuthorizedAppID}, ControllerTestCase{
+		contextElements: map[ctxpkg.RequestContextKey]interface{}{
+			services.ContextKeyAuthorizedAppID: testAppID,
+		},
+		env: &env.AppEnv{
+			AppService:             &testAppService{},
+			AppVersionService:      &testAppVersionService{},
+			ReleaseService:         &testReleaseService{},
+			AppVersionEventService: &testAppVersionEventService{},
+			AppSettingsService:     &testAppSettingsService{},
+			BitriseAPI:             &testBitriseAPI{},
+			AppContactService:      &testAppContactService{},
+			WorkerService:          &testWorkerService{},
+		},
+	})
+
+	t.Run("ok - when the workflow of the build is not whitelisted", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppID: testAppID,
+			},
+			env: &env.AppEnv{
+				AppService:         &testAppService{},
+				AppSettingsService: appSettingsService,
+				AppVersionService: &testAppVersionService{
+					findAllFn: func(app *models.App, filterParams map[string]interface{}) ([]models.AppVersion, error) {
+						require.Equal(t, testAppID, app.ID)
+						require.Equal(t, map[string]interface{}{"build_slug": "test-build-slug"}, filterParams)
+						return []models.AppVersion{}, nil
+					},
+					latestFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+					createFn: func(appVersion *models.AppVersion) (*models.AppVersion, []error, error) {
+						require.Equal(t, "ios", appVersion.Platform)
+						require.Equal(t, "test-build-slug", appVersion.BuildSlug)
+						require.Equal(t, "12", appVersion.BuildNumber)
+						require.Equal(t, "Old commit", appVersion.CommitMessage)
+						require.Equal(t, &testReleaseID, appVersion.ReleaseID)
+						require.NotEqual(t, time.Time{}, appVersion.LastUpdate)
+						appVersion.ID = testAppVersionID
+						appVersion.LastUpdate = testLastUpdate
+						return appVersion, nil, nil
+					},
+				},
+				ReleaseService: &testReleaseService{
+					findFn: func(release *models.Release) (*models.Release, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+					createFn: func(release *models.Release) (*models.Release, error) {
+						release.ID = testReleaseID
+						return release, nil
+					},
+				},
+				AppVersionEventService: &testAppVersionEventService{
+					createFn: func(appVersionEvent *models.AppVersionEvent) (*models.AppVersionEvent, error) {
+						require.Equal(t, testAppVersionID, appVersionEvent.AppVersionID)
+						return appVersionEvent, nil
+					},
+				},
+				BitriseAPI: &testBitriseAPI{
+					getBuildDetailsFn: func(apiToken, appSlug, buildSlug string) (*bitrise.BuildDetails, error) {
+						require.Equal(t, "test-api-token", apiToken)
+						require.Equal(t, "test-app-slug", appSlug)
+						require.Equal(t, "test-build-slug", buildSlug)
+						return &bitrise.BuildDetails{Slug: buildSlug, Status: bitrise.BuildStatusSuccess, TriggeredWorkflow: "primary", CommitMessage: "Old commit"}, nil
+					},
+					getArtifactsFn: func(apiToken, appSlug, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
+						return iosArtifacts, nil
+					},
+					getAppDetailsFn: func(apiToken, appSlug string) (*bitrise.AppDetails, error) {
+						return &bitrise.AppDetails{}, nil
+					},
+				},
+				AnalyticsClient: &testAnalyticsClient{
+					firstVersionCreatedFn: func(appSlug, buildSlug, platform string) {
+						require.Equal(t, "test-app-slug", appSlug)
+						require.Equal(t, "ios", platform)
+					},
+				},
+				AppContactService: &testAppContactService{},
+				WorkerService:     &testWorkerService{},
+			},
+			requestBody:        `{"build_slug":"test-build-slug"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppVersionImportPostResponse{
+				Data: []models.AppVersion{
+					{
+						Record:        models.Record{ID: testAppVersionID},
+						AppID:         testAppID,
+						Platform:      "ios",
+						BuildSlug:     "test-build-slug",
+						BuildNumber:   "12",
+						CommitMessage: "Old commit",
+						Scheme:        "test-scheme",
+						ReleaseID:     &testReleaseID,
+						LastUpdate:    testLastUpdate,
+						Status:        "ready",
+					},
+				},
+			},
+		})
+	})
+
+	t.Run("when request body contains invalid JSON", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppID: testAppID,
+			},
+			env: &env.AppEnv{
+				AppService:             &testAppService{},
+				AppSettingsService:     &testAppSettingsService{},
+				AppVersionService:      &testAppVersionService{},
+				ReleaseService:         &testReleaseService{},
+				AppVersionEventService: &testAppVersionEventService{},
+				BitriseAPI:             &testBitriseAPI{},
+				AppContactService:      &testAppContactService{},
+				WorkerService:          &testWorkerService{},
+			},
+			requestBody:        `invalid JSON`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   httpresponse.StandardErrorRespModel{Message: "Invalid request body, JSON decode failed"},
+		})
+	})
+
+	t.Run("when build slug is empty", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppID: testAppID,
+			},
+			env: &env.AppEnv{
+				AppService:             &testAppService{},
+				AppSettingsService:     &testAppSettingsService{},
+				AppVersionService:      &testAppVersionService{},
+				ReleaseService:         &testReleaseService{},
+				AppVersionEventService: &testAppVersionEventService{},
+				BitriseAPI:             &testBitriseAPI{},
+				AppContactService:      &testAppContactService{},
+				WorkerService:          &testWorkerService{},
+			},
+			requestBody:        `{}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedResponse: httpresponse.ValidationErrorRespModel{
+				Message: "Unprocessable Entity",
+				Errors:  []string{"build_slug: Cannot be empty"},
+			},
+		})
+	})
+
+	t.Run("when app settings not found in database", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppID: testAppID,
+			},
+			env: &env.AppEnv{
+				AppService: &testAppService{},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+				},
+				AppVersionService:      &testAppVersionService{},
+				ReleaseService:         &testReleaseService{},
+				AppVersionEventService: &testAppVersionEventService{},
+				BitriseAPI:             &testBitriseAPI{},
+				AppContactService:      &testAppContactService{},
+				WorkerService:          &testWorkerService{},
+			},
+			requestBody:        `{"build_slug":"test-build-slug"}`,
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   httpresponse.StandardErrorRespModel{Message: "Not Found"},
+		})
+	})
+
+	t.Run("when the build is already imported", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppID: testAppID,
+			},
+			env: &env.AppEnv{
+				AppService:         &testAppService{},
+				AppSettingsService: appSettingsService,
+				AppVersionService: &testAppVersionService{
+					findAllFn: func(app *models.App, filterParams map[string]interface{}) ([]models.AppVersion, error) {
+						return []models.AppVersion{{BuildSlug: "test-build-slug"}}, nil
+					},
+				},
+				ReleaseService:         &testReleaseService{},
+				AppVersionEventService: &testAppVersionEventService{},
+				BitriseAPI:             &testBitriseAPI{},
+				AppContactService:      &testAppContactService{},
+				WorkerService:          &testWorkerService{},
+			},
+			requestBody:        `{"build_slug":"test-build-slug"}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedResponse: httpresponse.ValidationErrorRespModel{
+				Message: "Unprocessable Entity",
+				Errors:  []string{"build_slug: Build is already imported"},
+			},
+		})
+	})
+
+	t.Run("when the build is still running", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppID: testAppID,
+			},
+			env: &env.AppEnv{
+				AppService:         &testAppService{},
+				AppSettingsService: appSettingsService,
+				AppVersionService: &testAppVersionService{
+					findAllFn: func(app *models.App, filterParams map[string]interface{}) ([]models.AppVersion, error) {
+						return []models.AppVersion{}, nil
+					},
+				},
+				ReleaseService:         &testReleaseService{},
+				AppVersionEventService: &testAppVersionEventService{},
+				BitriseAPI: &testBitriseAPI{
+					getBuildDetailsFn: func(apiToken, appSlug, buildSlug string) (*bitrise.BuildDetails, error) {
+						return &bitrise.BuildDetails{Status: bitrise.BuildStatusInProgress}, nil
+					},
+				},
+				AppContactService: &testAppContactService{},
+				WorkerService:     &testWorkerService{},
+			},
+			requestBody:        `{"build_slug":"test-build-slug"}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedResponse: httpresponse.ValidationErrorRespModel{
+				Message: "Unprocessable Entity",
+				Errors:  []string{"build_slug: Build is not finished yet"},
+			},
+		})
+	})
+
+	t.Run("when the build has no artifact to import", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppID: testAppID,
+			},
+			env: &env.AppEnv{
+				AppService:         &testAppService{},
+				AppSettingsService: appSettingsService,
+				AppVersionService: &testAppVersionService{
+					findAllFn: func(app *models.App, filterParams map[string]interface{}) ([]models.AppVersion, error) {
+						return []models.AppVersion{}, nil
+					},
+				},
+				ReleaseService:         &testReleaseService{},
+				AppVersionEventService: &testAppVersionEventService{},
+				BitriseAPI: &testBitriseAPI{
+					getBuildDetailsFn: func(apiToken, appSlug, buildSlug string) (*bitrise.BuildDetails, error) {
+						return &bitrise.BuildDetails{Status: bitrise.BuildStatusSuccess}, nil
+					},
+					getArtifactsFn: func(apiToken, appSlug, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
+						return []bitrise.ArtifactListElementResponseModel{}, nil
+					},
+					getAppDetailsFn: func(apiToken, appSlug string) (*bitrise.AppDetails, error) {
+						return &bitrise.AppDetails{}, nil
+					},
+				},
+				AppContactService: &testAppContactService{},
+				WorkerService:     &testWorkerService{},
+			},
+			requestBody:        `{"build_slug":"test-build-slug"}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedResponse: httpresponse.ValidationErrorRespModel{
+				Message: "Unprocessable Entity",
+				Errors:  []string{"build_slug: Build has no artifact to create a version from"},
+			},
+		})
+	})
+
+	t.Run("when error happens at getting the build details", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppID: testAppID,
+			},
+			env: &env.AppEnv{
+				AppService:         &testAppService{},
+				AppSettingsService: appSettingsService,
+				AppVersionService: &testAppVersionService{
+					findAllFn: func(app *models.App, filterParams map[string]interface{}) ([]models.AppVersion, error) {
+						return []models.AppVersion{}, nil
+					},
+				},
+				ReleaseService:         &testReleaseService{},
+				AppVersionEventService: &testAppVersionEventService{},
+				BitriseAPI: &testBitriseAPI{
+					getBuildDetailsFn: func(apiToken, appSlug, buildSlug string) (*bitrise.BuildDetails, error) {
+						return nil, errors.New("SOME-BITRISE-API-ERROR")
+					},
+				},
+				AppContactService: &testAppContactService{},
+				WorkerService:     &testWorkerService{},
+			},
+			requestBody:         `{"build_slug":"test-build-slug"}`,
+			expectedInternalErr: "SOME-BITRISE-API-ERROR",
+		})
+	})
+}
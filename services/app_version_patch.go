@@ -3,6 +3,7 @@ package services
 import (
 	"encoding/json"
 	"net/http"
+	"reflect"
 
 	"github.com/bitrise-io/addons-ship-backend/env"
 	"github.com/bitrise-io/addons-ship-backend/models"
@@ -14,13 +15,15 @@ import (
 
 // AppVersionPutRequestData ...
 type AppVersionPutRequestData struct {
-	AppStoreInfo models.AppStoreInfo `json:"app_store_info"`
+	AppStoreInfo      models.AppStoreInfo       `json:"app_store_info"`
+	IosReleaseOptions *models.IosReleaseOptions `json:"ios_release_options"`
 }
 
 // AppVersionPutResponseData ...
 type AppVersionPutResponseData struct {
 	*models.AppVersion
-	AppStoreInfo models.AppStoreInfo `json:"app_store_info"`
+	AppStoreInfo      models.AppStoreInfo       `json:"app_store_info"`
+	IosReleaseOptions *models.IosReleaseOptions `json:"ios_release_options,omitempty"`
 }
 
 // AppVersionPutResponse ...
@@ -54,14 +57,52 @@ func AppVersionPutHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Reques
 	case err != nil:
 		return errors.Wrap(err, "SQL Error")
 	}
+	previousAppStoreInfo, err := appVersionToUpdate.AppStoreInfo()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	previousIosReleaseOptions, err := appVersionToUpdate.IosReleaseOptions()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	storeInfoChanged := !reflect.DeepEqual(previousAppStoreInfo, params.AppStoreInfo)
 	appVersionToUpdate.AppStoreInfoData = appStoreInfo
-	verr, err := env.AppVersionService.Update(appVersionToUpdate, []string{"AppStoreInfoData"})
+	whitelist := []string{"AppStoreInfoData"}
+
+	// release options are only updated when they're sent
+	if params.IosReleaseOptions != nil {
+		if !appVersionToUpdate.IsApplePlatform() {
+			return httpresponse.RespondWithBadRequestError(w, "Release options are only available for iOS versions")
+		}
+		if releaseDate := params.IosReleaseOptions.ScheduledReleaseDate; releaseDate != nil {
+			if env.TimeService == nil {
+				return errors.New("No Time Service defined for handler")
+			}
+			if !releaseDate.After(env.TimeService.Now()) {
+				return httpresponse.RespondWithUnprocessableEntity(w, []error{errors.New("ios_release_options.scheduled_release_date: Has to be in the future")})
+			}
+		}
+		appVersionToUpdate.IosReleaseOptionsData, err = json.Marshal(params.IosReleaseOptions)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		whitelist = append(whitelist, "IosReleaseOptionsData")
+		storeInfoChanged = storeInfoChanged || !previousIosReleaseOptions.Equal(*params.IosReleaseOptions)
+	}
+
+	verr, err := env.AppVersionService.Update(appVersionToUpdate, whitelist)
 	if len(verr) > 0 {
 		return httpresponse.RespondWithUnprocessableEntity(w, verr)
 	}
 	if err != nil {
 		return errors.Wrap(err, "SQL Error")
 	}
+	// approvals were given to the previous store info and release options
+	if storeInfoChanged {
+		if err := invalidateApprovals(env, appVersionToUpdate); err != nil {
+			return errors.WithStack(err)
+		}
+	}
 	response, err := newArtifactVersionPatchResponse(appVersionToUpdate)
 	if err != nil {
 		return errors.WithStack(err)
@@ -77,8 +118,13 @@ func newArtifactVersionPatchResponse(appVersion *models.AppVersion) (AppVersionP
 	if err != nil {
 		return AppVersionPutResponseData{}, err
 	}
+	iosReleaseOptions, err := newIosReleaseOptionsResponse(appVersion)
+	if err != nil {
+		return AppVersionPutResponseData{}, err
+	}
 	return AppVersionPutResponseData{
-		AppVersion:   appVersion,
-		AppStoreInfo: appStoreInfo,
+		AppVersion:        appVersion,
+		AppStoreInfo:      appStoreInfo,
+		IosReleaseOptions: iosReleaseOptions,
 	}, nil
 }
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/bitrise-io/addons-ship-backend/env"
 	"github.com/bitrise-io/addons-ship-backend/models"
@@ -93,6 +94,12 @@ func Test_AppVersionPutHandler(t *testing.T) {
 						return nil, nil
 					},
 				},
+				ApprovalService: &testApprovalService{
+					invalidateAllFn: func(appVersion *models.AppVersion) (int, error) {
+						require.Equal(t, testAppVersionID, appVersion.ID)
+						return 0, nil
+					},
+				},
 			},
 			requestBody:        `{"app_store_info":{"short_description":"Some short description"}}`,
 			expectedStatusCode: http.StatusOK,
@@ -109,6 +116,157 @@ func Test_AppVersionPutHandler(t *testing.T) {
 		})
 	})
 
+	t.Run("ok - when store info of an approved version changes", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: &testAppVersionService{
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						appVersion.AppStoreInfoData = json.RawMessage(`{"short_description":"Approved description"}`)
+						return appVersion, nil
+					},
+					updateFn: func(appVersion *models.AppVersion, whitelist []string) ([]error, error) {
+						return nil, nil
+					},
+				},
+				ApprovalService: &testApprovalService{
+					invalidateAllFn: func(appVersion *models.AppVersion) (int, error) {
+						require.Equal(t, testAppVersionID, appVersion.ID)
+						return 2, nil
+					},
+				},
+				AppVersionEventService: &testAppVersionEventService{
+					createFn: func(event *models.AppVersionEvent) (*models.AppVersionEvent, error) {
+						require.Equal(t, testAppVersionID, event.AppVersionID)
+						require.Equal(t, "Approvals were invalidated by an edit of the store info", event.Text)
+						return event, nil
+					},
+				},
+			},
+			requestBody:        `{"app_store_info":{"short_description":"Edited description"}}`,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppVersionPutResponse{
+				Data: services.AppVersionPutResponseData{
+					AppVersion:   &models.AppVersion{Record: models.Record{ID: testAppVersionID}},
+					AppStoreInfo: models.AppStoreInfo{ShortDescription: "Edited description"},
+				},
+			},
+		})
+	})
+
+	t.Run("when store info doesn't change", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: &testAppVersionService{
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						appVersion.AppStoreInfoData = json.RawMessage(`{"short_description":"Approved description"}`)
+						return appVersion, nil
+					},
+					updateFn: func(appVersion *models.AppVersion, whitelist []string) ([]error, error) {
+						return nil, nil
+					},
+				},
+				ApprovalService: &testApprovalService{},
+			},
+			requestBody:        `{"app_store_info":{"short_description":"Approved description"}}`,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppVersionPutResponse{
+				Data: services.AppVersionPutResponseData{
+					AppVersion:   &models.AppVersion{Record: models.Record{ID: testAppVersionID}},
+					AppStoreInfo: models.AppStoreInfo{ShortDescription: "Approved description"},
+				},
+			},
+		})
+	})
+
+	t.Run("ok - ios release options", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: &testAppVersionService{
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						return &models.AppVersion{Platform: "ios", AppStoreInfoData: json.RawMessage(`{}`), IosReleaseOptionsData: json.RawMessage(`{}`)}, nil
+					},
+					updateFn: func(appVersion *models.AppVersion, whitelist []string) ([]error, error) {
+						require.Equal(t, []string{"AppStoreInfoData", "IosReleaseOptionsData"}, whitelist)
+						require.Equal(t, `{"release_type":"after_approval","phased_release":true,"reset_ratings":true}`, string(appVersion.IosReleaseOptionsData))
+						return nil, nil
+					},
+				},
+				ApprovalService: &testApprovalService{
+					invalidateAllFn: func(appVersion *models.AppVersion) (int, error) {
+						return 1, nil
+					},
+				},
+				AppVersionEventService: &testAppVersionEventService{
+					createFn: func(event *models.AppVersionEvent) (*models.AppVersionEvent, error) {
+						require.Equal(t, "Approvals were invalidated by an edit of the store info", event.Text)
+						return event, nil
+					},
+				},
+			},
+			requestBody:        `{"ios_release_options":{"release_type":"after_approval","phased_release":true,"reset_ratings":true}}`,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppVersionPutResponse{
+				Data: services.AppVersionPutResponseData{
+					AppVersion:        &models.AppVersion{Platform: "ios"},
+					IosReleaseOptions: &models.IosReleaseOptions{ReleaseType: "after_approval", PhasedRelease: true, ResetRatings: true},
+				},
+			},
+		})
+	})
+
+	t.Run("when ios release options are sent for an android version", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: &testAppVersionService{
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						return &models.AppVersion{Platform: "android", AppStoreInfoData: json.RawMessage(`{}`)}, nil
+					},
+				},
+			},
+			requestBody:        `{"ios_release_options":{"release_type":"manual"}}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   httpresponse.StandardErrorRespModel{Message: "Release options are only available for iOS versions"},
+		})
+	})
+
+	t.Run("when scheduled release date is not in the future", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: &testAppVersionService{
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						return &models.AppVersion{Platform: "ios", AppStoreInfoData: json.RawMessage(`{}`)}, nil
+					},
+				},
+				TimeService: &testTimeService{
+					nowFn: func() time.Time {
+						return time.Date(2019, 1, 1, 10, 0, 0, 0, time.UTC)
+					},
+				},
+			},
+			requestBody:        `{"ios_release_options":{"release_type":"scheduled","scheduled_release_date":"2019-01-01T10:00:00Z"}}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedResponse: httpresponse.ValidationErrorRespModel{
+				Message: "Unprocessable Entity",
+				Errors:  []string{"ios_release_options.scheduled_release_date: Has to be in the future"},
+			},
+		})
+	})
+
 	t.Run("when request body is not a valid JSON", func(t *testing.T) {
 		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
 			contextElements: map[ctxpkg.RequestContextKey]interface{}{
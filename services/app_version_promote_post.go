@@ -0,0 +1,216 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/api-utils/httprequest"
+	"github.com/bitrise-io/api-utils/httpresponse"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/thoas/go-funk"
+)
+
+// AppVersionPromoteParams ...
+type AppVersionPromoteParams struct {
+	FromTrack string `json:"from_track"`
+	ToTrack   string `json:"to_track"`
+}
+
+// AppVersionPromoteResponse ...
+type AppVersionPromoteResponse struct {
+	Data     models.PublishTask `json:"data"`
+	Warnings []string           `json:"warnings"`
+}
+
+// AppVersionPromotePostHandler promotes the Google Play release of an app
+// version from a track to another one. The promote task moves the already
+// uploaded release, so the version doesn't have to be resigned and uploaded
+// again.
+func AppVersionPromotePostHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Request) error {
+	authorizedAppVersionID, err := GetAuthorizedAppVersionIDFromContext(r.Context())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if env.AppVersionService == nil {
+		return errors.New("No App Version Service defined for handler")
+	}
+	if env.AppSettingsService == nil {
+		return errors.New("No App Settings Service defined for handler")
+	}
+	if env.PublishTaskService == nil {
+		return errors.New("No Publish Task Service defined for handler")
+	}
+	if env.AppVersionEventService == nil {
+		return errors.New("No App Version Event Service defined for handler")
+	}
+	if env.BitriseAPI == nil {
+		return errors.New("No Bitrise API Service defined for handler")
+	}
+
+	var params AppVersionPromoteParams
+	defer httprequest.BodyCloseWithErrorLog(r)
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		return httpresponse.RespondWithBadRequestError(w, "Invalid request body, JSON decode failed")
+	}
+
+	appVersion, err := env.AppVersionService.Find(&models.AppVersion{Record: models.Record{ID: authorizedAppVersionID}})
+	switch {
+	case errors.Cause(err) == gorm.ErrRecordNotFound:
+		return httpresponse.RespondWithNotFoundError(w)
+	case err != nil:
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	destination := publishDestinationByID(googlePlayDestinationID)
+	if destination == nil || destination.Platform() != appVersion.Platform {
+		return httpresponse.RespondWithBadRequestError(w, "Promotion is only available for Google Play releases")
+	}
+	for _, track := range []string{params.FromTrack, params.ToTrack} {
+		if !funk.ContainsString(destination.Tracks(), track) {
+			return httpresponse.RespondWithBadRequestError(w, fmt.Sprintf("Invalid track: %s", track))
+		}
+	}
+	if params.FromTrack == params.ToTrack {
+		return httpresponse.RespondWithBadRequestError(w, "Release can't be promoted to the track it's on")
+	}
+
+	appSettings, err := env.AppSettingsService.Find(&models.AppSettings{AppID: appVersion.AppID})
+	switch {
+	case errors.Cause(err) == gorm.ErrRecordNotFound:
+		return httpresponse.RespondWithNotFoundError(w)
+	case err != nil:
+		return errors.Wrap(err, "SQL Error")
+	}
+	androidSettings, err := publishAndroidSettings(appSettings)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	publishTasks, err := env.PublishTaskService.FindAll(appVersion)
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+	if _, err := currentPublishedRelease(appVersion, publishTasks, params.FromTrack, androidSettings.Track); err != nil {
+		return httpresponse.RespondWithBadRequestError(w, err.Error())
+	}
+
+	approvalWarnings, err := missingApprovals(env, appVersion, appSettings, []publishTarget{{destination: destination, track: params.ToTrack}})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if len(approvalWarnings) > 0 {
+		verrs := []error{}
+		for _, warning := range approvalWarnings {
+			verrs = append(verrs, errors.New(warning))
+		}
+		return httpresponse.RespondWithUnprocessableEntity(w, verrs)
+	}
+
+	appPublishTasks, err := env.PublishTaskService.FindAllForApp(&appVersion.App)
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+	warnings, err := newerVersionCodeWarnings(appVersion, appPublishTasks, destination.Tracks(), params.ToTrack, androidSettings.Track)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	config, err := getConfigJSON(appSettings)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	authToken, err := env.JWTService.Sign(appVersion.App.APIToken)
+	if err != nil {
+		return errors.Wrap(err, "Failed to sign API token")
+	}
+
+	rollout := androidSettings.Rollout()
+	query := url.Values{"track": {params.ToTrack}, "promote_from": {params.FromTrack}}
+	response, err := triggerPublishTask(env, destination, models.AndroidPromoteWorkflowID, config, PublishDestinationParams{
+		Env:         env,
+		AppVersion:  appVersion,
+		AppSettings: appSettings,
+		AuthToken:   authToken,
+		ConfigURL:   publishDestinationConfigURL(env, destination, appVersion.App.AppSlug, authorizedAppVersionID, query),
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	publishTask, err := env.PublishTaskService.Create(&models.PublishTask{
+		TaskID:        response.TaskIdentifier,
+		AppVersionID:  authorizedAppVersionID,
+		Destination:   destination.ID(),
+		Track:         params.ToTrack,
+		FromTrack:     params.FromTrack,
+		Status:        models.PublishTaskStatusPending,
+		Action:        models.PublishTaskActionPromote,
+		BatchID:       uuid.NewV4(),
+		ReleaseStatus: rollout.ReleaseStatus,
+		UserFraction:  rollout.UserFraction,
+	})
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	_, err = env.AppVersionEventService.Create(&models.AppVersionEvent{
+		Status:       models.PublishTaskStatusInProgress,
+		Text:         fmt.Sprintf("Promotion from %s to %s track started", params.FromTrack, params.ToTrack),
+		AppVersionID: authorizedAppVersionID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	return httpresponse.RespondWithSuccess(w, AppVersionPromoteResponse{Data: *publishTask, Warnings: warnings})
+}
+
+// newerVersionCodeWarnings warns about the tracks above the target track, which
+// already have a release of another version of the app with a greater version
+// code, as the promoted release wouldn't be served on those tracks
+func newerVersionCodeWarnings(appVersion *models.AppVersion, appPublishTasks []models.PublishTask, tracks []string, toTrack, defaultTrack string) ([]string, error) {
+	warnings := []string{}
+	artifactInfo, err := appVersion.ArtifactInfo()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	versionCode, err := strconv.Atoi(artifactInfo.VersionCode)
+	if err != nil {
+		return warnings, nil
+	}
+
+	newestVersionCodes := map[string]int{}
+	for _, publishTask := range appPublishTasks {
+		if uuid.Equal(publishTask.AppVersionID, appVersion.ID) || publishTask.Status != models.PublishTaskStatusSuccess ||
+			publishTask.Action == models.PublishTaskActionRollout || publishTaskDestinationID(&publishTask.AppVersion, publishTask) != googlePlayDestinationID {
+			continue
+		}
+		otherArtifactInfo, err := publishTask.AppVersion.ArtifactInfo()
+		if err != nil {
+			continue
+		}
+		otherVersionCode, err := strconv.Atoi(otherArtifactInfo.VersionCode)
+		if err != nil {
+			continue
+		}
+		track := publishTaskTrack(publishTask, defaultTrack)
+		if otherVersionCode > newestVersionCodes[track] {
+			newestVersionCodes[track] = otherVersionCode
+		}
+	}
+
+	for _, track := range tracks[funk.IndexOfString(tracks, toTrack)+1:] {
+		if newestVersionCodes[track] > versionCode {
+			warnings = append(warnings, fmt.Sprintf("Track %s already has a newer version code: %d", track, newestVersionCodes[track]))
+		}
+	}
+	return warnings, nil
+}
@@ -0,0 +1,273 @@
+package services_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/bitrise-io/addons-ship-backend/bitrise"
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/addons-ship-backend/services"
+	ctxpkg "github.com/bitrise-io/api-utils/context"
+	"github.com/bitrise-io/api-utils/httpresponse"
+	"github.com/bitrise-io/api-utils/security"
+	"github.com/c2fo/testify/require"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+func Test_AppVersionPromotePostHandler(t *testing.T) {
+	httpMethod := "POST"
+	url := "/apps/{app-slug}/versions/{version-id}/promote"
+	handler := services.AppVersionPromotePostHandler
+
+	testAppVersionID := uuid.FromStringOrNil("de438ddc-98e5-4226-a5f4-fd2d53474879")
+	testOtherAppVersionID := uuid.FromStringOrNil("6f1c2b8e-0d4a-4f3e-9b57-2c8d1e7a4b90")
+	testTaskIdentifier := uuid.FromStringOrNil("13a94c5d-4609-404e-ae69-c625e93b8b71")
+
+	internalPublishTasks := []models.PublishTask{
+		models.PublishTask{Destination: "google-play", Track: "internal", Status: "success", Action: "publish"},
+	}
+
+	// testEnv returns an environment, in which the version is published to the
+	// given tasks, the app has the given publish tasks of its other versions,
+	// and triggering the promote task is checked by the given function
+	testEnv := func(publishTasks, appPublishTasks []models.PublishTask, triggerDENTaskFn func(bitrise.TaskParams) (*bitrise.TriggerResponse, error), eventText string) *env.AppEnv {
+		return &env.AppEnv{
+			AddonHostURL: "http://ship.addon.url",
+			AppVersionService: &testAppVersionService{
+				findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+					require.Equal(t, testAppVersionID, appVersion.ID)
+					return &models.AppVersion{
+						Record:           models.Record{ID: testAppVersionID},
+						App:              models.App{AppSlug: "test-app-slug"},
+						Platform:         "android",
+						ArtifactInfoData: json.RawMessage(`{"version_code":"12"}`),
+					}, nil
+				},
+			},
+			AppSettingsService: &testAppSettingsService{
+				findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+					return &models.AppSettings{}, nil
+				},
+			},
+			PublishTaskService: &testPublishTaskService{
+				findAllFn: func(appVersion *models.AppVersion) ([]models.PublishTask, error) {
+					return publishTasks, nil
+				},
+				findAllForAppFn: func(app *models.App) ([]models.PublishTask, error) {
+					require.Equal(t, "test-app-slug", app.AppSlug)
+					return appPublishTasks, nil
+				},
+				createFn: func(publishTask *models.PublishTask) (*models.PublishTask, error) {
+					require.Equal(t, testTaskIdentifier, publishTask.TaskID)
+					require.Equal(t, testAppVersionID, publishTask.AppVersionID)
+					require.False(t, uuid.Equal(uuid.UUID{}, publishTask.BatchID))
+					publishTask.BatchID = uuid.UUID{}
+					return publishTask, nil
+				},
+			},
+			AppVersionEventService: &testAppVersionEventService{
+				createFn: func(event *models.AppVersionEvent) (*models.AppVersionEvent, error) {
+					require.Equal(t, &models.AppVersionEvent{Status: "in_progress", Text: eventText, AppVersionID: testAppVersionID}, event)
+					return event, nil
+				},
+			},
+			BitriseAPI: &testBitriseAPI{triggerDENTaskFn: triggerDENTaskFn},
+			JWTService: &security.JWTMock{
+				SignFn: func(token string) (string, error) {
+					return "jwt-token", nil
+				},
+			},
+		}
+	}
+
+	behavesAsServiceCravingHandler(t, httpMethod, url, handler, []string{"AppVersionService", "AppSettingsService", "PublishTaskService", "AppVersionEventService", "BitriseAPI"}, ControllerTestCase{
+		contextElements: map[ctxpkg.RequestContextKey]interface{}{
+			services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+		},
+		env: testEnv(internalPublishTasks, nil, nil, ""),
+	})
+
+	behavesAsContextCravingHandler(t, httpMethod, url, handler, []ctxpkg.RequestContextKey{services.ContextKeyAuthorizedAppVersionID}, ControllerTestCase{
+		contextElements: map[ctxpkg.RequestContextKey]interface{}{
+			services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+		},
+		env: testEnv(internalPublishTasks, nil, nil, ""),
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: testEnv(internalPublishTasks, []models.PublishTask{}, func(params bitrise.TaskParams) (*bitrise.TriggerResponse, error) {
+				require.Equal(t, "promote_android", params.Workflow)
+				require.Equal(t, "http://ship.addon.url/apps/test-app-slug/versions/de438ddc-98e5-4226-a5f4-fd2d53474879/android-config?promote_from=internal&track=beta", params.InlineEnvs["CONFIG_JSON_URL"])
+				return &bitrise.TriggerResponse{TaskIdentifier: testTaskIdentifier}, nil
+			}, "Promotion from internal to beta track started"),
+			requestBody:        `{"from_track":"internal","to_track":"beta"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppVersionPromoteResponse{
+				Data:     models.PublishTask{TaskID: testTaskIdentifier, Destination: "google-play", Track: "beta", FromTrack: "internal", Status: "pending", Action: "promote"},
+				Warnings: []string{},
+			},
+		})
+	})
+
+	t.Run("ok - when a higher track has a newer version code", func(t *testing.T) {
+		otherAppVersion := func(versionCode string) models.AppVersion {
+			return models.AppVersion{Record: models.Record{ID: testOtherAppVersionID}, ArtifactInfoData: json.RawMessage(`{"version_code":"` + versionCode + `"}`)}
+		}
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: testEnv(internalPublishTasks, []models.PublishTask{
+				models.PublishTask{Destination: "google-play", Track: "production", Status: "success", Action: "publish", AppVersionID: testOtherAppVersionID, AppVersion: otherAppVersion("10")},
+				models.PublishTask{Destination: "google-play", Track: "production", Status: "success", Action: "promote", AppVersionID: testOtherAppVersionID, AppVersion: otherAppVersion("14")},
+				models.PublishTask{Destination: "google-play", Track: "alpha", Status: "success", Action: "publish", AppVersionID: testOtherAppVersionID, AppVersion: otherAppVersion("20")},
+				models.PublishTask{Destination: "google-play", Track: "production", Status: "failed", Action: "publish", AppVersionID: testOtherAppVersionID, AppVersion: otherAppVersion("30")},
+				models.PublishTask{Destination: "google-play", Track: "beta", Status: "success", Action: "publish", AppVersionID: testAppVersionID, AppVersion: otherAppVersion("40")},
+			}, func(params bitrise.TaskParams) (*bitrise.TriggerResponse, error) {
+				return &bitrise.TriggerResponse{TaskIdentifier: testTaskIdentifier}, nil
+			}, "Promotion from internal to beta track started"),
+			requestBody:        `{"from_track":"internal","to_track":"beta"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppVersionPromoteResponse{
+				Data:     models.PublishTask{TaskID: testTaskIdentifier, Destination: "google-play", Track: "beta", FromTrack: "internal", Status: "pending", Action: "promote"},
+				Warnings: []string{"Track production already has a newer version code: 14"},
+			},
+		})
+	})
+
+	for _, tc := range []struct {
+		name            string
+		publishTasks    []models.PublishTask
+		requestBody     string
+		expectedMessage string
+	}{
+		{
+			name:            "when request body is invalid",
+			publishTasks:    internalPublishTasks,
+			requestBody:     `invalid JSON`,
+			expectedMessage: "Invalid request body, JSON decode failed",
+		},
+		{
+			name:            "when a track is invalid",
+			publishTasks:    internalPublishTasks,
+			requestBody:     `{"from_track":"internal","to_track":"nightly"}`,
+			expectedMessage: "Invalid track: nightly",
+		},
+		{
+			name:            "when the tracks are the same",
+			publishTasks:    internalPublishTasks,
+			requestBody:     `{"from_track":"internal","to_track":"internal"}`,
+			expectedMessage: "Release can't be promoted to the track it's on",
+		},
+		{
+			name:            "when version hasn't been published to the track",
+			publishTasks:    internalPublishTasks,
+			requestBody:     `{"from_track":"alpha","to_track":"beta"}`,
+			expectedMessage: "Version hasn't been published to the track yet",
+		},
+		{
+			name: "when a publish task of the track is running",
+			publishTasks: append([]models.PublishTask{
+				models.PublishTask{Destination: "google-play", Track: "internal", Status: "in_progress"},
+			}, internalPublishTasks...),
+			requestBody:     `{"from_track":"internal","to_track":"beta"}`,
+			expectedMessage: "A publish task of the track is still running",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+				contextElements: map[ctxpkg.RequestContextKey]interface{}{
+					services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+				},
+				env:                testEnv(tc.publishTasks, nil, nil, ""),
+				requestBody:        tc.requestBody,
+				expectedStatusCode: http.StatusBadRequest,
+				expectedResponse:   httpresponse.StandardErrorRespModel{Message: tc.expectedMessage},
+			})
+		})
+	}
+
+	t.Run("when the version is missing approvals for the target track", func(t *testing.T) {
+		testAppEnv := testEnv(internalPublishTasks, nil, nil, "")
+		testAppEnv.AppSettingsService = &testAppSettingsService{
+			findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+				return &models.AppSettings{ApprovalRequirementsData: json.RawMessage(`[{"destination":"google-play","track":"production","approvals":1}]`)}, nil
+			},
+		}
+		testAppEnv.ApprovalService = &testApprovalService{
+			findAllFn: func(appVersion *models.AppVersion) ([]models.Approval, error) {
+				require.Equal(t, testAppVersionID, appVersion.ID)
+				return []models.Approval{{Approver: "qa@bitrise.io", Status: "revoked"}}, nil
+			},
+		}
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env:                testAppEnv,
+			requestBody:        `{"from_track":"internal","to_track":"production"}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedResponse: httpresponse.ValidationErrorRespModel{
+				Message: "Unprocessable Entity",
+				Errors:  []string{"Publishing to google-play (production track) requires 1 approvals, the version has 0"},
+			},
+		})
+	})
+
+	t.Run("when version isn't an android version", func(t *testing.T) {
+		testAppEnv := testEnv(internalPublishTasks, nil, nil, "")
+		testAppEnv.AppVersionService = &testAppVersionService{
+			findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+				return &models.AppVersion{Platform: "ios"}, nil
+			},
+		}
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env:                testAppEnv,
+			requestBody:        `{"from_track":"internal","to_track":"beta"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   httpresponse.StandardErrorRespModel{Message: "Promotion is only available for Google Play releases"},
+		})
+	})
+
+	t.Run("when app version not found in database", func(t *testing.T) {
+		testAppEnv := testEnv(internalPublishTasks, nil, nil, "")
+		testAppEnv.AppVersionService = &testAppVersionService{
+			findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+				return nil, gorm.ErrRecordNotFound
+			},
+		}
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env:                testAppEnv,
+			requestBody:        `{"from_track":"internal","to_track":"beta"}`,
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   httpresponse.StandardErrorRespModel{Message: "Not Found"},
+		})
+	})
+
+	t.Run("when error happens at triggering DEN task", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: testEnv(internalPublishTasks, []models.PublishTask{}, func(params bitrise.TaskParams) (*bitrise.TriggerResponse, error) {
+				return nil, errors.New("SOME-BITRISE-API-ERROR")
+			}, ""),
+			requestBody:         `{"from_track":"internal","to_track":"beta"}`,
+			expectedInternalErr: "SOME-BITRISE-API-ERROR",
+		})
+	})
+}
@@ -1,24 +1,51 @@
 package services
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
-	"os"
+	"net/url"
+	"strings"
+	"time"
 
 	rice "github.com/GeertJohan/go.rice"
 	"github.com/bitrise-io/addons-ship-backend/bitrise"
 	"github.com/bitrise-io/addons-ship-backend/env"
 	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/api-utils/httprequest"
 	"github.com/bitrise-io/api-utils/httpresponse"
-	"github.com/bitrise-io/api-utils/structs"
 	"github.com/jinzhu/gorm"
 	"github.com/pkg/errors"
-	yaml "gopkg.in/yaml.v2"
+	uuid "github.com/satori/go.uuid"
+	"github.com/thoas/go-funk"
 )
 
+// AppVersionPublishDestinationParams ...
+type AppVersionPublishDestinationParams struct {
+	ID    string `json:"id"`
+	Track string `json:"track"`
+}
+
+// AppVersionPublishParams ...
+type AppVersionPublishParams struct {
+	Destinations []AppVersionPublishDestinationParams `json:"destinations"`
+	// ScheduledAt schedules the publish instead of starting it right away
+	ScheduledAt *time.Time `json:"scheduled_at"`
+}
+
 // AppVersionPublishResponse ...
 type AppVersionPublishResponse struct {
-	Data *bitrise.TriggerResponse `json:"data"`
+	Data             *bitrise.TriggerResponse `json:"data"`
+	PublishTasks     []models.PublishTask     `json:"publish_tasks"`
+	ScheduledPublish *models.ScheduledPublish `json:"scheduled_publish,omitempty"`
+	// Message explains why the publish to some of the destinations failed
+	Message string `json:"message,omitempty"`
+}
+
+type publishTarget struct {
+	destination PublishDestination
+	track       string
 }
 
 // AppVersionPublishPostHandler ...
@@ -28,6 +55,12 @@ func AppVersionPublishPostHandler(env *env.AppEnv, w http.ResponseWriter, r *htt
 		return errors.WithStack(err)
 	}
 
+	var params AppVersionPublishParams
+	defer httprequest.BodyCloseWithErrorLog(r)
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil && err != io.EOF {
+		return httpresponse.RespondWithBadRequestError(w, "Invalid request body, JSON decode failed")
+	}
+
 	if env.AppVersionService == nil {
 		return errors.New("No App Version Service defined for handler")
 	}
@@ -42,14 +75,121 @@ func AppVersionPublishPostHandler(env *env.AppEnv, w http.ResponseWriter, r *htt
 		return errors.Wrap(err, "SQL Error")
 	}
 
+	if env.AppSettingsService == nil {
+		return errors.New("No App Settings Service defined for handler")
+	}
+
+	appSettings, err := env.AppSettingsService.Find(&models.AppSettings{AppID: appVersion.AppID})
+	switch {
+	case errors.Cause(err) == gorm.ErrRecordNotFound:
+		return httpresponse.RespondWithNotFoundError(w)
+	case err != nil:
+		return errors.Wrap(err, "SQL Error")
+	}
+
 	if env.BitriseAPI == nil {
 		return errors.New("No Bitrise API Service defined for handler")
 	}
+	if env.PublishTaskService == nil {
+		return errors.New("No Publish Task Service defined for handler")
+	}
+
+	targets, err := publishTargets(appVersion.Platform, params.Destinations)
+	if err != nil {
+		return httpresponse.RespondWithBadRequestError(w, err.Error())
+	}
 
-	config, err := getConfigJSON()
+	warning, err := buildCodeWarning(env, appVersion)
 	if err != nil {
 		return errors.WithStack(err)
 	}
+	if warning != "" {
+		return httpresponse.RespondWithUnprocessableEntity(w, []error{errors.New(warning)})
+	}
+
+	// the approvals of scheduled publishes are checked when they get started
+	if params.ScheduledAt != nil {
+		return schedulePublish(env, w, appVersion, params)
+	}
+
+	approvalWarnings, err := missingApprovals(env, appVersion, appSettings, targets)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if len(approvalWarnings) > 0 {
+		verrs := []error{}
+		for _, warning := range approvalWarnings {
+			verrs = append(verrs, errors.New(warning))
+		}
+		return httpresponse.RespondWithUnprocessableEntity(w, verrs)
+	}
+
+	triggerResponse, publishTasks, err := publishAppVersion(env, appVersion, appSettings, targets, uuid.NewV4())
+	if isPublishTriggerError(err) {
+		return httpresponse.RespondWithJSON(w, http.StatusBadGateway, AppVersionPublishResponse{
+			Data:         triggerResponse,
+			PublishTasks: publishTasks,
+			Message:      err.Error(),
+		})
+	}
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return httpresponse.RespondWithSuccess(w, AppVersionPublishResponse{Data: triggerResponse, PublishTasks: publishTasks})
+}
+
+// schedulePublish stores the publish of an app version for the worker, which
+// starts it at the requested time
+func schedulePublish(env *env.AppEnv, w http.ResponseWriter, appVersion *models.AppVersion, params AppVersionPublishParams) error {
+	if env.ScheduledPublishService == nil {
+		return errors.New("No Scheduled Publish Service defined for handler")
+	}
+	if env.TimeService == nil {
+		return errors.New("No Time Service defined for handler")
+	}
+	if !params.ScheduledAt.After(env.TimeService.Now()) {
+		return httpresponse.RespondWithUnprocessableEntity(w, []error{errors.New("scheduled_at: Must be in the future")})
+	}
+	// the version is checked once more when the publish gets started
+	reason, err := publishDisabledReason(env, appVersion)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if reason != "" {
+		return httpresponse.RespondWithUnprocessableEntity(w, []error{errors.New(reason)})
+	}
+
+	scheduledPublish := &models.ScheduledPublish{
+		AppVersionID: appVersion.ID,
+		ScheduledAt:  *params.ScheduledAt,
+	}
+	destinations := []models.ScheduledPublishDestination{}
+	for _, destination := range params.Destinations {
+		destinations = append(destinations, models.ScheduledPublishDestination{ID: destination.ID, Track: destination.Track})
+	}
+	if err := scheduledPublish.SetDestinations(destinations); err != nil {
+		return errors.WithStack(err)
+	}
+	scheduledPublish, err = env.ScheduledPublishService.Create(scheduledPublish)
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	return httpresponse.RespondWithSuccess(w, AppVersionPublishResponse{PublishTasks: []models.PublishTask{}, ScheduledPublish: scheduledPublish})
+}
+
+// publishAppVersion triggers a publish task of an app version for each target,
+// and returns the response of the first trigger with the created tasks. The
+// tasks are created with the given batch ID. A failed task is created for each
+// target which couldn't be triggered, so the batch doesn't finish successfully,
+// and a publishTriggerError is returned with every task. On any other error the
+// tasks created before it are returned with the error.
+func publishAppVersion(env *env.AppEnv, appVersion *models.AppVersion, appSettings *models.AppSettings, targets []publishTarget, batchID uuid.UUID) (*bitrise.TriggerResponse, []models.PublishTask, error) {
+	config, err := getConfigJSON(appSettings)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
 
 	artifactList, err := env.BitriseAPI.GetArtifacts(
 		appVersion.App.BitriseAPIToken,
@@ -57,77 +197,254 @@ func AppVersionPublishPostHandler(env *env.AppEnv, w http.ResponseWriter, r *htt
 		appVersion.BuildSlug,
 	)
 	if err != nil {
-		return errors.WithStack(err)
+		return nil, nil, errors.WithStack(err)
 	}
 
 	authToken, err := env.JWTService.Sign(appVersion.App.APIToken)
 	if err != nil {
-		return errors.Wrap(err, "Failed to sign API token")
-	}
-
-	var workflowToTrigger, stackIDForTrigger string
-	var inlineEnvs map[string]string
-	var secrets map[string]interface{}
-	switch appVersion.Platform {
-	case "ios":
-		artifactData, _, _, _, _ := selectIosArtifact(artifactList)
-		workflowToTrigger = "resign_archive_app_store"
-		stackIDForTrigger = "osx-vs4mac-stable"
-		inlineEnvs = map[string]string{
-			"BITRISE_APP_SLUG":      appVersion.App.AppSlug,
-			"BITRISE_BUILD_SLUG":    appVersion.BuildSlug,
-			"BITRISE_ARTIFACT_SLUG": artifactData.Slug,
-			"CONFIG_JSON_URL":       fmt.Sprintf("%s/apps/%s/versions/%s/ios-config", env.AddonHostURL, appVersion.App.AppSlug, authorizedAppVersionID),
+		return nil, nil, errors.Wrap(err, "Failed to sign API token")
+	}
+
+	var firstTriggerResponse *bitrise.TriggerResponse
+	var publishErr error
+	publishTasks := []models.PublishTask{}
+	triggerErr := &publishTriggerError{}
+	for _, target := range targets {
+		triggerResponse, publishTask, err := publishToTarget(env, appVersion, appSettings, target, config, artifactList, authToken, batchID)
+		if triggerResponse != nil && firstTriggerResponse == nil {
+			firstTriggerResponse = triggerResponse
+		}
+		if publishTask != nil {
+			publishTasks = append(publishTasks, *publishTask)
+		}
+		if err != nil && publishTask != nil {
+			triggerErr.add(target.destination.ID(), err)
+			continue
 		}
-		secrets = map[string]interface{}{"envs": []bitrise.TaskSecret{
-			bitrise.TaskSecret{"BITRISE_ACCESS_TOKEN": appVersion.App.BitriseAPIToken},
-			bitrise.TaskSecret{"ADDON_SHIP_APP_ACCESS_TOKEN": authToken},
-			bitrise.TaskSecret{"SSH_RSA_PRIVATE_KEY": os.Getenv("GITHUB_SSH_KEY")},
-		}}
-	case "android":
-		workflowToTrigger = "resign_android"
-		stackIDForTrigger = "osx-vs4mac-stable"
-		inlineEnvs = map[string]string{
-			"CONFIG_JSON_URL":    fmt.Sprintf("%s/apps/%s/versions/%s/android-config", env.AddonHostURL, appVersion.App.AppSlug, authorizedAppVersionID),
-			"GIT_REPOSITORY_URL": "git@github.com:bitrise-io/addons-ship-bg-worker-task-android.git",
+		if err != nil {
+			publishErr = errors.WithStack(err)
+			break
 		}
-		secrets = map[string]interface{}{"envs": []bitrise.TaskSecret{
-			bitrise.TaskSecret{"BITRISE_ACCESS_TOKEN": appVersion.App.BitriseAPIToken},
-			bitrise.TaskSecret{"ADDON_SHIP_ACCESS_TOKEN": env.AddonAccessToken},
-			bitrise.TaskSecret{"ADDON_SHIP_APP_ACCESS_TOKEN": authToken},
-			bitrise.TaskSecret{"SSH_RSA_PRIVATE_KEY": os.Getenv("GITHUB_SSH_KEY")},
-		}}
+	}
+	if publishErr == nil && len(triggerErr.destinationIDs) > 0 {
+		publishErr = triggerErr
 	}
 
-	if env.PublishTaskService == nil {
-		return errors.New("No Publish Task Service defined for handler")
+	// the version is publishing as soon as one of its tasks got triggered, even
+	// when triggering the others failed
+	status := models.AppVersionStatusPublishing
+	if firstTriggerResponse == nil {
+		status = models.AppVersionStatusFailed
+	}
+	if len(publishTasks) > 0 {
+		if err := updateAppVersionStatus(env, appVersion, status); err != nil {
+			return firstTriggerResponse, publishTasks, errors.WithStack(err)
+		}
+	}
+	return firstTriggerResponse, publishTasks, publishErr
+}
+
+// publishToTarget triggers the publish task of an app version to a target, and
+// creates the task right after it got triggered. The trigger response is
+// returned even when the task couldn't be created. When the trigger fails, a
+// failed task is created and returned with the error.
+func publishToTarget(env *env.AppEnv, appVersion *models.AppVersion, appSettings *models.AppSettings, target publishTarget,
+	config interface{}, artifactList []bitrise.ArtifactListElementResponseModel, authToken string, batchID uuid.UUID) (*bitrise.TriggerResponse, *models.PublishTask, error) {
+	publishTask := &models.PublishTask{
+		AppVersionID: appVersion.ID,
+		Destination:  target.destination.ID(),
+		Track:        target.track,
+		Status:       models.PublishTaskStatusPending,
+		BatchID:      batchID,
+	}
+	if target.destination.ID() == googlePlayDestinationID {
+		androidSettings, err := publishAndroidSettings(appSettings)
+		if err != nil {
+			return nil, nil, errors.WithStack(err)
+		}
+		// the track history of the version needs the track the version got
+		// published to
+		if publishTask.Track == "" {
+			publishTask.Track = androidSettings.Track
+		}
+		rollout := androidSettings.Rollout()
+		publishTask.ReleaseStatus = rollout.ReleaseStatus
+		publishTask.UserFraction = rollout.UserFraction
+	}
+
+	query := url.Values{}
+	if target.track != "" {
+		query.Set("track", target.track)
 	}
-	response, err := env.BitriseAPI.TriggerDENTask(bitrise.TaskParams{
-		StackID:     stackIDForTrigger,
-		Workflow:    workflowToTrigger,
-		BuildConfig: config,
-		InlineEnvs:  inlineEnvs,
-		Secrets:     secrets,
-		WebhookURL:  env.AddonHostURL + "/task-webhook",
+	triggerResponse, triggerErr := triggerPublishTask(env, target.destination, target.destination.Workflow(), config, PublishDestinationParams{
+		Env:         env,
+		AppVersion:  appVersion,
+		AppSettings: appSettings,
+		Artifacts:   artifactList,
+		AuthToken:   authToken,
+		ConfigURL:   publishDestinationConfigURL(env, target.destination, appVersion.App.AppSlug, appVersion.ID, query),
 	})
+	if triggerErr != nil {
+		// the task IDs are unique, the failed task gets one which no webhook
+		// refers to
+		publishTask.TaskID = uuid.NewV4()
+		publishTask.Status = models.PublishTaskStatusFailed
+		publishTask, err := env.PublishTaskService.Create(publishTask)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "SQL Error")
+		}
+		return nil, publishTask, errors.WithStack(triggerErr)
+	}
+
+	publishTask.TaskID = triggerResponse.TaskIdentifier
+	publishTask, err := env.PublishTaskService.Create(publishTask)
 	if err != nil {
-		return errors.WithStack(err)
+		return triggerResponse, nil, errors.Wrap(err, "SQL Error")
 	}
+	return triggerResponse, publishTask, nil
+}
 
-	_, err = env.PublishTaskService.Create(&models.PublishTask{
-		TaskID:       response.TaskIdentifier,
-		AppVersionID: authorizedAppVersionID,
-	})
+// publishTriggerError is returned by publishAppVersion when the publish tasks of
+// some of the targets couldn't be triggered
+type publishTriggerError struct {
+	destinationIDs []string
+	errs           []error
+}
+
+func (e *publishTriggerError) add(destinationID string, err error) {
+	e.destinationIDs = append(e.destinationIDs, destinationID)
+	e.errs = append(e.errs, err)
+}
+
+func (e *publishTriggerError) Error() string {
+	messages := []string{}
+	for i, err := range e.errs {
+		messages = append(messages, fmt.Sprintf("%s: %s", e.destinationIDs[i], err))
+	}
+	return "Failed to trigger publish to " + strings.Join(messages, ", ")
+}
+
+// isPublishTriggerError tells whether publishing failed only because some of
+// the targets couldn't be triggered, when the created tasks can be returned
+func isPublishTriggerError(err error) bool {
+	_, ok := errors.Cause(err).(*publishTriggerError)
+	return ok
+}
+
+// updateAppVersionStatus sets the lifecycle status of an app version
+func updateAppVersionStatus(env *env.AppEnv, appVersion *models.AppVersion, status string) error {
+	appVersion.Status = status
+	verrs, err := env.AppVersionService.Update(appVersion, []string{"Status"})
+	if len(verrs) > 0 {
+		return errors.Errorf("Invalid app version: %s", verrs[0])
+	}
 	if err != nil {
 		return errors.Wrap(err, "SQL Error")
 	}
+	return nil
+}
 
-	return httpresponse.RespondWithSuccess(w, AppVersionPublishResponse{
-		Data: response,
-	})
+// publishTargets returns the destinations and tracks an app version gets
+// published to. When no destinations are requested, the version is published to
+// the default destination of its platform.
+func publishTargets(platform string, params []AppVersionPublishDestinationParams) ([]publishTarget, error) {
+	if len(params) == 0 {
+		destination := publishDestinationForPlatform(platform)
+		if destination == nil {
+			return nil, errors.Errorf("No publish destination for platform: %s", platform)
+		}
+		return []publishTarget{publishTarget{destination: destination}}, nil
+	}
+
+	targets := []publishTarget{}
+	for _, param := range params {
+		destination := publishDestinationByID(param.ID)
+		switch {
+		case destination == nil:
+			return nil, errors.Errorf("Unknown publish destination: %s", param.ID)
+		case destination.Platform() != platform:
+			return nil, errors.Errorf("Publish destination %s doesn't support platform: %s", param.ID, platform)
+		case param.Track != "" && !funk.ContainsString(destination.Tracks(), param.Track):
+			return nil, errors.Errorf("Invalid track for publish destination %s: %s", param.ID, param.Track)
+		}
+		for _, target := range targets {
+			if target.destination.ID() == param.ID && target.track == param.Track {
+				return nil, errors.Errorf("Publish destination is listed more than once: %s", param.ID)
+			}
+		}
+		targets = append(targets, publishTarget{destination: destination, track: param.Track})
+	}
+	return targets, nil
 }
 
-func getConfigJSON() (interface{}, error) {
+// publishDisabledReason returns why an app version can't be published from the
+// current artifacts of its build with the current settings of its app, see
+// isPublishEnabled. The reason is empty when the version can be published.
+func publishDisabledReason(env *env.AppEnv, appVersion *models.AppVersion) (string, error) {
+	artifacts, err := env.BitriseAPI.GetArtifacts(appVersion.App.BitriseAPIToken, appVersion.App.AppSlug, appVersion.BuildSlug)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	publishEnabled, err := isPublishEnabled(appVersion, artifacts)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	switch {
+	case publishEnabled:
+		return "", nil
+	case appVersion.Platform == "android" && len(appVersion.App.AndroidServiceAccountErrors()) > 0:
+		return fmt.Sprintf("The service account of the app has errors: %s", strings.Join(appVersion.App.AndroidServiceAccountErrors(), ", ")), nil
+	}
+	return "No artifact of the build of the version can be published", nil
+}
+
+// buildCodeWarning returns a warning when the build code of an app version is
+// not greater than the greatest one published of the same platform and flavor,
+// as the stores reject such builds, see
+// models.AppVersion.BuildCode. Build codes which can't be parsed aren't checked.
+func buildCodeWarning(env *env.AppEnv, appVersion *models.AppVersion) (string, error) {
+	latestPublished, err := env.AppVersionService.LatestPublished(appVersion)
+	switch {
+	case errors.Cause(err) == gorm.ErrRecordNotFound:
+		return "", nil
+	case err != nil:
+		return "", errors.Wrap(err, "SQL Error")
+	}
+
+	buildCode, err := appVersion.BuildCode()
+	if err != nil {
+		return "", err
+	}
+	publishedBuildCode, err := latestPublished.BuildCode()
+	if err != nil {
+		return "", err
+	}
+	buildCodeNumber, err := models.ParseVersionNumber(buildCode)
+	if err != nil {
+		return "", nil
+	}
+	publishedBuildCodeNumber, err := models.ParseVersionNumber(publishedBuildCode)
+	if err != nil {
+		return "", nil
+	}
+	if buildCodeNumber.Compare(publishedBuildCodeNumber) > 0 {
+		return "", nil
+	}
+
+	return fmt.Sprintf("%s %s is not greater than the greatest published one (%s)", buildCodeName(appVersion), buildCode, publishedBuildCode), nil
+}
+
+// buildCodeName returns how the stores call the build code of an app version
+func buildCodeName(appVersion *models.AppVersion) string {
+	if appVersion.Platform == "android" {
+		return "Version code"
+	}
+	return "Build number"
+}
+
+// getConfigJSON returns the default publish config, merged with the custom
+// publish config of the app, if it has one
+func getConfigJSON(appSettings *models.AppSettings) (interface{}, error) {
 	templateBox, err := rice.FindBox("../utility")
 	if err != nil {
 		return "", errors.WithStack(err)
@@ -137,10 +454,19 @@ func getConfigJSON() (interface{}, error) {
 		return "", errors.WithStack(err)
 	}
 
-	var config interface{}
-	err = yaml.Unmarshal([]byte(tmpContent), &config)
+	config, err := models.ParsePublishConfig(tmpContent)
 	if err != nil {
 		return "", err
 	}
-	return structs.ConvertMapIToMapS(config), nil
+	customConfig, err := appSettings.PublishConfig()
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to parse custom publish config")
+	}
+	if customConfig != nil {
+		config, err = models.MergePublishConfig(config, customConfig)
+		if err != nil {
+			return "", errors.Wrap(err, "Failed to merge custom publish config")
+		}
+	}
+	return map[string]interface{}(config), nil
 }
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/bitrise-io/addons-ship-backend/bitrise"
 	"github.com/bitrise-io/addons-ship-backend/env"
@@ -26,18 +27,24 @@ func Test_AppVersionPublishPostHandler(t *testing.T) {
 
 	testAppVersionID := uuid.FromStringOrNil("de438ddc-98e5-4226-a5f4-fd2d53474879")
 	testTaskIdentifier := uuid.FromStringOrNil("13a94c5d-4609-404e-ae69-c625e93b8b71")
+	testBatchID := uuid.FromStringOrNil("0f5b3d8e-7a0c-4b8f-9c57-2d4e1b6a9f30")
 
-	behavesAsServiceCravingHandler(t, httpMethod, url, handler, []string{"AppVersionService", "PublishTaskService", "BitriseAPI"}, ControllerTestCase{
+	behavesAsServiceCravingHandler(t, httpMethod, url, handler, []string{"AppVersionService", "AppSettingsService", "PublishTaskService", "BitriseAPI"}, ControllerTestCase{
 		contextElements: map[ctxpkg.RequestContextKey]interface{}{
 			services.ContextKeyAuthorizedAppVersionID: uuid.NewV4(),
 		},
 		env: &env.AppEnv{
 			AppVersionService: &testAppVersionService{
 				findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
-					return &models.AppVersion{}, nil
+					return &models.AppVersion{Platform: "android"}, nil
 				},
 			},
 			PublishTaskService: &testPublishTaskService{},
+			AppSettingsService: &testAppSettingsService{
+				findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+					return &models.AppSettings{}, nil
+				},
+			},
 			BitriseAPI: &testBitriseAPI{
 				getArtifactsFn: func(apiToken string, appSlug string, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
 					return []bitrise.ArtifactListElementResponseModel{}, nil
@@ -58,6 +65,11 @@ func Test_AppVersionPublishPostHandler(t *testing.T) {
 		env: &env.AppEnv{
 			AppVersionService:  &testAppVersionService{},
 			PublishTaskService: &testPublishTaskService{},
+			AppSettingsService: &testAppSettingsService{
+				findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+					return &models.AppSettings{}, nil
+				},
+			},
 			BitriseAPI: &testBitriseAPI{
 				getArtifactsFn: func(apiToken string, appSlug string, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
 					return []bitrise.ArtifactListElementResponseModel{}, nil
@@ -78,9 +90,22 @@ func Test_AppVersionPublishPostHandler(t *testing.T) {
 			},
 			env: &env.AppEnv{
 				AppVersionService: &testAppVersionService{
+					updateFn: func(appVersion *models.AppVersion, whitelist []string) ([]error, error) {
+						require.Equal(t, []string{"Status"}, whitelist)
+						require.Equal(t, "publishing", appVersion.Status)
+						return nil, nil
+					},
+					latestPublishedFn: func(*models.AppVersion) (*models.AppVersion, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
 					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
 						require.Equal(t, appVersion.ID, testAppVersionID)
-						return &models.AppVersion{App: models.App{}, AppStoreInfoData: json.RawMessage(`{}`)}, nil
+						return &models.AppVersion{App: models.App{}, Platform: "android", AppStoreInfoData: json.RawMessage(`{}`)}, nil
+					},
+				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						return &models.AppSettings{}, nil
 					},
 				},
 				BitriseAPI: &testBitriseAPI{
@@ -92,8 +117,8 @@ func Test_AppVersionPublishPostHandler(t *testing.T) {
 					},
 				},
 				PublishTaskService: &testPublishTaskService{
-					createFn: func(*models.PublishTask) (*models.PublishTask, error) {
-						return nil, nil
+					createFn: func(publishTask *models.PublishTask) (*models.PublishTask, error) {
+						return &models.PublishTask{}, nil
 					},
 				},
 				JWTService: &security.JWTMock{
@@ -104,7 +129,8 @@ func Test_AppVersionPublishPostHandler(t *testing.T) {
 			},
 			expectedStatusCode: http.StatusOK,
 			expectedResponse: services.AppVersionPublishResponse{
-				Data: &bitrise.TriggerResponse{},
+				Data:         &bitrise.TriggerResponse{},
+				PublishTasks: []models.PublishTask{models.PublishTask{}},
 			},
 		})
 	})
@@ -117,9 +143,18 @@ func Test_AppVersionPublishPostHandler(t *testing.T) {
 			env: &env.AppEnv{
 				AddonHostURL: "http://ship.addon.url",
 				AppVersionService: &testAppVersionService{
+					updateFn: func(appVersion *models.AppVersion, whitelist []string) ([]error, error) {
+						require.Equal(t, []string{"Status"}, whitelist)
+						require.Equal(t, "publishing", appVersion.Status)
+						return nil, nil
+					},
+					latestPublishedFn: func(*models.AppVersion) (*models.AppVersion, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
 					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
 						require.Equal(t, appVersion.ID, testAppVersionID)
 						return &models.AppVersion{
+							Record: models.Record{ID: testAppVersionID},
 							App: models.App{
 								AppSlug:         "test-app-slug",
 								BitriseAPIToken: "bitrise-api-addon-token",
@@ -131,6 +166,11 @@ func Test_AppVersionPublishPostHandler(t *testing.T) {
 						}, nil
 					},
 				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						return &models.AppSettings{}, nil
+					},
+				},
 				BitriseAPI: &testBitriseAPI{
 					getArtifactsFn: func(apiToken string, appSlug string, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
 						require.Equal(t, "bitrise-api-addon-token", apiToken)
@@ -163,6 +203,12 @@ func Test_AppVersionPublishPostHandler(t *testing.T) {
 				PublishTaskService: &testPublishTaskService{
 					createFn: func(publishTask *models.PublishTask) (*models.PublishTask, error) {
 						require.Equal(t, testTaskIdentifier, publishTask.TaskID)
+						require.Equal(t, testAppVersionID, publishTask.AppVersionID)
+						require.Equal(t, "app-store-connect", publishTask.Destination)
+						require.Equal(t, "", publishTask.Track)
+						require.Equal(t, "pending", publishTask.Status)
+						require.False(t, uuid.Equal(uuid.UUID{}, publishTask.BatchID))
+						publishTask.BatchID = testBatchID
 						return publishTask, nil
 					},
 				},
@@ -176,6 +222,82 @@ func Test_AppVersionPublishPostHandler(t *testing.T) {
 			expectedStatusCode: http.StatusOK,
 			expectedResponse: services.AppVersionPublishResponse{
 				Data: &bitrise.TriggerResponse{TaskIdentifier: testTaskIdentifier},
+				PublishTasks: []models.PublishTask{
+					models.PublishTask{TaskID: testTaskIdentifier, Destination: "app-store-connect", Status: "pending", BatchID: testBatchID},
+				},
+			},
+		})
+	})
+
+	t.Run("ok - ios version of a build with an archive per bundle ID", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				AddonHostURL: "http://ship.addon.url",
+				AppVersionService: &testAppVersionService{
+					updateFn: func(appVersion *models.AppVersion, whitelist []string) ([]error, error) {
+						require.Equal(t, []string{"Status"}, whitelist)
+						require.Equal(t, "publishing", appVersion.Status)
+						return nil, nil
+					},
+					latestPublishedFn: func(*models.AppVersion) (*models.AppVersion, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						return &models.AppVersion{
+							App:              models.App{AppSlug: "test-app-slug"},
+							Platform:         "ios",
+							AppStoreInfoData: json.RawMessage(`{}`),
+							BuildSlug:        "test-build-slug",
+							BundleID:         "io.bitrise.white-label",
+						}, nil
+					},
+				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						return &models.AppSettings{}, nil
+					},
+				},
+				BitriseAPI: &testBitriseAPI{
+					getArtifactsFn: func(apiToken string, appSlug string, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
+						return []bitrise.ArtifactListElementResponseModel{
+							bitrise.ArtifactListElementResponseModel{
+								Slug:         "white-label-artifact-slug",
+								Title:        "white-label.xcarchive.zip",
+								ArtifactMeta: &bitrise.ArtifactMeta{AppInfo: bitrise.AppInfo{BundleID: "io.bitrise.white-label"}},
+							},
+							bitrise.ArtifactListElementResponseModel{
+								Slug:         "main-artifact-slug",
+								Title:        "main.xcarchive.zip",
+								ArtifactMeta: &bitrise.ArtifactMeta{AppInfo: bitrise.AppInfo{BundleID: "io.bitrise.app"}},
+							},
+						}, nil
+					},
+					triggerDENTaskFn: func(params bitrise.TaskParams) (*bitrise.TriggerResponse, error) {
+						require.Equal(t, "white-label-artifact-slug", params.InlineEnvs["BITRISE_ARTIFACT_SLUG"])
+						return &bitrise.TriggerResponse{TaskIdentifier: testTaskIdentifier}, nil
+					},
+				},
+				PublishTaskService: &testPublishTaskService{
+					createFn: func(publishTask *models.PublishTask) (*models.PublishTask, error) {
+						publishTask.BatchID = testBatchID
+						return publishTask, nil
+					},
+				},
+				JWTService: &security.JWTMock{
+					SignFn: func(token string) (string, error) {
+						return "jwt-token", nil
+					},
+				},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppVersionPublishResponse{
+				Data: &bitrise.TriggerResponse{TaskIdentifier: testTaskIdentifier},
+				PublishTasks: []models.PublishTask{
+					models.PublishTask{TaskID: testTaskIdentifier, Destination: "app-store-connect", Status: "pending", BatchID: testBatchID},
+				},
 			},
 		})
 	})
@@ -194,9 +316,18 @@ func Test_AppVersionPublishPostHandler(t *testing.T) {
 				AddonHostURL:     "http://ship.addon.url",
 				AddonAccessToken: "super-secret-token",
 				AppVersionService: &testAppVersionService{
+					updateFn: func(appVersion *models.AppVersion, whitelist []string) ([]error, error) {
+						require.Equal(t, []string{"Status"}, whitelist)
+						require.Equal(t, "publishing", appVersion.Status)
+						return nil, nil
+					},
+					latestPublishedFn: func(*models.AppVersion) (*models.AppVersion, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
 					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
 						require.Equal(t, appVersion.ID, testAppVersionID)
 						return &models.AppVersion{
+							Record: models.Record{ID: testAppVersionID},
 							App: models.App{
 								AppSlug:         "test-app-slug",
 								BitriseAPIToken: "bitrise-api-addon-token",
@@ -208,6 +339,11 @@ func Test_AppVersionPublishPostHandler(t *testing.T) {
 						}, nil
 					},
 				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						return &models.AppSettings{}, nil
+					},
+				},
 				BitriseAPI: &testBitriseAPI{
 					getArtifactsFn: func(apiToken string, appSlug string, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
 						require.Equal(t, "bitrise-api-addon-token", apiToken)
@@ -234,6 +370,12 @@ func Test_AppVersionPublishPostHandler(t *testing.T) {
 				PublishTaskService: &testPublishTaskService{
 					createFn: func(publishTask *models.PublishTask) (*models.PublishTask, error) {
 						require.Equal(t, testTaskIdentifier, publishTask.TaskID)
+						require.Equal(t, testAppVersionID, publishTask.AppVersionID)
+						require.Equal(t, "google-play", publishTask.Destination)
+						require.Equal(t, "", publishTask.Track)
+						require.Equal(t, "pending", publishTask.Status)
+						require.False(t, uuid.Equal(uuid.UUID{}, publishTask.BatchID))
+						publishTask.BatchID = testBatchID
 						return publishTask, nil
 					},
 				},
@@ -247,12 +389,586 @@ func Test_AppVersionPublishPostHandler(t *testing.T) {
 			expectedStatusCode: http.StatusOK,
 			expectedResponse: services.AppVersionPublishResponse{
 				Data: &bitrise.TriggerResponse{TaskIdentifier: testTaskIdentifier},
+				PublishTasks: []models.PublishTask{
+					models.PublishTask{TaskID: testTaskIdentifier, Destination: "google-play", Status: "pending", BatchID: testBatchID},
+				},
 			},
 		})
 		require.NoError(t, revokeGitUserFn())
 		require.NoError(t, revokeGitPwdFn())
 	})
 
+	t.Run("ok - custom publish config", func(t *testing.T) {
+		testAppID := uuid.FromStringOrNil("4d9a2f5f-6c4c-4dc9-a5a0-3c2d0e52b4b1")
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: &testAppVersionService{
+					updateFn: func(appVersion *models.AppVersion, whitelist []string) ([]error, error) {
+						require.Equal(t, []string{"Status"}, whitelist)
+						require.Equal(t, "publishing", appVersion.Status)
+						return nil, nil
+					},
+					latestPublishedFn: func(*models.AppVersion) (*models.AppVersion, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						return &models.AppVersion{AppID: testAppID, Platform: "android", AppStoreInfoData: json.RawMessage(`{}`)}, nil
+					},
+				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						require.Equal(t, testAppID, appSettings.AppID)
+						return &models.AppSettings{
+							PublishStackID: "osx-xcode-11.1.x",
+							PublishBitriseYML: `workflows:
+  resign_android:
+    steps:
+    - slack@3: {}`,
+						}, nil
+					},
+				},
+				BitriseAPI: &testBitriseAPI{
+					getArtifactsFn: func(apiToken string, appSlug string, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
+						return []bitrise.ArtifactListElementResponseModel{}, nil
+					},
+					triggerDENTaskFn: func(params bitrise.TaskParams) (*bitrise.TriggerResponse, error) {
+						require.Equal(t, "osx-xcode-11.1.x", params.StackID)
+						require.Equal(t, "resign_android", params.Workflow)
+
+						config, ok := params.BuildConfig.(map[string]interface{})
+						require.True(t, ok)
+						workflow := config["workflows"].(map[string]interface{})["resign_android"].(map[string]interface{})
+						stepKeys := []string{}
+						for _, step := range workflow["steps"].([]interface{}) {
+							for stepKey := range step.(map[string]interface{}) {
+								stepKeys = append(stepKeys, stepKey)
+							}
+						}
+						require.Equal(t, []string{
+							"activate-ssh-key@4.0",
+							"git::git@github.com:bitrise-io/addons-ship-bg-worker-task-android-prepare.git@master",
+							"slack@3",
+							"git::git@github.com:bitrise-io/addons-ship-bg-worker-task-android-sync.git@master",
+						}, stepKeys)
+						return &bitrise.TriggerResponse{}, nil
+					},
+				},
+				PublishTaskService: &testPublishTaskService{
+					createFn: func(publishTask *models.PublishTask) (*models.PublishTask, error) {
+						publishTask.BatchID = testBatchID
+						return publishTask, nil
+					},
+				},
+				JWTService: &security.JWTMock{
+					SignFn: func(token string) (string, error) {
+						return "", nil
+					},
+				},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppVersionPublishResponse{
+				Data: &bitrise.TriggerResponse{},
+				PublishTasks: []models.PublishTask{
+					models.PublishTask{Destination: "google-play", Status: "pending", BatchID: testBatchID},
+				},
+			},
+		})
+	})
+
+	t.Run("ok - multiple destinations", func(t *testing.T) {
+		triggeredConfigURLs := []string{}
+		createdBatchIDs := []uuid.UUID{}
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				AddonHostURL: "http://ship.addon.url",
+				AppVersionService: &testAppVersionService{
+					updateFn: func(appVersion *models.AppVersion, whitelist []string) ([]error, error) {
+						require.Equal(t, []string{"Status"}, whitelist)
+						require.Equal(t, "publishing", appVersion.Status)
+						return nil, nil
+					},
+					latestPublishedFn: func(*models.AppVersion) (*models.AppVersion, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						return &models.AppVersion{Record: models.Record{ID: testAppVersionID}, App: models.App{AppSlug: "test-app-slug"}, Platform: "android", AppStoreInfoData: json.RawMessage(`{}`)}, nil
+					},
+				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						return &models.AppSettings{AndroidSettingsData: json.RawMessage(`{"release_status":"inProgress","user_fraction":0.2}`)}, nil
+					},
+				},
+				BitriseAPI: &testBitriseAPI{
+					getArtifactsFn: func(apiToken string, appSlug string, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
+						return []bitrise.ArtifactListElementResponseModel{}, nil
+					},
+					triggerDENTaskFn: func(params bitrise.TaskParams) (*bitrise.TriggerResponse, error) {
+						require.Equal(t, "resign_android", params.Workflow)
+						triggeredConfigURLs = append(triggeredConfigURLs, params.InlineEnvs["CONFIG_JSON_URL"])
+						if len(triggeredConfigURLs) == 1 {
+							return &bitrise.TriggerResponse{TaskIdentifier: testTaskIdentifier}, nil
+						}
+						return &bitrise.TriggerResponse{TaskIdentifier: uuid.FromStringOrNil("5b0a6c1e-3b9f-4d62-8a2c-7e4f1d9b0c35")}, nil
+					},
+				},
+				PublishTaskService: &testPublishTaskService{
+					createFn: func(publishTask *models.PublishTask) (*models.PublishTask, error) {
+						createdBatchIDs = append(createdBatchIDs, publishTask.BatchID)
+						publishTask.BatchID = testBatchID
+						return publishTask, nil
+					},
+				},
+				JWTService: &security.JWTMock{
+					SignFn: func(token string) (string, error) {
+						return "", nil
+					},
+				},
+			},
+			requestBody:        `{"destinations":[{"id":"google-play","track":"internal"},{"id":"google-play","track":"beta"}]}`,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppVersionPublishResponse{
+				Data: &bitrise.TriggerResponse{TaskIdentifier: testTaskIdentifier},
+				PublishTasks: []models.PublishTask{
+					models.PublishTask{TaskID: testTaskIdentifier, Destination: "google-play", Track: "internal", Status: "pending", BatchID: testBatchID, ReleaseStatus: "inProgress", UserFraction: 0.2},
+					models.PublishTask{TaskID: uuid.FromStringOrNil("5b0a6c1e-3b9f-4d62-8a2c-7e4f1d9b0c35"), Destination: "google-play", Track: "beta", Status: "pending", BatchID: testBatchID, ReleaseStatus: "inProgress", UserFraction: 0.2},
+				},
+			},
+		})
+		require.Equal(t, []string{
+			"http://ship.addon.url/apps/test-app-slug/versions/de438ddc-98e5-4226-a5f4-fd2d53474879/android-config?track=internal",
+			"http://ship.addon.url/apps/test-app-slug/versions/de438ddc-98e5-4226-a5f4-fd2d53474879/android-config?track=beta",
+		}, triggeredConfigURLs)
+		require.Len(t, createdBatchIDs, 2)
+		require.False(t, uuid.Equal(uuid.UUID{}, createdBatchIDs[0]))
+		require.Equal(t, createdBatchIDs[0], createdBatchIDs[1])
+	})
+
+	t.Run("ok - scheduled publish", func(t *testing.T) {
+		testScheduledPublishID := uuid.FromStringOrNil("7c1e4d2a-9b3f-4e58-a6d0-2f8b5c9e1a47")
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: &testAppVersionService{
+					latestPublishedFn: func(*models.AppVersion) (*models.AppVersion, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						return &models.AppVersion{Record: models.Record{ID: testAppVersionID}, Platform: "android", BuildSlug: "test-build-slug", ArtifactInfoData: json.RawMessage(`{"build_type":"release"}`), AppStoreInfoData: json.RawMessage(`{}`)}, nil
+					},
+				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						return &models.AppSettings{}, nil
+					},
+				},
+				BitriseAPI: &testBitriseAPI{
+					getArtifactsFn: func(apiToken string, appSlug string, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
+						require.Equal(t, "test-build-slug", buildSlug)
+						return []bitrise.ArtifactListElementResponseModel{}, nil
+					},
+				},
+				PublishTaskService: &testPublishTaskService{},
+				ScheduledPublishService: &testScheduledPublishService{
+					createFn: func(scheduledPublish *models.ScheduledPublish) (*models.ScheduledPublish, error) {
+						require.Equal(t, testAppVersionID, scheduledPublish.AppVersionID)
+						require.True(t, time.Date(2019, time.December, 2, 9, 0, 0, 0, time.UTC).Equal(scheduledPublish.ScheduledAt))
+						require.Equal(t, `[{"id":"google-play","track":"beta"}]`, string(scheduledPublish.DestinationsData))
+						scheduledPublish.ID = testScheduledPublishID
+						scheduledPublish.Status = models.ScheduledPublishStatusScheduled
+						return scheduledPublish, nil
+					},
+				},
+				TimeService: &testTimeService{
+					nowFn: func() time.Time { return time.Date(2019, time.November, 29, 17, 0, 0, 0, time.UTC) },
+				},
+			},
+			requestBody:        `{"destinations":[{"id":"google-play","track":"beta"}],"scheduled_at":"2019-12-02T09:00:00Z"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppVersionPublishResponse{
+				PublishTasks: []models.PublishTask{},
+				ScheduledPublish: &models.ScheduledPublish{
+					Record:           models.Record{ID: testScheduledPublishID},
+					AppVersionID:     testAppVersionID,
+					ScheduledAt:      time.Date(2019, time.December, 2, 9, 0, 0, 0, time.UTC),
+					DestinationsData: json.RawMessage(`[{"id":"google-play","track":"beta"}]`),
+					Status:           "scheduled",
+				},
+			},
+		})
+	})
+
+	t.Run("when a version which can't be published gets scheduled", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: &testAppVersionService{
+					latestPublishedFn: func(*models.AppVersion) (*models.AppVersion, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						return &models.AppVersion{Record: models.Record{ID: testAppVersionID}, Platform: "android", ArtifactInfoData: json.RawMessage(`{"build_type":"debug"}`), AppStoreInfoData: json.RawMessage(`{}`)}, nil
+					},
+				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						return &models.AppSettings{}, nil
+					},
+				},
+				BitriseAPI: &testBitriseAPI{
+					getArtifactsFn: func(apiToken string, appSlug string, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
+						return []bitrise.ArtifactListElementResponseModel{}, nil
+					},
+				},
+				PublishTaskService:      &testPublishTaskService{},
+				ScheduledPublishService: &testScheduledPublishService{},
+				TimeService: &testTimeService{
+					nowFn: func() time.Time { return time.Date(2019, time.November, 29, 17, 0, 0, 0, time.UTC) },
+				},
+			},
+			requestBody:        `{"scheduled_at":"2019-12-02T09:00:00Z"}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedResponse: httpresponse.ValidationErrorRespModel{
+				Message: "Unprocessable Entity",
+				Errors:  []string{"No artifact of the build of the version can be published"},
+			},
+		})
+	})
+
+	t.Run("when the scheduled time is not in the future", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: &testAppVersionService{
+					latestPublishedFn: func(*models.AppVersion) (*models.AppVersion, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						return &models.AppVersion{Record: models.Record{ID: testAppVersionID}, Platform: "android", AppStoreInfoData: json.RawMessage(`{}`)}, nil
+					},
+				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						return &models.AppSettings{}, nil
+					},
+				},
+				BitriseAPI:              &testBitriseAPI{},
+				PublishTaskService:      &testPublishTaskService{},
+				ScheduledPublishService: &testScheduledPublishService{},
+				TimeService: &testTimeService{
+					nowFn: func() time.Time { return time.Date(2019, time.December, 2, 9, 0, 0, 0, time.UTC) },
+				},
+			},
+			requestBody:        `{"scheduled_at":"2019-12-02T09:00:00Z"}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedResponse: httpresponse.ValidationErrorRespModel{
+				Message: "Unprocessable Entity",
+				Errors:  []string{"scheduled_at: Must be in the future"},
+			},
+		})
+	})
+
+	t.Run("when the version is missing approvals", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: &testAppVersionService{
+					latestPublishedFn: func(*models.AppVersion) (*models.AppVersion, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						return &models.AppVersion{Record: models.Record{ID: testAppVersionID}, Platform: "android", AppStoreInfoData: json.RawMessage(`{}`)}, nil
+					},
+				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						return &models.AppSettings{
+							ApprovalRequirementsData: json.RawMessage(`[{"destination":"google-play","approvals":2,"approvers":["qa@bitrise.io","product@bitrise.io"]},{"destination":"app-store-connect","approvals":1}]`),
+						}, nil
+					},
+				},
+				ApprovalService: &testApprovalService{
+					findAllFn: func(appVersion *models.AppVersion) ([]models.Approval, error) {
+						require.Equal(t, testAppVersionID, appVersion.ID)
+						return []models.Approval{
+							{Approver: "qa@bitrise.io", Status: "approved"},
+							{Approver: "product@bitrise.io", Status: "invalidated"},
+							{Approver: "dev@bitrise.io", Status: "approved"},
+						}, nil
+					},
+				},
+				BitriseAPI:         &testBitriseAPI{},
+				PublishTaskService: &testPublishTaskService{},
+			},
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedResponse: httpresponse.ValidationErrorRespModel{
+				Message: "Unprocessable Entity",
+				Errors:  []string{"Publishing to google-play requires 2 approvals, the version has 1"},
+			},
+		})
+	})
+
+	t.Run("when error happens at finding approvals", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: &testAppVersionService{
+					latestPublishedFn: func(*models.AppVersion) (*models.AppVersion, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						return &models.AppVersion{Record: models.Record{ID: testAppVersionID}, Platform: "android", AppStoreInfoData: json.RawMessage(`{}`)}, nil
+					},
+				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						return &models.AppSettings{ApprovalRequirementsData: json.RawMessage(`[{"approvals":1}]`)}, nil
+					},
+				},
+				ApprovalService: &testApprovalService{
+					findAllFn: func(appVersion *models.AppVersion) ([]models.Approval, error) {
+						return nil, errors.New("SOME-SQL-ERROR")
+					},
+				},
+				BitriseAPI:         &testBitriseAPI{},
+				PublishTaskService: &testPublishTaskService{},
+			},
+			expectedInternalErr: "SQL Error: SOME-SQL-ERROR",
+		})
+	})
+
+	for _, tc := range []struct {
+		name            string
+		requestBody     string
+		expectedMessage string
+	}{
+		{
+			name:            "when request body is invalid",
+			requestBody:     `invalid JSON`,
+			expectedMessage: "Invalid request body, JSON decode failed",
+		},
+		{
+			name:            "when destination is unknown",
+			requestBody:     `{"destinations":[{"id":"unknown-store"}]}`,
+			expectedMessage: "Unknown publish destination: unknown-store",
+		},
+		{
+			name:            "when destination doesn't support the platform of the version",
+			requestBody:     `{"destinations":[{"id":"app-store-connect"}]}`,
+			expectedMessage: "Publish destination app-store-connect doesn't support platform: android",
+		},
+		{
+			name:            "when track is invalid",
+			requestBody:     `{"destinations":[{"id":"google-play","track":"nightly"}]}`,
+			expectedMessage: "Invalid track for publish destination google-play: nightly",
+		},
+		{
+			name:            "when destination is listed more than once",
+			requestBody:     `{"destinations":[{"id":"google-play","track":"beta"},{"id":"google-play","track":"beta"}]}`,
+			expectedMessage: "Publish destination is listed more than once: google-play",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+				contextElements: map[ctxpkg.RequestContextKey]interface{}{
+					services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+				},
+				env: &env.AppEnv{
+					AppVersionService: &testAppVersionService{
+						findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+							return &models.AppVersion{Platform: "android", AppStoreInfoData: json.RawMessage(`{}`)}, nil
+						},
+					},
+					AppSettingsService: &testAppSettingsService{
+						findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+							return &models.AppSettings{}, nil
+						},
+					},
+					BitriseAPI:         &testBitriseAPI{},
+					PublishTaskService: &testPublishTaskService{},
+				},
+				requestBody:        tc.requestBody,
+				expectedStatusCode: http.StatusBadRequest,
+				expectedResponse:   httpresponse.StandardErrorRespModel{Message: tc.expectedMessage},
+			})
+		})
+	}
+
+	t.Run("when the build number isn't greater than the published one", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: &testAppVersionService{
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						return &models.AppVersion{Record: models.Record{ID: testAppVersionID}, Platform: "ios", BuildNumber: "1.2.9"}, nil
+					},
+					latestPublishedFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						require.Equal(t, testAppVersionID, appVersion.ID)
+						return &models.AppVersion{Platform: "ios", BuildNumber: "1.2.10"}, nil
+					},
+				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						return &models.AppSettings{}, nil
+					},
+				},
+				BitriseAPI:         &testBitriseAPI{},
+				PublishTaskService: &testPublishTaskService{},
+			},
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedResponse: httpresponse.ValidationErrorRespModel{
+				Message: "Unprocessable Entity",
+				Errors:  []string{"Build number 1.2.9 is not greater than the greatest published one (1.2.10)"},
+			},
+		})
+	})
+
+	t.Run("when error happens at updating the status of the version", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: &testAppVersionService{
+					updateFn: func(appVersion *models.AppVersion, whitelist []string) ([]error, error) {
+						return nil, errors.New("SOME-SQL-ERROR")
+					},
+					latestPublishedFn: func(*models.AppVersion) (*models.AppVersion, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						require.Equal(t, appVersion.ID, testAppVersionID)
+						return &models.AppVersion{App: models.App{}, Platform: "android", AppStoreInfoData: json.RawMessage(`{}`)}, nil
+					},
+				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						return &models.AppSettings{}, nil
+					},
+				},
+				BitriseAPI: &testBitriseAPI{
+					getArtifactsFn: func(apiToken string, appSlug string, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
+						return []bitrise.ArtifactListElementResponseModel{}, nil
+					},
+					triggerDENTaskFn: func(bitrise.TaskParams) (*bitrise.TriggerResponse, error) {
+						return &bitrise.TriggerResponse{}, nil
+					},
+				},
+				PublishTaskService: &testPublishTaskService{
+					createFn: func(publishTask *models.PublishTask) (*models.PublishTask, error) {
+						return &models.PublishTask{}, nil
+					},
+				},
+				JWTService: &security.JWTMock{
+					SignFn: func(token string) (string, error) {
+						return "", nil
+					},
+				},
+			},
+			expectedInternalErr: "SQL Error: SOME-SQL-ERROR",
+		})
+	})
+
+	t.Run("when app settings not found in database", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: &testAppVersionService{
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						return &models.AppVersion{AppStoreInfoData: json.RawMessage(`{}`)}, nil
+					},
+				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+				},
+				BitriseAPI:         &testBitriseAPI{},
+				PublishTaskService: &testPublishTaskService{},
+				JWTService:         &security.JWTMock{},
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   httpresponse.StandardErrorRespModel{Message: "Not Found"},
+		})
+	})
+
+	t.Run("when error happens at finding app settings", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: &testAppVersionService{
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						return &models.AppVersion{AppStoreInfoData: json.RawMessage(`{}`)}, nil
+					},
+				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						return nil, errors.New("SOME-SQL-ERROR")
+					},
+				},
+				BitriseAPI:         &testBitriseAPI{},
+				PublishTaskService: &testPublishTaskService{},
+				JWTService:         &security.JWTMock{},
+			},
+			expectedInternalErr: "SQL Error: SOME-SQL-ERROR",
+		})
+	})
+
+	t.Run("when there's no publish destination for the platform", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: &testAppVersionService{
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						return &models.AppVersion{Platform: "windows", AppStoreInfoData: json.RawMessage(`{}`)}, nil
+					},
+				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						return &models.AppSettings{}, nil
+					},
+				},
+				BitriseAPI: &testBitriseAPI{
+					getArtifactsFn: func(apiToken string, appSlug string, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
+						return []bitrise.ArtifactListElementResponseModel{}, nil
+					},
+				},
+				PublishTaskService: &testPublishTaskService{},
+				JWTService: &security.JWTMock{
+					SignFn: func(token string) (string, error) {
+						return "", nil
+					},
+				},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   httpresponse.StandardErrorRespModel{Message: "No publish destination for platform: windows"},
+		})
+	})
+
 	t.Run("when app version not found in database", func(t *testing.T) {
 		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
 			contextElements: map[ctxpkg.RequestContextKey]interface{}{
@@ -265,6 +981,11 @@ func Test_AppVersionPublishPostHandler(t *testing.T) {
 						return nil, gorm.ErrRecordNotFound
 					},
 				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						return &models.AppSettings{}, nil
+					},
+				},
 				BitriseAPI: &testBitriseAPI{
 					getArtifactsFn: func(apiToken string, appSlug string, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
 						return []bitrise.ArtifactListElementResponseModel{}, nil
@@ -293,6 +1014,11 @@ func Test_AppVersionPublishPostHandler(t *testing.T) {
 						return nil, errors.New("SOME-SQL-ERROR")
 					},
 				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						return &models.AppSettings{}, nil
+					},
+				},
 				BitriseAPI: &testBitriseAPI{
 					getArtifactsFn: func(apiToken string, appSlug string, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
 						return []bitrise.ArtifactListElementResponseModel{}, nil
@@ -315,9 +1041,17 @@ func Test_AppVersionPublishPostHandler(t *testing.T) {
 			},
 			env: &env.AppEnv{
 				AppVersionService: &testAppVersionService{
+					latestPublishedFn: func(*models.AppVersion) (*models.AppVersion, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
 					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
 						require.Equal(t, appVersion.ID, testAppVersionID)
-						return &models.AppVersion{App: models.App{}, AppStoreInfoData: json.RawMessage(`{}`)}, nil
+						return &models.AppVersion{App: models.App{}, Platform: "android", AppStoreInfoData: json.RawMessage(`{}`)}, nil
+					},
+				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						return &models.AppSettings{}, nil
 					},
 				},
 				BitriseAPI: &testBitriseAPI{
@@ -342,9 +1076,21 @@ func Test_AppVersionPublishPostHandler(t *testing.T) {
 			},
 			env: &env.AppEnv{
 				AppVersionService: &testAppVersionService{
+					updateFn: func(appVersion *models.AppVersion, whitelist []string) ([]error, error) {
+						require.Equal(t, "failed", appVersion.Status)
+						return nil, nil
+					},
+					latestPublishedFn: func(*models.AppVersion) (*models.AppVersion, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
 					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
 						require.Equal(t, appVersion.ID, testAppVersionID)
-						return &models.AppVersion{App: models.App{}, AppStoreInfoData: json.RawMessage(`{}`)}, nil
+						return &models.AppVersion{App: models.App{}, Platform: "android", AppStoreInfoData: json.RawMessage(`{}`)}, nil
+					},
+				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						return &models.AppSettings{}, nil
 					},
 				},
 				BitriseAPI: &testBitriseAPI{
@@ -355,14 +1101,27 @@ func Test_AppVersionPublishPostHandler(t *testing.T) {
 						return nil, errors.New("SOME-BITRISE-API-ERROR")
 					},
 				},
-				PublishTaskService: &testPublishTaskService{},
+				PublishTaskService: &testPublishTaskService{
+					createFn: func(publishTask *models.PublishTask) (*models.PublishTask, error) {
+						require.False(t, uuid.Equal(uuid.UUID{}, publishTask.TaskID))
+						publishTask.TaskID = testTaskIdentifier
+						publishTask.BatchID = testBatchID
+						return publishTask, nil
+					},
+				},
 				JWTService: &security.JWTMock{
 					SignFn: func(token string) (string, error) {
 						return "", nil
 					},
 				},
 			},
-			expectedInternalErr: "SOME-BITRISE-API-ERROR",
+			expectedStatusCode: http.StatusBadGateway,
+			expectedResponse: services.AppVersionPublishResponse{
+				PublishTasks: []models.PublishTask{
+					{TaskID: testTaskIdentifier, Destination: "google-play", Status: "failed", BatchID: testBatchID},
+				},
+				Message: "Failed to trigger publish to google-play: SOME-BITRISE-API-ERROR",
+			},
 		})
 	})
 
@@ -373,9 +1132,21 @@ func Test_AppVersionPublishPostHandler(t *testing.T) {
 			},
 			env: &env.AppEnv{
 				AppVersionService: &testAppVersionService{
+					updateFn: func(appVersion *models.AppVersion, whitelist []string) ([]error, error) {
+						require.Equal(t, "publishing", appVersion.Status)
+						return nil, nil
+					},
+					latestPublishedFn: func(*models.AppVersion) (*models.AppVersion, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
 					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
 						require.Equal(t, appVersion.ID, testAppVersionID)
-						return &models.AppVersion{App: models.App{}, AppStoreInfoData: json.RawMessage(`{}`)}, nil
+						return &models.AppVersion{App: models.App{}, Platform: "android", AppStoreInfoData: json.RawMessage(`{}`)}, nil
+					},
+				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						return &models.AppSettings{}, nil
 					},
 				},
 				BitriseAPI: &testBitriseAPI{
@@ -400,4 +1171,69 @@ func Test_AppVersionPublishPostHandler(t *testing.T) {
 			expectedInternalErr: "SQL Error: SOME-SQL-ERROR",
 		})
 	})
+
+	t.Run("when error happens at triggering the publish to one of the destinations", func(t *testing.T) {
+		triggerCount := 0
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				AppVersionService: &testAppVersionService{
+					updateFn: func(appVersion *models.AppVersion, whitelist []string) ([]error, error) {
+						require.Equal(t, "publishing", appVersion.Status)
+						return nil, nil
+					},
+					latestPublishedFn: func(*models.AppVersion) (*models.AppVersion, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+					findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						return &models.AppVersion{Record: models.Record{ID: testAppVersionID}, Platform: "android", AppStoreInfoData: json.RawMessage(`{}`)}, nil
+					},
+				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						return &models.AppSettings{}, nil
+					},
+				},
+				BitriseAPI: &testBitriseAPI{
+					getArtifactsFn: func(apiToken string, appSlug string, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
+						return []bitrise.ArtifactListElementResponseModel{}, nil
+					},
+					triggerDENTaskFn: func(bitrise.TaskParams) (*bitrise.TriggerResponse, error) {
+						triggerCount++
+						if triggerCount == 1 {
+							return &bitrise.TriggerResponse{TaskIdentifier: testTaskIdentifier}, nil
+						}
+						return nil, errors.New("SOME-BITRISE-API-ERROR")
+					},
+				},
+				PublishTaskService: &testPublishTaskService{
+					createFn: func(publishTask *models.PublishTask) (*models.PublishTask, error) {
+						if publishTask.Status == "failed" {
+							require.False(t, uuid.Equal(testTaskIdentifier, publishTask.TaskID))
+							publishTask.TaskID = uuid.FromStringOrNil("5b0a6c1e-3b9f-4d62-8a2c-7e4f1d9b0c35")
+						}
+						publishTask.BatchID = testBatchID
+						return publishTask, nil
+					},
+				},
+				JWTService: &security.JWTMock{
+					SignFn: func(token string) (string, error) {
+						return "", nil
+					},
+				},
+			},
+			requestBody:        `{"destinations":[{"id":"google-play","track":"internal"},{"id":"google-play","track":"beta"}]}`,
+			expectedStatusCode: http.StatusBadGateway,
+			expectedResponse: services.AppVersionPublishResponse{
+				Data: &bitrise.TriggerResponse{TaskIdentifier: testTaskIdentifier},
+				PublishTasks: []models.PublishTask{
+					{TaskID: testTaskIdentifier, Destination: "google-play", Track: "internal", Status: "pending", BatchID: testBatchID},
+					{TaskID: uuid.FromStringOrNil("5b0a6c1e-3b9f-4d62-8a2c-7e4f1d9b0c35"), Destination: "google-play", Track: "beta", Status: "failed", BatchID: testBatchID},
+				},
+				Message: "Failed to trigger publish to google-play: SOME-BITRISE-API-ERROR",
+			},
+		})
+	})
 }
@@ -0,0 +1,260 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/api-utils/httprequest"
+	"github.com/bitrise-io/api-utils/httpresponse"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// AppVersionRolloutParams ...
+type AppVersionRolloutParams struct {
+	Track        string  `json:"track"`
+	UserFraction float64 `json:"user_fraction"`
+}
+
+// AppVersionRolloutResponse ...
+type AppVersionRolloutResponse struct {
+	Data models.PublishTask `json:"data"`
+}
+
+// rolloutUpdate returns the updated rollout and the text of the version event
+// of the update
+type rolloutUpdate func(rollout models.AndroidRollout, params AppVersionRolloutParams) (models.AndroidRollout, string, error)
+
+// AppVersionRolloutIncreasePostHandler ...
+func AppVersionRolloutIncreasePostHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Request) error {
+	return appVersionRolloutHelper(env, w, r, func(rollout models.AndroidRollout, params AppVersionRolloutParams) (models.AndroidRollout, string, error) {
+		rollout, err := rollout.Increase(params.UserFraction)
+		return rollout, fmt.Sprintf("Rollout increased to %g%%", math.Round(params.UserFraction*10000)/100), err
+	})
+}
+
+// AppVersionRolloutHaltPostHandler ...
+func AppVersionRolloutHaltPostHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Request) error {
+	return appVersionRolloutHelper(env, w, r, func(rollout models.AndroidRollout, params AppVersionRolloutParams) (models.AndroidRollout, string, error) {
+		rollout, err := rollout.Halt()
+		return rollout, "Rollout halted", err
+	})
+}
+
+// AppVersionRolloutCompletePostHandler ...
+func AppVersionRolloutCompletePostHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Request) error {
+	return appVersionRolloutHelper(env, w, r, func(rollout models.AndroidRollout, params AppVersionRolloutParams) (models.AndroidRollout, string, error) {
+		rollout, err := rollout.Complete()
+		return rollout, "Rollout completed", err
+	})
+}
+
+// appVersionRolloutHelper updates the rollout of the Google Play release of an
+// app version on a track, by triggering the rollout workflow, which only updates
+// the release without re-signing and uploading it again
+func appVersionRolloutHelper(env *env.AppEnv, w http.ResponseWriter, r *http.Request, update rolloutUpdate) error {
+	authorizedAppVersionID, err := GetAuthorizedAppVersionIDFromContext(r.Context())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if env.AppVersionService == nil {
+		return errors.New("No App Version Service defined for handler")
+	}
+	if env.AppSettingsService == nil {
+		return errors.New("No App Settings Service defined for handler")
+	}
+	if env.PublishTaskService == nil {
+		return errors.New("No Publish Task Service defined for handler")
+	}
+	if env.AppVersionEventService == nil {
+		return errors.New("No App Version Event Service defined for handler")
+	}
+	if env.BitriseAPI == nil {
+		return errors.New("No Bitrise API Service defined for handler")
+	}
+
+	var params AppVersionRolloutParams
+	defer httprequest.BodyCloseWithErrorLog(r)
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil && err != io.EOF {
+		return httpresponse.RespondWithBadRequestError(w, "Invalid request body, JSON decode failed")
+	}
+
+	appVersion, err := env.AppVersionService.Find(&models.AppVersion{Record: models.Record{ID: authorizedAppVersionID}})
+	switch {
+	case errors.Cause(err) == gorm.ErrRecordNotFound:
+		return httpresponse.RespondWithNotFoundError(w)
+	case err != nil:
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	destination := publishDestinationByID(googlePlayDestinationID)
+	if destination == nil || destination.Platform() != appVersion.Platform {
+		return httpresponse.RespondWithBadRequestError(w, "Rollouts are only available for Google Play releases")
+	}
+
+	appSettings, err := env.AppSettingsService.Find(&models.AppSettings{AppID: appVersion.AppID})
+	switch {
+	case errors.Cause(err) == gorm.ErrRecordNotFound:
+		return httpresponse.RespondWithNotFoundError(w)
+	case err != nil:
+		return errors.Wrap(err, "SQL Error")
+	}
+	androidSettings, err := publishAndroidSettings(appSettings)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if params.Track == "" {
+		params.Track = androidSettings.Track
+	}
+
+	publishTasks, err := env.PublishTaskService.FindAll(appVersion)
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+	currentRelease, err := currentPublishedRelease(appVersion, publishTasks, params.Track, androidSettings.Track)
+	if err != nil {
+		return httpresponse.RespondWithBadRequestError(w, err.Error())
+	}
+	rollout, eventText, err := update(currentRelease.Rollout(), params)
+	if err != nil {
+		return httpresponse.RespondWithBadRequestError(w, err.Error())
+	}
+	if params.Track != "" {
+		eventText = fmt.Sprintf("%s on %s track", eventText, params.Track)
+	}
+
+	publishTask, err := triggerRolloutUpdate(env, appVersion, appSettings, params.Track, rollout)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = env.AppVersionEventService.Create(&models.AppVersionEvent{
+		Status:       models.PublishTaskStatusInProgress,
+		Text:         eventText,
+		AppVersionID: authorizedAppVersionID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	return httpresponse.RespondWithSuccess(w, AppVersionRolloutResponse{Data: *publishTask})
+}
+
+// triggerRolloutUpdate triggers the rollout workflow, which updates the rollout
+// of the Google Play release of an app version on a track, and records its
+// publish task
+func triggerRolloutUpdate(env *env.AppEnv, appVersion *models.AppVersion, appSettings *models.AppSettings, track string, rollout models.AndroidRollout) (*models.PublishTask, error) {
+	destination := publishDestinationByID(googlePlayDestinationID)
+	config, err := getConfigJSON(appSettings)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	authToken, err := env.JWTService.Sign(appVersion.App.APIToken)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to sign API token")
+	}
+
+	query := url.Values{"rollout_only": {"true"}, "release_status": {rollout.ReleaseStatus}}
+	if track != "" {
+		query.Set("track", track)
+	}
+	if rollout.UserFraction > 0 {
+		query.Set("user_fraction", strconv.FormatFloat(rollout.UserFraction, 'f', -1, 64))
+	}
+	response, err := triggerPublishTask(env, destination, models.AndroidRolloutWorkflowID, config, PublishDestinationParams{
+		Env:         env,
+		AppVersion:  appVersion,
+		AppSettings: appSettings,
+		AuthToken:   authToken,
+		ConfigURL:   publishDestinationConfigURL(env, destination, appVersion.App.AppSlug, appVersion.ID, query),
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	publishTask, err := env.PublishTaskService.Create(&models.PublishTask{
+		TaskID:        response.TaskIdentifier,
+		AppVersionID:  appVersion.ID,
+		Destination:   destination.ID(),
+		Track:         track,
+		Status:        models.PublishTaskStatusPending,
+		Action:        models.PublishTaskActionRollout,
+		BatchID:       uuid.NewV4(),
+		ReleaseStatus: rollout.ReleaseStatus,
+		UserFraction:  rollout.UserFraction,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "SQL Error")
+	}
+	return publishTask, nil
+}
+
+// currentPublishedRelease returns the latest successful Google Play publish task
+// of a track. The rollout can't be changed while a publish task of the track is
+// running. Tasks created before publish tasks had a status are skipped.
+func currentPublishedRelease(appVersion *models.AppVersion, publishTasks []models.PublishTask, track, defaultTrack string) (models.PublishTask, error) {
+	var current *models.PublishTask
+	for i, publishTask := range publishTasks {
+		if publishTask.Status == "" || publishTaskDestinationID(appVersion, publishTask) != googlePlayDestinationID || publishTaskTrack(publishTask, defaultTrack) != track {
+			continue
+		}
+		if !publishTask.Finished() {
+			return models.PublishTask{}, errors.New("A publish task of the track is still running")
+		}
+		if publishTask.Status == models.PublishTaskStatusSuccess {
+			current = &publishTasks[i]
+		}
+	}
+	if current == nil {
+		return models.PublishTask{}, errors.New("Version hasn't been published to the track yet")
+	}
+	return *current, nil
+}
+
+// publishTaskTrack returns the track of a publish task. Tasks created without a
+// track were published to the track of the settings.
+func publishTaskTrack(publishTask models.PublishTask, defaultTrack string) string {
+	if publishTask.Track != "" {
+		return publishTask.Track
+	}
+	return defaultTrack
+}
+
+// publishAndroidSettings returns the Android settings a version gets published
+// with to Google Play
+func publishAndroidSettings(appSettings *models.AppSettings) (models.AndroidSettings, error) {
+	if len(appSettings.AndroidSettingsData) == 0 {
+		return models.AndroidSettings{}, nil
+	}
+	androidSettings, err := appSettings.AndroidSettings()
+	if err != nil {
+		return models.AndroidSettings{}, errors.WithStack(err)
+	}
+	return androidSettings, nil
+}
+
+// androidRolloutFromQuery returns the rollout of the settings, overridden by
+// the rollout of the publish task, passed in the query of the config URL
+func androidRolloutFromQuery(rollout models.AndroidRollout, query url.Values) (models.AndroidRollout, error) {
+	if releaseStatus := query.Get("release_status"); releaseStatus != "" {
+		rollout.ReleaseStatus = releaseStatus
+		rollout.UserFraction = 0
+	}
+	if userFraction := query.Get("user_fraction"); userFraction != "" {
+		var err error
+		rollout.UserFraction, err = strconv.ParseFloat(userFraction, 64)
+		if err != nil {
+			return models.AndroidRollout{}, errors.New("Invalid user fraction")
+		}
+	}
+	return rollout, nil
+}
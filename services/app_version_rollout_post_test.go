@@ -0,0 +1,273 @@
+package services_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/bitrise-io/addons-ship-backend/bitrise"
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/addons-ship-backend/services"
+	ctxpkg "github.com/bitrise-io/api-utils/context"
+	"github.com/bitrise-io/api-utils/httpresponse"
+	"github.com/bitrise-io/api-utils/security"
+	"github.com/c2fo/testify/require"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+func Test_AppVersionRolloutPostHandlers(t *testing.T) {
+	httpMethod := "POST"
+	url := "/apps/{app-slug}/versions/{version-id}/rollout/increase"
+
+	testAppVersionID := uuid.FromStringOrNil("de438ddc-98e5-4226-a5f4-fd2d53474879")
+	testTaskIdentifier := uuid.FromStringOrNil("13a94c5d-4609-404e-ae69-c625e93b8b71")
+
+	stagedPublishTasks := []models.PublishTask{
+		models.PublishTask{Destination: "google-play", Track: "production", Status: "success", ReleaseStatus: "inProgress", UserFraction: 0.1},
+		models.PublishTask{Destination: "google-play", Track: "beta", Status: "success", ReleaseStatus: "completed"},
+	}
+
+	// testEnv returns an environment, in which the version is published to the
+	// given tasks, and triggering the rollout task is checked by the given
+	// function
+	testEnv := func(publishTasks []models.PublishTask, triggerDENTaskFn func(bitrise.TaskParams) (*bitrise.TriggerResponse, error), eventText string) *env.AppEnv {
+		return &env.AppEnv{
+			AddonHostURL: "http://ship.addon.url",
+			AppVersionService: &testAppVersionService{
+				findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+					require.Equal(t, testAppVersionID, appVersion.ID)
+					return &models.AppVersion{Record: models.Record{ID: testAppVersionID}, App: models.App{AppSlug: "test-app-slug"}, Platform: "android"}, nil
+				},
+			},
+			AppSettingsService: &testAppSettingsService{
+				findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+					return &models.AppSettings{}, nil
+				},
+			},
+			PublishTaskService: &testPublishTaskService{
+				findAllFn: func(appVersion *models.AppVersion) ([]models.PublishTask, error) {
+					return publishTasks, nil
+				},
+				createFn: func(publishTask *models.PublishTask) (*models.PublishTask, error) {
+					require.Equal(t, testTaskIdentifier, publishTask.TaskID)
+					require.Equal(t, "google-play", publishTask.Destination)
+					require.False(t, uuid.Equal(uuid.UUID{}, publishTask.BatchID))
+					publishTask.BatchID = uuid.UUID{}
+					return publishTask, nil
+				},
+			},
+			AppVersionEventService: &testAppVersionEventService{
+				createFn: func(event *models.AppVersionEvent) (*models.AppVersionEvent, error) {
+					require.Equal(t, &models.AppVersionEvent{Status: "in_progress", Text: eventText, AppVersionID: testAppVersionID}, event)
+					return event, nil
+				},
+			},
+			BitriseAPI: &testBitriseAPI{triggerDENTaskFn: triggerDENTaskFn},
+			JWTService: &security.JWTMock{
+				SignFn: func(token string) (string, error) {
+					return "jwt-token", nil
+				},
+			},
+		}
+	}
+
+	behavesAsServiceCravingHandler(t, httpMethod, url, services.AppVersionRolloutIncreasePostHandler, []string{"AppVersionService", "AppSettingsService", "PublishTaskService", "AppVersionEventService", "BitriseAPI"}, ControllerTestCase{
+		contextElements: map[ctxpkg.RequestContextKey]interface{}{
+			services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+		},
+		env: testEnv(stagedPublishTasks, nil, ""),
+	})
+
+	behavesAsContextCravingHandler(t, httpMethod, url, services.AppVersionRolloutIncreasePostHandler, []ctxpkg.RequestContextKey{services.ContextKeyAuthorizedAppVersionID}, ControllerTestCase{
+		contextElements: map[ctxpkg.RequestContextKey]interface{}{
+			services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+		},
+		env: testEnv(stagedPublishTasks, nil, ""),
+	})
+
+	t.Run("ok - increase", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, services.AppVersionRolloutIncreasePostHandler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: testEnv(stagedPublishTasks, func(params bitrise.TaskParams) (*bitrise.TriggerResponse, error) {
+				require.Equal(t, "rollout_android", params.Workflow)
+				require.Equal(t, "http://ship.addon.url/apps/test-app-slug/versions/de438ddc-98e5-4226-a5f4-fd2d53474879/android-config?release_status=inProgress&rollout_only=true&track=production&user_fraction=0.5", params.InlineEnvs["CONFIG_JSON_URL"])
+				return &bitrise.TriggerResponse{TaskIdentifier: testTaskIdentifier}, nil
+			}, "Rollout increased to 50% on production track"),
+			requestBody:        `{"track":"production","user_fraction":0.5}`,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppVersionRolloutResponse{
+				Data: models.PublishTask{TaskID: testTaskIdentifier, Destination: "google-play", Track: "production", Status: "pending", Action: "rollout", ReleaseStatus: "inProgress", UserFraction: 0.5},
+			},
+		})
+	})
+
+	t.Run("ok - halt", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, services.AppVersionRolloutHaltPostHandler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: testEnv([]models.PublishTask{
+				models.PublishTask{Destination: "google-play", Status: "success", ReleaseStatus: "inProgress", UserFraction: 0.1},
+				models.PublishTask{Destination: "google-play", Status: "failed", ReleaseStatus: "inProgress", UserFraction: 0.2},
+			}, func(params bitrise.TaskParams) (*bitrise.TriggerResponse, error) {
+				require.Equal(t, "http://ship.addon.url/apps/test-app-slug/versions/de438ddc-98e5-4226-a5f4-fd2d53474879/android-config?release_status=halted&rollout_only=true&user_fraction=0.1", params.InlineEnvs["CONFIG_JSON_URL"])
+				return &bitrise.TriggerResponse{TaskIdentifier: testTaskIdentifier}, nil
+			}, "Rollout halted"),
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppVersionRolloutResponse{
+				Data: models.PublishTask{TaskID: testTaskIdentifier, Destination: "google-play", Status: "pending", Action: "rollout", ReleaseStatus: "halted", UserFraction: 0.1},
+			},
+		})
+	})
+
+	t.Run("ok - on the track of the settings", func(t *testing.T) {
+		testAppEnv := testEnv(append([]models.PublishTask{
+			models.PublishTask{Destination: "google-play", Status: "success", ReleaseStatus: "inProgress", UserFraction: 0.3},
+		}, stagedPublishTasks...), func(params bitrise.TaskParams) (*bitrise.TriggerResponse, error) {
+			require.Equal(t, "http://ship.addon.url/apps/test-app-slug/versions/de438ddc-98e5-4226-a5f4-fd2d53474879/android-config?release_status=halted&rollout_only=true&track=production&user_fraction=0.1", params.InlineEnvs["CONFIG_JSON_URL"])
+			return &bitrise.TriggerResponse{TaskIdentifier: testTaskIdentifier}, nil
+		}, "Rollout halted on production track")
+		testAppEnv.AppSettingsService = &testAppSettingsService{
+			findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+				return &models.AppSettings{AndroidSettingsData: json.RawMessage(`{"track":"production"}`)}, nil
+			},
+		}
+		performControllerTest(t, httpMethod, url, services.AppVersionRolloutHaltPostHandler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env:                testAppEnv,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppVersionRolloutResponse{
+				Data: models.PublishTask{TaskID: testTaskIdentifier, Destination: "google-play", Track: "production", Status: "pending", Action: "rollout", ReleaseStatus: "halted", UserFraction: 0.1},
+			},
+		})
+	})
+
+	t.Run("ok - complete", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, services.AppVersionRolloutCompletePostHandler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: testEnv(stagedPublishTasks, func(params bitrise.TaskParams) (*bitrise.TriggerResponse, error) {
+				require.Equal(t, "http://ship.addon.url/apps/test-app-slug/versions/de438ddc-98e5-4226-a5f4-fd2d53474879/android-config?release_status=completed&rollout_only=true&track=production", params.InlineEnvs["CONFIG_JSON_URL"])
+				return &bitrise.TriggerResponse{TaskIdentifier: testTaskIdentifier}, nil
+			}, "Rollout completed on production track"),
+			requestBody:        `{"track":"production"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppVersionRolloutResponse{
+				Data: models.PublishTask{TaskID: testTaskIdentifier, Destination: "google-play", Track: "production", Status: "pending", Action: "rollout", ReleaseStatus: "completed"},
+			},
+		})
+	})
+
+	for _, tc := range []struct {
+		name            string
+		handler         func(*env.AppEnv, http.ResponseWriter, *http.Request) error
+		publishTasks    []models.PublishTask
+		requestBody     string
+		expectedMessage string
+	}{
+		{
+			name:            "when request body is invalid",
+			handler:         services.AppVersionRolloutIncreasePostHandler,
+			publishTasks:    stagedPublishTasks,
+			requestBody:     `invalid JSON`,
+			expectedMessage: "Invalid request body, JSON decode failed",
+		},
+		{
+			name:            "when version hasn't been published to the track",
+			handler:         services.AppVersionRolloutHaltPostHandler,
+			publishTasks:    stagedPublishTasks,
+			requestBody:     `{"track":"alpha"}`,
+			expectedMessage: "Version hasn't been published to the track yet",
+		},
+		{
+			name:    "when a publish task of the track is running",
+			handler: services.AppVersionRolloutHaltPostHandler,
+			publishTasks: append([]models.PublishTask{
+				models.PublishTask{Destination: "google-play", Track: "production", Status: "in_progress"},
+			}, stagedPublishTasks...),
+			requestBody:     `{"track":"production"}`,
+			expectedMessage: "A publish task of the track is still running",
+		},
+		{
+			name:            "when user fraction isn't greater than the current one",
+			handler:         services.AppVersionRolloutIncreasePostHandler,
+			publishTasks:    stagedPublishTasks,
+			requestBody:     `{"track":"production","user_fraction":0.1}`,
+			expectedMessage: "User fraction has to be greater than the current one and less than 1",
+		},
+		{
+			name:            "when rollout of the track isn't staged",
+			handler:         services.AppVersionRolloutCompletePostHandler,
+			publishTasks:    stagedPublishTasks,
+			requestBody:     `{"track":"beta"}`,
+			expectedMessage: "Rollout can only be completed while it's staged",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			performControllerTest(t, httpMethod, url, tc.handler, ControllerTestCase{
+				contextElements: map[ctxpkg.RequestContextKey]interface{}{
+					services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+				},
+				env:                testEnv(tc.publishTasks, nil, ""),
+				requestBody:        tc.requestBody,
+				expectedStatusCode: http.StatusBadRequest,
+				expectedResponse:   httpresponse.StandardErrorRespModel{Message: tc.expectedMessage},
+			})
+		})
+	}
+
+	t.Run("when version isn't an android version", func(t *testing.T) {
+		testAppEnv := testEnv(stagedPublishTasks, nil, "")
+		testAppEnv.AppVersionService = &testAppVersionService{
+			findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+				return &models.AppVersion{Platform: "ios"}, nil
+			},
+		}
+		performControllerTest(t, httpMethod, url, services.AppVersionRolloutHaltPostHandler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env:                testAppEnv,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   httpresponse.StandardErrorRespModel{Message: "Rollouts are only available for Google Play releases"},
+		})
+	})
+
+	t.Run("when app version not found in database", func(t *testing.T) {
+		testAppEnv := testEnv(stagedPublishTasks, nil, "")
+		testAppEnv.AppVersionService = &testAppVersionService{
+			findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+				return nil, gorm.ErrRecordNotFound
+			},
+		}
+		performControllerTest(t, httpMethod, url, services.AppVersionRolloutHaltPostHandler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env:                testAppEnv,
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   httpresponse.StandardErrorRespModel{Message: "Not Found"},
+		})
+	})
+
+	t.Run("when error happens at triggering DEN task", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, services.AppVersionRolloutHaltPostHandler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: testEnv(stagedPublishTasks, func(params bitrise.TaskParams) (*bitrise.TriggerResponse, error) {
+				return nil, errors.New("SOME-BITRISE-API-ERROR")
+			}, ""),
+			requestBody:         `{"track":"production"}`,
+			expectedInternalErr: "SOME-BITRISE-API-ERROR",
+		})
+	})
+}
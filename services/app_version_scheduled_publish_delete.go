@@ -0,0 +1,51 @@
+package services
+
+import (
+	"net/http"
+
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/api-utils/httpresponse"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// AppVersionScheduledPublishDeleteResponse ...
+type AppVersionScheduledPublishDeleteResponse struct {
+	Data *models.ScheduledPublish `json:"data"`
+}
+
+// AppVersionScheduledPublishDeleteHandler cancels a scheduled publish, which is
+// only possible until the worker starts it
+func AppVersionScheduledPublishDeleteHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Request) error {
+	authorizedScheduledPublishID, err := GetAuthorizedScheduledPublishIDFromContext(r.Context())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if env.ScheduledPublishService == nil {
+		return errors.New("No Scheduled Publish Service defined for handler")
+	}
+
+	scheduledPublish, err := env.ScheduledPublishService.Find(&models.ScheduledPublish{Record: models.Record{ID: authorizedScheduledPublishID}})
+	switch {
+	case errors.Cause(err) == gorm.ErrRecordNotFound:
+		return httpresponse.RespondWithNotFoundError(w)
+	case err != nil:
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	if !scheduledPublish.Pending() {
+		return httpresponse.RespondWithUnprocessableEntity(w, []error{
+			errors.Errorf("status: Publish is already %s", scheduledPublish.Status),
+		})
+	}
+
+	scheduledPublish.Status = models.ScheduledPublishStatusCanceled
+	err = env.ScheduledPublishService.Update(scheduledPublish, []string{"Status"})
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	return httpresponse.RespondWithSuccess(w, AppVersionScheduledPublishDeleteResponse{Data: scheduledPublish})
+}
@@ -0,0 +1,126 @@
+package services_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/addons-ship-backend/services"
+	ctxpkg "github.com/bitrise-io/api-utils/context"
+	"github.com/bitrise-io/api-utils/httpresponse"
+	"github.com/c2fo/testify/require"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+func Test_AppVersionScheduledPublishDeleteHandler(t *testing.T) {
+	httpMethod := "DELETE"
+	url := "/apps/{app-slug}/versions/{version-id}/scheduled-publishes/{scheduled-publish-id}"
+	handler := services.AppVersionScheduledPublishDeleteHandler
+
+	testScheduledPublishID := uuid.FromStringOrNil("7c1e4d2a-9b3f-4e58-a6d0-2f8b5c9e1a47")
+
+	behavesAsServiceCravingHandler(t, httpMethod, url, handler, []string{"ScheduledPublishService"}, ControllerTestCase{
+		contextElements: map[ctxpkg.RequestContextKey]interface{}{
+			services.ContextKeyAuthorizedScheduledPublishID: uuid.NewV4(),
+		},
+		env: &env.AppEnv{
+			ScheduledPublishService: &testScheduledPublishService{},
+		},
+	})
+
+	behavesAsContextCravingHandler(t, httpMethod, url, handler, []ctxpkg.RequestContextKey{services.ContextKeyAuthorizedScheduledPublishID}, ControllerTestCase{
+		contextElements: map[ctxpkg.RequestContextKey]interface{}{
+			services.ContextKeyAuthorizedScheduledPublishID: uuid.NewV4(),
+		},
+		env: &env.AppEnv{
+			ScheduledPublishService: &testScheduledPublishService{},
+		},
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedScheduledPublishID: testScheduledPublishID,
+			},
+			env: &env.AppEnv{
+				ScheduledPublishService: &testScheduledPublishService{
+					findFn: func(scheduledPublish *models.ScheduledPublish) (*models.ScheduledPublish, error) {
+						require.Equal(t, testScheduledPublishID, scheduledPublish.ID)
+						scheduledPublish.Status = models.ScheduledPublishStatusScheduled
+						return scheduledPublish, nil
+					},
+					updateFn: func(scheduledPublish *models.ScheduledPublish, whitelist []string) error {
+						require.Equal(t, []string{"Status"}, whitelist)
+						require.Equal(t, "canceled", scheduledPublish.Status)
+						return nil
+					},
+				},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppVersionScheduledPublishDeleteResponse{
+				Data: &models.ScheduledPublish{Record: models.Record{ID: testScheduledPublishID}, Status: "canceled"},
+			},
+		})
+	})
+
+	t.Run("when the publish is already started", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedScheduledPublishID: testScheduledPublishID,
+			},
+			env: &env.AppEnv{
+				ScheduledPublishService: &testScheduledPublishService{
+					findFn: func(scheduledPublish *models.ScheduledPublish) (*models.ScheduledPublish, error) {
+						scheduledPublish.Status = models.ScheduledPublishStatusStarted
+						return scheduledPublish, nil
+					},
+				},
+			},
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedResponse: httpresponse.ValidationErrorRespModel{
+				Message: "Unprocessable Entity",
+				Errors:  []string{"status: Publish is already started"},
+			},
+		})
+	})
+
+	t.Run("when scheduled publish not found", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedScheduledPublishID: testScheduledPublishID,
+			},
+			env: &env.AppEnv{
+				ScheduledPublishService: &testScheduledPublishService{
+					findFn: func(scheduledPublish *models.ScheduledPublish) (*models.ScheduledPublish, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+				},
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   httpresponse.StandardErrorRespModel{Message: "Not Found"},
+		})
+	})
+
+	t.Run("when error happens at updating scheduled publish", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedScheduledPublishID: testScheduledPublishID,
+			},
+			env: &env.AppEnv{
+				ScheduledPublishService: &testScheduledPublishService{
+					findFn: func(scheduledPublish *models.ScheduledPublish) (*models.ScheduledPublish, error) {
+						scheduledPublish.Status = models.ScheduledPublishStatusScheduled
+						return scheduledPublish, nil
+					},
+					updateFn: func(scheduledPublish *models.ScheduledPublish, whitelist []string) error {
+						return errors.New("SOME-SQL-ERROR")
+					},
+				},
+			},
+			expectedInternalErr: "SQL Error: SOME-SQL-ERROR",
+		})
+	})
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"net/http"
+
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/api-utils/httpresponse"
+	"github.com/pkg/errors"
+)
+
+// AppVersionScheduledPublishesGetResponse ...
+type AppVersionScheduledPublishesGetResponse struct {
+	Data []models.ScheduledPublish `json:"data"`
+}
+
+// AppVersionScheduledPublishesGetHandler ...
+func AppVersionScheduledPublishesGetHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Request) error {
+	authorizedAppVersionID, err := GetAuthorizedAppVersionIDFromContext(r.Context())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if env.ScheduledPublishService == nil {
+		return errors.New("No Scheduled Publish Service defined for handler")
+	}
+
+	scheduledPublishes, err := env.ScheduledPublishService.FindAll(&models.AppVersion{Record: models.Record{ID: authorizedAppVersionID}})
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	return httpresponse.RespondWithSuccess(w, AppVersionScheduledPublishesGetResponse{Data: scheduledPublishes})
+}
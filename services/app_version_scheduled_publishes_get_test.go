@@ -0,0 +1,92 @@
+package services_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/addons-ship-backend/services"
+	ctxpkg "github.com/bitrise-io/api-utils/context"
+	"github.com/c2fo/testify/require"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+func Test_AppVersionScheduledPublishesGetHandler(t *testing.T) {
+	httpMethod := "GET"
+	url := "/apps/{app-slug}/versions/{version-id}/scheduled-publishes"
+	handler := services.AppVersionScheduledPublishesGetHandler
+
+	testAppVersionID := uuid.FromStringOrNil("de438ddc-98e5-4226-a5f4-fd2d53474879")
+
+	behavesAsServiceCravingHandler(t, httpMethod, url, handler, []string{"ScheduledPublishService"}, ControllerTestCase{
+		contextElements: map[ctxpkg.RequestContextKey]interface{}{
+			services.ContextKeyAuthorizedAppVersionID: uuid.NewV4(),
+		},
+		env: &env.AppEnv{
+			ScheduledPublishService: &testScheduledPublishService{
+				findAllFn: func(appVersion *models.AppVersion) ([]models.ScheduledPublish, error) {
+					return []models.ScheduledPublish{}, nil
+				},
+			},
+		},
+	})
+
+	behavesAsContextCravingHandler(t, httpMethod, url, handler, []ctxpkg.RequestContextKey{services.ContextKeyAuthorizedAppVersionID}, ControllerTestCase{
+		contextElements: map[ctxpkg.RequestContextKey]interface{}{
+			services.ContextKeyAuthorizedAppVersionID: uuid.NewV4(),
+		},
+		env: &env.AppEnv{
+			ScheduledPublishService: &testScheduledPublishService{
+				findAllFn: func(appVersion *models.AppVersion) ([]models.ScheduledPublish, error) {
+					return []models.ScheduledPublish{}, nil
+				},
+			},
+		},
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		scheduledAt := time.Date(2019, time.December, 2, 9, 0, 0, 0, time.UTC)
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				ScheduledPublishService: &testScheduledPublishService{
+					findAllFn: func(appVersion *models.AppVersion) ([]models.ScheduledPublish, error) {
+						require.Equal(t, testAppVersionID, appVersion.ID)
+						return []models.ScheduledPublish{
+							{ScheduledAt: scheduledAt, Status: "scheduled"},
+							{ScheduledAt: scheduledAt.Add(time.Hour), Status: "canceled"},
+						}, nil
+					},
+				},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppVersionScheduledPublishesGetResponse{
+				Data: []models.ScheduledPublish{
+					{ScheduledAt: scheduledAt, Status: "scheduled"},
+					{ScheduledAt: scheduledAt.Add(time.Hour), Status: "canceled"},
+				},
+			},
+		})
+	})
+
+	t.Run("when error happens at finding scheduled publishes", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: &env.AppEnv{
+				ScheduledPublishService: &testScheduledPublishService{
+					findAllFn: func(appVersion *models.AppVersion) ([]models.ScheduledPublish, error) {
+						return nil, errors.New("SOME-SQL-ERROR")
+					},
+				},
+			},
+			expectedInternalErr: "SQL Error: SOME-SQL-ERROR",
+		})
+	})
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/api-utils/httpresponse"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// TrackHistoryData ...
+type TrackHistoryData struct {
+	Destination   string    `json:"destination"`
+	Track         string    `json:"track"`
+	FromTrack     string    `json:"from_track,omitempty"`
+	Action        string    `json:"action"`
+	Status        string    `json:"status"`
+	ReleaseStatus string    `json:"release_status,omitempty"`
+	UserFraction  float64   `json:"user_fraction,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// AppVersionTrackHistoryGetResponse ...
+type AppVersionTrackHistoryGetResponse struct {
+	Data []TrackHistoryData `json:"data"`
+}
+
+// AppVersionTrackHistoryGetHandler lists the tasks which published, promoted or
+// changed the rollout of the releases of an app version on the tracks of its
+// destinations, in the order they were started
+func AppVersionTrackHistoryGetHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Request) error {
+	authorizedAppVersionID, err := GetAuthorizedAppVersionIDFromContext(r.Context())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if env.AppVersionService == nil {
+		return errors.New("No App Version Service defined for handler")
+	}
+	if env.AppSettingsService == nil {
+		return errors.New("No App Settings Service defined for handler")
+	}
+	if env.PublishTaskService == nil {
+		return errors.New("No Publish Task Service defined for handler")
+	}
+
+	appVersion, err := env.AppVersionService.Find(&models.AppVersion{Record: models.Record{ID: authorizedAppVersionID}})
+	switch {
+	case errors.Cause(err) == gorm.ErrRecordNotFound:
+		return httpresponse.RespondWithNotFoundError(w)
+	case err != nil:
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	appSettings, err := env.AppSettingsService.Find(&models.AppSettings{AppID: appVersion.AppID})
+	switch {
+	case errors.Cause(err) == gorm.ErrRecordNotFound:
+		return httpresponse.RespondWithNotFoundError(w)
+	case err != nil:
+		return errors.Wrap(err, "SQL Error")
+	}
+	androidSettings, err := publishAndroidSettings(appSettings)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	publishTasks, err := env.PublishTaskService.FindAll(appVersion)
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	trackHistory := []TrackHistoryData{}
+	for _, publishTask := range publishTasks {
+		destinationID := publishTaskDestinationID(appVersion, publishTask)
+		if destinationID != googlePlayDestinationID {
+			continue
+		}
+		track := publishTaskTrack(publishTask, androidSettings.Track)
+		if track == "" {
+			continue
+		}
+		action := publishTask.Action
+		if action == "" {
+			action = models.PublishTaskActionPublish
+		}
+		trackHistory = append(trackHistory, TrackHistoryData{
+			Destination:   destinationID,
+			Track:         track,
+			FromTrack:     publishTask.FromTrack,
+			Action:        action,
+			Status:        publishTask.Status,
+			ReleaseStatus: publishTask.ReleaseStatus,
+			UserFraction:  publishTask.UserFraction,
+			CreatedAt:     publishTask.CreatedAt,
+		})
+	}
+
+	return httpresponse.RespondWithSuccess(w, AppVersionTrackHistoryGetResponse{Data: trackHistory})
+}
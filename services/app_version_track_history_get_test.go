@@ -0,0 +1,118 @@
+package services_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/addons-ship-backend/services"
+	ctxpkg "github.com/bitrise-io/api-utils/context"
+	"github.com/bitrise-io/api-utils/httpresponse"
+	"github.com/c2fo/testify/require"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+func Test_AppVersionTrackHistoryGetHandler(t *testing.T) {
+	httpMethod := "GET"
+	url := "/apps/{app-slug}/versions/{version-id}/track-history"
+	handler := services.AppVersionTrackHistoryGetHandler
+
+	testAppVersionID := uuid.FromStringOrNil("de438ddc-98e5-4226-a5f4-fd2d53474879")
+	testTime := time.Date(2019, 11, 8, 10, 0, 0, 0, time.UTC)
+
+	testEnv := func(publishTasks []models.PublishTask) *env.AppEnv {
+		return &env.AppEnv{
+			AppVersionService: &testAppVersionService{
+				findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+					require.Equal(t, testAppVersionID, appVersion.ID)
+					return &models.AppVersion{Record: models.Record{ID: testAppVersionID}, Platform: "android"}, nil
+				},
+			},
+			AppSettingsService: &testAppSettingsService{
+				findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+					return &models.AppSettings{AndroidSettingsData: json.RawMessage(`{"track":"alpha"}`)}, nil
+				},
+			},
+			PublishTaskService: &testPublishTaskService{
+				findAllFn: func(appVersion *models.AppVersion) ([]models.PublishTask, error) {
+					require.Equal(t, testAppVersionID, appVersion.ID)
+					return publishTasks, nil
+				},
+			},
+		}
+	}
+
+	behavesAsServiceCravingHandler(t, httpMethod, url, handler, []string{"AppVersionService", "AppSettingsService", "PublishTaskService"}, ControllerTestCase{
+		contextElements: map[ctxpkg.RequestContextKey]interface{}{
+			services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+		},
+		env: testEnv([]models.PublishTask{}),
+	})
+
+	behavesAsContextCravingHandler(t, httpMethod, url, handler, []ctxpkg.RequestContextKey{services.ContextKeyAuthorizedAppVersionID}, ControllerTestCase{
+		contextElements: map[ctxpkg.RequestContextKey]interface{}{
+			services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+		},
+		env: testEnv([]models.PublishTask{}),
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env: testEnv([]models.PublishTask{
+				models.PublishTask{Record: models.Record{CreatedAt: testTime}, Status: "success"},
+				models.PublishTask{Record: models.Record{CreatedAt: testTime}, Destination: "app-store-connect", Status: "success", Action: "publish"},
+				models.PublishTask{Record: models.Record{CreatedAt: testTime}, Destination: "google-play", Track: "beta", FromTrack: "alpha", Status: "success", Action: "promote", ReleaseStatus: "inProgress", UserFraction: 0.1},
+				models.PublishTask{Record: models.Record{CreatedAt: testTime}, Destination: "google-play", Track: "beta", Status: "pending", Action: "rollout", ReleaseStatus: "completed"},
+			}),
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppVersionTrackHistoryGetResponse{
+				Data: []services.TrackHistoryData{
+					{Destination: "google-play", Track: "alpha", Action: "publish", Status: "success", CreatedAt: testTime},
+					{Destination: "google-play", Track: "beta", FromTrack: "alpha", Action: "promote", Status: "success", ReleaseStatus: "inProgress", UserFraction: 0.1, CreatedAt: testTime},
+					{Destination: "google-play", Track: "beta", Action: "rollout", Status: "pending", ReleaseStatus: "completed", CreatedAt: testTime},
+				},
+			},
+		})
+	})
+
+	t.Run("when app version not found in database", func(t *testing.T) {
+		testAppEnv := testEnv([]models.PublishTask{})
+		testAppEnv.AppVersionService = &testAppVersionService{
+			findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+				return nil, gorm.ErrRecordNotFound
+			},
+		}
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env:                testAppEnv,
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   httpresponse.StandardErrorRespModel{Message: "Not Found"},
+		})
+	})
+
+	t.Run("when error happens at finding publish tasks", func(t *testing.T) {
+		testAppEnv := testEnv([]models.PublishTask{})
+		testAppEnv.PublishTaskService = &testPublishTaskService{
+			findAllFn: func(appVersion *models.AppVersion) ([]models.PublishTask, error) {
+				return nil, errors.New("SOME-SQL-ERROR")
+			},
+		}
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+			},
+			env:                 testAppEnv,
+			expectedInternalErr: "SQL Error: SOME-SQL-ERROR",
+		})
+	})
+}
@@ -2,6 +2,8 @@ package services
 
 import (
 	"net/http"
+	"sort"
+	"strings"
 
 	"github.com/bitrise-io/addons-ship-backend/env"
 	"github.com/bitrise-io/addons-ship-backend/models"
@@ -24,6 +26,9 @@ type AppVersionsGetResponseElement struct {
 	Module               string   `json:"module"`
 	ProductFlavor        string   `json:"product_flavor"`
 	BuildType            string   `json:"build_type"`
+	// Latest is set for the greatest version of each platform, product flavor
+	// and bundle ID, see models.CompareAppVersions
+	Latest bool `json:"latest"`
 }
 
 // AppVersionsGetResponse ...
@@ -45,6 +50,12 @@ func AppVersionsGetHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Reque
 	if platformFilter := r.URL.Query().Get("platform"); platformFilter != "" {
 		filterParams["platform"] = platformFilter
 	}
+	if statusFilter := r.URL.Query().Get("status"); statusFilter != "" {
+		filterParams["status"] = statusFilter
+	}
+	if r.URL.Query().Get("include_archived") != "true" {
+		filterParams["archived_at"] = nil
+	}
 
 	app, err := env.AppService.Find(&models.App{Record: models.Record{ID: authorizedAppID}})
 	if err != nil {
@@ -54,8 +65,16 @@ func AppVersionsGetHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Reque
 	if env.BitriseAPI == nil {
 		return errors.New("No Bitrise API Service defined for handler")
 	}
+	if env.AppVersionService == nil {
+		return errors.New("No App Version Service defined for handler")
+	}
 
-	response, err := newAppVersionsGetResponse(app, env)
+	appVersions, err := env.AppVersionService.FindAll(app, filterParams)
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	response, err := newAppVersionsGetResponse(app, appVersions, env)
 	if err != nil {
 		return errors.WithStack(err)
 	}
@@ -65,7 +84,7 @@ func AppVersionsGetHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Reque
 	})
 }
 
-func newAppVersionsGetResponse(app *models.App, env *env.AppEnv) ([]AppVersionsGetResponseElement, error) {
+func newAppVersionsGetResponse(app *models.App, appVersions []models.AppVersion, env *env.AppEnv) ([]AppVersionsGetResponseElement, error) {
 	elements := []AppVersionsGetResponseElement{}
 
 	appDetails, err := env.BitriseAPI.GetAppDetails(app.BitriseAPIToken, app.AppSlug)
@@ -78,7 +97,7 @@ func newAppVersionsGetResponse(app *models.App, env *env.AppEnv) ([]AppVersionsG
 		ProjectType: appDetails.ProjectType,
 	}
 
-	for _, appVersion := range app.AppVersions {
+	for _, appVersion := range appVersions {
 		artifactInfo, err := appVersion.ArtifactInfo()
 		if err != nil {
 			return nil, err
@@ -99,5 +118,17 @@ func newAppVersionsGetResponse(app *models.App, env *env.AppEnv) ([]AppVersionsG
 			ProductFlavor:        appVersion.ProductFlavor,
 		})
 	}
+
+	sort.SliceStable(elements, func(i, j int) bool {
+		return models.CompareAppVersions(&elements[i].AppVersion, &elements[j].AppVersion) > 0
+	})
+	latestOfGroups := map[string]bool{}
+	for i, element := range elements {
+		group := strings.Join([]string{element.Platform, element.AppVersion.ProductFlavor, element.AppVersion.BundleID}, "/")
+		if !latestOfGroups[group] {
+			latestOfGroups[group] = true
+			elements[i].Latest = true
+		}
+	}
 	return elements, nil
 }
@@ -21,7 +21,7 @@ func Test_AppVersionsGetHandler(t *testing.T) {
 	url := "/apps/{app-slug}/app-versions"
 	handler := services.AppVersionsGetHandler
 
-	behavesAsServiceCravingHandler(t, httpMethod, url, handler, []string{"AppService", "BitriseAPI"}, ControllerTestCase{
+	behavesAsServiceCravingHandler(t, httpMethod, url, handler, []string{"AppService", "BitriseAPI", "AppVersionService"}, ControllerTestCase{
 		contextElements: map[ctxpkg.RequestContextKey]interface{}{
 			services.ContextKeyAuthorizedAppID: uuid.NewV4(),
 		},
@@ -31,7 +31,8 @@ func Test_AppVersionsGetHandler(t *testing.T) {
 					return &models.App{}, nil
 				},
 			},
-			BitriseAPI: &testBitriseAPI{},
+			BitriseAPI:        &testBitriseAPI{},
+			AppVersionService: &testAppVersionService{},
 		},
 	})
 
@@ -57,6 +58,11 @@ func Test_AppVersionsGetHandler(t *testing.T) {
 						return &models.App{}, nil
 					},
 				},
+				AppVersionService: &testAppVersionService{
+					findAllFn: func(app *models.App, filterParams map[string]interface{}) ([]models.AppVersion, error) {
+						return []models.AppVersion{}, nil
+					},
+				},
 				BitriseAPI: &testBitriseAPI{
 					getArtifactsFn: func(string, string, string) ([]bitrise.ArtifactListElementResponseModel, error) {
 						return []bitrise.ArtifactListElementResponseModel{}, nil
@@ -84,16 +90,19 @@ func Test_AppVersionsGetHandler(t *testing.T) {
 			env: &env.AppEnv{
 				AppService: &testAppService{
 					findFn: func(app *models.App) (*models.App, error) {
-						return &models.App{
-							AppVersions: []models.AppVersion{
-								models.AppVersion{
-									Platform:         "ios",
-									ArtifactInfoData: json.RawMessage(`{"version":"v1.0"}`),
-								},
-								models.AppVersion{
-									Platform:         "android",
-									ArtifactInfoData: json.RawMessage(`{"version":"v1.12"}`),
-								},
+						return &models.App{}, nil
+					},
+				},
+				AppVersionService: &testAppVersionService{
+					findAllFn: func(app *models.App, filterParams map[string]interface{}) ([]models.AppVersion, error) {
+						return []models.AppVersion{
+							models.AppVersion{
+								Platform:         "ios",
+								ArtifactInfoData: json.RawMessage(`{"version":"v1.0"}`),
+							},
+							models.AppVersion{
+								Platform:         "android",
+								ArtifactInfoData: json.RawMessage(`{"version":"v1.12"}`),
 							},
 						}, nil
 					},
@@ -119,25 +128,27 @@ func Test_AppVersionsGetHandler(t *testing.T) {
 				Data: []services.AppVersionsGetResponseElement{
 					services.AppVersionsGetResponseElement{
 						AppVersion: models.AppVersion{
-							Platform: "ios",
+							Platform: "android",
 						},
-						Version: "v1.0",
+						Version: "v1.12",
 						AppInfo: services.AppData{
 							Title:       "The Adventures of Stealy",
 							AppIconURL:  pointers.NewStringPtr("https://bit.ly/1LixVJu"),
 							ProjectType: "other",
 						},
+						Latest: true,
 					},
 					services.AppVersionsGetResponseElement{
 						AppVersion: models.AppVersion{
-							Platform: "android",
+							Platform: "ios",
 						},
-						Version: "v1.12",
+						Version: "v1.0",
 						AppInfo: services.AppData{
 							Title:       "The Adventures of Stealy",
 							AppIconURL:  pointers.NewStringPtr("https://bit.ly/1LixVJu"),
 							ProjectType: "other",
 						},
+						Latest: true,
 					},
 				},
 			},
@@ -154,12 +165,16 @@ func Test_AppVersionsGetHandler(t *testing.T) {
 				AppService: &testAppService{
 					findFn: func(app *models.App) (*models.App, error) {
 						require.Equal(t, app.ID.String(), "211afc15-127a-40f9-8cbe-1dadc1f86cdf")
-						return &models.App{
-							AppVersions: []models.AppVersion{
-								models.AppVersion{
-									Platform:         "ios",
-									ArtifactInfoData: json.RawMessage(`{"version":"v1.0"}`),
-								},
+						return &models.App{}, nil
+					},
+				},
+				AppVersionService: &testAppVersionService{
+					findAllFn: func(app *models.App, filterParams map[string]interface{}) ([]models.AppVersion, error) {
+						require.Equal(t, map[string]interface{}{"platform": "ios", "archived_at": nil}, filterParams)
+						return []models.AppVersion{
+							models.AppVersion{
+								Platform:         "ios",
+								ArtifactInfoData: json.RawMessage(`{"version":"v1.0"}`),
 							},
 						}, nil
 					},
@@ -184,8 +199,130 @@ func Test_AppVersionsGetHandler(t *testing.T) {
 							Platform: "ios",
 						},
 						Version: "v1.0",
+						Latest:  true,
+					},
+				},
+			},
+		})
+	})
+
+	t.Run("ok - ordered by version and build number", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppID: uuid.NewV4(),
+			},
+			env: &env.AppEnv{
+				AppService: &testAppService{
+					findFn: func(app *models.App) (*models.App, error) {
+						return &models.App{}, nil
+					},
+				},
+				AppVersionService: &testAppVersionService{
+					findAllFn: func(app *models.App, filterParams map[string]interface{}) ([]models.AppVersion, error) {
+						return []models.AppVersion{
+							models.AppVersion{Platform: "ios", BuildNumber: "9", ArtifactInfoData: json.RawMessage(`{"version":"1.2.0"}`)},
+							models.AppVersion{Platform: "ios", BuildNumber: "10", ArtifactInfoData: json.RawMessage(`{"version":"1.2.0"}`)},
+							models.AppVersion{Platform: "ios", BuildNumber: "11", ArtifactInfoData: json.RawMessage(`{"version":"1.2.0-beta.2"}`)},
+							models.AppVersion{Platform: "ios", BuildNumber: "12", ArtifactInfoData: json.RawMessage(`{"version":"1.10.0"}`), BundleID: "io.bitrise.white-label"},
+						}, nil
+					},
+				},
+				BitriseAPI: &testBitriseAPI{
+					getAppDetailsFn: func(string, string) (*bitrise.AppDetails, error) {
+						return &bitrise.AppDetails{}, nil
+					},
+				},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppVersionsGetResponse{
+				Data: []services.AppVersionsGetResponseElement{
+					services.AppVersionsGetResponseElement{
+						AppVersion: models.AppVersion{Platform: "ios", BuildNumber: "12", BundleID: "io.bitrise.white-label"},
+						Version:    "1.10.0",
+						Latest:     true,
+					},
+					services.AppVersionsGetResponseElement{
+						AppVersion: models.AppVersion{Platform: "ios", BuildNumber: "10"},
+						Version:    "1.2.0",
+						Latest:     true,
+					},
+					services.AppVersionsGetResponseElement{
+						AppVersion: models.AppVersion{Platform: "ios", BuildNumber: "9"},
+						Version:    "1.2.0",
+					},
+					services.AppVersionsGetResponseElement{
+						AppVersion: models.AppVersion{Platform: "ios", BuildNumber: "11"},
+						Version:    "1.2.0-beta.2",
+					},
+				},
+			},
+		})
+	})
+
+	t.Run("ok - with status filter", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url+"?status=published", handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppID: uuid.NewV4(),
+			},
+			env: &env.AppEnv{
+				AppService: &testAppService{
+					findFn: func(app *models.App) (*models.App, error) {
+						return &models.App{}, nil
+					},
+				},
+				AppVersionService: &testAppVersionService{
+					findAllFn: func(app *models.App, filterParams map[string]interface{}) ([]models.AppVersion, error) {
+						require.Equal(t, map[string]interface{}{"status": "published", "archived_at": nil}, filterParams)
+						return []models.AppVersion{
+							models.AppVersion{Platform: "android", Status: "published", ArtifactInfoData: json.RawMessage(`{"version":"1.0"}`)},
+						}, nil
+					},
+				},
+				BitriseAPI: &testBitriseAPI{
+					getAppDetailsFn: func(string, string) (*bitrise.AppDetails, error) {
+						return &bitrise.AppDetails{}, nil
+					},
+				},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppVersionsGetResponse{
+				Data: []services.AppVersionsGetResponseElement{
+					services.AppVersionsGetResponseElement{
+						AppVersion: models.AppVersion{Platform: "android", Status: "published"},
+						Version:    "1.0",
+						Latest:     true,
+					},
+				},
+			},
+		})
+	})
+
+	t.Run("ok - including the archived versions", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url+"?include_archived=true", handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppID: uuid.NewV4(),
+			},
+			env: &env.AppEnv{
+				AppService: &testAppService{
+					findFn: func(app *models.App) (*models.App, error) {
+						return &models.App{}, nil
+					},
+				},
+				AppVersionService: &testAppVersionService{
+					findAllFn: func(app *models.App, filterParams map[string]interface{}) ([]models.AppVersion, error) {
+						require.Equal(t, map[string]interface{}{}, filterParams)
+						return []models.AppVersion{}, nil
 					},
 				},
+				BitriseAPI: &testBitriseAPI{
+					getAppDetailsFn: func(string, string) (*bitrise.AppDetails, error) {
+						return &bitrise.AppDetails{}, nil
+					},
+				},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.AppVersionsGetResponse{
+				Data: []services.AppVersionsGetResponseElement{},
 			},
 		})
 	})
@@ -206,6 +343,28 @@ func Test_AppVersionsGetHandler(t *testing.T) {
 		})
 	})
 
+	t.Run("when error happens at finding the versions of the app", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppID: uuid.NewV4(),
+			},
+			env: &env.AppEnv{
+				AppService: &testAppService{
+					findFn: func(app *models.App) (*models.App, error) {
+						return &models.App{}, nil
+					},
+				},
+				AppVersionService: &testAppVersionService{
+					findAllFn: func(app *models.App, filterParams map[string]interface{}) ([]models.AppVersion, error) {
+						return nil, errors.New("SOME-SQL-ERROR")
+					},
+				},
+				BitriseAPI: &testBitriseAPI{},
+			},
+			expectedInternalErr: "SQL Error: SOME-SQL-ERROR",
+		})
+	})
+
 	t.Run("when invalid JSON is stored in database for artifact info", func(t *testing.T) {
 		urlWithFilter := url + "?platform=ios"
 		performControllerTest(t, httpMethod, urlWithFilter, handler, ControllerTestCase{
@@ -216,12 +375,15 @@ func Test_AppVersionsGetHandler(t *testing.T) {
 				AppService: &testAppService{
 					findFn: func(app *models.App) (*models.App, error) {
 						require.Equal(t, app.ID.String(), "211afc15-127a-40f9-8cbe-1dadc1f86cdf")
-						return &models.App{
-							AppVersions: []models.AppVersion{
-								models.AppVersion{
-									ArtifactInfoData: json.RawMessage(`invalid JSON`),
-									Platform:         "ios",
-								},
+						return &models.App{}, nil
+					},
+				},
+				AppVersionService: &testAppVersionService{
+					findAllFn: func(app *models.App, filterParams map[string]interface{}) ([]models.AppVersion, error) {
+						return []models.AppVersion{
+							models.AppVersion{
+								ArtifactInfoData: json.RawMessage(`invalid JSON`),
+								Platform:         "ios",
 							},
 						}, nil
 					},
@@ -252,12 +414,15 @@ func Test_AppVersionsGetHandler(t *testing.T) {
 				AppService: &testAppService{
 					findFn: func(app *models.App) (*models.App, error) {
 						require.Equal(t, app.ID.String(), "211afc15-127a-40f9-8cbe-1dadc1f86cdf")
-						return &models.App{
-							AppVersions: []models.AppVersion{
-								models.AppVersion{
-									ArtifactInfoData: json.RawMessage(`{"version":"v1.0"}`),
-									Platform:         "ios",
-								},
+						return &models.App{}, nil
+					},
+				},
+				AppVersionService: &testAppVersionService{
+					findAllFn: func(app *models.App, filterParams map[string]interface{}) ([]models.AppVersion, error) {
+						return []models.AppVersion{
+							models.AppVersion{
+								ArtifactInfoData: json.RawMessage(`{"version":"v1.0"}`),
+								Platform:         "ios",
 							},
 						}, nil
 					},
@@ -3,11 +3,13 @@ package services_test
 import "github.com/bitrise-io/addons-ship-backend/models"
 
 type testAppVersionService struct {
-	createFn  func(*models.AppVersion) (*models.AppVersion, []error, error)
-	findFn    func(*models.AppVersion) (*models.AppVersion, error)
-	findAllFn func(*models.App, map[string]interface{}) ([]models.AppVersion, error)
-	updateFn  func(*models.AppVersion, []string) (validationErrors []error, dbErr error)
-	latestFn  func(*models.AppVersion) (*models.AppVersion, error)
+	createFn          func(*models.AppVersion) (*models.AppVersion, []error, error)
+	findFn            func(*models.AppVersion) (*models.AppVersion, error)
+	findAllFn         func(*models.App, map[string]interface{}) ([]models.AppVersion, error)
+	updateFn          func(*models.AppVersion, []string) (validationErrors []error, dbErr error)
+	latestFn          func(*models.AppVersion) (*models.AppVersion, error)
+	latestPublishedFn func(*models.AppVersion) (*models.AppVersion, error)
+	deleteFn          func(*models.AppVersion) error
 }
 
 func (a *testAppVersionService) Create(appVersion *models.AppVersion) (*models.AppVersion, []error, error) {
@@ -42,3 +44,17 @@ func (a *testAppVersionService) Latest(appVersion *models.AppVersion) (*models.A
 	}
 	panic("You have to override Latest function in tests")
 }
+
+func (a *testAppVersionService) LatestPublished(appVersion *models.AppVersion) (*models.AppVersion, error) {
+	if a.latestPublishedFn != nil {
+		return a.latestPublishedFn(appVersion)
+	}
+	panic("You have to override LatestPublished function in tests")
+}
+
+func (a *testAppVersionService) Delete(appVersion *models.AppVersion) error {
+	if a.deleteFn != nil {
+		return a.deleteFn(appVersion)
+	}
+	panic("You have to override Delete function in tests")
+}
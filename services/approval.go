@@ -0,0 +1,202 @@
+package services
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/thoas/go-funk"
+)
+
+// missingApprovals returns a warning for each approval requirement of the app
+// which the valid approvals of the version don't satisfy for the targets
+func missingApprovals(env *env.AppEnv, appVersion *models.AppVersion, appSettings *models.AppSettings, targets []publishTarget) ([]string, error) {
+	approvalRequirements, err := appSettings.ApprovalRequirements()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to parse approval requirements")
+	}
+	androidSettings, err := publishAndroidSettings(appSettings)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	requirements := []models.ApprovalRequirement{}
+	for _, approvalRequirement := range approvalRequirements {
+		for _, target := range targets {
+			track := target.track
+			if track == "" && target.destination.ID() == googlePlayDestinationID {
+				track = androidSettings.Track
+			}
+			if approvalRequirement.AppliesTo(target.destination.ID(), track) {
+				requirements = append(requirements, approvalRequirement)
+				break
+			}
+		}
+	}
+	if len(requirements) == 0 {
+		return nil, nil
+	}
+
+	if env.ApprovalService == nil {
+		return nil, errors.New("No Approval Service defined for handler")
+	}
+	approvals, err := env.ApprovalService.FindAll(appVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "SQL Error")
+	}
+
+	warnings := []string{}
+	for _, requirement := range requirements {
+		approvers := map[string]bool{}
+		for _, approval := range approvals {
+			if approval.Valid() && requirement.CountsApprover(approval.Approver) {
+				approvers[approval.Approver] = true
+			}
+		}
+		if len(approvers) >= requirement.Approvals {
+			continue
+		}
+		destination := ""
+		if requirement.Destination != "" {
+			destination = " to " + requirement.Destination
+		}
+		if requirement.Track != "" {
+			destination += fmt.Sprintf(" (%s track)", requirement.Track)
+		}
+		warnings = append(warnings, fmt.Sprintf("Publishing%s requires %d approvals, the version has %d", destination, requirement.Approvals, len(approvers)))
+	}
+	return warnings, nil
+}
+
+// validateApprovalRequirements checks the destinations and the tracks of the
+// requirements
+func validateApprovalRequirements(approvalRequirements []models.ApprovalRequirement) []error {
+	verrs := []error{}
+	for _, requirement := range approvalRequirements {
+		if requirement.Destination == "" {
+			continue
+		}
+		destination := publishDestinationByID(requirement.Destination)
+		if destination == nil {
+			verrs = append(verrs, errors.Errorf("approval_requirements.destination: Unknown publish destination: %s", requirement.Destination))
+			continue
+		}
+		if requirement.Track != "" && !funk.ContainsString(destination.Tracks(), requirement.Track) {
+			verrs = append(verrs, errors.Errorf("approval_requirements.track: Unknown track of %s: %s", requirement.Destination, requirement.Track))
+		}
+	}
+	return verrs
+}
+
+// invalidateApprovals invalidates the approvals of an app version, as they were
+// given to the store info before it got edited
+func invalidateApprovals(env *env.AppEnv, appVersion *models.AppVersion) error {
+	if env.ApprovalService == nil {
+		return errors.New("No Approval Service defined for handler")
+	}
+	invalidated, err := env.ApprovalService.InvalidateAll(appVersion)
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+	if invalidated == 0 {
+		return nil
+	}
+	if env.AppVersionEventService == nil {
+		return errors.New("No App Version Event Service defined for handler")
+	}
+	_, err = env.AppVersionEventService.Create(&models.AppVersionEvent{
+		Text:         "Approvals were invalidated by an edit of the store info",
+		AppVersionID: appVersion.ID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+	return nil
+}
+
+// sameApprovalRequirements tells whether the requirements are the same, in the
+// same order
+func sameApprovalRequirements(requirements, otherRequirements []models.ApprovalRequirement) bool {
+	if len(requirements) == 0 && len(otherRequirements) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(requirements, otherRequirements)
+}
+
+// recordApprovalRequirementsChange records the change of the approval
+// requirements on each version of the app which hasn't been published yet,
+// and invalidates their approvals, as they were given to the previous
+// requirements
+func recordApprovalRequirementsChange(env *env.AppEnv, appID uuid.UUID, approvalRequirements []models.ApprovalRequirement) error {
+	if env.AppVersionService == nil {
+		return errors.New("No App Version Service defined for handler")
+	}
+	if env.ApprovalService == nil {
+		return errors.New("No Approval Service defined for handler")
+	}
+	if env.AppVersionEventService == nil {
+		return errors.New("No App Version Event Service defined for handler")
+	}
+	appVersions, err := env.AppVersionService.FindAll(&models.App{Record: models.Record{ID: appID}}, map[string]interface{}{})
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+	for _, appVersion := range appVersions {
+		switch appVersion.Status {
+		case models.AppVersionStatusPublishing, models.AppVersionStatusPublished, models.AppVersionStatusSuperseded:
+			continue
+		}
+		appVersion := appVersion
+		invalidated, err := env.ApprovalService.InvalidateAll(&appVersion)
+		if err != nil {
+			return errors.Wrap(err, "SQL Error")
+		}
+		text := "Approval requirements were changed to " + approvalRequirementsText(approvalRequirements)
+		if invalidated > 0 {
+			text += ", the approvals were invalidated"
+		}
+		_, err = env.AppVersionEventService.Create(&models.AppVersionEvent{
+			Text:         text,
+			AppVersionID: appVersion.ID,
+		})
+		if err != nil {
+			return errors.Wrap(err, "SQL Error")
+		}
+	}
+	return nil
+}
+
+// approvalRequirementsText describes the requirements, e.g. "2 approvals to
+// google-play (production track)"
+func approvalRequirementsText(approvalRequirements []models.ApprovalRequirement) string {
+	if len(approvalRequirements) == 0 {
+		return "none"
+	}
+	texts := []string{}
+	for _, requirement := range approvalRequirements {
+		text := fmt.Sprintf("%d approvals", requirement.Approvals)
+		if requirement.Destination != "" {
+			text += " to " + requirement.Destination
+		}
+		if requirement.Track != "" {
+			text += fmt.Sprintf(" (%s track)", requirement.Track)
+		}
+		if len(requirement.Approvers) > 0 {
+			text += " from " + strings.Join(requirement.Approvers, ", ")
+		}
+		texts = append(texts, text)
+	}
+	return strings.Join(texts, "; ")
+}
+
+// approvalEventText describes an approval, or its revocation, with the comment
+// of the approver
+func approvalEventText(text, comment string) string {
+	if comment == "" {
+		return text
+	}
+	return fmt.Sprintf("%s: %s", text, comment)
+}
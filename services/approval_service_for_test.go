@@ -0,0 +1,48 @@
+package services_test
+
+import (
+	"github.com/bitrise-io/addons-ship-backend/models"
+)
+
+type testApprovalService struct {
+	createFn        func(*models.Approval) (*models.Approval, error)
+	findFn          func(*models.Approval) (*models.Approval, error)
+	findAllFn       func(appVersion *models.AppVersion) ([]models.Approval, error)
+	updateFn        func(*models.Approval, []string) error
+	invalidateAllFn func(appVersion *models.AppVersion) (int, error)
+}
+
+func (a *testApprovalService) Create(approval *models.Approval) (*models.Approval, error) {
+	if a.createFn != nil {
+		return a.createFn(approval)
+	}
+	panic("You have to override ApprovalService.Create function in tests")
+}
+
+func (a *testApprovalService) Find(approval *models.Approval) (*models.Approval, error) {
+	if a.findFn != nil {
+		return a.findFn(approval)
+	}
+	panic("You have to override ApprovalService.Find function in tests")
+}
+
+func (a *testApprovalService) FindAll(appVersion *models.AppVersion) ([]models.Approval, error) {
+	if a.findAllFn != nil {
+		return a.findAllFn(appVersion)
+	}
+	panic("You have to override ApprovalService.FindAll function in tests")
+}
+
+func (a *testApprovalService) Update(approval *models.Approval, whitelist []string) error {
+	if a.updateFn != nil {
+		return a.updateFn(approval, whitelist)
+	}
+	panic("You have to override ApprovalService.Update function in tests")
+}
+
+func (a *testApprovalService) InvalidateAll(appVersion *models.AppVersion) (int, error) {
+	if a.invalidateAllFn != nil {
+		return a.invalidateAllFn(appVersion)
+	}
+	panic("You have to override ApprovalService.InvalidateAll function in tests")
+}
@@ -297,6 +297,51 @@ func AuthorizeForAppContactEmailConfirmationHandlerFunc(env *env.AppEnv, h http.
 	})
 }
 
+// AuthorizeForApprovalHandlerFunc authorizes the app contact, who got the
+// approval request, by the token of the approval. The request body is kept for
+// the handler, as it contains the comment of the approver too.
+func AuthorizeForApprovalHandlerFunc(env *env.AppEnv, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payloadBytes, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			httpresponse.RespondWithInternalServerError(w, errors.Wrap(err, "Failed to get request payload"))
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(payloadBytes))
+
+		requestBody := struct {
+			ApprovalToken string `json:"approval_token"`
+		}{}
+		if err := json.Unmarshal(payloadBytes, &requestBody); err != nil {
+			httpresponse.RespondWithBadRequestErrorNoErr(w, "Invalid request body, JSON decode failed")
+			return
+		}
+		if requestBody.ApprovalToken == "" {
+			httpresponse.RespondWithNotFoundErrorNoErr(w)
+			return
+		}
+
+		if env.ApprovalService == nil {
+			httpresponse.RespondWithInternalServerError(w, errors.New("No Approval Service provided"))
+			return
+		}
+
+		approval, err := env.ApprovalService.Find(&models.Approval{Token: &requestBody.ApprovalToken})
+		switch {
+		case errors.Cause(err) == gorm.ErrRecordNotFound:
+			httpresponse.RespondWithNotFoundErrorNoErr(w)
+			return
+		case err != nil:
+			httpresponse.RespondWithInternalServerError(w, errors.WithStack(err))
+			return
+		}
+
+		// Access granted
+		ctx := ContextWithAuthorizedApprovalID(r.Context(), approval.ID)
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // AuthorizeForAppContactAccessHandlerFunc ...
 func AuthorizeForAppContactAccessHandlerFunc(env *env.AppEnv, h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -338,6 +383,88 @@ func AuthorizeForAppContactAccessHandlerFunc(env *env.AppEnv, h http.Handler) ht
 	})
 }
 
+// AuthorizeForReleaseAccessHandlerFunc ...
+func AuthorizeForReleaseAccessHandlerFunc(env *env.AppEnv, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if env.RequestParams == nil {
+			httpresponse.RespondWithInternalServerError(w, errors.New("No Request Params provided"))
+			return
+		}
+
+		appID, err := GetAuthorizedAppIDFromContext(r.Context())
+		if err != nil {
+			httpresponse.RespondWithInternalServerError(w, err)
+			return
+		}
+
+		releaseID, err := getUUIDFromRequest(env, r, "release-id")
+		if err != nil {
+			httpresponse.RespondWithBadRequestErrorNoErr(w, err.Error())
+			return
+		}
+
+		if env.ReleaseService == nil {
+			httpresponse.RespondWithInternalServerError(w, errors.New("No Release Service provided"))
+			return
+		}
+
+		release, err := env.ReleaseService.Find(&models.Release{Record: models.Record{ID: releaseID}, AppID: appID})
+		switch {
+		case errors.Cause(err) == gorm.ErrRecordNotFound:
+			httpresponse.RespondWithNotFoundErrorNoErr(w)
+			return
+		case err != nil:
+			httpresponse.RespondWithInternalServerError(w, errors.WithStack(err))
+			return
+		}
+
+		// Access granted
+		ctx := ContextWithAuthorizedReleaseID(r.Context(), release.ID)
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// AuthorizeForAppVersionScheduledPublishAccessHandlerFunc ...
+func AuthorizeForAppVersionScheduledPublishAccessHandlerFunc(env *env.AppEnv, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if env.RequestParams == nil {
+			httpresponse.RespondWithInternalServerError(w, errors.New("No Request Params provided"))
+			return
+		}
+
+		appVersionID, err := GetAuthorizedAppVersionIDFromContext(r.Context())
+		if err != nil {
+			httpresponse.RespondWithInternalServerError(w, err)
+			return
+		}
+
+		scheduledPublishID, err := getUUIDFromRequest(env, r, "scheduled-publish-id")
+		if err != nil {
+			httpresponse.RespondWithBadRequestErrorNoErr(w, err.Error())
+			return
+		}
+
+		if env.ScheduledPublishService == nil {
+			httpresponse.RespondWithInternalServerError(w, errors.New("No Scheduled Publish Service provided"))
+			return
+		}
+
+		scheduledPublish, err := env.ScheduledPublishService.Find(&models.ScheduledPublish{Record: models.Record{ID: scheduledPublishID}, AppVersionID: appVersionID})
+		switch {
+		case errors.Cause(err) == gorm.ErrRecordNotFound:
+			httpresponse.RespondWithNotFoundErrorNoErr(w)
+			return
+		case err != nil:
+			httpresponse.RespondWithInternalServerError(w, errors.WithStack(err))
+			return
+		}
+
+		// Access granted
+		ctx := ContextWithAuthorizedScheduledPublishID(r.Context(), scheduledPublish.ID)
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // AuthorizeBuildWebhookForAppAccessFunc ...
 func AuthorizeBuildWebhookForAppAccessFunc(env *env.AppEnv, h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -1200,6 +1200,109 @@ func Test_AuthorizeForAppContactEmailConfirmationHandlerFunc(t *testing.T) {
 	})
 }
 
+func Test_AuthorizeForApprovalHandlerFunc(t *testing.T) {
+	authHandler := &handlers.TestAuthHandler{
+		ContextElementList: map[string]ctxpkg.RequestContextKey{
+			"authorizedApprovalID": services.ContextKeyAuthorizedApprovalID,
+		},
+	}
+	httpMethod := "POST"
+	url := "/approve"
+
+	t.Run("ok", func(t *testing.T) {
+		handler := services.AuthorizeForApprovalHandlerFunc(&env.AppEnv{
+			ApprovalService: &testApprovalService{
+				findFn: func(approval *models.Approval) (*models.Approval, error) {
+					require.NotNil(t, approval.Token)
+					require.Equal(t, "5om3-r4nd0m-5tr1ng", *approval.Token)
+					approval.ID = uuid.FromStringOrNil("8a230385-0113-4cf3-a9c6-469a313e587a")
+					return approval, nil
+				},
+			},
+		}, authHandler)
+		performAuthorizationTest(t, httpMethod, url, handler, AuthorizationTestCase{
+			requestPayload:     map[string]string{"approval_token": "5om3-r4nd0m-5tr1ng", "comment": "Tested on device"},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"authorizedApprovalID": "8a230385-0113-4cf3-a9c6-469a313e587a",
+			},
+		})
+	})
+
+	t.Run("when request payload is invalid", func(t *testing.T) {
+		handler := services.AuthorizeForApprovalHandlerFunc(&env.AppEnv{
+			ApprovalService: &testApprovalService{},
+		}, authHandler)
+		performAuthorizationTest(t, httpMethod, url, handler, AuthorizationTestCase{
+			requestPayload:     "invalid-json",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"message": "Invalid request body, JSON decode failed",
+			},
+		})
+	})
+
+	t.Run("when approval token is empty", func(t *testing.T) {
+		handler := services.AuthorizeForApprovalHandlerFunc(&env.AppEnv{
+			ApprovalService: &testApprovalService{},
+		}, authHandler)
+		performAuthorizationTest(t, httpMethod, url, handler, AuthorizationTestCase{
+			requestPayload:     map[string]string{"comment": "Tested on device"},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: map[string]interface{}{
+				"message": "Not Found",
+			},
+		})
+	})
+
+	t.Run("when no approval service is defined", func(t *testing.T) {
+		handler := services.AuthorizeForApprovalHandlerFunc(&env.AppEnv{
+			ApprovalService: nil,
+		}, authHandler)
+		performAuthorizationTest(t, httpMethod, url, handler, AuthorizationTestCase{
+			requestPayload:     map[string]string{"approval_token": "5om3-r4nd0m-5tr1ng"},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: map[string]interface{}{
+				"message": "Internal Server Error",
+			},
+		})
+	})
+
+	t.Run("when no approval found by token", func(t *testing.T) {
+		handler := services.AuthorizeForApprovalHandlerFunc(&env.AppEnv{
+			ApprovalService: &testApprovalService{
+				findFn: func(approval *models.Approval) (*models.Approval, error) {
+					return nil, gorm.ErrRecordNotFound
+				},
+			},
+		}, authHandler)
+		performAuthorizationTest(t, httpMethod, url, handler, AuthorizationTestCase{
+			requestPayload:     map[string]string{"approval_token": "5om3-r4nd0m-5tr1ng"},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: map[string]interface{}{
+				"message": "Not Found",
+			},
+		})
+	})
+
+	t.Run("when error happens at finding approval", func(t *testing.T) {
+		handler := services.AuthorizeForApprovalHandlerFunc(&env.AppEnv{
+			ApprovalService: &testApprovalService{
+				findFn: func(approval *models.Approval) (*models.Approval, error) {
+					return nil, errors.New("SOME-SQL-ERROR")
+				},
+			},
+		}, authHandler)
+		performAuthorizationTest(t, httpMethod, url, handler, AuthorizationTestCase{
+			requestPayload:     map[string]string{"approval_token": "5om3-r4nd0m-5tr1ng"},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: map[string]interface{}{
+				"message": "Internal Server Error",
+			},
+		})
+	})
+}
+
 func Test_AuthorizeForAppContactAccessHandlerFunc(t *testing.T) {
 	authHandler := &handlers.TestAuthHandler{
 		ContextElementList: map[string]ctxpkg.RequestContextKey{
@@ -1383,6 +1486,372 @@ func Test_AuthorizeForAppContactAccessHandlerFunc(t *testing.T) {
 	})
 }
 
+func Test_AuthorizeForReleaseAccessHandlerFunc(t *testing.T) {
+	authHandler := &handlers.TestAuthHandler{
+		ContextElementList: map[string]ctxpkg.RequestContextKey{
+			"authorizedAppID":     services.ContextKeyAuthorizedAppID,
+			"authorizedReleaseID": services.ContextKeyAuthorizedReleaseID,
+		},
+	}
+	httpMethod := "GET"
+	url := "/apps/test_app_slug/releases/release_uuid"
+
+	testAppID := "211afc15-127a-40f9-8cbe-1dadc1f86cdf"
+	testReleaseID := "123afc15-127a-40f9-8cbe-1dadc1f86cdf"
+	validRequestParams := &providers.RequestParamsMock{
+		Params: map[string]string{
+			"release-id": testReleaseID,
+		},
+	}
+
+	successfulTestRelease := &testReleaseService{
+		findFn: func(release *models.Release) (*models.Release, error) {
+			require.Equal(t, testAppID, release.AppID.String())
+			require.Equal(t, testReleaseID, release.ID.String())
+
+			return &models.Release{
+				Record: models.Record{ID: uuid.FromStringOrNil(testReleaseID)},
+			}, nil
+		},
+	}
+
+	testRequestHeaders := map[string]string{
+		"Authorization": "token test-auth-token",
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		handler := services.AuthorizeForReleaseAccessHandlerFunc(&env.AppEnv{
+			RequestParams:  validRequestParams,
+			ReleaseService: successfulTestRelease,
+		}, authHandler)
+		performAuthorizationTest(t, httpMethod, url, handler, AuthorizationTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppID: uuid.FromStringOrNil(testAppID),
+			},
+			requestHeaders:     testRequestHeaders,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"authorizedAppID":     testAppID,
+				"authorizedReleaseID": testReleaseID,
+			},
+		})
+	})
+
+	t.Run("when no App ID found in context", func(t *testing.T) {
+		handler := services.AuthorizeForReleaseAccessHandlerFunc(&env.AppEnv{
+			RequestParams: &providers.RequestParamsMock{
+				Params: map[string]string{},
+			},
+			ReleaseService: successfulTestRelease,
+		}, authHandler)
+		performAuthorizationTest(t, httpMethod, url, handler, AuthorizationTestCase{
+			contextElements:    map[ctxpkg.RequestContextKey]interface{}{},
+			requestHeaders:     testRequestHeaders,
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: map[string]interface{}{
+				"message": "Internal Server Error",
+			},
+		})
+	})
+
+	t.Run("when no Request Params object is provided", func(t *testing.T) {
+		handler := services.AuthorizeForReleaseAccessHandlerFunc(&env.AppEnv{
+			ReleaseService: successfulTestRelease,
+		}, authHandler)
+		performAuthorizationTest(t, httpMethod, url, handler, AuthorizationTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppID: uuid.FromStringOrNil(testAppID),
+			},
+			requestHeaders:     testRequestHeaders,
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: map[string]interface{}{
+				"message": "Internal Server Error",
+			},
+		})
+	})
+
+	t.Run("when no release id found in url params", func(t *testing.T) {
+		handler := services.AuthorizeForReleaseAccessHandlerFunc(&env.AppEnv{
+			RequestParams: &providers.RequestParamsMock{
+				Params: map[string]string{},
+			},
+			ReleaseService: successfulTestRelease,
+		}, authHandler)
+		performAuthorizationTest(t, httpMethod, url, handler, AuthorizationTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppID: uuid.FromStringOrNil(testAppID),
+			},
+			requestHeaders:     testRequestHeaders,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"message": "Failed to fetch URL param release-id",
+			},
+		})
+	})
+
+	t.Run("when no valid release id found in url params", func(t *testing.T) {
+		handler := services.AuthorizeForReleaseAccessHandlerFunc(&env.AppEnv{
+			RequestParams: &providers.RequestParamsMock{
+				Params: map[string]string{
+					"release-id": "invalid-uuid",
+				},
+			},
+			ReleaseService: successfulTestRelease,
+		}, authHandler)
+		performAuthorizationTest(t, httpMethod, url, handler, AuthorizationTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppID: uuid.FromStringOrNil(testAppID),
+			},
+			requestHeaders:     testRequestHeaders,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"message": "Invalid UUID format for release-id",
+			},
+		})
+	})
+
+	t.Run("when no release service is provided in app env", func(t *testing.T) {
+		handler := services.AuthorizeForReleaseAccessHandlerFunc(&env.AppEnv{
+			RequestParams: validRequestParams,
+		}, authHandler)
+		performAuthorizationTest(t, httpMethod, url, handler, AuthorizationTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppID: uuid.FromStringOrNil(testAppID),
+			},
+			requestHeaders:     testRequestHeaders,
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: map[string]interface{}{
+				"message": "Internal Server Error",
+			},
+		})
+	})
+
+	t.Run("when release not found in database", func(t *testing.T) {
+		handler := services.AuthorizeForReleaseAccessHandlerFunc(&env.AppEnv{
+			RequestParams: validRequestParams,
+			ReleaseService: &testReleaseService{
+				findFn: func(release *models.Release) (*models.Release, error) {
+					return nil, gorm.ErrRecordNotFound
+				},
+			},
+		}, authHandler)
+		performAuthorizationTest(t, httpMethod, url, handler, AuthorizationTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppID: uuid.FromStringOrNil(testAppID),
+			},
+			requestHeaders:     testRequestHeaders,
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: map[string]interface{}{
+				"message": "Not Found",
+			},
+		})
+	})
+
+	t.Run("when unexpected error happens at database query", func(t *testing.T) {
+		handler := services.AuthorizeForReleaseAccessHandlerFunc(&env.AppEnv{
+			RequestParams: validRequestParams,
+			ReleaseService: &testReleaseService{
+				findFn: func(release *models.Release) (*models.Release, error) {
+					return nil, errors.New("SOME-SQL-ERROR")
+				},
+			},
+		}, authHandler)
+		performAuthorizationTest(t, httpMethod, url, handler, AuthorizationTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppID: uuid.FromStringOrNil(testAppID),
+			},
+			requestHeaders:     testRequestHeaders,
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: map[string]interface{}{
+				"message": "Internal Server Error",
+			},
+		})
+	})
+}
+
+func Test_AuthorizeForAppVersionScheduledPublishAccessHandlerFunc(t *testing.T) {
+	authHandler := &handlers.TestAuthHandler{
+		ContextElementList: map[string]ctxpkg.RequestContextKey{
+			"authorizedAppVersionID":       services.ContextKeyAuthorizedAppVersionID,
+			"authorizedScheduledPublishID": services.ContextKeyAuthorizedScheduledPublishID,
+		},
+	}
+	httpMethod := "GET"
+	url := "/apps/test_app_slug/versions/version_uuid/scheduled-publishes/scheduled_publish_uuid"
+
+	testAppVersionID := "211afc15-127a-40f9-8cbe-1dadc1f86cdf"
+	testScheduledPublishID := "123afc15-127a-40f9-8cbe-1dadc1f86cdf"
+	validRequestParams := &providers.RequestParamsMock{
+		Params: map[string]string{
+			"scheduled-publish-id": testScheduledPublishID,
+		},
+	}
+
+	successfulTestScheduledPublish := &testScheduledPublishService{
+		findFn: func(scheduledPublish *models.ScheduledPublish) (*models.ScheduledPublish, error) {
+			require.Equal(t, testAppVersionID, scheduledPublish.AppVersionID.String())
+			require.Equal(t, testScheduledPublishID, scheduledPublish.ID.String())
+
+			return &models.ScheduledPublish{
+				Record: models.Record{ID: uuid.FromStringOrNil(testScheduledPublishID)},
+			}, nil
+		},
+	}
+
+	testRequestHeaders := map[string]string{
+		"Authorization": "token test-auth-token",
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		handler := services.AuthorizeForAppVersionScheduledPublishAccessHandlerFunc(&env.AppEnv{
+			RequestParams:           validRequestParams,
+			ScheduledPublishService: successfulTestScheduledPublish,
+		}, authHandler)
+		performAuthorizationTest(t, httpMethod, url, handler, AuthorizationTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: uuid.FromStringOrNil(testAppVersionID),
+			},
+			requestHeaders:     testRequestHeaders,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: map[string]interface{}{
+				"authorizedAppVersionID":       testAppVersionID,
+				"authorizedScheduledPublishID": testScheduledPublishID,
+			},
+		})
+	})
+
+	t.Run("when no App Version ID found in context", func(t *testing.T) {
+		handler := services.AuthorizeForAppVersionScheduledPublishAccessHandlerFunc(&env.AppEnv{
+			RequestParams: &providers.RequestParamsMock{
+				Params: map[string]string{},
+			},
+			ScheduledPublishService: successfulTestScheduledPublish,
+		}, authHandler)
+		performAuthorizationTest(t, httpMethod, url, handler, AuthorizationTestCase{
+			contextElements:    map[ctxpkg.RequestContextKey]interface{}{},
+			requestHeaders:     testRequestHeaders,
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: map[string]interface{}{
+				"message": "Internal Server Error",
+			},
+		})
+	})
+
+	t.Run("when no Request Params object is provided", func(t *testing.T) {
+		handler := services.AuthorizeForAppVersionScheduledPublishAccessHandlerFunc(&env.AppEnv{
+			ScheduledPublishService: successfulTestScheduledPublish,
+		}, authHandler)
+		performAuthorizationTest(t, httpMethod, url, handler, AuthorizationTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: uuid.FromStringOrNil(testAppVersionID),
+			},
+			requestHeaders:     testRequestHeaders,
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: map[string]interface{}{
+				"message": "Internal Server Error",
+			},
+		})
+	})
+
+	t.Run("when no scheduled publish id found in url params", func(t *testing.T) {
+		handler := services.AuthorizeForAppVersionScheduledPublishAccessHandlerFunc(&env.AppEnv{
+			RequestParams: &providers.RequestParamsMock{
+				Params: map[string]string{},
+			},
+			ScheduledPublishService: successfulTestScheduledPublish,
+		}, authHandler)
+		performAuthorizationTest(t, httpMethod, url, handler, AuthorizationTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: uuid.FromStringOrNil(testAppVersionID),
+			},
+			requestHeaders:     testRequestHeaders,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"message": "Failed to fetch URL param scheduled-publish-id",
+			},
+		})
+	})
+
+	t.Run("when no valid scheduled publish id found in url params", func(t *testing.T) {
+		handler := services.AuthorizeForAppVersionScheduledPublishAccessHandlerFunc(&env.AppEnv{
+			RequestParams: &providers.RequestParamsMock{
+				Params: map[string]string{
+					"scheduled-publish-id": "invalid-uuid",
+				},
+			},
+			ScheduledPublishService: successfulTestScheduledPublish,
+		}, authHandler)
+		performAuthorizationTest(t, httpMethod, url, handler, AuthorizationTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: uuid.FromStringOrNil(testAppVersionID),
+			},
+			requestHeaders:     testRequestHeaders,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"message": "Invalid UUID format for scheduled-publish-id",
+			},
+		})
+	})
+
+	t.Run("when no scheduled publish service is provided in app env", func(t *testing.T) {
+		handler := services.AuthorizeForAppVersionScheduledPublishAccessHandlerFunc(&env.AppEnv{
+			RequestParams: validRequestParams,
+		}, authHandler)
+		performAuthorizationTest(t, httpMethod, url, handler, AuthorizationTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: uuid.FromStringOrNil(testAppVersionID),
+			},
+			requestHeaders:     testRequestHeaders,
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: map[string]interface{}{
+				"message": "Internal Server Error",
+			},
+		})
+	})
+
+	t.Run("when scheduled publish not found in database", func(t *testing.T) {
+		handler := services.AuthorizeForAppVersionScheduledPublishAccessHandlerFunc(&env.AppEnv{
+			RequestParams: validRequestParams,
+			ScheduledPublishService: &testScheduledPublishService{
+				findFn: func(scheduledPublish *models.ScheduledPublish) (*models.ScheduledPublish, error) {
+					return nil, gorm.ErrRecordNotFound
+				},
+			},
+		}, authHandler)
+		performAuthorizationTest(t, httpMethod, url, handler, AuthorizationTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: uuid.FromStringOrNil(testAppVersionID),
+			},
+			requestHeaders:     testRequestHeaders,
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: map[string]interface{}{
+				"message": "Not Found",
+			},
+		})
+	})
+
+	t.Run("when unexpected error happens at database query", func(t *testing.T) {
+		handler := services.AuthorizeForAppVersionScheduledPublishAccessHandlerFunc(&env.AppEnv{
+			RequestParams: validRequestParams,
+			ScheduledPublishService: &testScheduledPublishService{
+				findFn: func(scheduledPublish *models.ScheduledPublish) (*models.ScheduledPublish, error) {
+					return nil, errors.New("SOME-SQL-ERROR")
+				},
+			},
+		}, authHandler)
+		performAuthorizationTest(t, httpMethod, url, handler, AuthorizationTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppVersionID: uuid.FromStringOrNil(testAppVersionID),
+			},
+			requestHeaders:     testRequestHeaders,
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: map[string]interface{}{
+				"message": "Internal Server Error",
+			},
+		})
+	})
+}
+
 func Test_AuthorizeBuildWebhookForAppAccessFunc(t *testing.T) {
 	authHandler := &handlers.TestAuthHandler{
 		ContextElementList: map[string]ctxpkg.RequestContextKey{
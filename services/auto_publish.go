@@ -0,0 +1,147 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"go.uber.org/zap"
+)
+
+// AutoPublishAppVersion publishes a new version of a build by the matching
+// auto-publish rules of its app. It's run by a worker, after the uploadables of
+// the previous version got copied to the new one, see
+// enqueueNewAppVersionJobs. The outcome is recorded as an event of the version
+// for each rule, so that a wrong rule doesn't fail the job.
+func AutoPublishAppVersion(env *env.AppEnv, appVersion *models.AppVersion, workflow, branch string) error {
+	appSettings, err := env.AppSettingsService.Find(&models.AppSettings{AppID: appVersion.AppID})
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+	rules, err := matchingAutoPublishRules(appSettings, appVersion, workflow, branch)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	status, reason, err := autoPublishAppVersion(env, appSettings, appVersion, rules)
+	if err != nil {
+		env.Logger.Error("Failed to auto-publish app version", zap.String("app_version_id", appVersion.ID.String()), zap.Error(err))
+		status, reason = models.PublishTaskStatusFailed, "publishing failed to start"
+	}
+	for _, rule := range rules {
+		text := fmt.Sprintf("Publishing was started by the auto-publish rule %s", rule.Name)
+		if status != models.PublishTaskStatusPending {
+			text = fmt.Sprintf("Auto-publish rule %s was skipped: %s", rule.Name, reason)
+		}
+		_, err := env.AppVersionEventService.Create(&models.AppVersionEvent{
+			Status:       status,
+			Text:         text,
+			AppVersionID: appVersion.ID,
+		})
+		if err != nil {
+			return errors.Wrap(err, "SQL Error")
+		}
+	}
+	return nil
+}
+
+// matchingAutoPublishRules returns the auto-publish rules of an app, which a
+// new version of a build of the workflow and the branch matches
+func matchingAutoPublishRules(appSettings *models.AppSettings, appVersion *models.AppVersion, workflow, branch string) ([]models.AutoPublishRule, error) {
+	autoPublishRules, err := appSettings.AutoPublishRules()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	rules := []models.AutoPublishRule{}
+	for _, autoPublishRule := range autoPublishRules {
+		if autoPublishRule.Matches(appVersion, workflow, branch) {
+			rules = append(rules, autoPublishRule)
+		}
+	}
+	return rules, nil
+}
+
+// autoPublishAppVersion publishes an app version to the targets of the rules
+// in a single batch. When the version can't be published, the status of the
+// events and the reason is returned.
+func autoPublishAppVersion(env *env.AppEnv, appSettings *models.AppSettings, appVersion *models.AppVersion, rules []models.AutoPublishRule) (string, string, error) {
+	// the version is checked by the current artifacts and settings, as its
+	// status was set when it got created
+	reason, err := publishDisabledReason(env, appVersion)
+	if err != nil {
+		return "", "", errors.WithStack(err)
+	}
+	if reason != "" {
+		return models.PublishTaskStatusFailed, reason, nil
+	}
+	warning, err := buildCodeWarning(env, appVersion)
+	if err != nil {
+		return "", "", errors.WithStack(err)
+	}
+	if warning != "" {
+		return models.PublishTaskStatusFailed, warning, nil
+	}
+
+	params := []AppVersionPublishDestinationParams{}
+	added := map[AppVersionPublishDestinationParams]bool{}
+	for _, rule := range rules {
+		param := autoPublishDestinationParams(appVersion.Platform, rule)
+		if !added[param] {
+			params = append(params, param)
+			added[param] = true
+		}
+	}
+	targets, err := publishTargets(appVersion.Platform, params)
+	if err != nil {
+		return models.PublishTaskStatusFailed, err.Error(), nil
+	}
+	approvalWarnings, err := missingApprovals(env, appVersion, appSettings, targets)
+	if err != nil {
+		return "", "", errors.WithStack(err)
+	}
+	if len(approvalWarnings) > 0 {
+		return models.PublishTaskStatusFailed, strings.Join(approvalWarnings, ", "), nil
+	}
+
+	_, _, err = publishAppVersion(env, appVersion, appSettings, targets, uuid.NewV4())
+	if isPublishTriggerError(err) {
+		return models.PublishTaskStatusFailed, err.Error(), nil
+	}
+	if err != nil {
+		return "", "", errors.WithStack(err)
+	}
+	return models.PublishTaskStatusPending, "", nil
+}
+
+// validateAutoPublishRules checks the destinations and the tracks of the rules,
+// which the models can't, as the publish destinations are registered here
+func validateAutoPublishRules(autoPublishRules []models.AutoPublishRule) []error {
+	verrs := []error{}
+	for _, rule := range autoPublishRules {
+		if publishDestinationForPlatform(rule.Platform) == nil {
+			// the platform of the rule is validated by the model
+			continue
+		}
+		params := []AppVersionPublishDestinationParams{autoPublishDestinationParams(rule.Platform, rule)}
+		if _, err := publishTargets(rule.Platform, params); err != nil {
+			verrs = append(verrs, errors.Errorf("auto_publish_rules.destination: %s", err))
+		}
+	}
+	return verrs
+}
+
+// autoPublishDestinationParams returns the destination of a rule, which is the
+// default destination of the platform when the rule has none
+func autoPublishDestinationParams(platform string, rule models.AutoPublishRule) AppVersionPublishDestinationParams {
+	destinationID := rule.Destination
+	if destination := publishDestinationForPlatform(platform); destinationID == "" && destination != nil {
+		destinationID = destination.ID()
+	}
+	return AppVersionPublishDestinationParams{ID: destinationID, Track: rule.Track}
+}
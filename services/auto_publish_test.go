@@ -0,0 +1,164 @@
+package services_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bitrise-io/addons-ship-backend/bitrise"
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/addons-ship-backend/services"
+	"github.com/bitrise-io/api-utils/security"
+	"github.com/c2fo/testify/require"
+	"github.com/jinzhu/gorm"
+	uuid "github.com/satori/go.uuid"
+)
+
+func Test_AutoPublishAppVersion(t *testing.T) {
+	testAppVersionID := uuid.FromStringOrNil("de438ddc-98e5-4226-a5f4-fd2d53474879")
+	testTaskIdentifier := uuid.FromStringOrNil("13a94c5d-4609-404e-ae69-c625e93b8b71")
+
+	newAppVersion := func() *models.AppVersion {
+		return &models.AppVersion{
+			Record:           models.Record{ID: testAppVersionID},
+			App:              models.App{AppSlug: "test-app-slug", BitriseAPIToken: "test-api-token", APIToken: "addon-access-token"},
+			Platform:         "ios",
+			BuildNumber:      "12",
+			BuildSlug:        "test-build-slug",
+			Status:           models.AppVersionStatusReady,
+			ArtifactInfoData: json.RawMessage(`{"version":"1.0"}`),
+			AppStoreInfoData: json.RawMessage(`{}`),
+		}
+	}
+	appSettingsService := &testAppSettingsService{
+		findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+			return &models.AppSettings{
+				AutoPublishRulesData: json.RawMessage(`[{"name":"TestFlight","platform":"ios","workflow":"deploy","branch":"main"}]`),
+			}, nil
+		},
+	}
+	testArtifacts := []bitrise.ArtifactListElementResponseModel{
+		bitrise.ArtifactListElementResponseModel{
+			Slug:  "test-artifact-slug",
+			Title: "my-ios-artifact.xcarchive.zip",
+			ArtifactMeta: &bitrise.ArtifactMeta{
+				AppInfo:          bitrise.AppInfo{Version: "1.0", BuildNumber: "12", DeviceFamilyList: []int{1}},
+				ProvisioningInfo: bitrise.ProvisioningInfo{IPAExportMethod: "app-store"},
+			},
+		},
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		events := []string{}
+		publishTasks := []models.PublishTask{}
+		err := services.AutoPublishAppVersion(&env.AppEnv{
+			AddonHostURL:       "http://ship.addon.url",
+			AppSettingsService: appSettingsService,
+			AppVersionService: &testAppVersionService{
+				latestPublishedFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+					return nil, gorm.ErrRecordNotFound
+				},
+				updateFn: func(appVersion *models.AppVersion, whitelist []string) ([]error, error) {
+					require.Equal(t, "publishing", appVersion.Status)
+					return nil, nil
+				},
+			},
+			BitriseAPI: &testBitriseAPI{
+				getArtifactsFn: func(apiToken, appSlug, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
+					require.Equal(t, "test-api-token", apiToken)
+					require.Equal(t, "test-build-slug", buildSlug)
+					return testArtifacts, nil
+				},
+				triggerDENTaskFn: func(params bitrise.TaskParams) (*bitrise.TriggerResponse, error) {
+					require.Equal(t, "resign_archive_app_store", params.Workflow)
+					return &bitrise.TriggerResponse{TaskIdentifier: testTaskIdentifier}, nil
+				},
+			},
+			PublishTaskService: &testPublishTaskService{
+				createFn: func(publishTask *models.PublishTask) (*models.PublishTask, error) {
+					publishTasks = append(publishTasks, *publishTask)
+					return publishTask, nil
+				},
+			},
+			JWTService: &security.JWTMock{
+				SignFn: func(token string) (string, error) {
+					return "jwt-token", nil
+				},
+			},
+			AppVersionEventService: &testAppVersionEventService{
+				createFn: func(event *models.AppVersionEvent) (*models.AppVersionEvent, error) {
+					require.Equal(t, testAppVersionID, event.AppVersionID)
+					events = append(events, event.Status+": "+event.Text)
+					return event, nil
+				},
+			},
+		}, newAppVersion(), "deploy", "main")
+		require.NoError(t, err)
+		require.Len(t, publishTasks, 1)
+		require.Equal(t, "app-store-connect", publishTasks[0].Destination)
+		require.Equal(t, []string{"pending: Publishing was started by the auto-publish rule TestFlight"}, events)
+	})
+
+	t.Run("when the version can't be published from the artifacts of its build", func(t *testing.T) {
+		events := []string{}
+		err := services.AutoPublishAppVersion(&env.AppEnv{
+			AppSettingsService: appSettingsService,
+			BitriseAPI: &testBitriseAPI{
+				getArtifactsFn: func(apiToken, appSlug, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
+					return []bitrise.ArtifactListElementResponseModel{}, nil
+				},
+			},
+			AppVersionEventService: &testAppVersionEventService{
+				createFn: func(event *models.AppVersionEvent) (*models.AppVersionEvent, error) {
+					events = append(events, event.Status+": "+event.Text)
+					return event, nil
+				},
+			},
+		}, newAppVersion(), "deploy", "main")
+		require.NoError(t, err)
+		require.Equal(t, []string{"failed: Auto-publish rule TestFlight was skipped: No artifact of the build of the version can be published"}, events)
+	})
+
+	t.Run("when the build number of the version was published already", func(t *testing.T) {
+		events := []string{}
+		err := services.AutoPublishAppVersion(&env.AppEnv{
+			AppSettingsService: appSettingsService,
+			AppVersionService: &testAppVersionService{
+				latestPublishedFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+					return &models.AppVersion{Platform: "ios", BuildNumber: "12"}, nil
+				},
+			},
+			BitriseAPI: &testBitriseAPI{
+				getArtifactsFn: func(apiToken, appSlug, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
+					return testArtifacts, nil
+				},
+			},
+			AppVersionEventService: &testAppVersionEventService{
+				createFn: func(event *models.AppVersionEvent) (*models.AppVersionEvent, error) {
+					events = append(events, event.Status+": "+event.Text)
+					return event, nil
+				},
+			},
+		}, newAppVersion(), "deploy", "main")
+		require.NoError(t, err)
+		require.Equal(t, []string{"failed: Auto-publish rule TestFlight was skipped: Build number 12 is not greater than the greatest published one (12)"}, events)
+	})
+
+	t.Run("when no rule matches the version", func(t *testing.T) {
+		err := services.AutoPublishAppVersion(&env.AppEnv{
+			AppSettingsService: appSettingsService,
+		}, newAppVersion(), "deploy", "feature")
+		require.NoError(t, err)
+	})
+
+	t.Run("when error happens at finding app settings", func(t *testing.T) {
+		err := services.AutoPublishAppVersion(&env.AppEnv{
+			AppSettingsService: &testAppSettingsService{
+				findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+					return nil, gorm.ErrRecordNotFound
+				},
+			},
+		}, newAppVersion(), "deploy", "main")
+		require.EqualError(t, err, "SQL Error: record not found")
+	})
+}
@@ -9,6 +9,7 @@ type testBitriseAPI struct {
 	getArtifactPublicPageURLFn func(string, string, string, string) (string, error)
 	getAppDetailsFn            func(string, string) (*bitrise.AppDetails, error)
 	getBuildDetailsFn          func(string, string, string) (*bitrise.BuildDetails, error)
+	getBuildsFn                func(string, string, int) ([]bitrise.BuildDetails, error)
 	getProvisioningProfilesFn  func(string, string) ([]bitrise.ProvisioningProfile, error)
 	getProvisioningProfileFn   func(string, string, string) (*bitrise.ProvisioningProfile, error)
 	getCodeSigningIdentitiesFn func(string, string) ([]bitrise.CodeSigningIdentity, error)
@@ -17,6 +18,7 @@ type testBitriseAPI struct {
 	getAndroidKeystoreFileFn   func(string, string, string) (*bitrise.AndroidKeystoreFile, error)
 	getServiceAccountFilesFn   func(string, string) ([]bitrise.GenericProjectFile, error)
 	getServiceAccountFileFn    func(string, string, string) (*bitrise.GenericProjectFile, error)
+	downloadFileFn             func(string) ([]byte, error)
 	triggerDENTaskFn           func(params bitrise.TaskParams) (*bitrise.TriggerResponse, error)
 	registerWebhookFn          func(string, string, string, string) error
 }
@@ -63,6 +65,13 @@ func (a *testBitriseAPI) GetBuildDetails(authToken, appSlug, buildSlug string) (
 	return a.getBuildDetailsFn(authToken, appSlug, buildSlug)
 }
 
+func (a *testBitriseAPI) GetBuilds(authToken, appSlug string, limit int) ([]bitrise.BuildDetails, error) {
+	if a.getBuildsFn == nil {
+		panic("You have to override GetBuilds function in tests")
+	}
+	return a.getBuildsFn(authToken, appSlug, limit)
+}
+
 func (a *testBitriseAPI) GetProvisioningProfiles(authToken, appSlug string) ([]bitrise.ProvisioningProfile, error) {
 	if a.getProvisioningProfilesFn == nil {
 		panic("You have to override GetProvisioningProfiles function in tests")
@@ -119,6 +128,13 @@ func (a *testBitriseAPI) GetServiceAccountFile(authToken, appSlug, serviceJSONSL
 	return a.getServiceAccountFileFn(authToken, appSlug, serviceJSONSLug)
 }
 
+func (a *testBitriseAPI) DownloadFile(downloadURL string) ([]byte, error) {
+	if a.downloadFileFn == nil {
+		panic("You have to override DownloadFile function in tests")
+	}
+	return a.downloadFileFn(downloadURL)
+}
+
 func (a *testBitriseAPI) TriggerDENTask(params bitrise.TaskParams) (*bitrise.TriggerResponse, error) {
 	if a.triggerDENTaskFn == nil {
 		panic("You have to override TriggerDENTask function in tests")
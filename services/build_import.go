@@ -0,0 +1,385 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bitrise-io/addons-ship-backend/bitrise"
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	"github.com/simonmarton/common-colors/processimage"
+	"go.uber.org/zap"
+)
+
+// BuildImport is a finished build, whose artifacts get turned into app versions
+type BuildImport struct {
+	BuildSlug         string
+	BuildNumber       int
+	TriggeredWorkflow string
+	// CheckRules restricts the import to the builds matching the version rules
+	// of the app, the builds imported by hand can come from any workflow
+	CheckRules bool
+	// Notify sends the new version email to the contacts of the app
+	Notify bool
+	// AutoPublish enqueues the publish of the new versions by the auto-publish
+	// rules of the app, see enqueueNewAppVersionJobs
+	AutoPublish bool
+}
+
+func (b BuildImport) allowed(versionRules models.VersionRules, buildDetails *bitrise.BuildDetails) bool {
+	if !b.CheckRules {
+		return true
+	}
+	return versionRules.Matches(b.workflow(buildDetails), buildDetails.Branch, buildDetails.Tag)
+}
+
+func (b BuildImport) workflow(buildDetails *bitrise.BuildDetails) string {
+	if b.TriggeredWorkflow == "" {
+		return buildDetails.TriggeredWorkflow
+	}
+	return b.TriggeredWorkflow
+}
+
+// CheckBuildImportServices returns an error if a service used by ImportBuild
+// is missing
+func CheckBuildImportServices(env *env.AppEnv) error {
+	if env.AppService == nil {
+		return errors.New("No App Service defined for handler")
+	}
+	if env.AppSettingsService == nil {
+		return errors.New("No App Settings Service defined for handler")
+	}
+	if env.AppVersionService == nil {
+		return errors.New("No App Version Service defined for handler")
+	}
+	if env.AppVersionEventService == nil {
+		return errors.New("No App Version Event Service defined for handler")
+	}
+	if env.BitriseAPI == nil {
+		return errors.New("No Bitrise API Service defined for handler")
+	}
+	if env.AppContactService == nil {
+		return errors.New("No App Contact Service defined for handler")
+	}
+	if env.WorkerService == nil {
+		return errors.New("No Worker Service defined for handler")
+	}
+	if env.ReleaseService == nil {
+		return errors.New("No Release Service defined for handler")
+	}
+	return nil
+}
+
+// ImportBuild creates the app versions of a build from its artifacts, the same
+// way for the build webhook and for the builds imported later. The validation
+// errors are the ones of the created versions and of the Android settings.
+func ImportBuild(env *env.AppEnv, appSettings *models.AppSettings, build BuildImport) ([]models.AppVersion, []error, error) {
+	app := appSettings.App
+
+	artifacts, err := env.BitriseAPI.GetArtifacts(app.BitriseAPIToken, app.AppSlug, build.BuildSlug)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	appDetails, err := env.BitriseAPI.GetAppDetails(app.BitriseAPIToken, app.AppSlug)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	buildDetails, err := env.BitriseAPI.GetBuildDetails(app.BitriseAPIToken, app.AppSlug, build.BuildSlug)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	versionRules, err := appSettings.VersionRules()
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	if appDetails.AvatarURL != nil {
+		colors, err := processimage.FromURL(*appDetails.AvatarURL)
+		if err != nil {
+			env.Logger.Warn("Failed to generate header colors", zap.Any("app_details", appDetails), zap.Error(err))
+		} else {
+			app.HeaderColor1 = colors[0]
+			app.HeaderColor2 = colors[1]
+			verrs, err := env.AppService.Update(app, []string{"HeaderColor1", "HeaderColor2"})
+			if len(verrs) > 0 {
+				return nil, verrs, nil
+			}
+			if err != nil {
+				return nil, nil, errors.Wrap(err, "SQL Error")
+			}
+		}
+	}
+
+	createdAppVersions := []models.AppVersion{}
+	iosVersionCreated := false
+
+	if build.allowed(versionRules.Ios, buildDetails) && hasIosArtifact(artifacts) {
+		appVersions, err := prepareAppVersionsForIosPlatform(artifacts, build.BuildSlug)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, appVersion := range appVersions {
+			appVersion.LastUpdate = time.Now()
+			appVersion.AppID = appSettings.AppID
+			appVersion.CommitMessage = buildDetails.CommitMessage
+			latestAppVersion, err := env.AppVersionService.Latest(latestIosAppVersionCondition(appVersion))
+			if err != nil && errors.Cause(err) != gorm.ErrRecordNotFound {
+				return nil, nil, errors.Wrap(err, "SQL Error")
+			}
+			if latestAppVersion != nil {
+				appVersion.AppStoreInfoData = latestAppVersion.AppStoreInfoData
+			}
+			release, err := releaseForAppVersion(env, appVersion)
+			if err != nil {
+				return nil, nil, errors.WithStack(err)
+			}
+			appVersion.ReleaseID = &release.ID
+			appVersion.Status, err = newAppVersionStatus(appVersion, artifacts)
+			if err != nil {
+				return nil, nil, errors.WithStack(err)
+			}
+			appVersion, verrs, err := env.AppVersionService.Create(appVersion)
+			if len(verrs) > 0 {
+				return nil, verrs, nil
+			}
+			if err != nil {
+				return nil, nil, errors.Wrap(err, "SQL Error")
+			}
+			if err := supersedeAppVersion(env, latestAppVersion, appVersion); err != nil {
+				return nil, nil, errors.WithStack(err)
+			}
+			if err := enqueueNewAppVersionJobs(env, appSettings, build, buildDetails, latestAppVersion, appVersion); err != nil {
+				return nil, nil, errors.WithStack(err)
+			}
+			if latestAppVersion == nil && !iosVersionCreated {
+				env.AnalyticsClient.FirstVersionCreated(app.AppSlug, build.BuildSlug, appVersion.Platform)
+			}
+			iosVersionCreated = true
+
+			_, err = env.AppVersionEventService.Create(&models.AppVersionEvent{AppVersionID: appVersion.ID, Text: "New version was created"})
+			if err != nil {
+				return nil, nil, errors.Wrap(err, "SQL Error")
+			}
+
+			if build.Notify {
+				if err := sendNotification(env, appVersion, app, appDetails); err != nil {
+					return nil, nil, errors.WithStack(err)
+				}
+			}
+			createdAppVersions = append(createdAppVersions, *appVersion)
+		}
+	}
+
+	artifactSelector := bitrise.NewArtifactSelector(artifacts)
+	if build.allowed(versionRules.Android, buildDetails) && artifactSelector.HasAndroidArtifact() {
+		androidSettings, err := appSettings.AndroidSettings()
+		if err != nil {
+			return nil, nil, errors.WithStack(err)
+		}
+
+		appVersions, settingsErr, err := artifactSelector.PrepareAndroidAppVersions(build.BuildSlug, fmt.Sprintf("%d", build.BuildNumber), buildDetails.CommitMessage, androidSettings.Module)
+		if settingsErr != nil {
+			// the problems of the service account file don't depend on the build
+			app.AndroidErrors = append(app.AndroidServiceAccountErrors(), settingsErr.Error())
+			verrs, err := env.AppService.Update(app, []string{"AndroidErrors"})
+			if len(verrs) > 0 {
+				return nil, verrs, nil
+			}
+			if err != nil {
+				return nil, nil, errors.Wrap(err, "SQL Error")
+			}
+
+			return nil, []error{settingsErr}, nil
+		}
+		if err != nil {
+			return nil, nil, errors.WithStack(err)
+		}
+		for _, version := range appVersions {
+			latestAppVersion, err := env.AppVersionService.Latest(&models.AppVersion{
+				AppID:         app.ID,
+				Platform:      "android",
+				ProductFlavor: version.ProductFlavor,
+			})
+			if err != nil && errors.Cause(err) != gorm.ErrRecordNotFound {
+				return nil, nil, errors.Wrap(err, "SQL Error")
+			}
+			version.AppID = appSettings.AppID
+			release, err := releaseForAppVersion(env, &version)
+			if err != nil {
+				return nil, nil, errors.WithStack(err)
+			}
+			version.ReleaseID = &release.ID
+			version.Status, err = newAppVersionStatus(&version, artifacts)
+			if err != nil {
+				return nil, nil, errors.WithStack(err)
+			}
+			appVersion, verrs, err := env.AppVersionService.Create(&version)
+			if len(verrs) > 0 {
+				return nil, verrs, nil
+			}
+			if err != nil {
+				return nil, nil, errors.Wrap(err, "SQL Error")
+			}
+			if err := supersedeAppVersion(env, latestAppVersion, appVersion); err != nil {
+				return nil, nil, errors.WithStack(err)
+			}
+
+			if err := enqueueNewAppVersionJobs(env, appSettings, build, buildDetails, latestAppVersion, appVersion); err != nil {
+				return nil, nil, errors.WithStack(err)
+			}
+			if latestAppVersion == nil && !iosVersionCreated {
+				env.AnalyticsClient.FirstVersionCreated(app.AppSlug, build.BuildSlug, "android")
+			}
+
+			_, err = env.AppVersionEventService.Create(&models.AppVersionEvent{AppVersionID: appVersion.ID, Text: "New version was created"})
+			if err != nil {
+				return nil, nil, errors.Wrap(err, "SQL Error")
+			}
+
+			if build.Notify {
+				if err := sendNotification(env, appVersion, app, appDetails); err != nil {
+					return nil, nil, errors.WithStack(err)
+				}
+			}
+			createdAppVersions = append(createdAppVersions, *appVersion)
+
+			if serviceAccountErrors := app.AndroidServiceAccountErrors(); len(app.AndroidErrors) > len(serviceAccountErrors) {
+				app.AndroidErrors = serviceAccountErrors
+				verrs, err = env.AppService.Update(app, []string{"AndroidErrors"})
+				if len(verrs) > 0 {
+					return nil, verrs, nil
+				}
+				if err != nil {
+					return nil, nil, errors.Wrap(err, "SQL Error")
+				}
+			}
+		}
+	}
+
+	return createdAppVersions, nil, nil
+}
+
+// enqueueNewAppVersionJobs enqueues the copy of the uploadables of the previous
+// version to a new one. When the new version matches an auto-publish rule of the
+// app, it gets published by the same job, after the copy, see
+// AutoPublishAppVersion.
+func enqueueNewAppVersionJobs(env *env.AppEnv, appSettings *models.AppSettings, build BuildImport, buildDetails *bitrise.BuildDetails, previousAppVersion, appVersion *models.AppVersion) error {
+	copyUploadablesFromID := ""
+	if previousAppVersion != nil {
+		copyUploadablesFromID = previousAppVersion.ID.String()
+	}
+
+	if build.AutoPublish {
+		workflow := build.workflow(buildDetails)
+		rules, err := matchingAutoPublishRules(appSettings, appVersion, workflow, buildDetails.Branch)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if len(rules) > 0 {
+			err := env.WorkerService.EnqueueAutoPublishAppVersion(appVersion.ID, workflow, buildDetails.Branch, copyUploadablesFromID)
+			if err != nil {
+				return errors.Wrap(err, "Worker Error")
+			}
+			return nil
+		}
+	}
+
+	if copyUploadablesFromID == "" {
+		return nil
+	}
+	err := env.WorkerService.EnqueueCopyUploadablesToNewAppVersion(copyUploadablesFromID, appVersion.ID.String())
+	if err != nil {
+		return errors.Wrap(err, "Worker Error")
+	}
+	return nil
+}
+
+// releaseForAppVersion returns the release a new version belongs to: the
+// release of the build of the version, or else the release of the same version
+// string. A new release is created when there's none of them.
+func releaseForAppVersion(env *env.AppEnv, appVersion *models.AppVersion) (*models.Release, error) {
+	artifactInfo, err := appVersion.ArtifactInfo()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	conditions := []*models.Release{}
+	if appVersion.BuildSlug != "" {
+		conditions = append(conditions, &models.Release{AppID: appVersion.AppID, BuildSlug: appVersion.BuildSlug})
+	}
+	if artifactInfo.Version != "" {
+		conditions = append(conditions, &models.Release{AppID: appVersion.AppID, Version: artifactInfo.Version})
+	}
+	for _, condition := range conditions {
+		release, err := env.ReleaseService.Find(condition)
+		switch {
+		case err == nil:
+			return release, nil
+		case errors.Cause(err) != gorm.ErrRecordNotFound:
+			return nil, errors.Wrap(err, "SQL Error")
+		}
+	}
+	release, err := env.ReleaseService.Create(&models.Release{
+		AppID:     appVersion.AppID,
+		BuildSlug: appVersion.BuildSlug,
+		Version:   artifactInfo.Version,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "SQL Error")
+	}
+	return release, nil
+}
+
+// newAppVersionStatus returns the status of a new version: it's ready when it
+// can be published from the artifacts of its build, and a draft otherwise
+func newAppVersionStatus(appVersion *models.AppVersion, artifacts []bitrise.ArtifactListElementResponseModel) (string, error) {
+	publishEnabled, err := isPublishEnabled(appVersion, artifacts)
+	if err != nil {
+		return "", err
+	}
+	if publishEnabled {
+		return models.AppVersionStatusReady, nil
+	}
+	return models.AppVersionStatusDraft, nil
+}
+
+// isPublishEnabled tells whether the artifacts of its build make an app version
+// publishable, the same way as the publish_enabled flag of the version
+func isPublishEnabled(appVersion *models.AppVersion, artifacts []bitrise.ArtifactListElementResponseModel) (bool, error) {
+	switch {
+	case appVersion.IsApplePlatform():
+		_, publishEnabled, _, _, _ := selectIosArtifact(artifacts, appVersion)
+		return publishEnabled, nil
+	case appVersion.Platform == "android":
+		artifactSelector := bitrise.NewArtifactSelector(artifacts)
+		publishAndShareInfo, err := artifactSelector.PublishAndShareInfo(appVersion)
+		if err != nil {
+			return false, errors.WithStack(err)
+		}
+		return publishAndShareInfo.PublishEnabled && len(appVersion.App.AndroidServiceAccountErrors()) == 0, nil
+	}
+	return false, errors.Errorf("Invalid platform type of app version: %s", appVersion.Platform)
+}
+
+// supersedeAppVersion marks the previous version of a new one superseded,
+// unless its publishing was started, or it's a later version than the new one,
+// like when an older build gets imported
+func supersedeAppVersion(env *env.AppEnv, previousAppVersion, appVersion *models.AppVersion) error {
+	if previousAppVersion == nil || !previousAppVersion.Supersedable() || models.CompareAppVersions(previousAppVersion, appVersion) > 0 {
+		return nil
+	}
+	return updateAppVersionStatus(env, previousAppVersion, models.AppVersionStatusSuperseded)
+}
+
+func sendNotification(env *env.AppEnv, appVersion *models.AppVersion, app *models.App, appDetails *bitrise.AppDetails) error {
+	appContacts, err := env.AppContactService.FindAll(app)
+	appVersion.App = *app
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return env.Mailer.SendEmailNewVersion(appVersion, appContacts, env.AddonFrontendHostURL, appDetails)
+}
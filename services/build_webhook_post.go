@@ -2,20 +2,15 @@ package services
 
 import (
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"runtime/debug"
-	"strings"
-	"time"
 
-	"github.com/bitrise-io/addons-ship-backend/bitrise"
 	"github.com/bitrise-io/addons-ship-backend/env"
 	"github.com/bitrise-io/addons-ship-backend/models"
 	"github.com/bitrise-io/api-utils/httprequest"
 	"github.com/bitrise-io/api-utils/httpresponse"
 	"github.com/jinzhu/gorm"
 	"github.com/pkg/errors"
-	"github.com/simonmarton/common-colors/processimage"
 	"go.uber.org/zap"
 )
 
@@ -44,26 +39,8 @@ func BuildWebhookHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Request
 	case "build/triggered":
 		return httpresponse.RespondWithSuccess(w, nil)
 	case "build/finished":
-		if env.AppService == nil {
-			return errors.New("No App Service defined for handler")
-		}
-		if env.AppSettingsService == nil {
-			return errors.New("No App Settings Service defined for handler")
-		}
-		if env.AppVersionService == nil {
-			return errors.New("No App Version Service defined for handler")
-		}
-		if env.AppVersionEventService == nil {
-			return errors.New("No App Version Event Service defined for handler")
-		}
-		if env.BitriseAPI == nil {
-			return errors.New("No Bitrise API Service defined for handler")
-		}
-		if env.AppContactService == nil {
-			return errors.New("No App Contact Service defined for handler")
-		}
-		if env.WorkerService == nil {
-			return errors.New("No Worker Service defined for handler")
+		if err := CheckBuildImportServices(env); err != nil {
+			return err
 		}
 		var params BuildWebhookPayload
 		defer httprequest.BodyCloseWithErrorLog(r)
@@ -84,169 +61,23 @@ func BuildWebhookHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Request
 			return errors.Wrap(err, "SQL Error")
 		}
 
-		app := appSettings.App
-
-		artifacts, err := env.BitriseAPI.GetArtifacts(app.BitriseAPIToken, app.AppSlug, params.BuildSlug)
-		if err != nil {
-			return errors.WithStack(err)
+		_, verrs, err := ImportBuild(env, appSettings, BuildImport{
+			BuildSlug:         params.BuildSlug,
+			BuildNumber:       params.BuildNumber,
+			TriggeredWorkflow: params.BuildTriggeredWorkflow,
+			CheckRules:        true,
+			Notify:            true,
+			AutoPublish:       true,
+		})
+		if len(verrs) > 0 {
+			return httpresponse.RespondWithUnprocessableEntity(w, verrs)
 		}
-
-		appDetails, err := env.BitriseAPI.GetAppDetails(app.BitriseAPIToken, app.AppSlug)
-		if err != nil {
-			return errors.WithStack(err)
-		}
-
-		buildDetails, err := env.BitriseAPI.GetBuildDetails(app.BitriseAPIToken, app.AppSlug, params.BuildSlug)
 		if err != nil {
 			return errors.WithStack(err)
 		}
 
-		if appDetails.AvatarURL != nil {
-			colors, err := processimage.FromURL(*appDetails.AvatarURL)
-			if err != nil {
-				env.Logger.Warn("Failed to generate header colors", zap.Any("app_details", appDetails), zap.Error(err))
-			} else {
-				app.HeaderColor1 = colors[0]
-				app.HeaderColor2 = colors[1]
-				verrs, err := env.AppService.Update(app, []string{"HeaderColor1", "HeaderColor2"})
-				if len(verrs) > 0 {
-					return httpresponse.RespondWithUnprocessableEntity(w, verrs)
-				}
-				if err != nil {
-					return errors.Wrap(err, "SQL Error")
-				}
-			}
-		}
-
-		iosVersionCreated := false
-
-		workflowInWhitelist := params.BuildTriggeredWorkflow != "" && strings.Contains(appSettings.IosWorkflow, params.BuildTriggeredWorkflow)
-		if (appSettings.IosWorkflow == "" || workflowInWhitelist) && hasIosArtifact(artifacts) {
-			latestAppVersion, err := env.AppVersionService.Latest(&models.AppVersion{AppID: app.ID, Platform: "ios"})
-			if err != nil && errors.Cause(err) != gorm.ErrRecordNotFound {
-				return errors.Wrap(err, "SQL Error")
-			}
-			appVersion, err := prepareAppVersionForIosPlatform(w, r, artifacts, params.BuildSlug)
-			if err != nil {
-				return err
-			}
-			appVersion.LastUpdate = time.Now()
-			appVersion.AppID = authorizedAppID
-			appVersion.CommitMessage = buildDetails.CommitMessage
-			if latestAppVersion != nil {
-				appVersion.AppStoreInfoData = latestAppVersion.AppStoreInfoData
-			}
-			appVersion, verrs, err := env.AppVersionService.Create(appVersion)
-			if len(verrs) > 0 {
-				return httpresponse.RespondWithUnprocessableEntity(w, verrs)
-			}
-			if err != nil {
-				return errors.Wrap(err, "SQL Error")
-			}
-			iosVersionCreated = true
-			if latestAppVersion != nil {
-				err := env.WorkerService.EnqueueCopyUploadablesToNewAppVersion(latestAppVersion.ID.String(), appVersion.ID.String())
-				if err != nil {
-					return errors.Wrap(err, "Worker Error")
-				}
-			} else {
-				env.AnalyticsClient.FirstVersionCreated(app.AppSlug, params.BuildSlug, "ios")
-			}
-
-			_, err = env.AppVersionEventService.Create(&models.AppVersionEvent{AppVersionID: appVersion.ID, Text: "New version was created"})
-			if err != nil {
-				return errors.Wrap(err, "SQL Error")
-			}
-
-			if err := sendNotification(env, appVersion, app, appDetails); err != nil {
-				return errors.WithStack(err)
-			}
-		}
-
-		artifactSelector := bitrise.NewArtifactSelector(artifacts)
-		workflowInWhitelist = params.BuildTriggeredWorkflow != "" && strings.Contains(appSettings.AndroidWorkflow, params.BuildTriggeredWorkflow)
-		if (appSettings.AndroidWorkflow == "" || workflowInWhitelist) && artifactSelector.HasAndroidArtifact() {
-			androidSettings, err := appSettings.AndroidSettings()
-			if err != nil {
-				return errors.WithStack(err)
-			}
-
-			appVersions, settingsErr, err := artifactSelector.PrepareAndroidAppVersions(params.BuildSlug, fmt.Sprintf("%d", params.BuildNumber), buildDetails.CommitMessage, androidSettings.Module)
-			if settingsErr != nil {
-				app.AndroidErrors = []string{settingsErr.Error()}
-				verrs, err := env.AppService.Update(app, []string{"AndroidErrors"})
-				if len(verrs) > 0 {
-					return httpresponse.RespondWithUnprocessableEntity(w, verrs)
-				}
-				if err != nil {
-					return errors.Wrap(err, "SQL Error")
-				}
-
-				return httpresponse.RespondWithUnprocessableEntity(w, []error{settingsErr})
-			}
-			if err != nil {
-				return errors.WithStack(err)
-			}
-			for _, version := range appVersions {
-				latestAppVersion, err := env.AppVersionService.Latest(&models.AppVersion{
-					AppID:         app.ID,
-					Platform:      "android",
-					ProductFlavor: version.ProductFlavor,
-				})
-				if err != nil && errors.Cause(err) != gorm.ErrRecordNotFound {
-					return errors.Wrap(err, "SQL Error")
-				}
-				version.AppID = authorizedAppID
-				appVersion, verrs, err := env.AppVersionService.Create(&version)
-				if len(verrs) > 0 {
-					return httpresponse.RespondWithUnprocessableEntity(w, verrs)
-				}
-				if err != nil {
-					return errors.Wrap(err, "SQL Error")
-				}
-
-				if latestAppVersion != nil {
-					err := env.WorkerService.EnqueueCopyUploadablesToNewAppVersion(latestAppVersion.ID.String(), appVersion.ID.String())
-					if err != nil {
-						return errors.Wrap(err, "Worker Error")
-					}
-				} else if !iosVersionCreated {
-					env.AnalyticsClient.FirstVersionCreated(app.AppSlug, params.BuildSlug, "android")
-				}
-
-				_, err = env.AppVersionEventService.Create(&models.AppVersionEvent{AppVersionID: appVersion.ID, Text: "New version was created"})
-				if err != nil {
-					return errors.Wrap(err, "SQL Error")
-				}
-
-				if err := sendNotification(env, appVersion, app, appDetails); err != nil {
-					return errors.WithStack(err)
-				}
-
-				if len(app.AndroidErrors) > 0 {
-					app.AndroidErrors = []string{}
-					verrs, err = env.AppService.Update(app, []string{"AndroidErrors"})
-					if len(verrs) > 0 {
-						return httpresponse.RespondWithUnprocessableEntity(w, verrs)
-					}
-					if err != nil {
-						return errors.Wrap(err, "SQL Error")
-					}
-				}
-			}
-		}
-
 		return httpresponse.RespondWithSuccess(w, nil)
 	default:
 		return errors.New("Invalid build event")
 	}
 }
-
-func sendNotification(env *env.AppEnv, appVersion *models.AppVersion, app *models.App, appDetails *bitrise.AppDetails) error {
-	appContacts, err := env.AppContactService.FindAll(app)
-	appVersion.App = *app
-	if err != nil {
-		return errors.WithStack(err)
-	}
-	return env.Mailer.SendEmailNewVersion(appVersion, appContacts, env.AddonFrontendHostURL, appDetails)
-}
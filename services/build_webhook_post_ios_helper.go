@@ -2,20 +2,32 @@ package services
 
 import (
 	"encoding/json"
-	"net/http"
 	"reflect"
+	"sort"
 
 	"github.com/bitrise-io/addons-ship-backend/bitrise"
 	"github.com/bitrise-io/addons-ship-backend/models"
 	"github.com/pkg/errors"
 )
 
-func prepareAppVersionForIosPlatform(w http.ResponseWriter, r *http.Request, artifacts []bitrise.ArtifactListElementResponseModel, buildSlug string) (*models.AppVersion, error) {
-	selectedArtifact, _, _, _, _ := selectIosArtifact(artifacts)
-	if selectedArtifact == nil || reflect.DeepEqual(*selectedArtifact, bitrise.ArtifactListElementResponseModel{}) {
+func prepareAppVersionsForIosPlatform(artifacts []bitrise.ArtifactListElementResponseModel, buildSlug string) ([]*models.AppVersion, error) {
+	archives := selectIosArchives(artifacts)
+	if len(archives) == 0 {
 		return nil, errors.New("No iOS artifact found")
 	}
 
+	appVersions := []*models.AppVersion{}
+	for _, archive := range archives {
+		appVersion, err := prepareAppVersionForIosArchive(archive, buildSlug)
+		if err != nil {
+			return nil, err
+		}
+		appVersions = append(appVersions, appVersion)
+	}
+	return appVersions, nil
+}
+
+func prepareAppVersionForIosArchive(selectedArtifact bitrise.ArtifactListElementResponseModel, buildSlug string) (*models.AppVersion, error) {
 	if selectedArtifact.ArtifactMeta == nil {
 		return nil, errors.New("No artifact meta data found for artifact")
 	}
@@ -34,6 +46,12 @@ func prepareAppVersionForIosPlatform(w http.ResponseWriter, r *http.Request, art
 			supportedDeviceTypes = append(supportedDeviceTypes, "iPhone", "iPod Touch")
 		case 2:
 			supportedDeviceTypes = append(supportedDeviceTypes, "iPad")
+		case 3:
+			supportedDeviceTypes = append(supportedDeviceTypes, "Apple TV")
+		case 4:
+			supportedDeviceTypes = append(supportedDeviceTypes, "Apple Watch")
+		case 6:
+			supportedDeviceTypes = append(supportedDeviceTypes, "Mac")
 		default:
 			supportedDeviceTypes = append(supportedDeviceTypes, "Unknown")
 		}
@@ -43,7 +61,7 @@ func prepareAppVersionForIosPlatform(w http.ResponseWriter, r *http.Request, art
 		MinimumOS:            selectedArtifact.ArtifactMeta.AppInfo.MinimumOS,
 		BundleID:             selectedArtifact.ArtifactMeta.AppInfo.BundleID,
 		SupportedDeviceTypes: supportedDeviceTypes,
-		// ExpireDate:           selectedArtifact.ArtifactMeta.ProvisioningInfo.ExpireDate,
+		ExpireDate:           selectedArtifact.ArtifactMeta.ProvisioningInfo.ExpireDate,
 	}
 	artifactInfoData, err := json.Marshal(artifactInfo)
 	if err != nil {
@@ -51,14 +69,42 @@ func prepareAppVersionForIosPlatform(w http.ResponseWriter, r *http.Request, art
 	}
 
 	return &models.AppVersion{
-		Platform:         "ios",
+		Platform:         applePlatform(selectedArtifact.ArtifactMeta.AppInfo.DeviceFamilyList),
 		BuildSlug:        buildSlug,
 		ArtifactInfoData: artifactInfoData,
 		Scheme:           selectedArtifact.ArtifactMeta.Scheme,
+		BundleID:         selectedArtifact.ArtifactMeta.AppInfo.BundleID,
 		BuildNumber:      selectedArtifact.ArtifactMeta.AppInfo.BuildNumber,
 	}, nil
 }
 
+// applePlatform returns the platform of an Xcode archive from the device
+// families it was built for. Archives without device families are iOS ones.
+func applePlatform(deviceFamilyList []int) string {
+	for _, platform := range []struct {
+		familyID int
+		name     string
+	}{{3, "tvos"}, {4, "watchos"}, {6, "macos"}} {
+		for _, familyID := range deviceFamilyList {
+			if familyID == platform.familyID {
+				return platform.name
+			}
+		}
+	}
+	return "ios"
+}
+
+// latestIosAppVersionCondition is the condition of the previous version of an
+// Apple platform version, which is the latest version of the platform with the
+// same bundle ID, or with the same scheme, if the archive had no bundle ID
+func latestIosAppVersionCondition(appVersion *models.AppVersion) *models.AppVersion {
+	condition := &models.AppVersion{AppID: appVersion.AppID, Platform: appVersion.Platform, BundleID: appVersion.BundleID}
+	if appVersion.BundleID == "" {
+		condition.Scheme = appVersion.Scheme
+	}
+	return condition
+}
+
 func hasIosArtifact(artifacts []bitrise.ArtifactListElementResponseModel) bool {
 	for _, artifact := range artifacts {
 		if artifact.IsXCodeArchive() {
@@ -69,13 +115,72 @@ func hasIosArtifact(artifacts []bitrise.ArtifactListElementResponseModel) bool {
 	return false
 }
 
-func selectIosArtifact(artifacts []bitrise.ArtifactListElementResponseModel) (*bitrise.ArtifactListElementResponseModel, bool, bool, string, string) {
+// iosArtifactKey identifies the app an iOS artifact was built for: its bundle
+// ID, or its scheme, if the bundle ID is unknown
+func iosArtifactKey(artifact bitrise.ArtifactListElementResponseModel) string {
+	if artifact.ArtifactMeta == nil {
+		return ""
+	}
+	if artifact.ArtifactMeta.AppInfo.BundleID != "" {
+		return artifact.ArtifactMeta.AppInfo.BundleID
+	}
+	return artifact.ArtifactMeta.Scheme
+}
+
+func iosAppVersionKey(appVersion *models.AppVersion) string {
+	if appVersion.BundleID != "" {
+		return appVersion.BundleID
+	}
+	return appVersion.Scheme
+}
+
+// isIosArtifactOf reports whether an artifact of a build belongs to an app
+// version of the build. Artifacts and versions, which can't be told apart,
+// belong together.
+func isIosArtifactOf(artifact bitrise.ArtifactListElementResponseModel, appVersion *models.AppVersion) bool {
+	if appVersion == nil {
+		return true
+	}
+	versionKey := iosAppVersionKey(appVersion)
+	artifactKey := iosArtifactKey(artifact)
+	return versionKey == "" || artifactKey == "" || artifactKey == versionKey
+}
+
+// selectIosArchives returns one Xcode archive per bundle ID (or scheme), if a
+// build has multiple archives of the same app, the last one is used
+func selectIosArchives(artifacts []bitrise.ArtifactListElementResponseModel) []bitrise.ArtifactListElementResponseModel {
+	archivesByKey := map[string]bitrise.ArtifactListElementResponseModel{}
+	for _, artifact := range artifacts {
+		if artifact.IsXCodeArchive() {
+			archivesByKey[iosArtifactKey(artifact)] = artifact
+		}
+	}
+	keys := []string{}
+	for key := range archivesByKey {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	archives := []bitrise.ArtifactListElementResponseModel{}
+	for _, key := range keys {
+		archives = append(archives, archivesByKey[key])
+	}
+	return archives
+}
+
+// selectIosArtifact selects the Xcode archive and the IPAs of the app version
+// from the artifacts of its build. When appVersion is nil, or it has neither a
+// bundle ID nor a scheme, the last Xcode archive of the build is selected.
+func selectIosArtifact(artifacts []bitrise.ArtifactListElementResponseModel, appVersion *models.AppVersion) (*bitrise.ArtifactListElementResponseModel, bool, bool, string, string) {
 	publishEnabled := false
 	publicInstallPageEnabled := false
 	ipaIPAExportMethod := ""
 	publicInstallPageArtifactSlug := ""
 	var selectedArtifact bitrise.ArtifactListElementResponseModel
 	for _, artifact := range artifacts {
+		if !isIosArtifactOf(artifact, appVersion) {
+			continue
+		}
 		if artifact.IsIPA() {
 			if artifact.ArtifactMeta != nil && artifact.ArtifactMeta.ProvisioningInfo.IPAExportMethod != "" {
 				ipaIPAExportMethod = artifact.ArtifactMeta.ProvisioningInfo.IPAExportMethod
@@ -12,6 +12,7 @@ import (
 	"github.com/bitrise-io/addons-ship-backend/services"
 	ctxpkg "github.com/bitrise-io/api-utils/context"
 	"github.com/bitrise-io/api-utils/httpresponse"
+	"github.com/bitrise-io/api-utils/security"
 	"github.com/c2fo/testify/require"
 	"github.com/jinzhu/gorm"
 	"github.com/pkg/errors"
@@ -23,7 +24,19 @@ func Test_BuildWebhookHandler(t *testing.T) {
 	url := "/webhook"
 	handler := services.BuildWebhookHandler
 
-	behavesAsServiceCravingHandler(t, httpMethod, url, handler, []string{"AppService", "AppSettingsService", "AppVersionService", "AppVersionEventService", "BitriseAPI", "AppContactService", "WorkerService"}, ControllerTestCase{
+	testReleaseID := uuid.FromStringOrNil("5c2c0f29-9ad8-4a0e-9e1e-1f7a3c6a7b5e")
+	testTaskIdentifier := uuid.FromStringOrNil("13a94c5d-4609-404e-ae69-c625e93b8b71")
+	releaseService := &testReleaseService{
+		findFn: func(release *models.Release) (*models.Release, error) {
+			return nil, gorm.ErrRecordNotFound
+		},
+		createFn: func(release *models.Release) (*models.Release, error) {
+			release.ID = testReleaseID
+			return release, nil
+		},
+	}
+
+	behavesAsServiceCravingHandler(t, httpMethod, url, handler, []string{"AppService", "AppSettingsService", "AppVersionService", "AppVersionEventService", "BitriseAPI", "AppContactService", "WorkerService", "ReleaseService"}, ControllerTestCase{
 		contextElements: map[ctxpkg.RequestContextKey]interface{}{
 			services.ContextKeyAuthorizedAppID: uuid.NewV4(),
 		},
@@ -31,6 +44,7 @@ func Test_BuildWebhookHandler(t *testing.T) {
 		env: &env.AppEnv{
 			AppService:             &testAppService{},
 			AppVersionService:      &testAppVersionService{},
+			ReleaseService:         &testReleaseService{},
 			AppVersionEventService: &testAppVersionEventService{},
 			AppSettingsService:     &testAppSettingsService{},
 			BitriseAPI:             &testBitriseAPI{},
@@ -47,6 +61,7 @@ func Test_BuildWebhookHandler(t *testing.T) {
 		env: &env.AppEnv{
 			AppService:             &testAppService{},
 			AppVersionService:      &testAppVersionService{},
+			ReleaseService:         &testReleaseService{},
 			AppVersionEventService: &testAppVersionEventService{},
 			AppSettingsService:     &testAppSettingsService{},
 			BitriseAPI:             &testBitriseAPI{},
@@ -88,6 +103,7 @@ func Test_BuildWebhookHandler(t *testing.T) {
 						},
 					},
 					AppVersionService:      &testAppVersionService{},
+					ReleaseService:         releaseService,
 					AppVersionEventService: &testAppVersionEventService{},
 					BitriseAPI: &testBitriseAPI{
 						getArtifactsFn: func(apiToken, appSlug, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
@@ -126,6 +142,7 @@ func Test_BuildWebhookHandler(t *testing.T) {
 						},
 					},
 					AppVersionService:      &testAppVersionService{},
+					ReleaseService:         releaseService,
 					AppVersionEventService: &testAppVersionEventService{},
 					BitriseAPI:             &testBitriseAPI{},
 					AppContactService:      &testAppContactService{},
@@ -148,63 +165,829 @@ func Test_BuildWebhookHandler(t *testing.T) {
 						findFn: func(app *models.App) (*models.App, error) {
 							return nil, errors.New("SOME-SQL-ERROR")
 						},
-					},
-					AppSettingsService: &testAppSettingsService{
-						findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
-							return appSettings, nil
+					},
+					AppSettingsService: &testAppSettingsService{
+						findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+							return appSettings, nil
+						},
+					},
+					AppVersionService:      &testAppVersionService{},
+					ReleaseService:         releaseService,
+					AppVersionEventService: &testAppVersionEventService{},
+					BitriseAPI:             &testBitriseAPI{},
+					AppContactService:      &testAppContactService{},
+					WorkerService:          &testWorkerService{},
+				},
+				requestBody:         `{}`,
+				expectedInternalErr: "SQL Error: SOME-SQL-ERROR",
+			})
+		})
+
+		t.Run("when app settings not found in database", func(t *testing.T) {
+			performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+				contextElements: map[ctxpkg.RequestContextKey]interface{}{
+					services.ContextKeyAuthorizedAppID: uuid.NewV4(),
+				},
+				requestHeaders: map[string]string{"Bitrise-Event-Type": "build/finished"},
+				env: &env.AppEnv{
+					AppService: &testAppService{
+						findFn: func(app *models.App) (*models.App, error) {
+							return app, nil
+						},
+					},
+					AppSettingsService: &testAppSettingsService{
+						findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+							return nil, gorm.ErrRecordNotFound
+						},
+					},
+					AppVersionService:      &testAppVersionService{},
+					ReleaseService:         releaseService,
+					AppVersionEventService: &testAppVersionEventService{},
+					BitriseAPI:             &testBitriseAPI{},
+					AppContactService:      &testAppContactService{},
+					WorkerService:          &testWorkerService{},
+				},
+				requestBody:        `{}`,
+				expectedStatusCode: http.StatusNotFound,
+				expectedResponse:   httpresponse.StandardErrorRespModel{Message: "Not Found"},
+			})
+		})
+
+		t.Run("when platform is ios", func(t *testing.T) {
+			testAppVersionID := uuid.FromStringOrNil("9f7b06d1-e736-42d3-94c3-c2bcfda0463c")
+			testAppVersion2ID := uuid.FromStringOrNil("f951e094-0ac1-4edf-ac8f-bb035dfd683c")
+			t.Run("ok - more complex - when ios workflow whitelist is empty", func(t *testing.T) {
+				previousVersionSuperseded := false
+				performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+					contextElements: map[ctxpkg.RequestContextKey]interface{}{
+						services.ContextKeyAuthorizedAppID: uuid.NewV4(),
+					},
+					requestHeaders: map[string]string{"Bitrise-Event-Type": "build/finished"},
+					env: &env.AppEnv{
+						AddonFrontendHostURL: "https://ship.bitrise.io",
+						AppService: &testAppService{
+							findFn: func(app *models.App) (*models.App, error) {
+								return app, nil
+							},
+						},
+						AppSettingsService: &testAppSettingsService{
+							findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+								return &models.AppSettings{
+									IosWorkflow:     "",
+									AndroidWorkflow: "some-android-wf",
+									App: &models.App{
+										BitriseAPIToken: "test-api-token",
+										AppSlug:         "test-app-slug",
+									},
+								}, nil
+							},
+						},
+						AppVersionService: &testAppVersionService{
+							createFn: func(appVersion *models.AppVersion) (*models.AppVersion, []error, error) {
+								require.Equal(t, "ios", appVersion.Platform)
+								require.Equal(t, "test-build-slug", appVersion.BuildSlug)
+								require.NotEqual(t, time.Time{}, appVersion.LastUpdate)
+								require.Equal(t, "12", appVersion.BuildNumber)
+								require.Equal(t, "The detailed commit message", appVersion.CommitMessage)
+								require.Equal(t, "test-scheme", appVersion.Scheme)
+								require.Equal(t, &testReleaseID, appVersion.ReleaseID)
+								require.Equal(t, "ready", appVersion.Status)
+								artifactData, err := appVersion.ArtifactInfo()
+								require.NoError(t, err)
+								require.Equal(t, "1.0", artifactData.Version)
+								require.Equal(t, []string{"iPhone", "iPod Touch", "iPad", "Unknown"}, artifactData.SupportedDeviceTypes)
+								appVersion.ID = testAppVersionID
+								appVersion.App = models.App{
+									BitriseAPIToken: "test-api-token",
+									AppSlug:         "test-app-slug",
+								}
+								return appVersion, nil, nil
+							},
+							latestFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+								require.Equal(t, "ios", appVersion.Platform)
+								appVersion.ID = testAppVersion2ID
+								appVersion.Status = "ready"
+								return appVersion, nil
+							},
+							updateFn: func(appVersion *models.AppVersion, whitelist []string) ([]error, error) {
+								require.Equal(t, testAppVersion2ID, appVersion.ID)
+								require.Equal(t, []string{"Status"}, whitelist)
+								require.Equal(t, "superseded", appVersion.Status)
+								previousVersionSuperseded = true
+								return nil, nil
+							},
+						},
+						ReleaseService: &testReleaseService{
+							findFn: func(release *models.Release) (*models.Release, error) {
+								return nil, gorm.ErrRecordNotFound
+							},
+							createFn: func(release *models.Release) (*models.Release, error) {
+								require.Equal(t, "test-build-slug", release.BuildSlug)
+								require.Equal(t, "1.0", release.Version)
+								release.ID = testReleaseID
+								return release, nil
+							},
+						},
+						AppVersionEventService: &testAppVersionEventService{
+							createFn: func(appVersionEvent *models.AppVersionEvent) (*models.AppVersionEvent, error) {
+								require.Equal(t, testAppVersionID, appVersionEvent.AppVersionID)
+								require.Equal(t, "New version was created", appVersionEvent.Text)
+								return nil, nil
+							},
+						},
+						BitriseAPI: &testBitriseAPI{
+							getArtifactsFn: func(apiToken, appSlug, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
+								require.Equal(t, "test-api-token", apiToken)
+								require.Equal(t, "test-app-slug", appSlug)
+								require.Equal(t, "test-build-slug", buildSlug)
+								return []bitrise.ArtifactListElementResponseModel{
+									bitrise.ArtifactListElementResponseModel{
+										Title: "my-ios-artifact.ipa",
+										ArtifactMeta: &bitrise.ArtifactMeta{
+											AppInfo: bitrise.AppInfo{
+												Version:          "1.0",
+												DeviceFamilyList: []int{1, 2, 12},
+											},
+											ProvisioningInfo: bitrise.ProvisioningInfo{IPAExportMethod: "app-store"},
+										},
+									},
+									bitrise.ArtifactListElementResponseModel{
+										Title: "my-ios-artifact.xcarchive.zip",
+										ArtifactMeta: &bitrise.ArtifactMeta{
+											AppInfo: bitrise.AppInfo{
+												Version:          "1.0",
+												BuildNumber:      "12",
+												DeviceFamilyList: []int{1, 2, 12},
+											},
+											ProvisioningInfo: bitrise.ProvisioningInfo{IPAExportMethod: "app-store"},
+											Scheme:           "test-scheme",
+										},
+									},
+								}, nil
+							},
+							getAppDetailsFn: func(apiToken, appSlug string) (*bitrise.AppDetails, error) {
+								require.Equal(t, "test-api-token", apiToken)
+								require.Equal(t, "test-app-slug", appSlug)
+								return &bitrise.AppDetails{Title: "My awesome app"}, nil
+							},
+							getBuildDetailsFn: func(apiToken string, appSlug string, buildSlug string) (*bitrise.BuildDetails, error) {
+								require.Equal(t, "test-api-token", apiToken)
+								require.Equal(t, "test-app-slug", appSlug)
+								require.Equal(t, "test-build-slug", buildSlug)
+								return &bitrise.BuildDetails{CommitMessage: "The detailed commit message"}, nil
+							},
+						},
+						AppContactService: &testAppContactService{
+							findAllFn: func(app *models.App) ([]models.AppContact, error) {
+								require.Equal(t, "test-app-slug", app.AppSlug)
+								return []models.AppContact{
+									models.AppContact{Email: "the.address@we.send"},
+								}, nil
+							},
+						},
+						Mailer: &testMailer{
+							sendEmailNewVersionFn: func(appVersion *models.AppVersion, contacts []models.AppContact, frontendBaseURL string, appDetails *bitrise.AppDetails) error {
+								require.Equal(t, testAppVersionID, appVersion.ID)
+								require.Equal(t, "My awesome app", appDetails.Title)
+								require.Len(t, contacts, 1)
+								require.Equal(t, "the.address@we.send", contacts[0].Email)
+								require.Equal(t, "https://ship.bitrise.io", frontendBaseURL)
+								return nil
+							},
+						},
+						WorkerService: &testWorkerService{
+							enqueueCopyUploadablesToNewAppVersionFn: func(fromID, toID string) error {
+								require.Equal(t, testAppVersion2ID.String(), fromID)
+								require.Equal(t, testAppVersionID.String(), toID)
+								return nil
+							},
+						},
+					},
+					requestBody:        `{"build_slug":"test-build-slug","build_number":12}`,
+					expectedStatusCode: http.StatusOK,
+				})
+				require.True(t, previousVersionSuperseded)
+			})
+
+			t.Run("ok - when the archive is a tvOS app", func(t *testing.T) {
+				performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+					contextElements: map[ctxpkg.RequestContextKey]interface{}{
+						services.ContextKeyAuthorizedAppID: uuid.NewV4(),
+					},
+					requestHeaders: map[string]string{"Bitrise-Event-Type": "build/finished"},
+					env: &env.AppEnv{
+						AppService: &testAppService{
+							findFn: func(app *models.App) (*models.App, error) {
+								return app, nil
+							},
+						},
+						AppSettingsService: &testAppSettingsService{
+							findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+								return &models.AppSettings{
+									App: &models.App{
+										BitriseAPIToken: "test-api-token",
+										AppSlug:         "test-app-slug",
+									},
+								}, nil
+							},
+						},
+						AppVersionService: &testAppVersionService{
+							createFn: func(appVersion *models.AppVersion) (*models.AppVersion, []error, error) {
+								require.Equal(t, "tvos", appVersion.Platform)
+								artifactData, err := appVersion.ArtifactInfo()
+								require.NoError(t, err)
+								require.Equal(t, []string{"Apple TV"}, artifactData.SupportedDeviceTypes)
+								appVersion.ID = testAppVersionID
+								return appVersion, nil, nil
+							},
+							latestFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+								require.Equal(t, "tvos", appVersion.Platform)
+								require.Equal(t, "io.bitrise.tv", appVersion.BundleID)
+								return nil, gorm.ErrRecordNotFound
+							},
+						},
+						ReleaseService: releaseService,
+						AppVersionEventService: &testAppVersionEventService{
+							createFn: func(appVersionEvent *models.AppVersionEvent) (*models.AppVersionEvent, error) {
+								return nil, nil
+							},
+						},
+						BitriseAPI: &testBitriseAPI{
+							getArtifactsFn: func(apiToken, appSlug, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
+								return []bitrise.ArtifactListElementResponseModel{
+									bitrise.ArtifactListElementResponseModel{
+										Title: "tv.xcarchive.zip",
+										ArtifactMeta: &bitrise.ArtifactMeta{
+											AppInfo: bitrise.AppInfo{
+												Version:          "1.0",
+												BundleID:         "io.bitrise.tv",
+												DeviceFamilyList: []int{3},
+											},
+										},
+									},
+								}, nil
+							},
+							getAppDetailsFn: func(apiToken, appSlug string) (*bitrise.AppDetails, error) {
+								return &bitrise.AppDetails{}, nil
+							},
+							getBuildDetailsFn: func(apiToken string, appSlug string, buildSlug string) (*bitrise.BuildDetails, error) {
+								return &bitrise.BuildDetails{}, nil
+							},
+						},
+						AppContactService: &testAppContactService{
+							findAllFn: func(app *models.App) ([]models.AppContact, error) {
+								return []models.AppContact{}, nil
+							},
+						},
+						Mailer: &testMailer{
+							sendEmailNewVersionFn: func(appVersion *models.AppVersion, contacts []models.AppContact, frontendBaseURL string, appDetails *bitrise.AppDetails) error {
+								return nil
+							},
+						},
+						AnalyticsClient: &testAnalyticsClient{
+							firstVersionCreatedFn: func(appSlug, buildSlug, platform string) {
+								require.Equal(t, "tvos", platform)
+							},
+						},
+						WorkerService: &testWorkerService{},
+					},
+					requestBody:        `{"build_slug":"test-build-slug"}`,
+					expectedStatusCode: http.StatusOK,
+				})
+			})
+
+			t.Run("ok - when the build has an archive per bundle ID", func(t *testing.T) {
+				mainAppVersionID := uuid.FromStringOrNil("0c6d8d4a-4d0e-4a46-9c5c-3a1f6a0d6f11")
+				whiteLabelAppVersionID := uuid.FromStringOrNil("7a0f2cc5-1f3f-4b9e-8a57-4c6c3d1f0f22")
+				createdBundleIDs := []string{}
+				latestConditions := []models.AppVersion{}
+				copiedUploadables := map[string]string{}
+				performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+					contextElements: map[ctxpkg.RequestContextKey]interface{}{
+						services.ContextKeyAuthorizedAppID: uuid.NewV4(),
+					},
+					requestHeaders: map[string]string{"Bitrise-Event-Type": "build/finished"},
+					env: &env.AppEnv{
+						AddonFrontendHostURL: "https://ship.bitrise.io",
+						AppService: &testAppService{
+							findFn: func(app *models.App) (*models.App, error) {
+								return app, nil
+							},
+						},
+						AppSettingsService: &testAppSettingsService{
+							findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+								return &models.AppSettings{
+									App: &models.App{
+										BitriseAPIToken: "test-api-token",
+										AppSlug:         "test-app-slug",
+									},
+								}, nil
+							},
+						},
+						AppVersionService: &testAppVersionService{
+							createFn: func(appVersion *models.AppVersion) (*models.AppVersion, []error, error) {
+								createdBundleIDs = append(createdBundleIDs, appVersion.BundleID)
+								artifactData, err := appVersion.ArtifactInfo()
+								require.NoError(t, err)
+								require.Equal(t, appVersion.BundleID, artifactData.BundleID)
+								switch appVersion.BundleID {
+								case "io.bitrise.app":
+									require.Equal(t, "main-scheme", appVersion.Scheme)
+									require.Equal(t, `{"whats_new":"Main app"}`, string(appVersion.AppStoreInfoData))
+									appVersion.ID = mainAppVersionID
+								case "io.bitrise.white-label":
+									require.Equal(t, "white-label-scheme", appVersion.Scheme)
+									require.Nil(t, appVersion.AppStoreInfoData)
+									appVersion.ID = whiteLabelAppVersionID
+								}
+								return appVersion, nil, nil
+							},
+							latestFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+								latestConditions = append(latestConditions, *appVersion)
+								if appVersion.BundleID == "io.bitrise.white-label" {
+									return nil, gorm.ErrRecordNotFound
+								}
+								appVersion.ID = testAppVersion2ID
+								appVersion.AppStoreInfoData = json.RawMessage(`{"whats_new":"Main app"}`)
+								return appVersion, nil
+							},
+						},
+						ReleaseService: releaseService,
+						AppVersionEventService: &testAppVersionEventService{
+							createFn: func(appVersionEvent *models.AppVersionEvent) (*models.AppVersionEvent, error) {
+								return nil, nil
+							},
+						},
+						BitriseAPI: &testBitriseAPI{
+							getArtifactsFn: func(apiToken, appSlug, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
+								return []bitrise.ArtifactListElementResponseModel{
+									bitrise.ArtifactListElementResponseModel{
+										Title: "main.xcarchive.zip",
+										ArtifactMeta: &bitrise.ArtifactMeta{
+											AppInfo: bitrise.AppInfo{
+												Version:     "1.0",
+												BuildNumber: "12",
+												BundleID:    "io.bitrise.app",
+											},
+											Scheme: "main-scheme",
+										},
+									},
+									bitrise.ArtifactListElementResponseModel{
+										Title: "white-label.xcarchive.zip",
+										ArtifactMeta: &bitrise.ArtifactMeta{
+											AppInfo: bitrise.AppInfo{
+												Version:     "2.0",
+												BuildNumber: "12",
+												BundleID:    "io.bitrise.white-label",
+											},
+											Scheme: "white-label-scheme",
+										},
+									},
+								}, nil
+							},
+							getAppDetailsFn: func(apiToken, appSlug string) (*bitrise.AppDetails, error) {
+								return &bitrise.AppDetails{Title: "My awesome app"}, nil
+							},
+							getBuildDetailsFn: func(apiToken string, appSlug string, buildSlug string) (*bitrise.BuildDetails, error) {
+								return &bitrise.BuildDetails{}, nil
+							},
+						},
+						AppContactService: &testAppContactService{
+							findAllFn: func(app *models.App) ([]models.AppContact, error) {
+								return []models.AppContact{}, nil
+							},
+						},
+						Mailer: &testMailer{
+							sendEmailNewVersionFn: func(appVersion *models.AppVersion, contacts []models.AppContact, frontendBaseURL string, appDetails *bitrise.AppDetails) error {
+								return nil
+							},
+						},
+						WorkerService: &testWorkerService{
+							enqueueCopyUploadablesToNewAppVersionFn: func(fromID, toID string) error {
+								copiedUploadables[toID] = fromID
+								return nil
+							},
+						},
+					},
+					requestBody:        `{"build_slug":"test-build-slug","build_number":12}`,
+					expectedStatusCode: http.StatusOK,
+				})
+				require.Equal(t, []string{"io.bitrise.app", "io.bitrise.white-label"}, createdBundleIDs)
+				require.Len(t, latestConditions, 2)
+				require.Equal(t, "io.bitrise.app", latestConditions[0].BundleID)
+				require.Equal(t, "", latestConditions[0].Scheme)
+				require.Equal(t, map[string]string{mainAppVersionID.String(): testAppVersion2ID.String()}, copiedUploadables)
+			})
+
+			t.Run("ok - more complex - when triggered workflow is whitelisted for iOS", func(t *testing.T) {
+				performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+					contextElements: map[ctxpkg.RequestContextKey]interface{}{
+						services.ContextKeyAuthorizedAppID: uuid.NewV4(),
+					},
+					requestHeaders: map[string]string{"Bitrise-Event-Type": "build/finished"},
+					env: &env.AppEnv{
+						AppService: &testAppService{
+							findFn: func(app *models.App) (*models.App, error) {
+								return app, nil
+							},
+						},
+						AppSettingsService: &testAppSettingsService{
+							findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+								return &models.AppSettings{
+									IosWorkflow:     "ios-wf,ios-wf2",
+									AndroidWorkflow: "some-android-wf",
+									App: &models.App{
+										BitriseAPIToken: "test-api-token",
+										AppSlug:         "test-app-slug",
+									},
+								}, nil
+							},
+						},
+						AppVersionService: &testAppVersionService{
+							createFn: func(appVersion *models.AppVersion) (*models.AppVersion, []error, error) {
+								require.Equal(t, "ios", appVersion.Platform)
+								require.Equal(t, "test-build-slug", appVersion.BuildSlug)
+								artifactData, err := appVersion.ArtifactInfo()
+								require.NoError(t, err)
+								require.Equal(t, "1.0", artifactData.Version)
+								require.NotEqual(t, time.Time{}, appVersion.LastUpdate)
+								appVersion.ID = testAppVersionID
+								return appVersion, nil, nil
+							},
+							latestFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+								require.Equal(t, "ios", appVersion.Platform)
+								appVersion.ID = testAppVersion2ID
+								return appVersion, nil
+							},
+						},
+						ReleaseService: releaseService,
+						AppVersionEventService: &testAppVersionEventService{
+							createFn: func(appVersionEvent *models.AppVersionEvent) (*models.AppVersionEvent, error) {
+								require.Equal(t, testAppVersionID, appVersionEvent.AppVersionID)
+								require.Equal(t, "New version was created", appVersionEvent.Text)
+								return nil, nil
+							},
+						},
+						BitriseAPI: &testBitriseAPI{
+							getArtifactsFn: func(apiToken, appSlug, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
+								require.Equal(t, "test-api-token", apiToken)
+								require.Equal(t, "test-app-slug", appSlug)
+								require.Equal(t, "test-build-slug", buildSlug)
+								return []bitrise.ArtifactListElementResponseModel{
+									bitrise.ArtifactListElementResponseModel{
+										Title: "my-ios-artifact.ipa",
+										ArtifactMeta: &bitrise.ArtifactMeta{
+											AppInfo: bitrise.AppInfo{
+												Version: "1.0",
+											},
+											ProvisioningInfo: bitrise.ProvisioningInfo{IPAExportMethod: "app-store"},
+										},
+									},
+									bitrise.ArtifactListElementResponseModel{
+										Title: "my-ios-artifact.xcarchive.zip",
+										ArtifactMeta: &bitrise.ArtifactMeta{
+											AppInfo: bitrise.AppInfo{
+												Version:          "1.0",
+												DeviceFamilyList: []int{1, 2, 12},
+											},
+											ProvisioningInfo: bitrise.ProvisioningInfo{IPAExportMethod: "app-store"},
+										},
+									},
+								}, nil
+							},
+							getAppDetailsFn: func(apiToken, appSlug string) (*bitrise.AppDetails, error) {
+								return &bitrise.AppDetails{}, nil
+							},
+							getBuildDetailsFn: func(apiToken string, appSlug string, buildSlug string) (*bitrise.BuildDetails, error) {
+								require.Equal(t, "test-api-token", apiToken)
+								require.Equal(t, "test-app-slug", appSlug)
+								require.Equal(t, "test-build-slug", buildSlug)
+								return &bitrise.BuildDetails{CommitMessage: "The detailed commit message"}, nil
+							},
+						},
+						AppContactService: &testAppContactService{
+							findAllFn: func(app *models.App) ([]models.AppContact, error) {
+								return []models.AppContact{}, nil
+							},
+						},
+						Mailer: &testMailer{
+							sendEmailNewVersionFn: func(appVersion *models.AppVersion, contacts []models.AppContact, frontendBaseURL string, appDetails *bitrise.AppDetails) error {
+								return nil
+							},
+						},
+						WorkerService: &testWorkerService{
+							enqueueCopyUploadablesToNewAppVersionFn: func(fromID, toID string) error {
+								require.Equal(t, testAppVersion2ID.String(), fromID)
+								require.Equal(t, testAppVersionID.String(), toID)
+								return nil
+							},
+						},
+					},
+					requestBody:        `{"build_slug":"test-build-slug","build_triggered_workflow":"ios-wf"}`,
+					expectedStatusCode: http.StatusOK,
+				})
+			})
+
+			t.Run("ok - when triggered workflow only contains a whitelisted one for iOS", func(t *testing.T) {
+				performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+					contextElements: map[ctxpkg.RequestContextKey]interface{}{
+						services.ContextKeyAuthorizedAppID: uuid.NewV4(),
+					},
+					requestHeaders: map[string]string{"Bitrise-Event-Type": "build/finished"},
+					env: &env.AppEnv{
+						AppService: &testAppService{
+							findFn: func(app *models.App) (*models.App, error) {
+								return app, nil
+							},
+						},
+						AppSettingsService: &testAppSettingsService{
+							findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+								return &models.AppSettings{
+									IosWorkflow: "deploy",
+									App: &models.App{
+										BitriseAPIToken: "test-api-token",
+										AppSlug:         "test-app-slug",
+									},
+								}, nil
+							},
+						},
+						AppVersionService: &testAppVersionService{
+							createFn: func(appVersion *models.AppVersion) (*models.AppVersion, []error, error) {
+								t.Fatal("No version should be created")
+								return nil, nil, nil
+							},
+							latestFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+								return nil, gorm.ErrRecordNotFound
+							},
+						},
+						ReleaseService:         releaseService,
+						AppVersionEventService: &testAppVersionEventService{},
+						BitriseAPI: &testBitriseAPI{
+							getArtifactsFn: func(apiToken, appSlug, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
+								return []bitrise.ArtifactListElementResponseModel{
+									bitrise.ArtifactListElementResponseModel{
+										Title: "my-ios-artifact.xcarchive.zip",
+										ArtifactMeta: &bitrise.ArtifactMeta{
+											AppInfo:          bitrise.AppInfo{Version: "1.0"},
+											ProvisioningInfo: bitrise.ProvisioningInfo{IPAExportMethod: "app-store"},
+										},
+									},
+								}, nil
+							},
+							getAppDetailsFn: func(apiToken, appSlug string) (*bitrise.AppDetails, error) {
+								return &bitrise.AppDetails{}, nil
+							},
+							getBuildDetailsFn: func(apiToken string, appSlug string, buildSlug string) (*bitrise.BuildDetails, error) {
+								return &bitrise.BuildDetails{Branch: "master"}, nil
+							},
+						},
+						AppContactService: &testAppContactService{},
+						WorkerService:     &testWorkerService{},
+					},
+					requestBody:        `{"build_slug":"test-build-slug","build_triggered_workflow":"deploy-staging"}`,
+					expectedStatusCode: http.StatusOK,
+				})
+			})
+
+			t.Run("ok - when the branch doesn't match the iOS version rules", func(t *testing.T) {
+				performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+					contextElements: map[ctxpkg.RequestContextKey]interface{}{
+						services.ContextKeyAuthorizedAppID: uuid.NewV4(),
+					},
+					requestHeaders: map[string]string{"Bitrise-Event-Type": "build/finished"},
+					env: &env.AppEnv{
+						AppService: &testAppService{
+							findFn: func(app *models.App) (*models.App, error) {
+								return app, nil
+							},
+						},
+						AppSettingsService: &testAppSettingsService{
+							findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+								return &models.AppSettings{
+									VersionRulesData: json.RawMessage(`{"ios":{"workflows":["deploy-*"],"branch":"release/*"}}`),
+									App: &models.App{
+										BitriseAPIToken: "test-api-token",
+										AppSlug:         "test-app-slug",
+									},
+								}, nil
+							},
+						},
+						AppVersionService: &testAppVersionService{
+							createFn: func(appVersion *models.AppVersion) (*models.AppVersion, []error, error) {
+								t.Fatal("No version should be created")
+								return nil, nil, nil
+							},
+							latestFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+								return nil, gorm.ErrRecordNotFound
+							},
+						},
+						ReleaseService:         releaseService,
+						AppVersionEventService: &testAppVersionEventService{},
+						BitriseAPI: &testBitriseAPI{
+							getArtifactsFn: func(apiToken, appSlug, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
+								return []bitrise.ArtifactListElementResponseModel{
+									bitrise.ArtifactListElementResponseModel{
+										Title: "my-ios-artifact.xcarchive.zip",
+										ArtifactMeta: &bitrise.ArtifactMeta{
+											AppInfo:          bitrise.AppInfo{Version: "1.0"},
+											ProvisioningInfo: bitrise.ProvisioningInfo{IPAExportMethod: "app-store"},
+										},
+									},
+								}, nil
+							},
+							getAppDetailsFn: func(apiToken, appSlug string) (*bitrise.AppDetails, error) {
+								return &bitrise.AppDetails{}, nil
+							},
+							getBuildDetailsFn: func(apiToken string, appSlug string, buildSlug string) (*bitrise.BuildDetails, error) {
+								return &bitrise.BuildDetails{Branch: "master"}, nil
+							},
+						},
+						AppContactService: &testAppContactService{},
+						WorkerService:     &testWorkerService{},
+					},
+					requestBody:        `{"build_slug":"test-build-slug","build_triggered_workflow":"deploy-staging"}`,
+					expectedStatusCode: http.StatusOK,
+				})
+			})
+
+			t.Run("ok - when the iOS version rules only allow tagged builds", func(t *testing.T) {
+				performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+					contextElements: map[ctxpkg.RequestContextKey]interface{}{
+						services.ContextKeyAuthorizedAppID: uuid.NewV4(),
+					},
+					requestHeaders: map[string]string{"Bitrise-Event-Type": "build/finished"},
+					env: &env.AppEnv{
+						AppService: &testAppService{
+							findFn: func(app *models.App) (*models.App, error) {
+								return app, nil
+							},
+						},
+						AppSettingsService: &testAppSettingsService{
+							findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+								return &models.AppSettings{
+									VersionRulesData: json.RawMessage(`{"ios":{"tags_only":true}}`),
+									App: &models.App{
+										BitriseAPIToken: "test-api-token",
+										AppSlug:         "test-app-slug",
+									},
+								}, nil
+							},
+						},
+						AppVersionService: &testAppVersionService{
+							createFn: func(appVersion *models.AppVersion) (*models.AppVersion, []error, error) {
+								t.Fatal("No version should be created")
+								return nil, nil, nil
+							},
+							latestFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+								return nil, gorm.ErrRecordNotFound
+							},
+						},
+						ReleaseService:         releaseService,
+						AppVersionEventService: &testAppVersionEventService{},
+						BitriseAPI: &testBitriseAPI{
+							getArtifactsFn: func(apiToken, appSlug, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
+								return []bitrise.ArtifactListElementResponseModel{
+									bitrise.ArtifactListElementResponseModel{
+										Title: "my-ios-artifact.xcarchive.zip",
+										ArtifactMeta: &bitrise.ArtifactMeta{
+											AppInfo:          bitrise.AppInfo{Version: "1.0"},
+											ProvisioningInfo: bitrise.ProvisioningInfo{IPAExportMethod: "app-store"},
+										},
+									},
+								}, nil
+							},
+							getAppDetailsFn: func(apiToken, appSlug string) (*bitrise.AppDetails, error) {
+								return &bitrise.AppDetails{}, nil
+							},
+							getBuildDetailsFn: func(apiToken string, appSlug string, buildSlug string) (*bitrise.BuildDetails, error) {
+								return &bitrise.BuildDetails{Branch: "master"}, nil
+							},
+						},
+						AppContactService: &testAppContactService{},
+						WorkerService:     &testWorkerService{},
+					},
+					requestBody:        `{"build_slug":"test-build-slug","build_triggered_workflow":"deploy"}`,
+					expectedStatusCode: http.StatusOK,
+				})
+			})
+
+			t.Run("ok - when the version matches an auto-publish rule", func(t *testing.T) {
+				events := []string{}
+				autoPublishEnqueued := false
+				performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+					contextElements: map[ctxpkg.RequestContextKey]interface{}{
+						services.ContextKeyAuthorizedAppID: uuid.NewV4(),
+					},
+					requestHeaders: map[string]string{"Bitrise-Event-Type": "build/finished"},
+					env: &env.AppEnv{
+						AddonHostURL: "http://ship.addon.url",
+						AppService: &testAppService{
+							findFn: func(app *models.App) (*models.App, error) {
+								return app, nil
+							},
+						},
+						AppSettingsService: &testAppSettingsService{
+							findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+								return &models.AppSettings{
+									AutoPublishRulesData: json.RawMessage(`[{"name":"TestFlight","platform":"ios","workflow":"deploy","branch":"main"}]`),
+									App: &models.App{
+										BitriseAPIToken: "test-api-token",
+										AppSlug:         "test-app-slug",
+										APIToken:        "addon-access-token",
+									},
+								}, nil
+							},
+						},
+						AppVersionService: &testAppVersionService{
+							createFn: func(appVersion *models.AppVersion) (*models.AppVersion, []error, error) {
+								require.Equal(t, "ready", appVersion.Status)
+								appVersion.ID = testAppVersionID
+								return appVersion, nil, nil
+							},
+							latestFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+								return nil, gorm.ErrRecordNotFound
+							},
+						},
+						ReleaseService: releaseService,
+						AppVersionEventService: &testAppVersionEventService{
+							createFn: func(appVersionEvent *models.AppVersionEvent) (*models.AppVersionEvent, error) {
+								require.Equal(t, testAppVersionID, appVersionEvent.AppVersionID)
+								events = append(events, appVersionEvent.Status+": "+appVersionEvent.Text)
+								return appVersionEvent, nil
+							},
+						},
+						BitriseAPI: &testBitriseAPI{
+							getArtifactsFn: func(apiToken, appSlug, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
+								return []bitrise.ArtifactListElementResponseModel{
+									bitrise.ArtifactListElementResponseModel{
+										Slug:  "test-ipa-slug",
+										Title: "my-ios-artifact.ipa",
+										ArtifactMeta: &bitrise.ArtifactMeta{
+											AppInfo:          bitrise.AppInfo{Version: "1.0", BuildNumber: "12", DeviceFamilyList: []int{1}},
+											ProvisioningInfo: bitrise.ProvisioningInfo{IPAExportMethod: "app-store"},
+										},
+									},
+									bitrise.ArtifactListElementResponseModel{
+										Slug:  "test-artifact-slug",
+										Title: "my-ios-artifact.xcarchive.zip",
+										ArtifactMeta: &bitrise.ArtifactMeta{
+											AppInfo:          bitrise.AppInfo{Version: "1.0", BuildNumber: "12", DeviceFamilyList: []int{1}},
+											ProvisioningInfo: bitrise.ProvisioningInfo{IPAExportMethod: "app-store"},
+										},
+									},
+								}, nil
+							},
+							getAppDetailsFn: func(apiToken, appSlug string) (*bitrise.AppDetails, error) {
+								return &bitrise.AppDetails{}, nil
+							},
+							getBuildDetailsFn: func(apiToken string, appSlug string, buildSlug string) (*bitrise.BuildDetails, error) {
+								return &bitrise.BuildDetails{Branch: "main"}, nil
+							},
+						},
+						AnalyticsClient: &testAnalyticsClient{
+							firstVersionCreatedFn: func(appSlug, buildSlug, platform string) {},
 						},
-					},
-					AppVersionService:      &testAppVersionService{},
-					AppVersionEventService: &testAppVersionEventService{},
-					BitriseAPI:             &testBitriseAPI{},
-					AppContactService:      &testAppContactService{},
-					WorkerService:          &testWorkerService{},
-				},
-				requestBody:         `{}`,
-				expectedInternalErr: "SQL Error: SOME-SQL-ERROR",
-			})
-		})
-
-		t.Run("when app settings not found in database", func(t *testing.T) {
-			performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
-				contextElements: map[ctxpkg.RequestContextKey]interface{}{
-					services.ContextKeyAuthorizedAppID: uuid.NewV4(),
-				},
-				requestHeaders: map[string]string{"Bitrise-Event-Type": "build/finished"},
-				env: &env.AppEnv{
-					AppService: &testAppService{
-						findFn: func(app *models.App) (*models.App, error) {
-							return app, nil
+						AppContactService: &testAppContactService{
+							findAllFn: func(app *models.App) ([]models.AppContact, error) {
+								return []models.AppContact{}, nil
+							},
 						},
-					},
-					AppSettingsService: &testAppSettingsService{
-						findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
-							return nil, gorm.ErrRecordNotFound
+						Mailer: &testMailer{
+							sendEmailNewVersionFn: func(appVersion *models.AppVersion, contacts []models.AppContact, frontendBaseURL string, appDetails *bitrise.AppDetails) error {
+								return nil
+							},
+						},
+						WorkerService: &testWorkerService{
+							enqueueAutoPublishAppVersionFn: func(appVersionID uuid.UUID, workflow, branch, copyUploadablesFromID string) error {
+								require.Equal(t, testAppVersionID, appVersionID)
+								require.Equal(t, "deploy", workflow)
+								require.Equal(t, "main", branch)
+								require.Equal(t, "", copyUploadablesFromID)
+								autoPublishEnqueued = true
+								return nil
+							},
 						},
 					},
-					AppVersionService:      &testAppVersionService{},
-					AppVersionEventService: &testAppVersionEventService{},
-					BitriseAPI:             &testBitriseAPI{},
-					AppContactService:      &testAppContactService{},
-					WorkerService:          &testWorkerService{},
-				},
-				requestBody:        `{}`,
-				expectedStatusCode: http.StatusNotFound,
-				expectedResponse:   httpresponse.StandardErrorRespModel{Message: "Not Found"},
+					requestBody:        `{"build_slug":"test-build-slug","build_number":12,"build_triggered_workflow":"deploy"}`,
+					expectedStatusCode: http.StatusOK,
+				})
+				require.Equal(t, []string{
+					": New version was created",
+				}, events)
+				require.True(t, autoPublishEnqueued)
 			})
-		})
 
-		t.Run("when platform is ios", func(t *testing.T) {
-			testAppVersionID := uuid.FromStringOrNil("9f7b06d1-e736-42d3-94c3-c2bcfda0463c")
-			testAppVersion2ID := uuid.FromStringOrNil("f951e094-0ac1-4edf-ac8f-bb035dfd683c")
-			t.Run("ok - more complex - when ios workflow whitelist is empty", func(t *testing.T) {
+			t.Run("ok - when the version matching an auto-publish rule has a previous version", func(t *testing.T) {
+				events := []string{}
+				autoPublishEnqueued := false
+				testPreviousAppVersionID := uuid.FromStringOrNil("5d2b7a3e-8c41-4f6a-9e0d-1b3c5a7f9e21")
 				performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
 					contextElements: map[ctxpkg.RequestContextKey]interface{}{
 						services.ContextKeyAuthorizedAppID: uuid.NewV4(),
 					},
 					requestHeaders: map[string]string{"Bitrise-Event-Type": "build/finished"},
 					env: &env.AppEnv{
-						AddonFrontendHostURL: "https://ship.bitrise.io",
+						AddonHostURL: "http://ship.addon.url",
 						AppService: &testAppService{
 							findFn: func(app *models.App) (*models.App, error) {
 								return app, nil
@@ -213,127 +996,103 @@ func Test_BuildWebhookHandler(t *testing.T) {
 						AppSettingsService: &testAppSettingsService{
 							findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
 								return &models.AppSettings{
-									IosWorkflow:     "",
-									AndroidWorkflow: "some-android-wf",
+									AutoPublishRulesData: json.RawMessage(`[{"name":"TestFlight","platform":"ios","workflow":"deploy","branch":"main"}]`),
 									App: &models.App{
 										BitriseAPIToken: "test-api-token",
 										AppSlug:         "test-app-slug",
+										APIToken:        "addon-access-token",
 									},
 								}, nil
 							},
 						},
 						AppVersionService: &testAppVersionService{
 							createFn: func(appVersion *models.AppVersion) (*models.AppVersion, []error, error) {
-								require.Equal(t, "ios", appVersion.Platform)
-								require.Equal(t, "test-build-slug", appVersion.BuildSlug)
-								require.NotEqual(t, time.Time{}, appVersion.LastUpdate)
-								require.Equal(t, "12", appVersion.BuildNumber)
-								require.Equal(t, "The detailed commit message", appVersion.CommitMessage)
-								require.Equal(t, "test-scheme", appVersion.Scheme)
-								artifactData, err := appVersion.ArtifactInfo()
-								require.NoError(t, err)
-								require.Equal(t, "1.0", artifactData.Version)
-								require.Equal(t, []string{"iPhone", "iPod Touch", "iPad", "Unknown"}, artifactData.SupportedDeviceTypes)
+								require.Equal(t, "ready", appVersion.Status)
 								appVersion.ID = testAppVersionID
-								appVersion.App = models.App{
-									BitriseAPIToken: "test-api-token",
-									AppSlug:         "test-app-slug",
-								}
 								return appVersion, nil, nil
 							},
 							latestFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
-								require.Equal(t, "ios", appVersion.Platform)
-								appVersion.ID = testAppVersion2ID
-								return appVersion, nil
+								return &models.AppVersion{Record: models.Record{ID: testPreviousAppVersionID}, Platform: "ios"}, nil
 							},
 						},
+						ReleaseService: releaseService,
 						AppVersionEventService: &testAppVersionEventService{
 							createFn: func(appVersionEvent *models.AppVersionEvent) (*models.AppVersionEvent, error) {
 								require.Equal(t, testAppVersionID, appVersionEvent.AppVersionID)
-								require.Equal(t, "New version was created", appVersionEvent.Text)
-								return nil, nil
+								events = append(events, appVersionEvent.Status+": "+appVersionEvent.Text)
+								return appVersionEvent, nil
 							},
 						},
 						BitriseAPI: &testBitriseAPI{
 							getArtifactsFn: func(apiToken, appSlug, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
-								require.Equal(t, "test-api-token", apiToken)
-								require.Equal(t, "test-app-slug", appSlug)
-								require.Equal(t, "test-build-slug", buildSlug)
 								return []bitrise.ArtifactListElementResponseModel{
 									bitrise.ArtifactListElementResponseModel{
+										Slug:  "test-ipa-slug",
 										Title: "my-ios-artifact.ipa",
 										ArtifactMeta: &bitrise.ArtifactMeta{
-											AppInfo: bitrise.AppInfo{
-												Version:          "1.0",
-												DeviceFamilyList: []int{1, 2, 12},
-											},
+											AppInfo:          bitrise.AppInfo{Version: "1.0", BuildNumber: "12", DeviceFamilyList: []int{1}},
 											ProvisioningInfo: bitrise.ProvisioningInfo{IPAExportMethod: "app-store"},
 										},
 									},
 									bitrise.ArtifactListElementResponseModel{
+										Slug:  "test-artifact-slug",
 										Title: "my-ios-artifact.xcarchive.zip",
 										ArtifactMeta: &bitrise.ArtifactMeta{
-											AppInfo: bitrise.AppInfo{
-												Version:          "1.0",
-												BuildNumber:      "12",
-												DeviceFamilyList: []int{1, 2, 12},
-											},
+											AppInfo:          bitrise.AppInfo{Version: "1.0", BuildNumber: "12", DeviceFamilyList: []int{1}},
 											ProvisioningInfo: bitrise.ProvisioningInfo{IPAExportMethod: "app-store"},
-											Scheme:           "test-scheme",
 										},
 									},
 								}, nil
 							},
 							getAppDetailsFn: func(apiToken, appSlug string) (*bitrise.AppDetails, error) {
-								require.Equal(t, "test-api-token", apiToken)
-								require.Equal(t, "test-app-slug", appSlug)
-								return &bitrise.AppDetails{Title: "My awesome app"}, nil
+								return &bitrise.AppDetails{}, nil
 							},
 							getBuildDetailsFn: func(apiToken string, appSlug string, buildSlug string) (*bitrise.BuildDetails, error) {
-								require.Equal(t, "test-api-token", apiToken)
-								require.Equal(t, "test-app-slug", appSlug)
-								require.Equal(t, "test-build-slug", buildSlug)
-								return &bitrise.BuildDetails{CommitMessage: "The detailed commit message"}, nil
+								return &bitrise.BuildDetails{Branch: "main"}, nil
 							},
 						},
+						AnalyticsClient: &testAnalyticsClient{
+							firstVersionCreatedFn: func(appSlug, buildSlug, platform string) {},
+						},
 						AppContactService: &testAppContactService{
 							findAllFn: func(app *models.App) ([]models.AppContact, error) {
-								require.Equal(t, "test-app-slug", app.AppSlug)
-								return []models.AppContact{
-									models.AppContact{Email: "the.address@we.send"},
-								}, nil
+								return []models.AppContact{}, nil
 							},
 						},
 						Mailer: &testMailer{
 							sendEmailNewVersionFn: func(appVersion *models.AppVersion, contacts []models.AppContact, frontendBaseURL string, appDetails *bitrise.AppDetails) error {
-								require.Equal(t, testAppVersionID, appVersion.ID)
-								require.Equal(t, "My awesome app", appDetails.Title)
-								require.Len(t, contacts, 1)
-								require.Equal(t, "the.address@we.send", contacts[0].Email)
-								require.Equal(t, "https://ship.bitrise.io", frontendBaseURL)
 								return nil
 							},
 						},
 						WorkerService: &testWorkerService{
-							enqueueCopyUploadablesToNewAppVersionFn: func(fromID, toID string) error {
-								require.Equal(t, testAppVersion2ID.String(), fromID)
-								require.Equal(t, testAppVersionID.String(), toID)
+							enqueueAutoPublishAppVersionFn: func(appVersionID uuid.UUID, workflow, branch, copyUploadablesFromID string) error {
+								require.Equal(t, testAppVersionID, appVersionID)
+								require.Equal(t, "deploy", workflow)
+								require.Equal(t, "main", branch)
+								require.Equal(t, testPreviousAppVersionID.String(), copyUploadablesFromID)
+								autoPublishEnqueued = true
 								return nil
 							},
 						},
 					},
-					requestBody:        `{"build_slug":"test-build-slug","build_number":12}`,
+					requestBody:        `{"build_slug":"test-build-slug","build_number":12,"build_triggered_workflow":"deploy"}`,
 					expectedStatusCode: http.StatusOK,
 				})
+				require.Equal(t, []string{
+					": New version was created",
+				}, events)
+				require.True(t, autoPublishEnqueued)
 			})
 
-			t.Run("ok - more complex - when triggered workflow is whitelisted for iOS", func(t *testing.T) {
+			t.Run("ok - when the version doesn't match an auto-publish rule", func(t *testing.T) {
+				events := []string{}
 				performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
 					contextElements: map[ctxpkg.RequestContextKey]interface{}{
 						services.ContextKeyAuthorizedAppID: uuid.NewV4(),
 					},
 					requestHeaders: map[string]string{"Bitrise-Event-Type": "build/finished"},
 					env: &env.AppEnv{
+						AddonHostURL: "http://ship.addon.url",
 						AppService: &testAppService{
 							findFn: func(app *models.App) (*models.App, error) {
 								return app, nil
@@ -342,61 +1101,58 @@ func Test_BuildWebhookHandler(t *testing.T) {
 						AppSettingsService: &testAppSettingsService{
 							findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
 								return &models.AppSettings{
-									IosWorkflow:     "ios-wf,ios-wf2",
-									AndroidWorkflow: "some-android-wf",
+									AutoPublishRulesData: json.RawMessage(`[{"name":"TestFlight","platform":"ios","workflow":"deploy","branch":"main"}]`),
 									App: &models.App{
 										BitriseAPIToken: "test-api-token",
 										AppSlug:         "test-app-slug",
+										APIToken:        "addon-access-token",
 									},
 								}, nil
 							},
 						},
 						AppVersionService: &testAppVersionService{
 							createFn: func(appVersion *models.AppVersion) (*models.AppVersion, []error, error) {
-								require.Equal(t, "ios", appVersion.Platform)
-								require.Equal(t, "test-build-slug", appVersion.BuildSlug)
-								artifactData, err := appVersion.ArtifactInfo()
-								require.NoError(t, err)
-								require.Equal(t, "1.0", artifactData.Version)
-								require.NotEqual(t, time.Time{}, appVersion.LastUpdate)
+								require.Equal(t, "ready", appVersion.Status)
 								appVersion.ID = testAppVersionID
 								return appVersion, nil, nil
 							},
 							latestFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
-								require.Equal(t, "ios", appVersion.Platform)
-								appVersion.ID = testAppVersion2ID
-								return appVersion, nil
+								return nil, gorm.ErrRecordNotFound
+							},
+							latestPublishedFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+								return nil, gorm.ErrRecordNotFound
+							},
+							updateFn: func(appVersion *models.AppVersion, whitelist []string) ([]error, error) {
+								require.Equal(t, testAppVersionID, appVersion.ID)
+								require.Equal(t, []string{"Status"}, whitelist)
+								require.Equal(t, "publishing", appVersion.Status)
+								return nil, nil
 							},
 						},
+						ReleaseService: releaseService,
 						AppVersionEventService: &testAppVersionEventService{
 							createFn: func(appVersionEvent *models.AppVersionEvent) (*models.AppVersionEvent, error) {
 								require.Equal(t, testAppVersionID, appVersionEvent.AppVersionID)
-								require.Equal(t, "New version was created", appVersionEvent.Text)
-								return nil, nil
+								events = append(events, appVersionEvent.Status+": "+appVersionEvent.Text)
+								return appVersionEvent, nil
 							},
 						},
 						BitriseAPI: &testBitriseAPI{
 							getArtifactsFn: func(apiToken, appSlug, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
-								require.Equal(t, "test-api-token", apiToken)
-								require.Equal(t, "test-app-slug", appSlug)
-								require.Equal(t, "test-build-slug", buildSlug)
 								return []bitrise.ArtifactListElementResponseModel{
 									bitrise.ArtifactListElementResponseModel{
+										Slug:  "test-ipa-slug",
 										Title: "my-ios-artifact.ipa",
 										ArtifactMeta: &bitrise.ArtifactMeta{
-											AppInfo: bitrise.AppInfo{
-												Version: "1.0",
-											},
+											AppInfo:          bitrise.AppInfo{Version: "1.0", BuildNumber: "12", DeviceFamilyList: []int{1}},
 											ProvisioningInfo: bitrise.ProvisioningInfo{IPAExportMethod: "app-store"},
 										},
 									},
 									bitrise.ArtifactListElementResponseModel{
+										Slug:  "test-artifact-slug",
 										Title: "my-ios-artifact.xcarchive.zip",
 										ArtifactMeta: &bitrise.ArtifactMeta{
-											AppInfo: bitrise.AppInfo{
-												Version:          "1.0",
-												DeviceFamilyList: []int{1, 2, 12},
-											},
+											AppInfo:          bitrise.AppInfo{Version: "1.0", BuildNumber: "12", DeviceFamilyList: []int{1}},
 											ProvisioningInfo: bitrise.ProvisioningInfo{IPAExportMethod: "app-store"},
 										},
 									},
@@ -406,12 +1162,30 @@ func Test_BuildWebhookHandler(t *testing.T) {
 								return &bitrise.AppDetails{}, nil
 							},
 							getBuildDetailsFn: func(apiToken string, appSlug string, buildSlug string) (*bitrise.BuildDetails, error) {
-								require.Equal(t, "test-api-token", apiToken)
-								require.Equal(t, "test-app-slug", appSlug)
-								require.Equal(t, "test-build-slug", buildSlug)
-								return &bitrise.BuildDetails{CommitMessage: "The detailed commit message"}, nil
+								return &bitrise.BuildDetails{Branch: "feature"}, nil
+							},
+							triggerDENTaskFn: func(params bitrise.TaskParams) (*bitrise.TriggerResponse, error) {
+								require.Equal(t, "resign_archive_app_store", params.Workflow)
+								return &bitrise.TriggerResponse{TaskIdentifier: testTaskIdentifier}, nil
+							},
+						},
+						PublishTaskService: &testPublishTaskService{
+							createFn: func(publishTask *models.PublishTask) (*models.PublishTask, error) {
+								require.Equal(t, testTaskIdentifier, publishTask.TaskID)
+								require.Equal(t, testAppVersionID, publishTask.AppVersionID)
+								require.Equal(t, "app-store-connect", publishTask.Destination)
+								return publishTask, nil
 							},
 						},
+						JWTService: &security.JWTMock{
+							SignFn: func(token string) (string, error) {
+								require.Equal(t, "addon-access-token", token)
+								return "jwt-token", nil
+							},
+						},
+						AnalyticsClient: &testAnalyticsClient{
+							firstVersionCreatedFn: func(appSlug, buildSlug, platform string) {},
+						},
 						AppContactService: &testAppContactService{
 							findAllFn: func(app *models.App) ([]models.AppContact, error) {
 								return []models.AppContact{}, nil
@@ -422,17 +1196,14 @@ func Test_BuildWebhookHandler(t *testing.T) {
 								return nil
 							},
 						},
-						WorkerService: &testWorkerService{
-							enqueueCopyUploadablesToNewAppVersionFn: func(fromID, toID string) error {
-								require.Equal(t, testAppVersion2ID.String(), fromID)
-								require.Equal(t, testAppVersionID.String(), toID)
-								return nil
-							},
-						},
+						WorkerService: &testWorkerService{},
 					},
-					requestBody:        `{"build_slug":"test-build-slug","build_triggered_workflow":"ios-wf"}`,
+					requestBody:        `{"build_slug":"test-build-slug","build_number":12,"build_triggered_workflow":"deploy"}`,
 					expectedStatusCode: http.StatusOK,
 				})
+				require.Equal(t, []string{
+					": New version was created",
+				}, events)
 			})
 
 			t.Run("when error happens at finding app settings in database", func(t *testing.T) {
@@ -453,6 +1224,7 @@ func Test_BuildWebhookHandler(t *testing.T) {
 							},
 						},
 						AppVersionService:      &testAppVersionService{},
+						ReleaseService:         releaseService,
 						AppVersionEventService: &testAppVersionEventService{},
 						BitriseAPI:             &testBitriseAPI{},
 						AppContactService:      &testAppContactService{},
@@ -502,6 +1274,7 @@ func Test_BuildWebhookHandler(t *testing.T) {
 								return appVersion, nil
 							},
 						},
+						ReleaseService: releaseService,
 						AppVersionEventService: &testAppVersionEventService{
 							createFn: func(appVersionEvent *models.AppVersionEvent) (*models.AppVersionEvent, error) {
 								return nil, nil
@@ -560,6 +1333,7 @@ func Test_BuildWebhookHandler(t *testing.T) {
 								return appVersion, nil
 							},
 						},
+						ReleaseService: releaseService,
 						AppVersionEventService: &testAppVersionEventService{
 							createFn: func(appVersionEvent *models.AppVersionEvent) (*models.AppVersionEvent, error) {
 								return nil, nil
@@ -621,6 +1395,7 @@ func Test_BuildWebhookHandler(t *testing.T) {
 								return appVersion, nil
 							},
 						},
+						ReleaseService: releaseService,
 						AppVersionEventService: &testAppVersionEventService{
 							createFn: func(appVersionEvent *models.AppVersionEvent) (*models.AppVersionEvent, error) {
 								return nil, nil
@@ -687,6 +1462,7 @@ func Test_BuildWebhookHandler(t *testing.T) {
 								return appVersion, nil
 							},
 						},
+						ReleaseService: releaseService,
 						AppVersionEventService: &testAppVersionEventService{
 							createFn: func(appVersionEvent *models.AppVersionEvent) (*models.AppVersionEvent, error) {
 								return nil, nil
@@ -757,6 +1533,7 @@ func Test_BuildWebhookHandler(t *testing.T) {
 								return appVersion, nil
 							},
 						},
+						ReleaseService: releaseService,
 						AppVersionEventService: &testAppVersionEventService{
 							createFn: func(appVersionEvent *models.AppVersionEvent) (*models.AppVersionEvent, error) {
 								return nil, nil
@@ -883,6 +1660,7 @@ func Test_BuildWebhookHandler(t *testing.T) {
 								return appVersion, nil
 							},
 						},
+						ReleaseService: releaseService,
 						AppVersionEventService: &testAppVersionEventService{
 							createFn: func(appVersionEvent *models.AppVersionEvent) (*models.AppVersionEvent, error) {
 								return nil, nil
@@ -960,6 +1738,82 @@ func Test_BuildWebhookHandler(t *testing.T) {
 								return appVersion, nil
 							},
 						},
+						ReleaseService: releaseService,
+						AppVersionEventService: &testAppVersionEventService{
+							createFn: func(appVersionEvent *models.AppVersionEvent) (*models.AppVersionEvent, error) {
+								return nil, nil
+							},
+						},
+						BitriseAPI: &testBitriseAPI{
+							getArtifactsFn: func(apiToken, appSlug, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
+								return []bitrise.ArtifactListElementResponseModel{
+									bitrise.ArtifactListElementResponseModel{
+										Title: "my-ios-artifact.ipa",
+										ArtifactMeta: &bitrise.ArtifactMeta{
+											AppInfo: bitrise.AppInfo{
+												Version: "1.0",
+											},
+											ProvisioningInfo: bitrise.ProvisioningInfo{IPAExportMethod: "app-store"},
+										},
+									},
+									bitrise.ArtifactListElementResponseModel{
+										Title: "my-ios-artifact.xcarchive.zip",
+										ArtifactMeta: &bitrise.ArtifactMeta{
+											AppInfo: bitrise.AppInfo{
+												Version: "1.0",
+											},
+											ProvisioningInfo: bitrise.ProvisioningInfo{IPAExportMethod: "app-store"},
+										},
+									},
+								}, nil
+							},
+							getAppDetailsFn: func(apiToken, appSlug string) (*bitrise.AppDetails, error) {
+								return &bitrise.AppDetails{}, nil
+							},
+							getBuildDetailsFn: func(apiToken string, appSlug string, buildSlug string) (*bitrise.BuildDetails, error) {
+								return &bitrise.BuildDetails{}, nil
+							},
+						},
+						AppContactService: &testAppContactService{},
+						WorkerService:     &testWorkerService{},
+					},
+					requestBody:         `{"build_slug":"test-build-slug"}`,
+					expectedInternalErr: "SQL Error: SOME-SQL-ERROR",
+				})
+			})
+
+			t.Run("when db error is retrieved when finding the release of the new ios version", func(t *testing.T) {
+				performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+					contextElements: map[ctxpkg.RequestContextKey]interface{}{
+						services.ContextKeyAuthorizedAppID: uuid.NewV4(),
+					},
+					requestHeaders: map[string]string{"Bitrise-Event-Type": "build/finished"},
+					env: &env.AppEnv{
+						AppService: &testAppService{
+							findFn: func(app *models.App) (*models.App, error) {
+								return app, nil
+							},
+						},
+						AppSettingsService: &testAppSettingsService{
+							findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+								return &models.AppSettings{IosWorkflow: "",
+									App: &models.App{
+										BitriseAPIToken: "test-api-token",
+										AppSlug:         "test-app-slug",
+									},
+								}, nil
+							},
+						},
+						AppVersionService: &testAppVersionService{
+							latestFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+								return appVersion, nil
+							},
+						},
+						ReleaseService: &testReleaseService{
+							findFn: func(release *models.Release) (*models.Release, error) {
+								return nil, errors.New("SOME-SQL-ERROR")
+							},
+						},
 						AppVersionEventService: &testAppVersionEventService{
 							createFn: func(appVersionEvent *models.AppVersionEvent) (*models.AppVersionEvent, error) {
 								return nil, nil
@@ -1039,6 +1893,7 @@ func Test_BuildWebhookHandler(t *testing.T) {
 								return nil, nil
 							},
 						},
+						ReleaseService: releaseService,
 						AppVersionEventService: &testAppVersionEventService{
 							createFn: func(appVersionEvent *models.AppVersionEvent) (*models.AppVersionEvent, error) {
 								return nil, errors.New("SOME-SQL-ERROR")
@@ -1132,6 +1987,7 @@ func Test_BuildWebhookHandler(t *testing.T) {
 								return nil, nil
 							},
 						},
+						ReleaseService: releaseService,
 						AppVersionEventService: &testAppVersionEventService{
 							createFn: func(appVersionEvent *models.AppVersionEvent) (*models.AppVersionEvent, error) {
 								return nil, nil
@@ -1247,6 +2103,7 @@ func Test_BuildWebhookHandler(t *testing.T) {
 								return nil, nil
 							},
 						},
+						ReleaseService: releaseService,
 						AppVersionEventService: &testAppVersionEventService{
 							createFn: func(appVersionEvent *models.AppVersionEvent) (*models.AppVersionEvent, error) {
 								return nil, nil
@@ -1349,6 +2206,8 @@ func Test_BuildWebhookHandler(t *testing.T) {
 								require.Equal(t, "test-build-slug", appVersion.BuildSlug)
 								require.Equal(t, "Some commit message", appVersion.CommitMessage)
 								require.Equal(t, "test-product-flavor", appVersion.ProductFlavor)
+								require.Equal(t, &testReleaseID, appVersion.ReleaseID)
+								require.Equal(t, "draft", appVersion.Status)
 								appInfo, err := appVersion.ArtifactInfo()
 								require.NoError(t, err)
 								require.Equal(t, models.ArtifactInfo{
@@ -1369,6 +2228,17 @@ func Test_BuildWebhookHandler(t *testing.T) {
 								return appVersion, nil
 							},
 						},
+						ReleaseService: &testReleaseService{
+							findFn: func(release *models.Release) (*models.Release, error) {
+								if release.BuildSlug != "" {
+									require.Equal(t, "test-build-slug", release.BuildSlug)
+									return nil, gorm.ErrRecordNotFound
+								}
+								require.Equal(t, "1.0", release.Version)
+								release.ID = testReleaseID
+								return release, nil
+							},
+						},
 						AppVersionEventService: &testAppVersionEventService{
 							createFn: func(appVersionEvent *models.AppVersionEvent) (*models.AppVersionEvent, error) {
 								require.Equal(t, testAppVersionID, appVersionEvent.AppVersionID)
@@ -1476,6 +2346,7 @@ func Test_BuildWebhookHandler(t *testing.T) {
 								return appVersion, nil
 							},
 						},
+						ReleaseService: releaseService,
 						AppVersionEventService: &testAppVersionEventService{
 							createFn: func(appVersionEvent *models.AppVersionEvent) (*models.AppVersionEvent, error) {
 								require.Equal(t, testAppVersionID, appVersionEvent.AppVersionID)
@@ -1574,6 +2445,7 @@ func Test_BuildWebhookHandler(t *testing.T) {
 								return appVersion, nil
 							},
 						},
+						ReleaseService: releaseService,
 						AppVersionEventService: &testAppVersionEventService{
 							createFn: func(appVersionEvent *models.AppVersionEvent) (*models.AppVersionEvent, error) {
 								require.Equal(t, testAppVersionID, appVersionEvent.AppVersionID)
@@ -1684,6 +2556,7 @@ func Test_BuildWebhookHandler(t *testing.T) {
 								return nil, nil
 							},
 						},
+						ReleaseService: releaseService,
 						AppVersionEventService: &testAppVersionEventService{
 							createFn: func(appVersionEvent *models.AppVersionEvent) (*models.AppVersionEvent, error) {
 								return nil, nil
@@ -1743,6 +2616,7 @@ func Test_BuildWebhookHandler(t *testing.T) {
 								return nil, nil
 							},
 						},
+						ReleaseService: releaseService,
 						AppVersionEventService: &testAppVersionEventService{
 							createFn: func(appVersionEvent *models.AppVersionEvent) (*models.AppVersionEvent, error) {
 								return nil, nil
@@ -1800,6 +2674,7 @@ func Test_BuildWebhookHandler(t *testing.T) {
 								return nil, nil
 							},
 						},
+						ReleaseService: releaseService,
 						AppVersionEventService: &testAppVersionEventService{
 							createFn: func(appVersionEvent *models.AppVersionEvent) (*models.AppVersionEvent, error) {
 								return nil, nil
@@ -1872,6 +2747,7 @@ func Test_BuildWebhookHandler(t *testing.T) {
 								return nil, nil
 							},
 						},
+						ReleaseService: releaseService,
 						AppVersionEventService: &testAppVersionEventService{
 							createFn: func(appVersionEvent *models.AppVersionEvent) (*models.AppVersionEvent, error) {
 								return nil, nil
@@ -1946,6 +2822,7 @@ func Test_BuildWebhookHandler(t *testing.T) {
 								return nil, nil
 							},
 						},
+						ReleaseService: releaseService,
 						AppVersionEventService: &testAppVersionEventService{
 							createFn: func(appVersionEvent *models.AppVersionEvent) (*models.AppVersionEvent, error) {
 								return nil, errors.New("SOME-SQL-ERROR")
@@ -2030,6 +2907,7 @@ func Test_BuildWebhookHandler(t *testing.T) {
 								return nil, nil
 							},
 						},
+						ReleaseService: releaseService,
 						AppVersionEventService: &testAppVersionEventService{
 							createFn: func(appVersionEvent *models.AppVersionEvent) (*models.AppVersionEvent, error) {
 								return nil, nil
@@ -2130,6 +3008,7 @@ func Test_BuildWebhookHandler(t *testing.T) {
 								return nil, nil
 							},
 						},
+						ReleaseService: releaseService,
 						AppVersionEventService: &testAppVersionEventService{
 							createFn: func(appVersionEvent *models.AppVersionEvent) (*models.AppVersionEvent, error) {
 								return nil, nil
@@ -17,6 +17,12 @@ const (
 	ContextKeyAuthorizedScreenshotID ctxpkg.RequestContextKey = "ctx-authorized-screenshot-id"
 	// ContextKeyAuthorizedAppContactID ...
 	ContextKeyAuthorizedAppContactID ctxpkg.RequestContextKey = "ctx-authorized-app-contact-id"
+	// ContextKeyAuthorizedReleaseID ...
+	ContextKeyAuthorizedReleaseID ctxpkg.RequestContextKey = "ctx-authorized-release-id"
+	// ContextKeyAuthorizedScheduledPublishID ...
+	ContextKeyAuthorizedScheduledPublishID ctxpkg.RequestContextKey = "ctx-authorized-scheduled-publish-id"
+	// ContextKeyAuthorizedApprovalID ...
+	ContextKeyAuthorizedApprovalID ctxpkg.RequestContextKey = "ctx-authorized-approval-id"
 )
 
 // GetAuthorizedAppIDFromContext ...
@@ -74,3 +80,45 @@ func GetAuthorizedAppContactIDFromContext(ctx context.Context) (uuid.UUID, error
 func ContextWithAuthorizedAppContactID(ctx context.Context, appContactID uuid.UUID) context.Context {
 	return context.WithValue(ctx, ContextKeyAuthorizedAppContactID, appContactID)
 }
+
+// GetAuthorizedReleaseIDFromContext ...
+func GetAuthorizedReleaseIDFromContext(ctx context.Context) (uuid.UUID, error) {
+	id, ok := ctx.Value(ContextKeyAuthorizedReleaseID).(uuid.UUID)
+	if !ok {
+		return uuid.UUID{}, errors.New("Authorized Release ID not found in Context")
+	}
+	return id, nil
+}
+
+// ContextWithAuthorizedReleaseID ...
+func ContextWithAuthorizedReleaseID(ctx context.Context, releaseID uuid.UUID) context.Context {
+	return context.WithValue(ctx, ContextKeyAuthorizedReleaseID, releaseID)
+}
+
+// GetAuthorizedScheduledPublishIDFromContext ...
+func GetAuthorizedScheduledPublishIDFromContext(ctx context.Context) (uuid.UUID, error) {
+	id, ok := ctx.Value(ContextKeyAuthorizedScheduledPublishID).(uuid.UUID)
+	if !ok {
+		return uuid.UUID{}, errors.New("Authorized Scheduled Publish ID not found in Context")
+	}
+	return id, nil
+}
+
+// ContextWithAuthorizedScheduledPublishID ...
+func ContextWithAuthorizedScheduledPublishID(ctx context.Context, scheduledPublishID uuid.UUID) context.Context {
+	return context.WithValue(ctx, ContextKeyAuthorizedScheduledPublishID, scheduledPublishID)
+}
+
+// GetAuthorizedApprovalIDFromContext ...
+func GetAuthorizedApprovalIDFromContext(ctx context.Context) (uuid.UUID, error) {
+	id, ok := ctx.Value(ContextKeyAuthorizedApprovalID).(uuid.UUID)
+	if !ok {
+		return uuid.UUID{}, errors.New("Authorized Approval ID not found in Context")
+	}
+	return id, nil
+}
+
+// ContextWithAuthorizedApprovalID ...
+func ContextWithAuthorizedApprovalID(ctx context.Context, approvalID uuid.UUID) context.Context {
+	return context.WithValue(ctx, ContextKeyAuthorizedApprovalID, approvalID)
+}
@@ -161,3 +161,79 @@ func Test_ContextWithAuthorizedAppContactID(t *testing.T) {
 		require.Equal(t, anotherTestUUID, contextWithValue.Value(services.ContextKeyAuthorizedAppContactID))
 	})
 }
+
+func Test_GetAuthorizedReleaseIDFromContext(t *testing.T) {
+	testUUID := uuid.NewV4()
+
+	t.Run("ok", func(t *testing.T) {
+		releaseID, err := services.GetAuthorizedReleaseIDFromContext(context.WithValue(context.Background(), services.ContextKeyAuthorizedReleaseID, testUUID))
+		require.NoError(t, err)
+		require.Equal(t, testUUID, releaseID)
+	})
+
+	t.Run("error - value is not an UUID", func(t *testing.T) {
+		releaseID, err := services.GetAuthorizedReleaseIDFromContext(context.WithValue(context.Background(), services.ContextKeyAuthorizedReleaseID, "17"))
+		require.Equal(t, "Authorized Release ID not found in Context", err.Error())
+		require.Equal(t, uuid.UUID{}, releaseID)
+	})
+
+	t.Run("error - wrong key", func(t *testing.T) {
+		releaseID, err := services.GetAuthorizedReleaseIDFromContext(context.WithValue(context.Background(), ctxpkg.RequestContextKey("WrongKey"), testUUID))
+		require.Equal(t, "Authorized Release ID not found in Context", err.Error())
+		require.Equal(t, uuid.UUID{}, releaseID)
+	})
+}
+
+func Test_ContextWithAuthorizedReleaseID(t *testing.T) {
+	testUUID := uuid.NewV4()
+	t.Run("ok", func(t *testing.T) {
+		contextWithValue := services.ContextWithAuthorizedReleaseID(context.Background(), testUUID)
+		expectedContext := context.WithValue(context.Background(), services.ContextKeyAuthorizedReleaseID, testUUID)
+		require.Equal(t, expectedContext, contextWithValue)
+	})
+
+	t.Run("ok - the last set value is the valid", func(t *testing.T) {
+		anotherTestUUID := uuid.NewV4()
+		previousContext := context.WithValue(context.Background(), services.ContextKeyAuthorizedReleaseID, testUUID)
+		contextWithValue := services.ContextWithAuthorizedReleaseID(previousContext, anotherTestUUID)
+		require.Equal(t, anotherTestUUID, contextWithValue.Value(services.ContextKeyAuthorizedReleaseID))
+	})
+}
+
+func Test_GetAuthorizedScheduledPublishIDFromContext(t *testing.T) {
+	testUUID := uuid.NewV4()
+
+	t.Run("ok", func(t *testing.T) {
+		scheduledPublishID, err := services.GetAuthorizedScheduledPublishIDFromContext(context.WithValue(context.Background(), services.ContextKeyAuthorizedScheduledPublishID, testUUID))
+		require.NoError(t, err)
+		require.Equal(t, testUUID, scheduledPublishID)
+	})
+
+	t.Run("error - value is not an UUID", func(t *testing.T) {
+		scheduledPublishID, err := services.GetAuthorizedScheduledPublishIDFromContext(context.WithValue(context.Background(), services.ContextKeyAuthorizedScheduledPublishID, "17"))
+		require.Equal(t, "Authorized Scheduled Publish ID not found in Context", err.Error())
+		require.Equal(t, uuid.UUID{}, scheduledPublishID)
+	})
+
+	t.Run("error - wrong key", func(t *testing.T) {
+		scheduledPublishID, err := services.GetAuthorizedScheduledPublishIDFromContext(context.WithValue(context.Background(), ctxpkg.RequestContextKey("WrongKey"), testUUID))
+		require.Equal(t, "Authorized Scheduled Publish ID not found in Context", err.Error())
+		require.Equal(t, uuid.UUID{}, scheduledPublishID)
+	})
+}
+
+func Test_ContextWithAuthorizedScheduledPublishID(t *testing.T) {
+	testUUID := uuid.NewV4()
+	t.Run("ok", func(t *testing.T) {
+		contextWithValue := services.ContextWithAuthorizedScheduledPublishID(context.Background(), testUUID)
+		expectedContext := context.WithValue(context.Background(), services.ContextKeyAuthorizedScheduledPublishID, testUUID)
+		require.Equal(t, expectedContext, contextWithValue)
+	})
+
+	t.Run("ok - the last set value is the valid", func(t *testing.T) {
+		anotherTestUUID := uuid.NewV4()
+		previousContext := context.WithValue(context.Background(), services.ContextKeyAuthorizedScheduledPublishID, testUUID)
+		contextWithValue := services.ContextWithAuthorizedScheduledPublishID(previousContext, anotherTestUUID)
+		require.Equal(t, anotherTestUUID, contextWithValue.Value(services.ContextKeyAuthorizedScheduledPublishID))
+	})
+}
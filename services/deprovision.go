@@ -28,7 +28,18 @@ func DeprovisionHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Request)
 		return errors.Wrap(err, "SQL Error")
 	}
 
-	err = env.AppService.Delete(&models.App{Record: models.Record{ID: authorizedAppID}})
+	if env.TimeService == nil {
+		return errors.New("No Time Service defined for handler")
+	}
+
+	// the app is only purged after the grace period, until then it can be
+	// restored by provisioning it again
+	deprovisionedAt := env.TimeService.Now()
+	app.DeprovisionedAt = &deprovisionedAt
+	verrs, err := env.AppService.Update(app, []string{"DeprovisionedAt"})
+	if len(verrs) > 0 {
+		return httpresponse.RespondWithUnprocessableEntity(w, verrs)
+	}
 	if err != nil {
 		return errors.Wrap(err, "SQL Error")
 	}
@@ -3,6 +3,7 @@ package services_test
 import (
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/bitrise-io/addons-ship-backend/env"
 	"github.com/bitrise-io/addons-ship-backend/models"
@@ -22,12 +23,20 @@ func Test_DeprovisionHandler(t *testing.T) {
 
 	testAppID := uuid.FromStringOrNil("211afc15-127a-40f9-8cbe-1dadc1f86cdf")
 
-	behavesAsServiceCravingHandler(t, httpMethod, url, handler, []string{"AppService"}, ControllerTestCase{
+	testTime := time.Date(2019, 11, 22, 10, 0, 0, 0, time.UTC)
+	testTimeService := &testTimeService{nowFn: func() time.Time { return testTime }}
+
+	behavesAsServiceCravingHandler(t, httpMethod, url, handler, []string{"AppService", "TimeService"}, ControllerTestCase{
 		contextElements: map[ctxpkg.RequestContextKey]interface{}{
 			services.ContextKeyAuthorizedAppID: uuid.NewV4(),
 		},
 		env: &env.AppEnv{
-			AppService: &testAppService{},
+			AppService: &testAppService{
+				findFn: func(app *models.App) (*models.App, error) {
+					return app, nil
+				},
+			},
+			TimeService: testTimeService,
 		},
 	})
 
@@ -51,14 +60,17 @@ func Test_DeprovisionHandler(t *testing.T) {
 						require.Equal(t, testAppID, app.ID)
 						return app, nil
 					},
-					deleteFn: func(app *models.App) error {
+					updateFn: func(app *models.App, whitelist []string) ([]error, error) {
+						require.Equal(t, []string{"DeprovisionedAt"}, whitelist)
 						require.Equal(t, testAppID, app.ID)
-						return nil
+						require.Equal(t, testTime, *app.DeprovisionedAt)
+						return nil, nil
 					},
 				},
+				TimeService: testTimeService,
 			},
 			expectedStatusCode: http.StatusOK,
-			expectedResponse:   models.App{Record: models.Record{ID: testAppID}},
+			expectedResponse:   models.App{Record: models.Record{ID: testAppID}, DeprovisionedAt: &testTime},
 		})
 	})
 
@@ -72,11 +84,8 @@ func Test_DeprovisionHandler(t *testing.T) {
 					findFn: func(app *models.App) (*models.App, error) {
 						return nil, gorm.ErrRecordNotFound
 					},
-					deleteFn: func(app *models.App) error {
-						require.Equal(t, testAppID, app.ID)
-						return nil
-					},
 				},
+				TimeService: testTimeService,
 			},
 			expectedStatusCode: http.StatusNotFound,
 			expectedResponse:   httpresponse.StandardErrorRespModel{Message: "Not Found"},
@@ -93,17 +102,14 @@ func Test_DeprovisionHandler(t *testing.T) {
 					findFn: func(app *models.App) (*models.App, error) {
 						return nil, errors.New("SOME-SQL-ERROR")
 					},
-					deleteFn: func(app *models.App) error {
-						require.Equal(t, testAppID, app.ID)
-						return nil
-					},
 				},
+				TimeService: testTimeService,
 			},
 			expectedInternalErr: "SQL Error: SOME-SQL-ERROR",
 		})
 	})
 
-	t.Run("when database error happens at find", func(t *testing.T) {
+	t.Run("when database error happens at update", func(t *testing.T) {
 		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
 			contextElements: map[ctxpkg.RequestContextKey]interface{}{
 				services.ContextKeyAuthorizedAppID: testAppID,
@@ -114,10 +120,11 @@ func Test_DeprovisionHandler(t *testing.T) {
 						require.Equal(t, testAppID, app.ID)
 						return app, nil
 					},
-					deleteFn: func(app *models.App) error {
-						return errors.New("SOME-SQL-ERROR")
+					updateFn: func(app *models.App, whitelist []string) ([]error, error) {
+						return nil, errors.New("SOME-SQL-ERROR")
 					},
 				},
+				TimeService: testTimeService,
 			},
 			expectedInternalErr: "SQL Error: SOME-SQL-ERROR",
 		})
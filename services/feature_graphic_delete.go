@@ -44,6 +44,9 @@ func FeatureGraphicDeleteHandler(env *env.AppEnv, w http.ResponseWriter, r *http
 	if err != nil {
 		return errors.Wrap(err, "SQL Error")
 	}
+	if err := invalidateApprovals(env, &models.AppVersion{Record: models.Record{ID: authorizedAppVersionID}}); err != nil {
+		return errors.WithStack(err)
+	}
 
 	return httpresponse.RespondWithSuccess(w, FeatureGraphicDeleteResponse{
 		Data: featureGraphic,
@@ -61,6 +61,11 @@ func Test_FeatureGraphicDeleteHandler(t *testing.T) {
 						return &models.FeatureGraphic{}, nil
 					},
 				},
+				ApprovalService: &testApprovalService{
+					invalidateAllFn: func(appVersion *models.AppVersion) (int, error) {
+						return 0, nil
+					},
+				},
 				AWS: &providers.AWSMock{
 					DeleteObjectFn: func(path string) error {
 						return nil
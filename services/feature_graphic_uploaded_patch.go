@@ -43,6 +43,10 @@ func FeatureGraphicUploadedPatchHandler(env *env.AppEnv, w http.ResponseWriter,
 	if env.AWS == nil {
 		return errors.New("No AWS Provider defined for handler")
 	}
+	// approvals were given to the previous feature graphic
+	if err := invalidateApprovals(env, &models.AppVersion{Record: models.Record{ID: authorizedAppVersionID}}); err != nil {
+		return errors.WithStack(err)
+	}
 	presignedURL, err := env.AWS.GeneratePresignedGETURL(featureGraphicToUpdate.AWSPath(), presignedURLExpirationInterval)
 	if err != nil {
 		return errors.WithStack(err)
@@ -6,9 +6,11 @@ import (
 )
 
 type testMailer struct {
-	sendEmailConfirmationFn func(confirmURL string, contact *models.AppContact, appDetails *bitrise.AppDetails) error
-	sendEmailNewVersionFn   func(appVersion *models.AppVersion, contacts []models.AppContact, frontendBaseURL string, appDetails *bitrise.AppDetails) error
-	sendEmailPublishFn      func(appVersion *models.AppVersion, contacts []models.AppContact, appDetails *bitrise.AppDetails, frontendBaseURL string, publishSucceeded bool) error
+	sendEmailConfirmationFn      func(confirmURL string, contact *models.AppContact, appDetails *bitrise.AppDetails) error
+	sendEmailNewVersionFn        func(appVersion *models.AppVersion, contacts []models.AppContact, frontendBaseURL string, appDetails *bitrise.AppDetails) error
+	sendEmailPublishFn           func(appVersion *models.AppVersion, contacts []models.AppContact, appDetails *bitrise.AppDetails, frontendBaseURL string, publishSucceeded bool) error
+	sendEmailCodeSigningExpiryFn func(app *models.App, contacts []models.AppContact, appDetails *bitrise.AppDetails, frontendBaseURL string, expiringFiles []models.CodeSigningFileExpiry) error
+	sendEmailApprovalRequestFn   func(appVersion *models.AppVersion, approval *models.Approval, appDetails *bitrise.AppDetails, frontendBaseURL string) error
 }
 
 func (m *testMailer) SendEmailConfirmation(confirmURL string, contact *models.AppContact, appDetails *bitrise.AppDetails) error {
@@ -31,3 +33,17 @@ func (m *testMailer) SendEmailPublish(appVersion *models.AppVersion, contacts []
 	}
 	return m.sendEmailPublishFn(appVersion, contacts, appDetails, frontendBaseURL, publishSucceeded)
 }
+
+func (m *testMailer) SendEmailCodeSigningExpiry(app *models.App, contacts []models.AppContact, appDetails *bitrise.AppDetails, frontendBaseURL string, expiringFiles []models.CodeSigningFileExpiry) error {
+	if m.sendEmailCodeSigningExpiryFn == nil {
+		panic("You have to override Mailer.SendEmailCodeSigningExpiry function in tests")
+	}
+	return m.sendEmailCodeSigningExpiryFn(app, contacts, appDetails, frontendBaseURL, expiringFiles)
+}
+
+func (m *testMailer) SendEmailApprovalRequest(appVersion *models.AppVersion, approval *models.Approval, appDetails *bitrise.AppDetails, frontendBaseURL string) error {
+	if m.sendEmailApprovalRequestFn == nil {
+		panic("You have to override Mailer.SendEmailApprovalRequest function in tests")
+	}
+	return m.sendEmailApprovalRequestFn(appVersion, approval, appDetails, frontendBaseURL)
+}
@@ -62,12 +62,30 @@ func createAuthorizeForAppContactEmailConfirmationMiddleware(env *env.AppEnv) fu
 	}
 }
 
+func createAuthorizeForApprovalMiddleware(env *env.AppEnv) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return AuthorizeForApprovalHandlerFunc(env, h)
+	}
+}
+
 func createAuthorizeForAppContactAccessMiddleware(env *env.AppEnv) func(http.Handler) http.Handler {
 	return func(h http.Handler) http.Handler {
 		return AuthorizeForAppContactAccessHandlerFunc(env, h)
 	}
 }
 
+func createAuthorizeForReleaseAccessMiddleware(env *env.AppEnv) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return AuthorizeForReleaseAccessHandlerFunc(env, h)
+	}
+}
+
+func createAuthorizeForAppVersionScheduledPublishAccessMiddleware(env *env.AppEnv) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return AuthorizeForAppVersionScheduledPublishAccessHandlerFunc(env, h)
+	}
+}
+
 func createAuthorizeForBuildWebhookMiddleware(env *env.AppEnv) func(http.Handler) http.Handler {
 	return func(h http.Handler) http.Handler {
 		return AuthorizeBuildWebhookForAppAccessFunc(env, h)
@@ -147,6 +165,13 @@ func AuthorizeForAppContactEmailConfirmationHandling(appEnv *env.AppEnv) alice.C
 	)
 }
 
+// AuthorizeForApprovalHandling ...
+func AuthorizeForApprovalHandling(appEnv *env.AppEnv) alice.Chain {
+	return CommonMiddleware(appEnv).Append(
+		createAuthorizeForApprovalMiddleware(appEnv),
+	)
+}
+
 // AuthorizedAppContactMiddleware ...
 func AuthorizedAppContactMiddleware(appEnv *env.AppEnv) alice.Chain {
 	return AuthorizedAppMiddleware(appEnv).Append(
@@ -154,6 +179,20 @@ func AuthorizedAppContactMiddleware(appEnv *env.AppEnv) alice.Chain {
 	)
 }
 
+// AuthorizedReleaseMiddleware ...
+func AuthorizedReleaseMiddleware(appEnv *env.AppEnv) alice.Chain {
+	return AuthorizedAppMiddleware(appEnv).Append(
+		createAuthorizeForReleaseAccessMiddleware(appEnv),
+	)
+}
+
+// AuthorizedAppVersionScheduledPublishMiddleware ...
+func AuthorizedAppVersionScheduledPublishMiddleware(appEnv *env.AppEnv) alice.Chain {
+	return AuthorizedAppVersionMiddleware(appEnv).Append(
+		createAuthorizeForAppVersionScheduledPublishAccessMiddleware(appEnv),
+	)
+}
+
 // AuthorizedBuildWebhookMiddleware ...
 func AuthorizedBuildWebhookMiddleware(appEnv *env.AppEnv) alice.Chain {
 	return CommonMiddleware(appEnv).Append(
@@ -248,6 +248,26 @@ func Test_AuthorizeForAppContactEmailConfirmationHandling(t *testing.T) {
 	})
 }
 
+func Test_AuthorizeForApprovalHandling(t *testing.T) {
+	middleware.PerformTest(t, "POST", "/...", middleware.TestCase{
+		RequestBody:    map[string]string{"approval_token": "5om3-r4nd0m-5tr1ng"},
+		ExpectedStatus: http.StatusOK,
+		ExpectedResponse: map[string]interface{}{
+			"message": "Success",
+		},
+		Middleware: services.AuthorizeForApprovalHandling(&env.AppEnv{
+			ApprovalService: &testApprovalService{
+				findFn: func(approval *models.Approval) (*models.Approval, error) {
+					require.NotNil(t, approval.Token)
+					require.Equal(t, "5om3-r4nd0m-5tr1ng", *approval.Token)
+					approval.ID = uuid.FromStringOrNil("8a230385-0113-4cf3-a9c6-469a313e587a")
+					return approval, nil
+				},
+			},
+		}),
+	})
+}
+
 func Test_AuthorizedAppContactMiddleware(t *testing.T) {
 	middleware.PerformTest(t, "GET", "/...", middleware.TestCase{
 		RequestHeaders: map[string]string{
@@ -286,6 +306,88 @@ func Test_AuthorizedAppContactMiddleware(t *testing.T) {
 	})
 }
 
+func Test_AuthorizedReleaseMiddleware(t *testing.T) {
+	middleware.PerformTest(t, "GET", "/...", middleware.TestCase{
+		RequestHeaders: map[string]string{
+			"Authorization": "token ADDON_AUTH_TOKEN",
+		},
+		ExpectedStatus: http.StatusOK,
+		ExpectedResponse: map[string]interface{}{
+			"message": "Success",
+		},
+		Middleware: services.AuthorizedReleaseMiddleware(&env.AppEnv{
+			RequestParams: &providers.RequestParamsMock{
+				Params: map[string]string{
+					"app-slug":   "test_app_slug",
+					"release-id": "de438ddc-98e5-4226-a5f4-fd2d53474879",
+				},
+			},
+			AppService: &testAppService{
+				findFn: func(app *models.App) (*models.App, error) {
+					return app, nil
+				},
+			},
+			ReleaseService: &testReleaseService{
+				findFn: func(release *models.Release) (*models.Release, error) {
+					return release, nil
+				},
+			},
+			JWTService: &security.JWTMock{
+				VerifyFn: func(token string) (bool, error) {
+					return true, nil
+				},
+				GetTokenFn: func(token string) (interface{}, error) {
+					return "auth-token-from-jwt", nil
+				},
+			},
+		}),
+	})
+}
+
+func Test_AuthorizedAppVersionScheduledPublishMiddleware(t *testing.T) {
+	middleware.PerformTest(t, "DELETE", "/...", middleware.TestCase{
+		RequestHeaders: map[string]string{
+			"Authorization": "token ADDON_AUTH_TOKEN",
+		},
+		ExpectedStatus: http.StatusOK,
+		ExpectedResponse: map[string]interface{}{
+			"message": "Success",
+		},
+		Middleware: services.AuthorizedAppVersionScheduledPublishMiddleware(&env.AppEnv{
+			RequestParams: &providers.RequestParamsMock{
+				Params: map[string]string{
+					"app-slug":             "test_app_slug",
+					"version-id":           "de438ddc-98e5-4226-a5f4-fd2d53474879",
+					"scheduled-publish-id": "7c1e4d2a-9b3f-4e58-a6d0-2f8b5c9e1a47",
+				},
+			},
+			AppService: &testAppService{
+				findFn: func(app *models.App) (*models.App, error) {
+					return app, nil
+				},
+			},
+			AppVersionService: &testAppVersionService{
+				findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+					return appVersion, nil
+				},
+			},
+			ScheduledPublishService: &testScheduledPublishService{
+				findFn: func(scheduledPublish *models.ScheduledPublish) (*models.ScheduledPublish, error) {
+					return scheduledPublish, nil
+				},
+			},
+			JWTService: &security.JWTMock{
+				VerifyFn: func(token string) (bool, error) {
+					return true, nil
+				},
+				GetTokenFn: func(token string) (interface{}, error) {
+					return "auth-token-from-jwt", nil
+				},
+			},
+		}),
+	})
+}
+
 func Test_AuthorizedBuildWebhookMiddleware(t *testing.T) {
 	revokeFn, err := envutil.RevokableSetenv("APP_WEBHOOK_SECRET_ENCRYPT_KEY", "06042e86a7bd421c642c8c3e4ab13840")
 	require.NoError(t, err)
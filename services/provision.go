@@ -48,7 +48,28 @@ func ProvisionHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Request) e
 	}
 
 	app, err := env.AppService.Find(&models.App{AppSlug: params.AppSlug})
+	var deprovisionedApp *models.App
+	if errors.Cause(err) == gorm.ErrRecordNotFound {
+		deprovisionedApp, err = env.AppService.FindDeprovisioned(&models.App{AppSlug: params.AppSlug})
+		if err == nil {
+			app = deprovisionedApp
+		}
+	}
 	switch {
+	case deprovisionedApp != nil:
+		// the app is restored, if it's provisioned again within the grace
+		// period of the deprovisioning
+		app.DeprovisionedAt = nil
+		app.BitriseAPIToken = params.BitriseAPIToken
+		app.Plan = params.Plan
+		app.APIToken = crypto.SecureRandomHash(50)
+		verrs, err := env.AppService.Update(app, []string{"DeprovisionedAt", "BitriseAPIToken", "Plan", "APIToken"})
+		if len(verrs) > 0 {
+			return httpresponse.RespondWithUnprocessableEntity(w, verrs)
+		}
+		if err != nil {
+			return errors.Wrap(err, "SQL Error")
+		}
 	case errors.Cause(err) == gorm.ErrRecordNotFound:
 		app, err = env.AppService.Create(&models.App{
 			AppSlug:         params.AppSlug,
@@ -3,6 +3,7 @@ package services_test
 import (
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/bitrise-io/addons-ship-backend/env"
 	"github.com/bitrise-io/addons-ship-backend/models"
@@ -73,6 +74,9 @@ func Test_ProvisionHandler(t *testing.T) {
 						require.Equal(t, "test-app-slug", app.AppSlug)
 						return nil, gorm.ErrRecordNotFound
 					},
+					findDeprovisionedFn: func(app *models.App) (*models.App, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
 					createFn: func(app *models.App) (*models.App, error) {
 						require.Equal(t, "test-app-slug", app.AppSlug)
 						require.Equal(t, "test-bitrise-api-token", app.BitriseAPIToken)
@@ -112,6 +116,64 @@ func Test_ProvisionHandler(t *testing.T) {
 		require.NoError(t, revokeFn())
 	})
 
+	t.Run("ok when app was deprovisioned", func(t *testing.T) {
+		deprovisionedAt := time.Date(2019, 11, 22, 10, 0, 0, 0, time.UTC)
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			env: &env.AppEnv{
+				AddonHostURL: "http://ship.addon.url",
+				AppService: &testAppService{
+					findFn: func(app *models.App) (*models.App, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+					findDeprovisionedFn: func(app *models.App) (*models.App, error) {
+						require.Equal(t, "test-app-slug", app.AppSlug)
+						app.APIToken = "existing-token"
+						app.DeprovisionedAt = &deprovisionedAt
+						return app, nil
+					},
+					updateFn: func(app *models.App, whitelist []string) ([]error, error) {
+						require.Equal(t, []string{"DeprovisionedAt", "BitriseAPIToken", "Plan", "APIToken"}, whitelist)
+						require.Nil(t, app.DeprovisionedAt)
+						require.Equal(t, "test-bitrise-api-token", app.BitriseAPIToken)
+						require.Equal(t, "free", app.Plan)
+						require.NotEqual(t, app.APIToken, "existing-token")
+
+						// overwrite random token so we can make response expectations
+						app.APIToken = "new-random-token"
+						return nil, nil
+					},
+				},
+				BitriseAPI: &testBitriseAPI{},
+			},
+			requestBody:        `{"app_slug":"test-app-slug","api_token":"test-bitrise-api-token","plan":"free"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.ProvisionPostResponse{
+				Envs: []services.Env{
+					{Key: "ADDON_SHIP_API_URL", Value: "http://ship.addon.url"},
+					{Key: "ADDON_SHIP_API_TOKEN", Value: "new-random-token"},
+				},
+			},
+		})
+	})
+
+	t.Run("when database error happens at finding deprovisioned app", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			env: &env.AppEnv{
+				AppService: &testAppService{
+					findFn: func(app *models.App) (*models.App, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+					findDeprovisionedFn: func(app *models.App) (*models.App, error) {
+						return nil, errors.New("SOME-SQL-ERROR")
+					},
+				},
+				BitriseAPI: &testBitriseAPI{},
+			},
+			requestBody:         `{"app_slug":"test-app-slug","api_token":"test-bitrise-api-token","plan":"free"}`,
+			expectedInternalErr: "SQL Error: SOME-SQL-ERROR",
+		})
+	})
+
 	t.Run("when request body is invalid", func(t *testing.T) {
 		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
 			env: &env.AppEnv{
@@ -150,6 +212,9 @@ func Test_ProvisionHandler(t *testing.T) {
 					findFn: func(app *models.App) (*models.App, error) {
 						return nil, gorm.ErrRecordNotFound
 					},
+					findDeprovisionedFn: func(app *models.App) (*models.App, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
 					createFn: func(app *models.App) (*models.App, error) {
 						return nil, errors.New("SOME-SQL-ERROR")
 					},
@@ -173,6 +238,9 @@ func Test_ProvisionHandler(t *testing.T) {
 						require.Equal(t, "test-app-slug", app.AppSlug)
 						return nil, gorm.ErrRecordNotFound
 					},
+					findDeprovisionedFn: func(app *models.App) (*models.App, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
 					createFn: func(app *models.App) (*models.App, error) {
 						app.APIToken = "test-api-token"
 
@@ -208,6 +276,9 @@ func Test_ProvisionHandler(t *testing.T) {
 						require.Equal(t, "test-app-slug", app.AppSlug)
 						return nil, gorm.ErrRecordNotFound
 					},
+					findDeprovisionedFn: func(app *models.App) (*models.App, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
 					createFn: func(app *models.App) (*models.App, error) {
 						iv, err := crypto.GenerateIV()
 						require.NoError(t, err)
@@ -0,0 +1,292 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/bitrise-io/addons-ship-backend/bitrise"
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// PublishDestination is a target app versions can be published to. Each
+// destination is published to by a workflow of the publish config, which
+// downloads the data required for publishing from the config endpoint of the
+// destination.
+type PublishDestination interface {
+	// ID ...
+	ID() string
+	// Platform is the platform of the app versions the destination accepts
+	Platform() string
+	// Workflow is the ID of the workflow in the publish config
+	Workflow() string
+	// ConfigPath is the path of the config endpoint, relative to the app version
+	ConfigPath() string
+	// ConfigHandler serves the config endpoint
+	ConfigHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Request) error
+	// SettingsKey is the key of the destination settings in the app settings
+	SettingsKey() string
+	// Settings is the zero value of the destination settings, its fields describe
+	// the settings schema
+	Settings() interface{}
+	// Tracks are the tracks of the destination a version can be published to,
+	// nil if the destination has no tracks
+	Tracks() []string
+	// TaskEnvs returns the inline envs and the secrets of the publish task
+	TaskEnvs(params PublishDestinationParams) (map[string]string, map[string]interface{})
+	// AllowsReupload tells whether a version the destination already got can be
+	// published to it again, which is how a release gets rolled back
+	AllowsReupload() bool
+}
+
+// PublishDestinationParams is the data a publish task is created from
+type PublishDestinationParams struct {
+	Env         *env.AppEnv
+	AppVersion  *models.AppVersion
+	AppSettings *models.AppSettings
+	Artifacts   []bitrise.ArtifactListElementResponseModel
+	AuthToken   string
+	ConfigURL   string
+}
+
+var publishDestinations = []PublishDestination{
+	&appStoreConnectDestination{platform: "ios"},
+	&googlePlayDestination{},
+	&appStoreConnectDestination{platform: "tvos"},
+	&appStoreConnectDestination{platform: "watchos"},
+	&appStoreConnectDestination{platform: "macos"},
+}
+
+// RegisterPublishDestination adds a publish destination to the registry. It has
+// to be called before the router is created, so that the config endpoint of the
+// destination gets registered.
+func RegisterPublishDestination(destination PublishDestination) {
+	publishDestinations = append(publishDestinations, destination)
+}
+
+// PublishDestinations returns the registered publish destinations
+func PublishDestinations() []PublishDestination {
+	return append([]PublishDestination{}, publishDestinations...)
+}
+
+// publishDestinationForPlatform returns the first registered destination of a
+// platform
+func publishDestinationForPlatform(platform string) PublishDestination {
+	for _, destination := range publishDestinations {
+		if destination.Platform() == platform {
+			return destination
+		}
+	}
+	return nil
+}
+
+func publishDestinationByID(id string) PublishDestination {
+	for _, destination := range publishDestinations {
+		if destination.ID() == id {
+			return destination
+		}
+	}
+	return nil
+}
+
+// publishDestinationConfigURL returns the URL of the config endpoint of a
+// destination, the query passes the options of the publish task to the endpoint
+func publishDestinationConfigURL(env *env.AppEnv, destination PublishDestination, appSlug string, appVersionID uuid.UUID, query url.Values) string {
+	configURL := fmt.Sprintf("%s/apps/%s/versions/%s/%s", env.AddonHostURL, appSlug, appVersionID, destination.ConfigPath())
+	if len(query) > 0 {
+		configURL += "?" + query.Encode()
+	}
+	return configURL
+}
+
+// publishTaskDestinationID returns the ID of the destination of a publish task.
+// Tasks created before publish tasks had a destination were published to the
+// default destination of the platform.
+func publishTaskDestinationID(appVersion *models.AppVersion, publishTask models.PublishTask) string {
+	if publishTask.Destination != "" {
+		return publishTask.Destination
+	}
+	if destination := publishDestinationForPlatform(appVersion.Platform); destination != nil {
+		return destination.ID()
+	}
+	return ""
+}
+
+// triggerPublishTask starts a workflow of the publish config for a destination,
+// which is the publish workflow of the destination, unless the task only changes
+// an already published release
+func triggerPublishTask(env *env.AppEnv, destination PublishDestination, workflow string, config interface{}, params PublishDestinationParams) (*bitrise.TriggerResponse, error) {
+	inlineEnvs, secrets := destination.TaskEnvs(params)
+	response, err := env.BitriseAPI.TriggerDENTask(bitrise.TaskParams{
+		StackID:     params.AppSettings.PublishStack(),
+		Workflow:    workflow,
+		BuildConfig: config,
+		InlineEnvs:  inlineEnvs,
+		Secrets:     secrets,
+		WebhookURL:  env.AddonHostURL + "/task-webhook",
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return response, nil
+}
+
+// PublishDestinationSetting ...
+type PublishDestinationSetting struct {
+	Key  string `json:"key"`
+	Type string `json:"type"`
+}
+
+// publishDestinationSettingsSchema lists the settings of a destination with
+// their JSON types
+func publishDestinationSettingsSchema(destination PublishDestination) []PublishDestinationSetting {
+	schema := []PublishDestinationSetting{}
+	settingsType := reflect.TypeOf(destination.Settings())
+	for i := 0; i < settingsType.NumField(); i++ {
+		field := settingsType.Field(i)
+		key := strings.Split(field.Tag.Get("json"), ",")[0]
+		if key == "" || key == "-" {
+			continue
+		}
+		settingType := "string"
+		switch field.Type.Kind() {
+		case reflect.Bool:
+			settingType = "boolean"
+		case reflect.Slice:
+			settingType = "array"
+		case reflect.Int, reflect.Int64, reflect.Float64:
+			settingType = "number"
+		}
+		schema = append(schema, PublishDestinationSetting{Key: key, Type: settingType})
+	}
+	return schema
+}
+
+// appStoreConnectDestination publishes the versions of an Apple platform, the
+// destination of iOS versions keeps the IDs it had before the other platforms
+// were supported
+type appStoreConnectDestination struct {
+	platform string
+}
+
+func (d *appStoreConnectDestination) ID() string {
+	if d.platform == "ios" {
+		return "app-store-connect"
+	}
+	return "app-store-connect-" + d.platform
+}
+
+func (d *appStoreConnectDestination) Platform() string {
+	return d.platform
+}
+
+func (d *appStoreConnectDestination) Workflow() string {
+	if d.platform == "macos" {
+		return models.MacPublishWorkflowID
+	}
+	return models.IosPublishWorkflowID
+}
+
+func (d *appStoreConnectDestination) ConfigPath() string {
+	return d.platform + "-config"
+}
+
+func (d *appStoreConnectDestination) SettingsKey() string {
+	return "ios_settings"
+}
+
+func (d *appStoreConnectDestination) Settings() interface{} {
+	return models.IosSettings{}
+}
+
+func (d *appStoreConnectDestination) Tracks() []string {
+	return nil
+}
+
+// AllowsReupload is false, as App Store Connect rejects every build number it
+// has already got for the version
+func (d *appStoreConnectDestination) AllowsReupload() bool {
+	return false
+}
+
+func (d *appStoreConnectDestination) ConfigHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Request) error {
+	return AppVersionIosConfigGetHandler(env, w, r)
+}
+
+func (d *appStoreConnectDestination) TaskEnvs(params PublishDestinationParams) (map[string]string, map[string]interface{}) {
+	artifactData, _, _, _, _ := selectIosArtifact(params.Artifacts, params.AppVersion)
+	inlineEnvs := map[string]string{
+		"BITRISE_APP_SLUG":      params.AppVersion.App.AppSlug,
+		"BITRISE_BUILD_SLUG":    params.AppVersion.BuildSlug,
+		"BITRISE_ARTIFACT_SLUG": artifactData.Slug,
+		"CONFIG_JSON_URL":       params.ConfigURL,
+	}
+	secrets := map[string]interface{}{"envs": []bitrise.TaskSecret{
+		bitrise.TaskSecret{"BITRISE_ACCESS_TOKEN": params.AppVersion.App.BitriseAPIToken},
+		bitrise.TaskSecret{"ADDON_SHIP_APP_ACCESS_TOKEN": params.AuthToken},
+		bitrise.TaskSecret{"SSH_RSA_PRIVATE_KEY": os.Getenv("GITHUB_SSH_KEY")},
+	}}
+	return inlineEnvs, secrets
+}
+
+const googlePlayDestinationID = "google-play"
+
+type googlePlayDestination struct{}
+
+func (d *googlePlayDestination) ID() string {
+	return googlePlayDestinationID
+}
+
+func (d *googlePlayDestination) Platform() string {
+	return "android"
+}
+
+func (d *googlePlayDestination) Workflow() string {
+	return models.AndroidPublishWorkflowID
+}
+
+func (d *googlePlayDestination) ConfigPath() string {
+	return "android-config"
+}
+
+func (d *googlePlayDestination) SettingsKey() string {
+	return "android_settings"
+}
+
+func (d *googlePlayDestination) Settings() interface{} {
+	return models.AndroidSettings{}
+}
+
+func (d *googlePlayDestination) Tracks() []string {
+	return []string{"internal", "alpha", "beta", "production"}
+}
+
+// AllowsReupload is false, as Google Play rejects every version code it has
+// already got
+func (d *googlePlayDestination) AllowsReupload() bool {
+	return false
+}
+
+func (d *googlePlayDestination) ConfigHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Request) error {
+	return AppVersionAndroidConfigGetHandler(env, w, r)
+}
+
+func (d *googlePlayDestination) TaskEnvs(params PublishDestinationParams) (map[string]string, map[string]interface{}) {
+	inlineEnvs := map[string]string{
+		"CONFIG_JSON_URL":    params.ConfigURL,
+		"GIT_REPOSITORY_URL": "git@github.com:bitrise-io/addons-ship-bg-worker-task-android.git",
+	}
+	secrets := map[string]interface{}{"envs": []bitrise.TaskSecret{
+		bitrise.TaskSecret{"BITRISE_ACCESS_TOKEN": params.AppVersion.App.BitriseAPIToken},
+		bitrise.TaskSecret{"ADDON_SHIP_ACCESS_TOKEN": params.Env.AddonAccessToken},
+		bitrise.TaskSecret{"ADDON_SHIP_APP_ACCESS_TOKEN": params.AuthToken},
+		bitrise.TaskSecret{"SSH_RSA_PRIVATE_KEY": os.Getenv("GITHUB_SSH_KEY")},
+	}}
+	return inlineEnvs, secrets
+}
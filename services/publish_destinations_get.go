@@ -0,0 +1,39 @@
+package services
+
+import (
+	"net/http"
+
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/api-utils/httpresponse"
+)
+
+// PublishDestinationData ...
+type PublishDestinationData struct {
+	ID          string                      `json:"id"`
+	Platform    string                      `json:"platform"`
+	Workflow    string                      `json:"workflow"`
+	Tracks      []string                    `json:"tracks,omitempty"`
+	SettingsKey string                      `json:"settings_key"`
+	Settings    []PublishDestinationSetting `json:"settings"`
+}
+
+// PublishDestinationsGetResponse ...
+type PublishDestinationsGetResponse struct {
+	Data []PublishDestinationData `json:"data"`
+}
+
+// PublishDestinationsGetHandler ...
+func PublishDestinationsGetHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Request) error {
+	destinations := []PublishDestinationData{}
+	for _, destination := range PublishDestinations() {
+		destinations = append(destinations, PublishDestinationData{
+			ID:          destination.ID(),
+			Platform:    destination.Platform(),
+			Workflow:    destination.Workflow(),
+			Tracks:      destination.Tracks(),
+			SettingsKey: destination.SettingsKey(),
+			Settings:    publishDestinationSettingsSchema(destination),
+		})
+	}
+	return httpresponse.RespondWithSuccess(w, PublishDestinationsGetResponse{Data: destinations})
+}
@@ -0,0 +1,87 @@
+package services_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/services"
+	ctxpkg "github.com/bitrise-io/api-utils/context"
+	uuid "github.com/satori/go.uuid"
+)
+
+func Test_PublishDestinationsGetHandler(t *testing.T) {
+	appStoreConnectSettings := []services.PublishDestinationSetting{
+		{Key: "app_sku", Type: "string"},
+		{Key: "apple_developer_account_email", Type: "string"},
+		{Key: "app_specific_password", Type: "string"},
+		{Key: "selected_app_store_provisioning_profiles", Type: "array"},
+		{Key: "selected_code_signing_identity", Type: "string"},
+		{Key: "include_bit_code", Type: "boolean"},
+		{Key: "api_key_issuer_id", Type: "string"},
+		{Key: "api_key_id", Type: "string"},
+		{Key: "api_key_private_key", Type: "string"},
+	}
+	httpMethod := "GET"
+	url := "/apps/{app-slug}/publish-destinations"
+	handler := services.PublishDestinationsGetHandler
+
+	t.Run("ok", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppID: uuid.NewV4(),
+			},
+			env:                &env.AppEnv{},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.PublishDestinationsGetResponse{
+				Data: []services.PublishDestinationData{
+					{
+						ID:          "app-store-connect",
+						Platform:    "ios",
+						Workflow:    "resign_archive_app_store",
+						SettingsKey: "ios_settings",
+						Settings:    appStoreConnectSettings,
+					},
+					{
+						ID:          "google-play",
+						Platform:    "android",
+						Workflow:    "resign_android",
+						Tracks:      []string{"internal", "alpha", "beta", "production"},
+						SettingsKey: "android_settings",
+						Settings: []services.PublishDestinationSetting{
+							{Key: "track", Type: "string"},
+							{Key: "selected_keystore_file", Type: "string"},
+							{Key: "selected_service_account", Type: "string"},
+							{Key: "module", Type: "string"},
+							{Key: "user_fraction", Type: "number"},
+							{Key: "release_status", Type: "string"},
+							{Key: "release_name", Type: "string"},
+							{Key: "in_app_update_priority", Type: "number"},
+						},
+					},
+					{
+						ID:          "app-store-connect-tvos",
+						Platform:    "tvos",
+						Workflow:    "resign_archive_app_store",
+						SettingsKey: "ios_settings",
+						Settings:    appStoreConnectSettings,
+					},
+					{
+						ID:          "app-store-connect-watchos",
+						Platform:    "watchos",
+						Workflow:    "resign_archive_app_store",
+						SettingsKey: "ios_settings",
+						Settings:    appStoreConnectSettings,
+					},
+					{
+						ID:          "app-store-connect-macos",
+						Platform:    "macos",
+						Workflow:    "resign_archive_mac_app_store",
+						SettingsKey: "ios_settings",
+						Settings:    appStoreConnectSettings,
+					},
+				},
+			},
+		})
+	})
+}
@@ -3,8 +3,11 @@ package services_test
 import "github.com/bitrise-io/addons-ship-backend/models"
 
 type testPublishTaskService struct {
-	createFn func(*models.PublishTask) (*models.PublishTask, error)
-	findFn   func(*models.PublishTask) (*models.PublishTask, error)
+	createFn        func(*models.PublishTask) (*models.PublishTask, error)
+	findFn          func(*models.PublishTask) (*models.PublishTask, error)
+	findAllFn       func(*models.AppVersion) ([]models.PublishTask, error)
+	findAllForAppFn func(*models.App) ([]models.PublishTask, error)
+	updateFn        func(*models.PublishTask, []string) error
 }
 
 func (a *testPublishTaskService) Create(publishTask *models.PublishTask) (*models.PublishTask, error) {
@@ -20,3 +23,24 @@ func (a *testPublishTaskService) Find(publishTask *models.PublishTask) (*models.
 	}
 	panic("You have to override Find function in tests")
 }
+
+func (a *testPublishTaskService) FindAll(appVersion *models.AppVersion) ([]models.PublishTask, error) {
+	if a.findAllFn != nil {
+		return a.findAllFn(appVersion)
+	}
+	panic("You have to override FindAll function in tests")
+}
+
+func (a *testPublishTaskService) FindAllForApp(app *models.App) ([]models.PublishTask, error) {
+	if a.findAllForAppFn != nil {
+		return a.findAllForAppFn(app)
+	}
+	panic("You have to override FindAllForApp function in tests")
+}
+
+func (a *testPublishTaskService) Update(publishTask *models.PublishTask, whitelist []string) error {
+	if a.updateFn != nil {
+		return a.updateFn(publishTask, whitelist)
+	}
+	panic("You have to override Update function in tests")
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/api-utils/httprequest"
+	"github.com/bitrise-io/api-utils/httpresponse"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// ReleasePatchParams ...
+type ReleasePatchParams struct {
+	Notes string `json:"notes"`
+}
+
+// ReleasePatchResponse ...
+type ReleasePatchResponse struct {
+	Data *models.Release `json:"data"`
+}
+
+// ReleasePatchHandler ...
+func ReleasePatchHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Request) error {
+	authorizedReleaseID, err := GetAuthorizedReleaseIDFromContext(r.Context())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if env.ReleaseService == nil {
+		return errors.New("No Release Service defined for handler")
+	}
+
+	var params ReleasePatchParams
+	defer httprequest.BodyCloseWithErrorLog(r)
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		return httpresponse.RespondWithBadRequestError(w, "Invalid request body, JSON decode failed")
+	}
+
+	release, err := env.ReleaseService.Find(&models.Release{Record: models.Record{ID: authorizedReleaseID}})
+	switch {
+	case errors.Cause(err) == gorm.ErrRecordNotFound:
+		return httpresponse.RespondWithNotFoundError(w)
+	case err != nil:
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	release.Notes = params.Notes
+	verrs, err := env.ReleaseService.Update(release, []string{"Notes"})
+	if len(verrs) > 0 {
+		return httpresponse.RespondWithUnprocessableEntity(w, verrs)
+	}
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	return httpresponse.RespondWithSuccess(w, ReleasePatchResponse{Data: release})
+}
@@ -0,0 +1,122 @@
+package services_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/addons-ship-backend/services"
+	ctxpkg "github.com/bitrise-io/api-utils/context"
+	"github.com/bitrise-io/api-utils/httpresponse"
+	"github.com/c2fo/testify/require"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+func Test_ReleasePatchHandler(t *testing.T) {
+	httpMethod := "PATCH"
+	url := "/apps/{app-slug}/releases/{release-id}"
+	handler := services.ReleasePatchHandler
+
+	testReleaseID := uuid.FromStringOrNil("7a2d7c3e-3d5b-4f0c-8c1e-4d1f5b9a2c61")
+
+	behavesAsServiceCravingHandler(t, httpMethod, url, handler, []string{"ReleaseService"}, ControllerTestCase{
+		contextElements: map[ctxpkg.RequestContextKey]interface{}{
+			services.ContextKeyAuthorizedReleaseID: uuid.NewV4(),
+		},
+		env: &env.AppEnv{
+			ReleaseService: &testReleaseService{},
+		},
+		requestBody: `{}`,
+	})
+
+	behavesAsContextCravingHandler(t, httpMethod, url, handler, []ctxpkg.RequestContextKey{services.ContextKeyAuthorizedReleaseID}, ControllerTestCase{
+		contextElements: map[ctxpkg.RequestContextKey]interface{}{
+			services.ContextKeyAuthorizedReleaseID: uuid.NewV4(),
+		},
+		env: &env.AppEnv{
+			ReleaseService: &testReleaseService{},
+		},
+		requestBody: `{}`,
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedReleaseID: testReleaseID,
+			},
+			env: &env.AppEnv{
+				ReleaseService: &testReleaseService{
+					findFn: func(release *models.Release) (*models.Release, error) {
+						require.Equal(t, testReleaseID, release.ID)
+						return &models.Release{Record: models.Record{ID: testReleaseID}, Version: "1.0", Notes: "Old notes"}, nil
+					},
+					updateFn: func(release *models.Release, whitelist []string) ([]error, error) {
+						require.Equal(t, []string{"Notes"}, whitelist)
+						require.Equal(t, "Bug fixes on both platforms", release.Notes)
+						return nil, nil
+					},
+				},
+			},
+			requestBody:        `{"notes":"Bug fixes on both platforms"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.ReleasePatchResponse{
+				Data: &models.Release{Record: models.Record{ID: testReleaseID}, Version: "1.0", Notes: "Bug fixes on both platforms"},
+			},
+		})
+	})
+
+	t.Run("when request body is not a valid JSON", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedReleaseID: testReleaseID,
+			},
+			env: &env.AppEnv{
+				ReleaseService: &testReleaseService{},
+			},
+			requestBody:        `invalid JSON`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   httpresponse.StandardErrorRespModel{Message: "Invalid request body, JSON decode failed"},
+		})
+	})
+
+	t.Run("when release not found in database", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedReleaseID: testReleaseID,
+			},
+			env: &env.AppEnv{
+				ReleaseService: &testReleaseService{
+					findFn: func(release *models.Release) (*models.Release, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+				},
+			},
+			requestBody:        `{"notes":"Bug fixes"}`,
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   httpresponse.StandardErrorRespModel{Message: "Not Found"},
+		})
+	})
+
+	t.Run("when error happens at updating release", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedReleaseID: testReleaseID,
+			},
+			env: &env.AppEnv{
+				ReleaseService: &testReleaseService{
+					findFn: func(release *models.Release) (*models.Release, error) {
+						return release, nil
+					},
+					updateFn: func(release *models.Release, whitelist []string) ([]error, error) {
+						return nil, errors.New("SOME-SQL-ERROR")
+					},
+				},
+			},
+			requestBody:         `{"notes":"Bug fixes"}`,
+			expectedInternalErr: "SQL Error: SOME-SQL-ERROR",
+		})
+	})
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/api-utils/httpresponse"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// ReleasePublishResponse ...
+type ReleasePublishResponse struct {
+	PublishTasks []models.PublishTask `json:"publish_tasks"`
+	// Message explains why the publish to some of the destinations failed
+	Message string `json:"message,omitempty"`
+}
+
+// ReleasePublishPostHandler publishes the latest versions of a release to the
+// default publish destination of their platform, in one batch, see
+// models.Release.LatestAppVersions
+func ReleasePublishPostHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Request) error {
+	authorizedReleaseID, err := GetAuthorizedReleaseIDFromContext(r.Context())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if env.ReleaseService == nil {
+		return errors.New("No Release Service defined for handler")
+	}
+
+	release, err := env.ReleaseService.Find(&models.Release{Record: models.Record{ID: authorizedReleaseID}})
+	switch {
+	case errors.Cause(err) == gorm.ErrRecordNotFound:
+		return httpresponse.RespondWithNotFoundError(w)
+	case err != nil:
+		return errors.Wrap(err, "SQL Error")
+	}
+	appVersions := release.LatestAppVersions()
+	if len(appVersions) == 0 {
+		return httpresponse.RespondWithBadRequestError(w, "Release has no versions to publish")
+	}
+
+	if env.AppSettingsService == nil {
+		return errors.New("No App Settings Service defined for handler")
+	}
+
+	appSettings, err := env.AppSettingsService.Find(&models.AppSettings{AppID: release.AppID})
+	switch {
+	case errors.Cause(err) == gorm.ErrRecordNotFound:
+		return httpresponse.RespondWithNotFoundError(w)
+	case err != nil:
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	if env.BitriseAPI == nil {
+		return errors.New("No Bitrise API Service defined for handler")
+	}
+	if env.PublishTaskService == nil {
+		return errors.New("No Publish Task Service defined for handler")
+	}
+	if env.AppVersionService == nil {
+		return errors.New("No App Version Service defined for handler")
+	}
+
+	// every version is checked before triggering any of the tasks
+	targetsOfVersions := [][]publishTarget{}
+	for _, appVersion := range appVersions {
+		targets, err := publishTargets(appVersion.Platform, nil)
+		if err != nil {
+			return httpresponse.RespondWithBadRequestError(w, err.Error())
+		}
+		targetsOfVersions = append(targetsOfVersions, targets)
+
+		warning, err := buildCodeWarning(env, &appVersion)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if warning != "" {
+			return httpresponse.RespondWithUnprocessableEntity(w, []error{errors.New(warning)})
+		}
+
+		approvalWarnings, err := missingApprovals(env, &appVersion, appSettings, targets)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if len(approvalWarnings) > 0 {
+			verrs := []error{}
+			for _, warning := range approvalWarnings {
+				verrs = append(verrs, errors.Errorf("%s version: %s", appVersion.Platform, warning))
+			}
+			return httpresponse.RespondWithUnprocessableEntity(w, verrs)
+		}
+	}
+
+	response := ReleasePublishResponse{PublishTasks: []models.PublishTask{}}
+	batchID := uuid.NewV4()
+	triggerErrs := []string{}
+	for i, appVersion := range appVersions {
+		appVersion.App = release.App
+		_, publishTasks, err := publishAppVersion(env, &appVersion, appSettings, targetsOfVersions[i], batchID)
+		response.PublishTasks = append(response.PublishTasks, publishTasks...)
+		// the other versions are still published, the failed tasks are in
+		// the batch
+		if isPublishTriggerError(err) {
+			triggerErrs = append(triggerErrs, fmt.Sprintf("%s version: %s", appVersion.Platform, err))
+			continue
+		}
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if len(triggerErrs) > 0 {
+		response.Message = strings.Join(triggerErrs, ", ")
+		return httpresponse.RespondWithJSON(w, http.StatusBadGateway, response)
+	}
+
+	return httpresponse.RespondWithSuccess(w, response)
+}
@@ -0,0 +1,441 @@
+package services_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bitrise-io/addons-ship-backend/bitrise"
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/addons-ship-backend/services"
+	ctxpkg "github.com/bitrise-io/api-utils/context"
+	"github.com/bitrise-io/api-utils/httpresponse"
+	"github.com/bitrise-io/api-utils/security"
+	"github.com/c2fo/testify/require"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+func Test_ReleasePublishPostHandler(t *testing.T) {
+	httpMethod := "POST"
+	url := "/apps/{app-slug}/releases/{release-id}/publish"
+	handler := services.ReleasePublishPostHandler
+
+	testReleaseID := uuid.FromStringOrNil("7a2d7c3e-3d5b-4f0c-8c1e-4d1f5b9a2c61")
+	testAppID := uuid.FromStringOrNil("4d9a2f5f-6c4c-4dc9-a5a0-3c2d0e52b4b1")
+	testIosVersionID := uuid.FromStringOrNil("de438ddc-98e5-4226-a5f4-fd2d53474879")
+	testAndroidVersionID := uuid.FromStringOrNil("9f7b06d1-e736-42d3-94c3-c2bcfda0463c")
+	testTaskIdentifier := uuid.FromStringOrNil("13a94c5d-4609-404e-ae69-c625e93b8b71")
+
+	testRelease := func() *models.Release {
+		return &models.Release{
+			Record: models.Record{ID: testReleaseID},
+			AppID:  testAppID,
+			App: models.App{
+				AppSlug:         "test-app-slug",
+				BitriseAPIToken: "bitrise-api-addon-token",
+				APIToken:        "addon-access-token",
+			},
+			AppVersions: []models.AppVersion{
+				models.AppVersion{Record: models.Record{ID: testIosVersionID}, Platform: "ios", BuildSlug: "test-build-slug"},
+				models.AppVersion{Record: models.Record{ID: testAndroidVersionID}, Platform: "android", BuildSlug: "test-build-slug"},
+			},
+		}
+	}
+
+	behavesAsServiceCravingHandler(t, httpMethod, url, handler, []string{"ReleaseService", "AppSettingsService", "BitriseAPI", "PublishTaskService", "AppVersionService"}, ControllerTestCase{
+		contextElements: map[ctxpkg.RequestContextKey]interface{}{
+			services.ContextKeyAuthorizedReleaseID: uuid.NewV4(),
+		},
+		env: &env.AppEnv{
+			ReleaseService: &testReleaseService{
+				findFn: func(release *models.Release) (*models.Release, error) {
+					return &models.Release{AppVersions: []models.AppVersion{models.AppVersion{Platform: "android"}}}, nil
+				},
+			},
+			AppSettingsService: &testAppSettingsService{
+				findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+					return &models.AppSettings{}, nil
+				},
+			},
+			BitriseAPI:         &testBitriseAPI{},
+			PublishTaskService: &testPublishTaskService{},
+			AppVersionService:  &testAppVersionService{},
+		},
+	})
+
+	behavesAsContextCravingHandler(t, httpMethod, url, handler, []ctxpkg.RequestContextKey{services.ContextKeyAuthorizedReleaseID}, ControllerTestCase{
+		contextElements: map[ctxpkg.RequestContextKey]interface{}{
+			services.ContextKeyAuthorizedReleaseID: uuid.NewV4(),
+		},
+		env: &env.AppEnv{
+			ReleaseService: &testReleaseService{},
+		},
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		publishedVersionIDs := []uuid.UUID{}
+		batchIDs := map[uuid.UUID]bool{}
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedReleaseID: testReleaseID,
+			},
+			env: &env.AppEnv{
+				AddonHostURL: "http://ship.addon.url",
+				ReleaseService: &testReleaseService{
+					findFn: func(release *models.Release) (*models.Release, error) {
+						require.Equal(t, testReleaseID, release.ID)
+						return testRelease(), nil
+					},
+				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						require.Equal(t, testAppID, appSettings.AppID)
+						return &models.AppSettings{}, nil
+					},
+				},
+				BitriseAPI: &testBitriseAPI{
+					getArtifactsFn: func(apiToken string, appSlug string, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
+						require.Equal(t, "bitrise-api-addon-token", apiToken)
+						require.Equal(t, "test-app-slug", appSlug)
+						require.Equal(t, "test-build-slug", buildSlug)
+						return []bitrise.ArtifactListElementResponseModel{}, nil
+					},
+					triggerDENTaskFn: func(params bitrise.TaskParams) (*bitrise.TriggerResponse, error) {
+						return &bitrise.TriggerResponse{TaskIdentifier: testTaskIdentifier}, nil
+					},
+				},
+				AppVersionService: &testAppVersionService{
+					updateFn: func(appVersion *models.AppVersion, whitelist []string) ([]error, error) {
+						require.Equal(t, []string{"Status"}, whitelist)
+						require.Equal(t, "publishing", appVersion.Status)
+						return nil, nil
+					},
+					latestPublishedFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+				},
+				PublishTaskService: &testPublishTaskService{
+					createFn: func(publishTask *models.PublishTask) (*models.PublishTask, error) {
+						require.Equal(t, testTaskIdentifier, publishTask.TaskID)
+						publishedVersionIDs = append(publishedVersionIDs, publishTask.AppVersionID)
+						batchIDs[publishTask.BatchID] = true
+						publishTask.BatchID = uuid.UUID{}
+						return publishTask, nil
+					},
+				},
+				JWTService: &security.JWTMock{
+					SignFn: func(token string) (string, error) {
+						require.Equal(t, "addon-access-token", token)
+						return "jwt-token", nil
+					},
+				},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.ReleasePublishResponse{
+				PublishTasks: []models.PublishTask{
+					models.PublishTask{TaskID: testTaskIdentifier, Destination: "app-store-connect", Status: "pending"},
+					models.PublishTask{TaskID: testTaskIdentifier, Destination: "google-play", Status: "pending"},
+				},
+			},
+		})
+		require.Equal(t, []uuid.UUID{testIosVersionID, testAndroidVersionID}, publishedVersionIDs)
+		require.Len(t, batchIDs, 1)
+	})
+
+	t.Run("ok - when the release has older and archived versions", func(t *testing.T) {
+		archivedAt := time.Date(2019, 12, 1, 10, 0, 0, 0, time.UTC)
+		publishedVersionIDs := []uuid.UUID{}
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedReleaseID: testReleaseID,
+			},
+			env: &env.AppEnv{
+				AddonHostURL: "http://ship.addon.url",
+				ReleaseService: &testReleaseService{
+					findFn: func(release *models.Release) (*models.Release, error) {
+						release = testRelease()
+						release.AppVersions[0].BuildNumber = "12"
+						release.AppVersions = append(release.AppVersions,
+							models.AppVersion{Record: models.Record{ID: uuid.NewV4()}, Platform: "ios", BuildNumber: "11", BuildSlug: "test-build-slug"},
+							models.AppVersion{Record: models.Record{ID: uuid.NewV4()}, Platform: "android", BuildSlug: "test-build-slug", ArchivedAt: &archivedAt},
+						)
+						return release, nil
+					},
+				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						return &models.AppSettings{}, nil
+					},
+				},
+				BitriseAPI: &testBitriseAPI{
+					getArtifactsFn: func(apiToken string, appSlug string, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
+						return []bitrise.ArtifactListElementResponseModel{}, nil
+					},
+					triggerDENTaskFn: func(params bitrise.TaskParams) (*bitrise.TriggerResponse, error) {
+						return &bitrise.TriggerResponse{TaskIdentifier: testTaskIdentifier}, nil
+					},
+				},
+				AppVersionService: &testAppVersionService{
+					updateFn: func(appVersion *models.AppVersion, whitelist []string) ([]error, error) {
+						return nil, nil
+					},
+					latestPublishedFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+				},
+				PublishTaskService: &testPublishTaskService{
+					createFn: func(publishTask *models.PublishTask) (*models.PublishTask, error) {
+						publishedVersionIDs = append(publishedVersionIDs, publishTask.AppVersionID)
+						publishTask.BatchID = uuid.UUID{}
+						return publishTask, nil
+					},
+				},
+				JWTService: &security.JWTMock{
+					SignFn: func(token string) (string, error) {
+						return "jwt-token", nil
+					},
+				},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.ReleasePublishResponse{
+				PublishTasks: []models.PublishTask{
+					models.PublishTask{TaskID: testTaskIdentifier, Destination: "app-store-connect", Status: "pending"},
+					models.PublishTask{TaskID: testTaskIdentifier, Destination: "google-play", Status: "pending"},
+				},
+			},
+		})
+		require.Equal(t, []uuid.UUID{testIosVersionID, testAndroidVersionID}, publishedVersionIDs)
+	})
+
+	t.Run("when every version of the release is archived", func(t *testing.T) {
+		archivedAt := time.Date(2019, 12, 1, 10, 0, 0, 0, time.UTC)
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedReleaseID: testReleaseID,
+			},
+			env: &env.AppEnv{
+				ReleaseService: &testReleaseService{
+					findFn: func(release *models.Release) (*models.Release, error) {
+						release = testRelease()
+						for i := range release.AppVersions {
+							release.AppVersions[i].ArchivedAt = &archivedAt
+						}
+						return release, nil
+					},
+				},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   httpresponse.StandardErrorRespModel{Message: "Release has no versions to publish"},
+		})
+	})
+
+	t.Run("when the release has no versions", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedReleaseID: testReleaseID,
+			},
+			env: &env.AppEnv{
+				ReleaseService: &testReleaseService{
+					findFn: func(release *models.Release) (*models.Release, error) {
+						return &models.Release{}, nil
+					},
+				},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   httpresponse.StandardErrorRespModel{Message: "Release has no versions to publish"},
+		})
+	})
+
+	t.Run("when a version has no publish destination", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedReleaseID: testReleaseID,
+			},
+			env: &env.AppEnv{
+				ReleaseService: &testReleaseService{
+					findFn: func(release *models.Release) (*models.Release, error) {
+						return &models.Release{AppVersions: []models.AppVersion{models.AppVersion{Platform: "windows"}}}, nil
+					},
+				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						return &models.AppSettings{}, nil
+					},
+				},
+				BitriseAPI:         &testBitriseAPI{},
+				PublishTaskService: &testPublishTaskService{},
+				AppVersionService:  &testAppVersionService{},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   httpresponse.StandardErrorRespModel{Message: "No publish destination for platform: windows"},
+		})
+	})
+
+	t.Run("when the version code of a version isn't greater than the published one", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedReleaseID: testReleaseID,
+			},
+			env: &env.AppEnv{
+				ReleaseService: &testReleaseService{
+					findFn: func(release *models.Release) (*models.Release, error) {
+						release = testRelease()
+						release.AppVersions[1].ArtifactInfoData = json.RawMessage(`{"version_code":"7"}`)
+						return release, nil
+					},
+				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						return &models.AppSettings{}, nil
+					},
+				},
+				BitriseAPI:         &testBitriseAPI{},
+				PublishTaskService: &testPublishTaskService{},
+				AppVersionService: &testAppVersionService{
+					latestPublishedFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						if appVersion.Platform == "ios" {
+							return nil, gorm.ErrRecordNotFound
+						}
+						return &models.AppVersion{Platform: "android", ArtifactInfoData: json.RawMessage(`{"version_code":"8"}`)}, nil
+					},
+				},
+			},
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedResponse: httpresponse.ValidationErrorRespModel{
+				Message: "Unprocessable Entity",
+				Errors:  []string{"Version code 7 is not greater than the greatest published one (8)"},
+			},
+		})
+	})
+
+	t.Run("when release not found in database", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedReleaseID: testReleaseID,
+			},
+			env: &env.AppEnv{
+				ReleaseService: &testReleaseService{
+					findFn: func(release *models.Release) (*models.Release, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+				},
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   httpresponse.StandardErrorRespModel{Message: "Not Found"},
+		})
+	})
+
+	t.Run("when the publish of a version can't be triggered", func(t *testing.T) {
+		statuses := map[uuid.UUID]string{}
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedReleaseID: testReleaseID,
+			},
+			env: &env.AppEnv{
+				ReleaseService: &testReleaseService{
+					findFn: func(release *models.Release) (*models.Release, error) {
+						return testRelease(), nil
+					},
+				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						return &models.AppSettings{}, nil
+					},
+				},
+				BitriseAPI: &testBitriseAPI{
+					getArtifactsFn: func(apiToken string, appSlug string, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
+						return []bitrise.ArtifactListElementResponseModel{}, nil
+					},
+					triggerDENTaskFn: func(params bitrise.TaskParams) (*bitrise.TriggerResponse, error) {
+						if params.Workflow == "resign_android" {
+							return nil, errors.New("SOME-BITRISE-API-ERROR")
+						}
+						return &bitrise.TriggerResponse{TaskIdentifier: testTaskIdentifier}, nil
+					},
+				},
+				AppVersionService: &testAppVersionService{
+					updateFn: func(appVersion *models.AppVersion, whitelist []string) ([]error, error) {
+						statuses[appVersion.ID] = appVersion.Status
+						return nil, nil
+					},
+					latestPublishedFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+				},
+				PublishTaskService: &testPublishTaskService{
+					createFn: func(publishTask *models.PublishTask) (*models.PublishTask, error) {
+						publishTask.TaskID = testTaskIdentifier
+						publishTask.BatchID = uuid.UUID{}
+						return publishTask, nil
+					},
+				},
+				JWTService: &security.JWTMock{
+					SignFn: func(token string) (string, error) {
+						return "jwt-token", nil
+					},
+				},
+			},
+			expectedStatusCode: http.StatusBadGateway,
+			expectedResponse: services.ReleasePublishResponse{
+				PublishTasks: []models.PublishTask{
+					models.PublishTask{TaskID: testTaskIdentifier, Destination: "app-store-connect", Status: "pending"},
+					models.PublishTask{TaskID: testTaskIdentifier, Destination: "google-play", Status: "failed"},
+				},
+				Message: "android version: Failed to trigger publish to google-play: SOME-BITRISE-API-ERROR",
+			},
+		})
+		require.Equal(t, map[uuid.UUID]string{testIosVersionID: "publishing", testAndroidVersionID: "failed"}, statuses)
+	})
+
+	t.Run("when error happens at creating publish task", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedReleaseID: testReleaseID,
+			},
+			env: &env.AppEnv{
+				ReleaseService: &testReleaseService{
+					findFn: func(release *models.Release) (*models.Release, error) {
+						return testRelease(), nil
+					},
+				},
+				AppSettingsService: &testAppSettingsService{
+					findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+						return &models.AppSettings{}, nil
+					},
+				},
+				BitriseAPI: &testBitriseAPI{
+					getArtifactsFn: func(apiToken string, appSlug string, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
+						return []bitrise.ArtifactListElementResponseModel{}, nil
+					},
+					triggerDENTaskFn: func(params bitrise.TaskParams) (*bitrise.TriggerResponse, error) {
+						return &bitrise.TriggerResponse{TaskIdentifier: testTaskIdentifier}, nil
+					},
+				},
+				AppVersionService: &testAppVersionService{
+					updateFn: func(appVersion *models.AppVersion, whitelist []string) ([]error, error) {
+						require.Equal(t, "publishing", appVersion.Status)
+						return nil, nil
+					},
+					latestPublishedFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+				},
+				PublishTaskService: &testPublishTaskService{
+					createFn: func(publishTask *models.PublishTask) (*models.PublishTask, error) {
+						return nil, errors.New("SOME-SQL-ERROR")
+					},
+				},
+				JWTService: &security.JWTMock{
+					SignFn: func(token string) (string, error) {
+						return "jwt-token", nil
+					},
+				},
+			},
+			expectedInternalErr: "SQL Error: SOME-SQL-ERROR",
+		})
+	})
+}
@@ -0,0 +1,38 @@
+package services_test
+
+import "github.com/bitrise-io/addons-ship-backend/models"
+
+type testReleaseService struct {
+	createFn  func(*models.Release) (*models.Release, error)
+	findFn    func(*models.Release) (*models.Release, error)
+	findAllFn func(app *models.App) ([]models.Release, error)
+	updateFn  func(*models.Release, []string) ([]error, error)
+}
+
+func (r *testReleaseService) Create(release *models.Release) (*models.Release, error) {
+	if r.createFn != nil {
+		return r.createFn(release)
+	}
+	panic("You have to override ReleaseService.Create function in tests")
+}
+
+func (r *testReleaseService) Find(release *models.Release) (*models.Release, error) {
+	if r.findFn != nil {
+		return r.findFn(release)
+	}
+	panic("You have to override ReleaseService.Find function in tests")
+}
+
+func (r *testReleaseService) FindAll(app *models.App) ([]models.Release, error) {
+	if r.findAllFn != nil {
+		return r.findAllFn(app)
+	}
+	panic("You have to override ReleaseService.FindAll function in tests")
+}
+
+func (r *testReleaseService) Update(release *models.Release, whitelist []string) ([]error, error) {
+	if r.updateFn != nil {
+		return r.updateFn(release, whitelist)
+	}
+	panic("You have to override ReleaseService.Update function in tests")
+}
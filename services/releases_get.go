@@ -0,0 +1,121 @@
+package services
+
+import (
+	"net/http"
+
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/api-utils/httpresponse"
+	"github.com/pkg/errors"
+)
+
+// ReleaseAppVersionData ...
+type ReleaseAppVersionData struct {
+	models.AppVersion
+	Version        string `json:"version"`
+	PublishEnabled bool   `json:"publish_enabled"`
+	PublishStatus  string `json:"publish_status"`
+}
+
+// ReleaseData ...
+type ReleaseData struct {
+	models.Release
+	AppVersions []ReleaseAppVersionData `json:"app_versions"`
+	// Ready tells whether every version of the release can be published
+	Ready         bool   `json:"ready"`
+	PublishStatus string `json:"publish_status"`
+}
+
+// ReleasesGetResponse ...
+type ReleasesGetResponse struct {
+	Data []ReleaseData `json:"data"`
+}
+
+// ReleasesGetHandler lists the releases of an app. The readiness and the
+// publish status of the versions come from their lifecycle status, so no build
+// artifact or publish task has to be fetched per version.
+func ReleasesGetHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Request) error {
+	authorizedAppID, err := GetAuthorizedAppIDFromContext(r.Context())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if env.ReleaseService == nil {
+		return errors.New("No Release Service defined for handler")
+	}
+
+	releases, err := env.ReleaseService.FindAll(&models.App{Record: models.Record{ID: authorizedAppID}})
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	responseData := []ReleaseData{}
+	for i := range releases {
+		releaseData, err := newReleaseData(&releases[i])
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		responseData = append(responseData, releaseData)
+	}
+
+	return httpresponse.RespondWithSuccess(w, ReleasesGetResponse{Data: responseData})
+}
+
+// newReleaseData returns a release with the readiness and the publish status of
+// its versions. The release is ready, and has the publish status of its latest
+// versions, see models.Release.LatestAppVersions.
+func newReleaseData(release *models.Release) (ReleaseData, error) {
+	releaseData := ReleaseData{
+		Release:     *release,
+		AppVersions: []ReleaseAppVersionData{},
+	}
+	for _, appVersion := range release.AppVersions {
+		appVersion.App = release.App
+		artifactInfo, err := appVersion.ArtifactInfo()
+		if err != nil {
+			return ReleaseData{}, errors.WithStack(err)
+		}
+		releaseData.AppVersions = append(releaseData.AppVersions, ReleaseAppVersionData{
+			AppVersion:     appVersion,
+			Version:        artifactInfo.Version,
+			PublishEnabled: isPublishEnabledByStatus(&appVersion),
+			PublishStatus:  models.ReleasePublishStatus([]string{publishTaskStatusOfAppVersion(&appVersion)}),
+		})
+	}
+
+	latestAppVersions := release.LatestAppVersions()
+	releaseData.Ready = len(latestAppVersions) > 0
+	publishStatuses := []string{}
+	for _, appVersion := range latestAppVersions {
+		appVersion.App = release.App
+		releaseData.Ready = releaseData.Ready && isPublishEnabledByStatus(&appVersion)
+		publishStatuses = append(publishStatuses, publishTaskStatusOfAppVersion(&appVersion))
+	}
+	releaseData.PublishStatus = models.ReleasePublishStatus(publishStatuses)
+	return releaseData, nil
+}
+
+// isPublishEnabledByStatus tells whether an app version is publishable by its
+// lifecycle status, which is set from the artifacts of its build when it gets
+// created, see newAppVersionStatus. The service account of the app is checked
+// again, as it could have been changed since.
+func isPublishEnabledByStatus(appVersion *models.AppVersion) bool {
+	if !appVersion.Publishable() {
+		return false
+	}
+	return appVersion.Platform != "android" || len(appVersion.App.AndroidServiceAccountErrors()) == 0
+}
+
+// publishTaskStatusOfAppVersion returns the status of the latest publish task
+// of an app version by its lifecycle status, an empty status stands for a
+// version which wasn't published yet
+func publishTaskStatusOfAppVersion(appVersion *models.AppVersion) string {
+	switch appVersion.Status {
+	case models.AppVersionStatusPublishing:
+		return models.PublishTaskStatusInProgress
+	case models.AppVersionStatusPublished:
+		return models.PublishTaskStatusSuccess
+	case models.AppVersionStatusFailed:
+		return models.PublishTaskStatusFailed
+	}
+	return ""
+}
@@ -0,0 +1,160 @@
+package services_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/addons-ship-backend/services"
+	ctxpkg "github.com/bitrise-io/api-utils/context"
+	"github.com/c2fo/testify/require"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+func Test_ReleasesGetHandler(t *testing.T) {
+	httpMethod := "GET"
+	url := "/apps/{app-slug}/releases"
+	handler := services.ReleasesGetHandler
+
+	testAppID := uuid.FromStringOrNil("4d9a2f5f-6c4c-4dc9-a5a0-3c2d0e52b4b1")
+	testIosVersion := models.AppVersion{
+		Record:           models.Record{ID: uuid.FromStringOrNil("de438ddc-98e5-4226-a5f4-fd2d53474879")},
+		Platform:         "ios",
+		BuildSlug:        "test-build-slug",
+		BuildNumber:      "12",
+		ArtifactInfoData: json.RawMessage(`{"version":"1.0"}`),
+		Status:           "ready",
+	}
+	testSupersededIosVersion := models.AppVersion{
+		Record:           models.Record{ID: uuid.FromStringOrNil("0b8a3bcb-9c6f-4f3e-9d43-2e7b0c1d5a61")},
+		Platform:         "ios",
+		BuildSlug:        "test-older-build-slug",
+		BuildNumber:      "11",
+		ArtifactInfoData: json.RawMessage(`{"version":"1.0"}`),
+		Status:           "superseded",
+	}
+	testAndroidVersion := models.AppVersion{
+		Record:           models.Record{ID: uuid.FromStringOrNil("9f7b06d1-e736-42d3-94c3-c2bcfda0463c")},
+		Platform:         "android",
+		BuildSlug:        "test-build-slug",
+		ArtifactInfoData: json.RawMessage(`{"version":"1.0","build_type":"debug"}`),
+		Status:           "published",
+	}
+	testReleases := []models.Release{
+		models.Release{
+			BuildSlug:   "test-build-slug",
+			Version:     "1.0",
+			Notes:       "Bug fixes on both platforms",
+			App:         models.App{AppSlug: "test-app-slug", BitriseAPIToken: "test-api-token"},
+			AppVersions: []models.AppVersion{testIosVersion, testSupersededIosVersion, testAndroidVersion},
+		},
+		models.Release{Version: "0.9"},
+	}
+
+	behavesAsServiceCravingHandler(t, httpMethod, url, handler, []string{"ReleaseService"}, ControllerTestCase{
+		contextElements: map[ctxpkg.RequestContextKey]interface{}{
+			services.ContextKeyAuthorizedAppID: uuid.NewV4(),
+		},
+		env: &env.AppEnv{
+			ReleaseService: &testReleaseService{},
+		},
+	})
+
+	behavesAsContextCravingHandler(t, httpMethod, url, handler, []ctxpkg.RequestContextKey{services.ContextKeyAuthorizedAppID}, ControllerTestCase{
+		contextElements: map[ctxpkg.RequestContextKey]interface{}{
+			services.ContextKeyAuthorizedAppID: uuid.NewV4(),
+		},
+		env: &env.AppEnv{
+			ReleaseService: &testReleaseService{},
+		},
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppID: testAppID,
+			},
+			env: &env.AppEnv{
+				ReleaseService: &testReleaseService{
+					findAllFn: func(app *models.App) ([]models.Release, error) {
+						require.Equal(t, testAppID, app.ID)
+						return testReleases, nil
+					},
+				},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.ReleasesGetResponse{
+				Data: []services.ReleaseData{
+					services.ReleaseData{
+						Release: testReleases[0],
+						AppVersions: []services.ReleaseAppVersionData{
+							services.ReleaseAppVersionData{AppVersion: testIosVersion, Version: "1.0", PublishEnabled: true, PublishStatus: "unpublished"},
+							services.ReleaseAppVersionData{AppVersion: testSupersededIosVersion, Version: "1.0", PublishEnabled: false, PublishStatus: "unpublished"},
+							services.ReleaseAppVersionData{AppVersion: testAndroidVersion, Version: "1.0", PublishEnabled: true, PublishStatus: "published"},
+						},
+						Ready:         true,
+						PublishStatus: "partially_published",
+					},
+					services.ReleaseData{
+						Release:       testReleases[1],
+						AppVersions:   []services.ReleaseAppVersionData{},
+						Ready:         false,
+						PublishStatus: "unpublished",
+					},
+				},
+			},
+		})
+	})
+
+	t.Run("when the service account of the app has errors", func(t *testing.T) {
+		release := models.Release{
+			App:         models.App{AndroidErrors: []string{"Service account file: Invalid JSON"}},
+			AppVersions: []models.AppVersion{testIosVersion, testAndroidVersion},
+		}
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppID: testAppID,
+			},
+			env: &env.AppEnv{
+				ReleaseService: &testReleaseService{
+					findAllFn: func(app *models.App) ([]models.Release, error) {
+						return []models.Release{release}, nil
+					},
+				},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: services.ReleasesGetResponse{
+				Data: []services.ReleaseData{
+					services.ReleaseData{
+						Release: release,
+						AppVersions: []services.ReleaseAppVersionData{
+							services.ReleaseAppVersionData{AppVersion: testIosVersion, Version: "1.0", PublishEnabled: true, PublishStatus: "unpublished"},
+							services.ReleaseAppVersionData{AppVersion: testAndroidVersion, Version: "1.0", PublishEnabled: false, PublishStatus: "published"},
+						},
+						Ready:         false,
+						PublishStatus: "partially_published",
+					},
+				},
+			},
+		})
+	})
+
+	t.Run("when error happens at finding releases", func(t *testing.T) {
+		performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+			contextElements: map[ctxpkg.RequestContextKey]interface{}{
+				services.ContextKeyAuthorizedAppID: testAppID,
+			},
+			env: &env.AppEnv{
+				ReleaseService: &testReleaseService{
+					findAllFn: func(app *models.App) ([]models.Release, error) {
+						return nil, errors.New("SOME-SQL-ERROR")
+					},
+				},
+			},
+			expectedInternalErr: "SQL Error: SOME-SQL-ERROR",
+		})
+	})
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"go.uber.org/zap"
+)
+
+// StartScheduledPublish runs the publish flow of a due scheduled publish, the
+// same way as a publish requested by hand. When the version can no longer be
+// published, the scheduled publish fails and the contacts of the app get
+// notified about it.
+func StartScheduledPublish(env *env.AppEnv, scheduledPublish *models.ScheduledPublish) error {
+	if !scheduledPublish.Pending() {
+		return nil
+	}
+	// the publish is marked as started before triggering anything, so that an
+	// overlapping run of the worker doesn't start it once more
+	if err := updateScheduledPublishStatus(env, scheduledPublish, models.ScheduledPublishStatusStarted, ""); err != nil {
+		return errors.WithStack(err)
+	}
+
+	appVersion := &scheduledPublish.AppVersion
+	appSettings, err := env.AppSettingsService.Find(&models.AppSettings{AppID: appVersion.AppID})
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	reason, err := startScheduledPublish(env, scheduledPublish, appVersion, appSettings)
+	if err != nil {
+		env.Logger.Error("Failed to start scheduled publish", zap.String("scheduled_publish_id", scheduledPublish.ID.String()), zap.Error(err))
+		reason = "publishing failed to start"
+	}
+	if reason == "" {
+		return createScheduledPublishEvent(env, appVersion, models.PublishTaskStatusPending, "Scheduled publishing was started")
+	}
+
+	if err := updateScheduledPublishStatus(env, scheduledPublish, models.ScheduledPublishStatusFailed, reason); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := createScheduledPublishEvent(env, appVersion, models.PublishTaskStatusFailed, fmt.Sprintf("Scheduled publishing failed: %s", reason)); err != nil {
+		return errors.WithStack(err)
+	}
+	return sendTaskFinishNotification(appVersion, env, false)
+}
+
+// startScheduledPublish publishes the app version of a scheduled publish, and
+// returns the reason when the version can't be published
+func startScheduledPublish(env *env.AppEnv, scheduledPublish *models.ScheduledPublish, appVersion *models.AppVersion, appSettings *models.AppSettings) (string, error) {
+	// the artifacts and the settings could have changed since the publish was
+	// scheduled
+	reason, err := publishDisabledReason(env, appVersion)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	if reason != "" {
+		return reason, nil
+	}
+	warning, err := buildCodeWarning(env, appVersion)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	if warning != "" {
+		return warning, nil
+	}
+
+	destinations, err := scheduledPublish.Destinations()
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	params := []AppVersionPublishDestinationParams{}
+	for _, destination := range destinations {
+		params = append(params, AppVersionPublishDestinationParams{ID: destination.ID, Track: destination.Track})
+	}
+	targets, err := publishTargets(appVersion.Platform, params)
+	if err != nil {
+		return err.Error(), nil
+	}
+	approvalWarnings, err := missingApprovals(env, appVersion, appSettings, targets)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	if len(approvalWarnings) > 0 {
+		return strings.Join(approvalWarnings, ", "), nil
+	}
+
+	_, _, err = publishAppVersion(env, appVersion, appSettings, targets, uuid.NewV4())
+	if isPublishTriggerError(err) {
+		return err.Error(), nil
+	}
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return "", nil
+}
+
+func updateScheduledPublishStatus(env *env.AppEnv, scheduledPublish *models.ScheduledPublish, status, reason string) error {
+	scheduledPublish.Status = status
+	scheduledPublish.FailureReason = reason
+	if err := env.ScheduledPublishService.Update(scheduledPublish, []string{"Status", "FailureReason"}); err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+	return nil
+}
+
+func createScheduledPublishEvent(env *env.AppEnv, appVersion *models.AppVersion, status, text string) error {
+	_, err := env.AppVersionEventService.Create(&models.AppVersionEvent{
+		Status:       status,
+		Text:         text,
+		AppVersionID: appVersion.ID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+	return nil
+}
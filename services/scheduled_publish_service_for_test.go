@@ -0,0 +1,50 @@
+package services_test
+
+import (
+	"time"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+)
+
+type testScheduledPublishService struct {
+	createFn     func(*models.ScheduledPublish) (*models.ScheduledPublish, error)
+	findFn       func(*models.ScheduledPublish) (*models.ScheduledPublish, error)
+	findAllFn    func(appVersion *models.AppVersion) ([]models.ScheduledPublish, error)
+	findAllDueFn func(now time.Time) ([]models.ScheduledPublish, error)
+	updateFn     func(*models.ScheduledPublish, []string) error
+}
+
+func (p *testScheduledPublishService) Create(scheduledPublish *models.ScheduledPublish) (*models.ScheduledPublish, error) {
+	if p.createFn != nil {
+		return p.createFn(scheduledPublish)
+	}
+	panic("You have to override ScheduledPublishService.Create function in tests")
+}
+
+func (p *testScheduledPublishService) Find(scheduledPublish *models.ScheduledPublish) (*models.ScheduledPublish, error) {
+	if p.findFn != nil {
+		return p.findFn(scheduledPublish)
+	}
+	panic("You have to override ScheduledPublishService.Find function in tests")
+}
+
+func (p *testScheduledPublishService) FindAll(appVersion *models.AppVersion) ([]models.ScheduledPublish, error) {
+	if p.findAllFn != nil {
+		return p.findAllFn(appVersion)
+	}
+	panic("You have to override ScheduledPublishService.FindAll function in tests")
+}
+
+func (p *testScheduledPublishService) FindAllDue(now time.Time) ([]models.ScheduledPublish, error) {
+	if p.findAllDueFn != nil {
+		return p.findAllDueFn(now)
+	}
+	panic("You have to override ScheduledPublishService.FindAllDue function in tests")
+}
+
+func (p *testScheduledPublishService) Update(scheduledPublish *models.ScheduledPublish, whitelist []string) error {
+	if p.updateFn != nil {
+		return p.updateFn(scheduledPublish, whitelist)
+	}
+	panic("You have to override ScheduledPublishService.Update function in tests")
+}
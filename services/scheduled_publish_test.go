@@ -0,0 +1,228 @@
+package services_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bitrise-io/addons-ship-backend/bitrise"
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/addons-ship-backend/services"
+	"github.com/bitrise-io/api-utils/security"
+	"github.com/c2fo/testify/require"
+	"github.com/jinzhu/gorm"
+	uuid "github.com/satori/go.uuid"
+)
+
+func Test_StartScheduledPublish(t *testing.T) {
+	testAppVersionID := uuid.FromStringOrNil("de438ddc-98e5-4226-a5f4-fd2d53474879")
+	testTaskIdentifier := uuid.FromStringOrNil("13a94c5d-4609-404e-ae69-c625e93b8b71")
+
+	newScheduledPublish := func() *models.ScheduledPublish {
+		return &models.ScheduledPublish{
+			Status:           models.ScheduledPublishStatusScheduled,
+			DestinationsData: json.RawMessage(`[{"id":"google-play","track":"beta"}]`),
+			AppVersionID:     testAppVersionID,
+			AppVersion: models.AppVersion{
+				Record:           models.Record{ID: testAppVersionID},
+				App:              models.App{AppSlug: "test-app-slug", BitriseAPIToken: "test-api-token"},
+				Platform:         "android",
+				Status:           models.AppVersionStatusReady,
+				ArtifactInfoData: json.RawMessage(`{"build_type":"release"}`),
+				AppStoreInfoData: json.RawMessage(`{}`),
+			},
+		}
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		scheduledPublishStatuses := []string{}
+		publishTasks := []models.PublishTask{}
+		events := []models.AppVersionEvent{}
+		scheduledPublish := newScheduledPublish()
+		err := services.StartScheduledPublish(&env.AppEnv{
+			ScheduledPublishService: &testScheduledPublishService{
+				updateFn: func(scheduledPublish *models.ScheduledPublish, whitelist []string) error {
+					require.Equal(t, []string{"Status", "FailureReason"}, whitelist)
+					scheduledPublishStatuses = append(scheduledPublishStatuses, scheduledPublish.Status)
+					return nil
+				},
+			},
+			AppSettingsService: &testAppSettingsService{
+				findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+					return &models.AppSettings{}, nil
+				},
+			},
+			AppVersionService: &testAppVersionService{
+				latestPublishedFn: func(*models.AppVersion) (*models.AppVersion, error) {
+					return nil, gorm.ErrRecordNotFound
+				},
+				updateFn: func(appVersion *models.AppVersion, whitelist []string) ([]error, error) {
+					require.Equal(t, "publishing", appVersion.Status)
+					return nil, nil
+				},
+			},
+			BitriseAPI: &testBitriseAPI{
+				getArtifactsFn: func(apiToken string, appSlug string, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
+					require.Equal(t, "test-api-token", apiToken)
+					return []bitrise.ArtifactListElementResponseModel{}, nil
+				},
+				triggerDENTaskFn: func(params bitrise.TaskParams) (*bitrise.TriggerResponse, error) {
+					require.Equal(t, "resign_android", params.Workflow)
+					return &bitrise.TriggerResponse{TaskIdentifier: testTaskIdentifier}, nil
+				},
+			},
+			JWTService: &security.JWTMock{
+				SignFn: func(token string) (string, error) {
+					return "", nil
+				},
+			},
+			PublishTaskService: &testPublishTaskService{
+				createFn: func(publishTask *models.PublishTask) (*models.PublishTask, error) {
+					publishTasks = append(publishTasks, *publishTask)
+					return publishTask, nil
+				},
+			},
+			AppVersionEventService: &testAppVersionEventService{
+				createFn: func(event *models.AppVersionEvent) (*models.AppVersionEvent, error) {
+					events = append(events, *event)
+					return event, nil
+				},
+			},
+		}, scheduledPublish)
+		require.NoError(t, err)
+		require.Equal(t, []string{"started"}, scheduledPublishStatuses)
+		require.Len(t, publishTasks, 1)
+		require.Equal(t, testTaskIdentifier, publishTasks[0].TaskID)
+		require.Equal(t, "google-play", publishTasks[0].Destination)
+		require.Equal(t, "beta", publishTasks[0].Track)
+		require.Equal(t, []models.AppVersionEvent{
+			{Status: "pending", Text: "Scheduled publishing was started", AppVersionID: testAppVersionID},
+		}, events)
+	})
+
+	t.Run("when the version can no longer be published", func(t *testing.T) {
+		scheduledPublishStatuses := []string{}
+		events := []models.AppVersionEvent{}
+		emailSent := false
+		scheduledPublish := newScheduledPublish()
+		scheduledPublish.AppVersion.App.AndroidErrors = []string{"Service account file: Invalid JSON"}
+		err := services.StartScheduledPublish(&env.AppEnv{
+			AddonFrontendHostURL: "http://ship.frontend.url",
+			ScheduledPublishService: &testScheduledPublishService{
+				updateFn: func(scheduledPublish *models.ScheduledPublish, whitelist []string) error {
+					scheduledPublishStatuses = append(scheduledPublishStatuses, scheduledPublish.Status)
+					return nil
+				},
+			},
+			AppSettingsService: &testAppSettingsService{
+				findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+					return &models.AppSettings{}, nil
+				},
+			},
+			AppVersionEventService: &testAppVersionEventService{
+				createFn: func(event *models.AppVersionEvent) (*models.AppVersionEvent, error) {
+					events = append(events, *event)
+					return event, nil
+				},
+			},
+			AppContactService: &testAppContactService{
+				findAllFn: func(app *models.App) ([]models.AppContact, error) {
+					require.Equal(t, "test-app-slug", app.AppSlug)
+					return []models.AppContact{{Email: "someones@email.addr"}}, nil
+				},
+			},
+			BitriseAPI: &testBitriseAPI{
+				getArtifactsFn: func(apiToken string, appSlug string, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
+					return []bitrise.ArtifactListElementResponseModel{}, nil
+				},
+				getAppDetailsFn: func(apiToken string, appSlug string) (*bitrise.AppDetails, error) {
+					return &bitrise.AppDetails{Title: "Test App"}, nil
+				},
+			},
+			Mailer: &testMailer{
+				sendEmailPublishFn: func(appVersion *models.AppVersion, contacts []models.AppContact, appDetails *bitrise.AppDetails, frontendBaseURL string, publishSucceeded bool) error {
+					require.Equal(t, testAppVersionID, appVersion.ID)
+					require.Equal(t, []models.AppContact{{Email: "someones@email.addr"}}, contacts)
+					require.Equal(t, "http://ship.frontend.url", frontendBaseURL)
+					require.False(t, publishSucceeded)
+					emailSent = true
+					return nil
+				},
+			},
+		}, scheduledPublish)
+		require.NoError(t, err)
+		require.Equal(t, []string{"started", "failed"}, scheduledPublishStatuses)
+		require.Equal(t, "The service account of the app has errors: Service account file: Invalid JSON", scheduledPublish.FailureReason)
+		require.Equal(t, []models.AppVersionEvent{
+			{Status: "failed", Text: "Scheduled publishing failed: The service account of the app has errors: Service account file: Invalid JSON", AppVersionID: testAppVersionID},
+		}, events)
+		require.True(t, emailSent)
+	})
+
+	t.Run("when the version is missing approvals", func(t *testing.T) {
+		scheduledPublishStatuses := []string{}
+		events := []models.AppVersionEvent{}
+		scheduledPublish := newScheduledPublish()
+		err := services.StartScheduledPublish(&env.AppEnv{
+			ScheduledPublishService: &testScheduledPublishService{
+				updateFn: func(scheduledPublish *models.ScheduledPublish, whitelist []string) error {
+					scheduledPublishStatuses = append(scheduledPublishStatuses, scheduledPublish.Status)
+					return nil
+				},
+			},
+			AppSettingsService: &testAppSettingsService{
+				findFn: func(appSettings *models.AppSettings) (*models.AppSettings, error) {
+					return &models.AppSettings{ApprovalRequirementsData: json.RawMessage(`[{"approvals":1}]`)}, nil
+				},
+			},
+			AppVersionService: &testAppVersionService{
+				latestPublishedFn: func(*models.AppVersion) (*models.AppVersion, error) {
+					return nil, gorm.ErrRecordNotFound
+				},
+			},
+			ApprovalService: &testApprovalService{
+				findAllFn: func(appVersion *models.AppVersion) ([]models.Approval, error) {
+					return []models.Approval{{Approver: "qa@bitrise.io", Status: "revoked"}}, nil
+				},
+			},
+			AppVersionEventService: &testAppVersionEventService{
+				createFn: func(event *models.AppVersionEvent) (*models.AppVersionEvent, error) {
+					events = append(events, *event)
+					return event, nil
+				},
+			},
+			AppContactService: &testAppContactService{
+				findAllFn: func(app *models.App) ([]models.AppContact, error) {
+					return []models.AppContact{}, nil
+				},
+			},
+			BitriseAPI: &testBitriseAPI{
+				getArtifactsFn: func(apiToken string, appSlug string, buildSlug string) ([]bitrise.ArtifactListElementResponseModel, error) {
+					return []bitrise.ArtifactListElementResponseModel{}, nil
+				},
+				getAppDetailsFn: func(apiToken string, appSlug string) (*bitrise.AppDetails, error) {
+					return &bitrise.AppDetails{Title: "Test App"}, nil
+				},
+			},
+			Mailer: &testMailer{
+				sendEmailPublishFn: func(appVersion *models.AppVersion, contacts []models.AppContact, appDetails *bitrise.AppDetails, frontendBaseURL string, publishSucceeded bool) error {
+					return nil
+				},
+			},
+		}, scheduledPublish)
+		require.NoError(t, err)
+		require.Equal(t, []string{"started", "failed"}, scheduledPublishStatuses)
+		require.Equal(t, "Publishing requires 1 approvals, the version has 0", scheduledPublish.FailureReason)
+		require.Equal(t, []models.AppVersionEvent{
+			{Status: "failed", Text: "Scheduled publishing failed: Publishing requires 1 approvals, the version has 0", AppVersionID: testAppVersionID},
+		}, events)
+	})
+
+	t.Run("when the publish is canceled", func(t *testing.T) {
+		scheduledPublish := newScheduledPublish()
+		scheduledPublish.Status = models.ScheduledPublishStatusCanceled
+		require.NoError(t, services.StartScheduledPublish(&env.AppEnv{
+			ScheduledPublishService: &testScheduledPublishService{},
+		}, scheduledPublish))
+	})
+}
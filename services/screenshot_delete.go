@@ -46,6 +46,9 @@ func ScreenshotDeleteHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Req
 	if err != nil {
 		return errors.Wrap(err, "SQL Error")
 	}
+	if err := invalidateApprovals(env, &models.AppVersion{Record: models.Record{ID: screenshot.AppVersionID}}); err != nil {
+		return errors.WithStack(err)
+	}
 
 	return httpresponse.RespondWithSuccess(w, ScreenshotDeleteResponse{
 		Data: screenshot,
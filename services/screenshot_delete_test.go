@@ -51,6 +51,11 @@ func Test_ScreenshotDeleteHandler(t *testing.T) {
 						return testScreenshot, nil
 					},
 				},
+				ApprovalService: &testApprovalService{
+					invalidateAllFn: func(appVersion *models.AppVersion) (int, error) {
+						return 0, nil
+					},
+				},
 				AWS: &providers.AWSMock{
 					DeleteObjectFn: func(path string) error {
 						return nil
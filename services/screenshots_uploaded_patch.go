@@ -43,6 +43,10 @@ func ScreenshotsUploadedPatchHandler(env *env.AppEnv, w http.ResponseWriter, r *
 	if env.AWS == nil {
 		return errors.New("No AWS Provider defined for handler")
 	}
+	// approvals were given to the previous screenshots
+	if err := invalidateApprovals(env, &models.AppVersion{Record: models.Record{ID: authorizedAppVersionID}}); err != nil {
+		return errors.WithStack(err)
+	}
 	responseData, err := newScreenshotGetResponseData(screenshotsToUpdate, env.AWS)
 	if err != nil {
 		return errors.WithStack(err)
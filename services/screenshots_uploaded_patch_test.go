@@ -63,6 +63,11 @@ func Test_ScreenshotsUploadedPatchHandler(t *testing.T) {
 						return nil, nil
 					},
 				},
+				ApprovalService: &testApprovalService{
+					invalidateAllFn: func(appVersion *models.AppVersion) (int, error) {
+						return 0, nil
+					},
+				},
 				AWS: &providers.AWSMock{
 					GeneratePresignedGETURLFn: func(path string, expiration time.Duration) (string, error) {
 						return "", nil
@@ -145,6 +150,11 @@ func Test_ScreenshotsUploadedPatchHandler(t *testing.T) {
 						return nil, nil
 					},
 				},
+				ApprovalService: &testApprovalService{
+					invalidateAllFn: func(appVersion *models.AppVersion) (int, error) {
+						return 0, nil
+					},
+				},
 				AWS: &providers.AWSMock{
 					GeneratePresignedGETURLFn: func(path string, expiration time.Duration) (string, error) {
 						return fmt.Sprintf("http://presigned.aws.url/%s", path), nil
@@ -275,6 +285,11 @@ func Test_ScreenshotsUploadedPatchHandler(t *testing.T) {
 						return nil, nil
 					},
 				},
+				ApprovalService: &testApprovalService{
+					invalidateAllFn: func(appVersion *models.AppVersion) (int, error) {
+						return 0, nil
+					},
+				},
 				AWS: &providers.AWSMock{
 					GeneratePresignedGETURLFn: func(path string, expiration time.Duration) (string, error) {
 						return "", errors.New("SOME-AWS-ERROR")
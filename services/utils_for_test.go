@@ -113,6 +113,15 @@ func behavesAsServiceCravingHandler(t *testing.T, method, url string, handler fu
 			} else if sn == "AppContactService" {
 				controllerTestCase.env.AppContactService = nil
 				controllerTestCase.expectedInternalErr = "No App Contact Service defined for handler"
+			} else if sn == "ReleaseService" {
+				controllerTestCase.env.ReleaseService = nil
+				controllerTestCase.expectedInternalErr = "No Release Service defined for handler"
+			} else if sn == "ScheduledPublishService" {
+				controllerTestCase.env.ScheduledPublishService = nil
+				controllerTestCase.expectedInternalErr = "No Scheduled Publish Service defined for handler"
+			} else if sn == "ApprovalService" {
+				controllerTestCase.env.ApprovalService = nil
+				controllerTestCase.expectedInternalErr = "No Approval Service defined for handler"
 			} else if sn == "RequestParams" {
 				controllerTestCase.env.RequestParams = nil
 				controllerTestCase.expectedInternalErr = "No RequestParams defined for handler"
@@ -125,6 +134,9 @@ func behavesAsServiceCravingHandler(t *testing.T, method, url string, handler fu
 			} else if sn == "WorkerService" {
 				controllerTestCase.env.WorkerService = nil
 				controllerTestCase.expectedInternalErr = "No Worker Service defined for handler"
+			} else if sn == "TimeService" {
+				controllerTestCase.env.TimeService = nil
+				controllerTestCase.expectedInternalErr = "No Time Service defined for handler"
 			} else if sn == "Mailer" {
 				controllerTestCase.env.Mailer = nil
 				controllerTestCase.expectedInternalErr = "No Mailer defined for handler"
@@ -149,9 +161,18 @@ func behavesAsContextCravingHandler(t *testing.T, method, url string, handler fu
 			} else if ck == services.ContextKeyAuthorizedAppContactID {
 				controllerTestCase.contextElements[ck] = nil
 				controllerTestCase.expectedInternalErr = "Authorized App Contact ID not found in Context"
+			} else if ck == services.ContextKeyAuthorizedReleaseID {
+				controllerTestCase.contextElements[ck] = nil
+				controllerTestCase.expectedInternalErr = "Authorized Release ID not found in Context"
 			} else if ck == services.ContextKeyAuthorizedScreenshotID {
 				controllerTestCase.contextElements[ck] = nil
 				controllerTestCase.expectedInternalErr = "Authorized App Version Screenshot ID not found in Context"
+			} else if ck == services.ContextKeyAuthorizedScheduledPublishID {
+				controllerTestCase.contextElements[ck] = nil
+				controllerTestCase.expectedInternalErr = "Authorized Scheduled Publish ID not found in Context"
+			} else if ck == services.ContextKeyAuthorizedApprovalID {
+				controllerTestCase.contextElements[ck] = nil
+				controllerTestCase.expectedInternalErr = "Authorized Approval ID not found in Context"
 			} else {
 
 				t.Fatalf("Invalid context element name defined: %s", ck)
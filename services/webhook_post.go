@@ -57,6 +57,9 @@ func WebhookPostHandler(env *env.AppEnv, w http.ResponseWriter, r *http.Request)
 	if env.AppContactService == nil {
 		return errors.New("No App Contact Service defined for handler")
 	}
+	if env.PublishTaskService == nil {
+		return errors.New("No Publish Task Service defined for handler")
+	}
 
 	var params WebhookPayload
 	defer httprequest.BodyCloseWithErrorLog(r)
@@ -8,7 +8,9 @@ import (
 	"github.com/bitrise-io/addons-ship-backend/env"
 	"github.com/bitrise-io/addons-ship-backend/models"
 	"github.com/bitrise-io/api-utils/httpresponse"
+	"github.com/jinzhu/gorm"
 	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
 )
 
 func webhookPostStatusHelper(env *env.AppEnv, w http.ResponseWriter, r *http.Request, params WebhookPayload, appVersion *models.AppVersion) error {
@@ -18,9 +20,14 @@ func webhookPostStatusHelper(env *env.AppEnv, w http.ResponseWriter, r *http.Req
 	}
 	switch data.NewStatus {
 	case "started":
-		_, err := env.AppVersionEventService.Create(&models.AppVersionEvent{
+		publishTask, err := updatePublishTaskStatus(env, params.TaskID, models.PublishTaskStatusInProgress)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		startedText, _, _ := publishTaskEventTexts(publishTask)
+		_, err = env.AppVersionEventService.Create(&models.AppVersionEvent{
 			Status:       "in_progress",
-			Text:         "Publishing has started",
+			Text:         startedText,
 			AppVersionID: appVersion.ID,
 		})
 		if err != nil {
@@ -30,15 +37,25 @@ func webhookPostStatusHelper(env *env.AppEnv, w http.ResponseWriter, r *http.Req
 		if err != nil {
 			return errors.WithStack(err)
 		}
+		if publishesAppVersion(publishTask) {
+			err = updateAppVersionStatus(env, appVersion, models.AppVersionStatusPublishing)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+		}
 		return httpresponse.RespondWithSuccess(w, httpresponse.StandardErrorRespModel{Message: "ok"})
 	case "finished":
-		var eventText, eventStatus string
+		eventStatus := models.PublishTaskStatusSuccess
 		if data.ExitCode != 0 {
-			eventStatus = "failed"
-			eventText = "Failed to publish"
-		} else {
-			eventStatus = "success"
-			eventText = "Successfully published"
+			eventStatus = models.PublishTaskStatusFailed
+		}
+		publishTask, err := updatePublishTaskStatus(env, params.TaskID, eventStatus)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		_, eventText, failedText := publishTaskEventTexts(publishTask)
+		if eventStatus == models.PublishTaskStatusFailed {
+			eventText = failedText
 		}
 		event, err := env.AppVersionEventService.Create(&models.AppVersionEvent{
 			Status:       eventStatus,
@@ -56,10 +73,27 @@ func webhookPostStatusHelper(env *env.AppEnv, w http.ResponseWriter, r *http.Req
 		if err != nil {
 			return errors.Wrap(err, "Worker error")
 		}
-		err = sendTaskFinishNotification(&event.AppVersion, env, data.ExitCode)
+		if !publishesAppVersion(publishTask) {
+			return httpresponse.RespondWithSuccess(w, httpresponse.StandardErrorRespModel{Message: "ok"})
+		}
+		batchFinished, batchSucceeded, err := publishTaskBatchResult(env, appVersion, publishTask)
 		if err != nil {
 			return errors.WithStack(err)
 		}
+		if batchFinished {
+			status := models.AppVersionStatusFailed
+			if batchSucceeded && eventStatus == models.PublishTaskStatusSuccess {
+				status = models.AppVersionStatusPublished
+			}
+			err = updateAppVersionStatus(env, appVersion, status)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			err = sendTaskFinishNotification(&event.AppVersion, env, batchSucceeded)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+		}
 		env.AnalyticsClient.PublishFinished(appVersion.App.AppSlug, appVersion.ID, eventStatus)
 		return httpresponse.RespondWithSuccess(w, httpresponse.StandardErrorRespModel{Message: "ok"})
 	default:
@@ -80,7 +114,75 @@ func parseStatusData(data interface{}) (StatusData, error) {
 	return statusData, nil
 }
 
-func sendTaskFinishNotification(appVersion *models.AppVersion, env *env.AppEnv, exitCode int) error {
+// updatePublishTaskStatus sets the status of the publish task of the webhook.
+// Tasks triggered before publish tasks had a status are not updated, nil is
+// returned for them.
+func updatePublishTaskStatus(env *env.AppEnv, taskID uuid.UUID, status string) (*models.PublishTask, error) {
+	publishTask, err := env.PublishTaskService.Find(&models.PublishTask{TaskID: taskID})
+	switch {
+	case errors.Cause(err) == gorm.ErrRecordNotFound:
+		return nil, nil
+	case err != nil:
+		return nil, errors.Wrap(err, "SQL Error")
+	}
+	publishTask.Status = status
+	err = env.PublishTaskService.Update(publishTask, []string{"Status"})
+	if err != nil {
+		return nil, errors.Wrap(err, "SQL Error")
+	}
+	return publishTask, nil
+}
+
+// publishesAppVersion tells whether a publish task changes the lifecycle status
+// of its app version: the rollout and the promote tasks don't. A task without a
+// record or an action, like the ones created before the actions, is handled as
+// a publish.
+func publishesAppVersion(publishTask *models.PublishTask) bool {
+	return publishTask == nil || publishTask.Action == "" || publishTask.Action == models.PublishTaskActionPublish
+}
+
+// publishTaskEventTexts returns the texts of the started, the succeeded and the
+// failed events of a publish task, based on its action.
+func publishTaskEventTexts(publishTask *models.PublishTask) (string, string, string) {
+	if publishTask != nil {
+		switch publishTask.Action {
+		case models.PublishTaskActionRollout:
+			return "Rollout change has started", "Successfully changed the rollout", "Failed to change the rollout"
+		case models.PublishTaskActionPromote:
+			return "Promotion has started", "Successfully promoted", "Failed to promote"
+		}
+	}
+	return "Publishing has started", "Successfully published", "Failed to publish"
+}
+
+// publishTaskBatchResult tells whether every task published together with the
+// given one has finished, and whether all of them succeeded. A task without
+// a batch is handled as a batch of its own.
+func publishTaskBatchResult(env *env.AppEnv, appVersion *models.AppVersion, publishTask *models.PublishTask) (bool, bool, error) {
+	if publishTask == nil || uuid.Equal(publishTask.BatchID, uuid.UUID{}) {
+		return true, publishTask == nil || publishTask.Status == models.PublishTaskStatusSuccess, nil
+	}
+	publishTasks, err := env.PublishTaskService.FindAll(appVersion)
+	if err != nil {
+		return false, false, errors.Wrap(err, "SQL Error")
+	}
+	succeeded := true
+	for _, task := range publishTasks {
+		if !uuid.Equal(task.BatchID, publishTask.BatchID) {
+			continue
+		}
+		if uuid.Equal(task.ID, publishTask.ID) {
+			task = *publishTask
+		}
+		if !task.Finished() {
+			return false, false, nil
+		}
+		succeeded = succeeded && task.Status == models.PublishTaskStatusSuccess
+	}
+	return true, succeeded, nil
+}
+
+func sendTaskFinishNotification(appVersion *models.AppVersion, env *env.AppEnv, success bool) error {
 	contacts, err := env.AppContactService.FindAll(&appVersion.App)
 	if err != nil {
 		return errors.WithStack(err)
@@ -89,5 +191,5 @@ func sendTaskFinishNotification(appVersion *models.AppVersion, env *env.AppEnv,
 	if err != nil {
 		return errors.WithStack(err)
 	}
-	return env.Mailer.SendEmailPublish(appVersion, contacts, appDetais, env.AddonFrontendHostURL, exitCode == 0)
+	return env.Mailer.SendEmailPublish(appVersion, contacts, appDetais, env.AddonFrontendHostURL, success)
 }
@@ -12,6 +12,7 @@ import (
 	ctxpkg "github.com/bitrise-io/api-utils/context"
 	"github.com/bitrise-io/api-utils/httpresponse"
 	"github.com/c2fo/testify/require"
+	"github.com/jinzhu/gorm"
 	"github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 )
@@ -21,7 +22,7 @@ func Test_WebhookPostHandler(t *testing.T) {
 	url := "/task-webhook"
 	handler := services.WebhookPostHandler
 
-	behavesAsServiceCravingHandler(t, httpMethod, url, handler, []string{"AppVersionService", "AppVersionEventService", "WorkerService", "BitriseAPI", "AppContactService"}, ControllerTestCase{
+	behavesAsServiceCravingHandler(t, httpMethod, url, handler, []string{"AppVersionService", "AppVersionEventService", "WorkerService", "BitriseAPI", "AppContactService", "PublishTaskService"}, ControllerTestCase{
 		contextElements: map[ctxpkg.RequestContextKey]interface{}{
 			services.ContextKeyAuthorizedAppVersionID: uuid.NewV4(),
 		},
@@ -30,6 +31,7 @@ func Test_WebhookPostHandler(t *testing.T) {
 			AppVersionEventService: &testAppVersionEventService{},
 			WorkerService:          &testWorkerService{},
 			BitriseAPI:             &testBitriseAPI{},
+			PublishTaskService:     &testPublishTaskService{},
 			AppContactService:      &testAppContactService{},
 			AnalyticsClient:        &testAnalyticsClient{},
 		},
@@ -66,9 +68,10 @@ func Test_WebhookPostHandler(t *testing.T) {
 							return nil
 						},
 					},
-					BitriseAPI:        &testBitriseAPI{},
-					AppContactService: &testAppContactService{},
-					AnalyticsClient:   &testAnalyticsClient{},
+					BitriseAPI:         &testBitriseAPI{},
+					PublishTaskService: &testPublishTaskService{},
+					AppContactService:  &testAppContactService{},
+					AnalyticsClient:    &testAnalyticsClient{},
 				},
 				requestBody:        `{"type_id":"log"}`,
 				expectedStatusCode: http.StatusOK,
@@ -100,9 +103,10 @@ func Test_WebhookPostHandler(t *testing.T) {
 							return nil
 						},
 					},
-					BitriseAPI:        &testBitriseAPI{},
-					AppContactService: &testAppContactService{},
-					AnalyticsClient:   &testAnalyticsClient{},
+					BitriseAPI:         &testBitriseAPI{},
+					PublishTaskService: &testPublishTaskService{},
+					AppContactService:  &testAppContactService{},
+					AnalyticsClient:    &testAnalyticsClient{},
 				},
 				requestBody:        `{"type_id":"log","task_id":"96e72f92-6e4c-40d5-b829-48a1ea6440a1","data":{"chunk":"My awesome log chunk","position":1}}`,
 				expectedStatusCode: http.StatusOK,
@@ -127,9 +131,10 @@ func Test_WebhookPostHandler(t *testing.T) {
 							return nil
 						},
 					},
-					BitriseAPI:        &testBitriseAPI{},
-					AppContactService: &testAppContactService{},
-					AnalyticsClient:   &testAnalyticsClient{},
+					BitriseAPI:         &testBitriseAPI{},
+					PublishTaskService: &testPublishTaskService{},
+					AppContactService:  &testAppContactService{},
+					AnalyticsClient:    &testAnalyticsClient{},
 				},
 				requestBody:        `{"type_id":"log","data":"invalid JSON"}`,
 				expectedStatusCode: http.StatusBadRequest,
@@ -154,9 +159,10 @@ func Test_WebhookPostHandler(t *testing.T) {
 							return errors.New("SOME-WORKER-ERROR")
 						},
 					},
-					BitriseAPI:        &testBitriseAPI{},
-					AppContactService: &testAppContactService{},
-					AnalyticsClient:   &testAnalyticsClient{},
+					BitriseAPI:         &testBitriseAPI{},
+					PublishTaskService: &testPublishTaskService{},
+					AppContactService:  &testAppContactService{},
+					AnalyticsClient:    &testAnalyticsClient{},
 				},
 				requestBody:         `{"type_id":"log"}`,
 				expectedInternalErr: "Worker error: SOME-WORKER-ERROR",
@@ -166,6 +172,8 @@ func Test_WebhookPostHandler(t *testing.T) {
 
 	t.Run("when incoming webhook has 'status' type", func(t *testing.T) {
 		testAppVersionID := uuid.FromStringOrNil("e2915475-381d-4252-b5ec-c0fe511b12e8")
+		testPublishTaskID := uuid.FromStringOrNil("2dd6b2a5-ad8c-4f4f-9e3c-ee7e2ad0a2f4")
+		testBatchID := uuid.FromStringOrNil("b5e9b1a5-3a48-4b5b-9a4e-2f0a5d0f8d7c")
 
 		t.Run("when status data has invalid format", func(t *testing.T) {
 			performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
@@ -189,9 +197,10 @@ func Test_WebhookPostHandler(t *testing.T) {
 							return nil
 						},
 					},
-					BitriseAPI:        &testBitriseAPI{},
-					AppContactService: &testAppContactService{},
-					AnalyticsClient:   &testAnalyticsClient{},
+					BitriseAPI:         &testBitriseAPI{},
+					PublishTaskService: &testPublishTaskService{},
+					AppContactService:  &testAppContactService{},
+					AnalyticsClient:    &testAnalyticsClient{},
 				},
 				requestBody:        `{"type_id":"status","data":"some invalid JSON"}`,
 				expectedStatusCode: http.StatusBadRequest,
@@ -221,9 +230,10 @@ func Test_WebhookPostHandler(t *testing.T) {
 							return nil
 						},
 					},
-					BitriseAPI:        &testBitriseAPI{},
-					AppContactService: &testAppContactService{},
-					AnalyticsClient:   &testAnalyticsClient{},
+					BitriseAPI:         &testBitriseAPI{},
+					PublishTaskService: &testPublishTaskService{},
+					AppContactService:  &testAppContactService{},
+					AnalyticsClient:    &testAnalyticsClient{},
 				},
 				requestBody:         `{"type_id":"status","data":{"new_status":"some invalid status"}}`,
 				expectedInternalErr: "Invalid status of incoming webhook: some invalid status",
@@ -238,6 +248,11 @@ func Test_WebhookPostHandler(t *testing.T) {
 					},
 					env: &env.AppEnv{
 						AppVersionService: &testAppVersionService{
+							updateFn: func(appVersion *models.AppVersion, whitelist []string) ([]error, error) {
+								require.Equal(t, []string{"Status"}, whitelist)
+								require.Equal(t, "publishing", appVersion.Status)
+								return nil, nil
+							},
 							findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
 								return &models.AppVersion{}, nil
 							},
@@ -253,7 +268,12 @@ func Test_WebhookPostHandler(t *testing.T) {
 								return nil
 							},
 						},
-						BitriseAPI:        &testBitriseAPI{},
+						BitriseAPI: &testBitriseAPI{},
+						PublishTaskService: &testPublishTaskService{
+							findFn: func(*models.PublishTask) (*models.PublishTask, error) {
+								return nil, gorm.ErrRecordNotFound
+							},
+						},
 						AppContactService: &testAppContactService{},
 						AnalyticsClient:   &testAnalyticsClient{},
 					},
@@ -270,6 +290,11 @@ func Test_WebhookPostHandler(t *testing.T) {
 					},
 					env: &env.AppEnv{
 						AppVersionService: &testAppVersionService{
+							updateFn: func(appVersion *models.AppVersion, whitelist []string) ([]error, error) {
+								require.Equal(t, []string{"Status"}, whitelist)
+								require.Equal(t, "publishing", appVersion.Status)
+								return nil, nil
+							},
 							findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
 								return &models.AppVersion{Record: models.Record{ID: testAppVersionID}}, nil
 							},
@@ -294,7 +319,18 @@ func Test_WebhookPostHandler(t *testing.T) {
 								return nil
 							},
 						},
-						BitriseAPI:        &testBitriseAPI{},
+						BitriseAPI: &testBitriseAPI{},
+						PublishTaskService: &testPublishTaskService{
+							findFn: func(publishTask *models.PublishTask) (*models.PublishTask, error) {
+								require.Equal(t, "96e72f92-6e4c-40d5-b829-48a1ea6440a1", publishTask.TaskID.String())
+								return &models.PublishTask{Record: models.Record{ID: testPublishTaskID}, TaskID: publishTask.TaskID}, nil
+							},
+							updateFn: func(publishTask *models.PublishTask, whitelist []string) error {
+								require.Equal(t, "in_progress", publishTask.Status)
+								require.Equal(t, []string{"Status"}, whitelist)
+								return nil
+							},
+						},
 						AppContactService: &testAppContactService{},
 						AnalyticsClient:   &testAnalyticsClient{},
 					},
@@ -304,6 +340,47 @@ func Test_WebhookPostHandler(t *testing.T) {
 				})
 			})
 
+			t.Run("ok - when the task changes the rollout of the version", func(t *testing.T) {
+				performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+					contextElements: map[ctxpkg.RequestContextKey]interface{}{
+						services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+					},
+					env: &env.AppEnv{
+						AppVersionService: &testAppVersionService{
+							findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+								return &models.AppVersion{Record: models.Record{ID: testAppVersionID}, Status: "published"}, nil
+							},
+						},
+						AppVersionEventService: &testAppVersionEventService{
+							createFn: func(event *models.AppVersionEvent) (*models.AppVersionEvent, error) {
+								require.Equal(t, "Rollout change has started", event.Text)
+								return event, nil
+							},
+						},
+						WorkerService:     &testWorkerService{},
+						BitriseAPI:        &testBitriseAPI{},
+						AppContactService: &testAppContactService{},
+						Redis: &redis.Mock{
+							SetFn: func(string, interface{}, int) error {
+								return nil
+							},
+						},
+						PublishTaskService: &testPublishTaskService{
+							findFn: func(publishTask *models.PublishTask) (*models.PublishTask, error) {
+								return &models.PublishTask{Record: models.Record{ID: testPublishTaskID}, TaskID: publishTask.TaskID, Action: "rollout"}, nil
+							},
+							updateFn: func(publishTask *models.PublishTask, whitelist []string) error {
+								require.Equal(t, "in_progress", publishTask.Status)
+								return nil
+							},
+						},
+					},
+					requestBody:        `{"type_id":"status","task_id":"96e72f92-6e4c-40d5-b829-48a1ea6440a1","data":{"new_status":"started"}}`,
+					expectedStatusCode: http.StatusOK,
+					expectedResponse:   httpresponse.StandardErrorRespModel{Message: "ok"},
+				})
+			})
+
 			t.Run("when error happens at creating new app version event", func(t *testing.T) {
 				performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
 					contextElements: map[ctxpkg.RequestContextKey]interface{}{
@@ -326,7 +403,12 @@ func Test_WebhookPostHandler(t *testing.T) {
 								return nil
 							},
 						},
-						BitriseAPI:        &testBitriseAPI{},
+						BitriseAPI: &testBitriseAPI{},
+						PublishTaskService: &testPublishTaskService{
+							findFn: func(*models.PublishTask) (*models.PublishTask, error) {
+								return nil, gorm.ErrRecordNotFound
+							},
+						},
 						AppContactService: &testAppContactService{},
 						AnalyticsClient:   &testAnalyticsClient{},
 					},
@@ -357,7 +439,12 @@ func Test_WebhookPostHandler(t *testing.T) {
 								return errors.New("SOME-REDIS-ERROR")
 							},
 						},
-						BitriseAPI:        &testBitriseAPI{},
+						BitriseAPI: &testBitriseAPI{},
+						PublishTaskService: &testPublishTaskService{
+							findFn: func(*models.PublishTask) (*models.PublishTask, error) {
+								return nil, gorm.ErrRecordNotFound
+							},
+						},
 						AppContactService: &testAppContactService{},
 						AnalyticsClient:   &testAnalyticsClient{},
 					},
@@ -375,6 +462,11 @@ func Test_WebhookPostHandler(t *testing.T) {
 					},
 					env: &env.AppEnv{
 						AppVersionService: &testAppVersionService{
+							updateFn: func(appVersion *models.AppVersion, whitelist []string) ([]error, error) {
+								require.Equal(t, []string{"Status"}, whitelist)
+								require.Equal(t, "published", appVersion.Status)
+								return nil, nil
+							},
 							findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
 								return &models.AppVersion{Record: models.Record{ID: testAppVersionID}, App: models.App{AppSlug: "test-app-slug"}}, nil
 							},
@@ -395,6 +487,11 @@ func Test_WebhookPostHandler(t *testing.T) {
 								return nil, nil
 							},
 						},
+						PublishTaskService: &testPublishTaskService{
+							findFn: func(*models.PublishTask) (*models.PublishTask, error) {
+								return nil, gorm.ErrRecordNotFound
+							},
+						},
 						AppContactService: &testAppContactService{
 							findAllFn: func(app *models.App) ([]models.AppContact, error) {
 								return []models.AppContact{}, nil
@@ -427,6 +524,11 @@ func Test_WebhookPostHandler(t *testing.T) {
 					env: &env.AppEnv{
 						AddonFrontendHostURL: "http://ship.bitrise.io",
 						AppVersionService: &testAppVersionService{
+							updateFn: func(appVersion *models.AppVersion, whitelist []string) ([]error, error) {
+								require.Equal(t, []string{"Status"}, whitelist)
+								require.Equal(t, "published", appVersion.Status)
+								return nil, nil
+							},
 							findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
 								return &models.AppVersion{Record: models.Record{ID: testAppVersionID}, App: models.App{AppSlug: "test-app-slug"}}, nil
 							},
@@ -457,6 +559,17 @@ func Test_WebhookPostHandler(t *testing.T) {
 								return &bitrise.AppDetails{Title: "My awesome app"}, nil
 							},
 						},
+						PublishTaskService: &testPublishTaskService{
+							findFn: func(publishTask *models.PublishTask) (*models.PublishTask, error) {
+								require.Equal(t, "96e72f92-6e4c-40d5-b829-48a1ea6440a1", publishTask.TaskID.String())
+								return &models.PublishTask{Record: models.Record{ID: testPublishTaskID}, TaskID: publishTask.TaskID}, nil
+							},
+							updateFn: func(publishTask *models.PublishTask, whitelist []string) error {
+								require.Equal(t, "success", publishTask.Status)
+								require.Equal(t, []string{"Status"}, whitelist)
+								return nil
+							},
+						},
 						AppContactService: &testAppContactService{
 							findAllFn: func(app *models.App) ([]models.AppContact, error) {
 								return []models.AppContact{
@@ -498,6 +611,11 @@ func Test_WebhookPostHandler(t *testing.T) {
 					env: &env.AppEnv{
 						AddonFrontendHostURL: "http://ship.bitrise.io",
 						AppVersionService: &testAppVersionService{
+							updateFn: func(appVersion *models.AppVersion, whitelist []string) ([]error, error) {
+								require.Equal(t, []string{"Status"}, whitelist)
+								require.Equal(t, "failed", appVersion.Status)
+								return nil, nil
+							},
 							findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
 								return &models.AppVersion{Record: models.Record{ID: testAppVersionID}, App: models.App{AppSlug: "test-app-slug"}}, nil
 							},
@@ -528,6 +646,25 @@ func Test_WebhookPostHandler(t *testing.T) {
 								return &bitrise.AppDetails{Title: "My awesome app"}, nil
 							},
 						},
+						PublishTaskService: &testPublishTaskService{
+							findFn: func(publishTask *models.PublishTask) (*models.PublishTask, error) {
+								require.Equal(t, "96e72f92-6e4c-40d5-b829-48a1ea6440a1", publishTask.TaskID.String())
+								return &models.PublishTask{Record: models.Record{ID: testPublishTaskID}, TaskID: publishTask.TaskID, BatchID: testBatchID}, nil
+							},
+							updateFn: func(publishTask *models.PublishTask, whitelist []string) error {
+								require.Equal(t, "failed", publishTask.Status)
+								require.Equal(t, []string{"Status"}, whitelist)
+								return nil
+							},
+							findAllFn: func(appVersion *models.AppVersion) ([]models.PublishTask, error) {
+								require.Equal(t, testAppVersionID, appVersion.ID)
+								return []models.PublishTask{
+									models.PublishTask{Record: models.Record{ID: testPublishTaskID}, BatchID: testBatchID, Status: "in_progress"},
+									models.PublishTask{Record: models.Record{ID: uuid.NewV4()}, BatchID: testBatchID, Status: "success"},
+									models.PublishTask{Record: models.Record{ID: uuid.NewV4()}, BatchID: uuid.NewV4(), Status: "in_progress"},
+								}, nil
+							},
+						},
 						AppContactService: &testAppContactService{
 							findAllFn: func(app *models.App) ([]models.AppContact, error) {
 								return []models.AppContact{
@@ -561,6 +698,99 @@ func Test_WebhookPostHandler(t *testing.T) {
 				})
 			})
 
+			t.Run("ok - when the task promoted the version", func(t *testing.T) {
+				performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+					contextElements: map[ctxpkg.RequestContextKey]interface{}{
+						services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+					},
+					env: &env.AppEnv{
+						AppVersionService: &testAppVersionService{
+							findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+								return &models.AppVersion{Record: models.Record{ID: testAppVersionID}, App: models.App{AppSlug: "test-app-slug"}, Status: "published"}, nil
+							},
+						},
+						AppVersionEventService: &testAppVersionEventService{
+							createFn: func(event *models.AppVersionEvent) (*models.AppVersionEvent, error) {
+								require.Equal(t, "Failed to promote", event.Text)
+								event.AppVersion = models.AppVersion{Record: models.Record{ID: testAppVersionID}, App: models.App{AppSlug: "test-app-slug"}}
+								return event, nil
+							},
+						},
+						WorkerService: &testWorkerService{
+							enqueueStoreLogToAWSFn: func(uuid.UUID, int64, string, int64) error {
+								return nil
+							},
+						},
+						BitriseAPI: &testBitriseAPI{},
+						PublishTaskService: &testPublishTaskService{
+							findFn: func(publishTask *models.PublishTask) (*models.PublishTask, error) {
+								return &models.PublishTask{Record: models.Record{ID: testPublishTaskID}, TaskID: publishTask.TaskID, Action: "promote"}, nil
+							},
+							updateFn: func(publishTask *models.PublishTask, whitelist []string) error {
+								require.Equal(t, "failed", publishTask.Status)
+								return nil
+							},
+						},
+						AppContactService: &testAppContactService{},
+						Mailer:            &testMailer{},
+						AnalyticsClient:   &testAnalyticsClient{},
+					},
+					requestBody:        `{"type_id":"status","task_id":"96e72f92-6e4c-40d5-b829-48a1ea6440a1","data":{"new_status":"finished","exit_code":1}}`,
+					expectedStatusCode: http.StatusOK,
+					expectedResponse:   httpresponse.StandardErrorRespModel{Message: "ok"},
+				})
+			})
+
+			t.Run("when other tasks of the batch haven't finished yet", func(t *testing.T) {
+				performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
+					contextElements: map[ctxpkg.RequestContextKey]interface{}{
+						services.ContextKeyAuthorizedAppVersionID: testAppVersionID,
+					},
+					env: &env.AppEnv{
+						AppVersionService: &testAppVersionService{
+							findFn: func(appVersion *models.AppVersion) (*models.AppVersion, error) {
+								return &models.AppVersion{Record: models.Record{ID: testAppVersionID}, App: models.App{AppSlug: "test-app-slug"}}, nil
+							},
+						},
+						AppVersionEventService: &testAppVersionEventService{
+							createFn: func(event *models.AppVersionEvent) (*models.AppVersionEvent, error) {
+								event.AppVersion = models.AppVersion{Record: models.Record{ID: testAppVersionID}, App: models.App{AppSlug: "test-app-slug"}}
+								return event, nil
+							},
+						},
+						WorkerService: &testWorkerService{
+							enqueueStoreLogToAWSFn: func(uuid.UUID, int64, string, int64) error {
+								return nil
+							},
+						},
+						BitriseAPI: &testBitriseAPI{},
+						PublishTaskService: &testPublishTaskService{
+							findFn: func(publishTask *models.PublishTask) (*models.PublishTask, error) {
+								return &models.PublishTask{Record: models.Record{ID: testPublishTaskID}, TaskID: publishTask.TaskID, BatchID: testBatchID}, nil
+							},
+							updateFn: func(publishTask *models.PublishTask, whitelist []string) error {
+								return nil
+							},
+							findAllFn: func(appVersion *models.AppVersion) ([]models.PublishTask, error) {
+								return []models.PublishTask{
+									models.PublishTask{Record: models.Record{ID: testPublishTaskID}, BatchID: testBatchID, Status: "in_progress"},
+									models.PublishTask{Record: models.Record{ID: uuid.NewV4()}, BatchID: testBatchID, Status: "in_progress"},
+								}, nil
+							},
+						},
+						AppContactService: &testAppContactService{},
+						AnalyticsClient: &testAnalyticsClient{
+							publishFinishedFn: func(appSlug string, appVersionID uuid.UUID, result string) {
+								require.Equal(t, "success", result)
+							},
+						},
+					},
+					requestBody:        `{"type_id":"status","task_id":"96e72f92-6e4c-40d5-b829-48a1ea6440a1","data":{"new_status":"finished","exit_code":0}}`,
+					expectedStatusCode: http.StatusOK,
+					expectedResponse:   httpresponse.StandardErrorRespModel{Message: "ok"},
+				})
+			})
+
 			t.Run("when error happens at creating new app version event", func(t *testing.T) {
 				performControllerTest(t, httpMethod, url, handler, ControllerTestCase{
 					contextElements: map[ctxpkg.RequestContextKey]interface{}{
@@ -577,8 +807,13 @@ func Test_WebhookPostHandler(t *testing.T) {
 								return nil, errors.New("SOME-SQL-ERROR")
 							},
 						},
-						WorkerService:     &testWorkerService{},
-						BitriseAPI:        &testBitriseAPI{},
+						WorkerService: &testWorkerService{},
+						BitriseAPI:    &testBitriseAPI{},
+						PublishTaskService: &testPublishTaskService{
+							findFn: func(*models.PublishTask) (*models.PublishTask, error) {
+								return nil, gorm.ErrRecordNotFound
+							},
+						},
 						AppContactService: &testAppContactService{},
 						AnalyticsClient:   &testAnalyticsClient{},
 					},
@@ -609,7 +844,12 @@ func Test_WebhookPostHandler(t *testing.T) {
 								return nil
 							},
 						},
-						BitriseAPI:        &testBitriseAPI{},
+						BitriseAPI: &testBitriseAPI{},
+						PublishTaskService: &testPublishTaskService{
+							findFn: func(*models.PublishTask) (*models.PublishTask, error) {
+								return nil, gorm.ErrRecordNotFound
+							},
+						},
 						AppContactService: &testAppContactService{},
 						AnalyticsClient:   &testAnalyticsClient{},
 					},
@@ -640,7 +880,12 @@ func Test_WebhookPostHandler(t *testing.T) {
 								return errors.New("SOME-WORKER-ERROR")
 							},
 						},
-						BitriseAPI:        &testBitriseAPI{},
+						BitriseAPI: &testBitriseAPI{},
+						PublishTaskService: &testPublishTaskService{
+							findFn: func(*models.PublishTask) (*models.PublishTask, error) {
+								return nil, gorm.ErrRecordNotFound
+							},
+						},
 						AppContactService: &testAppContactService{},
 					},
 					requestBody:         `{"type_id":"status","data":{"new_status":"finished"}}`,
@@ -667,9 +912,10 @@ func Test_WebhookPostHandler(t *testing.T) {
 						return nil
 					},
 				},
-				BitriseAPI:        &testBitriseAPI{},
-				AppContactService: &testAppContactService{},
-				AnalyticsClient:   &testAnalyticsClient{},
+				BitriseAPI:         &testBitriseAPI{},
+				PublishTaskService: &testPublishTaskService{},
+				AppContactService:  &testAppContactService{},
+				AnalyticsClient:    &testAnalyticsClient{},
 			},
 			requestBody:        `invalid JSON`,
 			expectedStatusCode: http.StatusBadRequest,
@@ -694,9 +940,10 @@ func Test_WebhookPostHandler(t *testing.T) {
 						return nil
 					},
 				},
-				BitriseAPI:        &testBitriseAPI{},
-				AppContactService: &testAppContactService{},
-				AnalyticsClient:   &testAnalyticsClient{},
+				BitriseAPI:         &testBitriseAPI{},
+				PublishTaskService: &testPublishTaskService{},
+				AppContactService:  &testAppContactService{},
+				AnalyticsClient:    &testAnalyticsClient{},
 			},
 			requestBody:         `{"type_id":"log"}`,
 			expectedInternalErr: "SQL Error: SOME-SQL-ERROR",
@@ -720,9 +967,10 @@ func Test_WebhookPostHandler(t *testing.T) {
 						return nil
 					},
 				},
-				BitriseAPI:        &testBitriseAPI{},
-				AppContactService: &testAppContactService{},
-				AnalyticsClient:   &testAnalyticsClient{},
+				BitriseAPI:         &testBitriseAPI{},
+				PublishTaskService: &testPublishTaskService{},
+				AppContactService:  &testAppContactService{},
+				AnalyticsClient:    &testAnalyticsClient{},
 			},
 			requestBody:         `{"type_id":"invalid hook type"}`,
 			expectedInternalErr: "Invalid type of webhook: invalid hook type",
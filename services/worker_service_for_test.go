@@ -9,6 +9,8 @@ type testWorkerService struct {
 	enqueueStoreLogToAWSFn                  func(uuid.UUID, int64, string, int64) error
 	enqueueStoreLogChunkToRedisFn           func(string, models.LogChunk, int64) error
 	enqueueCopyUploadablesToNewAppVersionFn func(appVersionFromCopyID, appVersionToCopyID string) error
+	enqueueAutoPublishAppVersionFn          func(appVersionID uuid.UUID, workflow, branch, copyUploadablesFromID string) error
+	enqueueDeleteAppVersionFn               func(appVersionID uuid.UUID) error
 }
 
 func (s *testWorkerService) EnqueueStoreLogToAWS(appVersionEventID, publishTaskExternalID uuid.UUID, numberOfLogChunks int64, awsPath string, secondsFromNow int64) error {
@@ -31,3 +33,17 @@ func (s *testWorkerService) EnqueueCopyUploadablesToNewAppVersion(appVersionFrom
 	}
 	return s.enqueueCopyUploadablesToNewAppVersionFn(appVersionFromCopyID, appVersionToCopyID)
 }
+
+func (s *testWorkerService) EnqueueAutoPublishAppVersion(appVersionID uuid.UUID, workflow, branch, copyUploadablesFromID string) error {
+	if s.enqueueAutoPublishAppVersionFn == nil {
+		panic("You have to override EnqueueAutoPublishAppVersion function in tests")
+	}
+	return s.enqueueAutoPublishAppVersionFn(appVersionID, workflow, branch, copyUploadablesFromID)
+}
+
+func (s *testWorkerService) EnqueueDeleteAppVersion(appVersionID uuid.UUID) error {
+	if s.enqueueDeleteAppVersionFn == nil {
+		panic("You have to override EnqueueDeleteAppVersion function in tests")
+	}
+	return s.enqueueDeleteAppVersionFn(appVersionID)
+}
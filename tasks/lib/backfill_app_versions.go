@@ -0,0 +1,78 @@
+package lib
+
+import (
+	"fmt"
+
+	"github.com/bitrise-io/addons-ship-backend/dataservices"
+	"github.com/bitrise-io/addons-ship-backend/env"
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/addons-ship-backend/services"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// BackfillAppVersions imports the last count finished builds of the app as app
+// versions, oldest first, the same way as the build webhook would have. The
+// builds which already have versions are skipped, and no email is sent.
+func BackfillAppVersions(appSlug string, count int) error {
+	err := dataservices.InitializeConnection(dataservices.ConnectionParams{}, true)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer dataservices.Close()
+
+	appEnv, err := env.New(dataservices.GetDB())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	logger := appEnv.Logger
+	defer func() {
+		err := logger.Sync()
+		if err != nil {
+			fmt.Println(err)
+		}
+	}()
+
+	app, err := appEnv.AppService.Find(&models.App{AppSlug: appSlug})
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+	appSettings, err := appEnv.AppSettingsService.Find(&models.AppSettings{AppID: app.ID})
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	builds, err := appEnv.BitriseAPI.GetBuilds(app.BitriseAPIToken, app.AppSlug, count)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	for i := len(builds) - 1; i >= 0; i-- {
+		build := builds[i]
+		existingAppVersions, err := appEnv.AppVersionService.FindAll(app, map[string]interface{}{"build_slug": build.Slug})
+		if err != nil {
+			return errors.Wrap(err, "SQL Error")
+		}
+		if len(existingAppVersions) > 0 {
+			logger.Info("Build is already imported", zap.String("build_slug", build.Slug))
+			continue
+		}
+
+		appVersions, verrs, err := services.ImportBuild(appEnv, appSettings, services.BuildImport{
+			BuildSlug:         build.Slug,
+			BuildNumber:       build.BuildNumber,
+			TriggeredWorkflow: build.TriggeredWorkflow,
+			CheckRules:        true,
+		})
+		if len(verrs) > 0 {
+			logger.Warn("Failed to import build", zap.String("build_slug", build.Slug), zap.Any("errors", verrs))
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "Failed to import build %s", build.Slug)
+		}
+		logger.Info("Build imported", zap.String("build_slug", build.Slug), zap.Int("app_versions", len(appVersions)))
+	}
+
+	return nil
+}
@@ -2,10 +2,26 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"strconv"
 
 	"github.com/bitrise-io/addons-ship-backend/tasks/lib"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "backfill-app-versions" {
+		if len(os.Args) != 4 {
+			fmt.Println("Usage: tasks backfill-app-versions <app-slug> <number-of-builds>")
+			os.Exit(1)
+		}
+		count, err := strconv.Atoi(os.Args[3])
+		if err != nil || count < 1 {
+			fmt.Println("Number of builds has to be a positive integer")
+			os.Exit(1)
+		}
+		fmt.Println(lib.BackfillAppVersions(os.Args[2], count))
+		return
+	}
+
 	fmt.Println(lib.MigrateSelectedProvisioningProfileSlugToArray())
 }
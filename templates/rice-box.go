@@ -40,6 +40,20 @@ func init() {
 		Content: string("package templates\n\nimport (\n\t\"text/template\"\n\n\trice \"github.com/GeertJohan/go.rice\"\n\t\"github.com/bitrise-io/go-utils/templateutil\"\n\t\"github.com/pkg/errors\"\n)\n\n// Get ...\nfunc Get(templateFileName string, data map[string]interface{}) (string, error) {\n\ttemplateBox, err := rice.FindBox(\"\")\n\tif err != nil {\n\t\treturn \"\", errors.WithStack(err)\n\t}\n\n\ttmpContent, err := templateBox.String(templateFileName)\n\tif err != nil {\n\t\treturn \"\", errors.WithStack(err)\n\t}\n\n\tbody, err := templateutil.EvaluateTemplateStringToString(tmpContent, nil, template.FuncMap(data))\n\tif err != nil {\n\t\treturn \"\", err\n\t}\n\treturn body, nil\n}\n"),
 	}
 
+	file8 := &embedded.EmbeddedFile{
+		Filename:    "email/code_signing_expiry.html",
+		FileModTime: time.Unix(1573721421, 0),
+
+		Content: string("<!DOCTYPE html PUBLIC \"-//W3C//DTD XHTML 1.0 Transitional//EN\" \"http://www.w3.org/TR/xhtml1/DTD/xhtml1-transitional.dtd\">\n<html>\n  <head></head>\n  <body\n    style=\"font-family: -apple-system,BlinkMacSystemFont,'Segoe UI',Roboto,Oxygen-Sans,Ubuntu,Cantarell,'Helvetica Neue',sans-serif; background-color: #0dacc9\"\n  >\n    <table style=\"width: 100%; border-spacing: 0; padding: 40px; padding-bottom: 120px;\">\n      <tr>\n        <td style=\"padding: 0;\">\n          <table style=\"width: 100%; border-spacing: 0;\">\n            <tr>\n              <td style=\"width: 50%; padding: 0;\"></td>\n              <td style=\"padding: 0;\">\n                <table\n                  style=\"width: 500px; border-radius: 8px; border-spacing: 0; padding: 40px 48px; background-color: #fff;\"\n                >\n                  <tr>\n                    <td style=\"padding: 0;\">\n                      <table style=\"border-collapse: collapse;\">\n                        <tr style=\"display: none;\">\n                          <td>{{ CurrentTime }}</td>\n                        </tr>\n                        <tr>\n                          <td style=\"padding: 0; text-align: center;\">\n                            <a href=\"https://www.bitrise.io/\" target=\"_blank\"\n                              ><img\n                                alt=\"SHIP\"\n                                height=\"46px\"\n                                src=\"https://bitrise-public-content-production.s3.amazonaws.com/addons-ship/ship-email-logo.png\"\n                                width=\"240px\"\n                            /></a>\n                          </td>\n                        </tr>\n                        <tr style=\"height: 31px;\">\n                          <td style=\"padding: 0;\"></td>\n                        </tr>\n                        <tr style=\"height: 1px;\">\n                          <td style=\"width: 436px; padding: 0; background-color: #ececec;\"></td>\n                        </tr>\n                        <tr style=\"height: 24px;\">\n                          <td style=\"padding: 0;\"></td>\n                        </tr>\n                        <tr>\n                          <td style=\"padding: 0; line-height: 16px; font-size: 15px; font-weight: 700; color: #683d87\">\n                            Hey {{ Name }},\n                          </td>\n                        </tr>\n                        <tr>\n                          <td\n                            style=\"padding: 0; padding-top: 8px; line-height: 24px; font-size: 19px; font-weight: 500; color: #492f5c;\"\n                          >\n                            Code signing files of {{ AppTitle }} are about to expire, please renew them and update the\n                            settings on Ship:\n                          </td>\n                        </tr>\n                        <tr>\n                          <td style=\"padding: 0; padding-top: 24px;\">\n                            <table style=\"width: 100%; border-spacing: 0;\">\n                              <tr>\n                                <td style=\"border: 1px solid #ececec; border-radius: 8px; padding: 10px;\">\n                                  <table style=\"width: 100%; border-spacing: 0;\">\n                                    {{ range ExpiringFiles }}\n                                    <tr>\n                                      <td\n                                        style=\"width: 100%; padding: 6px 0; line-height: 16px; font-size: 16px; font-weight: 700; color: #777;\"\n                                      >\n                                        {{ if .Filename }}{{ .Filename }}{{ else }}{{ .Slug }}{{ end }}\n                                      </td>\n                                      <td\n                                        style=\"padding: 6px 0; padding-left: 16px; line-height: 16px; font-size: 13px; font-weight: 500; color: #ff2158; white-space: nowrap;\"\n                                      >\n                                        {{ .ExpiresAt.Format \"Jan 2, 2006\" }}\n                                      </td>\n                                    </tr>\n                                    {{ end }}\n                                  </table>\n                                </td>\n                              </tr>\n                            </table>\n                          </td>\n                        </tr>\n                        <tr>\n                          <td style=\"padding: 0; padding-top: 32px;\">\n                            <table style=\"width: 100%; border-spacing: 0;\">\n                              <tr>\n                                <td style=\"width: 50%; padding: 0;\"></td>\n                                <td style=\"width: 200px; padding: 0;\">\n                                  <a href=\"{{ AppURL }}\" style=\"text-decoration: none;\"\n                                    ><table style=\"width: 200px; border-spacing: 0;\">\n                                      <tr>\n                                        <td\n                                          style=\"border-radius: 4px; padding: 14px 16px; line-height: 20px; text-align: center; font-size: 16px; font-weight: 700; color: #f8f8f8; background: #760fc3; background-image: linear-gradient(#6c0eb2, #450674 97%);\"\n                                        >\n                                          View settings\n                                        </td>\n                                      </tr>\n                                    </table></a\n                                  >\n                                </td>\n                                <td style=\"width: 50%; padding: 0;\"></td>\n                              </tr>\n                            </table>\n                          </td>\n                        </tr>\n                        <tr style=\"display: none;\">\n                          <td>{{ CurrentTime }}</td>\n                        </tr>\n                      </table>\n                    </td>\n                  </tr>\n                </table>\n              </td>\n              <td style=\"width: 50%; padding: 0;\"></td>\n            </tr>\n          </table>\n        </td>\n      </tr>\n      <tr>\n        <td style=\"padding: 0; padding-top: 40px;\">\n          <table style=\"width: 100%; border-spacing: 0;\">\n            <tr>\n              <td style=\"padding: 0; line-height: 16px; text-align: center; font-size: 13px; color: #fff;\">\n                <table style=\"width: 100%; border-spacing: 0;\">\n                  <tr height=\"24px\">\n                    <td>\n                      <img\n                        alt=\"BITRISE\"\n                        height=\"24px\"\n                        src=\"https://s3.amazonaws.com/bitrise-public-content-production/emails/bitrise-icon.png\"\n                        width=\"30px\"\n                      />\n                    </td>\n                  </tr>\n                  <tr height=\"12px\">\n                    <td></td>\n                  </tr>\n                  <tr>\n                    <td>Bitrise Limited</td>\n                  </tr>\n                  <tr height=\"12px\">\n                    <td></td>\n                  </tr>\n                  <tr>\n                    <td>\n                      Need Help? <a href=\"mailto:letsconnect@bitrise.io\" style=\"color: #fff\">letsconnect@bitrise.io</a>\n                    </td>\n                  </tr>\n                </table>\n              </td>\n            </tr>\n          </table>\n        </td>\n      </tr>\n    </table>\n  </body>\n</html>\n"),
+	}
+
+	file9 := &embedded.EmbeddedFile{
+		Filename:    "email/approval_request.html",
+		FileModTime: time.Unix(1574935846, 0),
+
+		Content: string("<!DOCTYPE html PUBLIC \"-//W3C//DTD XHTML 1.0 Transitional//EN\" \"http://www.w3.org/TR/xhtml1/DTD/xhtml1-transitional.dtd\">\n<html>\n  <head></head>\n  <body\n    style=\"font-family: -apple-system,BlinkMacSystemFont,'Segoe UI',Roboto,Oxygen-Sans,Ubuntu,Cantarell,'Helvetica Neue',sans-serif; background-color: #0dacc9\"\n  >\n    <table style=\"width: 100%; border-spacing: 0; padding: 40px; padding-bottom: 120px;\">\n      <tr>\n        <td style=\"padding: 0;\">\n          <table style=\"width: 100%; border-spacing: 0;\">\n            <tr>\n              <td style=\"width: 50%; padding: 0;\"></td>\n              <td style=\"padding: 0;\">\n                <table\n                  style=\"width: 500px; border-radius: 8px; border-spacing: 0; padding: 40px 48px; background-color: #fff;\"\n                >\n                  <tr>\n                    <td style=\"padding: 0;\">\n                      <table style=\"border-collapse: collapse;\">\n                        <tr style=\"display: none;\">\n                          <td>{{ CurrentTime }}</td>\n                        </tr>\n                        <tr>\n                          <td style=\"padding: 0; text-align: center;\">\n                            <a href=\"https://www.bitrise.io/\" target=\"_blank\"\n                              ><img\n                                alt=\"SHIP\"\n                                height=\"46px\"\n                                src=\"https://bitrise-public-content-production.s3.amazonaws.com/addons-ship/ship-email-logo.png\"\n                                width=\"240px\"\n                            /></a>\n                          </td>\n                        </tr>\n                        <tr style=\"height: 31px;\">\n                          <td style=\"padding: 0;\"></td>\n                        </tr>\n                        <tr style=\"height: 1px;\">\n                          <td style=\"width: 436px; padding: 0; background-color: #ececec;\"></td>\n                        </tr>\n                        <tr style=\"height: 24px;\">\n                          <td style=\"padding: 0;\"></td>\n                        </tr>\n                        <tr>\n                          <td style=\"padding: 0; line-height: 16px; font-size: 15px; font-weight: 700; color: #683d87\">\n                            Hey {{ Name }},\n                          </td>\n                        </tr>\n                        <tr>\n                          <td\n                            style=\"padding: 0; padding-top: 8px; line-height: 24px; font-size: 19px; font-weight: 500; color: #492f5c;\"\n                          >\n                            Publishing version {{ Version }} ({{ BuildNumber }}) of {{ AppTitle }} needs your approval,\n                            please review its store info on Ship:\n                          </td>\n                        </tr>\n                        <tr>\n                          <td style=\"padding: 0; padding-top: 32px;\">\n                            <table style=\"width: 100%; border-spacing: 0;\">\n                              <tr>\n                                <td style=\"width: 50%; padding: 0;\"></td>\n                                <td style=\"width: 200px; padding: 0;\">\n                                  <a href=\"{{ AppURL }}\" style=\"text-decoration: none;\"\n                                    ><table style=\"width: 200px; border-spacing: 0;\">\n                                      <tr>\n                                        <td\n                                          style=\"border-radius: 4px; padding: 14px 16px; line-height: 20px; text-align: center; font-size: 16px; font-weight: 700; color: #f8f8f8; background: #760fc3; background-image: linear-gradient(#6c0eb2, #450674 97%);\"\n                                        >\n                                          Review version\n                                        </td>\n                                      </tr>\n                                    </table></a\n                                  >\n                                </td>\n                                <td style=\"width: 50%; padding: 0;\"></td>\n                              </tr>\n                            </table>\n                          </td>\n                        </tr>\n                        <tr style=\"display: none;\">\n                          <td>{{ CurrentTime }}</td>\n                        </tr>\n                      </table>\n                    </td>\n                  </tr>\n                </table>\n              </td>\n              <td style=\"width: 50%; padding: 0;\"></td>\n            </tr>\n          </table>\n        </td>\n      </tr>\n      <tr>\n        <td style=\"padding: 0; padding-top: 40px;\">\n          <table style=\"width: 100%; border-spacing: 0;\">\n            <tr>\n              <td style=\"padding: 0; line-height: 16px; text-align: center; font-size: 13px; color: #fff;\">\n                <table style=\"width: 100%; border-spacing: 0;\">\n                  <tr height=\"24px\">\n                    <td>\n                      <img\n                        alt=\"BITRISE\"\n                        height=\"24px\"\n                        src=\"https://s3.amazonaws.com/bitrise-public-content-production/emails/bitrise-icon.png\"\n                        width=\"30px\"\n                      />\n                    </td>\n                  </tr>\n                  <tr height=\"12px\">\n                    <td></td>\n                  </tr>\n                  <tr>\n                    <td>Bitrise Limited</td>\n                  </tr>\n                  <tr height=\"12px\">\n                    <td></td>\n                  </tr>\n                  <tr>\n                    <td>\n                      Need Help? <a href=\"mailto:letsconnect@bitrise.io\" style=\"color: #fff\">letsconnect@bitrise.io</a>\n                    </td>\n                  </tr>\n                </table>\n              </td>\n            </tr>\n          </table>\n        </td>\n      </tr>\n    </table>\n  </body>\n</html>\n"),
+	}
+
 	// define dirs
 	dir1 := &embedded.EmbeddedDir{
 		Filename:   "",
@@ -54,6 +68,8 @@ func init() {
 		Filename:   "email",
 		DirModTime: time.Unix(1571914896, 0),
 		ChildFiles: []*embedded.EmbeddedFile{
+			file9, // "email/approval_request.html"
+			file8, // "email/code_signing_expiry.html"
 			file3, // "email/confirmation.html"
 			file4, // "email/new_version.html"
 			file5, // "email/publish.html"
@@ -77,11 +93,13 @@ func init() {
 			"email": dir2,
 		},
 		Files: map[string]*embedded.EmbeddedFile{
-			"email/confirmation.html": file3,
-			"email/new_version.html":  file4,
-			"email/publish.html":      file5,
-			"rice-box.go":             file6,
-			"templates.go":            file7,
+			"email/approval_request.html":    file9,
+			"email/code_signing_expiry.html": file8,
+			"email/confirmation.html":        file3,
+			"email/new_version.html":         file4,
+			"email/publish.html":             file5,
+			"rice-box.go":                    file6,
+			"templates.go":                   file7,
 		},
 	})
 }
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/bitrise-io/addons-ship-backend/bitrise"
+	"github.com/bitrise-io/api-utils/httprequest"
+	"github.com/bitrise-io/api-utils/httpresponse"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// The task webhook payloads mirror the ones Ship accepts on /task-webhook.
+type statusData struct {
+	NewStatus     string     `json:"new_status"`
+	ExitCode      int        `json:"exit_code"`
+	LogChunkCount int64      `json:"generated_log_chunk_count"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty"`
+}
+
+type logChunkData struct {
+	Position int    `json:"position"`
+	Chunk    string `json:"chunk"`
+}
+
+type webhookPayload struct {
+	TypeID    string      `json:"type_id"`
+	Timestamp int64       `json:"timestamp"`
+	TaskID    uuid.UUID   `json:"task_id"`
+	Data      interface{} `json:"data"`
+}
+
+// triggerDENTaskHandler accepts a task the same way DEN does, then plays the
+// scripted task lifecycle back to the task's webhook URL in the background.
+func (s *server) triggerDENTaskHandler(w http.ResponseWriter, r *http.Request) {
+	if s.denAdminHeaderKey != "" && r.Header.Get(s.denAdminHeaderKey) != s.denAdminSecret {
+		httpresponse.RespondWithUnauthorizedNoErr(w)
+		return
+	}
+
+	var params bitrise.TaskParams
+	defer httprequest.BodyCloseWithErrorLog(r)
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		httpresponse.RespondWithBadRequestErrorNoErr(w, "Invalid request body, JSON decode failed")
+		return
+	}
+	if params.WebhookURL == "" {
+		httpresponse.RespondWithBadRequestErrorNoErr(w, "No webhook URL defined for task")
+		return
+	}
+
+	now := time.Now()
+	taskID := uuid.NewV4()
+	log.Printf("DEN task %s triggered, workflow: %s, stack: %s", taskID, params.Workflow, params.StackID)
+
+	go s.runDENTask(taskID, params, s.fixtures.denScript(params.Workflow))
+
+	httpresponse.RespondWithSuccessNoErr(w, bitrise.TriggerResponse{
+		ConfigType:     "yaml",
+		CreatedAt:      now,
+		TaskIdentifier: taskID,
+		UpdatedAt:      now,
+		WebhookURL:     params.WebhookURL,
+	})
+}
+
+func (s *server) runDENTask(taskID uuid.UUID, params bitrise.TaskParams, script denScript) {
+	delay := time.Duration(script.DelaySeconds) * time.Second
+
+	time.Sleep(delay)
+	if err := s.sendTaskWebhook(params.WebhookURL, taskID, "status", statusData{NewStatus: "started"}); err != nil {
+		log.Printf("DEN task %s: %s", taskID, err)
+		return
+	}
+
+	for i, chunk := range script.LogChunks {
+		if err := s.sendTaskWebhook(params.WebhookURL, taskID, "log", logChunkData{Position: i, Chunk: chunk}); err != nil {
+			log.Printf("DEN task %s: %s", taskID, err)
+			return
+		}
+	}
+
+	time.Sleep(delay)
+	finishedAt := time.Now()
+	err := s.sendTaskWebhook(params.WebhookURL, taskID, "status", statusData{
+		NewStatus:     "finished",
+		ExitCode:      script.ExitCode,
+		LogChunkCount: int64(len(script.LogChunks)),
+		FinishedAt:    &finishedAt,
+	})
+	if err != nil {
+		log.Printf("DEN task %s: %s", taskID, err)
+		return
+	}
+	log.Printf("DEN task %s finished with exit code %d", taskID, script.ExitCode)
+}
+
+func (s *server) sendTaskWebhook(webhookURL string, taskID uuid.UUID, typeID string, data interface{}) error {
+	payloadBytes, err := json.Marshal(webhookPayload{
+		TypeID:    typeID,
+		Timestamp: time.Now().Unix(),
+		TaskID:    taskID,
+		Data:      data,
+	})
+	if err != nil {
+		return errors.Wrap(err, "Failed to JSON serialize")
+	}
+	req, err := http.NewRequest("POST", webhookURL, bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Bitrise-Den-Webhook-Secret", s.denWebhookSecret)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to send %s webhook", typeID)
+	}
+	defer httpresponse.BodyCloseWithErrorLog(resp)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf("Failed to send %s webhook: status: %d", typeID, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/bitrise-io/addons-ship-backend/bitrise"
+	"github.com/bitrise-io/api-utils/structs"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+type artifact struct {
+	Slug                 string      `yaml:"slug"`
+	Title                string      `yaml:"title"`
+	ArtifactType         string      `yaml:"artifact_type"`
+	FileSizeBytes        int64       `yaml:"file_size_bytes"`
+	DownloadURL          string      `yaml:"download_url"`
+	IsPublicPageEnabled  bool        `yaml:"is_public_page_enabled"`
+	PublicInstallPageURL string      `yaml:"public_install_page_url"`
+	ArtifactMeta         interface{} `yaml:"artifact_meta"`
+}
+
+type build struct {
+	Slug              string     `yaml:"slug"`
+	BuildNumber       int        `yaml:"build_number"`
+	TriggeredWorkflow string     `yaml:"triggered_workflow"`
+	Branch            string     `yaml:"branch"`
+	Tag               string     `yaml:"tag"`
+	CommitMessage     string     `yaml:"commit_message"`
+	Artifacts         []artifact `yaml:"artifacts"`
+}
+
+type provisioningProfile struct {
+	Slug        string `yaml:"slug"`
+	Filename    string `yaml:"filename"`
+	DownloadURL string `yaml:"download_url"`
+}
+
+type buildCertificate struct {
+	Slug                string `yaml:"slug"`
+	Filename            string `yaml:"filename"`
+	DownloadURL         string `yaml:"download_url"`
+	CertificatePassword string `yaml:"certificate_password"`
+}
+
+type keystore struct {
+	Slug               string `yaml:"slug"`
+	Filename           string `yaml:"filename"`
+	DownloadURL        string `yaml:"download_url"`
+	Password           string `yaml:"password"`
+	Alias              string `yaml:"alias"`
+	PrivateKeyPassword string `yaml:"private_key_password"`
+}
+
+type genericProjectFile struct {
+	Slug        string `yaml:"slug"`
+	Filename    string `yaml:"filename"`
+	DownloadURL string `yaml:"download_url"`
+}
+
+type app struct {
+	Slug                 string                `yaml:"slug"`
+	BitriseAPIToken      string                `yaml:"bitrise_api_token"`
+	Title                string                `yaml:"title"`
+	ProjectType          string                `yaml:"project_type"`
+	AvatarURL            *string               `yaml:"avatar_url"`
+	Builds               []build               `yaml:"builds"`
+	ProvisioningProfiles []provisioningProfile `yaml:"provisioning_profiles"`
+	BuildCertificates    []buildCertificate    `yaml:"build_certificates"`
+	AndroidKeystoreFiles []keystore            `yaml:"android_keystore_files"`
+	GenericProjectFiles  []genericProjectFile  `yaml:"generic_project_files"`
+}
+
+type denScript struct {
+	DelaySeconds int      `yaml:"delay_seconds"`
+	ExitCode     int      `yaml:"exit_code"`
+	LogChunks    []string `yaml:"log_chunks"`
+}
+
+type fixtures struct {
+	Apps []app `yaml:"apps"`
+	DEN  struct {
+		denScript `yaml:",inline"`
+		Workflows map[string]denScript `yaml:"workflows"`
+	} `yaml:"den"`
+}
+
+func loadFixtures(path string) (*fixtures, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read fixture file %s", path)
+	}
+	var f fixtures
+	if err := yaml.Unmarshal(content, &f); err != nil {
+		return nil, errors.Wrapf(err, "Failed to parse fixture file %s", path)
+	}
+	return &f, nil
+}
+
+func (f *fixtures) app(appSlug string) *app {
+	for i := range f.Apps {
+		if f.Apps[i].Slug == appSlug {
+			return &f.Apps[i]
+		}
+	}
+	return nil
+}
+
+func (f *fixtures) denScript(workflow string) denScript {
+	if script, ok := f.DEN.Workflows[workflow]; ok {
+		return script
+	}
+	return f.DEN.denScript
+}
+
+func (a *app) build(buildSlug string) *build {
+	for i := range a.Builds {
+		if a.Builds[i].Slug == buildSlug {
+			return &a.Builds[i]
+		}
+	}
+	return nil
+}
+
+// details returns the build as a finished one
+func (b *build) details() bitrise.BuildDetails {
+	return bitrise.BuildDetails{
+		Slug:              b.Slug,
+		BuildNumber:       b.BuildNumber,
+		Status:            bitrise.BuildStatusSuccess,
+		TriggeredWorkflow: b.TriggeredWorkflow,
+		Branch:            b.Branch,
+		Tag:               b.Tag,
+		CommitMessage:     b.CommitMessage,
+	}
+}
+
+func (a *app) details() bitrise.AppDetails {
+	return bitrise.AppDetails{
+		Title:       a.Title,
+		AvatarURL:   a.AvatarURL,
+		ProjectType: a.ProjectType,
+	}
+}
+
+func (a *app) provisioningProfiles() []bitrise.ProvisioningProfile {
+	profiles := []bitrise.ProvisioningProfile{}
+	for _, p := range a.ProvisioningProfiles {
+		profiles = append(profiles, bitrise.ProvisioningProfile{Slug: p.Slug, Filename: p.Filename, DownloadURL: p.DownloadURL})
+	}
+	return profiles
+}
+
+func (a *app) codeSigningIdentities() []bitrise.CodeSigningIdentity {
+	identities := []bitrise.CodeSigningIdentity{}
+	for _, c := range a.BuildCertificates {
+		identities = append(identities, bitrise.CodeSigningIdentity{
+			Slug:                c.Slug,
+			Filename:            c.Filename,
+			DownloadURL:         c.DownloadURL,
+			CertificatePassword: c.CertificatePassword,
+		})
+	}
+	return identities
+}
+
+func (a *app) androidKeystoreFiles() []bitrise.AndroidKeystoreFile {
+	keystores := []bitrise.AndroidKeystoreFile{}
+	for _, k := range a.AndroidKeystoreFiles {
+		keystores = append(keystores, bitrise.AndroidKeystoreFile{
+			Slug:        k.Slug,
+			Filename:    k.Filename,
+			DownloadURL: k.DownloadURL,
+			ExposedMetadataStore: bitrise.ExposedMetadataStore{
+				Password:           k.Password,
+				Alias:              k.Alias,
+				PrivateKeyPassword: k.PrivateKeyPassword,
+			},
+		})
+	}
+	return keystores
+}
+
+func (a *app) genericProjectFiles() []bitrise.GenericProjectFile {
+	files := []bitrise.GenericProjectFile{}
+	for _, g := range a.GenericProjectFiles {
+		files = append(files, bitrise.GenericProjectFile{Slug: g.Slug, Filename: g.Filename, DownloadURL: g.DownloadURL})
+	}
+	return files
+}
+
+func (a artifact) listElement() (bitrise.ArtifactListElementResponseModel, error) {
+	element := bitrise.ArtifactListElementResponseModel{
+		Title:               a.Title,
+		Slug:                a.Slug,
+		IsPublicPageEnabled: a.IsPublicPageEnabled,
+	}
+	if a.ArtifactType != "" {
+		artifactType := a.ArtifactType
+		element.ArtifactType = &artifactType
+	}
+	if a.FileSizeBytes != 0 {
+		fileSize := a.FileSizeBytes
+		element.FileSizeBytes = &fileSize
+	}
+	metaBytes, err := a.metaJSON()
+	if err != nil {
+		return bitrise.ArtifactListElementResponseModel{}, err
+	}
+	if metaBytes != nil {
+		var meta bitrise.ArtifactMeta
+		if err := json.Unmarshal(metaBytes, &meta); err != nil {
+			return bitrise.ArtifactListElementResponseModel{}, errors.Wrapf(err, "Invalid artifact meta for artifact %s", a.Slug)
+		}
+		element.ArtifactMeta = &meta
+	}
+	return element, nil
+}
+
+func (a artifact) showItem() (bitrise.ArtifactShowResponseItemModel, error) {
+	title := a.Title
+	item := bitrise.ArtifactShowResponseItemModel{
+		Title:                &title,
+		Slug:                 a.Slug,
+		IsPublicPageEnabled:  a.IsPublicPageEnabled,
+		PublicInstallPageURL: a.PublicInstallPageURL,
+	}
+	if a.ArtifactType != "" {
+		artifactType := a.ArtifactType
+		item.ArtifactType = &artifactType
+	}
+	if a.DownloadURL != "" {
+		downloadURL := a.DownloadURL
+		item.DownloadPath = &downloadURL
+	}
+	if a.FileSizeBytes != 0 {
+		fileSize := a.FileSizeBytes
+		item.FileSizeBytes = &fileSize
+	}
+	metaBytes, err := a.metaJSON()
+	if err != nil {
+		return bitrise.ArtifactShowResponseItemModel{}, err
+	}
+	item.ArtifactMeta = metaBytes
+	return item, nil
+}
+
+// metaJSON converts the YAML artifact meta (written with the Bitrise API's
+// JSON keys) to its JSON form, so it decodes exactly as a real API response.
+func (a artifact) metaJSON() ([]byte, error) {
+	if a.ArtifactMeta == nil {
+		return nil, nil
+	}
+	metaBytes, err := json.Marshal(structs.ConvertMapIToMapS(a.ArtifactMeta))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to convert artifact meta of artifact %s", a.Slug)
+	}
+	return metaBytes, nil
+}
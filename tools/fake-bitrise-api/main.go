@@ -0,0 +1,261 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/bitrise-io/addons-ship-backend/bitrise"
+	"github.com/bitrise-io/api-utils/httpresponse"
+	"github.com/gorilla/mux"
+)
+
+type server struct {
+	fixtures           *fixtures
+	denAdminHeaderKey  string
+	denAdminSecret     string
+	denWebhookSecret   string
+	artifactsPageLimit int
+}
+
+func main() {
+	port := flag.String("port", envOrDefault("PORT", "8081"), "Port to listen on")
+	fixturesPath := flag.String("fixtures", envOrDefault("FAKE_BITRISE_API_FIXTURES", "tools/fake-bitrise-api/fixtures.yml"), "Path of the YAML fixture file")
+	flag.Parse()
+
+	f, err := loadFixtures(*fixturesPath)
+	if err != nil {
+		log.Fatalf("Failed to load fixtures: %s", err)
+	}
+
+	pageLimit, err := strconv.Atoi(envOrDefault("FAKE_BITRISE_API_ARTIFACTS_PAGE_LIMIT", "50"))
+	if err != nil || pageLimit < 1 {
+		log.Fatalf("Invalid artifacts page limit: %s", os.Getenv("FAKE_BITRISE_API_ARTIFACTS_PAGE_LIMIT"))
+	}
+
+	s := &server{
+		fixtures:           f,
+		denAdminHeaderKey:  os.Getenv("BITRISE_DEN_SERVER_ADMIN_SECRET_HEADER_KEY"),
+		denAdminSecret:     os.Getenv("BITRISE_DEN_SERVER_ADMIN_SECRET"),
+		denWebhookSecret:   os.Getenv("BITRISE_DEN_WEBHOOK_SECRET"),
+		artifactsPageLimit: pageLimit,
+	}
+
+	log.Printf("Fake Bitrise API serving %d app(s) from %s on port %s", len(f.Apps), *fixturesPath, *port)
+	log.Fatal(http.ListenAndServe(fmt.Sprintf(":%s", *port), s.router()))
+}
+
+func envOrDefault(key, defaultValue string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func (s *server) router() *mux.Router {
+	r := mux.NewRouter().StrictSlash(true)
+	api := r.PathPrefix("/v0.1").Subrouter()
+
+	api.HandleFunc("/bitrise-den/tasks", s.triggerDENTaskHandler).Methods("POST")
+
+	for _, route := range []struct {
+		path    string
+		handler func(*app, http.ResponseWriter, *http.Request)
+		method  string
+	}{
+		{path: "/apps/{app-slug}", handler: s.appHandler, method: "GET"},
+		{path: "/apps/{app-slug}/builds", handler: s.buildsHandler, method: "GET"},
+		{path: "/apps/{app-slug}/builds/{build-slug}", handler: s.buildHandler, method: "GET"},
+		{path: "/apps/{app-slug}/builds/{build-slug}/artifacts", handler: s.artifactsHandler, method: "GET"},
+		{path: "/apps/{app-slug}/builds/{build-slug}/artifacts/{artifact-slug}", handler: s.artifactHandler, method: "GET"},
+		{path: "/apps/{app-slug}/provisioning-profiles", handler: s.provisioningProfilesHandler, method: "GET"},
+		{path: "/apps/{app-slug}/provisioning-profiles/{slug}", handler: s.provisioningProfileHandler, method: "GET"},
+		{path: "/apps/{app-slug}/build-certificates", handler: s.codeSigningIdentitiesHandler, method: "GET"},
+		{path: "/apps/{app-slug}/build-certificates/{slug}", handler: s.codeSigningIdentityHandler, method: "GET"},
+		{path: "/apps/{app-slug}/android-keystore-files", handler: s.androidKeystoreFilesHandler, method: "GET"},
+		{path: "/apps/{app-slug}/generic-project-files", handler: s.genericProjectFilesHandler, method: "GET"},
+		{path: "/apps/{app-slug}/generic-project-files/{slug}", handler: s.genericProjectFileHandler, method: "GET"},
+		{path: "/apps/{app-slug}/outgoing-webhooks", handler: s.outgoingWebhookHandler, method: "POST"},
+	} {
+		api.HandleFunc(route.path, s.withApp(route.handler)).Methods(route.method)
+	}
+
+	r.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log.Printf("No fake route for %s %s", r.Method, r.URL.Path)
+		httpresponse.RespondWithNotFoundErrorNoErr(w)
+	})
+	return r
+}
+
+// withApp looks up the app of the request and checks the addon auth token
+// against the one in the fixtures, when the fixture defines one.
+func (s *server) withApp(h func(*app, http.ResponseWriter, *http.Request)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log.Printf("%s %s", r.Method, r.URL.String())
+		a := s.fixtures.app(mux.Vars(r)["app-slug"])
+		if a == nil {
+			httpresponse.RespondWithNotFoundErrorNoErr(w)
+			return
+		}
+		if a.BitriseAPIToken != "" && r.Header.Get("Bitrise-Addon-Auth-Token") != a.BitriseAPIToken {
+			httpresponse.RespondWithUnauthorizedNoErr(w)
+			return
+		}
+		h(a, w, r)
+	}
+}
+
+func (s *server) appHandler(a *app, w http.ResponseWriter, r *http.Request) {
+	respondWithData(w, a.details())
+}
+
+func (s *server) buildHandler(a *app, w http.ResponseWriter, r *http.Request) {
+	b := a.build(mux.Vars(r)["build-slug"])
+	if b == nil {
+		httpresponse.RespondWithNotFoundErrorNoErr(w)
+		return
+	}
+	respondWithData(w, b.details())
+}
+
+// buildsHandler lists the builds of the fixture latest first, without paging
+func (s *server) buildsHandler(a *app, w http.ResponseWriter, r *http.Request) {
+	builds := []bitrise.BuildDetails{}
+	for i := len(a.Builds) - 1; i >= 0; i-- {
+		builds = append(builds, a.Builds[i].details())
+	}
+	respondWithData(w, builds)
+}
+
+// artifactsHandler pages the artifact list the same way the Bitrise API does,
+// so the client's "next" handling gets exercised with small page limits.
+func (s *server) artifactsHandler(a *app, w http.ResponseWriter, r *http.Request) {
+	b := a.build(mux.Vars(r)["build-slug"])
+	if b == nil {
+		httpresponse.RespondWithNotFoundErrorNoErr(w)
+		return
+	}
+
+	start := 0
+	if next := r.URL.Query().Get("next"); next != "" {
+		for i, artifact := range b.Artifacts {
+			if artifact.Slug == next {
+				start = i
+				break
+			}
+		}
+	}
+	end := start + s.artifactsPageLimit
+	nextSlug := ""
+	if end < len(b.Artifacts) {
+		nextSlug = b.Artifacts[end].Slug
+	} else {
+		end = len(b.Artifacts)
+	}
+
+	artifacts := []bitrise.ArtifactListElementResponseModel{}
+	for _, artifact := range b.Artifacts[start:end] {
+		element, err := artifact.listElement()
+		if err != nil {
+			httpresponse.RespondWithInternalServerError(w, err)
+			return
+		}
+		artifacts = append(artifacts, element)
+	}
+
+	httpresponse.RespondWithSuccessNoErr(w, map[string]interface{}{
+		"data": artifacts,
+		"paging": map[string]interface{}{
+			"total_item_count": len(b.Artifacts),
+			"page_item_limit":  s.artifactsPageLimit,
+			"next":             nextSlug,
+		},
+	})
+}
+
+func (s *server) artifactHandler(a *app, w http.ResponseWriter, r *http.Request) {
+	b := a.build(mux.Vars(r)["build-slug"])
+	if b == nil {
+		httpresponse.RespondWithNotFoundErrorNoErr(w)
+		return
+	}
+	for _, artifact := range b.Artifacts {
+		if artifact.Slug != mux.Vars(r)["artifact-slug"] {
+			continue
+		}
+		item, err := artifact.showItem()
+		if err != nil {
+			httpresponse.RespondWithInternalServerError(w, err)
+			return
+		}
+		respondWithData(w, item)
+		return
+	}
+	httpresponse.RespondWithNotFoundErrorNoErr(w)
+}
+
+func (s *server) provisioningProfilesHandler(a *app, w http.ResponseWriter, r *http.Request) {
+	respondWithData(w, a.provisioningProfiles())
+}
+
+func (s *server) provisioningProfileHandler(a *app, w http.ResponseWriter, r *http.Request) {
+	for _, profile := range a.provisioningProfiles() {
+		if profile.Slug == mux.Vars(r)["slug"] {
+			respondWithData(w, profile)
+			return
+		}
+	}
+	httpresponse.RespondWithNotFoundErrorNoErr(w)
+}
+
+func (s *server) codeSigningIdentitiesHandler(a *app, w http.ResponseWriter, r *http.Request) {
+	respondWithData(w, a.codeSigningIdentities())
+}
+
+func (s *server) codeSigningIdentityHandler(a *app, w http.ResponseWriter, r *http.Request) {
+	for _, identity := range a.codeSigningIdentities() {
+		if identity.Slug == mux.Vars(r)["slug"] {
+			respondWithData(w, identity)
+			return
+		}
+	}
+	httpresponse.RespondWithNotFoundErrorNoErr(w)
+}
+
+func (s *server) androidKeystoreFilesHandler(a *app, w http.ResponseWriter, r *http.Request) {
+	respondWithData(w, a.androidKeystoreFiles())
+}
+
+func (s *server) genericProjectFilesHandler(a *app, w http.ResponseWriter, r *http.Request) {
+	respondWithData(w, a.genericProjectFiles())
+}
+
+// genericProjectFileHandler also serves keystore files, because the client
+// fetches a single keystore through the generic project files endpoint.
+func (s *server) genericProjectFileHandler(a *app, w http.ResponseWriter, r *http.Request) {
+	slug := mux.Vars(r)["slug"]
+	for _, file := range a.genericProjectFiles() {
+		if file.Slug == slug {
+			respondWithData(w, file)
+			return
+		}
+	}
+	for _, keystore := range a.androidKeystoreFiles() {
+		if keystore.Slug == slug {
+			respondWithData(w, keystore)
+			return
+		}
+	}
+	httpresponse.RespondWithNotFoundErrorNoErr(w)
+}
+
+func (s *server) outgoingWebhookHandler(a *app, w http.ResponseWriter, r *http.Request) {
+	httpresponse.RespondWithJSONNoErr(w, http.StatusCreated, map[string]string{"message": "ok"})
+}
+
+func respondWithData(w http.ResponseWriter, data interface{}) {
+	httpresponse.RespondWithSuccessNoErr(w, map[string]interface{}{"data": data})
+}
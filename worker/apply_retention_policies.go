@@ -0,0 +1,114 @@
+package worker
+
+import (
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/addons-ship-backend/services"
+	"github.com/gocraft/work"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"go.uber.org/zap"
+)
+
+var applyRetentionPolicies = "apply_retention_policies"
+
+// ApplyRetentionPolicies archives the versions of the apps which expired by
+// their retention policy. With the purge action the versions also get deleted,
+// the same way as they get deleted by hand, and the versions which can't be
+// deleted by hand are kept.
+func (c *Context) ApplyRetentionPolicies(job *work.Job) error {
+	c.env.Logger.Info("[i] Job ApplyRetentionPolicies started")
+	now := c.env.TimeService.Now()
+
+	appSettingsList, err := c.env.AppSettingsService.FindAllWithRetentionPolicy()
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	for _, appSettings := range appSettingsList {
+		app := appSettings.App
+		if app == nil {
+			continue
+		}
+		retentionPolicy, err := appSettings.RetentionPolicy()
+		if err != nil {
+			c.env.Logger.Error("[!] ApplyRetentionPolicies: Failed to parse retention policy", zap.String("app_slug", app.AppSlug), zap.Any("error", err))
+			continue
+		}
+
+		// archived versions only have to be purged, so they are left out when
+		// the policy archives
+		action := models.RetentionActionArchive
+		filterParams := map[string]interface{}{"archived_at": nil}
+		if retentionPolicy.Action == models.RetentionActionPurge {
+			action = models.RetentionActionPurge
+			delete(filterParams, "archived_at")
+		}
+		appVersions, err := c.env.AppVersionService.FindAll(app, filterParams)
+		if err != nil {
+			return errors.Wrap(err, "SQL Error")
+		}
+
+		publishedIDs, err := c.publishedAppVersionIDs(app)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		for _, appVersion := range retentionPolicy.Expired(appVersions, publishedIDs, now) {
+			appVersion := appVersion
+			// the versions which can't be deleted by hand are kept too
+			if action == models.RetentionActionPurge {
+				verrs, err := services.ValidateAppVersionDeletion(c.env, &appVersion)
+				if err != nil {
+					return errors.WithStack(err)
+				}
+				if len(verrs) > 0 {
+					c.env.Logger.Info("[i] ApplyRetentionPolicies: Kept app version", zap.String("app_version_id", appVersion.ID.String()), zap.Any("errors", verrs))
+					continue
+				}
+			}
+			if appVersion.ArchivedAt == nil {
+				appVersion.ArchivedAt = &now
+				verrs, err := c.env.AppVersionService.Update(&appVersion, []string{"ArchivedAt"})
+				if len(verrs) > 0 {
+					c.env.Logger.Error("[!] ApplyRetentionPolicies: Failed to archive app version", zap.String("app_version_id", appVersion.ID.String()), zap.Any("errors", verrs))
+					continue
+				}
+				if err != nil {
+					return errors.Wrap(err, "SQL Error")
+				}
+			}
+			if action == models.RetentionActionPurge {
+				if err := c.env.WorkerService.EnqueueDeleteAppVersion(appVersion.ID); err != nil {
+					return errors.Wrap(err, "Worker Error")
+				}
+			}
+			c.env.Logger.Info("[i] ApplyRetentionPolicies: Removed app version",
+				zap.String("app_slug", app.AppSlug),
+				zap.String("app_version_id", appVersion.ID.String()),
+				zap.String("platform", appVersion.Platform),
+				zap.String("product_flavor", appVersion.ProductFlavor),
+				zap.String("build_number", appVersion.BuildNumber),
+				zap.String("action", action),
+			)
+		}
+	}
+
+	c.env.Logger.Info("[i] Job ApplyRetentionPolicies finished")
+	return nil
+}
+
+// publishedAppVersionIDs returns the IDs of the versions of the app which have
+// ever been published successfully
+func (c *Context) publishedAppVersionIDs(app *models.App) (map[uuid.UUID]bool, error) {
+	publishTasks, err := c.env.PublishTaskService.FindAllForApp(app)
+	if err != nil {
+		return nil, errors.Wrap(err, "SQL Error")
+	}
+	publishedIDs := map[uuid.UUID]bool{}
+	for _, publishTask := range publishTasks {
+		if publishTask.Status == models.PublishTaskStatusSuccess {
+			publishedIDs[publishTask.AppVersionID] = true
+		}
+	}
+	return publishedIDs, nil
+}
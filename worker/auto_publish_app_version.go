@@ -0,0 +1,47 @@
+package worker
+
+import (
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/bitrise-io/addons-ship-backend/services"
+	"github.com/gocraft/work"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"go.uber.org/zap"
+)
+
+var autoPublishAppVersion = "auto_publish_app_version"
+
+// AutoPublishAppVersion publishes a new app version by the auto-publish rules of
+// its app. The uploadables of the previous version are copied to the new one
+// first, so that they get published with the version.
+func (c *Context) AutoPublishAppVersion(job *work.Job) error {
+	c.env.Logger.Info("[i] Job AutoPublishAppVersion started")
+	appVersionID := job.ArgString("app_version_id")
+	if appVersionID == "" {
+		c.env.Logger.Error("Failed to get ID of app version to auto-publish")
+		return errors.New("Failed to get app_version_id")
+	}
+
+	if copyUploadablesFromID := job.ArgString("copy_uploadables_from_id"); copyUploadablesFromID != "" {
+		if err := c.copyUploadables(copyUploadablesFromID, appVersionID); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	appVersion, err := c.env.AppVersionService.Find(&models.AppVersion{Record: models.Record{ID: uuid.FromStringOrNil(appVersionID)}})
+	switch {
+	case errors.Cause(err) == gorm.ErrRecordNotFound:
+		c.env.Logger.Warn("[!] AutoPublishAppVersion: App version was deleted", zap.String("app_version_id", appVersionID))
+		return nil
+	case err != nil:
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	if err := services.AutoPublishAppVersion(c.env, appVersion, job.ArgString("workflow"), job.ArgString("branch")); err != nil {
+		return errors.WithStack(err)
+	}
+
+	c.env.Logger.Info("[i] Job AutoPublishAppVersion finished")
+	return nil
+}
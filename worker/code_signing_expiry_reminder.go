@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"time"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/gocraft/work"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+var codeSigningExpiryReminder = "code_signing_expiry_reminder"
+
+// SendCodeSigningExpiryReminders ...
+func (c *Context) SendCodeSigningExpiryReminders(job *work.Job) error {
+	c.env.Logger.Info("[i] Job SendCodeSigningExpiryReminders started")
+	now := c.env.TimeService.Now()
+	maxReminderDays := 0
+	for _, days := range models.CodeSigningExpiryReminderDays {
+		if days > maxReminderDays {
+			maxReminderDays = days
+		}
+	}
+
+	appSettingsList, err := c.env.AppSettingsService.FindAllWithCodeSigningExpiringBefore(now.Add(time.Duration(maxReminderDays+1) * 24 * time.Hour))
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	for _, appSettings := range appSettingsList {
+		codeSigningExpiry, err := appSettings.CodeSigningExpiry()
+		if err != nil {
+			c.env.Logger.Error("[!] SendCodeSigningExpiryReminders: Failed to parse code signing expiry", zap.String("app_settings_id", appSettings.ID.String()), zap.Any("error", err))
+			continue
+		}
+		expiringFiles := codeSigningExpiry.FilesToRemind(now)
+		if len(expiringFiles) == 0 {
+			continue
+		}
+		app := appSettings.App
+		if app == nil {
+			continue
+		}
+
+		for _, file := range expiringFiles {
+			c.env.AnalyticsClient.CodeSigningFileExpiring(app.AppSlug, file.Slug, file.DaysLeft(now))
+		}
+
+		contacts, err := c.env.AppContactService.FindAll(app)
+		if err != nil {
+			return errors.Wrap(err, "SQL Error")
+		}
+		if len(contacts) == 0 {
+			continue
+		}
+		appDetails, err := c.env.BitriseAPI.GetAppDetails(app.BitriseAPIToken, app.AppSlug)
+		if err != nil {
+			c.env.Logger.Error("[!] SendCodeSigningExpiryReminders: Failed to fetch app details", zap.String("app_slug", app.AppSlug), zap.Any("error", err))
+			continue
+		}
+		err = c.env.Mailer.SendEmailCodeSigningExpiry(app, contacts, appDetails, c.env.AddonFrontendHostURL, expiringFiles)
+		if err != nil {
+			c.env.Logger.Error("[!] SendCodeSigningExpiryReminders: Failed to send email", zap.String("app_slug", app.AppSlug), zap.Any("error", err))
+		}
+	}
+
+	c.env.Logger.Info("[i] Job SendCodeSigningExpiryReminders finished")
+	return nil
+}
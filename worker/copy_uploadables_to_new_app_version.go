@@ -27,6 +27,17 @@ func (c *Context) CopyUploadablesToNewAppVersion(job *work.Job) error {
 		return errors.New("Failed to get to_id")
 	}
 
+	if err := c.copyUploadables(appVersionFromID, appVersionToID); err != nil {
+		return errors.WithStack(err)
+	}
+
+	c.env.Logger.Info("[i] Job CopyUploadablesToNewAppVersion finished")
+	return nil
+}
+
+// copyUploadables copies the screenshots and the feature graphic of an app
+// version to another one, with their files on S3
+func (c *Context) copyUploadables(appVersionFromID, appVersionToID string) error {
 	c.env.Logger.Info("[i] CopyUploadablesToNewAppVersion: Copying screenshots...")
 	originalScreenshots, err := c.env.ScreenshotService.FindAll(&models.AppVersion{Record: models.Record{ID: uuid.FromStringOrNil(appVersionFromID)}})
 	if err != nil {
@@ -90,7 +101,5 @@ func (c *Context) CopyUploadablesToNewAppVersion(job *work.Job) error {
 			return errors.WithStack(err)
 		}
 	}
-
-	c.env.Logger.Info("[i] Job CopyUploadablesToNewAppVersion finished")
 	return nil
 }
@@ -0,0 +1,121 @@
+package worker
+
+import (
+	"fmt"
+
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/gocraft/work"
+	"github.com/gomodule/redigo/redis"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"go.uber.org/zap"
+)
+
+var deleteAppVersion = "delete_app_version"
+
+// DeleteAppVersion removes the files of an app version from S3 and Redis, then
+// deletes the version with its records
+func (c *Context) DeleteAppVersion(job *work.Job) error {
+	c.env.Logger.Info("[i] Job DeleteAppVersion started")
+	appVersionID := job.ArgString("app_version_id")
+	if appVersionID == "" {
+		c.env.Logger.Error("Failed to get ID of app version to delete")
+		return errors.New("Failed to get app_version_id")
+	}
+
+	appVersion, err := c.env.AppVersionService.Find(&models.AppVersion{Record: models.Record{ID: uuid.FromStringOrNil(appVersionID)}})
+	switch {
+	case errors.Cause(err) == gorm.ErrRecordNotFound:
+		c.env.Logger.Warn("[!] DeleteAppVersion: App version was already deleted", zap.String("app_version_id", appVersionID))
+		return nil
+	case err != nil:
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	if err := c.deleteAppVersionFiles(appVersion); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err := c.env.AppVersionService.Delete(appVersion); err != nil && errors.Cause(err) != gorm.ErrRecordNotFound {
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	c.env.Logger.Info("[i] Job DeleteAppVersion finished")
+	return nil
+}
+
+// deleteAppVersionFiles removes the screenshots, the feature graphic and the
+// logs of an app version from S3, and the log chunks of its publish tasks from
+// Redis
+func (c *Context) deleteAppVersionFiles(appVersion *models.AppVersion) error {
+	c.env.Logger.Info("[i] Deleting screenshots of app version...", zap.String("app_version_id", appVersion.ID.String()))
+	screenshots, err := c.env.ScreenshotService.FindAll(appVersion)
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+	for _, screenshot := range screenshots {
+		if err := c.env.AWS.DeleteObject(screenshot.AWSPath()); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	c.env.Logger.Info("[i] Deleting feature graphic of app version...", zap.String("app_version_id", appVersion.ID.String()))
+	featureGraphic, err := c.env.FeatureGraphicService.Find(&models.FeatureGraphic{AppVersionID: appVersion.ID})
+	switch {
+	case errors.Cause(err) == gorm.ErrRecordNotFound:
+	case err != nil:
+		return errors.Wrap(err, "SQL Error")
+	default:
+		if err := c.env.AWS.DeleteObject(featureGraphic.AWSPath()); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	c.env.Logger.Info("[i] Deleting logs of app version...", zap.String("app_version_id", appVersion.ID.String()))
+	events, err := c.env.AppVersionEventService.FindAll(appVersion)
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+	for _, event := range events {
+		if !event.IsLogAvailable {
+			continue
+		}
+		logAWSPath, err := event.LogAWSPath()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if err := c.env.AWS.DeleteObject(logAWSPath); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	publishTasks, err := c.env.PublishTaskService.FindAll(appVersion)
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+	for _, publishTask := range publishTasks {
+		if err := c.deleteLogChunks(publishTask.TaskID.String()); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// deleteLogChunks removes the log chunks of a publish task, which weren't
+// expired yet
+func (c *Context) deleteLogChunks(taskID string) error {
+	chunkCountRedisKey := fmt.Sprintf("%s_chunk_count", taskID)
+	chunkCount, err := c.env.Redis.GetInt64(chunkCountRedisKey)
+	switch {
+	case err == redis.ErrNil:
+		return nil
+	case err != nil:
+		return errors.WithStack(err)
+	}
+	keys := []string{chunkCountRedisKey}
+	for i := int64(1); i <= chunkCount; i++ {
+		keys = append(keys, fmt.Sprintf("%s%d", taskID, i))
+	}
+	return errors.WithStack(c.env.Redis.Delete(keys...))
+}
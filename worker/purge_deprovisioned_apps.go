@@ -0,0 +1,66 @@
+package worker
+
+import (
+	"github.com/bitrise-io/addons-ship-backend/models"
+	"github.com/gocraft/work"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+var purgeDeprovisionedApps = "purge_deprovisioned_apps"
+
+// PurgeDeprovisionedApps deletes the apps which were deprovisioned before the
+// grace period, together with the files of their versions in S3 and Redis
+func (c *Context) PurgeDeprovisionedApps(job *work.Job) error {
+	c.env.Logger.Info("[i] Job PurgeDeprovisionedApps started")
+	deprovisionedBefore := c.env.TimeService.Now().Add(-c.env.DeprovisionGracePeriod)
+
+	apps, err := c.env.AppService.FindAllDeprovisionedBefore(deprovisionedBefore)
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	for _, app := range apps {
+		app := app
+		// an app which can't be purged mustn't hold back the others, it's
+		// retried by the next run
+		appVersionCount, err := c.purgeApp(&app)
+		if err != nil {
+			c.env.Logger.Error("[!] PurgeDeprovisionedApps: Failed to purge app",
+				zap.String("app_slug", app.AppSlug),
+				zap.Any("error", err),
+			)
+			continue
+		}
+		c.env.Logger.Info("[i] PurgeDeprovisionedApps: Purged app",
+			zap.String("app_slug", app.AppSlug),
+			zap.Int("app_version_count", appVersionCount),
+			zap.Time("deprovisioned_at", *app.DeprovisionedAt),
+		)
+	}
+
+	c.env.Logger.Info("[i] Job PurgeDeprovisionedApps finished")
+	return nil
+}
+
+// purgeApp deletes the files of the versions of an app and the app itself, and
+// returns the number of its versions
+func (c *Context) purgeApp(app *models.App) (int, error) {
+	appVersions, err := c.env.AppVersionService.FindAll(app, map[string]interface{}{})
+	if err != nil {
+		return 0, errors.Wrap(err, "SQL Error")
+	}
+	for _, appVersion := range appVersions {
+		appVersion := appVersion
+		if err := c.deleteAppVersionFiles(&appVersion); err != nil {
+			return 0, errors.WithStack(err)
+		}
+	}
+
+	// the records of the app are deleted by the foreign key cascades
+	if err := c.env.AppService.Delete(&models.App{Record: models.Record{ID: app.ID}}); err != nil && errors.Cause(err) != gorm.ErrRecordNotFound {
+		return 0, errors.Wrap(err, "SQL Error")
+	}
+	return len(appVersions), nil
+}
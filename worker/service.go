@@ -65,3 +65,28 @@ func (*Service) EnqueueCopyUploadablesToNewAppVersion(appVersionFromCopyID, appV
 	}
 	return nil
 }
+
+// EnqueueAutoPublishAppVersion ...
+func (*Service) EnqueueAutoPublishAppVersion(appVersionID uuid.UUID, workflow, branch, copyUploadablesFromID string) error {
+	enqueuer := work.NewEnqueuer(namespace, redisPool)
+	_, err := enqueuer.EnqueueUnique(autoPublishAppVersion, work.Q{
+		"app_version_id":           appVersionID.String(),
+		"workflow":                 workflow,
+		"branch":                   branch,
+		"copy_uploadables_from_id": copyUploadablesFromID,
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// EnqueueDeleteAppVersion ...
+func (*Service) EnqueueDeleteAppVersion(appVersionID uuid.UUID) error {
+	enqueuer := work.NewEnqueuer(namespace, redisPool)
+	_, err := enqueuer.EnqueueUnique(deleteAppVersion, work.Q{"app_version_id": appVersionID.String()})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package worker
+
+import (
+	"github.com/bitrise-io/addons-ship-backend/services"
+	"github.com/gocraft/work"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+var startScheduledPublishes = "start_scheduled_publishes"
+
+// StartScheduledPublishes starts the scheduled publishes which are due, with
+// the same publish flow as the ones started by hand
+func (c *Context) StartScheduledPublishes(job *work.Job) error {
+	c.env.Logger.Info("[i] Job StartScheduledPublishes started")
+
+	scheduledPublishes, err := c.env.ScheduledPublishService.FindAllDue(c.env.TimeService.Now())
+	if err != nil {
+		return errors.Wrap(err, "SQL Error")
+	}
+
+	for _, scheduledPublish := range scheduledPublishes {
+		scheduledPublish := scheduledPublish
+		// a publish which can't be started mustn't hold back the others
+		if err := services.StartScheduledPublish(c.env, &scheduledPublish); err != nil {
+			c.env.Logger.Error("[!] StartScheduledPublishes: Failed to start scheduled publish",
+				zap.String("scheduled_publish_id", scheduledPublish.ID.String()),
+				zap.String("app_version_id", scheduledPublish.AppVersionID.String()),
+				zap.Any("error", err),
+			)
+			continue
+		}
+		c.env.Logger.Info("[i] StartScheduledPublishes: Scheduled publish processed",
+			zap.String("scheduled_publish_id", scheduledPublish.ID.String()),
+			zap.String("status", scheduledPublish.Status),
+		)
+	}
+
+	c.env.Logger.Info("[i] Job StartScheduledPublishes finished")
+	return nil
+}
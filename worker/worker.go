@@ -37,6 +37,17 @@ func Start(appEnv *env.AppEnv) error {
 	pool.Job(storeLogToAWS, (&context).StoreLogToAWS)
 	pool.Job(storeLogChunkToRedis, (&context).StoreLogChunkToRedis)
 	pool.Job(copyUploadablesToNewAppVersion, (&context).CopyUploadablesToNewAppVersion)
+	pool.Job(autoPublishAppVersion, (&context).AutoPublishAppVersion)
+	pool.Job(codeSigningExpiryReminder, (&context).SendCodeSigningExpiryReminders)
+	pool.Job(deleteAppVersion, (&context).DeleteAppVersion)
+	pool.Job(applyRetentionPolicies, (&context).ApplyRetentionPolicies)
+	pool.Job(purgeDeprovisionedApps, (&context).PurgeDeprovisionedApps)
+	pool.Job(startScheduledPublishes, (&context).StartScheduledPublishes)
+
+	pool.PeriodicallyEnqueue("0 0 8 * * *", codeSigningExpiryReminder)
+	pool.PeriodicallyEnqueue("0 0 3 * * *", applyRetentionPolicies)
+	pool.PeriodicallyEnqueue("0 0 4 * * *", purgeDeprovisionedApps)
+	pool.PeriodicallyEnqueue("0 * * * * *", startScheduledPublishes)
 
 	pool.Start()
 	defer pool.Stop()